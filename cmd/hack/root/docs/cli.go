@@ -40,12 +40,16 @@ func runCLI(cmd *cobra.Command) error {
 	unobinCmd.AddCommand(
 		unobinroot.VersionCmd,
 		unobinroot.CheckCmd,
+		unobinroot.LintCmd,
+		unobinroot.TestCmd,
 		unobinroot.CompileCmd,
 		unobinroot.GenerateCmd,
 		unobinroot.FmtCmd,
 		unobinroot.PrintGraphCmd,
 		unobinroot.DepsCmd,
 		unobinroot.LSPCmd,
+		unobinroot.ProxyCmd,
+		unobinroot.SumDBCmd,
 	)
 	content := []byte(docgen.CLIReference(unobinCmd))
 	path := filepath.Join(cliOut, "cli.md")
@@ -19,12 +19,16 @@ func init() {
 	rootCmd.AddCommand(
 		root.VersionCmd,
 		root.CheckCmd,
+		root.LintCmd,
+		root.TestCmd,
 		root.CompileCmd,
 		root.GenerateCmd,
 		root.FmtCmd,
 		root.PrintGraphCmd,
 		root.DepsCmd,
 		root.LSPCmd,
+		root.ProxyCmd,
+		root.SumDBCmd,
 	)
 }
 
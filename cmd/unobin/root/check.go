@@ -34,6 +34,7 @@ var (
 type checkConfig struct {
 	path          string
 	replaceUnobin string
+	watch         bool
 }
 
 type checkTarget struct {
@@ -53,11 +54,12 @@ type checkResult struct {
 var errCheckNegative = errors.New("check found errors")
 
 func init() {
-	addFormatFlag(CheckCmd)
+	addDiagnosticFormatFlag(CheckCmd)
 	CheckCmd.Flags().StringVarP(&checkCfg.path, "path", "p", ".",
 		"Path to a Unobin source file or directory.")
 	CheckCmd.Flags().StringVar(&checkCfg.replaceUnobin, "replace-unobin", "",
 		"Local path to substitute for github.com/cloudboss/unobin so schema checks read it.")
+	addWatchFlag(CheckCmd, &checkCfg.watch)
 }
 
 func runCheck(cmd *cobra.Command, cfg *checkConfig) error {
@@ -65,11 +67,26 @@ func runCheck(cmd *cobra.Command, cfg *checkConfig) error {
 	if err != nil {
 		return err
 	}
-	format, err := cmdout.ParseFormat(formatValue)
+	format, err := cmdout.ParseDiagnosticFormat(formatValue)
 	if err != nil {
 		return err
 	}
-	target, checkErr := checkSourcePath(cmd, cfg.path, cfg.replaceUnobin)
+	if cfg.watch {
+		return watchCheck(cmd, cfg, format)
+	}
+	return checkOnce(cmd, cfg, format, sourcecheck.NewCache())
+}
+
+// checkOnce checks cfg.path and writes the result in format. cache keeps
+// parsed libraries and clean composites for the next check in watch
+// mode.
+func checkOnce(
+	cmd *cobra.Command,
+	cfg *checkConfig,
+	format cmdout.Format,
+	cache *sourcecheck.Cache,
+) error {
+	target, checkErr := checkSourcePath(cmd, cfg.path, cfg.replaceUnobin, cache)
 	if format == cmdout.FormatText {
 		for _, report := range target.diagnostics {
 			if err := diagnostic.WriteText(cmd.ErrOrStderr(), report); err != nil {
@@ -83,6 +100,14 @@ func runCheck(cmd *cobra.Command, cfg *checkConfig) error {
 		return err
 	}
 	if target.Type == "" {
+		if format == cmdout.FormatSARIF {
+			return cmdout.WriteSARIFCommandError(
+				cmd,
+				sarifTool(nil),
+				target.diagnostics,
+				checkCommandFailure(cfg.path, checkErr),
+			)
+		}
 		return cmdout.WriteCommandError(
 			cmd,
 			format,
@@ -96,13 +121,19 @@ func runCheck(cmd *cobra.Command, cfg *checkConfig) error {
 		diagnostic.FromError(checkErr, diagnostic.ConvertOptions{Path: mapper.Display}),
 	)
 	ok := !hasErrorDiagnostics(diagnostics)
-	if err := cmdout.WriteDocument(cmd.OutOrStdout(), format, checkResult{
-		Kind:          "check-result",
-		FormatVersion: 1,
-		OK:            ok,
-		Target:        target,
-		Diagnostics:   diagnostics,
-	}); err != nil {
+	var err error
+	if format == cmdout.FormatSARIF {
+		err = cmdout.WriteSARIF(cmd.OutOrStdout(), sarifTool(nil), diagnostics)
+	} else {
+		err = cmdout.WriteDocument(cmd.OutOrStdout(), format, checkResult{
+			Kind:          "check-result",
+			FormatVersion: 1,
+			OK:            ok,
+			Target:        target,
+			Diagnostics:   diagnostics,
+		})
+	}
+	if err != nil {
 		return err
 	}
 	if !ok {
@@ -115,9 +146,10 @@ func checkSourcePath(
 	cmd *cobra.Command,
 	path string,
 	replaceUnobin string,
+	cache *sourcecheck.Cache,
 ) (checkTarget, error) {
 	collector := &diagnostic.Collector{}
-	target, err := checkSourcePathWithReporter(cmd, path, replaceUnobin, collector)
+	target, err := checkSourcePathWithReporter(cmd, path, replaceUnobin, cache, collector)
 	target.diagnostics = collector.Diagnostics()
 	return target, err
 }
@@ -126,6 +158,7 @@ func checkSourcePathWithReporter(
 	cmd *cobra.Command,
 	path string,
 	replaceUnobin string,
+	cache *sourcecheck.Cache,
 	reporter diagnostic.Reporter,
 ) (checkTarget, error) {
 	info, err := os.Stat(path)
@@ -133,20 +166,21 @@ func checkSourcePathWithReporter(
 		return checkTarget{}, err
 	}
 	if info.IsDir() {
-		return checkSourceDir(cmd, path, replaceUnobin, reporter)
+		return checkSourceDir(cmd, path, replaceUnobin, cache, reporter)
 	}
-	return checkSourceFile(cmd, path, replaceUnobin, reporter)
+	return checkSourceFile(cmd, path, replaceUnobin, cache, reporter)
 }
 
 func checkSourceDir(
 	cmd *cobra.Command,
 	path string,
 	replaceUnobin string,
+	cache *sourcecheck.Cache,
 	reporter diagnostic.Reporter,
 ) (checkTarget, error) {
 	factoryPath := filepath.Join(path, "factory.ub")
 	if info, err := os.Stat(factoryPath); err == nil && !info.IsDir() {
-		return checkSourceFile(cmd, factoryPath, replaceUnobin, reporter)
+		return checkSourceFile(cmd, factoryPath, replaceUnobin, cache, reporter)
 	} else if err != nil && !errors.Is(err, fs.ErrNotExist) {
 		return checkTarget{}, err
 	}
@@ -154,7 +188,7 @@ func checkSourceDir(
 	source := sourceForDir(path)
 	if resolve.HasCompositeExports(source) {
 		target := checkTarget{Path: cleanCheckPath(path), Type: "library"}
-		opts, err := checkOptions(cmd, path, path, replaceUnobin, reporter)
+		opts, err := checkOptions(cmd, path, path, replaceUnobin, cache, reporter)
 		if err != nil {
 			return target, err
 		}
@@ -184,6 +218,7 @@ func checkSourceFile(
 	cmd *cobra.Command,
 	path string,
 	replaceUnobin string,
+	cache *sourcecheck.Cache,
 	reporter diagnostic.Reporter,
 ) (checkTarget, error) {
 	target := checkTarget{Path: cleanCheckPath(path), Type: checkTypeFromName(path)}
@@ -195,7 +230,7 @@ func checkSourceFile(
 	dir := filepath.Dir(path)
 	switch file.Kind {
 	case syntax.FileFactory:
-		opts, err := checkOptions(cmd, dir, dir, replaceUnobin, reporter)
+		opts, err := checkOptions(cmd, dir, dir, replaceUnobin, cache, reporter)
 		if err != nil {
 			return target, err
 		}
@@ -203,13 +238,13 @@ func checkSourceFile(
 		_, err = sourcecheck.CheckFactoryBody(file.Factory.Body, opts)
 		return target, err
 	case syntax.FileLibrary:
-		opts, err := checkOptions(cmd, dir, dir, replaceUnobin, reporter)
+		opts, err := checkOptions(cmd, dir, dir, replaceUnobin, cache, reporter)
 		if err != nil {
 			return target, err
 		}
 		opts.RootSourceFile = sourceFileForProject(opts.ProjectDir, path)
 		return target, sourcecheck.CheckLibraryFile(file.Library, opts)
-	case syntax.FileStack, syntax.FileProject, syntax.FileProjectLock:
+	case syntax.FileStack, syntax.FileProject, syntax.FileProjectLock, syntax.FileTest:
 		return target, nil
 	default:
 		return checkTarget{}, fmt.Errorf("%s has no checkable Unobin source", path)
@@ -230,9 +265,11 @@ func checkTypeFromName(path string) string {
 		return "project"
 	case "project-lock.ub":
 		return "project-lock"
-	default:
-		return ""
 	}
+	if strings.HasSuffix(path, "_test.ub") {
+		return "test"
+	}
+	return ""
 }
 
 func checkTypeFromKind(kind syntax.FileKind) string {
@@ -247,6 +284,8 @@ func checkTypeFromKind(kind syntax.FileKind) string {
 		return "project"
 	case syntax.FileProjectLock:
 		return "project-lock"
+	case syntax.FileTest:
+		return "test"
 	default:
 		return ""
 	}
@@ -317,6 +356,7 @@ func checkOptions(
 	projectStart string,
 	sourceDir string,
 	replaceUnobin string,
+	cache *sourcecheck.Cache,
 	reporter diagnostic.Reporter,
 ) (sourcecheck.Options, error) {
 	projectDir, err := printGraphProjectDir(projectStart)
@@ -342,6 +382,11 @@ func checkOptions(
 	if err != nil {
 		return sourcecheck.Options{}, err
 	}
+	vendor, err := compile.OpenVendor(projectDir, projectLock)
+	if err != nil {
+		return sourcecheck.Options{}, err
+	}
+	resolver = vendor.Wrap(resolver)
 	resolver = compile.WrapProjectLockSources(resolver, projectLock)
 	resolver, err = compile.WrapReplaces(resolver, projectDir, replaceUnobin, replaceMap)
 	if err != nil {
@@ -365,6 +410,7 @@ func checkOptions(
 		Resolver:    resolver,
 		Versions:    repoVersions,
 		SchemaCache: sourcecheck.NewSchemaCache(schemaRoots...),
+		Cache:       cache,
 		Reporter:    reporter,
 	}, nil
 }
@@ -372,8 +418,8 @@ func checkOptions(
 func checkToolOutput(cmd *cobra.Command) io.Writer {
 	value, err := cmd.Flags().GetString("format")
 	if err == nil {
-		format, parseErr := cmdout.ParseFormat(value)
-		if parseErr == nil && format.Machine() {
+		format, parseErr := cmdout.ParseDiagnosticFormat(value)
+		if parseErr == nil && format != cmdout.FormatText {
 			return io.Discard
 		}
 	}
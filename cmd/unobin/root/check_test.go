@@ -1,7 +1,9 @@
 package root
 
 import (
+	"encoding/json"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -27,3 +29,75 @@ func TestCheckCommandRejectsUnknownRole(t *testing.T) {
 	require.Error(t, err)
 	require.Contains(t, out, "cannot determine UB file role")
 }
+
+func TestCheckCommandSARIF(t *testing.T) {
+	setCLIVersion(t, "dev")
+	t.Chdir(filepath.Join("testdata", "ub", "check-command", "valid", "default-factory"))
+
+	out, err := runCommand(t, "check", "--format", "sarif")
+
+	require.NoError(t, err)
+	log := decodeSARIF(t, out)
+	require.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+	require.Equal(t, "unobin", log.Runs[0].Tool.Driver.Name)
+	require.True(t, log.Runs[0].Invocations[0].ExecutionSuccessful)
+	require.Empty(t, log.Runs[0].Results)
+}
+
+func TestCheckCommandSARIFFailure(t *testing.T) {
+	setCLIVersion(t, "dev")
+	path := filepath.Join(
+		"testdata", "ub", "check-command", "invalid", "unknown-role", "loose.ub")
+
+	out, err := runCommand(t, "check", "-p", path, "--format", "sarif")
+
+	require.Error(t, err)
+	log := decodeSARIF(t, out)
+	require.False(t, log.Runs[0].Invocations[0].ExecutionSuccessful)
+	require.NotEmpty(t, log.Runs[0].Results)
+	require.Contains(t, log.Runs[0].Results[0].Message.Text, "cannot determine UB file role")
+}
+
+// sarifLog is the part of a SARIF log the command tests read.
+type sarifLog struct {
+	Version string `json:"version"`
+	Runs    []struct {
+		Tool struct {
+			Driver struct {
+				Name  string `json:"name"`
+				Rules []struct {
+					ID   string `json:"id"`
+					Name string `json:"name"`
+				} `json:"rules"`
+			} `json:"driver"`
+		} `json:"tool"`
+		Invocations []struct {
+			ExecutionSuccessful bool `json:"executionSuccessful"`
+		} `json:"invocations"`
+		Results []struct {
+			RuleID  string `json:"ruleId"`
+			Level   string `json:"level"`
+			Message struct {
+				Text string `json:"text"`
+			} `json:"message"`
+			Locations []struct {
+				PhysicalLocation struct {
+					ArtifactLocation struct {
+						URI string `json:"uri"`
+					} `json:"artifactLocation"`
+					Region struct {
+						StartLine int `json:"startLine"`
+					} `json:"region"`
+				} `json:"physicalLocation"`
+			} `json:"locations"`
+		} `json:"results"`
+	} `json:"runs"`
+}
+
+func decodeSARIF(t *testing.T, out string) sarifLog {
+	t.Helper()
+	var log sarifLog
+	require.NoError(t, json.NewDecoder(strings.NewReader(out)).Decode(&log))
+	return log
+}
@@ -2,7 +2,9 @@ package root
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"maps"
 	"os"
 	"path/filepath"
@@ -60,14 +62,30 @@ func currentCommandRemotes() map[string]*resolve.Source {
 func runCommandWithRemotes(t *testing.T, remotes map[string]*resolve.Source,
 	args ...string) (string, error) {
 	t.Helper()
+	out := &bytes.Buffer{}
+	err := executeCommand(t, context.Background(), remotes, out, args...)
+	return out.String(), err
+}
+
+// executeCommand runs args against a fresh root command with ctx,
+// writing both output streams to out.
+func executeCommand(t *testing.T, ctx context.Context, remotes map[string]*resolve.Source,
+	out io.Writer, args ...string) error {
+	t.Helper()
 	stubCompileResolver(t, mergedCommandRemotes(remotes))
 	resetFlags(CheckCmd)
+	resetFlags(LintCmd)
+	resetFlags(TestCmd)
 	resetFlags(CompileCmd)
 	resetFlags(PrintGraphCmd)
 	resetFlags(depsSyncCmd)
 	resetFlags(depsListCmd)
 	resetFlags(depsVerifyCmd)
 	resetFlags(depsGetCmd)
+	resetFlags(depsOutdatedCmd)
+	resetFlags(depsUpdateCmd)
+	resetFlags(depsWhyCmd)
+	resetFlags(depsVendorCmd)
 	resetFlags(LSPCmd)
 	root := &cobra.Command{
 		Use:          "unobin",
@@ -75,16 +93,21 @@ func runCommandWithRemotes(t *testing.T, remotes map[string]*resolve.Source,
 	}
 	root.AddCommand(VersionCmd)
 	root.AddCommand(CheckCmd)
+	root.AddCommand(LintCmd)
+	root.AddCommand(TestCmd)
 	root.AddCommand(CompileCmd)
 	root.AddCommand(PrintGraphCmd)
 	root.AddCommand(DepsCmd)
 	root.AddCommand(LSPCmd)
-	out := &bytes.Buffer{}
+	// A subcommand keeps the context of the run that first reached it,
+	// so each run hands its own down.
+	for _, cmd := range root.Commands() {
+		cmd.SetContext(ctx)
+	}
 	root.SetOut(out)
 	root.SetErr(out)
 	root.SetArgs(args)
-	err := root.Execute()
-	return out.String(), err
+	return root.ExecuteContext(ctx)
 }
 
 func stubCompileResolver(t *testing.T, remotes map[string]*resolve.Source) {
@@ -215,6 +238,24 @@ func TestDepsSyncOutputCompilesForReplacedUnobinSubdir(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestCompilePlatformArguments(t *testing.T) {
+	dir := t.TempDir()
+	factory := filepath.Join(dir, "factory.ub")
+
+	_, err := runCommand(t, "compile", "-p", factory, "-o", dir, "--platform", "linux/amd64")
+	require.EqualError(t, err, "--platform needs --build")
+
+	_, err = runCommand(t, "compile", "-p", factory, "-o", dir, "--build",
+		"--platform", "linux/amd64,plan9/386")
+	require.ErrorContains(t, err, "platform plan9/386 is not supported")
+
+	out, err := runCommand(t, "compile", "-p", factory, "-o", dir, "--platform", "linux/amd64",
+		"--format", "json")
+	require.Error(t, err)
+	require.Contains(t, out, `"code":"unobin.command.invalid-args"`)
+	require.Contains(t, out, "--platform needs --build")
+}
+
 func TestDepsSyncKeepsSchemaDependencyDirect(t *testing.T) {
 	dir := t.TempDir()
 	writeSchemaDependencyFactory(t, dir)
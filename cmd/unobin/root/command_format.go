@@ -2,9 +2,27 @@ package root
 
 import (
 	"github.com/cloudboss/unobin/internal/cmdout"
+	"github.com/cloudboss/unobin/pkg/toolchain"
 	"github.com/spf13/cobra"
 )
 
 func addFormatFlag(command *cobra.Command) {
 	command.Flags().String("format", "text", cmdout.FormatHelp())
 }
+
+// addDiagnosticFormatFlag adds the format flag of a command whose
+// result is a list of diagnostics, which can also be written as SARIF.
+func addDiagnosticFormatFlag(command *cobra.Command) {
+	command.Flags().String("format", "text", cmdout.DiagnosticFormatHelp())
+}
+
+// sarifTool describes this CLI as the tool of a SARIF log whose rules
+// are rules.
+func sarifTool(rules []cmdout.SARIFRule) cmdout.SARIFTool {
+	return cmdout.SARIFTool{
+		Name:           "unobin",
+		Version:        cliVersion(),
+		InformationURI: "https://" + toolchain.UnobinModulePath,
+		Rules:          rules,
+	}
+}
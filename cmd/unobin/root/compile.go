@@ -35,6 +35,8 @@ type compileConfig struct {
 	replaceUnobin   string
 	replaceGoModule []string
 	build           bool
+	platforms       []string
+	watch           bool
 }
 
 func init() {
@@ -68,6 +70,13 @@ func init() {
 
 	CompileCmd.Flags().BoolVar(&compileCfg.build, "build", false,
 		"After writing the source, run `go build` in the output directory.")
+
+	CompileCmd.Flags().StringArrayVar(&compileCfg.platforms, "platform", nil,
+		"Build for a platform instead of the host, repeatable or comma-separated. "+
+			"Format: `os/arch`. Each binary is named `<name>-<os>-<arch>`, and "+
+			"their checksums are written to SHA256SUMS. Needs --build.")
+
+	addWatchFlag(CompileCmd, &compileCfg.watch)
 }
 
 func runCompile(cmd *cobra.Command, cfg *compileConfig) error {
@@ -80,6 +89,10 @@ func runCompile(cmd *cobra.Command, cfg *compileConfig) error {
 		return err
 	}
 	replaceGoModules, err := parseReplaceFlags(cfg.replaceGoModule)
+	var platforms []compile.Platform
+	if err == nil {
+		platforms, err = parseCompilePlatforms(cfg)
+	}
 	if err != nil {
 		if format.Machine() {
 			return writeCompileCommandFailure(
@@ -100,10 +113,36 @@ func runCompile(cmd *cobra.Command, cfg *compileConfig) error {
 		ReplaceUnobin:    cfg.replaceUnobin,
 		ReplaceGoModules: replaceGoModules,
 		Build:            cfg.build,
+		Platforms:        platforms,
 		NewResolver:      newCompileResolver,
 		Stdout:           cmd.OutOrStdout(),
 		Stderr:           cmd.ErrOrStderr(),
 	}
+	if cfg.watch {
+		return watchCompile(cmd, cfg, format, options)
+	}
+	return compileOnce(cmd, cfg, format, options)
+}
+
+// parseCompilePlatforms parses --platform, which only means something
+// for a build.
+func parseCompilePlatforms(cfg *compileConfig) ([]compile.Platform, error) {
+	if len(cfg.platforms) == 0 {
+		return nil, nil
+	}
+	if !cfg.build {
+		return nil, errors.New("--platform needs --build")
+	}
+	return compile.ParsePlatforms(cfg.platforms)
+}
+
+// compileOnce compiles with options and writes the result in format.
+func compileOnce(
+	cmd *cobra.Command,
+	cfg *compileConfig,
+	format cmdout.Format,
+	options compile.Options,
+) error {
 	if !format.Machine() {
 		return compile.Run(options)
 	}
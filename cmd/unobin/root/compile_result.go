@@ -30,12 +30,24 @@ type compileSourceResult struct {
 }
 
 type compileOutputResult struct {
-	Dir    string  `json:"dir"     ub:"dir"`
-	MainGo string  `json:"main-go" ub:"main-go"`
-	GoMod  string  `json:"go-mod"  ub:"go-mod"`
-	Assets *string `json:"assets,omitempty" ub:"assets,omitempty"`
-	Built  bool    `json:"built"   ub:"built"`
-	Binary *string `json:"binary"  ub:"binary"`
+	Dir        string                `json:"dir"     ub:"dir"`
+	MainGo     string                `json:"main-go" ub:"main-go"`
+	GoMod      string                `json:"go-mod"  ub:"go-mod"`
+	Assets     *string               `json:"assets,omitempty" ub:"assets,omitempty"`
+	Built      bool                  `json:"built"   ub:"built"`
+	Binary     *string               `json:"binary"  ub:"binary"`
+	SBOM       *string               `json:"sbom,omitempty"       ub:"sbom,omitempty"`
+	Provenance *string               `json:"provenance,omitempty" ub:"provenance,omitempty"`
+	Binaries   []compileBinaryResult `json:"binaries,omitempty"   ub:"binaries,omitempty"`
+	Checksums  *string               `json:"checksums,omitempty"  ub:"checksums,omitempty"`
+}
+
+type compileBinaryResult struct {
+	Platform   string `json:"platform"   ub:"platform"`
+	Path       string `json:"path"       ub:"path"`
+	SHA256     string `json:"sha256"     ub:"sha256"`
+	SBOM       string `json:"sbom"       ub:"sbom"`
+	Provenance string `json:"provenance" ub:"provenance"`
 }
 
 type compileCommandResult struct {
@@ -59,7 +71,7 @@ func buildCompileCommandResult(
 	if result.Built && result.ContentRevision == "" {
 		return compileCommandResult{}, errors.New("built compile result needs a content revision")
 	}
-	if result.Built && result.BinaryPath == "" {
+	if result.Built && result.BinaryPath == "" && len(result.Binaries) == 0 {
 		return compileCommandResult{}, errors.New("built compile result needs a binary path")
 	}
 	files, err := publicCompileFiles(result.Files, mapper)
@@ -88,6 +100,18 @@ func buildCompileCommandResult(
 	if result.Built {
 		response.Factory.ContentRevision = optionalCompileString(result.ContentRevision)
 		response.Output.Binary = optionalCompileString(mapper.Display(result.BinaryPath))
+		response.Output.SBOM = optionalCompileString(mapper.Display(result.SBOMPath))
+		response.Output.Provenance = optionalCompileString(mapper.Display(result.ProvenancePath))
+		for _, binary := range result.Binaries {
+			response.Output.Binaries = append(response.Output.Binaries, compileBinaryResult{
+				Platform:   binary.Platform.String(),
+				Path:       mapper.Display(binary.Path),
+				SHA256:     binary.SHA256,
+				SBOM:       mapper.Display(binary.SBOMPath),
+				Provenance: mapper.Display(binary.ProvenancePath),
+			})
+		}
+		response.Output.Checksums = optionalCompileString(mapper.Display(result.ChecksumsPath))
 	}
 	return response, nil
 }
@@ -43,10 +43,44 @@ func TestCompileResultFormatGolden(t *testing.T) {
 			MainGoPath: filepath.Join(root, "build", "main.go"),
 			GoModPath:  filepath.Join(root, "build", "go.mod"),
 			Built:      true, BinaryPath: filepath.Join(root, "build", "demo"),
+			SBOMPath:       filepath.Join(root, "build", "demo.sbom.json"),
+			ProvenancePath: filepath.Join(root, "build", "demo.provenance.json"),
 			Files: []filechange.Change{
 				{Path: filepath.Join(root, "build", "demo"), Action: filechange.ActionCreated},
 			},
 		},
+		{
+			FactoryName: "demo", Version: "v1.2.3", ContentRevision: "abc123def456",
+			SourcePath: filepath.Join(root, "factory.ub"), ProjectDir: root,
+			OutputDir:  filepath.Join(root, "build"),
+			MainGoPath: filepath.Join(root, "build", "main.go"),
+			GoModPath:  filepath.Join(root, "build", "go.mod"),
+			Built:      true,
+			Binaries: []compilepkg.Binary{
+				{
+					Platform: compilepkg.Platform{OS: "linux", Arch: "amd64"},
+					Path:     filepath.Join(root, "build", "demo-linux-amd64"),
+					SHA256:   "1f2e3d4c",
+					SBOMPath: filepath.Join(root, "build", "demo-linux-amd64.sbom.json"),
+					ProvenancePath: filepath.Join(
+						root, "build", "demo-linux-amd64.provenance.json"),
+				},
+				{
+					Platform: compilepkg.Platform{OS: "darwin", Arch: "arm64"},
+					Path:     filepath.Join(root, "build", "demo-darwin-arm64"),
+					SHA256:   "5a6b7c8d",
+					SBOMPath: filepath.Join(root, "build", "demo-darwin-arm64.sbom.json"),
+					ProvenancePath: filepath.Join(
+						root, "build", "demo-darwin-arm64.provenance.json"),
+				},
+			},
+			ChecksumsPath: filepath.Join(root, "build", "SHA256SUMS"),
+			Files: []filechange.Change{
+				{Path: filepath.Join(root, "build", "demo-linux-amd64"), Action: filechange.ActionCreated},
+				{Path: filepath.Join(root, "build", "demo-darwin-arm64"), Action: filechange.ActionCreated},
+				{Path: filepath.Join(root, "build", "SHA256SUMS"), Action: filechange.ActionCreated},
+			},
+		},
 	}
 	diagnostics := []diagnostic.Diagnostic{{
 		Code: "unobin.compile.built", Severity: diagnostic.SeverityInfo,
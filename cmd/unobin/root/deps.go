@@ -16,9 +16,9 @@ import (
 	"github.com/cloudboss/unobin/pkg/deps"
 	"github.com/cloudboss/unobin/pkg/diagnostic"
 	"github.com/cloudboss/unobin/pkg/filechange"
-	"github.com/cloudboss/unobin/pkg/git"
 	"github.com/cloudboss/unobin/pkg/projectmarker"
 	"github.com/cloudboss/unobin/pkg/resolve"
+	"github.com/cloudboss/unobin/pkg/sumdb"
 	"github.com/cloudboss/unobin/pkg/toolchain"
 	"github.com/spf13/cobra"
 )
@@ -85,10 +85,15 @@ var (
 	}
 )
 
-// depsListTags lists a repository's tags. It is a package var so tests can
-// resolve versions without a network round trip.
+// depsListTags lists a repository's tags through the sources UNOBIN_PROXY
+// names. It is a package var so tests can resolve versions without a
+// network round trip.
 var depsListTags = func(url string) ([]string, error) {
-	return git.ListTags(context.Background(), resolve.WithDefaultScheme(url))
+	resolver, err := newRemoteResolver()
+	if err != nil {
+		return nil, err
+	}
+	return resolver.ListTags(context.Background(), url)
 }
 
 var newRemoteResolver = resolve.NewRemoteResolver
@@ -400,15 +405,15 @@ func getDependency(
 			"%s is toolchain-versioned; pin it with the project's unobin-version line",
 			dep.URL)
 	}
-	tags, err := depsListTags(dep.URL)
+	project, projectName, err := readProjectOrEmpty(root)
 	if err != nil {
 		return nil, err
 	}
-	version, err := deps.ResolveVersion(dep, query, tags)
+	tags, err := depsListTags(dep.URL)
 	if err != nil {
 		return nil, err
 	}
-	project, projectName, err := readProjectOrEmpty(root)
+	version, err := deps.ResolveVersion(dep, query, tags, project.Requires[dep])
 	if err != nil {
 		return nil, err
 	}
@@ -500,14 +505,17 @@ func reconcileProject(
 	}
 	next := map[deps.Dependency]deps.Requirement{}
 	for dep, version := range direct {
-		next[dep] = deps.Requirement{Version: version}
+		req := m.Requires[dep]
+		req.Version, req.Indirect = version, false
+		next[dep] = req
 	}
 	for dep, req := range m.Requires {
 		if _, ok := direct[dep]; ok {
 			continue
 		}
 		if reachable[dep] {
-			next[dep] = deps.Requirement{Version: req.Version, Indirect: true}
+			req.Indirect = true
+			next[dep] = req
 		}
 	}
 	m.Requires = next
@@ -591,7 +599,7 @@ func reachableRequirements(
 	for dep, version := range direct {
 		project.SetRequire(dep, version, false)
 	}
-	selection, err := deps.Resolve(project, deps.NewFetcher(resolver))
+	selection, err := deps.Resolve(project, deps.NewListingFetcher(resolver, depsListTags))
 	if err != nil {
 		return nil, err
 	}
@@ -754,7 +762,7 @@ func resolveAndWrite(
 	if err != nil {
 		return nil, err
 	}
-	selection, err := deps.Resolve(project, deps.NewFetcher(resolver))
+	selection, err := deps.Resolve(project, deps.NewListingFetcher(resolver, depsListTags))
 	if err != nil {
 		return nil, err
 	}
@@ -769,9 +777,35 @@ func resolveAndWrite(
 		return nil, err
 	}
 	projectLock.ToolchainVersion = cliVersion()
+	if err := checkSumDB(root, projectLock); err != nil {
+		return nil, err
+	}
 	return writeDependencyFiles(root, project, projectLock)
 }
 
+// checkSumDB checks the UB hashes projectLock adds or changes against the
+// checksum database UNOBIN_SUMDB names, before they are written.
+func checkSumDB(root string, projectLock *deps.ProjectLock) error {
+	cfg, err := sumdb.ParseConfig(os.Getenv(sumdb.Env))
+	if err != nil || cfg == nil {
+		return err
+	}
+	prev, err := readProjectLockOrNil(root)
+	if err != nil {
+		return err
+	}
+	remote, err := newRemoteResolver()
+	if err != nil {
+		return err
+	}
+	client := &sumdb.Client{
+		Config:   *cfg,
+		StateDir: filepath.Join(remote.CacheRoot, "sumdb"),
+		NoSumDB:  os.Getenv(sumdb.NoSumDBEnv),
+	}
+	return client.CheckLock(context.Background(), prev, projectLock)
+}
+
 func writeDependencyFiles(
 	root string,
 	project *deps.Project,
@@ -958,10 +992,12 @@ func runDepsClean(cmd *cobra.Command) error {
 		return dependencyCommandFailure(cmd, format, nil, err)
 	}
 	removed := false
-	if _, err := os.Stat(resolver.ImportsDir()); err == nil {
-		removed = true
-	} else if !errors.Is(err, fs.ErrNotExist) {
-		return dependencyCommandFailure(cmd, format, nil, err)
+	for _, dir := range []string{resolver.ImportsDir(), resolver.ProxyImportsDir()} {
+		if _, err := os.Stat(dir); err == nil {
+			removed = true
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return dependencyCommandFailure(cmd, format, nil, err)
+		}
 	}
 	dir, err := resolver.CleanImports()
 	if err != nil {
@@ -0,0 +1,102 @@
+package root
+
+import (
+	"fmt"
+
+	"github.com/cloudboss/unobin/internal/cmdout"
+	"github.com/cloudboss/unobin/pkg/compile"
+	"github.com/cloudboss/unobin/pkg/deps"
+	"github.com/cloudboss/unobin/pkg/diagnostic"
+	"github.com/spf13/cobra"
+)
+
+var (
+	depsVendorCfg = &depsSyncConfig{}
+	depsVendorCmd = &cobra.Command{
+		Use:   "vendor",
+		Short: "Copy the project-lock dependencies into vendor/",
+		Long: `Copy the source of every dependency selected in project-lock.ub into
+vendor/ under the project, so compile, check, and the language server read
+them from the project instead of fetching them.
+
+vendor/ records the project-lock.ub it was written from. Once it exists,
+a vendor/ tree that no longer matches project-lock.ub, or a vendored UB
+project whose content hash changed, is an error; run deps vendor again
+after deps sync, get, or update.
+
+vendor/ does not hold the Go modules the factory and its Go libraries
+require, nor the Go toolchain. A vendored compile downloads neither, so
+both must already be cached on the build machine.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDepsVendor(cmd, depsVendorCfg)
+		},
+	}
+)
+
+type dependencyVendorResult struct {
+	Kind          string                  `json:"kind"           ub:"kind"`
+	FormatVersion int                     `json:"format-version" ub:"format-version"`
+	VendorDir     string                  `json:"vendor-dir"     ub:"vendor-dir"`
+	Projects      int                     `json:"projects"       ub:"projects"`
+	Files         int                     `json:"files"          ub:"files"`
+	Diagnostics   []diagnostic.Diagnostic `json:"diagnostics"    ub:"diagnostics"`
+}
+
+func init() {
+	addFormatFlag(depsVendorCmd)
+	depsVendorCmd.Flags().StringVarP(&depsVendorCfg.stackPath, "path", "p", ".", depsPathHelp)
+	depsVendorCmd.Flags().StringVar(
+		&depsVendorCfg.replaceUnobin, "replace-unobin", "", depsReplaceHelp)
+	DepsCmd.AddCommand(depsVendorCmd)
+}
+
+// runDepsVendor copies the project-lock dependencies into vendor/ and
+// checks the copy the way compile will read it.
+func runDepsVendor(cmd *cobra.Command, cfg *depsSyncConfig) error {
+	format, err := dependencyCommandFormat(cmd)
+	if err != nil {
+		return err
+	}
+	result, err := vendorDependencies(cfg)
+	if err != nil {
+		return dependencyCommandFailure(cmd, format, nil, err)
+	}
+	if format.Machine() {
+		return cmdout.WriteDocument(cmd.OutOrStdout(), format, result)
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "Vendored %d projects (%d files) into %s/\n",
+		result.Projects, result.Files, result.VendorDir)
+	return nil
+}
+
+func vendorDependencies(cfg *depsSyncConfig) (dependencyVendorResult, error) {
+	var result dependencyVendorResult
+	projectLock, err := readProjectLock(cfg.stackPath)
+	if err != nil {
+		return result, err
+	}
+	root, err := projectRoot(cfg.stackPath)
+	if err != nil {
+		return result, err
+	}
+	resolver, err := newDepsResolver(root, cfg.replaceUnobin, nil)
+	if err != nil {
+		return result, err
+	}
+	vendored, err := deps.Vendor(root, projectLock, resolver)
+	if err != nil {
+		return result, err
+	}
+	if _, err := compile.OpenVendor(root, projectLock); err != nil {
+		return result, err
+	}
+	return dependencyVendorResult{
+		Kind:          "dependency-vendor-result",
+		FormatVersion: 1,
+		VendorDir:     deps.VendorDirName,
+		Projects:      vendored.Projects,
+		Files:         vendored.Files,
+		Diagnostics:   dependencyDiagnostics(),
+	}, nil
+}
@@ -0,0 +1,472 @@
+package root
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/cloudboss/unobin/internal/cmdout"
+	"github.com/cloudboss/unobin/pkg/compile"
+	"github.com/cloudboss/unobin/pkg/deps"
+	"github.com/cloudboss/unobin/pkg/diagnostic"
+	"github.com/cloudboss/unobin/pkg/filechange"
+	"github.com/cloudboss/unobin/pkg/toolchain"
+	"github.com/spf13/cobra"
+	"golang.org/x/mod/semver"
+)
+
+var (
+	depsOutdatedCfg = &depsSyncConfig{}
+	depsOutdatedCmd = &cobra.Command{
+		Use:   "outdated",
+		Short: "Show newer versions of the project-lock dependencies",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDepsOutdated(cmd, depsOutdatedCfg)
+		},
+	}
+
+	depsUpdateCfg = &depsUpdateConfig{}
+	depsUpdateCmd = &cobra.Command{
+		Use:   "update [dependency...]",
+		Short: "Raise dependency floors to newer versions and re-pin",
+		Long: `Raise dependency floors in project.ub to newer versions and re-pin.
+
+With no arguments every floor in project.ub is raised; otherwise only the
+named dependencies are. A floor moves to the latest release of its major
+version, or to the latest release of any major version with --major,
+that the requirement's upper bound and exclusions admit. Pre-release
+tags are chosen only for a requirement that sets allow-prerelease.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDepsUpdate(cmd, depsUpdateCfg, args)
+		},
+	}
+
+	depsWhyCfg = &depsSyncConfig{}
+	depsWhyCmd = &cobra.Command{
+		Use:   "why <dependency>",
+		Short: "Show the import chain that selects a dependency",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDepsWhy(cmd, depsWhyCfg, args[0])
+		},
+	}
+)
+
+type depsUpdateConfig struct {
+	depsSyncConfig
+	major bool
+}
+
+type dependencyOutdatedEntry struct {
+	ID               string               `json:"id"                ub:"id"`
+	Kind             deps.ProjectLockKind `json:"kind"              ub:"kind"`
+	Version          string               `json:"version"           ub:"version"`
+	LatestCompatible string               `json:"latest-compatible" ub:"latest-compatible"`
+	Latest           string               `json:"latest"            ub:"latest"`
+	Indirect         bool                 `json:"indirect"          ub:"indirect"`
+}
+
+type dependencyOutdatedResult struct {
+	Kind          string                    `json:"kind"           ub:"kind"`
+	FormatVersion int                       `json:"format-version" ub:"format-version"`
+	Dependencies  []dependencyOutdatedEntry `json:"dependencies"   ub:"dependencies"`
+	Diagnostics   []diagnostic.Diagnostic   `json:"diagnostics"    ub:"diagnostics"`
+}
+
+type dependencyUpdate struct {
+	ID   string `json:"id"   ub:"id"`
+	From string `json:"from" ub:"from"`
+	To   string `json:"to"   ub:"to"`
+}
+
+type dependencyUpdateResult struct {
+	Kind          string                  `json:"kind"           ub:"kind"`
+	FormatVersion int                     `json:"format-version" ub:"format-version"`
+	Updates       []dependencyUpdate      `json:"updates"        ub:"updates"`
+	ProjectFile   string                  `json:"project-file"   ub:"project-file"`
+	LockFile      string                  `json:"lock-file"      ub:"lock-file"`
+	Direct        int                     `json:"direct"         ub:"direct"`
+	Indirect      int                     `json:"indirect"       ub:"indirect"`
+	Selected      int                     `json:"selected"       ub:"selected"`
+	Files         []filechange.Change     `json:"files"          ub:"files"`
+	Diagnostics   []diagnostic.Diagnostic `json:"diagnostics"    ub:"diagnostics"`
+}
+
+type dependencyUpdateOperation struct {
+	Updates []dependencyUpdate
+	Write   *dependencyWriteResult
+}
+
+type dependencyWhyResult struct {
+	Kind          string                  `json:"kind"           ub:"kind"`
+	FormatVersion int                     `json:"format-version" ub:"format-version"`
+	Dependency    string                  `json:"dependency"     ub:"dependency"`
+	Version       string                  `json:"version"        ub:"version"`
+	Indirect      bool                    `json:"indirect"       ub:"indirect"`
+	Chain         []string                `json:"chain"          ub:"chain"`
+	Diagnostics   []diagnostic.Diagnostic `json:"diagnostics"    ub:"diagnostics"`
+}
+
+func init() {
+	addFormatFlag(depsOutdatedCmd)
+	addFormatFlag(depsUpdateCmd)
+	addFormatFlag(depsWhyCmd)
+	depsOutdatedCmd.Flags().StringVarP(&depsOutdatedCfg.stackPath, "path", "p", ".", depsPathHelp)
+	depsUpdateCmd.Flags().StringVarP(&depsUpdateCfg.stackPath, "path", "p", ".", depsPathHelp)
+	depsUpdateCmd.Flags().StringVar(
+		&depsUpdateCfg.replaceUnobin, "replace-unobin", "", depsReplaceHelp)
+	depsUpdateCmd.Flags().BoolVar(&depsUpdateCfg.major, "major", false,
+		"Allow a floor to move to a new major version.")
+	depsWhyCmd.Flags().StringVarP(&depsWhyCfg.stackPath, "path", "p", ".", depsPathHelp)
+	depsWhyCmd.Flags().StringVar(&depsWhyCfg.replaceUnobin, "replace-unobin", "", depsReplaceHelp)
+	DepsCmd.AddCommand(depsOutdatedCmd, depsUpdateCmd, depsWhyCmd)
+}
+
+// dependencyVersions lists dep's versions, listing each repository's tags
+// once per command.
+type dependencyVersions map[string][]string
+
+func (v dependencyVersions) of(dep deps.Dependency) ([]string, error) {
+	tags, ok := v[dep.URL]
+	if !ok {
+		var err error
+		tags, err = depsListTags(dep.URL)
+		if err != nil {
+			return nil, err
+		}
+		v[dep.URL] = tags
+	}
+	return deps.Versions(dep, tags), nil
+}
+
+// atLeast returns candidate, or current when candidate is not higher.
+func atLeast(current, candidate string) string {
+	if candidate == "" || semver.Compare(candidate, current) < 0 {
+		return current
+	}
+	return candidate
+}
+
+// runDepsOutdated prints each project-lock dependency whose repository
+// has a newer version its requirement admits: the latest of its major
+// version and the latest of any major version.
+func runDepsOutdated(cmd *cobra.Command, cfg *depsSyncConfig) error {
+	format, err := dependencyCommandFormat(cmd)
+	if err != nil {
+		return err
+	}
+	root, err := projectRoot(cfg.stackPath)
+	if err != nil {
+		return dependencyCommandFailure(cmd, format, nil, err)
+	}
+	projectLock, err := readProjectLock(cfg.stackPath)
+	if err != nil {
+		return dependencyCommandFailure(cmd, format, nil, err)
+	}
+	project, _, err := readProjectOrEmpty(root)
+	if err != nil {
+		return dependencyCommandFailure(cmd, format, nil, err)
+	}
+	result := dependencyOutdatedResult{
+		Kind:          "dependency-outdated",
+		FormatVersion: 1,
+		Dependencies:  []dependencyOutdatedEntry{},
+		Diagnostics:   dependencyDiagnostics(),
+	}
+	versions := dependencyVersions{}
+	for _, id := range projectLock.SortedIDs() {
+		selected := projectLock.Deps[id]
+		dependency, err := deps.ParseDependency(id)
+		if err != nil {
+			return dependencyCommandFailure(cmd, format, nil, err)
+		}
+		available, err := versions.of(dependency)
+		if err != nil {
+			return dependencyCommandFailure(cmd, format, nil, err)
+		}
+		// An indirect dependency the project does not require has no
+		// bounds, so any release counts.
+		requirement, required := project.Requires[dependency]
+		result.Dependencies = append(result.Dependencies, dependencyOutdatedEntry{
+			ID:      id,
+			Kind:    selected.Kind,
+			Version: selected.Version,
+			LatestCompatible: atLeast(selected.Version,
+				requirement.Latest(available, semver.Major(selected.Version))),
+			Latest:   atLeast(selected.Version, requirement.Latest(available, "")),
+			Indirect: !required || requirement.Indirect,
+		})
+	}
+	if format.Machine() {
+		return cmdout.WriteDocument(cmd.OutOrStdout(), format, result)
+	}
+	out := cmd.OutOrStdout()
+	outdated := 0
+	for _, dependency := range result.Dependencies {
+		if dependency.Latest == dependency.Version {
+			continue
+		}
+		outdated++
+		line := dependency.ID + " " + dependency.Version
+		if dependency.LatestCompatible != dependency.Version {
+			line += " -> " + dependency.LatestCompatible
+		}
+		if dependency.Latest != dependency.LatestCompatible {
+			line += " (latest " + dependency.Latest + ")"
+		}
+		fmt.Fprintln(out, line)
+	}
+	if outdated == 0 {
+		fmt.Fprintln(cmd.ErrOrStderr(), "all dependencies are up to date")
+	}
+	return nil
+}
+
+// runDepsUpdate raises dependency floors in the project to newer releases
+// and re-pins.
+func runDepsUpdate(cmd *cobra.Command, cfg *depsUpdateConfig, args []string) error {
+	format, err := dependencyCommandFormat(cmd)
+	if err != nil {
+		return err
+	}
+	var announce func(dependencyUpdate)
+	if format == cmdout.FormatText {
+		announce = func(update dependencyUpdate) {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Updating %s %s -> %s\n",
+				update.ID, update.From, update.To)
+		}
+	}
+	operation, err := updateDependencies(
+		cfg, args, dependencyToolOutput(cmd, format), announce,
+	)
+	if err != nil {
+		var result *dependencyWriteResult
+		if operation != nil {
+			result = operation.Write
+		}
+		return dependencyCommandFailure(cmd, format, result, err)
+	}
+	result := operation.Write
+	if format.Machine() {
+		return cmdout.WriteDocument(cmd.OutOrStdout(), format, dependencyUpdateResult{
+			Kind:          "dependency-update-result",
+			FormatVersion: 1,
+			Updates:       operation.Updates,
+			ProjectFile:   result.ProjectFile,
+			LockFile:      result.LockFile,
+			Direct:        result.Direct,
+			Indirect:      result.Indirect,
+			Selected:      result.Selected,
+			Files:         result.Files,
+			Diagnostics:   dependencyDiagnostics(),
+		})
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(),
+		"Wrote %s (%d direct, %d indirect) and %s (%d selected)\n",
+		result.ProjectFile, result.Direct, result.Indirect,
+		result.LockFile, result.Selected)
+	return nil
+}
+
+// updateDependencies raises the floor of each named dependency, or of
+// every dependency the project requires when none is named, then selects
+// versions again and rewrites both files. A named dependency the project
+// does not require but project-lock selects gains an indirect floor.
+func updateDependencies(
+	cfg *depsUpdateConfig,
+	args []string,
+	toolOutput io.Writer,
+	announce func(dependencyUpdate),
+) (*dependencyUpdateOperation, error) {
+	root, err := projectRoot(cfg.stackPath)
+	if err != nil {
+		return nil, err
+	}
+	project, err := deps.ReadProject(os.DirFS(root))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("no %s found; run `unobin deps sync` first", deps.ProjectFileName)
+	}
+	if err != nil {
+		return nil, err
+	}
+	projectLock, err := readProjectLockOrNil(root)
+	if err != nil {
+		return nil, err
+	}
+	targets, err := updateTargets(project, projectLock, args)
+	if err != nil {
+		return nil, err
+	}
+	operation := &dependencyUpdateOperation{Updates: []dependencyUpdate{}}
+	versions := dependencyVersions{}
+	for _, dependency := range targets {
+		requirement, required := project.Requires[dependency]
+		if deps.IsReplacementSentinel(requirement.Version) {
+			continue
+		}
+		current := requirement.Version
+		if !required {
+			current = projectLock.Deps[dependency.String()].Version
+			requirement.Indirect = true
+		}
+		available, err := versions.of(dependency)
+		if err != nil {
+			return nil, err
+		}
+		major := semver.Major(current)
+		if cfg.major {
+			major = ""
+		}
+		next := requirement.Latest(available, major)
+		if next == "" || semver.Compare(next, current) <= 0 {
+			continue
+		}
+		update := dependencyUpdate{ID: dependency.String(), From: current, To: next}
+		if announce != nil {
+			announce(update)
+		}
+		project.SetRequire(dependency, next, requirement.Indirect)
+		operation.Updates = append(operation.Updates, update)
+	}
+	operation.Write, err = resolveAndWrite(root, project, cfg.replaceUnobin, toolOutput)
+	return operation, err
+}
+
+// updateTargets returns the dependencies named by args in order, or every
+// dependency the project requires, sorted, when args is empty.
+func updateTargets(
+	project *deps.Project,
+	projectLock *deps.ProjectLock,
+	args []string,
+) ([]deps.Dependency, error) {
+	if len(args) == 0 {
+		targets := make([]deps.Dependency, 0, len(project.Requires))
+		for dependency := range project.Requires {
+			targets = append(targets, dependency)
+		}
+		slices.SortFunc(targets, func(a, b deps.Dependency) int {
+			return strings.Compare(a.String(), b.String())
+		})
+		return targets, nil
+	}
+	targets := make([]deps.Dependency, 0, len(args))
+	for _, arg := range args {
+		dependency, err := deps.ParseDependency(arg)
+		if err != nil {
+			return nil, err
+		}
+		if dependency.URL == toolchain.UnobinModulePath {
+			return nil, fmt.Errorf(
+				"%s is toolchain-versioned; pin it with the project's unobin-version line",
+				dependency.URL)
+		}
+		_, required := project.Requires[dependency]
+		locked := projectLock != nil && projectLock.Deps[dependency.String()] != nil
+		if !required && !locked {
+			return nil, fmt.Errorf(
+				"%s is not a dependency of this project; add it with `unobin deps get`",
+				dependency)
+		}
+		if !slices.Contains(targets, dependency) {
+			targets = append(targets, dependency)
+		}
+	}
+	return targets, nil
+}
+
+// runDepsWhy prints the import chain that makes project-lock select a
+// dependency: the .ub file in the project that starts it and each remote
+// package imported on the way.
+func runDepsWhy(cmd *cobra.Command, cfg *depsSyncConfig, arg string) error {
+	format, err := dependencyCommandFormat(cmd)
+	if err != nil {
+		return err
+	}
+	result, err := explainDependency(cfg, arg, dependencyToolOutput(cmd, format))
+	if err != nil {
+		return dependencyCommandFailure(cmd, format, nil, err)
+	}
+	if format.Machine() {
+		return cmdout.WriteDocument(cmd.OutOrStdout(), format, result)
+	}
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "# %s %s\n", result.Dependency, result.Version)
+	for _, step := range result.Chain {
+		fmt.Fprintln(out, step)
+	}
+	return nil
+}
+
+func explainDependency(
+	cfg *depsSyncConfig,
+	arg string,
+	toolOutput io.Writer,
+) (dependencyWhyResult, error) {
+	var result dependencyWhyResult
+	dependency, err := deps.ParseDependency(arg)
+	if err != nil {
+		return result, err
+	}
+	root, err := projectRoot(cfg.stackPath)
+	if err != nil {
+		return result, err
+	}
+	projectLock, err := readProjectLock(cfg.stackPath)
+	if err != nil {
+		return result, err
+	}
+	id := dependency.String()
+	selected, ok := projectLock.Deps[id]
+	if !ok {
+		return result, fmt.Errorf("%s is not selected in %s", id, deps.ProjectLockFileName)
+	}
+	project, _, err := readProjectOrEmpty(root)
+	if err != nil {
+		return result, err
+	}
+	selection := map[deps.Dependency]string{}
+	for lockedID, locked := range projectLock.Deps {
+		lockedDependency, err := deps.ParseDependency(lockedID)
+		if err != nil {
+			return result, err
+		}
+		selection[lockedDependency] = locked.Version
+	}
+	resolver, err := newDepsResolver(root, cfg.replaceUnobin, project.Replace)
+	if err != nil {
+		return result, err
+	}
+	unobinReplace, err := printGraphUnobinReplace(root, cfg.replaceUnobin, project.Replace)
+	if err != nil {
+		return result, err
+	}
+	schemaRoots := compile.UnobinSchemaRoots(toolOutput, unobinReplace, cliVersion())
+	chains, err := deps.ImportChains(
+		os.DirFS(root), selection, resolver, project.Replace, schemaRoots)
+	if err != nil {
+		return result, err
+	}
+	chain, ok := chains[id]
+	if !ok {
+		return result, fmt.Errorf(
+			"no import reaches %s; run `unobin deps sync` to prune it", id)
+	}
+	indirect := true
+	if requirement, ok := project.Requires[dependency]; ok {
+		indirect = requirement.Indirect
+	}
+	return dependencyWhyResult{
+		Kind:          "dependency-why",
+		FormatVersion: 1,
+		Dependency:    id,
+		Version:       selected.Version,
+		Indirect:      indirect,
+		Chain:         chain,
+		Diagnostics:   dependencyDiagnostics(),
+	}, nil
+}
@@ -31,18 +31,26 @@ func TestDeveloperFormatInventoryGolden(t *testing.T) {
 	cases := []formatInventoryCommandGolden{
 		{Path: "version"},
 		{Path: "check"},
+		{Path: "lint"},
+		{Path: "test"},
 		{Path: "compile"},
 		{Path: "deps list"},
 		{Path: "deps sync"},
 		{Path: "deps get"},
 		{Path: "deps verify"},
 		{Path: "deps clean"},
+		{Path: "deps outdated"},
+		{Path: "deps update"},
+		{Path: "deps why"},
+		{Path: "deps vendor"},
 		{Path: "generate factory"},
 		{Path: "generate golibrary"},
 		{Path: "generate ublibrary"},
 		{Path: "print-graph"},
 		{Path: "fmt", Payload: true},
 		{Path: "lsp", Payload: true},
+		{Path: "proxy serve", Payload: true},
+		{Path: "sumdb serve", Payload: true},
 	}
 	for i := range cases {
 		command := findInventoryCommand(t, root, cases[i].Path)
@@ -63,12 +71,16 @@ func developerInventoryRoot() *cobra.Command {
 	root.AddCommand(
 		VersionCmd,
 		CheckCmd,
+		LintCmd,
+		TestCmd,
 		CompileCmd,
 		DepsCmd,
 		GenerateCmd,
 		PrintGraphCmd,
 		FmtCmd,
 		LSPCmd,
+		ProxyCmd,
+		SumDBCmd,
 	)
 	return root
 }
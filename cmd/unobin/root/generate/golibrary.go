@@ -1,12 +1,14 @@
 package generate
 
 import (
+	"cmp"
 	"fmt"
 	"path/filepath"
 	"strings"
 
 	"github.com/cloudboss/unobin/internal/cmdout"
 	"github.com/cloudboss/unobin/pkg/gogen"
+	"github.com/cloudboss/unobin/pkg/gogen/cfn"
 	"github.com/cloudboss/unobin/pkg/gogen/tf"
 	"github.com/spf13/cobra"
 )
@@ -15,15 +17,24 @@ var (
 	golibraryCfg = &golibraryConfig{}
 	GolibraryCmd = &cobra.Command{
 		Use:   "golibrary",
-		Short: "Generate a Go library skeleton from a TF provider schema",
+		Short: "Generate a Go library skeleton from a TF provider or CFN resource schemas",
 		Args:  cobra.NoArgs,
-		Long: "Generate a Go library from a Terraform provider schema.\n\n" +
-			"The generated Go library contains typed structs with ub tags and\n" +
-			"CRUD method stubs for every resource in the provider.\n\n" +
+		Long: "Generate a Go library from a Terraform provider schema or CloudFormation\n" +
+			"resource provider schemas.\n\n" +
+			"The generated Go library contains typed structs with ub tags for every\n" +
+			"resource. With --from tf, its CRUD methods are stubs. The provider schema\n" +
+			"comes from running terraform, or from a saved \"terraform providers schema\n" +
+			"-json\" file given with --schema-file. With --from cfn, it reads every\n" +
+			"*.json schema in --schema-dir and its CRUD methods call the AWS Cloud\n" +
+			"Control API.\n\n" +
 			"Examples:\n" +
 			"  unobin generate golibrary --from tf --provider random " +
 			"--go-module-path example.com/libraries/random\n" +
 			"  unobin generate golibrary --from tf --provider aws -o ./aws-library " +
+			"--go-module-path example.com/libraries/aws\n" +
+			"  unobin generate golibrary --from tf --schema-file provider-schema.json " +
+			"--go-module-path example.com/libraries/aws\n" +
+			"  unobin generate golibrary --from cfn --schema-dir ./schemas " +
 			"--go-module-path example.com/libraries/aws",
 
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -31,6 +42,12 @@ var (
 		},
 	}
 	newGoLibraryAdapter = func(cfg *golibraryConfig) gogen.SchemaAdapter {
+		if strings.ToLower(cfg.from) == "cfn" {
+			return cfn.NewAdapter(cfg.schemaDir)
+		}
+		if cfg.schemaFile != "" {
+			return tf.NewAdapter(tf.FileFetcher{Path: cfg.schemaFile}, cfg.provider, "")
+		}
 		return tf.NewAdapter(&tf.CLIFetcher{}, cfg.provider, cfg.providerVersion)
 	}
 )
@@ -50,6 +67,8 @@ type golibraryConfig struct {
 	from            string
 	provider        string
 	providerVersion string
+	schemaFile      string
+	schemaDir       string
 	output          string
 	goModulePath    string
 	replaceUnobin   string
@@ -58,11 +77,16 @@ type golibraryConfig struct {
 func init() {
 	GolibraryCmd.Flags().String("format", "text", cmdout.FormatHelp())
 	GolibraryCmd.Flags().StringVar(&golibraryCfg.from, "from", "tf",
-		"Schema source")
+		"Schema source: tf or cfn")
 	GolibraryCmd.Flags().StringVar(&golibraryCfg.provider, "provider", "",
-		"Terraform provider source (e.g., hashicorp/aws, ansible/ansible)")
+		"Terraform provider source (e.g., hashicorp/aws, ansible/ansible); "+
+			"with --schema-file, defaults to the file's only provider")
 	GolibraryCmd.Flags().StringVar(&golibraryCfg.providerVersion, "provider-version", "",
 		"Terraform provider version constraint (e.g., \"~> 5.0\")")
+	GolibraryCmd.Flags().StringVar(&golibraryCfg.schemaFile, "schema-file", "",
+		"Saved \"terraform providers schema -json\" output to read instead of running terraform, for --from tf")
+	GolibraryCmd.Flags().StringVar(&golibraryCfg.schemaDir, "schema-dir", "",
+		"Directory of CloudFormation resource provider schema JSON files, for --from cfn")
 	GolibraryCmd.Flags().StringVarP(&golibraryCfg.output, "output", "o", "",
 		"Output directory for the generated Go library")
 	GolibraryCmd.Flags().StringVar(&golibraryCfg.goModulePath, "go-module-path", "",
@@ -78,9 +102,35 @@ func runGenerate(cmd *cobra.Command, cfg *golibraryConfig) error {
 	if err != nil {
 		return err
 	}
-	if strings.ToLower(cfg.from) == "tf" && len(cfg.provider) == 0 {
+	switch strings.ToLower(cfg.from) {
+	case "tf":
+		if len(cfg.schemaFile) != 0 && len(cfg.providerVersion) != 0 {
+			return commandFailure(cmd, format, nil,
+				fmt.Errorf("--provider-version cannot be used with --schema-file"))
+		}
+		if len(cfg.provider) == 0 && len(cfg.schemaFile) != 0 {
+			provider, err := tf.FileFetcher{Path: cfg.schemaFile}.Provider()
+			if err != nil {
+				return commandFailure(cmd, format, nil, err)
+			}
+			withProvider := *cfg
+			withProvider.provider = provider
+			cfg = &withProvider
+		}
+		if len(cfg.provider) == 0 {
+			return commandFailure(
+				cmd, format, nil, fmt.Errorf("--provider is required when --from is 'tf'"),
+			)
+		}
+	case "cfn":
+		if len(cfg.schemaDir) == 0 {
+			return commandFailure(
+				cmd, format, nil, fmt.Errorf("--schema-dir is required when --from is 'cfn'"),
+			)
+		}
+	default:
 		return commandFailure(
-			cmd, format, nil, fmt.Errorf("--provider is required when --from is 'tf'"),
+			cmd, format, nil, fmt.Errorf("--from must be 'tf' or 'cfn', not %q", cfg.from),
 		)
 	}
 	if len(cfg.goModulePath) == 0 {
@@ -121,7 +171,7 @@ func runGenerate(cmd *cobra.Command, cfg *golibraryConfig) error {
 			FormatVersion: 1,
 			OutputDir:     out.OutDir,
 			ModulePath:    out.ModulePath,
-			Provider:      cfg.provider,
+			Provider:      cmp.Or(cfg.provider, adapter.Name()),
 			Resources:     out.Resources,
 			DataSources:   out.DataSources,
 			Files:         out.Files,
@@ -73,3 +73,15 @@ func TestGenerateGoLibraryReportsResourcesAndDataSources(t *testing.T) {
 	require.Contains(t, stderr.String(), "1 resources")
 	require.Contains(t, stderr.String(), "2 data sources")
 }
+
+func TestGenerateGoLibraryRejectsUnknownSource(t *testing.T) {
+	cmd := &cobra.Command{}
+	cmd.SetErr(&bytes.Buffer{})
+
+	err := runGenerate(cmd, &golibraryConfig{
+		from:         "dcl",
+		output:       t.TempDir(),
+		goModulePath: "example.com/demo",
+	})
+	require.ErrorContains(t, err, `--from must be 'tf' or 'cfn', not "dcl"`)
+}
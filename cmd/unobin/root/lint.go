@@ -0,0 +1,216 @@
+package root
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/cloudboss/unobin/internal/cmdout"
+	"github.com/cloudboss/unobin/pkg/deps"
+	"github.com/cloudboss/unobin/pkg/diagnostic"
+	"github.com/cloudboss/unobin/pkg/lang/syntax"
+	"github.com/cloudboss/unobin/pkg/lint"
+	"github.com/cloudboss/unobin/pkg/projectmarker"
+	"github.com/spf13/cobra"
+)
+
+var LintCmd = &cobra.Command{
+	Use:   "lint [paths...]",
+	Short: "Report style and best-practice findings in Unobin source",
+	Long: `Run the lint rules over factory and library files.
+
+With no path arguments, lint walks the current directory. Directory
+arguments are walked recursively for *.ub files; stack and project
+files are skipped.
+
+A project.ub lint block sets each rule's severity, or turns it off:
+
+  lint: {
+    unused-input: 'error'
+    trigger-always: 'off'
+  }
+
+A comment of the form "# lint:ignore code" suppresses that rule's
+findings on its own line and on the line after it.
+
+Lint exits non-zero only when a finding has error severity.
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runLint(cmd, args)
+	},
+}
+
+type lintResult struct {
+	Kind          string                  `json:"kind"           ub:"kind"`
+	FormatVersion int                     `json:"format-version" ub:"format-version"`
+	OK            bool                    `json:"ok"             ub:"ok"`
+	Files         []string                `json:"files"          ub:"files"`
+	Diagnostics   []diagnostic.Diagnostic `json:"diagnostics"    ub:"diagnostics"`
+}
+
+var errLintNegative = errors.New("lint found errors")
+
+func init() {
+	addDiagnosticFormatFlag(LintCmd)
+}
+
+func runLint(cmd *cobra.Command, args []string) error {
+	formatValue, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return err
+	}
+	format, err := cmdout.ParseDiagnosticFormat(formatValue)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+	result, err := lintPaths(args)
+	if err != nil {
+		switch format {
+		case cmdout.FormatText:
+			return err
+		case cmdout.FormatSARIF:
+			return cmdout.WriteSARIFCommandError(cmd, sarifTool(lintSARIFRules()), nil,
+				cmdout.Fail(cmdout.CodeFailed, "lint failed", err))
+		}
+		return cmdout.WriteCommandError(cmd, format, nil,
+			cmdout.Fail(cmdout.CodeFailed, "lint failed", err))
+	}
+	if format == cmdout.FormatText {
+		if err := writeLintText(cmd.OutOrStdout(), result.Diagnostics); err != nil {
+			return err
+		}
+		if !result.OK {
+			return errLintNegative
+		}
+		return nil
+	}
+	if format == cmdout.FormatSARIF {
+		err = cmdout.WriteSARIF(cmd.OutOrStdout(), sarifTool(lintSARIFRules()), result.Diagnostics)
+	} else {
+		err = cmdout.WriteDocument(cmd.OutOrStdout(), format, result)
+	}
+	if err != nil {
+		return err
+	}
+	if !result.OK {
+		return cmdout.Reported(errLintNegative)
+	}
+	return nil
+}
+
+// lintPaths lints the factory and library files under paths. A file
+// that does not parse reports its errors as findings, so one broken
+// file does not hide the rest.
+func lintPaths(paths []string) (lintResult, error) {
+	files, err := expandFmtPaths(paths)
+	if err != nil {
+		return lintResult{}, err
+	}
+	result := lintResult{Kind: "lint-result", FormatVersion: 1, Files: []string{}}
+	projects := map[string]*deps.Project{}
+	var groups [][]diagnostic.Diagnostic
+	for _, path := range files {
+		display := cleanCheckPath(path)
+		file, err := parseAndValidateSource(path)
+		if err != nil {
+			var pathErr *fs.PathError
+			if errors.As(err, &pathErr) {
+				return lintResult{}, err
+			}
+			groups = append(groups, diagnostic.FromError(err, diagnostic.ConvertOptions{
+				Path: func(string) string { return display },
+			}))
+			result.Files = append(result.Files, display)
+			continue
+		}
+		if file.Kind != syntax.FileFactory && file.Kind != syntax.FileLibrary {
+			continue
+		}
+		project, err := lintProject(path, projects)
+		if err != nil {
+			return lintResult{}, err
+		}
+		findings, err := lint.File(file, lint.Options{Path: display, Project: project})
+		if err != nil {
+			return lintResult{}, err
+		}
+		groups = append(groups, findings)
+		result.Files = append(result.Files, display)
+	}
+	result.Diagnostics = diagnostic.Merge(groups...)
+	result.OK = !hasErrorDiagnostics(result.Diagnostics)
+	return result, nil
+}
+
+// lintProject returns the project.ub governing path, or nil when path
+// is not inside a UB project. Projects are read once per root.
+func lintProject(path string, projects map[string]*deps.Project) (*deps.Project, error) {
+	root, marker, err := deps.FindProjectMarkerDir(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if marker.Kind != projectmarker.UB {
+		return nil, nil
+	}
+	if project, ok := projects[root]; ok {
+		return project, nil
+	}
+	project, err := deps.ReadProject(os.DirFS(root))
+	if err != nil {
+		return nil, diagnostic.Context(filepath.Join(root, deps.ProjectFileName), err)
+	}
+	projects[root] = project
+	return project, nil
+}
+
+// lintSARIFRules returns the metadata of every lint rule, at its
+// default severity, for a SARIF log.
+func lintSARIFRules() []cmdout.SARIFRule {
+	rules := lint.Rules()
+	out := make([]cmdout.SARIFRule, 0, len(rules))
+	for _, code := range slices.Sorted(maps.Keys(rules)) {
+		rule := rules[code]
+		out = append(out, cmdout.SARIFRule{
+			ID:          lint.CodePrefix + code,
+			Name:        code,
+			Description: rule.Description,
+			Severity:    rule.Severity,
+		})
+	}
+	return out
+}
+
+func writeLintText(out io.Writer, diagnostics []diagnostic.Diagnostic) error {
+	if len(diagnostics) == 0 {
+		_, err := fmt.Fprintln(out, "OK")
+		return err
+	}
+	return writeDiagnosticLines(out, diagnostics)
+}
+
+// writeDiagnosticLines writes one `path:line:column: severity: message
+// [code]` line per diagnostic.
+func writeDiagnosticLines(out io.Writer, diagnostics []diagnostic.Diagnostic) error {
+	for _, d := range diagnostics {
+		location := d.Path
+		if d.Span != nil {
+			location = fmt.Sprintf("%s:%d:%d", d.Path, d.Span.Start.Line, d.Span.Start.Column)
+		}
+		if _, err := fmt.Fprintf(out, "%s: %s: %s [%s]\n",
+			location, d.Severity, d.Message, d.Code); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package root
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cloudboss/unobin/pkg/lint"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintCommandPrintsOK(t *testing.T) {
+	t.Chdir(filepath.Join("testdata", "ub", "lint-command", "valid", "project"))
+
+	out, err := runCommand(t, "lint")
+
+	require.NoError(t, err)
+	require.Equal(t, "OK\n", out)
+}
+
+func TestLintCommandReportsFindings(t *testing.T) {
+	t.Chdir(filepath.Join("testdata", "ub", "lint-command", "invalid", "project"))
+
+	out, err := runCommand(t, "lint", "factory.ub")
+
+	require.ErrorIs(t, err, errLintNegative)
+	require.Equal(t,
+		"factory.ub:6:5: error: input \"extra\" is never used [unobin.lint.unused-input]\n"+
+			"factory.ub:10:5: warning: local \"unused\" is never used [unobin.lint.unused-local]\n"+
+			"Error: lint found errors\n",
+		out)
+}
+
+func TestLintCommandJSON(t *testing.T) {
+	t.Chdir(filepath.Join("testdata", "ub", "lint-command", "invalid", "project"))
+
+	out, err := runCommand(t, "lint", "--format", "json")
+
+	require.ErrorIs(t, err, errLintNegative)
+	var result lintResult
+	require.NoError(t, json.NewDecoder(strings.NewReader(out)).Decode(&result))
+	require.Equal(t, "lint-result", result.Kind)
+	require.False(t, result.OK)
+	require.Equal(t, []string{"factory.ub"}, result.Files)
+	require.Len(t, result.Diagnostics, 2)
+	require.Equal(t, "unobin.lint.unused-input", result.Diagnostics[0].Code)
+	require.Equal(t, "unobin.lint.unused-local", result.Diagnostics[1].Code)
+}
+
+func TestLintCommandSARIF(t *testing.T) {
+	t.Chdir(filepath.Join("testdata", "ub", "lint-command", "invalid", "project"))
+
+	out, err := runCommand(t, "lint", "--format", "sarif")
+
+	require.ErrorIs(t, err, errLintNegative)
+	log := decodeSARIF(t, out)
+	run := log.Runs[0]
+	require.Len(t, run.Tool.Driver.Rules, len(lint.Rules()))
+	require.Len(t, run.Results, 2)
+	result := run.Results[0]
+	require.Equal(t, "unobin.lint.unused-input", result.RuleID)
+	require.Equal(t, "error", result.Level)
+	require.Equal(t, "factory.ub", result.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	require.Equal(t, 6, result.Locations[0].PhysicalLocation.Region.StartLine)
+	require.Equal(t, "warning", run.Results[1].Level)
+}
@@ -143,6 +143,11 @@ func buildSourceGraph(
 	if err != nil {
 		return nil, "", err
 	}
+	vendor, err := compile.OpenVendor(projectDir, projectLock)
+	if err != nil {
+		return nil, "", err
+	}
+	resolver = vendor.Wrap(resolver)
 	resolver = compile.WrapProjectLockSources(resolver, projectLock)
 	resolver, err = compile.WrapReplaces(resolver, projectDir, cfg.replaceUnobin, replaceMap)
 	if err != nil {
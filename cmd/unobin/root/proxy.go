@@ -0,0 +1,76 @@
+package root
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/cloudboss/unobin/pkg/proxy"
+	"github.com/spf13/cobra"
+)
+
+// ProxyCmd is the parent for the UB project proxy subcommands.
+var ProxyCmd = &cobra.Command{
+	Use:   "proxy",
+	Short: "Serve UB project sources over the proxy protocol",
+	Long: `Serve UB project sources over the proxy protocol that UNOBIN_PROXY names.
+
+UNOBIN_PROXY is a comma-separated list of proxy URLs, direct, and off,
+tried in order; a proxy that does not have a repository or version passes
+it on to the next entry. It defaults to direct, fetching from the git host.`,
+}
+
+var (
+	proxyServeAddr string
+	proxyServeCmd  = &cobra.Command{
+		Use:   "serve",
+		Short: "Serve the local import cache to other unobin clients",
+		Long: `Serve the local import cache to other unobin clients.
+
+Every repository unobin has fetched into the import cache is served at
+the versions and commits it was fetched at. Nothing is fetched on demand,
+so run deps sync or compile on this machine to fill the cache.`,
+		Args: cobra.NoArgs,
+		RunE: runProxyServe,
+	}
+)
+
+func init() {
+	proxyServeCmd.Flags().StringVar(&proxyServeAddr, "addr", "localhost:7070",
+		"Address to listen on.")
+	ProxyCmd.AddCommand(proxyServeCmd)
+}
+
+func runProxyServe(cmd *cobra.Command, args []string) error {
+	resolver, err := newRemoteResolver()
+	if err != nil {
+		return err
+	}
+	listener, err := net.Listen("tcp", proxyServeAddr)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "Serving %s on http://%s\n",
+		resolver.ImportsDir(), listener.Addr())
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+	defer stop()
+	server := &http.Server{
+		Handler:           proxy.NewHandler(resolver.ImportsDir()),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+	if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
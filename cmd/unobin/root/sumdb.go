@@ -0,0 +1,137 @@
+package root
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cloudboss/unobin/pkg/deps"
+	"github.com/cloudboss/unobin/pkg/sumdb"
+	"github.com/spf13/cobra"
+	"golang.org/x/mod/sumdb/note"
+)
+
+// SumDBCmd is the parent for the checksum database subcommands.
+var SumDBCmd = &cobra.Command{
+	Use:   "sumdb",
+	Short: "Serve a checksum database of UB project hashes",
+	Long: `Serve a checksum database of UB project hashes.
+
+With UNOBIN_SUMDB set to a database's verifier key and URL, deps sync,
+get, and update check every UB project hash they add to project-lock.ub
+against the database before writing it. Ids matching a glob prefix in the
+comma-separated UNOBIN_NOSUMDB are not checked.`,
+}
+
+var (
+	sumdbServeAddr string
+	sumdbServeDir  string
+	sumdbServeName string
+	sumdbServeCmd  = &cobra.Command{
+		Use:   "serve",
+		Short: "Serve a checksum database from a local log",
+		Long: `Serve a checksum database from a local log.
+
+The log is an append-only file of project hashes under --dir, with its
+signing key beside it; the first run creates both. A lookup of a version
+the log does not have fetches the project, hashes it, and appends it.
+The verifier key clients set in UNOBIN_SUMDB is printed at startup.`,
+		Args: cobra.NoArgs,
+		RunE: runSumDBServe,
+	}
+)
+
+func init() {
+	sumdbServeCmd.Flags().StringVar(&sumdbServeAddr, "addr", "localhost:7071",
+		"Address to listen on.")
+	sumdbServeCmd.Flags().StringVar(&sumdbServeDir, "dir", "",
+		"Directory holding the log and signing key. Defaults to sumdb-log under the import cache root.")
+	sumdbServeCmd.Flags().StringVar(&sumdbServeName, "name", "localhost",
+		"Name of the signing key the first run creates.")
+	SumDBCmd.AddCommand(sumdbServeCmd)
+}
+
+func runSumDBServe(cmd *cobra.Command, args []string) error {
+	resolver, err := newRemoteResolver()
+	if err != nil {
+		return err
+	}
+	dir := sumdbServeDir
+	if dir == "" {
+		dir = filepath.Join(resolver.CacheRoot, "sumdb-log")
+	}
+	log, err := sumdb.OpenLog(dir)
+	if err != nil {
+		return err
+	}
+	signer, vkey, err := sumdbSigner(dir, sumdbServeName)
+	if err != nil {
+		return err
+	}
+	listener, err := net.Listen("tcp", sumdbServeAddr)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "Serving %s on http://%s\nUNOBIN_SUMDB=\"%s http://%s\"\n",
+		dir, listener.Addr(), vkey, listener.Addr())
+	hash := func(_ context.Context, id, version string) (string, error) {
+		return deps.HashRemoteProject(resolver, id, version)
+	}
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+	defer stop()
+	server := &http.Server{
+		Handler:           sumdb.NewHandler(log, signer, hash),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+	if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// sumdbSigner reads the signing key in dir, creating one named name if
+// dir has none, and returns it with its verifier key.
+func sumdbSigner(dir, name string) (note.Signer, string, error) {
+	keyPath := filepath.Join(dir, "key")
+	pubPath := filepath.Join(dir, "key.pub")
+	skey, err := os.ReadFile(keyPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		newSkey, newVkey, err := note.GenerateKey(rand.Reader, name)
+		if err != nil {
+			return nil, "", err
+		}
+		if err := os.WriteFile(keyPath, []byte(newSkey+"\n"), 0o600); err != nil {
+			return nil, "", err
+		}
+		if err := os.WriteFile(pubPath, []byte(newVkey+"\n"), 0o644); err != nil {
+			return nil, "", err
+		}
+		skey = []byte(newSkey)
+	} else if err != nil {
+		return nil, "", err
+	}
+	signer, err := note.NewSigner(strings.TrimSpace(string(skey)))
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: %w", keyPath, err)
+	}
+	vkey, err := os.ReadFile(pubPath)
+	if err != nil {
+		return nil, "", err
+	}
+	return signer, strings.TrimSpace(string(vkey)), nil
+}
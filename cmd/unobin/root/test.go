@@ -0,0 +1,232 @@
+package root
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudboss/unobin/internal/cmdout"
+	"github.com/cloudboss/unobin/pkg/diagnostic"
+	"github.com/cloudboss/unobin/pkg/factorytest"
+	"github.com/cloudboss/unobin/pkg/lang/syntax"
+	"github.com/cloudboss/unobin/pkg/sourcecheck"
+	"github.com/spf13/cobra"
+)
+
+var (
+	testCfg = &testConfig{}
+	TestCmd = &cobra.Command{
+		Use:   "test [paths...]",
+		Short: "Run a factory's *_test.ub files against mocked libraries",
+		Long: `Run the cases of each *_test.ub file against the factory.ub beside it.
+
+With no path arguments, test walks the current directory. Directory
+arguments are walked recursively for *_test.ub files.
+
+Each case plans and applies the factory with the runtime a compiled
+factory uses, but every Go library type returns the outputs the case
+mocks for it, and state lives in memory. A test run makes no cloud calls
+and writes no state. Go library functions cannot run outside a compiled
+factory, so a factory that calls one cannot be tested this way.
+
+Test exits non-zero when a case fails or a factory does not check.
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTest(cmd, testCfg, args)
+		},
+	}
+)
+
+type testConfig struct {
+	replaceUnobin string
+}
+
+type testCaseResult struct {
+	File     string   `json:"file"     ub:"file"`
+	Name     string   `json:"name"     ub:"name"`
+	OK       bool     `json:"ok"       ub:"ok"`
+	Failures []string `json:"failures" ub:"failures"`
+}
+
+type testResult struct {
+	Kind          string                  `json:"kind"           ub:"kind"`
+	FormatVersion int                     `json:"format-version" ub:"format-version"`
+	OK            bool                    `json:"ok"             ub:"ok"`
+	Tests         []testCaseResult        `json:"tests"          ub:"tests"`
+	Diagnostics   []diagnostic.Diagnostic `json:"diagnostics"    ub:"diagnostics"`
+}
+
+var errTestNegative = errors.New("tests failed")
+
+func init() {
+	addFormatFlag(TestCmd)
+	TestCmd.Flags().StringVar(&testCfg.replaceUnobin, "replace-unobin", "",
+		"Local path to substitute for github.com/cloudboss/unobin so schema checks read it.")
+}
+
+func runTest(cmd *cobra.Command, cfg *testConfig, args []string) error {
+	formatValue, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return err
+	}
+	format, err := cmdout.ParseFormat(formatValue)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+	result, err := testPaths(cmd, cfg, args)
+	if err != nil {
+		if format == cmdout.FormatText {
+			return err
+		}
+		return cmdout.WriteCommandError(cmd, format, nil,
+			cmdout.Fail(cmdout.CodeFailed, "test failed", err))
+	}
+	if format == cmdout.FormatText {
+		if err := writeTestText(cmd, result); err != nil {
+			return err
+		}
+		if !result.OK {
+			return errTestNegative
+		}
+		return nil
+	}
+	if err := cmdout.WriteDocument(cmd.OutOrStdout(), format, result); err != nil {
+		return err
+	}
+	if !result.OK {
+		return cmdout.Reported(errTestNegative)
+	}
+	return nil
+}
+
+// testPaths runs the test files under paths. A test file or factory
+// that does not check reports its errors as diagnostics, so one broken
+// factory does not hide the other results.
+func testPaths(cmd *cobra.Command, cfg *testConfig, paths []string) (testResult, error) {
+	files, err := expandFmtPaths(paths)
+	if err != nil {
+		return testResult{}, err
+	}
+	result := testResult{Kind: "test-result", FormatVersion: 1, Tests: []testCaseResult{}}
+	factories := map[string]*factorytest.Factory{}
+	collector := &diagnostic.Collector{}
+	var groups [][]diagnostic.Diagnostic
+	for _, path := range files {
+		if !strings.HasSuffix(path, "_test.ub") {
+			continue
+		}
+		display := cleanCheckPath(path)
+		factory, file, err := loadTest(cmd, cfg, path, factories, collector)
+		if err != nil {
+			var pathErr *fs.PathError
+			if errors.As(err, &pathErr) {
+				return testResult{}, err
+			}
+			groups = append(groups, diagnostic.FromError(err, diagnostic.ConvertOptions{
+				Path: checkPathMapper(filepath.Dir(path)).Display,
+			}))
+			continue
+		}
+		for _, r := range factorytest.RunFile(cmd.Context(), *factory, file.Test) {
+			failures := r.Failures
+			if failures == nil {
+				failures = []string{}
+			}
+			result.Tests = append(result.Tests, testCaseResult{
+				File:     display,
+				Name:     r.Name,
+				OK:       r.Passed(),
+				Failures: failures,
+			})
+		}
+	}
+	groups = append(groups, collector.Diagnostics())
+	result.Diagnostics = diagnostic.Merge(groups...)
+	result.OK = !hasErrorDiagnostics(result.Diagnostics)
+	for _, test := range result.Tests {
+		result.OK = result.OK && test.OK
+	}
+	return result, nil
+}
+
+// loadTest parses the test file at path and checks the factory.ub
+// beside it. Factories are checked once per directory, and reporter
+// receives the warnings of each check.
+func loadTest(
+	cmd *cobra.Command,
+	cfg *testConfig,
+	path string,
+	factories map[string]*factorytest.Factory,
+	reporter diagnostic.Reporter,
+) (*factorytest.Factory, *syntax.File, error) {
+	file, err := parseAndValidateSource(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if file.Kind != syntax.FileTest {
+		return nil, nil, fmt.Errorf("%s does not declare tests", path)
+	}
+	dir := filepath.Dir(path)
+	if factory, ok := factories[dir]; ok {
+		return factory, file, nil
+	}
+	factoryPath := filepath.Join(dir, "factory.ub")
+	source, err := parseAndValidateSource(factoryPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil, fmt.Errorf("%s has no factory.ub beside it", path)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if source.Kind != syntax.FileFactory {
+		return nil, nil, fmt.Errorf("%s does not declare a factory", factoryPath)
+	}
+	opts, err := checkOptions(cmd, dir, dir, cfg.replaceUnobin, nil, reporter)
+	if err != nil {
+		return nil, nil, err
+	}
+	opts.RootSourceFile = sourceFileForProject(opts.ProjectDir, factoryPath)
+	checked, err := sourcecheck.CheckFactoryBody(source.Factory.Body, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	factory := &factorytest.Factory{
+		Body:                 &source.Factory.Body,
+		Libraries:            checked.Libraries,
+		LibraryConfigSchemas: checked.LibraryConfigSchemas,
+		AssetCatalog:         checked.AssetCatalog,
+		RootAssetSetID:       checked.RootAssetSetID,
+	}
+	factories[dir] = factory
+	return factory, file, nil
+}
+
+func writeTestText(cmd *cobra.Command, result testResult) error {
+	if err := writeDiagnosticLines(cmd.ErrOrStderr(), result.Diagnostics); err != nil {
+		return err
+	}
+	out := cmd.OutOrStdout()
+	passed := 0
+	for _, test := range result.Tests {
+		status := "FAIL"
+		if test.OK {
+			status = "ok"
+			passed++
+		}
+		if _, err := fmt.Fprintf(out, "%-4s %s: %s\n", status, test.File, test.Name); err != nil {
+			return err
+		}
+		for _, failure := range test.Failures {
+			if _, err := fmt.Fprintf(out, "     %s\n", failure); err != nil {
+				return err
+			}
+		}
+	}
+	_, err := fmt.Fprintf(out, "%d passed, %d failed\n", passed, len(result.Tests)-passed)
+	return err
+}
@@ -0,0 +1,80 @@
+package root
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestCommandPasses(t *testing.T) {
+	setCLIVersion(t, "dev")
+	t.Chdir(filepath.Join("testdata", "ub", "test-command", "valid", "greeting"))
+
+	out, err := runCommand(t, "test")
+
+	require.NoError(t, err)
+	require.Equal(t,
+		"ok   greeting_test.ub: greets by name\n"+
+			"ok   greeting_test.ub: rejects an empty name\n"+
+			"2 passed, 0 failed\n",
+		out)
+}
+
+func TestTestCommandReportsFailures(t *testing.T) {
+	setCLIVersion(t, "dev")
+	t.Chdir(filepath.Join("testdata", "ub", "test-command", "invalid", "greeting"))
+
+	out, err := runCommand(t, "test", "greeting_test.ub")
+
+	require.ErrorIs(t, err, errTestNegative)
+	require.Equal(t,
+		"ok   greeting_test.ub: greets by name\n"+
+			"FAIL greeting_test.ub: greets formally\n"+
+			"     output greeting is 'hello, world', want 'good day, world'\n"+
+			"1 passed, 1 failed\n"+
+			"Error: tests failed\n",
+		out)
+}
+
+func TestTestCommandJSON(t *testing.T) {
+	setCLIVersion(t, "dev")
+	t.Chdir(filepath.Join("testdata", "ub", "test-command", "invalid", "greeting"))
+
+	out, err := runCommand(t, "test", "--format", "json")
+
+	require.ErrorIs(t, err, errTestNegative)
+	var result testResult
+	require.NoError(t, json.NewDecoder(strings.NewReader(out)).Decode(&result))
+	require.Equal(t, "test-result", result.Kind)
+	require.False(t, result.OK)
+	require.Equal(t, []testCaseResult{
+		{File: "greeting_test.ub", Name: "greets by name", OK: true, Failures: []string{}},
+		{File: "greeting_test.ub", Name: "greets formally", Failures: []string{
+			"output greeting is 'hello, world', want 'good day, world'",
+		}},
+	}, result.Tests)
+	require.Empty(t, result.Diagnostics)
+}
+
+func TestTestCommandNeedsFactory(t *testing.T) {
+	dir := t.TempDir()
+	copyTestFixture(t, filepath.Join("valid", "greeting", "greeting_test.ub"),
+		filepath.Join(dir, "greeting_test.ub"))
+	t.Chdir(dir)
+
+	out, err := runCommand(t, "test")
+
+	require.ErrorIs(t, err, errTestNegative)
+	require.Contains(t, out, "greeting_test.ub has no factory.ub beside it")
+}
+
+func copyTestFixture(t *testing.T, name, dst string) {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", "ub", "test-command", name))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(dst, body, 0o644))
+}
@@ -0,0 +1,280 @@
+package root
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/cloudboss/unobin/internal/cmdout"
+	"github.com/cloudboss/unobin/pkg/compile"
+	"github.com/cloudboss/unobin/pkg/diagnostic"
+	"github.com/cloudboss/unobin/pkg/filechange"
+	"github.com/cloudboss/unobin/pkg/sourcecheck"
+	"github.com/spf13/cobra"
+)
+
+// watchInterval is how often watch mode scans for changes. A change is
+// acted on once a scan finds nothing newer, so a burst of saves runs the
+// command once. Tests shorten it.
+var watchInterval = 250 * time.Millisecond
+
+// watchChangeRecord announces the changes that start another run in
+// watch mode. The run's own result record follows it.
+type watchChangeRecord struct {
+	Kind          string              `json:"kind"           ub:"kind"`
+	FormatVersion int                 `json:"format-version" ub:"format-version"`
+	Files         []filechange.Change `json:"files"          ub:"files"`
+}
+
+// watchTarget is a command that watch mode runs again on change.
+type watchTarget struct {
+	format cmdout.Format
+	// roots returns the paths to watch. It is called again after each
+	// run, since an edit to project.ub or the factory's assets can move
+	// them.
+	roots func() ([]string, error)
+	skip  func(path string, entry fs.DirEntry) bool
+	// run runs the command once. keepCache is false when a change
+	// reached more than UB source, which cached analysis does not track.
+	run func(keepCache bool) error
+}
+
+func addWatchFlag(cmd *cobra.Command, watch *bool) {
+	cmd.Flags().BoolVar(watch, "watch", false,
+		"Run again whenever the project, its local replacements, or its assets change,"+
+			" until interrupted.")
+}
+
+func watchCheck(cmd *cobra.Command, cfg *checkConfig, format cmdout.Format) error {
+	if format == cmdout.FormatSARIF {
+		return errors.New("--watch streams records and cannot write a SARIF log")
+	}
+	cache := sourcecheck.NewCache()
+	return runWatch(cmd, watchTarget{
+		format: format,
+		roots: func() ([]string, error) {
+			return watchRoots(cfg.path, cfg.replaceUnobin, nil)
+		},
+		skip: watchSkip(),
+		run: func(keepCache bool) error {
+			if !keepCache {
+				cache = sourcecheck.NewCache()
+			}
+			return checkOnce(cmd, cfg, format, cache)
+		},
+	})
+}
+
+func watchCompile(
+	cmd *cobra.Command,
+	cfg *compileConfig,
+	format cmdout.Format,
+	options compile.Options,
+) error {
+	if cfg.outDir == "" || cfg.outDir == "-" {
+		err := errors.New("--watch needs --out to name an output directory")
+		if format.Machine() {
+			return writeCompileCommandFailure(
+				cmd, format, &diagnostic.Collector{}, nil,
+				compilePathMapper(cfg, nil), cmdout.CodeInvalidArgs, err,
+			)
+		}
+		return err
+	}
+	outDir, err := filepath.Abs(cfg.outDir)
+	if err != nil {
+		return err
+	}
+	options.Cache = sourcecheck.NewCache()
+	return runWatch(cmd, watchTarget{
+		format: format,
+		roots: func() ([]string, error) {
+			return watchRoots(cfg.factoryPath, cfg.replaceUnobin, options.ReplaceGoModules)
+		},
+		skip: watchSkip(outDir),
+		run: func(keepCache bool) error {
+			if !keepCache {
+				options.Cache = sourcecheck.NewCache()
+			}
+			return compileOnce(cmd, cfg, format, options)
+		},
+	})
+}
+
+// runWatch runs target, then runs it again after each settled change
+// under its roots until the command's context ends or it is
+// interrupted. A run that fails is reported and the watch goes on.
+func runWatch(cmd *cobra.Command, target watchTarget) error {
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	roots, err := target.roots()
+	if err != nil {
+		return err
+	}
+	watcher, err := filechange.NewWatcher(roots, target.skip, watchInterval)
+	if err != nil {
+		return err
+	}
+	if err := runWatchTarget(cmd, target, true); err != nil {
+		return err
+	}
+	for {
+		changes, err := watcher.Next(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := writeWatchChanges(cmd, target.format, changes); err != nil {
+			return err
+		}
+		if err := runWatchTarget(cmd, target, watchKeepsCache(changes)); err != nil {
+			return err
+		}
+		// A project file that no longer reads keeps the roots it had,
+		// and the run has already reported why.
+		if roots, err := target.roots(); err == nil {
+			if err := watcher.SetRoots(roots); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// runWatchTarget runs target once. A failure the run reported, or one it
+// can report now, keeps the watch going; only a failure to write output
+// ends it.
+func runWatchTarget(cmd *cobra.Command, target watchTarget, keepCache bool) error {
+	err := target.run(keepCache)
+	if err == nil || cmdout.IsReported(err) {
+		return nil
+	}
+	if !target.format.Machine() {
+		cmdout.PrintUnreportedError(cmd, err)
+		return nil
+	}
+	if err := cmdout.WriteCommandError(cmd, target.format, nil, err); !cmdout.IsReported(err) {
+		return err
+	}
+	return nil
+}
+
+func writeWatchChanges(cmd *cobra.Command, format cmdout.Format, changes []filechange.Change) error {
+	files := make([]filechange.Change, len(changes))
+	for i, change := range changes {
+		files[i] = filechange.Change{Path: watchDisplayPath(change.Path), Action: change.Action}
+	}
+	if format.Machine() {
+		return cmdout.WriteRecord(cmd.OutOrStdout(), format, watchChangeRecord{
+			Kind:          "watch-change",
+			FormatVersion: 1,
+			Files:         files,
+		})
+	}
+	for _, file := range files {
+		if _, err := fmt.Fprintf(cmd.ErrOrStderr(), "%s %s\n", file.Action, file.Path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// watchRoots returns the paths a watch of the source at path covers:
+// its project root, the local replacements in project.ub and on the
+// command line, and the asset sources the factory declares. Hidden
+// directories under a root are skipped, so an asset is watched through
+// its own root as well.
+func watchRoots(
+	path string,
+	replaceUnobin string,
+	replaceGoModules map[string]string,
+) ([]string, error) {
+	absolute, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	sourceDir := absolute
+	factoryPath := filepath.Join(absolute, "factory.ub")
+	if info, err := os.Stat(absolute); err == nil && !info.IsDir() {
+		sourceDir = filepath.Dir(absolute)
+		factoryPath = absolute
+	}
+	projectDir, err := printGraphProjectDir(sourceDir)
+	if err != nil {
+		return nil, err
+	}
+	roots := []string{projectDir}
+	if project, err := printGraphProject(projectDir); err == nil && project != nil {
+		for _, local := range project.Replace {
+			replacement, err := printGraphAbsReplacePath(projectDir, local)
+			if err != nil {
+				return nil, err
+			}
+			roots = append(roots, replacement)
+		}
+	}
+	if replaceUnobin != "" {
+		replacement, err := filepath.Abs(replaceUnobin)
+		if err != nil {
+			return nil, err
+		}
+		roots = append(roots, replacement)
+	}
+	for _, local := range replaceGoModules {
+		roots = append(roots, local)
+	}
+	if file, err := parseAndValidateSource(factoryPath); err == nil && file.Factory != nil {
+		for _, decl := range file.Factory.Body.Assets {
+			if decl.Source != nil {
+				roots = append(roots, filepath.Join(filepath.Dir(factoryPath), decl.Source.Value))
+			}
+		}
+	}
+	slices.Sort(roots)
+	return slices.Compact(roots), nil
+}
+
+// watchSkip leaves hidden files and directories, editor backups, and the
+// directories in skipped, such as the compile output, out of a watch.
+func watchSkip(skipped ...string) func(string, fs.DirEntry) bool {
+	return func(path string, entry fs.DirEntry) bool {
+		name := entry.Name()
+		if strings.HasPrefix(name, ".") || strings.HasSuffix(name, "~") {
+			return true
+		}
+		return entry.IsDir() && slices.Contains(skipped, path)
+	}
+}
+
+// watchKeepsCache reports whether changes reached only UB source, which
+// cached analysis tracks by content. Go source, project files, and
+// assets start a fresh cache.
+func watchKeepsCache(changes []filechange.Change) bool {
+	for _, change := range changes {
+		name := filepath.Base(change.Path)
+		if filepath.Ext(name) != ".ub" || name == "project.ub" || name == "project-lock.ub" {
+			return false
+		}
+	}
+	return true
+}
+
+func watchDisplayPath(path string) string {
+	workingDir, err := os.Getwd()
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	relative, err := filepath.Rel(workingDir, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(relative)
+}
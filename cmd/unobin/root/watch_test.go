@@ -0,0 +1,138 @@
+package root
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloudboss/unobin/pkg/filechange"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckWatchStreamsRecordsOnChange(t *testing.T) {
+	setCLIVersion(t, "dev")
+	setWatchInterval(t, 10*time.Millisecond)
+	dir := t.TempDir()
+	copyWatchFixture(t, filepath.Join("valid", "default-factory", "factory.ub"),
+		filepath.Join(dir, "factory.ub"))
+	edit, err := filepath.Abs(filepath.Join(
+		"testdata", "ub", "check-command", "invalid", "watch-edit", "factory.ub"))
+	require.NoError(t, err)
+	t.Chdir(dir)
+	ctx, cancel := context.WithCancel(context.Background())
+	out := &watchOutput{}
+	done := make(chan error, 1)
+	go func() {
+		done <- executeCommand(t, ctx, nil, out, "check", "--watch", "--format", "json")
+	}()
+
+	out.waitRecords(t, 1)
+	body, err := os.ReadFile(edit)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "factory.ub"), body, 0o644))
+	records := out.waitRecords(t, 3)
+	cancel()
+
+	require.NoError(t, <-done)
+	require.Equal(t, "check-result", records[0]["kind"])
+	require.Equal(t, true, records[0]["ok"])
+	require.Equal(t, "watch-change", records[1]["kind"])
+	require.Equal(t, []any{map[string]any{"path": "factory.ub", "action": "updated"}},
+		records[1]["files"])
+	require.Equal(t, "check-result", records[2]["kind"])
+	require.Equal(t, false, records[2]["ok"])
+}
+
+func TestCheckWatchRejectsSARIF(t *testing.T) {
+	out, err := runCommand(t, "check", "--watch", "--format", "sarif")
+
+	require.Error(t, err)
+	require.Contains(t, out, "cannot write a SARIF log")
+}
+
+func TestCompileWatchNeedsOutputDirectory(t *testing.T) {
+	out, err := runCommand(t, "compile", "--watch", "--out", "-")
+
+	require.Error(t, err)
+	require.Contains(t, out, "--watch needs --out")
+}
+
+func TestWatchRootsCoverReplacementsAndAssets(t *testing.T) {
+	project, err := filepath.Abs(filepath.Join(
+		"testdata", "ub", "check-command", "valid", "watch-roots", "project"))
+	require.NoError(t, err)
+
+	roots, err := watchRoots(project, "", map[string]string{"example.com/go": "/replaced/go"})
+
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"/replaced/go",
+		filepath.Join(filepath.Dir(project), "lib"),
+		project,
+		filepath.Join(project, ".settings"),
+	}, roots)
+}
+
+func TestWatchKeepsCacheOnlyForUBSource(t *testing.T) {
+	ub := filechange.Change{Path: "/p/lib/library.ub", Action: filechange.ActionUpdated}
+	goSource := filechange.Change{Path: "/p/lib/lib.go", Action: filechange.ActionUpdated}
+	project := filechange.Change{Path: "/p/project.ub", Action: filechange.ActionUpdated}
+
+	require.True(t, watchKeepsCache([]filechange.Change{ub}))
+	require.False(t, watchKeepsCache([]filechange.Change{ub, goSource}))
+	require.False(t, watchKeepsCache([]filechange.Change{project}))
+}
+
+func setWatchInterval(t *testing.T, interval time.Duration) {
+	t.Helper()
+	prev := watchInterval
+	watchInterval = interval
+	t.Cleanup(func() { watchInterval = prev })
+}
+
+func copyWatchFixture(t *testing.T, name, dst string) {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", "ub", "check-command", name))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(dst, body, 0o644))
+}
+
+// watchOutput collects a watching command's output while the test reads
+// it.
+type watchOutput struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (o *watchOutput) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.buf.Write(p)
+}
+
+// waitRecords waits until the output holds count JSON records and
+// returns them decoded.
+func (o *watchOutput) waitRecords(t *testing.T, count int) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	require.Eventually(t, func() bool {
+		o.mu.Lock()
+		text := o.buf.String()
+		o.mu.Unlock()
+		records = nil
+		for line := range strings.Lines(text) {
+			var record map[string]any
+			if json.Unmarshal([]byte(line), &record) == nil {
+				records = append(records, record)
+			}
+		}
+		return len(records) >= count
+	}, 10*time.Second, 10*time.Millisecond)
+	return records
+}
@@ -138,7 +138,7 @@ Emacs session.  When t, install automatically."
      "(primary_expression (identifier) @font-lock-variable-name-face)"
      ""
      "((field_key (identifier) @font-lock-keyword-face)"
-     " (#match? @font-lock-keyword-face \"^(actions|assets|configurations|constraints|data-sources|deps|encryption|factory|imports|inputs|library|library-configs|locals|outputs|parallelism|pin|project|project-lock|replace|requires|resources|stack|state|state-moves|toolchain|unobin-version|version)$\"))"
+     " (#match? @font-lock-keyword-face \"^(actions|assets|checks|configurations|constraints|data-sources|deps|encryption|factory|imports|inputs|library|library-configs|locals|outputs|parallelism|pin|project|project-lock|replace|requires|resources|stack|state|state-moves|toolchain|types|unobin-version|version)$\"))"
      ""
      "((field_key (identifier) @font-lock-preprocessor-face)"
      " (#match? @font-lock-preprocessor-face \"^@\"))"
@@ -157,11 +157,11 @@ Emacs session.  When t, install automatically."
    "\n"))
 
 (defconst unobin-ts-mode--field-keywords
-  '("actions" "assets" "configurations" "constraints" "data-sources" "deps"
+  '("actions" "assets" "checks" "configurations" "constraints" "data-sources" "deps"
     "encryption" "factory" "imports" "inputs" "library" "library-configs" "locals"
     "outputs" "parallelism" "pin" "project" "project-lock" "replace" "requires"
-    "resources" "stack" "state" "state-moves" "toolchain" "unobin-version"
-    "version"))
+    "resources" "stack" "state" "state-moves" "toolchain" "types"
+    "unobin-version" "version"))
 
 (defconst unobin-ts-mode--reference-roots
   '("@core" "@each" "@self" "action" "asset" "data-source" "input" "local"
@@ -108,14 +108,33 @@ func WriteCommandError(
 	collected []diagnostic.Diagnostic,
 	failure error,
 ) error {
-	command := CommandName(cmd)
-	summary := "command failed"
-	if command != "" {
-		summary = command + " failed"
-	}
 	if failure == nil {
-		failure = errors.New(summary)
+		failure = errors.New(commandSummary(cmd))
+	}
+	response, err := commandErrorFor(cmd, collected, failure)
+	if err != nil {
+		return err
+	}
+	if err := WriteDocument(cmd.OutOrStdout(), format, response); err != nil {
+		return err
+	}
+	return Reported(failure)
+}
+
+func commandSummary(cmd *cobra.Command) string {
+	if command := CommandName(cmd); command != "" {
+		return command + " failed"
 	}
+	return "command failed"
+}
+
+func commandErrorFor(
+	cmd *cobra.Command,
+	collected []diagnostic.Diagnostic,
+	failure error,
+) (CommandError, error) {
+	command := CommandName(cmd)
+	summary := commandSummary(cmd)
 	commandFailure, ok := AsFailure(failure)
 	if !ok {
 		commandFailure = &Failure{
@@ -145,9 +164,9 @@ func WriteCommandError(
 	)
 	files, err := filechange.Compose(commandFailure.Files)
 	if err != nil {
-		return fmt.Errorf("command error files: %w", err)
+		return CommandError{}, fmt.Errorf("command error files: %w", err)
 	}
-	response := CommandError{
+	return CommandError{
 		Kind:          "command-error",
 		FormatVersion: 1,
 		Command:       command,
@@ -155,9 +174,5 @@ func WriteCommandError(
 		Message:       message,
 		Diagnostics:   diagnostics,
 		Files:         files,
-	}
-	if err := WriteDocument(cmd.OutOrStdout(), format, response); err != nil {
-		return err
-	}
-	return Reported(failure)
+	}, nil
 }
@@ -8,6 +8,7 @@ const (
 	FormatText   Format = "text"
 	FormatJSON   Format = "json"
 	FormatUnobin Format = "unobin"
+	FormatSARIF  Format = "sarif"
 )
 
 func ParseFormat(value string) (Format, error) {
@@ -26,10 +27,30 @@ func ParseFormat(value string) (Format, error) {
 	}
 }
 
+// ParseDiagnosticFormat parses the format of a command whose result is
+// a list of diagnostics. Such a command can also write SARIF.
+func ParseDiagnosticFormat(value string) (Format, error) {
+	if value == string(FormatSARIF) {
+		return FormatSARIF, nil
+	}
+	format, err := ParseFormat(value)
+	if err != nil {
+		return "", fmt.Errorf(
+			"--format: unknown '%s' (want text, json, unobin, sarif)",
+			value,
+		)
+	}
+	return format, nil
+}
+
 func FormatHelp() string {
 	return "Output format: text, json, unobin."
 }
 
+func DiagnosticFormatHelp() string {
+	return "Output format: text, json, unobin, sarif."
+}
+
 func (f Format) Machine() bool {
 	return f == FormatJSON || f == FormatUnobin
 }
@@ -3,8 +3,10 @@ package cmdout
 import "testing"
 
 type formatGolden struct {
-	Help  string             `json:"help"`
-	Cases []formatCaseGolden `json:"cases"`
+	Help            string             `json:"help"`
+	Cases           []formatCaseGolden `json:"cases"`
+	DiagnosticHelp  string             `json:"diagnostic-help"`
+	DiagnosticCases []formatCaseGolden `json:"diagnostic-cases"`
 }
 
 type formatCaseGolden struct {
@@ -16,7 +18,7 @@ type formatCaseGolden struct {
 
 func TestFormatGolden(t *testing.T) {
 	result := formatGolden{Help: FormatHelp()}
-	for _, input := range []string{"", "text", "json", "unobin", "yaml", "JSON"} {
+	for _, input := range []string{"", "text", "json", "unobin", "sarif", "yaml", "JSON"} {
 		format, err := ParseFormat(input)
 		result.Cases = append(result.Cases, formatCaseGolden{
 			Input:   input,
@@ -25,5 +27,15 @@ func TestFormatGolden(t *testing.T) {
 			Error:   cmdoutErrorString(err),
 		})
 	}
+	result.DiagnosticHelp = DiagnosticFormatHelp()
+	for _, input := range []string{"", "json", "sarif", "SARIF", "yaml"} {
+		format, err := ParseDiagnosticFormat(input)
+		result.DiagnosticCases = append(result.DiagnosticCases, formatCaseGolden{
+			Input:   input,
+			Format:  format,
+			Machine: format.Machine(),
+			Error:   cmdoutErrorString(err),
+		})
+	}
 	requireCmdoutGolden(t, "testdata/format.json", result)
 }
@@ -0,0 +1,286 @@
+package cmdout
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"io"
+	"slices"
+
+	"github.com/cloudboss/unobin/pkg/diagnostic"
+	"github.com/spf13/cobra"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// SARIFTool describes the tool that produced a SARIF log. Rules holds
+// metadata for the codes the tool can report; a code a result uses
+// without metadata gets a rule with only its id.
+type SARIFTool struct {
+	Name           string
+	Version        string
+	InformationURI string
+	Rules          []SARIFRule
+}
+
+// SARIFRule is the metadata of one diagnostic code. Name is a short
+// name for the code, and Severity the level it reports at by default.
+type SARIFRule struct {
+	ID          string
+	Name        string
+	Description string
+	Severity    diagnostic.Severity
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool        sarifToolComponent `json:"tool"`
+	Invocations []sarifInvocation  `json:"invocations"`
+	ColumnKind  string             `json:"columnKind"`
+	Results     []sarifResult      `json:"results"`
+}
+
+type sarifToolComponent struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string                     `json:"name"`
+	Version        string                     `json:"version,omitempty"`
+	InformationURI string                     `json:"informationUri,omitempty"`
+	Rules          []sarifReportingDescriptor `json:"rules"`
+}
+
+type sarifReportingDescriptor struct {
+	ID                   string                       `json:"id"`
+	Name                 string                       `json:"name,omitempty"`
+	ShortDescription     *sarifMessage                `json:"shortDescription,omitempty"`
+	DefaultConfiguration *sarifReportingConfiguration `json:"defaultConfiguration,omitempty"`
+}
+
+type sarifReportingConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful        bool                `json:"executionSuccessful"`
+	ToolExecutionNotifications []sarifNotification `json:"toolExecutionNotifications,omitempty"`
+}
+
+type sarifNotification struct {
+	Level      string              `json:"level"`
+	Message    sarifMessage        `json:"message"`
+	Descriptor *sarifDescriptorRef `json:"descriptor,omitempty"`
+}
+
+type sarifDescriptorRef struct {
+	ID string `json:"id"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId"`
+}
+
+type sarifRegion struct {
+	StartLine   int  `json:"startLine"`
+	StartColumn int  `json:"startColumn,omitempty"`
+	EndLine     int  `json:"endLine,omitempty"`
+	EndColumn   int  `json:"endColumn,omitempty"`
+	ByteOffset  *int `json:"byteOffset,omitempty"`
+	ByteLength  *int `json:"byteLength,omitempty"`
+}
+
+// WriteSARIF writes diagnostics as a SARIF 2.1.0 log with one run of
+// tool. Paths are written relative to the %SRCROOT% base, the way code
+// scanning services resolve them against the repository root.
+func WriteSARIF(out io.Writer, tool SARIFTool, diagnostics []diagnostic.Diagnostic) error {
+	return writeSARIF(out, tool, diagnostics, sarifInvocation{ExecutionSuccessful: true})
+}
+
+// WriteSARIFCommandError writes a command failure as a SARIF log whose
+// invocation did not succeed. The failure's message is a tool
+// notification and its diagnostics are results, so a scanning service
+// still records what was found before the command failed. Like
+// WriteCommandError, it returns failure as a reported error.
+func WriteSARIFCommandError(
+	cmd *cobra.Command,
+	tool SARIFTool,
+	collected []diagnostic.Diagnostic,
+	failure error,
+) error {
+	if failure == nil {
+		failure = errors.New(commandSummary(cmd))
+	}
+	response, err := commandErrorFor(cmd, collected, failure)
+	if err != nil {
+		return err
+	}
+	invocation := sarifInvocation{
+		ToolExecutionNotifications: []sarifNotification{{
+			Level:      "error",
+			Message:    sarifMessage{Text: response.Message},
+			Descriptor: &sarifDescriptorRef{ID: string(response.Code)},
+		}},
+	}
+	if err := writeSARIF(cmd.OutOrStdout(), tool, response.Diagnostics, invocation); err != nil {
+		return err
+	}
+	return Reported(failure)
+}
+
+func writeSARIF(
+	out io.Writer,
+	tool SARIFTool,
+	diagnostics []diagnostic.Diagnostic,
+	invocation sarifInvocation,
+) error {
+	rules, index := sarifRules(tool.Rules, diagnostics)
+	results := make([]sarifResult, 0, len(diagnostics))
+	for _, d := range diagnostic.Normalize(diagnostics) {
+		results = append(results, sarifResultFor(d, index[d.Code]))
+	}
+	log := sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{{
+			Tool: sarifToolComponent{Driver: sarifDriver{
+				Name:           tool.Name,
+				Version:        tool.Version,
+				InformationURI: tool.InformationURI,
+				Rules:          rules,
+			}},
+			Invocations: []sarifInvocation{invocation},
+			ColumnKind:  "unicodeCodePoints",
+			Results:     results,
+		}},
+	}
+	var encoded bytes.Buffer
+	encoder := json.NewEncoder(&encoded)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(log); err != nil {
+		return err
+	}
+	return writeEncoded(out, encoded.Bytes())
+}
+
+// sarifRules returns the driver's rules sorted by id, with a bare rule
+// for each code diagnostics use that tool has no metadata for, and the
+// index of each rule by id.
+func sarifRules(
+	known []SARIFRule,
+	diagnostics []diagnostic.Diagnostic,
+) ([]sarifReportingDescriptor, map[string]int) {
+	byID := map[string]SARIFRule{}
+	for _, rule := range known {
+		byID[rule.ID] = rule
+	}
+	for _, d := range diagnostics {
+		if _, ok := byID[d.Code]; !ok {
+			byID[d.Code] = SARIFRule{ID: d.Code}
+		}
+	}
+	sorted := make([]SARIFRule, 0, len(byID))
+	for _, rule := range byID {
+		sorted = append(sorted, rule)
+	}
+	slices.SortFunc(sorted, func(a, b SARIFRule) int { return cmp.Compare(a.ID, b.ID) })
+	rules := make([]sarifReportingDescriptor, len(sorted))
+	index := make(map[string]int, len(sorted))
+	for i, rule := range sorted {
+		descriptor := sarifReportingDescriptor{ID: rule.ID, Name: rule.Name}
+		if rule.Description != "" {
+			descriptor.ShortDescription = &sarifMessage{Text: rule.Description}
+		}
+		if rule.Severity != "" {
+			descriptor.DefaultConfiguration = &sarifReportingConfiguration{
+				Level: sarifLevel(rule.Severity),
+			}
+		}
+		rules[i] = descriptor
+		index[rule.ID] = i
+	}
+	return rules, index
+}
+
+func sarifResultFor(d diagnostic.Diagnostic, ruleIndex int) sarifResult {
+	text := d.Message
+	if d.Hint != "" {
+		text += "\nhint: " + d.Hint
+	}
+	result := sarifResult{
+		RuleID:    d.Code,
+		RuleIndex: ruleIndex,
+		Level:     sarifLevel(d.Severity),
+		Message:   sarifMessage{Text: text},
+	}
+	if d.Path == "" {
+		return result
+	}
+	location := sarifPhysicalLocation{
+		ArtifactLocation: sarifArtifactLocation{URI: d.Path, URIBaseID: "%SRCROOT%"},
+	}
+	if d.Span != nil && d.Span.Start.Line > 0 {
+		location.Region = sarifRegionFor(d.Span)
+	}
+	result.Locations = []sarifLocation{{PhysicalLocation: location}}
+	return result
+}
+
+func sarifRegionFor(span *diagnostic.Span) *sarifRegion {
+	region := &sarifRegion{StartLine: span.Start.Line, StartColumn: span.Start.Column}
+	if span.End == nil || span.End.Line == 0 {
+		return region
+	}
+	region.EndLine = span.End.Line
+	region.EndColumn = span.End.Column
+	if length := span.End.Offset - span.Start.Offset; length >= 0 {
+		offset := span.Start.Offset
+		region.ByteOffset = &offset
+		region.ByteLength = &length
+	}
+	return region
+}
+
+// sarifLevel maps a severity to a SARIF level. SARIF calls information
+// a note.
+func sarifLevel(severity diagnostic.Severity) string {
+	switch severity {
+	case diagnostic.SeverityError:
+		return "error"
+	case diagnostic.SeverityWarning:
+		return "warning"
+	default:
+		return "note"
+	}
+}
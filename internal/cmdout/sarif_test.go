@@ -0,0 +1,90 @@
+package cmdout
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/cloudboss/unobin/pkg/diagnostic"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+type sarifGolden struct {
+	Success sarifCaseGolden `json:"success"`
+	Failure sarifCaseGolden `json:"failure"`
+}
+
+type sarifCaseGolden struct {
+	Log      json.RawMessage `json:"log"`
+	Error    string          `json:"error"`
+	Reported bool            `json:"reported"`
+}
+
+func TestSARIFGolden(t *testing.T) {
+	tool := SARIFTool{
+		Name:           "unobin",
+		Version:        "v1.2.3",
+		InformationURI: "https://github.com/cloudboss/unobin",
+		Rules: []SARIFRule{
+			{
+				ID:          "unobin.lint.unused-input",
+				Name:        "unused-input",
+				Description: "An input is declared but nothing reads it.",
+				Severity:    diagnostic.SeverityWarning,
+			},
+			{
+				ID:          "unobin.lint.sensitive-output",
+				Name:        "sensitive-output",
+				Description: "An output reads a sensitive input but is not marked sensitive.",
+				Severity:    diagnostic.SeverityError,
+			},
+		},
+	}
+	diagnostics := []diagnostic.Diagnostic{
+		{
+			Code:     "unobin.lint.unused-input",
+			Severity: diagnostic.SeverityError,
+			Message:  `input "path" is never used`,
+			Hint:     "remove it, or read it as input.path",
+			Path:     "app/factory.ub",
+			Span: &diagnostic.Span{
+				Start: diagnostic.Position{Line: 4, Column: 5, Offset: 40},
+				End:   &diagnostic.Position{Line: 4, Column: 9, Offset: 44},
+			},
+		},
+		{
+			Code:     "unobin.type-mismatch",
+			Severity: diagnostic.SeverityInfo,
+			Message:  "point only",
+			Path:     "app/factory.ub",
+			Span:     &diagnostic.Span{Start: diagnostic.Position{Line: 2, Column: 1, Offset: 10}},
+		},
+		{
+			Code:     "unobin.error",
+			Severity: diagnostic.SeverityWarning,
+			Message:  "no location",
+		},
+	}
+
+	var result sarifGolden
+	var success bytes.Buffer
+	require.NoError(t, WriteSARIF(&success, tool, diagnostics))
+	result.Success = sarifCaseGolden{Log: success.Bytes()}
+
+	root := &cobra.Command{Use: "unobin"}
+	command := &cobra.Command{Use: "check"}
+	root.AddCommand(command)
+	var stdout bytes.Buffer
+	command.SetOut(&stdout)
+	failure := Fail(CodeFailed, "check failed", errors.New("no checkable source"))
+	err := WriteSARIFCommandError(command, tool, diagnostics[2:], failure)
+	result.Failure = sarifCaseGolden{
+		Log:      stdout.Bytes(),
+		Error:    cmdoutErrorString(err),
+		Reported: IsReported(err),
+	}
+
+	requireCmdoutGolden(t, "testdata/sarif.json", result)
+}
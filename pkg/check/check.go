@@ -31,6 +31,7 @@ type Checker struct {
 	scopes               []checkerScope
 	bodyScopes           []checkerScope
 	nodesByScope         map[string][]*runtime.Node
+	skipBody             func(body *syntax.FactoryBody) bool
 }
 
 type checkerScope struct {
@@ -118,7 +119,37 @@ func (c *Checker) scopesInOrder() []checkerScope {
 }
 
 func (c *Checker) bodyScopesInOrder() []checkerScope {
-	return cloneCheckerScopes(c.bodyScopes)
+	if c.skipBody == nil {
+		return cloneCheckerScopes(c.bodyScopes)
+	}
+	scopes := make([]checkerScope, 0, len(c.bodyScopes))
+	for _, scope := range c.bodyScopes {
+		if scope.address != "" && c.skipBody(scope.body) {
+			continue
+		}
+		scopes = append(scopes, scope)
+	}
+	return cloneCheckerScopes(scopes)
+}
+
+// SkipBodies makes the checks that run once per composite body pass over
+// the bodies skip reports, such as bodies an earlier check of the same
+// library found clean. Checks that run per call site still cover them,
+// and the root body is always checked.
+func (c *Checker) SkipBodies(skip func(body *syntax.FactoryBody) bool) {
+	c.skipBody = skip
+}
+
+// CompositeBodies returns the composite bodies the checks cover, in the
+// order they are checked.
+func (c *Checker) CompositeBodies() []*syntax.FactoryBody {
+	bodies := make([]*syntax.FactoryBody, 0, len(c.bodyScopes))
+	for _, scope := range c.bodyScopes {
+		if scope.address != "" {
+			bodies = append(bodies, scope.body)
+		}
+	}
+	return bodies
 }
 
 func cloneCheckerScopes(scopes []checkerScope) []checkerScope {
@@ -147,6 +178,7 @@ func (c *Checker) References(observe func(e lang.Expr, t typecheck.Type)) *lang.
 	r.checkLocalCycles()
 	r.checkNodeCycles()
 	r.checkConstraints()
+	r.checkChecks()
 	r.checkTypes()
 	r.checkStateMoves()
 	return r.errs
@@ -236,7 +268,9 @@ func (c *referenceChecker) checkDeclarations() {
 		}
 		lib, ok := libs[n.Alias]
 		if !ok {
-			c.addf(n.Body.Span().Start, `library %q is not imported`, n.Alias)
+			c.addError(lang.Errorf(lang.ErrResolve, n.Body.Span().Start,
+				`library %q is not imported`, n.Alias,
+			).WithCode(lang.CodeUnknownImport, map[string]string{"alias": n.Alias}))
 			continue
 		}
 		// A composite call site already matched a type of this kind. A
@@ -323,7 +357,9 @@ func (c *referenceChecker) checkConstraintValues(values []lang.Expr, scope strin
 		if !ok {
 			continue
 		}
-		it := c.checkConstraintIteration(constraintForEach(obj), scope)
+		it := c.checkIteration(constraintForEach(obj), func(e lang.Expr, it iterScope) {
+			c.checkConstraintExpr(e, scope, it)
+		})
 		for _, fld := range obj.Fields {
 			if fld.Key.Kind != lang.FieldIdent {
 				continue
@@ -344,13 +380,13 @@ type iterScope struct {
 	names map[string]bool
 }
 
-// checkConstraintIteration checks an entry's @for-each value and
-// returns the bindings its when and require are checked under. Each
-// chain level's iterable is checked with only the earlier levels in
-// scope; malformed levels are skipped, with validation the place that
-// reports them.
-func (c *referenceChecker) checkConstraintIteration(
-	forEach lang.Expr, scope string,
+// checkIteration checks a constraint or check entry's @for-each value
+// with checkExpr and returns the bindings the rest of the entry is
+// checked under. Each chain level's iterable is checked with only the
+// earlier levels in scope; malformed levels are skipped, with
+// validation the place that reports them.
+func (c *referenceChecker) checkIteration(
+	forEach lang.Expr, checkExpr func(lang.Expr, iterScope),
 ) iterScope {
 	switch fe := forEach.(type) {
 	case nil:
@@ -366,18 +402,18 @@ func (c *referenceChecker) checkConstraintIteration(
 			if !strings.HasPrefix(f.Key.Name, "@") || f.Key.Name == "@each" {
 				continue
 			}
-			c.checkConstraintExpr(f.Value, scope, it)
+			checkExpr(f.Value, it)
 			it.names[f.Key.Name] = true
 		}
 		return it
 	default:
-		c.checkConstraintExpr(forEach, scope, iterScope{})
+		checkExpr(forEach, iterScope{})
 		return iterScope{bare: true}
 	}
 }
 
-// constraintForEach returns a constraint entry's @for-each expression,
-// or nil when the entry does not iterate.
+// constraintForEach returns a constraint or check entry's @for-each
+// expression, or nil when the entry does not iterate.
 func constraintForEach(obj *lang.ObjectLit) lang.Expr {
 	for _, fld := range obj.Fields {
 		if fld.Key.Kind == lang.FieldIdent && fld.Key.Name == "@for-each" {
@@ -460,6 +496,12 @@ func (c *referenceChecker) checkBody(body lang.Expr, scope string, eachOK bool)
 }
 
 func (c *referenceChecker) checkExpr(expr lang.Expr, scope string, eachOK bool) {
+	c.checkExprIn(expr, scope, iterScope{bare: eachOK})
+}
+
+// checkExprIn walks an expression that may read any address root, with
+// the iteration bindings it names checked against it.
+func (c *referenceChecker) checkExprIn(expr lang.Expr, scope string, it iterScope) {
 	c.checkExprIdents(expr)
 	lang.ScanExpr(expr, lang.ScanCallbacks{
 		DotPath: func(n *lang.DotPath, _ lang.ScanContext) lang.ScanDecision {
@@ -477,7 +519,7 @@ func (c *referenceChecker) checkExpr(expr lang.Expr, scope string, eachOK bool)
 				c.checkAsset(n, scope)
 			default:
 				if strings.HasPrefix(n.Root.Name, "@") {
-					c.checkBindingPath(n, iterScope{bare: eachOK})
+					c.checkBindingPath(n, it)
 				}
 			}
 			return lang.ScanContinue
@@ -506,8 +548,9 @@ func (c *referenceChecker) checkSplat(dp *lang.DotPath) {
 // not declared by the imported library. Bare calls and unimported
 // aliases are rejected earlier by lang.ValidateCalls; this adds the
 // existence and argument-count checks against the library's declared
-// function set. A call against a UB library is always an error: only
-// Go libraries export functions. A Go library with no schema is left
+// function set. A UB library exports only the functions its
+// `functions:` block declares, and one declaring none rejects every
+// call. A Go library with no schema is left
 // alone, since schemas exist only at compile and the runtime's own
 // re-check of the embedded source sees none. An unreadable Go library
 // never reaches here; its schema read fails the compile first.
@@ -518,8 +561,7 @@ func (c *referenceChecker) checkCall(call *lang.Call, scope string) {
 	if call.Library.Name == lang.CoreNamespace {
 		sig, ok := runtime.CoreFunctionSigs()[call.Func.Name]
 		if !ok {
-			c.addf(call.Func.S.Start, `%s has no function %q`,
-				lang.CoreNamespace, call.Func.Name)
+			c.addError(unknownCoreFunction(call.Func.Name, call.Func.S.Start))
 			return
 		}
 		c.checkCallArity(call, sig)
@@ -1115,22 +1157,27 @@ func (c *referenceChecker) addf(pos lang.Position, format string, args ...any) {
 }
 
 func (c *referenceChecker) addDiagnostic(pos lang.Position, message, hint string) {
+	c.addError(&lang.Error{
+		Kind: lang.ErrResolve,
+		Pos:  pos,
+		Msg:  message,
+		Hint: hint,
+	})
+}
+
+// addError adds err unless an identical one was already reported.
+func (c *referenceChecker) addError(err *lang.Error) {
 	key := fmt.Sprintf(
 		"%s:%d:%d:%s:%s",
-		pos.File,
-		pos.Line,
-		pos.Column,
-		message,
-		hint,
+		err.Pos.File,
+		err.Pos.Line,
+		err.Pos.Column,
+		err.Msg,
+		err.Hint,
 	)
 	if c.seen[key] {
 		return
 	}
 	c.seen[key] = true
-	c.errs.Add(&lang.Error{
-		Kind: lang.ErrResolve,
-		Pos:  pos,
-		Msg:  message,
-		Hint: hint,
-	})
+	c.errs.Add(err)
 }
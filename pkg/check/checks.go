@@ -0,0 +1,68 @@
+package check
+
+import (
+	"github.com/cloudboss/unobin/pkg/lang"
+	"github.com/cloudboss/unobin/pkg/typecheck"
+)
+
+// checkChecks walks the factory's `checks:` entries. A check runs after
+// apply against the same values the factory's outputs read, so unlike a
+// constraint it may reference inputs, locals, and every node's results.
+// Iteration bindings resolve the way they do in a predicate constraint.
+func (c *referenceChecker) checkChecks() {
+	if c.rootSyntax == nil {
+		return
+	}
+	for _, decl := range c.rootSyntax.Checks {
+		obj, ok := decl.Value.(*lang.ObjectLit)
+		if !ok {
+			continue
+		}
+		it := c.checkIteration(constraintForEach(obj), func(e lang.Expr, it iterScope) {
+			c.checkExprIn(e, "", it)
+		})
+		if assert := checkAssert(obj); assert != nil {
+			c.checkExprIn(assert, "", it)
+		}
+	}
+}
+
+// checkCheckTypes infers each check's assertion with TBoolean as the
+// target. A bare @for-each iterable must be a non-null list or map, as
+// for a constraint, and types the @each binding the assertion reads.
+func (c *referenceChecker) checkCheckTypes() {
+	if c.rootSyntax == nil {
+		return
+	}
+	s := c.outputScope("")
+	for _, decl := range c.rootSyntax.Checks {
+		obj, ok := decl.Value.(*lang.ObjectLit)
+		if !ok {
+			continue
+		}
+		entryScope := s
+		if forEach := constraintForEach(obj); forEach != nil {
+			if _, chained := forEach.(*lang.ArrayLit); !chained {
+				withEach := *s
+				t := typecheck.Infer(forEach, typecheck.TUnknown(), s, c.errs)
+				checkConstraintIterable(t, forEach.Span().Start, c.errs)
+				withEach.Each = eachBindingFor(t)
+				entryScope = &withEach
+			}
+		}
+		if assert := checkAssert(obj); assert != nil {
+			typecheck.Check(assert, typecheck.TBoolean(), entryScope, c.errs)
+		}
+	}
+}
+
+// checkAssert returns a check entry's `assert:` expression, or nil when
+// the entry has none.
+func checkAssert(obj *lang.ObjectLit) lang.Expr {
+	for _, fld := range obj.Fields {
+		if fld.Key.Kind == lang.FieldIdent && fld.Key.Name == "assert" {
+			return fld.Value
+		}
+	}
+	return nil
+}
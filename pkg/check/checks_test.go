@@ -0,0 +1,19 @@
+package check
+
+import (
+	"testing"
+
+	"github.com/cloudboss/unobin/internal/ubtest"
+	"github.com/cloudboss/unobin/pkg/lang/syntax"
+)
+
+func TestCheckChecksFixtures(t *testing.T) {
+	ubtest.RequireInvalidFixtureGoldens(t, "testdata/ub/checks")
+	ubtest.Run(t, "testdata/ub/checks", func(name string, src []byte) (string, []string) {
+		file, err := syntax.ParseSource("factory.ub", src)
+		if err != nil {
+			return "", []string{err.Error()}
+		}
+		return "", NewSyntax(file.Factory.Body, nil, nil, "").References(nil).Messages()
+	})
+}
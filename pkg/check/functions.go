@@ -0,0 +1,197 @@
+package check
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/cloudboss/unobin/pkg/lang"
+	"github.com/cloudboss/unobin/pkg/lang/syntax"
+	"github.com/cloudboss/unobin/pkg/runtime"
+	"github.com/cloudboss/unobin/pkg/typecheck"
+)
+
+// Functions checks a UB library's `functions:` declarations as a set:
+// every call resolves to a @core function or a sibling and passes the
+// right number of arguments, no function reaches itself through its
+// calls, and each body type-checks against its declared return type
+// with its parameters typed as declared. Scope is the syntax
+// validator's: a body reading anything but its parameters has already
+// been rejected.
+func Functions(decls []syntax.FunctionDecl) *lang.ErrorList {
+	errs := lang.NewErrorList(0)
+	sigs := runtime.SyntaxFunctionSigs(decls)
+	calls := make(map[string][]string, len(decls))
+	for _, fn := range decls {
+		calls[fn.Name.Name] = checkFunctionCalls(fn, sigs, errs)
+	}
+	checkFunctionRecursion(decls, calls, errs)
+	for _, fn := range decls {
+		checkFunctionTypes(fn, sigs, errs)
+	}
+	return errs
+}
+
+// checkFunctionCalls reports unknown functions and argument-count
+// mismatches in fn's body and returns the siblings it calls, in order
+// of first appearance.
+func checkFunctionCalls(
+	fn syntax.FunctionDecl,
+	sigs map[string]typecheck.FuncSig,
+	errs *lang.ErrorList,
+) []string {
+	var siblings []string
+	lang.Walk(fn.Body, func(e lang.Expr) {
+		call, ok := e.(*lang.Call)
+		if !ok {
+			return
+		}
+		switch {
+		case call.Library != nil && call.Func != nil:
+			if call.Library.Name != lang.CoreNamespace {
+				return
+			}
+			sig, ok := runtime.CoreFunctionSigs()[call.Func.Name]
+			if !ok {
+				errs.Add(unknownCoreFunction(call.Func.Name, call.Func.S.Start))
+				return
+			}
+			checkFunctionArity(call, call.Library.Name+"."+call.Func.Name, sig, errs)
+		case call.Callee != nil:
+			name := call.Callee.Name
+			sig, ok := sigs[name]
+			if !ok {
+				errs.Addf(lang.ErrResolve, call.Callee.S.Start,
+					"function %q: no function %q is declared in this library",
+					fn.Name.Name, name)
+				return
+			}
+			checkFunctionArity(call, name, sig, errs)
+			if !slices.Contains(siblings, name) {
+				siblings = append(siblings, name)
+			}
+		}
+	})
+	return siblings
+}
+
+// unknownCoreFunction reports a call to a function @core does not
+// have, suggesting the closest name it does have.
+func unknownCoreFunction(name string, pos lang.Position) *lang.Error {
+	err := lang.Errorf(lang.ErrResolve, pos, `%s has no function %q`, lang.CoreNamespace, name)
+	data := map[string]string{"function": name}
+	names := slices.Sorted(maps.Keys(runtime.CoreFunctionSigs()))
+	if suggestion, ok := closestName(name, names); ok {
+		err.Hint = fmt.Sprintf("did you mean %s.%s?", lang.CoreNamespace, suggestion)
+		data["suggestion"] = suggestion
+	}
+	return err.WithCode(lang.CodeUnknownCoreFunction, data)
+}
+
+// closestName returns the candidate fewest single-byte edits away from
+// name, when it is close enough to be a likely misspelling: no more
+// edits than a third of name's length, and at least one.
+func closestName(name string, candidates []string) (string, bool) {
+	limit := max(1, len(name)/3)
+	best, bestDistance := "", limit+1
+	for _, candidate := range candidates {
+		if d := editDistance(name, candidate); d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best, best != ""
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func checkFunctionArity(
+	call *lang.Call,
+	name string,
+	sig typecheck.FuncSig,
+	errs *lang.ErrorList,
+) {
+	n := len(call.Args)
+	fixed := len(sig.Params)
+	variadic := sig.Variadic != nil
+	if (variadic && n < fixed) || (!variadic && n != fixed) {
+		want := argCount(fixed)
+		if variadic {
+			want = "at least " + want
+		}
+		errs.Addf(lang.ErrResolve, call.S.Start, "%s takes %s, got %d", name, want, n)
+	}
+}
+
+// checkFunctionRecursion reports a function that reaches itself through
+// its calls. A function body is evaluated eagerly with no base case to
+// stop it, so any recursion would never terminate. Each cycle is
+// reported once, at the function where the walk closes the loop.
+func checkFunctionRecursion(
+	decls []syntax.FunctionDecl,
+	calls map[string][]string,
+	errs *lang.ErrorList,
+) {
+	pos := make(map[string]lang.Position, len(decls))
+	names := make([]string, 0, len(decls))
+	for _, fn := range decls {
+		pos[fn.Name.Name] = fn.Name.S.Start
+		names = append(names, fn.Name.Name)
+	}
+	lang.FindCycles(names,
+		func(name string) []string { return calls[name] },
+		func(callee string) string { return callee },
+		func(callee string, cycle []string) {
+			errs.Addf(lang.ErrSchema, pos[callee],
+				"function %q is recursive: %s", callee, strings.Join(cycle, " -> "))
+		})
+}
+
+func checkFunctionTypes(
+	fn syntax.FunctionDecl,
+	sigs map[string]typecheck.FuncSig,
+	errs *lang.ErrorList,
+) {
+	if fn.Body == nil {
+		return
+	}
+	sig := sigs[fn.Name.Name]
+	bindings := make(map[string]typecheck.Type, len(fn.Params))
+	for i, p := range fn.Params {
+		bindings[p.Name.Name] = sig.Params[i]
+	}
+	scope := &typecheck.Scope{
+		Bindings: bindings,
+		LookupFunction: func(library, name string) (typecheck.FuncSig, bool) {
+			switch library {
+			case lang.CoreNamespace:
+				sig, ok := runtime.CoreFunctionSigs()[name]
+				return sig, ok
+			case "":
+				sig, ok := sigs[name]
+				return sig, ok
+			}
+			return typecheck.FuncSig{}, false
+		},
+	}
+	typecheck.Check(fn.Body, sig.Result, scope, errs)
+}
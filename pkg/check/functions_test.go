@@ -0,0 +1,57 @@
+package check
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudboss/unobin/internal/ubtest"
+	"github.com/cloudboss/unobin/pkg/lang"
+	"github.com/cloudboss/unobin/pkg/lang/syntax"
+)
+
+func TestFunctionsFixtures(t *testing.T) {
+	ubtest.Run(t, "testdata/ub/functions", func(name string, src []byte) (string, []string) {
+		f, err := syntax.ParseSource("library.ub", src)
+		if err != nil {
+			return "", []string{err.Error()}
+		}
+		if errs := syntax.ValidateFile(f); errs.Len() > 0 {
+			return "", errs.Messages()
+		}
+		return "", Functions(f.Library.Functions).Messages()
+	})
+}
+
+func TestUnknownCoreFunctionSuggestsClosestName(t *testing.T) {
+	err := unknownCoreFunction("lenght", lang.Position{})
+	require.Equal(t, lang.CodeUnknownCoreFunction, err.Code)
+	require.Equal(t, "did you mean @core.length?", err.Hint)
+	require.Equal(t, map[string]string{"function": "lenght", "suggestion": "length"}, err.Data)
+
+	err = unknownCoreFunction("frobnicate", lang.Position{})
+	require.Empty(t, err.Hint)
+	require.Equal(t, map[string]string{"function": "frobnicate"}, err.Data)
+}
+
+func TestClosestName(t *testing.T) {
+	candidates := []string{"join", "length", "lower", "upper"}
+	tests := []struct {
+		name string
+		want string
+		ok   bool
+	}{
+		{name: "length", want: "length", ok: true},
+		{name: "lenth", want: "length", ok: true},
+		{name: "uper", want: "upper", ok: true},
+		{name: "jn", ok: false},
+		{name: "frobnicate", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := closestName(tt.name, candidates)
+			require.Equal(t, tt.ok, ok)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
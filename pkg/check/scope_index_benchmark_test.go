@@ -21,6 +21,37 @@ func BenchmarkReferencesManyCompositeScopes(b *testing.B) {
 	}
 }
 
+// BenchmarkReferencesManyCompositeBodies compares checking every
+// composite body with skipping the bodies an earlier check found clean,
+// as a source check with a cache does after its first run.
+func BenchmarkReferencesManyCompositeBodies(b *testing.B) {
+	for _, skip := range []bool{false, true} {
+		name := "checked"
+		if skip {
+			name = "cached"
+		}
+		b.Run(name, func(b *testing.B) {
+			checker := NewSyntax(
+				benchmarkDistinctRootBody(400), benchmarkDistinctLibraries(400), nil, "")
+			if skip {
+				clean := map[*syntax.FactoryBody]bool{}
+				for _, body := range checker.CompositeBodies() {
+					clean[body] = true
+				}
+				checker.SkipBodies(func(body *syntax.FactoryBody) bool { return clean[body] })
+			}
+			b.ReportAllocs()
+			b.ResetTimer()
+			for range b.N {
+				errs := checker.References(nil)
+				if errs.Len() != 0 {
+					b.Fatalf("References returned diagnostics: %v", errs.Messages())
+				}
+			}
+		})
+	}
+}
+
 func benchmarkLibraries() map[string]*runtime.Library {
 	body := benchmarkCompositeBody()
 	return map[string]*runtime.Library{
@@ -58,6 +89,34 @@ func benchmarkRootBody(count int) syntax.FactoryBody {
 	return body
 }
 
+// benchmarkDistinctLibraries returns a library with count composites,
+// each with its own body, so no two call sites share a body scope.
+func benchmarkDistinctLibraries(count int) map[string]*runtime.Library {
+	composites := make(map[string]*runtime.CompositeType, count)
+	for i := range count {
+		name := fmt.Sprintf("greeting-%03d", i)
+		body := benchmarkCompositeBody()
+		composites[name] = &runtime.CompositeType{
+			Name:       name,
+			SyntaxBody: &body,
+			Libraries: map[string]*runtime.Library{
+				"local": {},
+			},
+		}
+	}
+	return map[string]*runtime.Library{
+		"outer": {ResourceComposites: composites},
+	}
+}
+
+func benchmarkDistinctRootBody(count int) syntax.FactoryBody {
+	body := benchmarkRootBody(count)
+	for i := range body.Resources {
+		body.Resources[i].Selector.Export = syntax.Ident{Name: fmt.Sprintf("greeting-%03d", i)}
+	}
+	return body
+}
+
 func benchmarkCompositeBody() syntax.FactoryBody {
 	return syntax.FactoryBody{
 		Inputs: []syntax.InputDecl{{
@@ -42,6 +42,18 @@ func TestCheckerScopesDeduplicateRepeatedCompositeBody(t *testing.T) {
 	require.Same(t, compositeBody, bodyScopes[1].body)
 }
 
+func TestCheckerSkipBodiesKeepsRootScope(t *testing.T) {
+	checker, compositeBody := scopeIndexChecker(t, "two-calls")
+	require.Equal(t, []*syntax.FactoryBody{compositeBody}, checker.CompositeBodies())
+
+	checker.SkipBodies(func(body *syntax.FactoryBody) bool { return body == compositeBody })
+
+	bodyScopes := checker.bodyScopesInOrder()
+	assert.Equal(t, []string{""}, scopeAddresses(bodyScopes))
+	assert.Len(t, checker.scopesInOrder(), 3)
+	assert.Equal(t, []*syntax.FactoryBody{compositeBody}, checker.CompositeBodies())
+}
+
 func scopeIndexChecker(t *testing.T, rootFixture string) (*Checker, *syntax.FactoryBody) {
 	t.Helper()
 	composite := parseSyntaxCompositeFixture(
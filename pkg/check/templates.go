@@ -0,0 +1,80 @@
+package check
+
+import (
+	"path/filepath"
+
+	"github.com/cloudboss/unobin/pkg/asset"
+	"github.com/cloudboss/unobin/pkg/lang"
+	"github.com/cloudboss/unobin/pkg/lang/syntax"
+	"github.com/cloudboss/unobin/pkg/typecheck"
+)
+
+// lookupTemplateFor returns the scope's template lookup for
+// @core.render-template. A template argument written as a static
+// asset.<name>.content or asset.<name>['<path>'].content reads the
+// captured bytes and parses them with positions in the asset's source
+// file, so a slot diagnostic names the template line. Anything else,
+// a directory's archive, or content the catalog cannot read is left to
+// the runtime.
+func (c *referenceChecker) lookupTemplateFor(scope string) typecheck.LookupTemplateFn {
+	return func(e lang.Expr) (*lang.InterpolatedString, error) {
+		path, ok := e.(*lang.DotPath)
+		if !ok || path.Root == nil || path.Root.Name != "asset" {
+			return nil, nil
+		}
+		reference, ok := resolveAssetReference(
+			path,
+			c.assetSets[scope],
+			c.assetNames[scope],
+			nil,
+		)
+		if !ok || reference.attribute != "content" {
+			return nil, nil
+		}
+		name := path.Segments[0].Name
+		internalPath := ""
+		if index, ok := path.Segments[1].Index.(*lang.StringLit); ok {
+			internalPath = index.Value
+		}
+		set := c.assetSets[scope]
+		item, _ := set.Asset(name)
+		if entry, ok := item.Entry(internalPath); !ok || entry.Kind != asset.EntryKindFile {
+			return nil, nil
+		}
+		value, err := set.Value(name, internalPath)
+		if err != nil {
+			return nil, nil
+		}
+		content, err := set.Catalog().Content(string(value.Content))
+		if err != nil {
+			return nil, nil
+		}
+		return lang.ParseTemplate(c.assetSourcePath(scope, name, internalPath), content)
+	}
+}
+
+// assetSourcePath names an asset entry by where its content was
+// captured from: the declared source, relative to the declaring file,
+// joined with the internal path of a directory entry.
+func (c *referenceChecker) assetSourcePath(scope, name, internalPath string) string {
+	decl := c.assetDecl(scope, name)
+	if decl == nil || decl.Source == nil {
+		return "asset." + name
+	}
+	dir := filepath.Dir(decl.S.Start.File)
+	return filepath.Join(dir, filepath.FromSlash(decl.Source.Value), filepath.FromSlash(internalPath))
+}
+
+func (c *referenceChecker) assetDecl(scope, name string) *syntax.AssetDecl {
+	for _, s := range c.scopes {
+		if s.address != scope || s.body == nil {
+			continue
+		}
+		for i := range s.body.Assets {
+			if s.body.Assets[i].Name.Name == name {
+				return &s.body.Assets[i]
+			}
+		}
+	}
+	return nil
+}
@@ -35,6 +35,7 @@ func (c *referenceChecker) checkTypes() {
 	c.checkLocalsBodyTypes()
 	c.checkOutputBodyTypes()
 	c.checkConstraintTypes()
+	c.checkCheckTypes()
 }
 
 func (c *referenceChecker) checkLibraryConfigDecls() {
@@ -79,6 +80,7 @@ func (c *referenceChecker) scopeForLibraryConfig(scope string) *typecheck.Scope
 		Inputs:         c.scopeInputs(scope),
 		LookupNode:     c.lookupNodeFor(scope),
 		LookupAsset:    c.lookupAssetFor(scope),
+		LookupTemplate: c.lookupTemplateFor(scope),
 		LookupFunction: c.lookupFunctionFor(scope),
 		Observe:        c.observe,
 	}
@@ -141,6 +143,7 @@ func (c *referenceChecker) checkLocalsBlockTypes(scope string) {
 		Inputs:         c.scopeInputs(scope),
 		LookupNode:     c.lookupNodeFor(scope),
 		LookupAsset:    c.lookupAssetFor(scope),
+		LookupTemplate: c.lookupTemplateFor(scope),
 		LookupFunction: c.lookupFunctionFor(scope),
 		Observe:        c.observe,
 	}
@@ -192,8 +195,31 @@ func (c *referenceChecker) checkRequiredPresence(
 		if present[name] || defaulted[name] {
 			continue
 		}
-		c.addf(n.Body.Span().Start,
-			"missing required input %q on %s.%s", name, n.Alias, n.Type)
+		c.addError(lang.Errorf(lang.ErrResolve, n.Body.Span().Start,
+			"missing required input %q on %s.%s", name, n.Alias, n.Type,
+		).WithCode(lang.CodeMissingInput, map[string]string{
+			"input": name, "type": t.String(), "stub": stubValue(t),
+		}))
+	}
+}
+
+// stubValue returns a literal of type t to fill in for a missing input:
+// the empty value of its kind, or null where no literal is obviously
+// right.
+func stubValue(t typecheck.Type) string {
+	switch t.Kind {
+	case typecheck.String, typecheck.AssetPath:
+		return "''"
+	case typecheck.Integer, typecheck.Number:
+		return "0"
+	case typecheck.Boolean:
+		return "false"
+	case typecheck.List, typecheck.Tuple:
+		return "[]"
+	case typecheck.Map, typecheck.Object, typecheck.LibraryConfig:
+		return "{}"
+	default:
+		return "null"
 	}
 }
 
@@ -270,7 +296,7 @@ func (c *referenceChecker) syntaxInputFields(
 }
 
 func syntaxInputType(decl syntax.InputDecl) (lang.TypeExpr, bool, bool) {
-	if opt, ok := decl.Type.(*lang.TypeOptional); ok {
+	if opt, ok := lang.ResolveNamed(decl.Type).(*lang.TypeOptional); ok {
 		return opt.Elem, true, false
 	}
 	if inputDeclHasDefault(decl.Body) {
@@ -429,6 +455,7 @@ func (c *referenceChecker) scopeFor(n *runtime.Node) *typecheck.Scope {
 		Inputs:         inputs,
 		LookupNode:     c.lookupNodeFor(n.Composite),
 		LookupAsset:    c.lookupAssetFor(n.Composite),
+		LookupTemplate: c.lookupTemplateFor(n.Composite),
 		LookupFunction: c.lookupFunctionFor(n.Composite),
 		Observe:        c.observe,
 	}
@@ -575,6 +602,7 @@ func (c *referenceChecker) inferCompositeOutputs(node *runtime.Node) map[string]
 		Inputs:         c.scopeInputs(node.Address),
 		LookupNode:     c.lookupNodeFor(node.Address),
 		LookupAsset:    c.lookupAssetFor(node.Address),
+		LookupTemplate: c.lookupTemplateFor(node.Address),
 		LookupFunction: c.lookupFunctionFor(node.Address),
 	}
 	s.LookupLocal = c.lookupLocalFor(node.Address, s)
@@ -815,6 +843,7 @@ func (c *referenceChecker) outputScope(scope string) *typecheck.Scope {
 		Inputs:         c.scopeInputs(scope),
 		LookupNode:     c.lookupNodeFor(scope),
 		LookupAsset:    c.lookupAssetFor(scope),
+		LookupTemplate: c.lookupTemplateFor(scope),
 		LookupFunction: c.lookupFunctionFor(scope),
 		Observe:        c.observe,
 	}
@@ -852,6 +881,7 @@ func (c *referenceChecker) checkConstraintTypeExprs(values []lang.Expr, scope st
 		Inputs:         c.scopeInputs(scope),
 		LookupNode:     c.lookupNodeFor(scope),
 		LookupAsset:    c.lookupAssetFor(scope),
+		LookupTemplate: c.lookupTemplateFor(scope),
 		LookupFunction: c.lookupFunctionFor(scope),
 		MissingAsNull:  true,
 		Observe:        c.observe,
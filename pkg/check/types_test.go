@@ -240,6 +240,27 @@ func TestCheckTypesRejectsMissingReferenceInputs(t *testing.T) {
 		`missing required input "items" on ext.bucket`,
 		`missing required input "tags" on ext.bucket`,
 	}, errs.Messages())
+	require.Equal(t, lang.CodeMissingInput, errs.Errors()[0].Code)
+	require.Equal(t, map[string]string{
+		"input": "items", "type": "list(string)", "stub": "[]",
+	}, errs.Errors()[0].Data)
+}
+
+func TestStubValue(t *testing.T) {
+	tests := []struct {
+		typ  typecheck.Type
+		want string
+	}{
+		{typ: typecheck.Type{Kind: typecheck.String}, want: "''"},
+		{typ: typecheck.Type{Kind: typecheck.Integer}, want: "0"},
+		{typ: typecheck.Type{Kind: typecheck.Boolean}, want: "false"},
+		{typ: typecheck.Type{Kind: typecheck.List}, want: "[]"},
+		{typ: typecheck.Type{Kind: typecheck.Object}, want: "{}"},
+		{typ: typecheck.Type{Kind: typecheck.Opaque}, want: "null"},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.want, stubValue(tt.typ), tt.typ.String())
+	}
 }
 
 func TestCheckTypesRejectsNullMapInput(t *testing.T) {
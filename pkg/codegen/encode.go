@@ -75,10 +75,14 @@ func encodeNodeWithSpans(b *strings.Builder, n lang.Node, spanName SyntaxSpanNam
 		return encodeTypeObject(b, x, spanName)
 	case *lang.TypeTuple:
 		return encodeTypeTuple(b, x, spanName)
+	case *lang.TypeUnion:
+		return encodeTypeUnion(b, x, spanName)
 	case *lang.TypeOptional:
 		return encodeTypeOptional(b, x, spanName)
 	case *lang.TypeLibraryConfig:
 		return encodeTypeLibraryConfig(b, x, spanName)
+	case *lang.TypeNamed:
+		return encodeTypeNamed(b, x, spanName)
 	case nil:
 		b.WriteString("nil")
 		return nil
@@ -647,6 +651,33 @@ func encodeTypeTuple(b *strings.Builder, n *lang.TypeTuple, spanName SyntaxSpanN
 	return nil
 }
 
+func encodeTypeUnion(b *strings.Builder, n *lang.TypeUnion, spanName SyntaxSpanNamer) error {
+	b.WriteString("&lang.TypeUnion{")
+	fields := syntaxFieldWriter{}
+	writeSpanField(b, &fields, n.S, spanName)
+	fields.next(b, "Tag")
+	b.WriteString(strconv.Quote(n.Tag))
+	fields.next(b, "Variants")
+	b.WriteString("[]*lang.TypeUnionVariant{")
+	for i, v := range n.Variants {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString("{")
+		variantFields := syntaxFieldWriter{}
+		writeSpanField(b, &variantFields, v.S, spanName)
+		variantFields.next(b, "Name")
+		b.WriteString(strconv.Quote(v.Name))
+		variantFields.next(b, "Type")
+		if err := encodeNodeWithSpans(b, v.Type, spanName); err != nil {
+			return err
+		}
+		b.WriteString("}")
+	}
+	b.WriteString("}}")
+	return nil
+}
+
 func encodeTypeOptional(b *strings.Builder, n *lang.TypeOptional, spanName SyntaxSpanNamer) error {
 	b.WriteString("&lang.TypeOptional{")
 	fields := syntaxFieldWriter{}
@@ -677,6 +708,31 @@ func encodeTypeLibraryConfig(
 	return nil
 }
 
+// encodeTypeNamed writes the name with the type it resolved to, so the
+// compiled factory checks values without the `types:` block. The
+// resolved type may be declared in another library's file, whose spans
+// the generated package has no source metadata for, so it is written
+// without spans.
+func encodeTypeNamed(b *strings.Builder, n *lang.TypeNamed, spanName SyntaxSpanNamer) error {
+	b.WriteString("&lang.TypeNamed{")
+	fields := syntaxFieldWriter{}
+	writeSpanField(b, &fields, n.S, spanName)
+	if n.Library != "" {
+		fields.next(b, "Library")
+		b.WriteString(strconv.Quote(n.Library))
+	}
+	fields.next(b, "Name")
+	b.WriteString(strconv.Quote(n.Name))
+	if n.Resolved != nil {
+		fields.next(b, "Resolved")
+		if err := encodeNodeWithSpans(b, n.Resolved, nil); err != nil {
+			return err
+		}
+	}
+	b.WriteString("}")
+	return nil
+}
+
 func fileKindIdent(k lang.FileKind) string {
 	switch k {
 	case lang.FileFactory:
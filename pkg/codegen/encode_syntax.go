@@ -75,6 +75,12 @@ func encodeSyntaxFactoryBody(
 			return err
 		}
 	}
+	if len(n.Checks) > 0 {
+		fields.next(b, "Checks")
+		if err := encodeSyntaxChecks(b, n.Checks, spanName); err != nil {
+			return err
+		}
+	}
 	if len(n.Imports) > 0 {
 		fields.next(b, "Imports")
 		if err := encodeSyntaxImports(b, n.Imports, spanName); err != nil {
@@ -263,6 +269,31 @@ func encodeSyntaxConstraints(
 	return nil
 }
 
+func encodeSyntaxChecks(
+	b *strings.Builder,
+	decls []syntax.CheckDecl,
+	spanName SyntaxSpanNamer,
+) error {
+	b.WriteString("[]syntax.CheckDecl{")
+	for i, decl := range decls {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		value, err := encodeNodeString(decl.Value, spanName)
+		if err != nil {
+			return err
+		}
+		b.WriteString("{")
+		fields := syntaxFieldWriter{}
+		writeSpanField(b, &fields, decl.S, spanName)
+		fields.next(b, "Value")
+		b.WriteString(value)
+		b.WriteString("}")
+	}
+	b.WriteString("}")
+	return nil
+}
+
 func encodeSyntaxImports(
 	b *strings.Builder,
 	decls []syntax.ImportDecl,
@@ -439,3 +470,83 @@ func encodeSyntaxIdent(b *strings.Builder, n syntax.Ident, spanName SyntaxSpanNa
 	b.WriteString(strconv.Quote(n.Name))
 	b.WriteString("}")
 }
+
+func encodeSyntaxFunctions(
+	b *strings.Builder,
+	decls []syntax.FunctionDecl,
+	spanName SyntaxSpanNamer,
+) error {
+	b.WriteString("[]syntax.FunctionDecl{")
+	for i, decl := range decls {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString("{")
+		fields := syntaxFieldWriter{}
+		writeSpanField(b, &fields, decl.S, spanName)
+		fields.next(b, "Name")
+		encodeSyntaxIdent(b, decl.Name, spanName)
+		if decl.Description != nil {
+			s, err := encodeNodeString(decl.Description, spanName)
+			if err != nil {
+				return err
+			}
+			fields.next(b, "Description")
+			b.WriteString(s)
+		}
+		if len(decl.Params) > 0 {
+			fields.next(b, "Params")
+			if err := encodeSyntaxFunctionParams(b, decl.Params, spanName); err != nil {
+				return err
+			}
+		}
+		if decl.Returns != nil {
+			typ, err := encodeNodeString(decl.Returns, spanName)
+			if err != nil {
+				return err
+			}
+			fields.next(b, "Returns")
+			b.WriteString(typ)
+		}
+		if decl.Body != nil {
+			body, err := encodeNodeString(decl.Body, spanName)
+			if err != nil {
+				return err
+			}
+			fields.next(b, "Body")
+			b.WriteString(body)
+		}
+		b.WriteString("}")
+	}
+	b.WriteString("}")
+	return nil
+}
+
+func encodeSyntaxFunctionParams(
+	b *strings.Builder,
+	params []syntax.FunctionParam,
+	spanName SyntaxSpanNamer,
+) error {
+	b.WriteString("[]syntax.FunctionParam{")
+	for i, param := range params {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString("{")
+		fields := syntaxFieldWriter{}
+		writeSpanField(b, &fields, param.S, spanName)
+		fields.next(b, "Name")
+		encodeSyntaxIdent(b, param.Name, spanName)
+		if param.Type != nil {
+			typ, err := encodeNodeString(param.Type, spanName)
+			if err != nil {
+				return err
+			}
+			fields.next(b, "Type")
+			b.WriteString(typ)
+		}
+		b.WriteString("}")
+	}
+	b.WriteString("}")
+	return nil
+}
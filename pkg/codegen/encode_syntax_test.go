@@ -63,3 +63,20 @@ func TestEncodeSyntaxFactoryBodyIncludesAssets(t *testing.T) {
 	_, err = goparser.ParseFile(token.NewFileSet(), "generated.go", generated, 0)
 	require.NoError(t, err)
 }
+
+func TestEncodeSyntaxFactoryBodyIncludesChecks(t *testing.T) {
+	body := syntax.FactoryBody{
+		Checks: []syntax.CheckDecl{
+			{Value: &parse.ObjectLit{Fields: []*parse.Field{{
+				Key:   parse.FieldKey{Kind: parse.FieldIdent, Name: "assert"},
+				Value: &parse.BoolLit{Value: true},
+			}}}},
+		},
+	}
+
+	got, err := EncodeSyntaxFactoryBody(body)
+
+	require.NoError(t, err)
+	assert.Contains(t, got, "Checks: []syntax.CheckDecl{{Value: &lang.ObjectLit{")
+	assert.Contains(t, got, `Name: "assert"`)
+}
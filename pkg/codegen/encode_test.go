@@ -298,6 +298,20 @@ func TestEncodeTypeTuple(t *testing.T) {
 	parsesAsGoExpr(t, got)
 }
 
+func TestEncodeTypeUnion(t *testing.T) {
+	got, err := EncodeNode(&lang.TypeUnion{
+		Tag: "kind",
+		Variants: []*lang.TypeUnionVariant{
+			{Name: "git", Type: &lang.TypeObject{}},
+			{Name: "s3", Type: &lang.TypeNamed{Name: "bucket"}},
+		},
+	})
+	require.NoError(t, err)
+	require.Contains(t, got, `&lang.TypeUnion{Tag: "kind", Variants: []*lang.TypeUnionVariant{`)
+	require.Contains(t, got, `{Name: "git", Type: &lang.TypeObject{`)
+	parsesAsGoExpr(t, got)
+}
+
 func TestEncodeTypeOptional(t *testing.T) {
 	got, err := EncodeNode(&lang.TypeOptional{Elem: &lang.TypeAtomic{Name: "string"}})
 	require.NoError(t, err)
@@ -315,6 +329,17 @@ func TestEncodeTypeLibraryConfig(t *testing.T) {
 	parsesAsGoExpr(t, got)
 }
 
+func TestEncodeTypeNamed(t *testing.T) {
+	got, err := EncodeNode(&lang.TypeNamed{
+		Library:  "net",
+		Name:     "port",
+		Resolved: &lang.TypeAtomic{Name: "integer"},
+	})
+	require.NoError(t, err)
+	require.Contains(t, got, `&lang.TypeNamed{Library: "net", Name: "port", Resolved: &lang.TypeAtomic{`)
+	parsesAsGoExpr(t, got)
+}
+
 func encodeExpr(t *testing.T, src string) string {
 	t.Helper()
 	f, err := lang.ParseSource("test.ub", []byte("v: "+src+"\n"))
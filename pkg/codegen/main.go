@@ -61,6 +61,9 @@ type Input struct {
 	// LibraryConfigSchemas maps library-config paths to schemas resolved by
 	// source analysis, including schema packages not listed in imports.
 	LibraryConfigSchemas map[string]runtime.LibraryConfigSchema
+	// Materials is the encoded sbom.Manifest WriteSource writes beside
+	// main.go and the binary embeds for `version --sbom`; nil embeds none.
+	Materials []byte
 }
 
 // Generate produces the formatted Go source for the factory binary's
@@ -108,6 +111,7 @@ func Generate(in Input) ([]byte, error) {
 		HasLang                bool
 		HasTypecheck           bool
 		HasAssets              bool
+		HasMaterials           bool
 		RootAssetSetID         string
 		Inject                 bool
 	}{
@@ -133,6 +137,7 @@ func Generate(in Input) ([]byte, error) {
 		HasTypecheck: schemasNeedTypecheck(in.GoSchemas) ||
 			libraryConfigSchemasNeedTypecheck(in.LibraryConfigSchemas),
 		HasAssets:      in.HasAssets,
+		HasMaterials:   len(in.Materials) > 0,
 		RootAssetSetID: in.RootAssetSetID,
 		Inject:         len(constraintAliases)+len(defaultAliases)+len(schemaInjectAliases) > 0,
 	}
@@ -703,7 +708,7 @@ var mainTemplate = template.Must(template.New("main.go").Funcs(template.FuncMap{
 package main
 
 import (
-{{if .HasAssets}}	_ "embed"
+{{if or .HasAssets .HasMaterials}}	_ "embed"
 
 {{end -}}
 {{if .HasLang}}	"github.com/cloudboss/unobin/pkg/lang"
@@ -719,6 +724,9 @@ import (
 {{if .HasAssets}}
 //go:embed factory.assets
 var factoryAssets []byte
+{{end}}{{if .HasMaterials}}
+//go:embed factory.materials.json
+var factoryMaterials []byte
 {{end}}
 
 var factorySource = parse.NewSourceFile(
@@ -771,6 +779,7 @@ func main() {
 		RootAssetSetID:    {{quote .RootAssetSetID}},
 {{end -}}
 {{if .HasLibraryConfigSchema}}		LibraryConfigSchemas: configSchemas,
+{{end}}{{if .HasMaterials}}		Materials: factoryMaterials,
 {{end}}		UnobinVersion: unobinVersion,
 	})
 {{else -}}
@@ -794,6 +803,7 @@ func main() {
 		RootAssetSetID:    {{quote .RootAssetSetID}},
 {{end -}}
 {{if .HasLibraryConfigSchema}}		LibraryConfigSchemas: configSchemas,
+{{end}}{{if .HasMaterials}}		Materials: factoryMaterials,
 {{end}}		UnobinVersion: unobinVersion,
 	})
 {{end -}}
@@ -456,6 +456,21 @@ func TestGenerateEmbedsAssets(t *testing.T) {
 	require.NotContains(t, source, "asset bundle")
 }
 
+func TestGenerateEmbedsMaterials(t *testing.T) {
+	body := ubtest.ReadValidFixture(t, "testdata/ub/write-source", "minimal")
+	in := testMainInput(t, body, "demo")
+	in.Materials = []byte("{}\n")
+
+	out, err := Generate(in)
+	require.NoError(t, err)
+
+	source := string(out)
+	require.Contains(t, source, `_ "embed"`)
+	require.Contains(t, source, "//go:embed factory.materials.json\nvar factoryMaterials []byte")
+	require.Contains(t, source, "Materials:       factoryMaterials,")
+	require.NotContains(t, source, "go:embed factory.assets")
+}
+
 func TestGenerateOmitsAssetsWithoutBundle(t *testing.T) {
 	body := ubtest.ReadValidFixture(t, "testdata/ub/write-source", "minimal")
 	out, err := Generate(testMainInput(t, body, "demo"))
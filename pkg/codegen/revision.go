@@ -8,13 +8,16 @@ import (
 	"path/filepath"
 	"slices"
 	"strings"
+
+	"github.com/cloudboss/unobin/pkg/sbom"
 )
 
 // ContentRevision returns a short content-addressable revision for the
-// generated library in dir. It hashes every Go source file plus go.mod and
-// go.sum in sorted path order, so the result is a stable fingerprint of the
-// factory source, the inlined UB libraries, and the full pinned Go dependency
-// set that go.sum records. The compiled binary itself is excluded; only
+// generated library in dir. It hashes every Go source file plus go.mod,
+// go.sum, and the embedded asset bundle and materials manifest in sorted
+// path order, so the result is a stable fingerprint of the factory
+// source, the inlined UB libraries, and the full pinned Go dependency set
+// that go.sum records. The compiled binary itself is excluded; only
 // build inputs contribute. Run it after `go mod tidy` so go.sum is present.
 func ContentRevision(dir string) (string, error) {
 	var paths []string
@@ -27,7 +30,7 @@ func ContentRevision(dir string) (string, error) {
 		}
 		name := d.Name()
 		if name == "go.mod" || name == "go.sum" || name == "factory.assets" ||
-			strings.HasSuffix(name, ".go") {
+			name == sbom.ManifestFile || strings.HasSuffix(name, ".go") {
 			rel, err := filepath.Rel(dir, p)
 			if err != nil {
 				return err
@@ -58,114 +58,70 @@ func GenerateUBLibrary(
 	imports map[string]map[string]map[string]string,
 	goSpecs map[string]GoLibrarySpecs,
 ) ([]byte, error) {
-	return GenerateUBLibraryPackage(alias, alias, syntaxBodies, imports, goSpecs, nil)
-}
-
-// GenerateUBLibraryPackage produces a UB library package whose Go package
-// identifier can differ from the runtime library name.
-func GenerateUBLibraryPackage(
-	packageID string,
-	libraryName string,
-	syntaxBodies map[string]map[string]syntax.FactoryBody,
-	imports map[string]map[string]map[string]string,
-	goSpecs map[string]GoLibrarySpecs,
-	sourceFiles map[string]syntax.SourceFileSpec,
-) ([]byte, error) {
-	return generateUBLibraryPackage(
-		packageID,
-		libraryName,
-		syntaxBodies,
-		imports,
-		goSpecs,
-		sourceFiles,
-		nil,
-		nil,
-	)
-}
-
-// GenerateUBLibraryPackageWithAssets includes the captured asset-set ID
-// for each composite body.
-func GenerateUBLibraryPackageWithAssets(
-	packageID string,
-	libraryName string,
-	syntaxBodies map[string]map[string]syntax.FactoryBody,
-	imports map[string]map[string]map[string]string,
-	goSpecs map[string]GoLibrarySpecs,
-	sourceFiles map[string]syntax.SourceFileSpec,
-	assetSetIDs map[string]map[string]string,
-) ([]byte, error) {
-	return GenerateUBLibraryPackageWithAssetsAndConfigSchemas(
-		packageID,
-		libraryName,
-		syntaxBodies,
-		imports,
-		goSpecs,
-		sourceFiles,
-		assetSetIDs,
-		nil,
-	)
+	return GenerateUBLibraryPackage(UBLibraryInput{
+		PackageID:    alias,
+		LibraryName:  alias,
+		SyntaxBodies: syntaxBodies,
+		Imports:      imports,
+		GoSpecs:      goSpecs,
+	})
 }
 
-// GenerateUBLibraryPackageWithAssetsAndConfigSchemas includes the captured
-// asset-set IDs and resolved library-config schemas for each composite body.
-func GenerateUBLibraryPackageWithAssetsAndConfigSchemas(
-	packageID string,
-	libraryName string,
-	syntaxBodies map[string]map[string]syntax.FactoryBody,
-	imports map[string]map[string]map[string]string,
-	goSpecs map[string]GoLibrarySpecs,
-	sourceFiles map[string]syntax.SourceFileSpec,
-	assetSetIDs map[string]map[string]string,
-	libraryConfigSchemas map[string]map[string]map[string]runtime.LibraryConfigSchema,
-) ([]byte, error) {
-	return generateUBLibraryPackage(
-		packageID,
-		libraryName,
-		syntaxBodies,
-		imports,
-		goSpecs,
-		sourceFiles,
-		assetSetIDs,
-		libraryConfigSchemas,
-	)
+// UBLibraryInput is what GenerateUBLibraryPackage generates a UB library
+// package from. PackageID is the Go package identifier, which can differ
+// from LibraryName, the runtime library name. SyntaxBodies, Imports, and
+// GoSpecs are as GenerateUBLibrary describes them.
+type UBLibraryInput struct {
+	PackageID    string
+	LibraryName  string
+	SyntaxBodies map[string]map[string]syntax.FactoryBody
+	Imports      map[string]map[string]map[string]string
+	GoSpecs      map[string]GoLibrarySpecs
+	// SourceFiles describes each source file of the library, so the
+	// generated bodies keep their spans.
+	SourceFiles map[string]syntax.SourceFileSpec
+	// AssetSetIDs maps a composite's kind and name to the asset-set ID
+	// captured for its body.
+	AssetSetIDs map[string]map[string]string
+	// LibraryConfigSchemas maps a composite's kind and name to its
+	// resolved library-config schemas.
+	LibraryConfigSchemas map[string]map[string]map[string]runtime.LibraryConfigSchema
+	// Functions are the library's UB-written functions. Each is evaluated
+	// from its syntax at call time and its declared signature is attached
+	// as the library's schema, so a call against the generated library is
+	// checked like one against a Go library.
+	Functions []syntax.FunctionDecl
 }
 
-func generateUBLibraryPackage(
-	packageID string,
-	libraryName string,
-	syntaxBodies map[string]map[string]syntax.FactoryBody,
-	imports map[string]map[string]map[string]string,
-	goSpecs map[string]GoLibrarySpecs,
-	sourceFiles map[string]syntax.SourceFileSpec,
-	assetSetIDs map[string]map[string]string,
-	libraryConfigSchemas map[string]map[string]map[string]runtime.LibraryConfigSchema,
-) ([]byte, error) {
-	if packageID == "" {
+// GenerateUBLibraryPackage produces the Go source of the UB library
+// package in describes.
+func GenerateUBLibraryPackage(in UBLibraryInput) ([]byte, error) {
+	if in.PackageID == "" {
 		return nil, fmt.Errorf("ublibrary: package name is required")
 	}
-	if libraryName == "" {
+	if in.LibraryName == "" {
 		return nil, fmt.Errorf("ublibrary: library name is required")
 	}
 
 	idents := newIdentTable()
-	sourceHelpers, sourceHelperByFile := sourceHelpersFor(sourceFiles)
+	sourceHelpers, sourceHelperByFile := sourceHelpersFor(in.SourceFiles)
 	hasConfigSchemaLang := false
 	hasConfigSchemaTypecheck := false
 	groups := map[string]*compositeGroup{}
 	for _, c := range compositeKinds {
 		groups[c.kind] = &compositeGroup{MapField: c.mapField, Symbol: c.symbol}
 	}
-	for _, kind := range compositeKindNames(syntaxBodies) {
+	for _, kind := range compositeKindNames(in.SyntaxBodies) {
 		group, ok := groups[kind]
 		if !ok {
-			return nil, fmt.Errorf("ublibrary %q: unknown kind %q", libraryName, kind)
+			return nil, fmt.Errorf("ublibrary %q: unknown kind %q", in.LibraryName, kind)
 		}
-		for _, name := range compositeNames(syntaxBodies[kind]) {
-			configSchemas := libraryConfigSchemas[kind][name]
+		for _, name := range compositeNames(in.SyntaxBodies[kind]) {
+			configSchemas := in.LibraryConfigSchemas[kind][name]
 			entry := compositeEntry{
 				Name:       name,
 				Symbol:     group.Symbol,
-				AssetSetID: assetSetIDs[kind][name],
+				AssetSetID: in.AssetSetIDs[kind][name],
 			}
 			if len(configSchemas) > 0 {
 				entry.LibraryConfigSchemas = libraryConfigSchemasLiteral(configSchemas)
@@ -175,14 +131,14 @@ func generateUBLibraryPackage(
 					libraryConfigSchemasNeedTypecheck(configSchemas)
 			}
 			encoded, err := encodeSyntaxBodyWithSourceHelpers(
-				syntaxBodies[kind][name], sourceHelperByFile)
+				in.SyntaxBodies[kind][name], sourceHelperByFile)
 			if err != nil {
 				return nil, fmt.Errorf("ublibrary %q: encode %s %q syntax body: %w",
-					libraryName, kind, name, err)
+					in.LibraryName, kind, name, err)
 			}
 			entry.SyntaxBody = "&" + encoded
-			for _, localAlias := range sortedAliases(imports[kind][name]) {
-				p := imports[kind][name][localAlias]
+			for _, localAlias := range sortedAliases(in.Imports[kind][name]) {
+				p := in.Imports[kind][name][localAlias]
 				entry.Libraries = append(entry.Libraries, libraryBinding{
 					LocalAlias: localAlias,
 					Path:       p,
@@ -200,7 +156,17 @@ func generateUBLibraryPackage(
 		}
 	}
 
-	specVars, varOf := specVarsFor(idents, goSpecs)
+	encodedFunctions := ""
+	if len(in.Functions) > 0 {
+		var err error
+		encodedFunctions, err = encodeSyntaxFunctionsWithSourceHelpers(
+			in.Functions, sourceHelperByFile)
+		if err != nil {
+			return nil, fmt.Errorf("ublibrary %q: encode functions: %w", in.LibraryName, err)
+		}
+	}
+
+	specVars, varOf := specVarsFor(idents, in.GoSpecs)
 	for _, g := range orderedGroups {
 		for _, entry := range g.Entries {
 			for i, b := range entry.Libraries {
@@ -221,21 +187,23 @@ func generateUBLibraryPackage(
 		Groups           []*compositeGroup
 		GoImports        []goImport
 		SourceHelpers    []sourceHelper
+		Functions        string
 		HasLang          bool
 		HasTypecheck     bool
 		HasSyntaxBodies  bool
 		HasSourceHelpers bool
 	}{
-		PackageName:   sanitizeIdent(packageID),
-		LibraryName:   libraryName,
+		PackageName:   sanitizeIdent(in.PackageID),
+		LibraryName:   in.LibraryName,
 		SpecVars:      specVars,
 		Groups:        orderedGroups,
 		GoImports:     idents.imports(),
 		SourceHelpers: sourceHelpers,
+		Functions:     encodedFunctions,
 		HasLang: specVarsNeedLang(specVars) || hasSyntaxBodies(orderedGroups) ||
-			hasConfigSchemaLang,
+			hasConfigSchemaLang || encodedFunctions != "",
 		HasTypecheck:     specVarsNeedTypecheck(specVars) || hasConfigSchemaTypecheck,
-		HasSyntaxBodies:  hasSyntaxBodies(orderedGroups),
+		HasSyntaxBodies:  hasSyntaxBodies(orderedGroups) || encodedFunctions != "",
 		HasSourceHelpers: len(sourceHelpers) > 0,
 	}
 	if err := ubLibraryTemplate.Execute(&buf, data); err != nil {
@@ -317,8 +285,41 @@ func encodeSyntaxBodyWithSourceHelpers(
 	if len(helpers) == 0 {
 		return EncodeSyntaxFactoryBody(body)
 	}
+	var encoded string
+	err := withSourceHelperSpans(helpers, func(spanName SyntaxSpanNamer) error {
+		var err error
+		encoded, err = EncodeSyntaxFactoryBodyWithSpans(body, spanName)
+		return err
+	})
+	return encoded, err
+}
+
+func encodeSyntaxFunctionsWithSourceHelpers(
+	decls []syntax.FunctionDecl,
+	helpers map[string]sourceHelper,
+) (string, error) {
+	var b strings.Builder
+	if len(helpers) == 0 {
+		if err := encodeSyntaxFunctions(&b, decls, nil); err != nil {
+			return "", err
+		}
+		return b.String(), nil
+	}
+	err := withSourceHelperSpans(helpers, func(spanName SyntaxSpanNamer) error {
+		return encodeSyntaxFunctions(&b, decls, spanName)
+	})
+	return b.String(), err
+}
+
+// withSourceHelperSpans runs encode with a span namer that renders each
+// span through its file's source helper, and reports every file the
+// encoding touched that has no helper.
+func withSourceHelperSpans(
+	helpers map[string]sourceHelper,
+	encode func(SyntaxSpanNamer) error,
+) error {
 	missing := map[string]bool{}
-	encoded, err := EncodeSyntaxFactoryBodyWithSpans(body, func(s parse.Span) string {
+	err := encode(func(s parse.Span) string {
 		helper, ok := helpers[s.Start.File]
 		if !ok {
 			missing[s.Start.File] = true
@@ -327,7 +328,7 @@ func encodeSyntaxBodyWithSourceHelpers(
 		return fmt.Sprintf("%s(%d, %d)", helper.FuncName, s.Start.Offset, s.End.Offset)
 	})
 	if err != nil {
-		return "", err
+		return err
 	}
 	if len(missing) > 0 {
 		files := make([]string, 0, len(missing))
@@ -335,9 +336,9 @@ func encodeSyntaxBodyWithSourceHelpers(
 			files = append(files, file)
 		}
 		slices.Sort(files)
-		return "", fmt.Errorf("missing source metadata for %s", strings.Join(files, ", "))
+		return fmt.Errorf("missing source metadata for %s", strings.Join(files, ", "))
 	}
-	return encoded, nil
+	return nil
 }
 
 func compositeKindNames(syntaxBodies map[string]map[string]syntax.FactoryBody) []string {
@@ -571,8 +572,16 @@ func {{.FuncName}}(start, end int) parse.Span {
 {{- if .Schema}}
 	{{.Schema}}
 {{- end}}
+{{end}}
+{{- if .Functions}}	functions := {{.Functions}}
 {{end}}	return &runtime.Library{
 		Name: {{quote .LibraryName}},
+{{- if .Functions}}
+		Functions: runtime.SyntaxFunctions(functions),
+		Schema: &runtime.LibrarySchema{
+			Functions: runtime.SyntaxFunctionSigs(functions),
+		},
+{{- end}}
 {{range .Groups}}		{{.MapField}}: map[string]*runtime.CompositeType{
 {{range .Entries}}			{{quote .Name}}: {
 				Name: {{quote .Name}},
@@ -166,16 +166,12 @@ func TestGenerateUBLibraryCompositeConfigSchemaGolden(t *testing.T) {
 					},
 				},
 			}
-			out, err := GenerateUBLibraryPackageWithAssetsAndConfigSchemas(
-				"wrapper",
-				"wrapper",
-				map[string]map[string]syntax.FactoryBody{"action": {"region": body}},
-				nil,
-				nil,
-				nil,
-				nil,
-				configSchemas,
-			)
+			out, err := GenerateUBLibraryPackage(UBLibraryInput{
+				PackageID:            "wrapper",
+				LibraryName:          "wrapper",
+				SyntaxBodies:         map[string]map[string]syntax.FactoryBody{"action": {"region": body}},
+				LibraryConfigSchemas: configSchemas,
+			})
 			if err != nil {
 				return "", []string{err.Error()}
 			}
@@ -196,14 +192,12 @@ func TestGenerateUBLibraryEmitsSyntaxBodyWithSpans(t *testing.T) {
 		},
 	}
 
-	out, err := GenerateUBLibraryPackage(
-		"net",
-		"net",
-		resourceSyntaxBodies(map[string]syntax.FactoryBody{"cluster": body}),
-		nil,
-		nil,
-		sourceFiles,
-	)
+	out, err := GenerateUBLibraryPackage(UBLibraryInput{
+		PackageID:    "net",
+		LibraryName:  "net",
+		SyntaxBodies: resourceSyntaxBodies(map[string]syntax.FactoryBody{"cluster": body}),
+		SourceFiles:  sourceFiles,
+	})
 	require.NoError(t, err)
 
 	s := string(out)
@@ -236,17 +230,15 @@ func TestGenerateUBLibraryUsesMultipleSourceHelpers(t *testing.T) {
 		},
 	}
 
-	out, err := GenerateUBLibraryPackage(
-		"net",
-		"net",
-		resourceSyntaxBodies(map[string]syntax.FactoryBody{
+	out, err := GenerateUBLibraryPackage(UBLibraryInput{
+		PackageID:   "net",
+		LibraryName: "net",
+		SyntaxBodies: resourceSyntaxBodies(map[string]syntax.FactoryBody{
 			"cluster": first,
 			"node":    second,
 		}),
-		nil,
-		nil,
-		sourceFiles,
-	)
+		SourceFiles: sourceFiles,
+	})
 	require.NoError(t, err)
 
 	s := string(out)
@@ -575,14 +567,12 @@ func TestGenerateUBLibraryCompilesWithCaller(t *testing.T) {
 		},
 	}
 
-	out, err := GenerateUBLibraryPackage(
-		"net",
-		"net",
-		resourceSyntaxBodies(map[string]syntax.FactoryBody{"cluster": body}),
-		nil,
-		nil,
-		sourceFiles,
-	)
+	out, err := GenerateUBLibraryPackage(UBLibraryInput{
+		PackageID:    "net",
+		LibraryName:  "net",
+		SyntaxBodies: resourceSyntaxBodies(map[string]syntax.FactoryBody{"cluster": body}),
+		SourceFiles:  sourceFiles,
+	})
 	require.NoError(t, err)
 
 	rootDir := findUnobinRoot(t)
@@ -654,6 +644,112 @@ replace github.com/cloudboss/unobin => %s
 			"github.com/example/repo//libraries/network (libraries/network/library.ub)")
 }
 
+func parseUBLibraryFunctions(t testing.TB, name string) ([]syntax.FunctionDecl, []byte) {
+	t.Helper()
+	src := []byte(ubtest.ReadValidFixture(t, "testdata/ub/ublibrary-functions", name))
+	f, err := syntax.ParseSource("library.ub", src)
+	require.NoError(t, err)
+	require.NotNil(t, f.Library)
+	return f.Library.Functions, src
+}
+
+func TestGenerateUBLibraryEmitsFunctions(t *testing.T) {
+	functions, src := parseUBLibraryFunctions(t, "siblings")
+	sourceFiles := map[string]syntax.SourceFileSpec{
+		"library.ub": {
+			DisplayPath:    "github.com/example/repo//libraries/names (libraries/names/library.ub)",
+			ProjectRelPath: "libraries/names/library.ub",
+			LineStarts:     parse.LineStarts(src),
+		},
+	}
+
+	out, err := GenerateUBLibraryPackage(UBLibraryInput{
+		PackageID:   "names",
+		LibraryName: "names",
+		SourceFiles: sourceFiles,
+		Functions:   functions,
+	})
+	require.NoError(t, err)
+
+	s := string(out)
+	require.Contains(t, s, `functions := []syntax.FunctionDecl{{S: sp0(`)
+	require.Contains(t, s, `Functions: runtime.SyntaxFunctions(functions),`)
+	require.Contains(t, s, `Functions: runtime.SyntaxFunctionSigs(functions),`)
+	require.Contains(t, s, `"github.com/cloudboss/unobin/pkg/lang/syntax"`)
+
+	fset := token.NewFileSet()
+	_, err = parser.ParseFile(fset, "names.go", out, parser.AllErrors)
+	require.NoError(t, err, "generated source should parse:\n%s", out)
+}
+
+func TestGenerateUBLibraryFunctionsRunWithCaller(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipped: spawns `go run` and is slow")
+	}
+
+	functions, _ := parseUBLibraryFunctions(t, "siblings")
+	out, err := GenerateUBLibraryPackage(UBLibraryInput{
+		PackageID:   "names",
+		LibraryName: "names",
+		Functions:   functions,
+	})
+	require.NoError(t, err)
+
+	rootDir := findUnobinRoot(t)
+	tmp := t.TempDir()
+
+	pkgDir := filepath.Join(tmp, "internal", "names")
+	require.NoError(t, os.MkdirAll(pkgDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(pkgDir, "names.go"), out, 0o644))
+
+	main := `package main
+
+import (
+	"fmt"
+	"os"
+
+	"example.test/check/internal/names"
+)
+
+func main() {
+	lib := names.Library()
+	got, err := lib.Functions["bucket-names"].Func([]any{[]any{"dev", "prod"}})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Printf("bucket-names=%v\n", got)
+	fmt.Printf("params=%d\n", len(lib.Schema.Functions["bucket-name"].Params))
+}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, "main.go"), []byte(main), 0o644))
+
+	goMod := fmt.Sprintf(`module example.test/check
+
+go 1.26
+
+require github.com/cloudboss/unobin v0.0.0
+
+replace github.com/cloudboss/unobin => %s
+`, rootDir)
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, "go.mod"), []byte(goMod), 0o644))
+
+	tidy := exec.Command("go", "mod", "tidy")
+	tidy.Dir = tmp
+	if tidyOut, err := tidy.CombinedOutput(); err != nil {
+		t.Fatalf("go mod tidy:\n%s", tidyOut)
+	}
+
+	run := exec.Command("go", "run", ".")
+	run.Dir = tmp
+	runOut, err := run.CombinedOutput()
+	require.NoError(t, err, "go run:\n%s", runOut)
+
+	got := string(runOut)
+	require.Contains(t, got, "bucket-names=[dev-logs prod-logs]")
+	require.Contains(t, got, "params=2")
+}
+
 func findUnobinRoot(t *testing.T) string {
 	t.Helper()
 	cwd, err := os.Getwd()
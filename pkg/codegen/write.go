@@ -8,6 +8,7 @@ import (
 	"slices"
 
 	"github.com/cloudboss/unobin/pkg/filechange"
+	"github.com/cloudboss/unobin/pkg/sbom"
 )
 
 // Replaces maps a library path to a local filesystem path to substitute
@@ -19,8 +20,9 @@ type Replaces map[string]string
 // WriteSource lays out a generated binary's source tree in dir, ready for
 // `go build` to consume. It writes:
 //
-//	<dir>/main.go    // From Generate.
-//	<dir>/go.mod     // With the right require statements.
+//	<dir>/main.go                 // From Generate.
+//	<dir>/factory.materials.json  // From Input.Materials, when set.
+//	<dir>/go.mod                  // With the right require statements.
 //
 // goVersion is the Go toolchain version to declare. unobinVersion is
 // the version of `github.com/cloudboss/unobin` the generated binary
@@ -53,18 +55,16 @@ func WriteSource(
 		changes = appendFileChange(changes, change)
 	} else {
 		var assetChanges []filechange.Change
-		assetChanges, err = filechange.Observe([]string{assetPath}, func() error {
-			removeErr := os.Remove(assetPath)
-			if errors.Is(removeErr, os.ErrNotExist) {
-				return nil
-			}
-			return removeErr
-		})
+		assetChanges, err = removeStale(assetPath)
 		changes = append(changes, assetChanges...)
 	}
 	if err != nil {
 		return finishWriteSource(changes, err)
 	}
+	changes, err = writeMaterials(changes, filepath.Join(dir, sbom.ManifestFile), in.Materials)
+	if err != nil {
+		return finishWriteSource(changes, err)
+	}
 	goModules, err := modulesForGoMod(in.GoImports, in.GoModules, importVersions)
 	if err != nil {
 		return finishWriteSource(changes, err)
@@ -78,6 +78,32 @@ func WriteSource(
 	return finishWriteSource(changes, err)
 }
 
+// writeMaterials writes the materials manifest to path, or removes one
+// an earlier compile left when there is none.
+func writeMaterials(
+	changes []filechange.Change,
+	path string,
+	materials []byte,
+) ([]filechange.Change, error) {
+	if len(materials) > 0 {
+		change, err := filechange.WriteFile(path, materials, 0o644)
+		return appendFileChange(changes, change), err
+	}
+	removed, err := removeStale(path)
+	return append(changes, removed...), err
+}
+
+// removeStale removes a sidecar file an earlier compile wrote, if any.
+func removeStale(path string) ([]filechange.Change, error) {
+	return filechange.Observe([]string{path}, func() error {
+		err := os.Remove(path)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	})
+}
+
 func appendFileChange(changes []filechange.Change, change filechange.Change) []filechange.Change {
 	if change.Path == "" {
 		return changes
@@ -263,6 +263,36 @@ func TestWriteSourceManagesAssetSidecar(t *testing.T) {
 	require.ErrorIs(t, err, os.ErrNotExist)
 }
 
+func TestWriteSourceManagesMaterialsSidecar(t *testing.T) {
+	body := ubtest.ReadValidFixture(t, "testdata/ub/write-source", "minimal")
+	dir := filepath.Join(t.TempDir(), "out")
+	in := testMainInput(t, body, "demo")
+	in.Materials = []byte("{}\n")
+	path := filepath.Join(dir, "factory.materials.json")
+
+	changes, err := writeSource(t, dir, in)
+	require.NoError(t, err)
+	require.Equal(t, []filechange.Change{
+		{Path: path, Action: filechange.ActionCreated},
+		{Path: filepath.Join(dir, "go.mod"), Action: filechange.ActionCreated},
+		{Path: filepath.Join(dir, "main.go"), Action: filechange.ActionCreated},
+	}, changes)
+	materials, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "{}\n", string(materials))
+
+	in.Materials = nil
+	changes, err = writeSource(t, dir, in)
+	require.NoError(t, err)
+	require.Equal(t, []filechange.Change{
+		{Path: path, Action: filechange.ActionRemoved},
+		{Path: filepath.Join(dir, "go.mod"), Action: filechange.ActionUnchanged},
+		{Path: filepath.Join(dir, "main.go"), Action: filechange.ActionUpdated},
+	}, changes)
+	_, err = os.Stat(path)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
 func writeSource(
 	t *testing.T,
 	dir string,
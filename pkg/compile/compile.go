@@ -23,6 +23,7 @@ import (
 	"github.com/cloudboss/unobin/pkg/deps"
 	"github.com/cloudboss/unobin/pkg/diagnostic"
 	"github.com/cloudboss/unobin/pkg/filechange"
+	ufs "github.com/cloudboss/unobin/pkg/fs"
 	"github.com/cloudboss/unobin/pkg/golibrary"
 	"github.com/cloudboss/unobin/pkg/goschema"
 	"github.com/cloudboss/unobin/pkg/lang"
@@ -63,6 +64,10 @@ type Options struct {
 	ReplaceGoModules map[string]string
 	// Build runs `go build` in OutDir after writing the source.
 	Build bool
+	// Platforms, when set, builds a binary per platform instead of one
+	// for the host, named `<factory>-<os>-<arch>`, and writes their
+	// checksums to ChecksumsFile beside them. It needs Build.
+	Platforms []Platform
 	// NewResolver constructs the import resolver for a project root;
 	// nil uses NewProjectResolver.
 	NewResolver func(projectDir string) (resolve.Resolver, error)
@@ -72,6 +77,9 @@ type Options struct {
 	Stderr io.Writer
 	// Reporter receives warnings and notices. Nil preserves text output.
 	Reporter diagnostic.Reporter
+	// Cache keeps parsed UB libraries between runs, the way watch mode
+	// compiles again after an edit; nil parses every library each run.
+	Cache *sourcecheck.Cache
 	// TypeObserver, when set, receives every expression the stack's
 	// type checks infer, with its type. The residual-Unknown harness
 	// uses it; nil compiles without recording.
@@ -91,6 +99,10 @@ type Result struct {
 	AssetsPath      string
 	Built           bool
 	BinaryPath      string
+	SBOMPath        string
+	ProvenancePath  string
+	Binaries        []Binary
+	ChecksumsPath   string
 	Files           []filechange.Change
 }
 
@@ -365,6 +377,11 @@ func run(opts Options, resultOut **Result) error {
 	if err != nil {
 		return err
 	}
+	vendor, err := OpenVendor(projectDir, projectLock)
+	if err != nil {
+		return err
+	}
+	resolver = vendor.Wrap(resolver)
 	// The guard sits under every replace layer, so a replaced import
 	// never reaches it and an unreplaced one is refused.
 	resolver = &unobinImportGuard{wrapped: resolver}
@@ -414,6 +431,7 @@ func run(opts Options, resultOut **Result) error {
 		Source:                  rootSource,
 		RootSourceFile:          factorySource,
 		GeneratedOutputPath:     generatedOutputPath,
+		Cache:                   opts.Cache,
 	})
 	if err != nil {
 		return err
@@ -520,6 +538,12 @@ func run(opts Options, resultOut **Result) error {
 		return err
 	}
 
+	manifest := materialsManifest(name, opts.LibraryPath, projectLock, assetCatalog)
+	in.Materials, err = manifest.Encode()
+	if err != nil {
+		return err
+	}
+
 	replaces := codegen.Replaces{}
 	if replaceUnobinAbs != "" {
 		replaces[toolchain.UnobinModulePath] = replaceUnobinAbs
@@ -528,6 +552,9 @@ func run(opts Options, resultOut **Result) error {
 	if err := addProjectReplaces(replaces, projectDir, replaceMap, analysis.GoModules); err != nil {
 		return err
 	}
+	if err := vendor.addGoReplaces(replaces, analysis.GoModules); err != nil {
+		return err
+	}
 
 	changes, err := codegen.WriteSource(opts.OutDir, in,
 		opts.GoVersion, unobinVersion, analysis.GoModules, replaces)
@@ -554,7 +581,8 @@ func run(opts Options, resultOut **Result) error {
 	if opts.Build {
 		buildResult, err := runGoBuild(
 			opts.stdout(), opts.stderr(), opts.reporter(),
-			opts.OutDir, name, opts.Version, unobinVersion,
+			opts.OutDir, name, opts.Version, unobinVersion, opts.Platforms,
+			vendor != nil,
 		)
 		result.Files = append(result.Files, buildResult.Files...)
 		result.ContentRevision = buildResult.ContentRevision
@@ -562,7 +590,30 @@ func run(opts Options, resultOut **Result) error {
 			return err
 		}
 		result.Built = true
-		result.BinaryPath = filepath.Join(opts.OutDir, name)
+		if len(opts.Platforms) == 0 {
+			result.BinaryPath = filepath.Join(opts.OutDir, name)
+			bom, changes, err := writeBillOfMaterials(
+				manifest, opts.Version, result.ContentRevision, result.BinaryPath)
+			result.Files = append(result.Files, changes...)
+			if err != nil {
+				return err
+			}
+			result.SBOMPath = bom.SBOMPath
+			result.ProvenancePath = bom.ProvenancePath
+			return nil
+		}
+		result.ChecksumsPath = filepath.Join(opts.OutDir, ChecksumsFile)
+		for _, binary := range buildResult.Binaries {
+			bom, changes, err := writeBillOfMaterials(
+				manifest, opts.Version, result.ContentRevision, binary.Path)
+			result.Files = append(result.Files, changes...)
+			if err != nil {
+				return err
+			}
+			binary.SBOMPath = bom.SBOMPath
+			binary.ProvenancePath = bom.ProvenancePath
+			result.Binaries = append(result.Binaries, binary)
+		}
 	}
 	return nil
 }
@@ -601,11 +652,13 @@ func verifySelectedUnobin(
 	reporter diagnostic.Reporter,
 	goBin string,
 	dir string,
+	env []string,
 	expected string,
 ) error {
 	list := exec.Command(goBin, "list", "-m",
 		"-f", "{{.Version}}{{if .Replace}} replaced{{end}}", toolchain.UnobinModulePath)
 	list.Dir = dir
+	list.Env = commandEnv(env)
 	out, err := list.Output()
 	if err != nil {
 		return diagnostic.Context(fmt.Sprintf(
@@ -629,8 +682,18 @@ func verifySelectedUnobin(
 type goBuildResult struct {
 	ContentRevision string
 	Files           []filechange.Change
+	Binaries        []Binary
 }
 
+// offlineGoEnv keeps the go command from downloading modules, so a
+// module missing from the module cache fails the build.
+var offlineGoEnv = []string{"GOPROXY=off"}
+
+// runGoBuild tidies the module in dir and builds it. With no platforms
+// it builds binaryName for the host; otherwise it builds a binary per
+// platform and writes their checksums. An offline build, the one a
+// vendored project gets, downloads neither the Go toolchain nor any Go
+// modules.
 func runGoBuild(
 	stdout io.Writer,
 	stderr io.Writer,
@@ -639,15 +702,26 @@ func runGoBuild(
 	binaryName string,
 	version string,
 	expectedUnobin string,
+	platforms []Platform,
+	offline bool,
 ) (goBuildResult, error) {
 	result := goBuildResult{Files: []filechange.Change{}}
-	goBin, err := toolchain.Ensure(stderr)
-	if err != nil {
+	var goBin string
+	var goEnv []string
+	var err error
+	if offline {
+		goEnv = offlineGoEnv
+		goBin, err = toolchain.Installed()
+		if err != nil {
+			return result, offlineToolchainError(err)
+		}
+	} else if goBin, err = toolchain.Ensure(stderr); err != nil {
 		return result, err
 	}
 
 	tidy := exec.Command(goBin, "mod", "tidy")
 	tidy.Dir = dir
+	tidy.Env = commandEnv(goEnv)
 	tidy.Stdout = stdout
 	tidy.Stderr = stderr
 	changes, err := filechange.Observe([]string{
@@ -656,10 +730,13 @@ func runGoBuild(
 	}, tidy.Run)
 	result.Files = append(result.Files, changes...)
 	if err != nil {
+		if offline {
+			return result, offlineTidyError(err)
+		}
 		return result, diagnostic.Context("go mod tidy failed", err)
 	}
 
-	if err := verifySelectedUnobin(reporter, goBin, dir, expectedUnobin); err != nil {
+	if err := verifySelectedUnobin(reporter, goBin, dir, goEnv, expectedUnobin); err != nil {
 		return result, err
 	}
 
@@ -672,26 +749,80 @@ func runGoBuild(
 	ldflags := fmt.Sprintf(
 		"-X main.factoryVersion=%s -X main.contentRevision=%s -X main.unobinVersion=%s",
 		version, revision, expectedUnobin)
-	build := exec.Command(
-		goBin, "build", "-buildvcs=false", "-ldflags", ldflags, "-o", binaryName, ".")
-	build.Dir = dir
-	build.Stdout = stdout
-	build.Stderr = stderr
-	changes, err = filechange.Observe(
-		[]string{filepath.Join(dir, binaryName)}, build.Run,
-	)
-	result.Files = append(result.Files, changes...)
-	if err != nil {
-		return result, diagnostic.Context("go build failed", err)
+	build := func(output string, env []string) error {
+		cmd := exec.Command(
+			goBin, "build", "-buildvcs=false", "-ldflags", ldflags, "-o", output, ".")
+		cmd.Dir = dir
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
+		cmd.Env = commandEnv(slices.Concat(goEnv, env))
+		changes, err := filechange.Observe(
+			[]string{filepath.Join(dir, output)}, cmd.Run,
+		)
+		result.Files = append(result.Files, changes...)
+		if err != nil {
+			return diagnostic.Context("go build failed", err)
+		}
+		diagnostic.Report(reporter, diagnostic.Diagnostic{
+			Code:     "unobin.compile.built",
+			Severity: diagnostic.SeverityInfo,
+			Message: fmt.Sprintf(
+				"Built %s %s (content-revision %s)", output, version, revision,
+			),
+		})
+		return nil
 	}
-	diagnostic.Report(reporter, diagnostic.Diagnostic{
-		Code:     "unobin.compile.built",
-		Severity: diagnostic.SeverityInfo,
-		Message: fmt.Sprintf(
-			"Built %s %s (content-revision %s)", binaryName, version, revision,
-		),
-	})
-	return result, nil
+	if len(platforms) == 0 {
+		return result, build(binaryName, nil)
+	}
+	for _, platform := range platforms {
+		output := platform.binaryName(binaryName)
+		env := []string{"GOOS=" + platform.OS, "GOARCH=" + platform.Arch}
+		if err := build(output, env); err != nil {
+			return result, err
+		}
+		path := filepath.Join(dir, output)
+		sum, err := ufs.FileSHA256(path)
+		if err != nil {
+			return result, err
+		}
+		result.Binaries = append(result.Binaries, Binary{
+			Platform: platform, Path: path, SHA256: sum,
+		})
+	}
+	change, err := writeChecksums(dir, result.Binaries)
+	if change.Path != "" {
+		result.Files = append(result.Files, change)
+	}
+	return result, err
+}
+
+// commandEnv returns the environment of a go command run with extra
+// set, or nil, inheriting this process's, when extra is empty.
+func commandEnv(extra []string) []string {
+	if len(extra) == 0 {
+		return nil
+	}
+	return append(os.Environ(), extra...)
+}
+
+// offlineTidyError explains a tidy that failed because downloads were
+// off: vendor/ holds the Go libraries but not the modules they require.
+func offlineTidyError(err error) error {
+	return diagnostic.Context(fmt.Sprintf(
+		"go mod tidy failed with Go module downloads disabled; while %s/ exists, compile"+
+			" fetches nothing, so the modules the factory and its vendored Go libraries"+
+			" require must already be in the Go module cache. Compile once without %s/"+
+			" to fill it", deps.VendorDirName, deps.VendorDirName), err)
+}
+
+// offlineToolchainError explains a missing Go toolchain that compile did
+// not download because vendor/ exists.
+func offlineToolchainError(err error) error {
+	return diagnostic.Context(fmt.Sprintf(
+		"while %s/ exists, compile downloads nothing, so the pinned Go toolchain must"+
+			" already be in the unobin cache. Compile once without %s/ to fetch it",
+		deps.VendorDirName, deps.VendorDirName), err)
 }
 
 // ProjectLockVersions reads dependency project-lock from dir and returns each repository's
@@ -781,6 +912,7 @@ func (r *projectLockResolver) Resolve(ref resolve.ImportRef) (*resolve.Source, e
 	projectLockRef.ProjectSubdir = owner.Project.Subdir
 	projectLockRef.PackageSubdir = ri.Subdir
 	projectLockRef.Version = entry.Commit
+	projectLockRef.Hash = entry.Hash
 	src, err := r.wrapped.Resolve(&projectLockRef)
 	if err != nil {
 		return nil, err
@@ -826,6 +958,7 @@ func (r *projectLockResolver) verifyUBHash(
 		ProjectSubdir: project.Subdir,
 		PackageSubdir: project.Subdir,
 		Version:       entry.Commit,
+		Hash:          entry.Hash,
 	}
 	projectSrc, err := r.wrapped.Resolve(projectRef)
 	if err != nil {
@@ -834,7 +967,7 @@ func (r *projectLockResolver) verifyUBHash(
 	if err := requireUBProjectMarker(project, projectSrc.FS); err != nil {
 		return err
 	}
-	hash, err := deps.HashUBProject(projectSrc.FS)
+	hash, err := resolve.HashUBProject(projectSrc.FS)
 	if err != nil {
 		return err
 	}
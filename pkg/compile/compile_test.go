@@ -159,7 +159,7 @@ func TestWrapProjectLockSourcesRequiresUBProjectMarker(t *testing.T) {
 	fsys := fstest.MapFS{
 		"library.ub": &fstest.MapFile{Data: []byte("thing: resource {}\n")},
 	}
-	hash, err := deps.HashUBProject(fsys)
+	hash, err := resolve.HashUBProject(fsys)
 	require.NoError(t, err)
 	projectLock := deps.NewProjectLock()
 	projectLock.Deps["example.com/repo"] = &deps.ProjectLockDep{
@@ -0,0 +1,95 @@
+package compile
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/cloudboss/unobin/pkg/filechange"
+	"github.com/cloudboss/unobin/pkg/toolchain"
+)
+
+// ChecksumsFile is the file a cross-platform build writes beside its
+// binaries, in the `<sha256>  <name>` form `sha256sum -c` reads.
+const ChecksumsFile = "SHA256SUMS"
+
+// Platform is a GOOS/GOARCH pair a factory binary is built for.
+type Platform struct {
+	OS   string
+	Arch string
+}
+
+// String returns the platform as `<os>/<arch>`.
+func (p Platform) String() string { return p.OS + "/" + p.Arch }
+
+// binaryName returns the name of the binary built for p from a factory
+// named name.
+func (p Platform) binaryName(name string) string {
+	return name + "-" + p.OS + "-" + p.Arch
+}
+
+// Binary is one binary of a cross-platform build, with the SBOM and
+// provenance statement written beside it.
+type Binary struct {
+	Platform       Platform
+	Path           string
+	SHA256         string
+	SBOMPath       string
+	ProvenancePath string
+}
+
+// ParsePlatforms parses `<os>/<arch>` values, each of which may hold a
+// comma-separated list. Only the platforms the pinned Go toolchain
+// covers are accepted, and each may appear once.
+func ParsePlatforms(values []string) ([]Platform, error) {
+	var platforms []Platform
+	for _, value := range values {
+		for field := range strings.SplitSeq(value, ",") {
+			field = strings.TrimSpace(field)
+			osName, arch, ok := strings.Cut(field, "/")
+			if !ok || osName == "" || arch == "" {
+				return nil, fmt.Errorf("platform %q is not of the form <os>/<arch>", field)
+			}
+			platform := Platform{OS: osName, Arch: arch}
+			if !slices.Contains(SupportedPlatforms(), platform) {
+				return nil, fmt.Errorf("platform %s is not supported; use one of %s",
+					platform, strings.Join(platformStrings(SupportedPlatforms()), ", "))
+			}
+			if slices.Contains(platforms, platform) {
+				return nil, fmt.Errorf("platform %s is given more than once", platform)
+			}
+			platforms = append(platforms, platform)
+		}
+	}
+	return platforms, nil
+}
+
+// SupportedPlatforms returns the platforms a factory may be built for,
+// those the pinned Go toolchain has an archive for.
+func SupportedPlatforms() []Platform {
+	pinned := toolchain.Platforms()
+	platforms := make([]Platform, 0, len(pinned))
+	for _, p := range pinned {
+		platforms = append(platforms, Platform{OS: p.OS, Arch: p.Arch})
+	}
+	return platforms
+}
+
+func platformStrings(platforms []Platform) []string {
+	out := make([]string, 0, len(platforms))
+	for _, p := range platforms {
+		out = append(out, p.String())
+	}
+	return out
+}
+
+// writeChecksums writes ChecksumsFile in dir with a line for each of
+// binaries, named relative to dir.
+func writeChecksums(dir string, binaries []Binary) (filechange.Change, error) {
+	var b strings.Builder
+	for _, binary := range binaries {
+		fmt.Fprintf(&b, "%s  %s\n", binary.SHA256, filepath.Base(binary.Path))
+	}
+	return filechange.WriteFile(filepath.Join(dir, ChecksumsFile), []byte(b.String()), 0o644)
+}
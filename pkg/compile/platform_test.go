@@ -0,0 +1,78 @@
+package compile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	ufs "github.com/cloudboss/unobin/pkg/fs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePlatforms(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []string
+		want    []Platform
+		wantErr string
+	}{
+		{
+			name:   "lists and repeats",
+			values: []string{"linux/amd64,darwin/arm64", "linux/arm64"},
+			want: []Platform{
+				{OS: "linux", Arch: "amd64"},
+				{OS: "darwin", Arch: "arm64"},
+				{OS: "linux", Arch: "arm64"},
+			},
+		},
+		{
+			name:    "malformed",
+			values:  []string{"linux"},
+			wantErr: `platform "linux" is not of the form <os>/<arch>`,
+		},
+		{
+			name:   "unsupported",
+			values: []string{"windows/amd64"},
+			wantErr: "platform windows/amd64 is not supported; use one of " +
+				"darwin/amd64, darwin/arm64, linux/amd64, linux/arm64",
+		},
+		{
+			name:    "duplicate",
+			values:  []string{"linux/amd64", "linux/amd64"},
+			wantErr: "platform linux/amd64 is given more than once",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePlatforms(tt.values)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWriteChecksums(t *testing.T) {
+	dir := t.TempDir()
+	var binaries []Binary
+	for _, p := range []Platform{{OS: "linux", Arch: "amd64"}, {OS: "darwin", Arch: "arm64"}} {
+		path := filepath.Join(dir, p.binaryName("demo"))
+		require.NoError(t, os.WriteFile(path, []byte(p.String()), 0o755))
+		sum, err := ufs.FileSHA256(path)
+		require.NoError(t, err)
+		binaries = append(binaries, Binary{Platform: p, Path: path, SHA256: sum})
+	}
+
+	change, err := writeChecksums(dir, binaries)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(dir, ChecksumsFile), change.Path)
+	got, err := os.ReadFile(change.Path)
+	require.NoError(t, err)
+	require.Equal(t,
+		"baf0239e48ff4c47ebac3ba02b5cf1506b69cd5a0c0d0c825a53ba65976fb942  demo-linux-amd64\n"+
+			"2b42ce3bed293705db91d71982cdd0ac166fc667348d213b7868fab1f74e1495  demo-darwin-arm64\n",
+		string(got))
+}
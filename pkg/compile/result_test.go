@@ -12,6 +12,7 @@ import (
 	"github.com/cloudboss/unobin/pkg/asset"
 	"github.com/cloudboss/unobin/pkg/filechange"
 	"github.com/cloudboss/unobin/pkg/resolve"
+	"github.com/cloudboss/unobin/pkg/sbom"
 	"github.com/stretchr/testify/require"
 )
 
@@ -95,7 +96,19 @@ func TestRunResultWritesCapturedAssets(t *testing.T) {
 	mainSource, err := os.ReadFile(compiled.MainGoPath)
 	require.NoError(t, err)
 	require.Contains(t, string(mainSource), "//go:embed factory.assets")
+	require.Contains(t, string(mainSource), "//go:embed factory.materials.json")
 	require.NotContains(t, string(mainSource), "./message.txt")
+
+	body, err := os.ReadFile(filepath.Join(root, "build", sbom.ManifestFile))
+	require.NoError(t, err)
+	manifest, err := sbom.DecodeManifest(body)
+	require.NoError(t, err)
+	require.Equal(t, sbom.Factory{Name: "demo", LibraryPath: "example.com/demo"}, manifest.Factory)
+	require.Empty(t, manifest.Projects)
+	require.Len(t, manifest.Assets, 1)
+	require.Equal(t, "message", manifest.Assets[0].Name)
+	require.Empty(t, manifest.Assets[0].Path)
+	require.Equal(t, int64(15), manifest.Assets[0].Size)
 }
 
 func TestRunResultRejectsAssetStdout(t *testing.T) {
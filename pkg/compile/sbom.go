@@ -0,0 +1,118 @@
+package compile
+
+import (
+	"debug/buildinfo"
+	"path/filepath"
+
+	"github.com/cloudboss/unobin/pkg/asset"
+	"github.com/cloudboss/unobin/pkg/deps"
+	"github.com/cloudboss/unobin/pkg/filechange"
+	ufs "github.com/cloudboss/unobin/pkg/fs"
+	"github.com/cloudboss/unobin/pkg/sbom"
+)
+
+// materialsManifest lists what a factory is built from beyond its Go
+// modules: every dependency projectLock selects and every captured file
+// of catalog.
+func materialsManifest(
+	name string,
+	libraryPath string,
+	projectLock *deps.ProjectLock,
+	catalog *asset.Catalog,
+) sbom.Manifest {
+	manifest := sbom.Manifest{
+		FormatVersion: sbom.ManifestFormatVersion,
+		Factory:       sbom.Factory{Name: name, LibraryPath: libraryPath},
+	}
+	if projectLock != nil {
+		for _, id := range projectLock.SortedIDs() {
+			dep := projectLock.Deps[id]
+			manifest.Projects = append(manifest.Projects, sbom.Project{
+				ID:      id,
+				Kind:    string(dep.Kind),
+				Version: dep.Version,
+				Commit:  dep.Commit,
+				Hash:    dep.Hash,
+			})
+		}
+	}
+	for _, set := range catalog.Sets() {
+		for _, item := range set.Assets() {
+			for _, entry := range item.Entries() {
+				if entry.Kind != asset.EntryKindFile {
+					continue
+				}
+				manifest.Assets = append(manifest.Assets, sbom.Asset{
+					Set:    set.ID,
+					Name:   item.Name,
+					Path:   entry.InternalPath,
+					SHA256: entry.ContentSHA256,
+					Size:   entry.ContentSize,
+				})
+			}
+		}
+	}
+	return manifest
+}
+
+// billOfMaterials is the SBOM and provenance statement written beside
+// one binary.
+type billOfMaterials struct {
+	SBOMPath       string
+	ProvenancePath string
+}
+
+// writeBillOfMaterials writes `<binary>.sbom.json` and
+// `<binary>.provenance.json` beside the binary at path. They are the
+// documents the binary's own `version --sbom` and `version --provenance`
+// print, since both read the same manifest and Go build info.
+func writeBillOfMaterials(
+	manifest sbom.Manifest,
+	version string,
+	revision string,
+	path string,
+) (billOfMaterials, []filechange.Change, error) {
+	var (
+		out     billOfMaterials
+		changes []filechange.Change
+	)
+	info, err := buildinfo.ReadFile(path)
+	if err != nil {
+		return out, changes, err
+	}
+	build := sbom.Build{
+		Manifest: manifest, Version: version, ContentRevision: revision, Info: info,
+	}
+	sum, err := ufs.FileSHA256(path)
+	if err != nil {
+		return out, changes, err
+	}
+	bom, err := sbom.CycloneDX(build)
+	if err != nil {
+		return out, changes, err
+	}
+	statement, err := sbom.Provenance(build, []sbom.Subject{
+		{Name: filepath.Base(path), SHA256: sum},
+	})
+	if err != nil {
+		return out, changes, err
+	}
+	for _, doc := range []struct {
+		path string
+		body []byte
+	}{
+		{path: path + ".sbom.json", body: bom},
+		{path: path + ".provenance.json", body: statement},
+	} {
+		change, err := filechange.WriteFile(doc.path, doc.body, 0o644)
+		if change.Path != "" {
+			changes = append(changes, change)
+		}
+		if err != nil {
+			return out, changes, err
+		}
+	}
+	out.SBOMPath = path + ".sbom.json"
+	out.ProvenancePath = path + ".provenance.json"
+	return out, changes, nil
+}
@@ -0,0 +1,167 @@
+package compile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudboss/unobin/pkg/codegen"
+	"github.com/cloudboss/unobin/pkg/deps"
+	"github.com/cloudboss/unobin/pkg/projectmarker"
+	"github.com/cloudboss/unobin/pkg/resolve"
+)
+
+// Vendor serves remote imports from the vendor directory `unobin deps
+// vendor` writes under a project root, so a vendored project compiles
+// without fetching its dependencies.
+type Vendor struct {
+	dir         string
+	projectLock *deps.ProjectLock
+}
+
+// OpenVendor returns the vendor directory under root when `unobin deps
+// vendor` wrote one, or nil when root has none. The directory must have
+// been written from the same dependencies projectLock selects, and every
+// vendored UB project must still hash to its project-lock.ub hash, so a
+// vendor directory that has drifted from the lock is an error rather
+// than a silent substitute.
+func OpenVendor(root string, projectLock *deps.ProjectLock) (*Vendor, error) {
+	vendored, err := deps.ReadVendorLock(root)
+	if err != nil || vendored == nil {
+		return nil, err
+	}
+	if projectLock == nil {
+		projectLock = deps.NewProjectLock()
+	}
+	if mismatches := deps.VendorMismatches(vendored, projectLock); len(mismatches) > 0 {
+		return nil, vendorMismatchError(mismatches)
+	}
+	dir, err := filepath.Abs(filepath.Join(root, deps.VendorDirName))
+	if err != nil {
+		return nil, err
+	}
+	v := &Vendor{dir: dir, projectLock: projectLock}
+	var missing []string
+	for _, id := range projectLock.SortedIDs() {
+		if _, err := os.Stat(v.projectDir(id)); err != nil {
+			missing = append(missing, fmt.Sprintf("%s is missing from %s/", id, deps.VendorDirName))
+		}
+	}
+	if len(missing) > 0 {
+		return nil, vendorMismatchError(missing)
+	}
+	verified, err := deps.Verify(projectLock, v)
+	if err != nil {
+		return nil, err
+	}
+	var changed []string
+	for _, m := range verified.Mismatches {
+		changed = append(changed, fmt.Sprintf(
+			"%s: hash mismatch (selected %s, got %s)", m.ID, m.ExpectedHash, m.ActualHash))
+	}
+	if len(changed) > 0 {
+		return nil, vendorMismatchError(changed)
+	}
+	return v, nil
+}
+
+func vendorMismatchError(mismatches []string) error {
+	return fmt.Errorf("%s/ does not match %s:\n  %s\nrun `unobin deps vendor` to refresh it",
+		deps.VendorDirName, deps.ProjectLockFileName, strings.Join(mismatches, "\n  "))
+}
+
+// projectDir returns the vendored directory of the project-lock id.
+func (v *Vendor) projectDir(id string) string {
+	url, subdir, _ := resolve.SplitRepoSubdir(id)
+	return filepath.Join(resolve.VendorRepoDir(v.dir, url), filepath.FromSlash(subdir))
+}
+
+// Wrap returns resolver with its remote imports served from v. Local
+// imports still go to resolver. A nil v returns resolver unchanged.
+func (v *Vendor) Wrap(resolver resolve.Resolver) resolve.Resolver {
+	if v == nil {
+		return resolver
+	}
+	return &vendorResolver{vendor: v, wrapped: resolver}
+}
+
+type vendorResolver struct {
+	vendor  *Vendor
+	wrapped resolve.Resolver
+}
+
+func (r *vendorResolver) Resolve(ref resolve.ImportRef) (*resolve.Source, error) {
+	if _, ok := ref.(*resolve.RemoteImport); ok {
+		return r.vendor.Resolve(ref)
+	}
+	return r.wrapped.Resolve(ref)
+}
+
+// Resolve returns the vendored source of a remote import. The import
+// must belong to a project selected in project-lock.ub and name no
+// version or the selected one.
+func (v *Vendor) Resolve(ref resolve.ImportRef) (*resolve.Source, error) {
+	ri, ok := ref.(*resolve.RemoteImport)
+	if !ok {
+		return nil, fmt.Errorf("unsupported import ref type %T", ref)
+	}
+	owner, entry, ok := projectLockOwner(v.projectLock, ri)
+	if !ok {
+		return nil, fmt.Errorf(
+			"%s is not vendored; run `unobin deps sync` and `unobin deps vendor`",
+			deps.RemotePackage{URL: ri.URL, Subdir: ri.Subdir})
+	}
+	switch ri.Version {
+	case "", entry.Commit, entry.Version, deps.ProjectTag(owner.Project, entry.Version):
+	default:
+		return nil, fmt.Errorf("%s@%s is not vendored; %s/ holds %s",
+			deps.RemotePackage{URL: ri.URL, Subdir: ri.Subdir}, ri.Version,
+			deps.VendorDirName, entry.Version)
+	}
+	vendorRef := *ri
+	vendorRef.ProjectSubdir = owner.Project.Subdir
+	vendorRef.PackageSubdir = ri.Subdir
+	return resolve.VendoredSource(&vendorRef, entry.Commit, resolve.VendorRepoDir(v.dir, ri.URL))
+}
+
+// CachedSource returns the vendored source of ref, reporting false when
+// the vendor directory does not hold it. It lets the language server
+// read a vendored project as it reads the import cache.
+func (v *Vendor) CachedSource(ref *resolve.RemoteImport, commit string) (*resolve.Source, bool, error) {
+	src, err := resolve.VendoredSource(ref, commit, resolve.VendorRepoDir(v.dir, ref.URL))
+	if err != nil {
+		return nil, false, nil
+	}
+	return src, true, nil
+}
+
+// addGoReplaces records a go.mod replace pointing each vendored Go
+// module the factory uses at its vendored copy, unless a replace for
+// the module is already recorded.
+func (v *Vendor) addGoReplaces(replaces codegen.Replaces, goModules map[string]string) error {
+	if v == nil {
+		return nil
+	}
+	for _, id := range v.projectLock.SortedIDs() {
+		if v.projectLock.Deps[id].Kind != deps.ProjectLockKindGo {
+			continue
+		}
+		dir := v.projectDir(id)
+		marker, err := projectmarker.ClassifyDir(dir)
+		if err != nil {
+			return err
+		}
+		if marker.Kind != projectmarker.Go {
+			continue
+		}
+		if _, ok := goModules[marker.ModulePath]; !ok {
+			continue
+		}
+		if _, ok := replaces[marker.ModulePath]; ok {
+			continue
+		}
+		replaces[marker.ModulePath] = dir
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package compile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudboss/unobin/internal/ubtest"
+	"github.com/cloudboss/unobin/pkg/codegen"
+	"github.com/cloudboss/unobin/pkg/deps"
+	"github.com/cloudboss/unobin/pkg/resolve"
+	"github.com/stretchr/testify/require"
+)
+
+// writeVendorTree writes a vendor directory holding a UB project with a
+// helloer package and a Go module, and returns the project-lock it was
+// written from.
+func writeVendorTree(t *testing.T, root string) *deps.ProjectLock {
+	t.Helper()
+	dir := filepath.Join(root, deps.VendorDirName)
+	files := map[string]string{
+		"github.com/x/scratch/ub/project.ub": ubtest.ReadValidFixture(
+			t, "testdata/ub/vendor", "empty-project"),
+		"github.com/x/scratch/ub/helloer/library.ub": ubtest.ReadValidFixture(
+			t, "testdata/ub/vendor", "helloer-library"),
+		"github.com/x/golib/go.mod": "module github.com/x/golib\n",
+		"github.com/x/golib/lib.go": "package golib\n",
+	}
+	for name, body := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(body), 0o644))
+	}
+	hash, err := resolve.HashUBProject(os.DirFS(filepath.Join(dir, "github.com/x/scratch/ub")))
+	require.NoError(t, err)
+	projectLock := deps.NewProjectLock()
+	projectLock.ToolchainVersion = "dev"
+	projectLock.Deps["github.com/x/scratch//ub"] = &deps.ProjectLockDep{
+		Kind: deps.ProjectLockKindUB, Version: "v0.8.0", Commit: "c1", Hash: hash,
+	}
+	projectLock.Deps["github.com/x/golib"] = &deps.ProjectLockDep{
+		Kind: deps.ProjectLockKindGo, Version: "v1.0.0", Commit: "go1",
+	}
+	require.NoError(t, deps.WriteProjectLock(
+		filepath.Join(dir, deps.ProjectLockFileName), projectLock))
+	return projectLock
+}
+
+func TestOpenVendorWithoutVendor(t *testing.T) {
+	vendor, err := OpenVendor(t.TempDir(), nil)
+	require.NoError(t, err)
+	require.Nil(t, vendor)
+	var resolver resolve.Resolver = resolve.NewLocalResolver(".")
+	require.Same(t, resolver, vendor.Wrap(resolver))
+}
+
+func TestVendorResolve(t *testing.T) {
+	root := t.TempDir()
+	projectLock := writeVendorTree(t, root)
+	vendor, err := OpenVendor(root, projectLock)
+	require.NoError(t, err)
+	dir, err := filepath.Abs(filepath.Join(root, deps.VendorDirName))
+	require.NoError(t, err)
+
+	for _, version := range []string{"", "v0.8.0", "ub/v0.8.0", "c1"} {
+		src, err := vendor.Resolve(&resolve.RemoteImport{
+			URL: "github.com/x/scratch", Subdir: "ub/helloer", Version: version,
+		})
+		require.NoError(t, err, version)
+		require.Equal(t, "c1", src.Commit)
+		require.Equal(t, filepath.Join(dir, "github.com/x/scratch/ub/helloer"), src.Path)
+		require.Equal(t, "ub", src.ProjectSubdir)
+	}
+
+	src, err := vendor.Resolve(&resolve.RemoteImport{URL: "github.com/x/golib"})
+	require.NoError(t, err)
+	require.Equal(t, "github.com/x/golib", src.ModulePath)
+
+	_, err = vendor.Resolve(&resolve.RemoteImport{
+		URL: "github.com/x/scratch", Subdir: "ub/helloer", Version: "v0.9.0",
+	})
+	require.EqualError(t, err,
+		"github.com/x/scratch//ub/helloer@v0.9.0 is not vendored; vendor/ holds v0.8.0")
+	_, err = vendor.Resolve(&resolve.RemoteImport{URL: "github.com/x/other"})
+	require.EqualError(t, err,
+		"github.com/x/other is not vendored; run `unobin deps sync` and `unobin deps vendor`")
+	_, err = vendor.Resolve(&resolve.RemoteImport{URL: "github.com/x/scratch", Subdir: "ub/missing"})
+	require.ErrorContains(t, err, "github.com/x/scratch//ub/missing is not vendored in ")
+
+	replaces := codegen.Replaces{}
+	require.NoError(t, vendor.addGoReplaces(replaces, map[string]string{
+		"github.com/x/golib": "v1.0.0",
+	}))
+	require.Equal(t, codegen.Replaces{
+		"github.com/x/golib": filepath.Join(dir, "github.com/x/golib"),
+	}, replaces)
+}
+
+func TestOpenVendorMismatch(t *testing.T) {
+	root := t.TempDir()
+	projectLock := writeVendorTree(t, root)
+	projectLock.Deps["github.com/x/golib"].Version = "v1.1.0"
+	projectLock.Deps["github.com/x/golib"].Commit = "go2"
+
+	_, err := OpenVendor(root, projectLock)
+	require.EqualError(t, err, "vendor/ does not match project-lock.ub:\n"+
+		"  github.com/x/golib is vendored at v1.0.0 (go1) but project-lock.ub selects v1.1.0 (go2)\n"+
+		"run `unobin deps vendor` to refresh it")
+}
+
+func TestOpenVendorHashMismatch(t *testing.T) {
+	root := t.TempDir()
+	projectLock := writeVendorTree(t, root)
+	library := filepath.Join(root, deps.VendorDirName, "github.com/x/scratch/ub/helloer/library.ub")
+	body, err := os.ReadFile(library)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(library, append(body, '\n'), 0o644))
+
+	_, err = OpenVendor(root, projectLock)
+	require.ErrorContains(t, err, "vendor/ does not match project-lock.ub:\n"+
+		"  github.com/x/scratch//ub: hash mismatch (selected "+projectLock.Deps["github.com/x/scratch//ub"].Hash)
+}
+
+func TestOpenVendorMissingProject(t *testing.T) {
+	root := t.TempDir()
+	projectLock := writeVendorTree(t, root)
+	require.NoError(t, os.RemoveAll(filepath.Join(root, deps.VendorDirName, "github.com/x/golib")))
+
+	_, err := OpenVendor(root, projectLock)
+	require.EqualError(t, err, "vendor/ does not match project-lock.ub:\n"+
+		"  github.com/x/golib is missing from vendor/\n"+
+		"run `unobin deps vendor` to refresh it")
+}
+
+func TestOfflineCommandEnv(t *testing.T) {
+	require.Nil(t, commandEnv(nil))
+	env := commandEnv(offlineGoEnv)
+	require.Equal(t, "GOPROXY=off", env[len(env)-1])
+	require.Greater(t, len(env), len(offlineGoEnv))
+}
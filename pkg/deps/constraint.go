@@ -0,0 +1,164 @@
+package deps
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// constraintOperators are the operators a version clause may start with,
+// longest first so that >= is not read as >.
+var constraintOperators = []string{">=", "<=", "<", ">", "="}
+
+// parseConstraint reads a requirement's version field: a bare floor
+// (v1.2.0), an exact version (= v1.2.0), or comma-separated bounds with
+// exactly one lower bound and at most one upper bound (>= v1.2.0, < v2).
+// A partial upper bound stands for its .0 release, so < v2 admits every
+// v1 and <= v1.4 admits no v1.4.1. An exclusive lower bound (>) is
+// refused: selection needs a floor that is itself a version.
+func parseConstraint(id, text string) (Requirement, error) {
+	var req Requirement
+	for clause := range strings.SplitSeq(text, ",") {
+		clause = strings.TrimSpace(clause)
+		op, version := "", clause
+		for _, candidate := range constraintOperators {
+			if rest, ok := strings.CutPrefix(clause, candidate); ok {
+				op, version = candidate, strings.TrimSpace(rest)
+				break
+			}
+		}
+		if op == ">" {
+			return Requirement{}, fmt.Errorf(
+				"project: dependency %q: %q uses >, which is not supported; "+
+					"use >=, <, <=, or =", id, text)
+		}
+		if err := requireSemver(id, version); err != nil {
+			return Requirement{}, err
+		}
+		switch op {
+		case "=":
+			if req.Version != "" || req.Ceiling != "" {
+				return Requirement{}, fmt.Errorf(
+					"project: dependency %q: %q has = with another bound", id, text)
+			}
+			req.Version = version
+			req.Ceiling = version
+			req.CeilingInclusive = true
+		case "", ">=":
+			if req.Version != "" {
+				return Requirement{}, fmt.Errorf(
+					"project: dependency %q: %q has more than one lower bound", id, text)
+			}
+			req.Version = version
+		default:
+			if req.Ceiling != "" {
+				return Requirement{}, fmt.Errorf(
+					"project: dependency %q: %q has more than one upper bound", id, text)
+			}
+			req.Ceiling = version
+			req.CeilingInclusive = op == "<="
+		}
+	}
+	if req.Version == "" {
+		return Requirement{}, fmt.Errorf(
+			"project: dependency %q: %q has no lower bound; start it with >= <version>", id, text)
+	}
+	if req.Ceiling != "" && !req.belowCeiling(req.Version) {
+		return Requirement{}, fmt.Errorf(
+			"project: dependency %q: no version satisfies %q", id, text)
+	}
+	return req, nil
+}
+
+// Constraint renders the requirement's version field as project.ub
+// writes it: the floor alone, or the floor and its upper bound.
+func (r Requirement) Constraint() string {
+	if r.Ceiling == "" {
+		return r.Version
+	}
+	if r.CeilingInclusive && r.Ceiling == r.Version {
+		return "= " + r.Version
+	}
+	op := "<"
+	if r.CeilingInclusive {
+		op = "<="
+	}
+	return fmt.Sprintf(">= %s, %s %s", r.Version, op, r.Ceiling)
+}
+
+// String describes everything the requirement asks of a version, for
+// conflict errors.
+func (r Requirement) String() string {
+	s := r.Constraint()
+	if len(r.Exclude) > 0 {
+		s += ", excluding " + strings.Join(r.Exclude, ", ")
+	}
+	if r.AllowPrerelease {
+		s += ", pre-releases allowed"
+	}
+	return s
+}
+
+// Admits reports whether version is one the requirement lets selection
+// settle on, apart from its floor: below the upper bound, not excluded,
+// and a release unless the requirement takes pre-releases.
+func (r Requirement) Admits(version string) bool {
+	return r.admitsPrerelease(version) && r.belowCeiling(version) &&
+		!slices.Contains(r.Exclude, version)
+}
+
+// TakesPrereleases reports whether the requirement accepts pre-release
+// versions: it allows them outright, or its floor is one.
+func (r Requirement) TakesPrereleases() bool {
+	return r.AllowPrerelease || semver.Prerelease(r.Version) != ""
+}
+
+// Latest returns the greatest version in vs the requirement admits, or ""
+// when there is none. It picks a pre-release only when AllowPrerelease is
+// set: a pre-release floor takes that pre-release, not newer ones. A
+// non-empty major, such as v1, limits the choice to that major version.
+func (r Requirement) Latest(vs []string, major string) string {
+	var admitted []string
+	for _, v := range vs {
+		if major != "" && semver.Major(v) != major {
+			continue
+		}
+		if semver.Prerelease(v) != "" && !r.AllowPrerelease {
+			continue
+		}
+		if r.Admits(v) {
+			admitted = append(admitted, v)
+		}
+	}
+	return Highest(admitted)
+}
+
+func (r Requirement) admitsPrerelease(version string) bool {
+	return semver.Prerelease(version) == "" || r.TakesPrereleases()
+}
+
+func (r Requirement) belowCeiling(version string) bool {
+	if r.Ceiling == "" {
+		return true
+	}
+	cmp := semver.Compare(version, r.Ceiling)
+	return cmp < 0 || r.CeilingInclusive && cmp == 0
+}
+
+// violation explains why the requirement rules out version, or returns
+// "" when it does not.
+func (r Requirement) violation(version string) string {
+	switch {
+	case !r.belowCeiling(version) && r.CeilingInclusive:
+		return fmt.Sprintf("%s is above %s", version, r.Ceiling)
+	case !r.belowCeiling(version):
+		return fmt.Sprintf("%s is not below %s", version, r.Ceiling)
+	case slices.Contains(r.Exclude, version):
+		return fmt.Sprintf("%s is excluded", version)
+	case !r.admitsPrerelease(version):
+		return fmt.Sprintf("%s is a pre-release", version)
+	}
+	return ""
+}
@@ -17,6 +17,12 @@ type projectFetcher struct {
 	resolver resolve.Resolver
 }
 
+// listingFetcher is a projectFetcher that lists versions from tags.
+type listingFetcher struct {
+	projectFetcher
+	listTags func(url string) ([]string, error)
+}
+
 // NewFetcher returns a Fetcher that reads dependency projects through
 // resolver. It is the production Fetcher behind unobin deps; tests pass a
 // fake resolver.
@@ -24,6 +30,23 @@ func NewFetcher(resolver resolve.Resolver) Fetcher {
 	return &projectFetcher{resolver: resolver}
 }
 
+// NewListingFetcher returns a Fetcher like NewFetcher that is also a
+// VersionLister, reading a dependency's versions from the tags listTags
+// returns for its repository.
+func NewListingFetcher(
+	resolver resolve.Resolver, listTags func(url string) ([]string, error),
+) Fetcher {
+	return &listingFetcher{projectFetcher: projectFetcher{resolver: resolver}, listTags: listTags}
+}
+
+func (f *listingFetcher) Versions(dep Dependency) ([]string, error) {
+	tags, err := f.listTags(dep.URL)
+	if err != nil {
+		return nil, err
+	}
+	return Versions(dep, tags), nil
+}
+
 func (f *projectFetcher) Fetch(dep Dependency, version string) (*Project, error) {
 	ref := &resolve.RemoteImport{
 		URL:     dep.URL,
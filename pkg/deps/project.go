@@ -3,6 +3,7 @@ package deps
 import (
 	"fmt"
 	"io/fs"
+	"maps"
 	"slices"
 	"strings"
 
@@ -10,6 +11,7 @@ import (
 
 	"github.com/cloudboss/unobin/pkg/filechange"
 	"github.com/cloudboss/unobin/pkg/lang"
+	"github.com/cloudboss/unobin/pkg/lang/parse"
 	"github.com/cloudboss/unobin/pkg/lang/syntax"
 	"github.com/cloudboss/unobin/pkg/toolchain"
 )
@@ -17,10 +19,18 @@ import (
 // ProjectFileName is the dependency project filename.
 const ProjectFileName = "project.ub"
 
-// Requirement is a root dependency floor declared by project.ub.
+// Requirement is a dependency floor declared by project.ub, with the
+// constraints that narrow which versions selection may settle on.
+// Ceiling, when set, is an upper bound selection must stay below, or at
+// when CeilingInclusive. A pre-release is selected only when the floor is
+// one or AllowPrerelease is set. Exclude lists versions never selected.
 type Requirement struct {
-	Version  string
-	Indirect bool
+	Version          string
+	Indirect         bool
+	Ceiling          string
+	CeilingInclusive bool
+	AllowPrerelease  bool
+	Exclude          []string
 }
 
 // Project is a parsed dependency project. Requires maps each dependency
@@ -31,11 +41,13 @@ type Requirement struct {
 // instead of fetching it, and the dependency needs no floor or project-lock entry.
 // UnobinVersion, when set, pins the toolchain: only a CLI of exactly that
 // version compiles the project, since the unobin runtime is not a
-// dependency whose version resolution selects.
+// dependency whose version resolution selects. Lint maps a lint rule
+// code to the severity `unobin lint` reports its findings at.
 type Project struct {
 	UnobinVersion string
 	Requires      map[Dependency]Requirement
 	Replace       map[Dependency]string
+	Lint          map[string]string
 }
 
 func (m *Project) RequireVersions() map[Dependency]string {
@@ -77,11 +89,16 @@ func (m *Project) IndirectCount() int {
 	return count
 }
 
+// SetRequire sets dep's floor and indirect flag, keeping any constraints
+// the project already declares for it.
 func (m *Project) SetRequire(dep Dependency, version string, indirect bool) {
 	if m.Requires == nil {
 		m.Requires = map[Dependency]Requirement{}
 	}
-	m.Requires[dep] = Requirement{Version: version, Indirect: indirect}
+	req := m.Requires[dep]
+	req.Version = version
+	req.Indirect = indirect
+	m.Requires[dep] = req
 }
 
 // ReadProject reads and parses project.ub from fsys. A missing file
@@ -117,6 +134,9 @@ func EncodeProject(m *Project) []byte {
 	if len(m.Replace) > 0 {
 		encodeStringBlock(&b, "replace", m.Replace)
 	}
+	if len(m.Lint) > 0 {
+		encodeLintBlock(&b, m.Lint)
+	}
 	b.WriteString("}\n")
 	return []byte(b.String())
 }
@@ -134,10 +154,23 @@ func encodeRequireBlock(b *strings.Builder, entries map[Dependency]Requirement)
 	for _, id := range ids {
 		req := byID[id]
 		fmt.Fprintf(b, "'%s': {\n", id)
-		fmt.Fprintf(b, "version: '%s'\n", req.Version)
+		fmt.Fprintf(b, "version: '%s'\n", req.Constraint())
 		if req.Indirect {
 			b.WriteString("indirect: true\n")
 		}
+		if req.AllowPrerelease {
+			b.WriteString("allow-prerelease: true\n")
+		}
+		if len(req.Exclude) > 0 {
+			b.WriteString("exclude: [")
+			for i, version := range req.Exclude {
+				if i > 0 {
+					b.WriteString(", ")
+				}
+				fmt.Fprintf(b, "'%s'", version)
+			}
+			b.WriteString("]\n")
+		}
 		b.WriteString("}\n")
 	}
 	b.WriteString("}\n")
@@ -163,6 +196,14 @@ func encodeStringBlock(
 	b.WriteString("}\n")
 }
 
+func encodeLintBlock(b *strings.Builder, entries map[string]string) {
+	b.WriteString("lint: {\n")
+	for _, code := range slices.Sorted(maps.Keys(entries)) {
+		fmt.Fprintf(b, "%s: '%s'\n", code, entries[code])
+	}
+	b.WriteString("}\n")
+}
+
 // WriteProject serializes m as canonical project.ub source and atomically
 // replaces the file at path.
 func WriteProject(path string, m *Project) error {
@@ -202,6 +243,12 @@ func parseProjectBody(f *syntax.File) (*Project, error) {
 	if err != nil {
 		return nil, err
 	}
+	for _, decl := range f.Project.Lint {
+		if m.Lint == nil {
+			m.Lint = map[string]string{}
+		}
+		m.Lint[decl.Code.Name] = decl.Severity.Value
+	}
 	return checkProjectToolchainPin(m)
 }
 
@@ -215,14 +262,23 @@ func parseProjectRequires(decls []syntax.ProjectRequire) (map[Dependency]Require
 		if decl.Version == nil {
 			return nil, fmt.Errorf("project: dependency %q: missing version", decl.ID.Value)
 		}
-		version := decl.Version.Value
-		if err := requireSemver(decl.ID.Value, version); err != nil {
+		req, err := parseConstraint(decl.ID.Value, decl.Version.Value)
+		if err != nil {
 			return nil, err
 		}
-		out[dep] = Requirement{
-			Version:  version,
-			Indirect: decl.Indirect != nil && decl.Indirect.Value,
+		req.Indirect = decl.Indirect != nil && decl.Indirect.Value
+		req.AllowPrerelease = decl.AllowPrerelease != nil && decl.AllowPrerelease.Value
+		if decl.Exclude != nil {
+			for _, elem := range decl.Exclude.Elements {
+				version := elem.(*parse.StringLit).Value
+				if !semver.IsValid(version) || semver.Canonical(version) != version {
+					return nil, fmt.Errorf(
+						"project: dependency %q: exclude: %q is not a full version", decl.ID.Value, version)
+				}
+				req.Exclude = append(req.Exclude, version)
+			}
 		}
+		out[dep] = req
 	}
 	return out, nil
 }
@@ -43,7 +43,42 @@ func ProjectLockFromImportsWithSchemaRoots(
 	replace map[Dependency]string,
 	schemaRoots []goschema.ModuleRoot,
 ) (*ProjectLock, error) {
-	w := &projectLockWalker{
+	w := newProjectLockWalker(selection, resolver, replace, schemaRoots)
+	if err := w.walkProject(rootFS); err != nil {
+		return nil, err
+	}
+	return w.projectLock, nil
+}
+
+// ImportChains walks the project rooted at rootFS as
+// ProjectLockFromImportsWithSchemaRoots does and returns, for each project
+// id it would lock, the first import chain that reaches it. A chain starts
+// with the .ub file under the root that holds the first import, followed
+// by each remote package on the way as `<package>@<version>`, ending with
+// the package the project provides. A replaced package along the way has
+// no version.
+func ImportChains(
+	rootFS fs.FS,
+	selection map[Dependency]string,
+	resolver resolve.Resolver,
+	replace map[Dependency]string,
+	schemaRoots []goschema.ModuleRoot,
+) (map[string][]string, error) {
+	w := newProjectLockWalker(selection, resolver, replace, schemaRoots)
+	w.chains = map[string][]string{}
+	if err := w.walkProject(rootFS); err != nil {
+		return nil, err
+	}
+	return w.chains, nil
+}
+
+func newProjectLockWalker(
+	selection map[Dependency]string,
+	resolver resolve.Resolver,
+	replace map[Dependency]string,
+	schemaRoots []goschema.ModuleRoot,
+) *projectLockWalker {
+	return &projectLockWalker{
 		resolver:    resolver,
 		selection:   selection,
 		replace:     replace,
@@ -52,6 +87,9 @@ func ProjectLockFromImportsWithSchemaRoots(
 		inProgress:  map[string]bool{},
 		walked:      map[string]bool{},
 	}
+}
+
+func (w *projectLockWalker) walkProject(rootFS fs.FS) error {
 	err := fs.WalkDir(rootFS, ".", func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
@@ -78,12 +116,12 @@ func ProjectLockFromImportsWithSchemaRoots(
 		return w.projectLockFileImports(rootFS, path)
 	})
 	if err != nil {
-		return nil, err
+		return err
 	}
 	if err := validateProjectLockDeps(w.projectLock); err != nil {
-		return nil, fmt.Errorf("project-lock: %w", err)
+		return fmt.Errorf("project-lock: %w", err)
 	}
-	return w.projectLock, nil
+	return nil
 }
 
 func (w *projectLockWalker) projectLockFileImports(rootFS fs.FS, path string) error {
@@ -95,6 +133,7 @@ func (w *projectLockWalker) projectLockFileImports(rootFS fs.FS, path string) er
 	if err != nil {
 		return err
 	}
+	w.trail = []string{path}
 	for _, ref := range refs {
 		if local, ok := ref.Ref.(*resolve.LocalImport); ok {
 			if ref.Kind == resolve.SyntaxDependencyLibraryConfig {
@@ -121,6 +160,30 @@ type projectLockWalker struct {
 	projectLock *ProjectLock
 	inProgress  map[string]bool
 	walked      map[string]bool
+	// trail is the import chain to the package being walked, and chains,
+	// when not nil, collects the first trail to reach each locked project.
+	trail  []string
+	chains map[string][]string
+}
+
+// recordChain records the chain to project through the package named
+// by step, unless another chain reached project first.
+func (w *projectLockWalker) recordChain(project ProjectID, step string) {
+	if w.chains == nil {
+		return
+	}
+	id := project.String()
+	if _, ok := w.chains[id]; ok {
+		return
+	}
+	w.chains[id] = append(slices.Clone(w.trail), step)
+}
+
+// walkBodiesFrom walks src's bodies with step on the trail.
+func (w *projectLockWalker) walkBodiesFrom(step string, src *resolve.Source) error {
+	w.trail = append(w.trail, step)
+	defer func() { w.trail = w.trail[:len(w.trail)-1] }()
+	return w.walkBodies(src)
 }
 
 type projectLockImportRef struct {
@@ -216,6 +279,7 @@ func (w *projectLockWalker) walkRemote(
 		if err := w.checkRemoteSchemaDependency(pkg, owner, version, src, classification); err != nil {
 			return err
 		}
+		w.recordChain(owner.Project, packageKey)
 		w.walked[packageKey] = true
 		return nil
 	}
@@ -247,8 +311,9 @@ func (w *projectLockWalker) walkRemote(
 		}
 		w.projectLock.Deps[projectID] = entry
 	}
+	w.recordChain(owner.Project, packageKey)
 	if kind == ProjectLockKindUB {
-		if err := w.walkBodies(src); err != nil {
+		if err := w.walkBodiesFrom(packageKey, src); err != nil {
 			return err
 		}
 	}
@@ -359,7 +424,7 @@ func (w *projectLockWalker) projectLockEntry(
 		return nil, err
 	}
 	entry.Commit = projectSrc.Commit
-	entry.Hash, err = HashUBProject(projectSrc.FS)
+	entry.Hash, err = resolve.HashUBProject(projectSrc.FS)
 	if err != nil {
 		return nil, err
 	}
@@ -474,7 +539,7 @@ func (w *projectLockWalker) walkReplaced(
 	case resolve.SourceFactory:
 		return fmt.Errorf("a factory cannot be imported")
 	case resolve.SourceUBLibrary:
-		return w.walkBodies(src)
+		return w.walkBodiesFrom(pkg.String(), src)
 	case resolve.SourceGoLibrary:
 		return validateGoLibrarySource(src)
 	default:
@@ -515,6 +515,35 @@ func TestProjectLockFromImportsRecursesThroughRemoteUB(t *testing.T) {
 	}, projectLock.Deps)
 }
 
+func TestImportChainsFollowRemoteUB(t *testing.T) {
+	root := mapFS(map[string]string{
+		"factory.ub": projectLockWalkFixture(t, "project-lock-from-imports-recurses-through-remote-ub-1"),
+	})
+	r := &fakeResolver{sources: map[string]*resolve.Source{
+		srcKey("github.com/scratch/repo", "ub/helloer", "v0.1.0"): ubSrc(
+			"c2", "h2", map[string]string{
+				"library.ub": projectLockWalkFixture(t, "project-lock-from-imports-recurses-through-remote-ub-2"),
+			}),
+		srcKey("github.com/cloudboss/unobin", "pkg/libraries/local", "v0.1.0"): goSrc("c3"),
+	}}
+	sel := map[Dependency]string{
+		{URL: "github.com/scratch/repo", Subdir: "ub/helloer"}:              "v0.1.0",
+		{URL: "github.com/cloudboss/unobin", Subdir: "pkg/libraries/local"}: "v0.1.0",
+	}
+	chains, err := ImportChains(root, sel, r, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"github.com/scratch/repo//ub/helloer": {
+			"factory.ub", "github.com/scratch/repo//ub/helloer@v0.1.0",
+		},
+		"github.com/cloudboss/unobin//pkg/libraries/local": {
+			"factory.ub",
+			"github.com/scratch/repo//ub/helloer@v0.1.0",
+			"github.com/cloudboss/unobin//pkg/libraries/local@v0.1.0",
+		},
+	}, chains)
+}
+
 func TestProjectLockFromImportsRecursesThroughSourceDeclaredRemoteUB(t *testing.T) {
 	root := mapFS(map[string]string{
 		"factory.ub": projectLockWalkFixture(t, "source-remote-root"),
@@ -142,3 +142,62 @@ func TestEncodeProjectWritesToolchainLine(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, m.UnobinVersion, back.UnobinVersion)
 }
+
+func TestSetRequireKeepsConstraints(t *testing.T) {
+	m, err := ReadProject(projectFixtureFS(t, "testdata/ub/project/valid/constraints.ub"))
+	require.NoError(t, err)
+	bounded := Dependency{URL: "github.com/x/bounded"}
+	m.SetRequire(bounded, "v1.5.0", true)
+	require.Equal(t, Requirement{Version: "v1.5.0", Indirect: true, Ceiling: "v2"},
+		m.Requires[bounded])
+	require.Contains(t, string(EncodeProject(m)), "version: '>= v1.5.0, < v2'\n")
+}
+
+func TestParseConstraintOperators(t *testing.T) {
+	cases := []struct {
+		text string
+		want Requirement
+		err  string
+	}{
+		{text: "v1.2.0", want: Requirement{Version: "v1.2.0"}},
+		{text: ">= v1.2.0", want: Requirement{Version: "v1.2.0"}},
+		{text: ">= v1.2.0, < v2", want: Requirement{Version: "v1.2.0", Ceiling: "v2"}},
+		{
+			text: ">=v1.2.0,<=v1.4",
+			want: Requirement{Version: "v1.2.0", Ceiling: "v1.4", CeilingInclusive: true},
+		},
+		{
+			text: "= v1.2.0",
+			want: Requirement{Version: "v1.2.0", Ceiling: "v1.2.0", CeilingInclusive: true},
+		},
+		{
+			text: "=v1.2.0",
+			want: Requirement{Version: "v1.2.0", Ceiling: "v1.2.0", CeilingInclusive: true},
+		},
+		{
+			text: "> v1.2.0",
+			err: `project: dependency "x": "> v1.2.0" uses >, which is not supported; ` +
+				`use >=, <, <=, or =`,
+		},
+		{
+			text: "= v1.2.0, <= v1.3",
+			err:  `project: dependency "x": "= v1.2.0, <= v1.3" has more than one upper bound`,
+		},
+		{
+			text: ">= v1.0.0, = v1.2.0",
+			err:  `project: dependency "x": ">= v1.0.0, = v1.2.0" has = with another bound`,
+		},
+	}
+	for _, tt := range cases {
+		got, err := parseConstraint("x", tt.text)
+		if tt.err != "" {
+			require.EqualError(t, err, tt.err, tt.text)
+			continue
+		}
+		require.NoError(t, err, tt.text)
+		require.Equal(t, tt.want, got, tt.text)
+		if tt.want.Ceiling == tt.want.Version {
+			require.Equal(t, "= "+tt.want.Version, got.Constraint(), tt.text)
+		}
+	}
+}
@@ -1,6 +1,11 @@
 package deps
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
 
 // Fetcher fetches a dependency's project at a selected version, for the
 // version walk. It returns nil when the dependency declares no project:
@@ -10,6 +15,13 @@ type Fetcher interface {
 	Fetch(dep Dependency, version string) (*Project, error)
 }
 
+// VersionLister is implemented by a Fetcher that can list a dependency's
+// available versions. Resolve uses it to move a selection past a version
+// a requirement excludes or a pre-release a requirement does not take.
+type VersionLister interface {
+	Versions(dep Dependency) ([]string, error)
+}
+
 // Resolve runs minimal version selection over the dependency graph rooted
 // at a project. It selects the highest floor for each dependency, fetches
 // it at that version to read its own requirements, and repeats until the
@@ -18,34 +30,102 @@ type Fetcher interface {
 // the walk terminates at any dependency with no project. The result maps
 // each dependency to its selected version -- project-lock, keyed per imported
 // library, is built separately by the import walk.
+//
+// A selected version some requirement excludes, or a pre-release some
+// requirement does not take, is raised to the next version every
+// requirement admits when fetch is a VersionLister. A selection above an
+// upper bound, or one that cannot be raised, is a *ConflictError naming
+// the chain behind each requirement on the dependency.
 func Resolve(root *Project, fetch Fetcher) (map[Dependency]string, error) {
 	sel := NewSelection()
 	var queue []Dependency
-	enqueue := func(reqs map[Dependency]string) {
-		for dep, floor := range reqs {
-			if sel.Add(dep, floor) {
+	// reachedBy holds the chain whose requirement set each selection, so
+	// requirements read from a dependency extend the chain that led to it.
+	reachedBy := map[Dependency]Chain{}
+	enqueue := func(reqs map[Dependency]Requirement, chain Chain) {
+		for _, dep := range sortedDependencies(reqs) {
+			if sel.Require(dep, reqs[dep], chain) {
+				reachedBy[dep] = chain
 				queue = append(queue, dep)
 			}
 		}
 	}
-	enqueue(root.RequireVersions())
+	enqueue(root.Requires, nil)
 
 	fetchedAt := map[Dependency]string{}
-	for len(queue) > 0 {
-		dep := queue[0]
-		queue = queue[1:]
-		version := sel.Version(dep)
-		if fetchedAt[dep] == version {
-			continue // already fetched at the current selection
-		}
-		project, err := fetch.Fetch(dep, version)
+	for {
+		for len(queue) > 0 {
+			dep := queue[0]
+			queue = queue[1:]
+			version := sel.Version(dep)
+			if fetchedAt[dep] == version {
+				continue // already fetched at the current selection
+			}
+			project, err := fetch.Fetch(dep, version)
+			if err != nil {
+				return nil, fmt.Errorf("resolve %s@%s: %w", dep, version, err)
+			}
+			fetchedAt[dep] = version
+			if project != nil {
+				chain := append(slices.Clone(reachedBy[dep]), ChainLink{Dep: dep, Version: version})
+				enqueue(project.Requires, chain)
+			}
+		}
+		raised, err := raiseConflicts(sel, fetch, root.Replace)
 		if err != nil {
-			return nil, fmt.Errorf("resolve %s@%s: %w", dep, version, err)
+			return nil, err
 		}
-		fetchedAt[dep] = version
-		if project != nil {
-			enqueue(project.RequireVersions())
+		if len(raised) == 0 {
+			break
 		}
+		queue = append(queue, raised...)
 	}
 	return sel.Chosen(), nil
 }
+
+// raiseConflicts checks every selected version against the requirements
+// on it, raising the ones an exclusion or pre-release policy rules out,
+// and returns the dependencies it raised. A replaced dependency is read
+// from its local path at no version, so nothing constrains it.
+func raiseConflicts(
+	sel *Selection, fetch Fetcher, replace map[Dependency]string,
+) ([]Dependency, error) {
+	var raised []Dependency
+	for _, dep := range sortedDependencies(sel.chosen) {
+		if _, ok := replace[dep]; ok {
+			continue
+		}
+		err := sel.Check(dep)
+		var conflict *ConflictError
+		if !errors.As(err, &conflict) {
+			continue
+		}
+		lister, ok := fetch.(VersionLister)
+		if !ok || !conflict.raisable {
+			return nil, err
+		}
+		vs, listErr := lister.Versions(dep)
+		if listErr != nil {
+			return nil, fmt.Errorf("resolve %s: %w", dep, listErr)
+		}
+		next := sel.Next(dep, vs)
+		if next == "" {
+			conflict.Reason += ", and no later version satisfies every requirement"
+			return nil, conflict
+		}
+		sel.Add(dep, next)
+		raised = append(raised, dep)
+	}
+	return raised, nil
+}
+
+func sortedDependencies[V any](m map[Dependency]V) []Dependency {
+	deps := make([]Dependency, 0, len(m))
+	for dep := range m {
+		deps = append(deps, dep)
+	}
+	slices.SortFunc(deps, func(a, b Dependency) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	return deps
+}
@@ -2,6 +2,7 @@ package deps
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -314,3 +315,109 @@ func TestResolveFetchError(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "x/a@v1.0.0")
 }
+
+// fakeVersionLister is a fakeFetcher that lists a dependency's versions
+// from the keys of its canned projects.
+type fakeVersionLister struct{ *fakeFetcher }
+
+func (f fakeVersionLister) Versions(d Dependency) ([]string, error) {
+	var out []string
+	for key := range f.projects {
+		if version, ok := strings.CutPrefix(key, d.String()+"@"); ok {
+			out = append(out, version)
+		}
+	}
+	return out, nil
+}
+
+func TestResolveRaisesPastExcludedVersion(t *testing.T) {
+	root := &Project{Requires: map[Dependency]Requirement{
+		dep("x/a"): {Version: "v1.0.0"},
+		dep("x/c"): {Version: "v1.2.0", Exclude: []string{"v1.4.1", "v1.4.2"}},
+	}}
+	f := fetcherFor(map[string]map[string]string{
+		"x/a@v1.0.0": {"x/c": "v1.4.1"},
+		"x/c@v1.2.0": nil,
+		"x/c@v1.4.1": nil,
+		"x/c@v1.4.2": nil,
+		"x/c@v1.5.0": {"x/d": "v1.0.0"},
+		"x/d@v1.0.0": nil,
+	})
+	got, err := Resolve(root, fakeVersionLister{f})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"x/a": "v1.0.0", "x/c": "v1.5.0", "x/d": "v1.0.0",
+	}, selected(got))
+
+	_, err = Resolve(root, f)
+	require.EqualError(t, err, "conflicting requirements on x/c: v1.4.1 is excluded\n"+
+		"\tproject.ub requires v1.2.0, excluding v1.4.1, v1.4.2\n"+
+		"\tproject.ub -> x/a@v1.0.0 requires v1.4.1")
+}
+
+func TestResolveRaisesPrereleaseToRelease(t *testing.T) {
+	universe := map[string]map[string]string{
+		"x/a@v1.0.0":      {"x/c": "v2.0.0-rc.1"},
+		"x/c@v1.0.0":      nil,
+		"x/c@v2.0.0-rc.1": nil,
+		"x/c@v2.0.0":      nil,
+	}
+	root := &Project{Requires: toReqs(map[string]string{"x/a": "v1.0.0", "x/c": "v1.0.0"})}
+	got, err := Resolve(root, fakeVersionLister{fetcherFor(universe)})
+	require.NoError(t, err)
+	assert.Equal(t, "v2.0.0", selected(got)["x/c"])
+
+	// A requirement that allows pre-releases settles on the pre-release.
+	root.Requires[dep("x/c")] = Requirement{Version: "v1.0.0", AllowPrerelease: true}
+	got, err = Resolve(root, fakeVersionLister{fetcherFor(universe)})
+	require.NoError(t, err)
+	assert.Equal(t, "v2.0.0-rc.1", selected(got)["x/c"])
+
+	// With no release to move to, the pre-release is a conflict.
+	delete(universe, "x/c@v2.0.0")
+	root.Requires[dep("x/c")] = Requirement{Version: "v1.0.0"}
+	_, err = Resolve(root, fakeVersionLister{fetcherFor(universe)})
+	require.EqualError(t, err, "conflicting requirements on x/c: v2.0.0-rc.1 is a pre-release, "+
+		"and no later version satisfies every requirement\n"+
+		"\tproject.ub requires v1.0.0\n"+
+		"\tproject.ub -> x/a@v1.0.0 requires v2.0.0-rc.1")
+}
+
+func TestResolveCeilingConflictNamesChains(t *testing.T) {
+	root := &Project{Requires: map[Dependency]Requirement{
+		dep("x/a"): {Version: "v1.0.0"},
+		dep("x/c"): {Version: "v1.2.0", Ceiling: "v2"},
+	}}
+	f := fetcherFor(map[string]map[string]string{
+		"x/a@v1.0.0": {"x/b": "v1.3.0"},
+		"x/b@v1.3.0": {"x/c": "v2.1.0"},
+		"x/c@v1.2.0": nil,
+		"x/c@v2.1.0": nil,
+	})
+	_, err := Resolve(root, fakeVersionLister{f})
+	var conflict *ConflictError
+	require.ErrorAs(t, err, &conflict)
+	assert.Equal(t, "v2.1.0", conflict.Version)
+	require.EqualError(t, err, "conflicting requirements on x/c: v2.1.0 is not below v2\n"+
+		"\tproject.ub requires >= v1.2.0, < v2\n"+
+		"\tproject.ub -> x/a@v1.0.0 -> x/b@v1.3.0 requires v2.1.0")
+}
+
+func TestResolveIgnoresConstraintsFromSupersededVersions(t *testing.T) {
+	// x/b@v1.0.0 caps x/c below v2, but x/e raises x/b to v2.0.0, which
+	// does not; only the selected version's requirements constrain.
+	f := fetcherFor(map[string]map[string]string{
+		"x/a@v1.0.0": {"x/b": "v1.0.0"},
+		"x/e@v1.0.0": {"x/b": "v2.0.0", "x/c": "v2.0.0"},
+		"x/b@v2.0.0": nil,
+		"x/c@v2.0.0": nil,
+	})
+	f.projects["x/b@v1.0.0"] = &Project{Requires: map[Dependency]Requirement{
+		dep("x/c"): {Version: "v1.0.0", Ceiling: "v2"},
+	}}
+	f.projects["x/c@v1.0.0"] = &Project{}
+	root := &Project{Requires: toReqs(map[string]string{"x/a": "v1.0.0", "x/e": "v1.0.0"})}
+	got, err := Resolve(root, f)
+	require.NoError(t, err)
+	assert.Equal(t, "v2.0.0", selected(got)["x/c"])
+}
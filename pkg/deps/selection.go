@@ -1,7 +1,10 @@
 package deps
 
 import (
+	"fmt"
 	"maps"
+	"slices"
+	"strings"
 
 	"golang.org/x/mod/semver"
 )
@@ -13,13 +16,45 @@ import (
 // dependency graph; the highest floor per dependency is the selected
 // version. Floors must be valid semver, which the project reader
 // guarantees.
+//
+// Require records a whole Requirement along with the chain that declared
+// it, so the selection can also be checked against each requirement's
+// upper bound, exclusions, and pre-release policy.
 type Selection struct {
-	chosen map[Dependency]string
+	chosen   map[Dependency]string
+	required map[Dependency][]chainedRequirement
+}
+
+// Chain is the path from the root project to the project that declares
+// a requirement: each dependency at the version the walk read it. An
+// empty Chain is the root project itself.
+type Chain []ChainLink
+
+// ChainLink is one project on a Chain.
+type ChainLink struct {
+	Dep     Dependency
+	Version string
+}
+
+func (c Chain) String() string {
+	parts := []string{ProjectFileName}
+	for _, link := range c {
+		parts = append(parts, link.Dep.String()+"@"+link.Version)
+	}
+	return strings.Join(parts, " -> ")
+}
+
+type chainedRequirement struct {
+	req   Requirement
+	chain Chain
 }
 
 // NewSelection returns an empty Selection.
 func NewSelection() *Selection {
-	return &Selection{chosen: map[Dependency]string{}}
+	return &Selection{
+		chosen:   map[Dependency]string{},
+		required: map[Dependency][]chainedRequirement{},
+	}
 }
 
 // Add records a required floor for dep and reports whether it raised the
@@ -36,6 +71,14 @@ func (s *Selection) Add(dep Dependency, floor string) bool {
 	return true
 }
 
+// Require records req, declared by the project at the end of chain, and
+// adds its floor. It reports whether the floor raised the selection, as
+// Add does.
+func (s *Selection) Require(dep Dependency, req Requirement, chain Chain) bool {
+	s.required[dep] = append(s.required[dep], chainedRequirement{req: req, chain: chain})
+	return s.Add(dep, req.Version)
+}
+
 // Version returns the selected version for dep, or "" if dep has not been
 // added.
 func (s *Selection) Version(dep Dependency) string {
@@ -47,3 +90,84 @@ func (s *Selection) Version(dep Dependency) string {
 func (s *Selection) Chosen() map[Dependency]string {
 	return maps.Clone(s.chosen)
 }
+
+// Check returns a *ConflictError when a requirement on dep rules out its
+// selected version. Only requirements declared by the root project or by
+// a dependency at its selected version count: a version the selection
+// moved past no longer constrains the graph.
+func (s *Selection) Check(dep Dependency) error {
+	version := s.chosen[dep]
+	active := s.active(dep)
+	for _, r := range active {
+		if reason := r.req.violation(version); reason != "" {
+			return &ConflictError{
+				Dep:          dep,
+				Version:      version,
+				Reason:       reason,
+				requirements: active,
+				raisable:     r.req.belowCeiling(version),
+			}
+		}
+	}
+	return nil
+}
+
+// Next returns the lowest version in vs above dep's selection that every
+// requirement on dep admits, or "" when there is none.
+func (s *Selection) Next(dep Dependency, vs []string) string {
+	active := s.active(dep)
+	var candidates []string
+	for _, v := range vs {
+		if semver.Compare(v, s.chosen[dep]) <= 0 {
+			continue
+		}
+		if !slices.ContainsFunc(active, func(r chainedRequirement) bool { return !r.req.Admits(v) }) {
+			candidates = append(candidates, v)
+		}
+	}
+	semver.Sort(candidates)
+	if len(candidates) == 0 {
+		return ""
+	}
+	return candidates[0]
+}
+
+func (s *Selection) active(dep Dependency) []chainedRequirement {
+	var out []chainedRequirement
+	for _, r := range s.required[dep] {
+		if len(r.chain) > 0 {
+			last := r.chain[len(r.chain)-1]
+			if s.chosen[last.Dep] != last.Version {
+				continue
+			}
+		}
+		out = append(out, r)
+	}
+	slices.SortFunc(out, func(a, b chainedRequirement) int {
+		return strings.Compare(a.chain.String(), b.chain.String())
+	})
+	return out
+}
+
+// ConflictError reports a dependency whose selected version some
+// requirement rules out, with every requirement on it and the chain that
+// declared each.
+type ConflictError struct {
+	Dep     Dependency
+	Version string
+	Reason  string
+
+	requirements []chainedRequirement
+	// raisable is set when a later version might satisfy every
+	// requirement: the selection is below the violated upper bound.
+	raisable bool
+}
+
+func (e *ConflictError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "conflicting requirements on %s: %s", e.Dep, e.Reason)
+	for _, r := range e.requirements {
+		fmt.Fprintf(&b, "\n\t%s requires %s", r.chain, r.req)
+	}
+	return b.String()
+}
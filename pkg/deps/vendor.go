@@ -0,0 +1,212 @@
+package deps
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudboss/unobin/pkg/projectmarker"
+	"github.com/cloudboss/unobin/pkg/resolve"
+)
+
+// VendorDirName is the directory under a project root that `unobin deps
+// vendor` fills with the sources project-lock.ub selects.
+const VendorDirName = "vendor"
+
+// VendorResult counts what Vendor copied.
+type VendorResult struct {
+	Projects int
+	Files    int
+}
+
+// Vendor copies the source of every dependency projectLock selects into
+// the vendor directory under root, each at its locked commit and laid
+// out as <repository>/<subdir>, and writes projectLock beside them as
+// the record of what was copied. Hidden files and nested projects are
+// left out, as the content hash leaves them out. An existing vendor
+// directory is replaced only when it holds such a record, so a directory
+// Vendor did not write is never removed.
+func Vendor(root string, projectLock *ProjectLock, resolver resolve.Resolver) (*VendorResult, error) {
+	dir := filepath.Join(root, VendorDirName)
+	if _, err := os.Stat(dir); err == nil {
+		if _, err := os.Stat(filepath.Join(dir, ProjectLockFileName)); err != nil {
+			return nil, fmt.Errorf(
+				"%s exists but was not written by `unobin deps vendor`; move it aside first", dir)
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	tmp := filepath.Join(root, "."+VendorDirName+".tmp")
+	if err := os.RemoveAll(tmp); err != nil {
+		return nil, err
+	}
+	result, err := vendorInto(tmp, projectLock, resolver)
+	if err != nil {
+		_ = os.RemoveAll(tmp)
+		return nil, err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp, dir); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func vendorInto(dir string, projectLock *ProjectLock, resolver resolve.Resolver) (*VendorResult, error) {
+	result := &VendorResult{}
+	for _, id := range projectLock.SortedIDs() {
+		entry := projectLock.Deps[id]
+		url, subdir, err := resolve.SplitRepoSubdir(id)
+		if err != nil {
+			return nil, fmt.Errorf("project-lock id %q: %w", id, err)
+		}
+		ref := &resolve.RemoteImport{URL: url, Subdir: subdir, Version: entry.Commit}
+		if entry.Kind == ProjectLockKindUB {
+			ref.Hash = entry.Hash
+		}
+		src, err := resolver.Resolve(ref)
+		if err != nil {
+			return nil, fmt.Errorf("vendor %s: %w", id, err)
+		}
+		target := filepath.Join(resolve.VendorRepoDir(dir, url), filepath.FromSlash(subdir))
+		files, err := copyProject(src.FS, target)
+		if err != nil {
+			return nil, fmt.Errorf("vendor %s: %w", id, err)
+		}
+		result.Projects++
+		result.Files += files
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	if err := WriteProjectLock(filepath.Join(dir, ProjectLockFileName), projectLock); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// copyProject copies the project rooted at fsys into dir and returns the
+// number of files copied.
+func copyProject(fsys fs.FS, dir string) (int, error) {
+	if fsys == nil {
+		return 0, fmt.Errorf("missing filesystem")
+	}
+	files := 0
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type()&fs.ModeSymlink != 0 {
+			return fmt.Errorf("%s: symlink is not supported", p)
+		}
+		if p != "." && hiddenPath(p) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		target := filepath.Join(dir, filepath.FromSlash(p))
+		if d.IsDir() {
+			if p != "." {
+				marker, err := projectmarker.Classify(fsys, p)
+				if err != nil {
+					return err
+				}
+				if marker.Kind != projectmarker.None {
+					return fs.SkipDir
+				}
+			}
+			return os.MkdirAll(target, 0o755)
+		}
+		if err := copyFile(fsys, p, target); err != nil {
+			return err
+		}
+		files++
+		return nil
+	})
+	return files, err
+}
+
+func copyFile(fsys fs.FS, name, target string) error {
+	in, err := fsys.Open(name)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	perm := os.FileMode(0o644)
+	if info.Mode().Perm()&0o111 != 0 {
+		perm = 0o755
+	}
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// ReadVendorLock reads the project-lock.ub a vendor directory was written
+// from. It returns nil when root has no vendor directory written by
+// Vendor.
+func ReadVendorLock(root string) (*ProjectLock, error) {
+	projectLock, err := ReadProjectLock(os.DirFS(filepath.Join(root, VendorDirName)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s/%s: %w", VendorDirName, ProjectLockFileName, err)
+	}
+	return projectLock, nil
+}
+
+// VendorMismatches describes each way the dependencies a vendor directory
+// was written from differ from those projectLock selects. A nil
+// projectLock selects nothing.
+func VendorMismatches(vendored, projectLock *ProjectLock) []string {
+	if projectLock == nil {
+		projectLock = NewProjectLock()
+	}
+	var mismatches []string
+	for _, id := range projectLock.SortedIDs() {
+		if _, ok := vendored.Deps[id]; !ok {
+			mismatches = append(mismatches, fmt.Sprintf("%s is not vendored", id))
+		}
+	}
+	for _, id := range vendored.SortedIDs() {
+		have := vendored.Deps[id]
+		want, ok := projectLock.Deps[id]
+		switch {
+		case !ok:
+			mismatches = append(mismatches,
+				fmt.Sprintf("%s is vendored but not selected in %s", id, ProjectLockFileName))
+		case *have != *want:
+			mismatches = append(mismatches, fmt.Sprintf(
+				"%s is vendored at %s (%s) but %s selects %s (%s)",
+				id, have.Version, have.Commit, ProjectLockFileName, want.Version, want.Commit))
+		}
+	}
+	return mismatches
+}
+
+func hiddenPath(p string) bool {
+	for part := range strings.SplitSeq(p, "/") {
+		if strings.HasPrefix(part, ".") {
+			return true
+		}
+	}
+	return false
+}
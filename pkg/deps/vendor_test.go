@@ -0,0 +1,112 @@
+package deps
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudboss/unobin/internal/ubtest"
+	"github.com/cloudboss/unobin/pkg/resolve"
+)
+
+func vendorResolver(t *testing.T) (*fakeResolver, string) {
+	t.Helper()
+	emptyProject := ubtest.ReadValidFixture(t, "testdata/ub/vendor", "empty-project")
+	scratch := mapFS(map[string]string{
+		ProjectFileName:             emptyProject,
+		"ub/helloer/library.ub":     ubtest.ReadValidFixture(t, "testdata/ub/vendor", "helloer-library"),
+		".git/config":               "[core]\n",
+		"nested/" + ProjectFileName: emptyProject,
+	})
+	golib := mapFS(map[string]string{
+		"go.mod": "module github.com/x/golib\n",
+		"lib.go": "package lib\n",
+	})
+	return &fakeResolver{sources: map[string]*resolve.Source{
+		srcKey("github.com/scratch/repo", "", "c1"): {Commit: "c1", FS: scratch},
+		srcKey("github.com/x/golib", "", "go1"):     {Commit: "go1", FS: golib},
+	}}, hashProject(t, scratch)
+}
+
+func TestVendorCopiesSelectedProjects(t *testing.T) {
+	resolver, hash := vendorResolver(t)
+	projectLock := verifyProjectLock(hash)
+	root := t.TempDir()
+
+	result, err := Vendor(root, projectLock, resolver)
+	require.NoError(t, err)
+	require.Equal(t, &VendorResult{Projects: 2, Files: 4}, result)
+
+	dir := filepath.Join(root, VendorDirName)
+	for _, name := range []string{
+		"github.com/scratch/repo/project.ub",
+		"github.com/scratch/repo/ub/helloer/library.ub",
+		"github.com/x/golib/go.mod",
+		"github.com/x/golib/lib.go",
+	} {
+		require.FileExists(t, filepath.Join(dir, filepath.FromSlash(name)))
+	}
+	require.NoDirExists(t, filepath.Join(dir, "github.com/scratch/repo/.git"))
+	require.NoDirExists(t, filepath.Join(dir, "github.com/scratch/repo/nested"))
+	require.NoDirExists(t, filepath.Join(root, "."+VendorDirName+".tmp"))
+
+	vendored, err := ReadVendorLock(root)
+	require.NoError(t, err)
+	require.Equal(t, projectLock, vendored)
+	require.Empty(t, VendorMismatches(vendored, projectLock))
+
+	// A vendor directory Vendor wrote is replaced on the next run.
+	_, err = Vendor(root, projectLock, resolver)
+	require.NoError(t, err)
+}
+
+func TestVendorKeepsForeignDirectory(t *testing.T) {
+	resolver, hash := vendorResolver(t)
+	root := t.TempDir()
+	foreign := filepath.Join(root, VendorDirName, "keep.txt")
+	require.NoError(t, os.MkdirAll(filepath.Dir(foreign), 0o755))
+	require.NoError(t, os.WriteFile(foreign, []byte("keep\n"), 0o644))
+
+	_, err := Vendor(root, verifyProjectLock(hash), resolver)
+	require.ErrorContains(t, err, "was not written by `unobin deps vendor`")
+	require.FileExists(t, foreign)
+}
+
+func TestVendorResolveError(t *testing.T) {
+	root := t.TempDir()
+	_, err := Vendor(root, verifyProjectLock("sha256:x"), &fakeResolver{})
+	require.EqualError(t, err,
+		"vendor github.com/scratch/repo: no source for github.com/scratch/repo//@c1")
+	require.NoDirExists(t, filepath.Join(root, VendorDirName))
+	require.NoDirExists(t, filepath.Join(root, "."+VendorDirName+".tmp"))
+}
+
+func TestReadVendorLockWithoutVendor(t *testing.T) {
+	vendored, err := ReadVendorLock(t.TempDir())
+	require.NoError(t, err)
+	require.Nil(t, vendored)
+}
+
+func TestVendorMismatches(t *testing.T) {
+	selected := verifyProjectLock("sha256:a")
+	moved := verifyProjectLock("sha256:a")
+	moved.Deps["github.com/scratch/repo"] = &ProjectLockDep{
+		Kind: ProjectLockKindUB, Version: "v0.9.0", Commit: "c2", Hash: "sha256:b",
+	}
+	delete(moved.Deps, "github.com/x/golib")
+	moved.Deps["github.com/x/extra"] = &ProjectLockDep{
+		Kind: ProjectLockKindGo, Version: "v1.0.0", Commit: "e1",
+	}
+
+	require.Equal(t, []string{
+		"github.com/x/golib is not vendored",
+		"github.com/scratch/repo is vendored at v0.9.0 (c2) but project-lock.ub selects v0.8.0 (c1)",
+		"github.com/x/extra is vendored but not selected in project-lock.ub",
+	}, VendorMismatches(moved, selected))
+	require.Equal(t, []string{
+		"github.com/scratch/repo is vendored but not selected in project-lock.ub",
+		"github.com/x/golib is vendored but not selected in project-lock.ub",
+	}, VendorMismatches(selected, nil))
+}
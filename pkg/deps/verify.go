@@ -42,7 +42,7 @@ func Verify(projectLock *ProjectLock, resolver resolve.Resolver) (*VerifyResult,
 		if err := requireUBProjectMarker(src.FS); err != nil {
 			return nil, fmt.Errorf("verify %s: %w", id, err)
 		}
-		hash, err := HashUBProject(src.FS)
+		hash, err := resolve.HashUBProject(src.FS)
 		if err != nil {
 			return nil, fmt.Errorf("verify %s: %w", id, err)
 		}
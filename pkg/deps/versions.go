@@ -2,6 +2,7 @@ package deps
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"golang.org/x/mod/semver"
@@ -51,28 +52,36 @@ func Highest(vs []string) string {
 }
 
 // ResolveVersion turns a `deps get` query into a concrete version of dep,
-// chosen among the repository's tags. An empty query or "latest" picks the
-// highest available version; a full version (vX.Y.Z) is used as-is once
-// confirmed present; a partial version (v1 or v1.2) picks the highest
-// available version under that prefix.
-func ResolveVersion(dep Dependency, query string, tags []string) (string, error) {
+// chosen among the repository's tags and within req, the requirement the
+// project already declares for dep (zero when it has none). An empty
+// query or "latest" picks the highest release req admits, or the highest
+// pre-release when there is no such release; a full version (vX.Y.Z) is
+// used as-is once confirmed present and not ruled out by req's upper
+// bound or exclusions; a partial version (v1 or v1.2) picks as latest
+// does among the versions under that prefix.
+func ResolveVersion(dep Dependency, query string, tags []string, req Requirement) (string, error) {
 	available := Versions(dep, tags)
 	if query == "" || query == "latest" {
-		if v := Highest(available); v != "" {
+		if v := latestAdmitted(req, available); v != "" {
 			return v, nil
 		}
+		if len(available) > 0 {
+			return "", fmt.Errorf("%s: no available version satisfies %s", dep, req)
+		}
 		return "", fmt.Errorf("%s: no versions available", dep)
 	}
 	if !semver.IsValid(query) {
 		return "", fmt.Errorf("%s: %q is not a version", dep, query)
 	}
 	if semver.Canonical(query) == query {
-		for _, v := range available {
-			if v == query {
-				return v, nil
-			}
+		if !slices.Contains(available, query) {
+			return "", fmt.Errorf("%s: version %s is not available", dep, query)
+		}
+		if !req.belowCeiling(query) || slices.Contains(req.Exclude, query) {
+			return "", fmt.Errorf("%s: version %s does not satisfy the %s requirement %s",
+				dep, query, ProjectFileName, req)
 		}
-		return "", fmt.Errorf("%s: version %s is not available", dep, query)
+		return query, nil
 	}
 	var matches []string
 	for _, v := range available {
@@ -80,8 +89,19 @@ func ResolveVersion(dep Dependency, query string, tags []string) (string, error)
 			matches = append(matches, v)
 		}
 	}
-	if v := Highest(matches); v != "" {
+	if v := latestAdmitted(req, matches); v != "" {
 		return v, nil
 	}
 	return "", fmt.Errorf("%s: no version matches %s", dep, query)
 }
+
+// latestAdmitted returns the highest version in vs req admits, falling
+// back to the highest pre-release within req's bounds and exclusions
+// when req admits no release.
+func latestAdmitted(req Requirement, vs []string) string {
+	if v := req.Latest(vs, ""); v != "" {
+		return v
+	}
+	req.AllowPrerelease = true
+	return req.Latest(vs, "")
+}
@@ -143,6 +143,7 @@ func TestResolveVersion(t *testing.T) {
 		dep     Dependency
 		query   string
 		tags    []string
+		req     Requirement
 		want    string
 		wantErr bool
 	}{
@@ -173,10 +174,32 @@ func TestResolveVersion(t *testing.T) {
 			tags: []string{"net/v1.0.0", "net/v1.1.0", "v2.0.0"}, want: "v1.0.0"},
 		{name: "subdir dep partial query stays unprefixed", dep: sub, query: "v1",
 			tags: []string{"net/v1.0.0", "net/v1.1.0", "v2.0.0"}, want: "v1.1.0"},
+		{name: "latest prefers a release", dep: root, query: "latest",
+			tags: []string{"v1.0.0", "v1.1.0-rc.1"}, want: "v1.0.0"},
+		{name: "latest falls back to a pre-release", dep: root, query: "",
+			tags: []string{"v1.0.0-rc.1", "v1.0.0-rc.2"}, want: "v1.0.0-rc.2"},
+		{name: "latest takes a pre-release the requirement allows", dep: root, query: "",
+			tags: []string{"v1.0.0", "v1.1.0-rc.1"},
+			req:  Requirement{Version: "v1.0.0", AllowPrerelease: true}, want: "v1.1.0-rc.1"},
+		{name: "latest stays below the upper bound", dep: root, query: "latest",
+			tags: []string{"v1.0.0", "v1.9.0", "v2.0.0"},
+			req:  Requirement{Version: "v1.0.0", Ceiling: "v2"}, want: "v1.9.0"},
+		{name: "latest skips excluded versions", dep: root, query: "v1",
+			tags: []string{"v1.0.0", "v1.4.0", "v1.4.1"},
+			req:  Requirement{Version: "v1.0.0", Exclude: []string{"v1.4.1"}}, want: "v1.4.0"},
+		{name: "exact excluded", dep: root, query: "v1.4.1",
+			tags: []string{"v1.4.1"},
+			req:  Requirement{Version: "v1.0.0", Exclude: []string{"v1.4.1"}}, wantErr: true},
+		{name: "exact above the upper bound", dep: root, query: "v2.0.0",
+			tags: []string{"v1.0.0", "v2.0.0"},
+			req:  Requirement{Version: "v1.0.0", Ceiling: "v2"}, wantErr: true},
+		{name: "nothing within the upper bound", dep: root, query: "",
+			tags: []string{"v2.0.0"},
+			req:  Requirement{Version: "v1.0.0", Ceiling: "v2"}, wantErr: true},
 	}
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
-			got, err := ResolveVersion(c.dep, c.query, c.tags)
+			got, err := ResolveVersion(c.dep, c.query, c.tags, c.req)
 			if c.wantErr {
 				require.Error(t, err)
 				return
@@ -0,0 +1,273 @@
+// Package factorytest runs the cases of a `*_test.ub` file against the
+// factory beside it. Each case plans and applies the factory with the
+// real runtime executor, but every Go library type is replaced by a mock
+// that returns the outputs the case declares, and state lives in memory,
+// so a test run touches no cloud and no state backend.
+//
+// A Go library's functions have no implementation outside a compiled
+// factory, so a factory that calls one cannot be tested this way.
+package factorytest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/cloudboss/unobin/pkg/asset"
+	"github.com/cloudboss/unobin/pkg/lang"
+	"github.com/cloudboss/unobin/pkg/lang/parse"
+	"github.com/cloudboss/unobin/pkg/lang/syntax"
+	"github.com/cloudboss/unobin/pkg/runner"
+	"github.com/cloudboss/unobin/pkg/runtime"
+	"github.com/cloudboss/unobin/pkg/sdk/state"
+	"github.com/cloudboss/unobin/pkg/state/memory"
+)
+
+// Factory is a checked factory a test case runs against. Libraries and
+// LibraryConfigSchemas come from a source check of Body, the way
+// `unobin check` resolves them.
+type Factory struct {
+	Body                 *syntax.FactoryBody
+	Libraries            map[string]*runtime.Library
+	LibraryConfigSchemas map[string]runtime.LibraryConfigSchema
+	AssetCatalog         *asset.Catalog
+	RootAssetSetID       string
+}
+
+// Result is the outcome of one test case. Failures holds one message
+// per assertion that did not hold; a case with none passed.
+type Result struct {
+	Name     string
+	Failures []string
+}
+
+// Passed reports whether every assertion of the case held.
+func (r Result) Passed() bool { return len(r.Failures) == 0 }
+
+// RunFile runs every case of file in order.
+func RunFile(ctx context.Context, factory Factory, file *syntax.TestFile) []Result {
+	results := make([]Result, 0, len(file.Cases))
+	for _, tc := range file.Cases {
+		results = append(results, Run(ctx, factory, tc))
+	}
+	return results
+}
+
+// Run plans tc's inputs against factory, checks the plan, applies it,
+// and checks the outputs. When tc expects an error, the case passes only
+// if one of those stages fails with a message containing it.
+func Run(ctx context.Context, factory Factory, tc syntax.TestCase) Result {
+	r := &run{result: Result{Name: tc.Name.Value}}
+	outputs, err := r.execute(ctx, factory, tc)
+	var expected *parse.StringLit
+	if tc.Expect != nil {
+		expected = tc.Expect.Error
+	}
+	switch {
+	case err != nil && expected == nil:
+		r.failf("%v", err)
+	case err != nil && !strings.Contains(err.Error(), expected.Value):
+		r.failf("error %q does not contain %q", err.Error(), expected.Value)
+	case err == nil && expected != nil:
+		r.failf("expected an error containing %q, but the run succeeded", expected.Value)
+	case err == nil:
+		if tc.Expect != nil {
+			r.checkOutputs(tc.Expect.Outputs, outputs)
+		}
+		for _, key := range r.mocks.unused() {
+			r.failf("mock %q matched no library call", key)
+		}
+	}
+	return r.result
+}
+
+// run collects the failures of one test case.
+type run struct {
+	result Result
+	mocks  *mocks
+}
+
+func (r *run) failf(format string, args ...any) {
+	r.result.Failures = append(r.result.Failures, fmt.Sprintf(format, args...))
+}
+
+// execute plans and applies the factory for tc and returns the
+// factory's outputs. The plan is checked on the way, so its failures
+// are recorded even when apply then fails.
+func (r *run) execute(
+	ctx context.Context,
+	factory Factory,
+	tc syntax.TestCase,
+) (map[string]any, error) {
+	inputs, err := evalObject(tc.Inputs)
+	if err != nil {
+		return nil, fmt.Errorf("inputs: %w", err)
+	}
+	mocked := map[string]map[string]any{}
+	for _, mock := range tc.Mocks {
+		outputs, err := evalObject(mock.Outputs)
+		if err != nil {
+			return nil, fmt.Errorf("mock %q: %w", mock.Target.Value, err)
+		}
+		mocked[mock.Target.Value] = outputs
+	}
+	r.mocks = newMocks(mocked)
+	libs := mockLibraries(factory.Libraries, r.mocks)
+	inputs, err = runner.ValidateInputs(factory.Body, inputs, libs, factory.LibraryConfigSchemas)
+	if err != nil {
+		return nil, err
+	}
+	cache, err := asset.NewCache(factory.AssetCatalog, "")
+	if err != nil {
+		return nil, err
+	}
+	exec := &runtime.Executor{
+		SyntaxSource:   factory.Body,
+		DAG:            runtime.BuildSyntaxDAG(*factory.Body, libs),
+		Libraries:      libs,
+		Inputs:         inputs,
+		AssetCatalog:   factory.AssetCatalog,
+		AssetCache:     cache,
+		RootAssetSetID: factory.RootAssetSetID,
+		Store:          memory.NewStore("test"),
+		Factory:        state.FactoryInfo{Name: "test", Version: "test"},
+	}
+	plan, err := exec.Plan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if tc.Expect != nil {
+		r.checkPlan(tc.Expect, plan)
+	}
+	// The plan goes through its file form, as it does between
+	// `unobin plan` and `unobin apply`.
+	encoded, err := runtime.EncodePlan(plan)
+	if err != nil {
+		return nil, err
+	}
+	planFile, err := runtime.DecodePlan(encoded)
+	if err != nil {
+		return nil, err
+	}
+	result, err := exec.ApplyPlan(ctx, planFile)
+	if err != nil {
+		return nil, err
+	}
+	return result.Outputs, nil
+}
+
+var decisions = []runtime.Decision{
+	runtime.DecisionCreate,
+	runtime.DecisionUpdate,
+	runtime.DecisionReplace,
+	runtime.DecisionDestroy,
+	runtime.DecisionNoOp,
+	runtime.DecisionRerun,
+	runtime.DecisionSkip,
+	runtime.DecisionRead,
+	runtime.DecisionEval,
+}
+
+func (r *run) checkPlan(expect *syntax.TestExpect, plan *runtime.Plan) {
+	steps := make(map[string]*runtime.PlanStep, len(plan.Steps))
+	for _, step := range plan.Steps {
+		steps[step.Address] = step
+	}
+	for _, d := range expect.Decisions {
+		want := runtime.Decision(d.Decision.Value)
+		if !slices.Contains(decisions, want) {
+			r.failf("decision for %s: %q is not a plan decision", d.Address.Value, want)
+			continue
+		}
+		step, ok := steps[d.Address.Value]
+		if !ok {
+			r.failf("decision for %s: the plan has no such step", d.Address.Value)
+			continue
+		}
+		if step.Decision != want {
+			r.failf("decision for %s: got %s, want %s", d.Address.Value, step.Decision, want)
+		}
+	}
+	for _, in := range expect.Inputs {
+		step, ok := steps[in.Address.Value]
+		if !ok {
+			r.failf("inputs for %s: the plan has no such step", in.Address.Value)
+			continue
+		}
+		want, err := evalObject(in.Inputs)
+		if err != nil {
+			r.failf("inputs for %s: %v", in.Address.Value, err)
+			continue
+		}
+		for _, field := range slices.Sorted(maps.Keys(want)) {
+			if _, unresolved := step.UnresolvedInputs[field]; unresolved {
+				r.failf("inputs for %s: %s is not known until apply", in.Address.Value, field)
+				continue
+			}
+			got, ok := step.Inputs[field]
+			if !ok {
+				r.failf("inputs for %s: %s is not set", in.Address.Value, field)
+				continue
+			}
+			if !sameValue(got, want[field]) {
+				r.failf("inputs for %s: %s is %s, want %s",
+					in.Address.Value, field, lang.Render(got), lang.Render(want[field]))
+			}
+		}
+	}
+}
+
+func (r *run) checkOutputs(expect *parse.ObjectLit, outputs map[string]any) {
+	if expect == nil {
+		return
+	}
+	want, err := evalObject(expect)
+	if err != nil {
+		r.failf("outputs: %v", err)
+		return
+	}
+	for _, name := range slices.Sorted(maps.Keys(want)) {
+		got, ok := outputs[name]
+		if !ok {
+			r.failf("output %s is not set", name)
+			continue
+		}
+		if !sameValue(got, want[name]) {
+			r.failf("output %s is %s, want %s", name, lang.Render(got), lang.Render(want[name]))
+		}
+	}
+}
+
+// evalObject evaluates a literal object from a test file. Test values
+// are constants, so nothing is in scope.
+func evalObject(obj *parse.ObjectLit) (map[string]any, error) {
+	if obj == nil {
+		return map[string]any{}, nil
+	}
+	v, err := runtime.Eval(obj, &runtime.EvalContext{})
+	if err != nil {
+		return nil, err
+	}
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("evaluated to %T, not an object", v)
+	}
+	return m, nil
+}
+
+// sameValue compares values by their JSON form, so numbers compare
+// equal whatever Go type evaluation or the plan file gave them.
+func sameValue(a, b any) bool {
+	aj, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	bj, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return string(aj) == string(bj)
+}
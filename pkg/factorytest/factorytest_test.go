@@ -0,0 +1,71 @@
+package factorytest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudboss/unobin/pkg/lang/syntax"
+	"github.com/cloudboss/unobin/pkg/runtime"
+	"github.com/cloudboss/unobin/pkg/typecheck"
+)
+
+func TestRunFile(t *testing.T) {
+	dir := filepath.Join("testdata", "ub", "run", "valid", "web")
+	factory := parseFixture(t, filepath.Join(dir, "factory.ub"))
+	tests := parseFixture(t, filepath.Join(dir, "web_test.ub"))
+	str := typecheck.Type{Kind: typecheck.String}
+	libs := map[string]*runtime.Library{
+		"aws": {
+			Name: "aws",
+			Schema: &runtime.LibrarySchema{
+				Resources: map[string]*runtime.TypeSchema{
+					"instance": {
+						Inputs:  map[string]typecheck.Type{"name": str},
+						Outputs: map[string]typecheck.Type{"id": str},
+					},
+				},
+				DataSources: map[string]*runtime.TypeSchema{
+					"image": {
+						Inputs:  map[string]typecheck.Type{"name": str},
+						Outputs: map[string]typecheck.Type{"id": str},
+					},
+				},
+			},
+		},
+	}
+
+	results := RunFile(context.Background(), Factory{
+		Body:      &factory.Factory.Body,
+		Libraries: libs,
+	}, tests.Test)
+
+	require.Equal(t, []Result{
+		{Name: "names the instance"},
+		{Name: "mocks each replica by its template address"},
+		{Name: "reports what does not hold", Failures: []string{
+			"decision for resource.web: got create, want update",
+			"decision for resource.gone: the plan has no such step",
+			"inputs for resource.web: name is 'web', want 'api'",
+			"output id is 'i-1', want 'i-9'",
+			`mock "resource.other" matched no library call`,
+		}},
+		{Name: "rejects an empty name"},
+		{Name: "fails when the expected error does not occur", Failures: []string{
+			`expected an error containing "name must not be empty", but the run succeeded`,
+		}},
+	}, results)
+}
+
+func parseFixture(t *testing.T, path string) *syntax.File {
+	t.Helper()
+	body, err := os.ReadFile(path)
+	require.NoError(t, err)
+	file, err := syntax.ParseSource(path, body)
+	require.NoError(t, err)
+	require.NoError(t, syntax.ValidateFile(file).Err())
+	return file
+}
@@ -0,0 +1,264 @@
+package factorytest
+
+import (
+	"context"
+	"maps"
+	"reflect"
+	"slices"
+	"sync"
+
+	"github.com/cloudboss/unobin/pkg/lang"
+	"github.com/cloudboss/unobin/pkg/runtime"
+	"github.com/cloudboss/unobin/pkg/sdk/cfg"
+	"github.com/cloudboss/unobin/pkg/stateref"
+)
+
+// mockValues receives any set of fields, so a mock decodes the inputs
+// and configuration of every type without knowing its Go struct.
+type mockValues struct {
+	Values map[string]any `ub:",remain"`
+}
+
+// mocks holds a test case's mocked outputs, keyed by node address or
+// `<alias>.<type>`, and records which of them a library call used.
+type mocks struct {
+	outputs map[string]map[string]any
+
+	mu   sync.Mutex
+	used map[string]bool
+}
+
+func newMocks(outputs map[string]map[string]any) *mocks {
+	return &mocks{outputs: outputs, used: map[string]bool{}}
+}
+
+// lookup returns the outputs for the call ctx is made for. The node's
+// exact address wins, then its address without for-each keys, then
+// its library alias and type. A call no mock covers gets no outputs.
+func (m *mocks) lookup(ctx context.Context, alias, typ string) map[string]any {
+	var keys []string
+	if addr, ok := runtime.StepAddress(ctx); ok {
+		keys = append(keys, addr)
+		if template, err := stateref.Template(addr); err == nil && template != addr {
+			keys = append(keys, template)
+		}
+	}
+	keys = append(keys, alias+"."+typ)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, key := range keys {
+		if outputs, ok := m.outputs[key]; ok {
+			m.used[key] = true
+			return maps.Clone(outputs)
+		}
+	}
+	return map[string]any{}
+}
+
+// unused returns the mock keys no library call matched, sorted.
+func (m *mocks) unused() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var keys []string
+	for key := range m.outputs {
+		if !m.used[key] {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// mockLibraries returns copies of libs whose Go types are backed by m.
+// A Go library from a source check carries only its schema, so each
+// type the schema names gets a mock registration. UB composites are
+// copied with their own import tables mocked the same way.
+func mockLibraries(libs map[string]*runtime.Library, m *mocks) map[string]*runtime.Library {
+	b := &mockBuilder{
+		mocks:      m,
+		libraries:  map[mockKey]*runtime.Library{},
+		composites: map[*runtime.CompositeType]*runtime.CompositeType{},
+	}
+	return b.table(libs)
+}
+
+type mockKey struct {
+	lib   *runtime.Library
+	alias string
+}
+
+type mockBuilder struct {
+	mocks      *mocks
+	libraries  map[mockKey]*runtime.Library
+	composites map[*runtime.CompositeType]*runtime.CompositeType
+}
+
+func (b *mockBuilder) table(libs map[string]*runtime.Library) map[string]*runtime.Library {
+	if libs == nil {
+		return nil
+	}
+	out := make(map[string]*runtime.Library, len(libs))
+	for alias, lib := range libs {
+		out[alias] = b.library(alias, lib)
+	}
+	return out
+}
+
+// library mocks lib under alias. The alias is part of the key because a
+// mock may name a type by the alias its scope imports it under.
+func (b *mockBuilder) library(alias string, lib *runtime.Library) *runtime.Library {
+	if lib == nil {
+		return nil
+	}
+	key := mockKey{lib: lib, alias: alias}
+	if mocked, ok := b.libraries[key]; ok {
+		return mocked
+	}
+	mocked := &runtime.Library{
+		Name:          lib.Name,
+		LibraryPath:   lib.LibraryPath,
+		Description:   lib.Description,
+		Configuration: lib.Configuration,
+		Actions:       lib.Actions,
+		Resources:     lib.Resources,
+		DataSources:   lib.DataSources,
+		Functions:     lib.Functions,
+		Schema:        lib.Schema,
+		Constraints:   lib.Constraints,
+		Defaults:      lib.Defaults,
+	}
+	b.libraries[key] = mocked
+	for _, ct := range lib.ResourceComposites {
+		mocked.AddComposite(b.composite(ct))
+	}
+	for _, ct := range lib.DataComposites {
+		mocked.AddComposite(b.composite(ct))
+	}
+	for _, ct := range lib.ActionComposites {
+		mocked.AddComposite(b.composite(ct))
+	}
+	schema := lib.Schema
+	if schema == nil {
+		return mocked
+	}
+	if schema.HasConfiguration && mocked.Configuration == nil {
+		mocked.Configuration = &cfg.ConfigurationType[*mockValues]{
+			New: func() *mockValues { return &mockValues{} },
+		}
+	}
+	mocked.Resources = map[string]runtime.ResourceRegistration{}
+	mocked.DataSources = map[string]runtime.DataSourceRegistration{}
+	mocked.Actions = map[string]runtime.ActionRegistration{}
+	mocked.Constraints = map[string][]lang.ConstraintSpec{}
+	mocked.Defaults = map[string][]lang.DefaultSpec{}
+	for typ, ts := range schema.Resources {
+		mocked.Resources[typ] = &mockResource{mockType{b.mocks, alias, typ}}
+		addSpecs(mocked, runtime.NodeResource, typ, ts)
+	}
+	for typ, ts := range schema.DataSources {
+		mocked.DataSources[typ] = &mockDataSource{mockType{b.mocks, alias, typ}}
+		addSpecs(mocked, runtime.NodeDataSource, typ, ts)
+	}
+	for typ, ts := range schema.Actions {
+		mocked.Actions[typ] = &mockAction{mockType{b.mocks, alias, typ}}
+		addSpecs(mocked, runtime.NodeAction, typ, ts)
+	}
+	return mocked
+}
+
+// addSpecs carries a type's schema constraints and defaults onto lib,
+// where codegen would have put them in a compiled factory.
+func addSpecs(
+	lib *runtime.Library,
+	kind runtime.NodeKind,
+	typ string,
+	ts *runtime.TypeSchema,
+) {
+	if ts == nil {
+		return
+	}
+	key := string(kind) + "." + typ
+	if len(ts.Constraints) > 0 {
+		lib.Constraints[key] = ts.Constraints
+	}
+	if len(ts.Defaults) > 0 {
+		lib.Defaults[key] = ts.Defaults
+	}
+}
+
+func (b *mockBuilder) composite(ct *runtime.CompositeType) *runtime.CompositeType {
+	if mocked, ok := b.composites[ct]; ok {
+		return mocked
+	}
+	mocked := *ct
+	b.composites[ct] = &mocked
+	mocked.Libraries = b.table(ct.Libraries)
+	return &mocked
+}
+
+// mockType is the part every mock registration shares: where its
+// outputs come from and the alias and type a mock may name it by.
+type mockType struct {
+	mocks *mocks
+	alias string
+	typ   string
+}
+
+func (t mockType) NewReceiver() any { return &mockValues{} }
+
+func (t mockType) OutputType() reflect.Type { return reflect.TypeFor[map[string]any]() }
+
+func (t mockType) outputs(ctx context.Context) map[string]any {
+	return t.mocks.lookup(ctx, t.alias, t.typ)
+}
+
+type mockResource struct{ mockType }
+
+func (r *mockResource) SchemaVersion() int { return 1 }
+
+func (r *mockResource) Migrate(
+	_ int, prior runtime.MigrationState,
+) (runtime.MigrationState, error) {
+	return prior, nil
+}
+
+func (r *mockResource) Create(ctx context.Context, _, _ any) (any, error) {
+	return r.outputs(ctx), nil
+}
+
+func (r *mockResource) Read(_ context.Context, _, _, prior any) (any, error) {
+	if prior == nil {
+		return nil, runtime.ErrNotFound
+	}
+	return prior, nil
+}
+
+func (r *mockResource) Update(ctx context.Context, _, _, _, _, _ any) (any, error) {
+	return r.outputs(ctx), nil
+}
+
+func (r *mockResource) ValidateInputs(context.Context, any, any) error { return nil }
+
+func (r *mockResource) Delete(context.Context, any, any, any) error { return nil }
+
+func (r *mockResource) ReplaceFields(any) []string { return nil }
+
+func (r *mockResource) EquivalentInput(any, string, map[string]any) bool { return false }
+
+func (r *mockResource) ModifyResourcePlan(
+	any, any, map[string]any, map[string]any, bool,
+) (runtime.ResourcePlanResponse, error) {
+	return runtime.ResourcePlanResponse{}, nil
+}
+
+type mockDataSource struct{ mockType }
+
+func (d *mockDataSource) Read(ctx context.Context, _, _ any) (any, error) {
+	return d.outputs(ctx), nil
+}
+
+type mockAction struct{ mockType }
+
+func (a *mockAction) Run(ctx context.Context, _, _ any) (any, error) {
+	return a.outputs(ctx), nil
+}
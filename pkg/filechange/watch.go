@@ -0,0 +1,188 @@
+package filechange
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Tree is the observed content of the files under a set of roots, keyed
+// by path.
+type Tree struct {
+	files map[string]treeFile
+}
+
+type treeFile struct {
+	kind    fs.FileMode
+	size    int64
+	modTime time.Time
+	sum     [sha256.Size]byte
+}
+
+// ScanTree observes the files under roots. A root may be a file or a
+// directory, and a missing root has no files. skip, when set, leaves out
+// a path below a root and, for a directory, everything under it. A file
+// whose size and modification time match prev keeps its hash from prev,
+// so a rescan reads only the files that were touched.
+func ScanTree(
+	roots []string,
+	skip func(path string, entry fs.DirEntry) bool,
+	prev Tree,
+) (Tree, error) {
+	tree := Tree{files: map[string]treeFile{}}
+	for _, root := range roots {
+		err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if path != root && skip != nil && skip(path, entry) {
+				if entry.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if entry.IsDir() {
+				return nil
+			}
+			if _, ok := tree.files[path]; ok {
+				return nil
+			}
+			file, ok, err := observeTreeFile(path, prev.files[path])
+			if err != nil {
+				return err
+			}
+			if ok {
+				tree.files[path] = file
+			}
+			return nil
+		})
+		if err != nil {
+			return Tree{}, err
+		}
+	}
+	return tree, nil
+}
+
+// Diff reports what changed from before to after, sorted by path. Files
+// with the same content in both are left out.
+func Diff(before, after Tree) []Change {
+	changes := []Change{}
+	for path, file := range after.files {
+		old, ok := before.files[path]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Path: path, Action: ActionCreated})
+		case old.kind != file.kind || old.sum != file.sum:
+			changes = append(changes, Change{Path: path, Action: ActionUpdated})
+		}
+	}
+	for path := range before.files {
+		if _, ok := after.files[path]; !ok {
+			changes = append(changes, Change{Path: path, Action: ActionRemoved})
+		}
+	}
+	return Sort(changes)
+}
+
+func observeTreeFile(path string, prev treeFile) (treeFile, bool, error) {
+	info, err := os.Lstat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return treeFile{}, false, nil
+	}
+	if err != nil {
+		return treeFile{}, false, err
+	}
+	file := treeFile{kind: info.Mode().Type(), size: info.Size(), modTime: info.ModTime()}
+	if file.kind == prev.kind && file.size == prev.size && file.modTime.Equal(prev.modTime) {
+		file.sum = prev.sum
+		return file, true, nil
+	}
+	snapshot, err := snapshotFile(path)
+	if err != nil {
+		return treeFile{}, false, err
+	}
+	if !snapshot.exists {
+		return treeFile{}, false, nil
+	}
+	file.sum = sha256.Sum256(snapshot.content)
+	return file, true, nil
+}
+
+// Watcher reports changes to the files under a set of roots by scanning
+// them every interval. It reports a burst of changes once it settles,
+// after a scan that finds nothing newer than the one before, so a save
+// that writes several files is seen as one change.
+type Watcher struct {
+	roots    []string
+	skip     func(path string, entry fs.DirEntry) bool
+	interval time.Duration
+	tree     Tree
+}
+
+// NewWatcher returns a watcher of roots whose changes are reported from
+// the files as they are now.
+func NewWatcher(
+	roots []string,
+	skip func(path string, entry fs.DirEntry) bool,
+	interval time.Duration,
+) (*Watcher, error) {
+	tree, err := ScanTree(roots, skip, Tree{})
+	if err != nil {
+		return nil, err
+	}
+	return &Watcher{roots: roots, skip: skip, interval: interval, tree: tree}, nil
+}
+
+// SetRoots replaces the watched roots. Files under a new root are taken
+// as they are now rather than reported as created, and files no longer
+// under any root are forgotten.
+func (w *Watcher) SetRoots(roots []string) error {
+	tree, err := ScanTree(roots, w.skip, w.tree)
+	if err != nil {
+		return err
+	}
+	for path := range tree.files {
+		if file, ok := w.tree.files[path]; ok {
+			tree.files[path] = file
+		}
+	}
+	w.roots = roots
+	w.tree = tree
+	return nil
+}
+
+// Next waits for the files to change and settle, and returns what
+// changed since the last report. It returns the context's error when ctx
+// is done first.
+func (w *Watcher) Next(ctx context.Context) ([]Change, error) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	last := w.tree
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+		current, err := ScanTree(w.roots, w.skip, last)
+		if err != nil {
+			return nil, err
+		}
+		settled := len(Diff(last, current)) == 0
+		last = current
+		if !settled {
+			continue
+		}
+		if changes := Diff(w.tree, current); len(changes) > 0 {
+			w.tree = current
+			return changes, nil
+		}
+	}
+}
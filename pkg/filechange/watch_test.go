@@ -0,0 +1,98 @@
+package filechange
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanTreeDiff(t *testing.T) {
+	dir := t.TempDir()
+	writeWatchFile(t, dir, "kept.txt", "same")
+	writeWatchFile(t, dir, "edited.txt", "old")
+	writeWatchFile(t, dir, "removed.txt", "gone")
+	writeWatchFile(t, dir, "skipped/ignored.txt", "old")
+	skip := func(path string, entry fs.DirEntry) bool { return entry.Name() == "skipped" }
+
+	before, err := ScanTree([]string{dir}, skip, Tree{})
+	require.NoError(t, err)
+	writeWatchFile(t, dir, "edited.txt", "new")
+	writeWatchFile(t, dir, "created.txt", "new")
+	writeWatchFile(t, dir, "skipped/ignored.txt", "new")
+	require.NoError(t, os.Remove(filepath.Join(dir, "removed.txt")))
+	touched := time.Now().Add(time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "kept.txt"), touched, touched))
+	after, err := ScanTree([]string{dir}, skip, before)
+	require.NoError(t, err)
+
+	require.Equal(t, []Change{
+		{Path: filepath.Join(dir, "created.txt"), Action: ActionCreated},
+		{Path: filepath.Join(dir, "edited.txt"), Action: ActionUpdated},
+		{Path: filepath.Join(dir, "removed.txt"), Action: ActionRemoved},
+	}, Diff(before, after))
+}
+
+func TestScanTreeMissingRoot(t *testing.T) {
+	tree, err := ScanTree([]string{filepath.Join(t.TempDir(), "missing")}, nil, Tree{})
+
+	require.NoError(t, err)
+	require.Empty(t, Diff(Tree{}, tree))
+}
+
+func TestWatcherReportsSettledChanges(t *testing.T) {
+	dir := t.TempDir()
+	writeWatchFile(t, dir, "a.txt", "a")
+	watcher, err := NewWatcher([]string{dir}, nil, 5*time.Millisecond)
+	require.NoError(t, err)
+
+	writeWatchFile(t, dir, "a.txt", "b")
+	writeWatchFile(t, dir, "b.txt", "b")
+	changes, err := watcher.Next(context.Background())
+
+	require.NoError(t, err)
+	require.Equal(t, []Change{
+		{Path: filepath.Join(dir, "a.txt"), Action: ActionUpdated},
+		{Path: filepath.Join(dir, "b.txt"), Action: ActionCreated},
+	}, changes)
+}
+
+func TestWatcherSetRootsTakesNewFilesAsTheyAre(t *testing.T) {
+	first := t.TempDir()
+	second := t.TempDir()
+	writeWatchFile(t, first, "a.txt", "a")
+	writeWatchFile(t, second, "b.txt", "b")
+	watcher, err := NewWatcher([]string{first}, nil, 5*time.Millisecond)
+	require.NoError(t, err)
+
+	require.NoError(t, watcher.SetRoots([]string{first, second}))
+	writeWatchFile(t, second, "b.txt", "changed")
+	changes, err := watcher.Next(context.Background())
+
+	require.NoError(t, err)
+	require.Equal(t, []Change{
+		{Path: filepath.Join(second, "b.txt"), Action: ActionUpdated},
+	}, changes)
+}
+
+func TestWatcherStopsWithContext(t *testing.T) {
+	watcher, err := NewWatcher([]string{t.TempDir()}, nil, 5*time.Millisecond)
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = watcher.Next(ctx)
+
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func writeWatchFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
@@ -1,6 +1,7 @@
 // Package git wraps the go-git operations the resolver needs:
 // resolving a ref to a commit SHA without fetching, listing a repo's
-// tags, and cloning a repo at a specific ref into a directory.
+// tags, and cloning a repo at a specific ref into a directory, with the
+// credentials a Credentials resolves for the remote.
 package git
 
 import (
@@ -18,14 +19,21 @@ import (
 // LsRemote returns the commit SHA that ref points at on the remote at
 // url. ref may be a tag, branch, or a full commit SHA. No local clone
 // is created.
-func LsRemote(ctx context.Context, url, ref string) (string, error) {
+func LsRemote(ctx context.Context, url, ref string, creds *Credentials) (string, error) {
+	auth, err := creds.AuthFor(ctx, url)
+	if err != nil {
+		return "", creds.redact(fmt.Errorf("ls-remote %s: %w", url, err))
+	}
 	rem := gogit.NewRemote(memory.NewStorage(), &config.RemoteConfig{
 		Name: "origin",
 		URLs: []string{url},
 	})
-	refs, err := rem.ListContext(ctx, &gogit.ListOptions{PeelingOption: gogit.AppendPeeled})
+	refs, err := rem.ListContext(ctx, &gogit.ListOptions{
+		Auth:          auth,
+		PeelingOption: gogit.AppendPeeled,
+	})
 	if err != nil {
-		return "", fmt.Errorf("ls-remote %s: %w", url, err)
+		return "", creds.redact(fmt.Errorf("ls-remote %s: %w", url, err))
 	}
 	wantTag := plumbing.NewTagReferenceName(ref).String()
 	wantPeeledTag := wantTag + "^{}"
@@ -46,7 +54,7 @@ func LsRemote(ctx context.Context, url, ref string) (string, error) {
 	if plumbing.IsHash(ref) {
 		return ref, nil
 	}
-	return "", fmt.Errorf("ls-remote %s: no ref matches %q", url, ref)
+	return "", creds.redact(fmt.Errorf("ls-remote %s: no ref matches %q", url, ref))
 }
 
 // ListTags returns the tag names defined on the remote at url, without
@@ -54,14 +62,18 @@ func LsRemote(ctx context.Context, url, ref string) (string, error) {
 // are excluded so each tag appears once. No local clone is created.
 // Order is whatever the remote reports; callers that need ordering sort
 // the result themselves.
-func ListTags(ctx context.Context, url string) ([]string, error) {
+func ListTags(ctx context.Context, url string, creds *Credentials) ([]string, error) {
+	auth, err := creds.AuthFor(ctx, url)
+	if err != nil {
+		return nil, creds.redact(fmt.Errorf("ls-remote %s: %w", url, err))
+	}
 	rem := gogit.NewRemote(memory.NewStorage(), &config.RemoteConfig{
 		Name: "origin",
 		URLs: []string{url},
 	})
-	refs, err := rem.ListContext(ctx, &gogit.ListOptions{})
+	refs, err := rem.ListContext(ctx, &gogit.ListOptions{Auth: auth})
 	if err != nil {
-		return nil, fmt.Errorf("ls-remote %s: %w", url, err)
+		return nil, creds.redact(fmt.Errorf("ls-remote %s: %w", url, err))
 	}
 	var tags []string
 	for _, r := range refs {
@@ -80,15 +92,20 @@ func ListTags(ctx context.Context, url string) ([]string, error) {
 
 // Clone clones the repo at url into dest and checks out ref. dest
 // must not yet exist. Returns the resolved commit SHA.
-func Clone(ctx context.Context, url, ref, dest string) (string, error) {
+func Clone(ctx context.Context, url, ref, dest string, creds *Credentials) (string, error) {
 	if _, err := os.Stat(dest); err == nil {
-		return "", fmt.Errorf("clone %s: %s already exists", url, dest)
+		return "", creds.redact(fmt.Errorf("clone %s: %s already exists", url, dest))
+	}
+	auth, err := creds.AuthFor(ctx, url)
+	if err != nil {
+		return "", creds.redact(fmt.Errorf("clone %s: %w", url, err))
 	}
 	repo, err := gogit.PlainCloneContext(ctx, dest, false, &gogit.CloneOptions{
-		URL: url,
+		URL:  url,
+		Auth: auth,
 	})
 	if err != nil {
-		return "", fmt.Errorf("clone %s: %w", url, err)
+		return "", creds.redact(fmt.Errorf("clone %s: %w", url, err))
 	}
 	hash, err := repo.ResolveRevision(plumbing.Revision(ref))
 	if err != nil {
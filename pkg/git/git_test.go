@@ -62,7 +62,7 @@ func TestListTags(t *testing.T) {
 	tag("networking/v1.5.0", false)
 	tag("latest", false) // not semver, returned as-is
 
-	got, err := ListTags(context.Background(), dir)
+	got, err := ListTags(context.Background(), dir, nil)
 	require.NoError(t, err)
 	require.ElementsMatch(t,
 		[]string{"v1", "v2.0.0", "v2.1.0", "networking/v1.5.0", "latest"}, got)
@@ -72,7 +72,7 @@ func TestLsRemoteResolvesTag(t *testing.T) {
 	src := filepath.Join(t.TempDir(), "src")
 	wantSHA := makeRepo(t, src)
 
-	got, err := LsRemote(context.Background(), src, "v1")
+	got, err := LsRemote(context.Background(), src, "v1", nil)
 	require.NoError(t, err)
 	require.Equal(t, wantSHA, got)
 }
@@ -87,7 +87,7 @@ func TestLsRemoteResolvesAnnotatedTagToCommit(t *testing.T) {
 	})
 	require.NoError(t, err)
 
-	got, err := LsRemote(context.Background(), src, "v1")
+	got, err := LsRemote(context.Background(), src, "v1", nil)
 	require.NoError(t, err)
 	require.Equal(t, commit.String(), got)
 }
@@ -96,7 +96,7 @@ func TestLsRemoteResolvesBranch(t *testing.T) {
 	src := filepath.Join(t.TempDir(), "src")
 	wantSHA := makeRepo(t, src)
 
-	got, err := LsRemote(context.Background(), src, "main")
+	got, err := LsRemote(context.Background(), src, "main", nil)
 	require.NoError(t, err)
 	require.Equal(t, wantSHA, got)
 }
@@ -105,7 +105,7 @@ func TestLsRemotePassesThroughHash(t *testing.T) {
 	src := filepath.Join(t.TempDir(), "src")
 	wantSHA := makeRepo(t, src)
 
-	got, err := LsRemote(context.Background(), src, wantSHA)
+	got, err := LsRemote(context.Background(), src, wantSHA, nil)
 	require.NoError(t, err)
 	require.Equal(t, wantSHA, got)
 }
@@ -114,7 +114,7 @@ func TestLsRemoteUnknownRef(t *testing.T) {
 	src := filepath.Join(t.TempDir(), "src")
 	makeRepo(t, src)
 
-	_, err := LsRemote(context.Background(), src, "no-such-ref")
+	_, err := LsRemote(context.Background(), src, "no-such-ref", nil)
 	require.Error(t, err)
 }
 
@@ -123,7 +123,7 @@ func TestCloneCheckoutByTag(t *testing.T) {
 	wantSHA := makeRepo(t, src)
 
 	dest := filepath.Join(t.TempDir(), "dest")
-	gotSHA, err := Clone(context.Background(), src, "v1", dest)
+	gotSHA, err := Clone(context.Background(), src, "v1", dest, nil)
 	require.NoError(t, err)
 	require.Equal(t, wantSHA, gotSHA)
 
@@ -137,7 +137,7 @@ func TestCloneCheckoutByCommit(t *testing.T) {
 	wantSHA := makeRepo(t, src)
 
 	dest := filepath.Join(t.TempDir(), "dest")
-	gotSHA, err := Clone(context.Background(), src, wantSHA, dest)
+	gotSHA, err := Clone(context.Background(), src, wantSHA, dest, nil)
 	require.NoError(t, err)
 	require.Equal(t, wantSHA, gotSHA)
 }
@@ -150,7 +150,7 @@ func TestCloneRejectsExistingDest(t *testing.T) {
 	require.NoError(t, os.MkdirAll(dest, 0o755))
 	require.NoError(t, os.WriteFile(filepath.Join(dest, "x"), []byte{}, 0o644))
 
-	_, err := Clone(context.Background(), src, "v1", dest)
+	_, err := Clone(context.Background(), src, "v1", dest, nil)
 	require.Error(t, err)
 }
 
@@ -159,6 +159,6 @@ func TestCloneUnknownRef(t *testing.T) {
 	makeRepo(t, src)
 	dest := filepath.Join(t.TempDir(), "dest")
 
-	_, err := Clone(context.Background(), src, "no-such-ref", dest)
+	_, err := Clone(context.Background(), src, "no-such-ref", dest, nil)
 	require.Error(t, err)
 }
@@ -0,0 +1,292 @@
+package cfn
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/cloudboss/unobin/pkg/gogen"
+)
+
+// Adapter implements gogen.SchemaAdapter backed by a directory of
+// CloudFormation resource provider schema files, such as the ones the
+// CloudFormation registry publishes or DescribeType returns. Every *.json
+// file in the directory holds one resource type.
+type Adapter struct {
+	schemaDir string
+
+	once    sync.Once
+	schemas []resourceSchema
+	err     error
+}
+
+// NewAdapter creates an Adapter reading the schema files in schemaDir.
+func NewAdapter(schemaDir string) *Adapter {
+	return &Adapter{schemaDir: schemaDir}
+}
+
+// Name returns the lowercased vendor of the first resource type in the
+// schema directory (aws for AWS::S3::Bucket), or cfn when the directory
+// cannot be read; FetchResources then reports why.
+func (a *Adapter) Name() string {
+	schemas, err := a.load()
+	if err != nil || len(schemas) == 0 {
+		return "cfn"
+	}
+	vendor, _, _ := splitTypeName(schemas[0].TypeName)
+	return strings.ToLower(vendor)
+}
+
+// FetchResources converts the schemas whose type names match one of
+// resources, such as s3 or S3::Bucket, ignoring case and the vendor
+// prefix. An empty resources converts every schema.
+func (a *Adapter) FetchResources(
+	_ context.Context,
+	resources []string,
+) ([]gogen.ResourceSchema, error) {
+	schemas, err := a.load()
+	if err != nil {
+		return nil, err
+	}
+	var all []gogen.ResourceSchema
+	for _, s := range schemas {
+		if !matchesType(s.TypeName, resources) {
+			continue
+		}
+		all = append(all, convertResource(s))
+	}
+	return all, nil
+}
+
+// FetchDataSources returns nil: resource provider schemas describe only
+// resources.
+func (a *Adapter) FetchDataSources(
+	context.Context,
+	[]string,
+) ([]gogen.DataSourceSchema, error) {
+	return nil, nil
+}
+
+// FetchConfiguration returns nil: generated resources load the default
+// AWS configuration chain, so the library has no configuration to expose.
+func (a *Adapter) FetchConfiguration(context.Context) (*gogen.ConfigurationSchema, error) {
+	return nil, nil
+}
+
+func (a *Adapter) load() ([]resourceSchema, error) {
+	a.once.Do(func() {
+		a.schemas, a.err = loadSchemas(a.schemaDir)
+	})
+	return a.schemas, a.err
+}
+
+// resourceSchema is the subset of the resource provider schema format
+// that code generation reads.
+type resourceSchema struct {
+	TypeName             string              `json:"typeName"`
+	Description          string              `json:"description"`
+	Properties           map[string]property `json:"properties"`
+	Definitions          map[string]property `json:"definitions"`
+	Required             []string            `json:"required"`
+	ReadOnlyProperties   []string            `json:"readOnlyProperties"`
+	CreateOnlyProperties []string            `json:"createOnlyProperties"`
+	PrimaryIdentifier    []string            `json:"primaryIdentifier"`
+}
+
+type property struct {
+	Type        json.RawMessage     `json:"type"`
+	Ref         string              `json:"$ref"`
+	Description string              `json:"description"`
+	Items       *property           `json:"items"`
+	Properties  map[string]property `json:"properties"`
+}
+
+func loadSchemas(dir string) ([]resourceSchema, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("no schema directory given")
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("%s: no *.json schema files", dir)
+	}
+	slices.Sort(paths)
+	schemas := make([]resourceSchema, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var s resourceSchema
+		if err := json.Unmarshal(data, &s); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", path, err)
+		}
+		if _, _, ok := splitTypeName(s.TypeName); !ok {
+			return nil, fmt.Errorf("%s: typeName %q is not Vendor::Service::Resource",
+				path, s.TypeName)
+		}
+		schemas = append(schemas, s)
+	}
+	return schemas, nil
+}
+
+// splitTypeName splits AWS::S3::Bucket into its vendor and the Go name
+// of its service and resource, S3Bucket.
+func splitTypeName(typeName string) (string, string, bool) {
+	parts := strings.Split(typeName, "::")
+	if len(parts) != 3 {
+		return "", "", false
+	}
+	service, resource := cfnNameToGo(parts[1]), cfnNameToGo(parts[2])
+	if parts[0] == "" || service == "" || resource == "" {
+		return "", "", false
+	}
+	return parts[0], service + resource, true
+}
+
+func matchesType(typeName string, resources []string) bool {
+	if len(resources) == 0 {
+		return true
+	}
+	full := strings.ToLower(typeName)
+	_, rest, _ := strings.Cut(full, "::")
+	for _, r := range resources {
+		r = strings.ToLower(r)
+		if strings.HasPrefix(rest, r) || strings.HasPrefix(full, r) {
+			return true
+		}
+	}
+	return false
+}
+
+func convertResource(s resourceSchema) gogen.ResourceSchema {
+	_, goName, _ := splitTypeName(s.TypeName)
+	readOnly := topLevelProperties(s.ReadOnlyProperties)
+	createOnly := topLevelProperties(s.CreateOnlyProperties)
+
+	var inputFields, outputFields []gogen.Field
+	var createOnlyFields []string
+	for name, p := range s.Properties {
+		goField := cfnNameToGo(name)
+		if goField == "" {
+			continue
+		}
+		field := gogen.Field{
+			Name:        goField,
+			CloudName:   name,
+			GoType:      propertyGoType(p, s.Definitions, nil),
+			Description: p.Description,
+			Required:    slices.Contains(s.Required, name),
+		}
+		if slices.Contains(readOnly, name) {
+			field.Required = false
+			outputFields = append(outputFields, field)
+			continue
+		}
+		inputFields = append(inputFields, field)
+		if slices.Contains(createOnly, name) {
+			createOnlyFields = append(createOnlyFields, goField)
+		}
+	}
+	byName := func(a, b gogen.Field) int { return cmp.Compare(a.Name, b.Name) }
+	slices.SortFunc(inputFields, byName)
+	slices.SortFunc(outputFields, byName)
+	slices.Sort(createOnlyFields)
+
+	var primaryIdentifier []string
+	for _, name := range topLevelProperties(s.PrimaryIdentifier) {
+		primaryIdentifier = append(primaryIdentifier, cfnNameToGo(name))
+	}
+
+	return gogen.ResourceSchema{
+		GoName:            goName,
+		CloudType:         s.TypeName,
+		Description:       s.Description,
+		InputFields:       inputFields,
+		OutputFields:      outputFields,
+		CreateOnlyFields:  createOnlyFields,
+		PrimaryIdentifier: primaryIdentifier,
+		CloudControl:      true,
+	}
+}
+
+// topLevelProperties returns the property names that JSON pointers such
+// as /properties/Arn name. Pointers into nested properties are dropped:
+// generated fields are whole top-level properties.
+func topLevelProperties(pointers []string) []string {
+	var names []string
+	for _, pointer := range pointers {
+		name, ok := strings.CutPrefix(pointer, "/properties/")
+		if !ok || name == "" || strings.Contains(name, "/") {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// propertyGoType maps a property's JSON schema type to a Go type.
+// Objects, whether inline or behind a $ref, become map[string]any, and a
+// property allowing several types becomes any. seen guards against
+// definitions that refer to themselves.
+func propertyGoType(p property, defs map[string]property, seen []string) string {
+	if p.Ref != "" {
+		name, ok := strings.CutPrefix(p.Ref, "#/definitions/")
+		def, found := defs[name]
+		if !ok || !found || slices.Contains(seen, name) {
+			return "any"
+		}
+		return propertyGoType(def, defs, append(seen, name))
+	}
+	var kind string
+	if err := json.Unmarshal(p.Type, &kind); err != nil {
+		if len(p.Properties) > 0 {
+			return "map[string]any"
+		}
+		return "any"
+	}
+	switch kind {
+	case "string":
+		return "string"
+	case "integer":
+		return "int64"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		if p.Items == nil {
+			return "[]any"
+		}
+		return "[]" + propertyGoType(*p.Items, defs, seen)
+	case "object":
+		return "map[string]any"
+	default:
+		return "any"
+	}
+}
+
+// cfnNameToGo converts a CloudFormation name, already PascalCase in
+// nearly every schema, to an exported Go identifier. Returns an empty
+// string when the name cannot be one.
+func cfnNameToGo(name string) string {
+	if name == "" || name[0] >= '0' && name[0] <= '9' {
+		return ""
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') {
+			continue
+		}
+		return ""
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
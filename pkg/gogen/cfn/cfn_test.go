@@ -0,0 +1,158 @@
+package cfn
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cloudboss/unobin/pkg/gogen"
+)
+
+func TestConvertS3Bucket(t *testing.T) {
+	adapter := NewAdapter("testdata")
+	if got := adapter.Name(); got != "aws" {
+		t.Errorf("Name() = %q, want aws", got)
+	}
+	resources, err := adapter.FetchResources(context.Background(), []string{"s3"})
+	if err != nil {
+		t.Fatalf("FetchResources: %v", err)
+	}
+	if len(resources) != 1 {
+		t.Fatalf("expected 1 resource, got %d", len(resources))
+	}
+
+	want := gogen.ResourceSchema{
+		GoName:    "S3Bucket",
+		CloudType: "AWS::S3::Bucket",
+		Description: "The AWS::S3::Bucket resource creates an Amazon S3 bucket in the same " +
+			"AWS Region where you create the AWS CloudFormation stack.",
+		InputFields: []gogen.Field{
+			{
+				Name: "BucketName", CloudName: "BucketName", GoType: "string",
+				Description: "A name for the bucket.",
+			},
+			{
+				Name: "ObjectLockEnabled", CloudName: "ObjectLockEnabled", GoType: "bool",
+				Description: "Indicates whether this bucket has an Object Lock configuration enabled.",
+			},
+			{
+				Name: "Tags", CloudName: "Tags", GoType: "[]map[string]any",
+				Description: "An arbitrary set of tags (key-value pairs) for this S3 bucket.",
+			},
+			{
+				Name: "VersioningConfiguration", CloudName: "VersioningConfiguration",
+				GoType: "map[string]any",
+			},
+		},
+		OutputFields: []gogen.Field{
+			{
+				Name: "Arn", CloudName: "Arn", GoType: "string",
+				Description: "The Amazon Resource Name (ARN) of the specified bucket.",
+			},
+			{
+				Name: "DomainName", CloudName: "DomainName", GoType: "string",
+				Description: "The IPv4 DNS name of the specified bucket.",
+			},
+		},
+		CreateOnlyFields:  []string{"BucketName", "ObjectLockEnabled"},
+		PrimaryIdentifier: []string{"BucketName"},
+		CloudControl:      true,
+	}
+	if !reflect.DeepEqual(resources[0], want) {
+		t.Errorf("FetchResources:\n got %+v\nwant %+v", resources[0], want)
+	}
+}
+
+func TestFetchResourcesFilters(t *testing.T) {
+	adapter := NewAdapter("testdata")
+	for _, tt := range []struct {
+		resources []string
+		want      []string
+	}{
+		{nil, []string{"LogsLogGroup", "S3Bucket"}},
+		{[]string{"logs"}, []string{"LogsLogGroup"}},
+		{[]string{"S3::Bucket"}, []string{"S3Bucket"}},
+		{[]string{"AWS::Logs::LogGroup"}, []string{"LogsLogGroup"}},
+		{[]string{"ec2"}, nil},
+	} {
+		resources, err := adapter.FetchResources(context.Background(), tt.resources)
+		if err != nil {
+			t.Fatalf("FetchResources(%v): %v", tt.resources, err)
+		}
+		var got []string
+		for _, rs := range resources {
+			got = append(got, rs.GoName)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("FetchResources(%v) = %v, want %v", tt.resources, got, tt.want)
+		}
+	}
+}
+
+func TestRequiredAndIntegerProperties(t *testing.T) {
+	resources, err := NewAdapter("testdata").FetchResources(context.Background(), []string{"logs"})
+	if err != nil {
+		t.Fatalf("FetchResources: %v", err)
+	}
+	for _, f := range resources[0].InputFields {
+		switch f.Name {
+		case "RetentionInDays":
+			if !f.Required || f.GoType != "int64" {
+				t.Errorf("RetentionInDays = %+v, want a required int64", f)
+			}
+		case "DataProtectionPolicy":
+			if f.Required || f.GoType != "map[string]any" {
+				t.Errorf("DataProtectionPolicy = %+v, want an optional map[string]any", f)
+			}
+		}
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	empty := t.TempDir()
+	_, err := NewAdapter(empty).FetchResources(context.Background(), nil)
+	if err == nil || !strings.Contains(err.Error(), "no *.json schema files") {
+		t.Errorf("empty dir: got %v", err)
+	}
+
+	bad := t.TempDir()
+	path := filepath.Join(bad, "bad.json")
+	if err := os.WriteFile(path, []byte(`{"typeName": "Bucket"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	adapter := NewAdapter(bad)
+	if got := adapter.Name(); got != "cfn" {
+		t.Errorf("Name() = %q, want cfn", got)
+	}
+	_, err = adapter.FetchResources(context.Background(), nil)
+	if err == nil || !strings.Contains(err.Error(), `typeName "Bucket" is not Vendor::Service::Resource`) {
+		t.Errorf("bad typeName: got %v", err)
+	}
+}
+
+func TestPropertyGoType(t *testing.T) {
+	defs := map[string]property{
+		"Self": {Ref: "#/definitions/Self"},
+		"Name": {Type: []byte(`"string"`)},
+	}
+	tests := []struct {
+		p    property
+		want string
+	}{
+		{property{Type: []byte(`"number"`)}, "float64"},
+		{property{Type: []byte(`["string", "object"]`)}, "any"},
+		{property{Type: []byte(`"array"`)}, "[]any"},
+		{property{Type: []byte(`"array"`), Items: &property{Ref: "#/definitions/Name"}}, "[]string"},
+		{property{Ref: "#/definitions/Self"}, "any"},
+		{property{Ref: "#/definitions/Missing"}, "any"},
+		{property{Properties: map[string]property{"A": {}}}, "map[string]any"},
+	}
+	for _, tt := range tests {
+		if got := propertyGoType(tt.p, defs, nil); got != tt.want {
+			t.Errorf("propertyGoType(%+v) = %q, want %q", tt.p, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,4 @@
+// Package cfn parses CloudFormation resource provider schemas (the
+// registry's JSON schema files) and converts them to gogen.ResourceSchema
+// values whose generated resources call the AWS Cloud Control API.
+package cfn
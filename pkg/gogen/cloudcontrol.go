@@ -0,0 +1,311 @@
+package gogen
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// cloudControlFile is the resources/ file holding the Cloud Control
+// calls every CloudControl resource shares.
+const cloudControlFile = "cloud_control.go"
+
+// cloudControlRequires are the go.mod requirements of cloudControlFile.
+var cloudControlRequires = []string{
+	"github.com/aws/aws-sdk-go-v2 v1.42.1",
+	"github.com/aws/aws-sdk-go-v2/config v1.32.17",
+	"github.com/aws/aws-sdk-go-v2/service/cloudcontrol v1.31.1",
+}
+
+// CloudControlFile renders the resources/ file that creates, reads,
+// updates, and deletes resources through the AWS Cloud Control API. The
+// client loads the default AWS configuration chain, so credentials and
+// region come from the environment the factory runs in. The client is
+// built once and shared by every call.
+func CloudControlFile(from string) ([]byte, error) {
+	var b bytes.Buffer
+	writeGeneratedComment(&b, from)
+	b.WriteString(cloudControlSource)
+	return formatSource(b.Bytes())
+}
+
+// writeCloudControlIdentifier adds the output field holding the
+// identifier Cloud Control returns on create, which every later call
+// names the resource by.
+func writeCloudControlIdentifier(b *bytes.Buffer, rs ResourceSchema) {
+	b.WriteString("\t// CloudControlIdentifier is the identifier Cloud Control returned on create")
+	if len(rs.PrimaryIdentifier) > 0 {
+		names := make([]string, len(rs.PrimaryIdentifier))
+		for i, name := range rs.PrimaryIdentifier {
+			names[i] = cloudName(rs, name)
+		}
+		fmt.Fprintf(b, ":\n\t// the resource's %s", strings.Join(names, "|"))
+	}
+	b.WriteString(".\n")
+	b.WriteString("\tCloudControlIdentifier string `ub:\"cloud-control-identifier\" json:\"-\"`\n")
+}
+
+// writeCloudControlMethods renders a resource's CRUD methods as calls
+// to the helpers in cloudControlFile.
+func writeCloudControlMethods(b *bytes.Buffer, rs ResourceSchema) {
+	name, typeName := rs.GoName, rs.CloudType
+	outPtr := "*" + name + "Output"
+
+	fmt.Fprintf(b, "func (r *%s) Create(ctx context.Context, cfg any) (%s, error) {\n", name, outPtr)
+	fmt.Fprintf(b, "\tid, err := createResource(ctx, %q, r)\n", typeName)
+	b.WriteString("\tif err != nil {\n\t\treturn nil, err\n\t}\n")
+	fmt.Fprintf(b, "\treturn r.Read(ctx, cfg, &%sOutput{CloudControlIdentifier: id})\n", name)
+	b.WriteString("}\n\n")
+
+	fmt.Fprintf(b, "func (r *%s) Read(ctx context.Context, cfg any, priorOutputs %s) (%s, error) {\n",
+		name, outPtr, outPtr)
+	fmt.Fprintf(b, "\tout := &%sOutput{CloudControlIdentifier: priorOutputs.CloudControlIdentifier}\n", name)
+	fmt.Fprintf(b, "\tif err := getResource(ctx, %q, out.CloudControlIdentifier, out); err != nil {\n",
+		typeName)
+	b.WriteString("\t\treturn nil, err\n\t}\n")
+	b.WriteString("\treturn out, nil\n")
+	b.WriteString("}\n\n")
+
+	fmt.Fprintf(b,
+		"func (r *%s) Update(ctx context.Context, cfg any, prior runtime.Prior[%s, %s]) (%s, error) {\n",
+		name, name, outPtr, outPtr)
+	b.WriteString("\tid := prior.Outputs.CloudControlIdentifier\n")
+	fmt.Fprintf(b, "\tif err := updateResource(ctx, %q, id, prior.Inputs, r); err != nil {\n", typeName)
+	b.WriteString("\t\treturn nil, err\n\t}\n")
+	b.WriteString("\treturn r.Read(ctx, cfg, prior.Outputs)\n")
+	b.WriteString("}\n\n")
+
+	fmt.Fprintf(b, "func (r *%s) Delete(ctx context.Context, cfg any, priorOutputs %s) error {\n",
+		name, outPtr)
+	fmt.Fprintf(b, "\treturn deleteResource(ctx, %q, priorOutputs.CloudControlIdentifier)\n", typeName)
+	b.WriteString("}\n")
+}
+
+// cloudName returns the cloud property name of the field named goName.
+func cloudName(rs ResourceSchema, goName string) string {
+	for _, fields := range [][]Field{rs.InputFields, rs.OutputFields} {
+		for _, f := range fields {
+			if f.Name == goName && f.CloudName != "" {
+				return f.CloudName
+			}
+		}
+	}
+	return goName
+}
+
+const cloudControlSource = `package resources
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/cloudcontrol"
+	"github.com/aws/aws-sdk-go-v2/service/cloudcontrol/types"
+	"github.com/cloudboss/unobin/pkg/runtime"
+)
+
+// pollInterval is how long to wait between checks of a pending request.
+const pollInterval = 5 * time.Second
+
+var (
+	sharedClientOnce sync.Once
+	sharedClient     *cloudcontrol.Client
+	sharedClientErr  error
+)
+
+// cloudControlClient returns the client every call shares, loading the
+// AWS configuration on first use. The configuration outlives ctx, so a
+// canceled first caller does not leave later ones without a client.
+func cloudControlClient(ctx context.Context) (*cloudcontrol.Client, error) {
+	sharedClientOnce.Do(func() {
+		awsCfg, err := config.LoadDefaultConfig(context.WithoutCancel(ctx))
+		if err != nil {
+			sharedClientErr = fmt.Errorf("load AWS configuration: %w", err)
+			return
+		}
+		sharedClient = cloudcontrol.NewFromConfig(awsCfg)
+	})
+	return sharedClient, sharedClientErr
+}
+
+// createResource creates a resource of typeName from desired's JSON and
+// returns its identifier once the request completes.
+func createResource(ctx context.Context, typeName string, desired any) (string, error) {
+	client, err := cloudControlClient(ctx)
+	if err != nil {
+		return "", err
+	}
+	state, err := json.Marshal(desired)
+	if err != nil {
+		return "", err
+	}
+	out, err := client.CreateResource(ctx, &cloudcontrol.CreateResourceInput{
+		TypeName:     aws.String(typeName),
+		DesiredState: aws.String(string(state)),
+	})
+	if err != nil {
+		return "", fmt.Errorf("create %s: %w", typeName, err)
+	}
+	event, err := waitForRequest(ctx, client, out.ProgressEvent)
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(event.Identifier), nil
+}
+
+// getResource decodes the current properties of the resource into out,
+// returning runtime.ErrNotFound when it no longer exists.
+func getResource(ctx context.Context, typeName, identifier string, out any) error {
+	client, err := cloudControlClient(ctx)
+	if err != nil {
+		return err
+	}
+	res, err := client.GetResource(ctx, &cloudcontrol.GetResourceInput{
+		TypeName:   aws.String(typeName),
+		Identifier: aws.String(identifier),
+	})
+	var notFound *types.ResourceNotFoundException
+	if errors.As(err, &notFound) {
+		return runtime.ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("read %s %s: %w", typeName, identifier, err)
+	}
+	return json.Unmarshal([]byte(aws.ToString(res.ResourceDescription.Properties)), out)
+}
+
+// updateResource patches every top-level property that differs between
+// prior and desired, and waits for the request to complete.
+func updateResource(ctx context.Context, typeName, identifier string, prior, desired any) error {
+	patch, err := patchDocument(prior, desired)
+	if err != nil || patch == nil {
+		return err
+	}
+	client, err := cloudControlClient(ctx)
+	if err != nil {
+		return err
+	}
+	out, err := client.UpdateResource(ctx, &cloudcontrol.UpdateResourceInput{
+		TypeName:      aws.String(typeName),
+		Identifier:    aws.String(identifier),
+		PatchDocument: aws.String(string(patch)),
+	})
+	if err != nil {
+		return fmt.Errorf("update %s %s: %w", typeName, identifier, err)
+	}
+	_, err = waitForRequest(ctx, client, out.ProgressEvent)
+	return err
+}
+
+// deleteResource deletes the resource and waits for the request to
+// complete. A resource that is already gone is not an error.
+func deleteResource(ctx context.Context, typeName, identifier string) error {
+	client, err := cloudControlClient(ctx)
+	if err != nil {
+		return err
+	}
+	out, err := client.DeleteResource(ctx, &cloudcontrol.DeleteResourceInput{
+		TypeName:   aws.String(typeName),
+		Identifier: aws.String(identifier),
+	})
+	var notFound *types.ResourceNotFoundException
+	if errors.As(err, &notFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("delete %s %s: %w", typeName, identifier, err)
+	}
+	_, err = waitForRequest(ctx, client, out.ProgressEvent)
+	return err
+}
+
+// waitForRequest polls a resource request until it succeeds or fails.
+func waitForRequest(
+	ctx context.Context,
+	client *cloudcontrol.Client,
+	event *types.ProgressEvent,
+) (*types.ProgressEvent, error) {
+	for {
+		switch event.OperationStatus {
+		case types.OperationStatusSuccess:
+			return event, nil
+		case types.OperationStatusFailed, types.OperationStatusCancelComplete:
+			return nil, fmt.Errorf("%s %s: %s: %s", event.Operation, aws.ToString(event.TypeName),
+				event.ErrorCode, aws.ToString(event.StatusMessage))
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(pollInterval):
+		}
+		out, err := client.GetResourceRequestStatus(ctx, &cloudcontrol.GetResourceRequestStatusInput{
+			RequestToken: event.RequestToken,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("request %s: %w", aws.ToString(event.RequestToken), err)
+		}
+		event = out.ProgressEvent
+	}
+}
+
+// patchDocument returns a JSON Patch that sets every top-level property
+// of desired that differs from prior and removes every one desired
+// leaves out, or nil when the two are the same.
+func patchDocument(prior, desired any) ([]byte, error) {
+	before, err := properties(prior)
+	if err != nil {
+		return nil, err
+	}
+	after, err := properties(desired)
+	if err != nil {
+		return nil, err
+	}
+	type operation struct {
+		Op    string          ` + "`json:\"op\"`" + `
+		Path  string          ` + "`json:\"path\"`" + `
+		Value json.RawMessage ` + "`json:\"value,omitempty\"`" + `
+	}
+	var ops []operation
+	for _, name := range sortedKeys(after) {
+		if old, ok := before[name]; !ok || !bytes.Equal(old, after[name]) {
+			ops = append(ops, operation{Op: "add", Path: "/" + name, Value: after[name]})
+		}
+	}
+	for _, name := range sortedKeys(before) {
+		if _, ok := after[name]; !ok {
+			ops = append(ops, operation{Op: "remove", Path: "/" + name})
+		}
+	}
+	if len(ops) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(ops)
+}
+
+func properties(v any) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var props map[string]json.RawMessage
+	err = json.Unmarshal(data, &props)
+	return props, err
+}
+
+func sortedKeys(m map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+`
@@ -33,6 +33,8 @@ type ConfigurationSchema struct {
 }
 
 // ResourceSchema describes one cloud resource type for code generation.
+// A CloudControl resource gets CRUD methods that call the AWS Cloud
+// Control API with CloudType as the type name instead of stubs.
 type ResourceSchema struct {
 	GoName            string
 	CloudType         string
@@ -41,6 +43,7 @@ type ResourceSchema struct {
 	OutputFields      []Field
 	CreateOnlyFields  []string
 	PrimaryIdentifier []string
+	CloudControl      bool
 }
 
 // DataSourceSchema describes one cloud data source for code generation.
@@ -52,12 +55,19 @@ type DataSourceSchema struct {
 	OutputFields []Field
 }
 
-// Field is one property of a resource or data source.
+// Field is one property of a resource or data source. CloudName is the
+// property's name in the cloud API's JSON, when it differs from Name.
+// A Sensitive field is tagged so its value is redacted; a Computed
+// output is one the provider recomputes when the resource's inputs
+// change.
 type Field struct {
 	Name        string
+	CloudName   string
 	GoType      string
 	Description string
 	Required    bool
+	Sensitive   bool
+	Computed    bool
 }
 
 // Input configures a generation run.
@@ -130,11 +140,23 @@ func Generate(ctx context.Context, adapter SchemaAdapter, in Input) (*Output, er
 		return nil, fmt.Errorf("mkdir %s: %w", outDir, err)
 	}
 
+	var goModRequires []string
 	if len(resources) > 0 {
 		resourcesDir := filepath.Join(outDir, "resources")
 		if err := os.MkdirAll(resourcesDir, 0o755); err != nil {
 			return generationFailure(output, fmt.Errorf("mkdir %s: %w", resourcesDir, err))
 		}
+		if slices.ContainsFunc(resources, func(rs ResourceSchema) bool { return rs.CloudControl }) {
+			src, err := CloudControlFile(in.From)
+			if err != nil {
+				return generationFailure(output, fmt.Errorf("render %s: %w", cloudControlFile, err))
+			}
+			path := filepath.Join(resourcesDir, cloudControlFile)
+			if err := write(path, src); err != nil {
+				return generationFailure(output, fmt.Errorf("write %s: %w", path, err))
+			}
+			goModRequires = cloudControlRequires
+		}
 		for _, rs := range resources {
 			src, err := ResourceFile(rs, in.From)
 			if err != nil {
@@ -186,7 +208,7 @@ func Generate(ctx context.Context, adapter SchemaAdapter, in Input) (*Output, er
 		return generationFailure(output, fmt.Errorf("write library.go: %w", err))
 	}
 
-	goModSrc, err := GoMod(in.ModulePath, in.ReplaceUnobin, in.UnobinVersion)
+	goModSrc, err := GoMod(in.ModulePath, in.ReplaceUnobin, in.UnobinVersion, goModRequires...)
 	if err != nil {
 		return generationFailure(output, fmt.Errorf("render go.mod: %w", err))
 	}
@@ -231,11 +253,15 @@ func staleGeneratedFiles(outDir string, desired map[string]bool) ([]string, erro
 		}
 		candidates = append(candidates, matches...)
 	}
-	configurationPath := filepath.Join(outDir, "configuration.go")
-	if _, err := os.Lstat(configurationPath); err == nil {
-		candidates = append(candidates, configurationPath)
-	} else if !os.IsNotExist(err) {
-		return nil, err
+	for _, path := range []string{
+		filepath.Join(outDir, "configuration.go"),
+		filepath.Join(outDir, "resources", cloudControlFile),
+	} {
+		if _, err := os.Lstat(path); err == nil {
+			candidates = append(candidates, path)
+		} else if !os.IsNotExist(err) {
+			return nil, err
+		}
 	}
 	stale := candidates[:0]
 	for _, path := range candidates {
@@ -6,6 +6,7 @@ import (
 	"go/token"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -201,3 +202,37 @@ func TestGenerateNoResources(t *testing.T) {
 		t.Error("expected error when no resources or data sources found")
 	}
 }
+
+func TestGenerateCloudControlHelper(t *testing.T) {
+	dir := t.TempDir()
+	rs := sampleResourceSchema()
+	rs.CloudControl = true
+	adapter := &mockAdapter{name: "aws", resources: []ResourceSchema{rs}}
+	if _, err := Generate(context.Background(), adapter, Input{
+		OutDir: dir, ModulePath: "example.com/aws", From: "cfn",
+	}); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	helper := filepath.Join(dir, "resources", "cloud_control.go")
+	if _, err := os.Stat(helper); err != nil {
+		t.Fatalf("expected %s: %v", helper, err)
+	}
+	goMod, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		t.Fatalf("read go.mod: %v", err)
+	}
+	if !strings.Contains(string(goMod), "github.com/aws/aws-sdk-go-v2/service/cloudcontrol") {
+		t.Errorf("expected go.mod to require cloudcontrol, got:\n%s", goMod)
+	}
+
+	// Stub resources no longer need the helper.
+	adapter.resources = []ResourceSchema{sampleResourceSchema()}
+	if _, err := Generate(context.Background(), adapter, Input{
+		OutDir: dir, ModulePath: "example.com/aws", From: "tf",
+	}); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if _, err := os.Stat(helper); !os.IsNotExist(err) {
+		t.Errorf("expected %s to be removed (err=%v)", helper, err)
+	}
+}
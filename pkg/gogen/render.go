@@ -22,7 +22,10 @@ func ResourceFile(rs ResourceSchema, from string) ([]byte, error) {
 	b.WriteString("package resources\n\n")
 	b.WriteString("import (\n")
 	b.WriteString(`	"context"` + "\n")
-	b.WriteString(`	"fmt"` + "\n\n")
+	if !rs.CloudControl {
+		b.WriteString(`	"fmt"` + "\n")
+	}
+	b.WriteString("\n")
 	b.WriteString(`	"github.com/cloudboss/unobin/pkg/runtime"` + "\n")
 	b.WriteString(")\n\n")
 
@@ -30,6 +33,7 @@ func ResourceFile(rs ResourceSchema, from string) ([]byte, error) {
 		for _, line := range wordWrap(sanitizeComment(rs.Description), 80) {
 			fmt.Fprintf(&b, "// %s\n", line)
 		}
+		b.WriteString("//\n")
 	}
 	fmt.Fprintf(&b, "// %s implements runtime.Resource for %s.\n", rs.GoName, rs.CloudType)
 	fmt.Fprintf(&b, "type %s struct {\n", rs.GoName)
@@ -44,8 +48,7 @@ func ResourceFile(rs ResourceSchema, from string) ([]byte, error) {
 		if !f.Required {
 			goType = PointerType(goType)
 		}
-		tag := UBTag(f.Name)
-		fmt.Fprintf(&b, "\t%s %s `ub:\"%s\"`\n", f.Name, goType, tag)
+		fmt.Fprintf(&b, "\t%s %s `%s`\n", f.Name, goType, resourceFieldTag(rs, f, !f.Required))
 	}
 	if len(rs.InputFields) == 0 {
 		b.WriteString("\t// No writable fields\n")
@@ -62,10 +65,11 @@ func ResourceFile(rs ResourceSchema, from string) ([]byte, error) {
 		if f.Description != "" {
 			fmt.Fprintf(&b, "\t// %s\n", sanitizeComment(f.Description))
 		}
-		tag := UBTag(f.Name)
-		fmt.Fprintf(&b, "\t%s %s `ub:\"%s\"`\n", f.Name, f.GoType, tag)
+		fmt.Fprintf(&b, "\t%s %s `%s`\n", f.Name, f.GoType, resourceFieldTag(rs, f, false))
 	}
-	if len(rs.OutputFields) == 0 {
+	if rs.CloudControl {
+		writeCloudControlIdentifier(&b, rs)
+	} else if len(rs.OutputFields) == 0 {
 		b.WriteString("\t// No read-only fields\n")
 	}
 	b.WriteString("}\n\n")
@@ -85,6 +89,13 @@ func ResourceFile(rs ResourceSchema, from string) ([]byte, error) {
 	}
 	b.WriteString("}\n\n")
 
+	writePlanModifier(&b, rs)
+
+	if rs.CloudControl {
+		writeCloudControlMethods(&b, rs)
+		return formatSource(b.Bytes())
+	}
+
 	outPtr := "*" + outName
 	for _, op := range []struct {
 		method, params, returns string
@@ -101,12 +112,35 @@ func ResourceFile(rs ResourceSchema, from string) ([]byte, error) {
 		b.WriteString(writeStub(rs.GoName, op.method, op.params, op.returns))
 	}
 
-	raw := b.Bytes()
-	out, err := format.Source(raw)
-	if err != nil {
-		return nil, fmt.Errorf("format: %w\n\nraw source:\n%s", err, raw)
+	return formatSource(b.Bytes())
+}
+
+// fieldTag returns the ub struct tag for a field, marking a sensitive
+// field so its value is redacted in plans and output.
+func fieldTag(f Field) string {
+	name := UBTag(f.Name)
+	if f.Sensitive {
+		name += ",sensitive"
 	}
-	return out, nil
+	return fmt.Sprintf("ub:%q", name)
+}
+
+// resourceFieldTag returns the struct tag for a resource field. Cloud
+// Control resources marshal to the API's JSON, so their fields also
+// carry json tags naming the cloud property.
+func resourceFieldTag(rs ResourceSchema, f Field, omitEmpty bool) string {
+	tag := fieldTag(f)
+	if !rs.CloudControl {
+		return tag
+	}
+	name := f.CloudName
+	if name == "" {
+		name = f.Name
+	}
+	if omitEmpty {
+		name += ",omitempty"
+	}
+	return tag + fmt.Sprintf(" json:%q", name)
 }
 
 // DataSourceFile renders a Go source file for one data source into the data/
@@ -125,6 +159,7 @@ func DataSourceFile(ds DataSourceSchema, from string) ([]byte, error) {
 		for _, line := range wordWrap(sanitizeComment(ds.Description), 80) {
 			fmt.Fprintf(&b, "// %s\n", line)
 		}
+		b.WriteString("//\n")
 	}
 	fmt.Fprintf(&b, "// %s implements runtime.DataSource for %s.\n", ds.GoName, ds.CloudType)
 	fmt.Fprintf(&b, "type %s struct {\n", ds.GoName)
@@ -139,8 +174,7 @@ func DataSourceFile(ds DataSourceSchema, from string) ([]byte, error) {
 		if !f.Required {
 			goType = PointerType(goType)
 		}
-		tag := UBTag(f.Name)
-		fmt.Fprintf(&b, "\t%s %s `ub:\"%s\"`\n", f.Name, goType, tag)
+		fmt.Fprintf(&b, "\t%s %s `%s`\n", f.Name, goType, fieldTag(f))
 	}
 	b.WriteString("}\n\n")
 
@@ -154,8 +188,7 @@ func DataSourceFile(ds DataSourceSchema, from string) ([]byte, error) {
 		if f.Description != "" {
 			fmt.Fprintf(&b, "\t// %s\n", sanitizeComment(f.Description))
 		}
-		tag := UBTag(f.Name)
-		fmt.Fprintf(&b, "\t%s %s `ub:\"%s\"`\n", f.Name, f.GoType, tag)
+		fmt.Fprintf(&b, "\t%s %s `%s`\n", f.Name, f.GoType, fieldTag(f))
 	}
 	b.WriteString("}\n\n")
 
@@ -164,12 +197,7 @@ func DataSourceFile(ds DataSourceSchema, from string) ([]byte, error) {
 	b.WriteString("\treturn nil, fmt.Errorf(\"read not implemented\")\n")
 	b.WriteString("}\n")
 
-	raw := b.Bytes()
-	out, err := format.Source(raw)
-	if err != nil {
-		return nil, fmt.Errorf("format: %w\n\nraw source:\n%s", err, raw)
-	}
-	return out, nil
+	return formatSource(b.Bytes())
 }
 
 // LibraryFile renders a library.go that registers all resources and data
@@ -271,6 +299,7 @@ func ConfigurationFile(cs ConfigurationSchema, packageName, from string) ([]byte
 		for _, line := range wordWrap(sanitizeComment(cs.Description), 80) {
 			fmt.Fprintf(&b, "// %s\n", line)
 		}
+		b.WriteString("//\n")
 	}
 	fmt.Fprintf(&b, "// %s is the operator-facing body for configurations such as\n", cs.GoName)
 	fmt.Fprintf(&b, "// %s { ... } or name: %s { ... }.\n", packageName, packageName)
@@ -293,12 +322,7 @@ func ConfigurationFile(cs ConfigurationSchema, packageName, from string) ([]byte
 	}
 	b.WriteString("}\n")
 
-	raw := b.Bytes()
-	out, err := format.Source(raw)
-	if err != nil {
-		return nil, fmt.Errorf("format: %w\n\nraw source:\n%s", err, raw)
-	}
-	return out, nil
+	return formatSource(b.Bytes())
 }
 
 // configGoType maps a SchemaAdapter Go type to the supported plain
@@ -322,8 +346,9 @@ func configGoType(goType string) string {
 // pins the unobin requirement when it is a release version; otherwise
 // the v0.0.0 placeholder stands in, which only resolves with a
 // replace. When replaceUnobin is non-empty, its absolute path is used
-// to add a replace directive for the unobin dependency.
-func GoMod(modulePath, replaceUnobin, unobinVersion string) ([]byte, error) {
+// to add a replace directive for the unobin dependency. Each of
+// requires is a further "<module> <version>" requirement.
+func GoMod(modulePath, replaceUnobin, unobinVersion string, requires ...string) ([]byte, error) {
 	if !semver.IsValid(unobinVersion) {
 		unobinVersion = "v0.0.0"
 	}
@@ -331,6 +356,9 @@ func GoMod(modulePath, replaceUnobin, unobinVersion string) ([]byte, error) {
 	fmt.Fprintf(&b, "module %s\n\n", modulePath)
 	b.WriteString("go 1.26\n\n")
 	b.WriteString("require (\n")
+	for _, r := range requires {
+		fmt.Fprintf(&b, "\t%s\n", r)
+	}
 	fmt.Fprintf(&b, "\tgithub.com/cloudboss/unobin %s\n", unobinVersion)
 	b.WriteString(")\n")
 	if replaceUnobin != "" {
@@ -340,6 +368,35 @@ func GoMod(modulePath, replaceUnobin, unobinVersion string) ([]byte, error) {
 	return b.Bytes(), nil
 }
 
+// writePlanModifier renders a ModifyResourcePlan that marks the
+// resource's computed outputs unknown when an input changes, so nodes
+// reading them wait for apply rather than planning with stale values.
+func writePlanModifier(b *bytes.Buffer, rs ResourceSchema) {
+	var computed []string
+	for _, f := range rs.OutputFields {
+		if f.Computed && f.Name != "" {
+			computed = append(computed, UBTag(f.Name))
+		}
+	}
+	if len(computed) == 0 {
+		return
+	}
+	outPtr := "*" + rs.GoName + "Output"
+	fmt.Fprintf(b, "func (r *%s) ModifyResourcePlan(\n", rs.GoName)
+	fmt.Fprintf(b, "\treq runtime.ResourcePlanRequest[%s, %s, any],\n", rs.GoName, outPtr)
+	b.WriteString("\tresp *runtime.ResourcePlanResponse,\n")
+	b.WriteString(") error {\n")
+	b.WriteString("\tif req.HasPriorState && runtime.Changed(req.PriorInputs, req.CurrentInputs) {\n")
+	b.WriteString("\t\tresp.MarkOutputUnknown(\n")
+	for _, name := range computed {
+		fmt.Fprintf(b, "\t\t\t%q,\n", name)
+	}
+	b.WriteString("\t\t)\n")
+	b.WriteString("\t}\n")
+	b.WriteString("\treturn nil\n")
+	b.WriteString("}\n\n")
+}
+
 func writeGeneratedComment(b *bytes.Buffer, from string) {
 	if from == "" {
 		from = "unknown"
@@ -361,6 +418,14 @@ func writeStub(goName, method, params, returns string) string {
 	return b.String()
 }
 
+func formatSource(raw []byte) ([]byte, error) {
+	out, err := format.Source(raw)
+	if err != nil {
+		return nil, fmt.Errorf("format: %w\n\nraw source:\n%s", err, raw)
+	}
+	return out, nil
+}
+
 func escapeQuote(s string) string {
 	return strings.ReplaceAll(s, `"`, `\"`)
 }
@@ -42,6 +42,7 @@ func TestResourceFileProducesParseableGo(t *testing.T) {
 	checks := []string{
 		"Generated by unobin generate golibrary --from tf",
 		"package resources",
+		"// An S3 bucket for storing objects\n//\n// S3Bucket implements runtime.Resource for ",
 		"type S3Bucket struct {",
 		"type S3BucketOutput struct {",
 		"BucketName string `ub:\"bucket-name\"`",
@@ -410,3 +411,131 @@ func TestGoModDevFallsBackToPlaceholder(t *testing.T) {
 		t.Errorf("expected the v0.0.0 placeholder for a dev build, got:\n%s", string(src))
 	}
 }
+
+func TestResourceFileCloudControl(t *testing.T) {
+	rs := sampleResourceSchema()
+	rs.CloudControl = true
+	rs.InputFields[0].CloudName = "BucketName"
+
+	src, err := ResourceFile(rs, "cfn")
+	if err != nil {
+		t.Fatalf("ResourceFile: %v", err)
+	}
+	s := string(src)
+	checks := []string{
+		"BucketName string `ub:\"bucket-name\" json:\"BucketName\"`",
+		"Tags *map[string]string `ub:\"tags\" json:\"Tags,omitempty\"`",
+		"Arn string `ub:\"arn\" json:\"Arn\"`",
+		"// the resource's BucketName.\n" +
+			"\tCloudControlIdentifier string `ub:\"cloud-control-identifier\" json:\"-\"`",
+		`id, err := createResource(ctx, "AWS::S3::Bucket", r)`,
+		`getResource(ctx, "AWS::S3::Bucket", out.CloudControlIdentifier, out)`,
+		`updateResource(ctx, "AWS::S3::Bucket", id, prior.Inputs, r)`,
+		`return deleteResource(ctx, "AWS::S3::Bucket", priorOutputs.CloudControlIdentifier)`,
+	}
+	for _, c := range checks {
+		if !strings.Contains(s, c) {
+			t.Errorf("expected generated source to contain %q\n\n%s", c, s)
+		}
+	}
+	if strings.Contains(s, "not implemented") || strings.Contains(s, `"fmt"`) {
+		t.Errorf("expected Cloud Control methods instead of stubs:\n%s", s)
+	}
+}
+
+func TestCloudControlFileProducesParseableGo(t *testing.T) {
+	src, err := CloudControlFile("cfn")
+	if err != nil {
+		t.Fatalf("CloudControlFile: %v", err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "cloud_control.go", src, parser.AllErrors); err != nil {
+		t.Fatalf("generated source does not parse: %v", err)
+	}
+	if !strings.HasPrefix(string(src), "// Generated by unobin generate golibrary --from cfn.") {
+		t.Errorf("expected a generated comment, got:\n%s", src)
+	}
+	if n := strings.Count(string(src), "config.LoadDefaultConfig("); n != 1 {
+		t.Errorf("expected the AWS configuration to load in one place, found %d", n)
+	}
+	if !strings.Contains(string(src), "sharedClientOnce.Do(") {
+		t.Errorf("expected the Cloud Control client to be built once, got:\n%s", src)
+	}
+}
+
+func TestGoModExtraRequires(t *testing.T) {
+	src, err := GoMod("example.com/libraries/testmod", "", "v0.6.0",
+		"github.com/aws/aws-sdk-go-v2/service/cloudcontrol v1.31.1")
+	if err != nil {
+		t.Fatalf("GoMod: %v", err)
+	}
+	want := "require (\n" +
+		"\tgithub.com/aws/aws-sdk-go-v2/service/cloudcontrol v1.31.1\n" +
+		"\tgithub.com/cloudboss/unobin v0.6.0\n" +
+		")\n"
+	if !strings.Contains(string(src), want) {
+		t.Errorf("expected go.mod to contain %q, got:\n%s", want, src)
+	}
+}
+
+func TestResourceFileSensitiveAndComputed(t *testing.T) {
+	rs := sampleResourceSchema()
+	rs.InputFields = append(rs.InputFields,
+		Field{Name: "Password", GoType: "string", Sensitive: true})
+	rs.OutputFields[0].Computed = true
+	rs.OutputFields[1].Computed = true
+	rs.OutputFields = append(rs.OutputFields,
+		Field{Name: "Secret", GoType: "string", Sensitive: true, Computed: true})
+
+	src, err := ResourceFile(rs, "tf")
+	if err != nil {
+		t.Fatalf("ResourceFile: %v", err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "s3_bucket.go", src, parser.AllErrors); err != nil {
+		t.Fatalf("generated source does not parse: %v\n\n%s", err, src)
+	}
+	s := string(src)
+	checks := []string{
+		"`ub:\"password,sensitive\"`",
+		"`ub:\"secret,sensitive\"`",
+		"func (r *S3Bucket) ModifyResourcePlan(\n" +
+			"\treq runtime.ResourcePlanRequest[S3Bucket, *S3BucketOutput, any],\n" +
+			"\tresp *runtime.ResourcePlanResponse,\n" +
+			") error {\n" +
+			"\tif req.HasPriorState && runtime.Changed(req.PriorInputs, req.CurrentInputs) {\n" +
+			"\t\tresp.MarkOutputUnknown(\n" +
+			"\t\t\t\"arn\",\n" +
+			"\t\t\t\"domain-name\",\n" +
+			"\t\t\t\"secret\",\n" +
+			"\t\t)\n",
+	}
+	for _, c := range checks {
+		if !strings.Contains(s, c) {
+			t.Errorf("expected generated source to contain %q\n\n%s", c, s)
+		}
+	}
+}
+
+func TestResourceFileNoPlanModifierWithoutComputed(t *testing.T) {
+	src, err := ResourceFile(sampleResourceSchema(), "tf")
+	if err != nil {
+		t.Fatalf("ResourceFile: %v", err)
+	}
+	if strings.Contains(string(src), "ModifyResourcePlan") {
+		t.Errorf("expected no plan modifier without computed outputs:\n%s", src)
+	}
+}
+
+func TestDataSourceFileSensitive(t *testing.T) {
+	src, err := DataSourceFile(DataSourceSchema{
+		GoName:       "Secret",
+		CloudType:    "aws_secret",
+		InputFields:  []Field{{Name: "Name", GoType: "string", Required: true}},
+		OutputFields: []Field{{Name: "Value", GoType: "string", Sensitive: true}},
+	}, "tf")
+	if err != nil {
+		t.Fatalf("DataSourceFile: %v", err)
+	}
+	if !strings.Contains(string(src), "Value string `ub:\"value,sensitive\"`") {
+		t.Errorf("expected a sensitive output tag:\n%s", src)
+	}
+}
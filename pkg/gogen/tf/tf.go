@@ -63,6 +63,42 @@ terraform {
 	return out, nil
 }
 
+// FileFetcher reads a schema saved from "terraform providers schema
+// -json", such as a checked-in provider-schema.json, so generation needs
+// neither the terraform binary nor network access.
+type FileFetcher struct {
+	Path string
+}
+
+func (f FileFetcher) FetchSchema(context.Context, string, string, string) ([]byte, error) {
+	return os.ReadFile(f.Path)
+}
+
+// Provider returns the source of the one provider the file holds, such
+// as hashicorp/aws for registry.terraform.io/hashicorp/aws.
+func (f FileFetcher) Provider() (string, error) {
+	data, err := os.ReadFile(f.Path)
+	if err != nil {
+		return "", err
+	}
+	parsed, err := parseProviderSchema(data)
+	if err != nil {
+		return "", fmt.Errorf("parse %s: %w", f.Path, err)
+	}
+	if len(parsed.ProviderSchemas) != 1 {
+		return "", fmt.Errorf("%s holds %d provider schemas; choose one with --provider",
+			f.Path, len(parsed.ProviderSchemas))
+	}
+	for key := range parsed.ProviderSchemas {
+		parts := strings.Split(key, "/")
+		if len(parts) > 2 {
+			parts = parts[len(parts)-2:]
+		}
+		return strings.Join(parts, "/"), nil
+	}
+	return "", nil
+}
+
 // Adapter implements gogen.SchemaAdapter backed by TF provider schemas.
 // provider is the fully-qualified TF registry source (e.g. "hashicorp/aws"
 // or "ansible/ansible"). The part after the final slash becomes the local
@@ -92,23 +128,11 @@ func (a *Adapter) FetchResources(
 	ctx context.Context,
 	resources []string,
 ) ([]gogen.ResourceSchema, error) {
-	data, err := a.Fetcher.FetchSchema(ctx, a.source, a.localName, a.version)
+	ps, err := a.providerSchema(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	parsed, err := parseProviderSchema(data)
-	if err != nil {
-		return nil, fmt.Errorf("parse provider schema: %w", err)
-	}
-
-	var providerName string
-	for k := range parsed.ProviderSchemas {
-		providerName = k
-		break
-	}
-	ps := parsed.ProviderSchemas[providerName]
-
 	prefixes := resourcePrefixes(a.localName, resources)
 
 	var all []gogen.ResourceSchema
@@ -132,22 +156,10 @@ func (a *Adapter) FetchResources(
 // configuration attributes; the renderer then skips emitting a
 // ProviderConfig struct and library Configuration entry.
 func (a *Adapter) FetchConfiguration(ctx context.Context) (*gogen.ConfigurationSchema, error) {
-	data, err := a.Fetcher.FetchSchema(ctx, a.source, a.localName, a.version)
+	ps, err := a.providerSchema(ctx)
 	if err != nil {
 		return nil, err
 	}
-
-	parsed, err := parseProviderSchema(data)
-	if err != nil {
-		return nil, fmt.Errorf("parse provider schema: %w", err)
-	}
-
-	var providerName string
-	for k := range parsed.ProviderSchemas {
-		providerName = k
-		break
-	}
-	ps := parsed.ProviderSchemas[providerName]
 	if ps.Provider == nil || len(ps.Provider.Block.Attributes) == 0 {
 		return nil, nil
 	}
@@ -185,23 +197,11 @@ func (a *Adapter) FetchDataSources(
 	ctx context.Context,
 	resources []string,
 ) ([]gogen.DataSourceSchema, error) {
-	data, err := a.Fetcher.FetchSchema(ctx, a.source, a.localName, a.version)
+	ps, err := a.providerSchema(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	parsed, err := parseProviderSchema(data)
-	if err != nil {
-		return nil, fmt.Errorf("parse provider schema: %w", err)
-	}
-
-	var providerName string
-	for k := range parsed.ProviderSchemas {
-		providerName = k
-		break
-	}
-	ps := parsed.ProviderSchemas[providerName]
-
 	prefixes := resourcePrefixes(a.localName, resources)
 
 	var all []gogen.DataSourceSchema
@@ -219,6 +219,33 @@ func (a *Adapter) FetchDataSources(
 	return all, nil
 }
 
+// providerSchema fetches the schema and picks the adapter's provider out
+// of it: the one whose address ends in the provider source, or the only
+// one there is.
+func (a *Adapter) providerSchema(ctx context.Context) (tfProvSchema, error) {
+	data, err := a.Fetcher.FetchSchema(ctx, a.source, a.localName, a.version)
+	if err != nil {
+		return tfProvSchema{}, err
+	}
+
+	parsed, err := parseProviderSchema(data)
+	if err != nil {
+		return tfProvSchema{}, fmt.Errorf("parse provider schema: %w", err)
+	}
+
+	for address, ps := range parsed.ProviderSchemas {
+		if address == a.source || strings.HasSuffix(address, "/"+a.source) {
+			return ps, nil
+		}
+	}
+	if len(parsed.ProviderSchemas) == 1 {
+		for _, ps := range parsed.ProviderSchemas {
+			return ps, nil
+		}
+	}
+	return tfProvSchema{}, fmt.Errorf("provider schema has no provider %q", a.source)
+}
+
 // resourcePrefixes maps short service names to TF resource name prefixes.
 func resourcePrefixes(provider string, resources []string) []string {
 	if len(resources) == 0 {
@@ -261,8 +288,9 @@ type tfResourceSchema struct {
 }
 
 type tfBlock struct {
-	Attributes map[string]tfAttribute `json:"attributes"`
-	BlockTypes map[string]tfBlockType `json:"block_types"`
+	Attributes  map[string]tfAttribute `json:"attributes"`
+	BlockTypes  map[string]tfBlockType `json:"block_types"`
+	Description string                 `json:"description"`
 }
 
 type tfAttribute struct {
@@ -283,33 +311,9 @@ type tfBlockType struct {
 }
 
 func convertDataSource(name string, ds tfResourceSchema) (gogen.DataSourceSchema, error) {
-	goName := tfNameToGo(name)
-
-	var inputFields, outputFields []gogen.Field
-
-	for attrName, attr := range ds.Block.Attributes {
-		goField := tfAttrNameToGo(attrName)
-		if goField == "" {
-			continue
-		}
-		goType := tfTypeToGo(attr.Type)
-
-		field := gogen.Field{
-			Name:        goField,
-			GoType:      goType,
-			Description: attr.Description,
-			Required:    attr.Required,
-		}
-
-		if attr.Computed && !attr.Optional && !attr.Required {
-			outputFields = append(outputFields, field)
-		} else {
-			inputFields = append(inputFields, field)
-		}
-	}
-
+	inputFields, outputFields, _ := convertBlock(ds.Block)
 	return gogen.DataSourceSchema{
-		GoName:       goName,
+		GoName:       tfNameToGo(name),
 		CloudType:    name,
 		Description:  "",
 		InputFields:  inputFields,
@@ -326,26 +330,39 @@ func parseProviderSchema(data []byte) (*tfProviderSchema, error) {
 }
 
 func convertResource(name string, rs tfResourceSchema) (gogen.ResourceSchema, error) {
-	goName := tfNameToGo(name)
+	inputFields, outputFields, createOnlyFields := convertBlock(rs.Block)
+	return gogen.ResourceSchema{
+		GoName:           tfNameToGo(name),
+		CloudType:        name,
+		Description:      "",
+		InputFields:      inputFields,
+		OutputFields:     outputFields,
+		CreateOnlyFields: createOnlyFields,
+	}, nil
+}
 
+// convertBlock splits a block's attributes into input fields and the
+// computed-only output fields, sorted by name, and returns the inputs
+// that force a new resource. Nested blocks are inputs: one holding at
+// most one item is an object, any other a list or map of objects.
+func convertBlock(block tfBlock) ([]gogen.Field, []gogen.Field, []string) {
 	var inputFields, outputFields []gogen.Field
 	var createOnlyFields []string
 
-	for attrName, attr := range rs.Block.Attributes {
+	for attrName, attr := range block.Attributes {
 		goField := tfAttrNameToGo(attrName)
 		if goField == "" {
 			continue
 		}
-		goType := tfTypeToGo(attr.Type)
-
 		field := gogen.Field{
 			Name:        goField,
-			GoType:      goType,
+			GoType:      tfTypeToGo(attr.Type),
 			Description: attr.Description,
 			Required:    attr.Required,
+			Sensitive:   attr.Sensitive,
 		}
-
 		if attr.Computed && !attr.Optional && !attr.Required {
+			field.Computed = true
 			outputFields = append(outputFields, field)
 		} else {
 			inputFields = append(inputFields, field)
@@ -355,14 +372,53 @@ func convertResource(name string, rs tfResourceSchema) (gogen.ResourceSchema, er
 		}
 	}
 
-	return gogen.ResourceSchema{
-		GoName:           goName,
-		CloudType:        name,
-		Description:      "",
-		InputFields:      inputFields,
-		OutputFields:     outputFields,
-		CreateOnlyFields: createOnlyFields,
-	}, nil
+	for blockName, bt := range block.BlockTypes {
+		goField := tfAttrNameToGo(blockName)
+		if goField == "" {
+			continue
+		}
+		inputFields = append(inputFields, gogen.Field{
+			Name:        goField,
+			GoType:      blockTypeToGo(bt),
+			Description: bt.Block.Description,
+			Required:    bt.MinItems > 0,
+			Sensitive:   blockSensitive(bt.Block),
+		})
+	}
+
+	byName := func(a, b gogen.Field) int { return cmp.Compare(a.Name, b.Name) }
+	slices.SortFunc(inputFields, byName)
+	slices.SortFunc(outputFields, byName)
+	slices.Sort(createOnlyFields)
+	return inputFields, outputFields, createOnlyFields
+}
+
+// blockTypeToGo converts a nested block type to a Go type string.
+func blockTypeToGo(bt tfBlockType) string {
+	switch {
+	case bt.NestingMode == "single" || bt.NestingMode == "group" || bt.MaxItems == 1:
+		return "map[string]any"
+	case bt.NestingMode == "map":
+		return "map[string]map[string]any"
+	default:
+		return "[]map[string]any"
+	}
+}
+
+// blockSensitive reports whether a nested block holds a sensitive
+// attribute at any depth, which makes the whole block sensitive.
+func blockSensitive(block tfBlock) bool {
+	for _, attr := range block.Attributes {
+		if attr.Sensitive {
+			return true
+		}
+	}
+	for _, bt := range block.BlockTypes {
+		if blockSensitive(bt.Block) {
+			return true
+		}
+	}
+	return false
 }
 
 // tfTypeToGo converts a TF attribute type to a Go type string.
@@ -5,7 +5,11 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/cloudboss/unobin/pkg/gogen"
 )
 
 type staticFetcher struct {
@@ -344,3 +348,122 @@ func TestConvertDataSource(t *testing.T) {
 		t.Errorf("expected 1 output field, got %d", len(got.OutputFields))
 	}
 }
+
+func TestFileFetcher(t *testing.T) {
+	fetcher := FileFetcher{Path: filepath.Join("testdata", "provider_schema.json")}
+	provider, err := fetcher.Provider()
+	if err != nil {
+		t.Fatalf("Provider: %v", err)
+	}
+	if provider != "hashicorp/aws" {
+		t.Errorf("Provider() = %q, want hashicorp/aws", provider)
+	}
+
+	adapter := NewAdapter(fetcher, provider, "")
+	resources, err := adapter.FetchResources(context.Background(), []string{"db"})
+	if err != nil {
+		t.Fatalf("FetchResources: %v", err)
+	}
+	if len(resources) != 1 || resources[0].GoName != "DbInstance" {
+		t.Fatalf("expected DbInstance, got %+v", resources)
+	}
+
+	_, err = FileFetcher{Path: filepath.Join("testdata", "missing.json")}.Provider()
+	if err == nil {
+		t.Error("expected an error for a missing schema file")
+	}
+}
+
+func TestProviderSchemaPicksSource(t *testing.T) {
+	schema := `{
+	  "format_version": "1.0",
+	  "provider_schemas": {
+	    "registry.terraform.io/hashicorp/random": {
+	      "resource_schemas": {"random_id": {"block": {}}}
+	    },
+	    "registry.terraform.io/hashicorp/aws": {
+	      "resource_schemas": {"aws_s3_bucket": {"block": {}}}
+	    }
+	  }
+	}`
+	fetcher := &staticFetcher{data: []byte(schema)}
+	for provider, want := range map[string]string{
+		"hashicorp/aws":    "S3Bucket",
+		"aws":              "S3Bucket",
+		"hashicorp/random": "Id",
+	} {
+		resources, err := NewAdapter(fetcher, provider, "").FetchResources(context.Background(), nil)
+		if err != nil {
+			t.Fatalf("FetchResources(%s): %v", provider, err)
+		}
+		if len(resources) != 1 || resources[0].GoName != want {
+			t.Errorf("FetchResources(%s) = %+v, want %s", provider, resources, want)
+		}
+	}
+	_, err := NewAdapter(fetcher, "hashicorp/google", "").FetchResources(context.Background(), nil)
+	if err == nil || !strings.Contains(err.Error(), `provider schema has no provider "hashicorp/google"`) {
+		t.Errorf("expected a missing provider error, got %v", err)
+	}
+}
+
+func TestConvertNestedSensitiveComputed(t *testing.T) {
+	adapter := NewAdapter(
+		FileFetcher{Path: filepath.Join("testdata", "provider_schema.json")}, "hashicorp/aws", "")
+	resources, err := adapter.FetchResources(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("FetchResources: %v", err)
+	}
+	rs := resources[0]
+
+	wantInputs := []gogen.Field{
+		{
+			Name: "BlueGreenUpdate", GoType: "map[string]any",
+			Description: "Blue/green update settings",
+		},
+		{Name: "CaCertIdentifier", GoType: "string"},
+		{Name: "Identifier", GoType: "string", Description: "Name of the DB instance", Required: true},
+		{
+			Name: "Password", GoType: "string", Description: "Password for the master DB user",
+			Sensitive: true,
+		},
+		{Name: "RestoreToPointInTime", GoType: "map[string]any", Required: true},
+		{Name: "S3Import", GoType: "[]map[string]any", Sensitive: true},
+		{Name: "Timeouts", GoType: "map[string]any"},
+	}
+	if !reflect.DeepEqual(rs.InputFields, wantInputs) {
+		t.Errorf("InputFields:\n got %+v\nwant %+v", rs.InputFields, wantInputs)
+	}
+
+	wantOutputs := []gogen.Field{
+		{
+			Name: "Address", GoType: "string", Description: "Hostname of the DB instance",
+			Computed: true,
+		},
+		{
+			Name: "MasterUserSecret", GoType: "[]map[string]any",
+			Description: "Secret holding the master user password", Sensitive: true, Computed: true,
+		},
+	}
+	if !reflect.DeepEqual(rs.OutputFields, wantOutputs) {
+		t.Errorf("OutputFields:\n got %+v\nwant %+v", rs.OutputFields, wantOutputs)
+	}
+}
+
+func TestBlockTypeToGo(t *testing.T) {
+	tests := []struct {
+		bt   tfBlockType
+		want string
+	}{
+		{tfBlockType{NestingMode: "single"}, "map[string]any"},
+		{tfBlockType{NestingMode: "group"}, "map[string]any"},
+		{tfBlockType{NestingMode: "list", MaxItems: 1}, "map[string]any"},
+		{tfBlockType{NestingMode: "list"}, "[]map[string]any"},
+		{tfBlockType{NestingMode: "set", MaxItems: 3}, "[]map[string]any"},
+		{tfBlockType{NestingMode: "map"}, "map[string]map[string]any"},
+	}
+	for _, tt := range tests {
+		if got := blockTypeToGo(tt.bt); got != tt.want {
+			t.Errorf("blockTypeToGo(%+v) = %q, want %q", tt.bt, got, tt.want)
+		}
+	}
+}
@@ -194,6 +194,7 @@ func (c *analysisContext) readSchema(
 		return nil, err
 	}
 	maps.Copy(schema.Functions, extractFunctions(libraryFunc, c.root.files, &c.errs))
+	schema.FunctionDescriptions = extractFunctionDescriptions(libraryFunc)
 	if len(c.errs) > 0 {
 		return nil, errors.Join(c.errs...)
 	}
@@ -1095,9 +1096,43 @@ func extractFunctions(
 	fn *ast.FuncDecl, files []*ast.File, errs *[]error,
 ) map[string]typecheck.FuncSig {
 	out := map[string]typecheck.FuncSig{}
+	for _, entry := range functionEntries(fn) {
+		if name, ok := stringLit(entry.Key); ok {
+			out[name] = functionSig(name, entry.Value, files, errs)
+		}
+	}
+	return out
+}
+
+// extractFunctionDescriptions returns the description each MakeFunc
+// registration in the Functions map passes as a string literal, keyed
+// by function name. A registration with any other description has no
+// entry.
+func extractFunctionDescriptions(fn *ast.FuncDecl) map[string]string {
+	out := map[string]string{}
+	for _, entry := range functionEntries(fn) {
+		name, ok := stringLit(entry.Key)
+		if !ok {
+			continue
+		}
+		call, ok := entry.Value.(*ast.CallExpr)
+		if !ok || calleeName(call.Fun) != "MakeFunc" || len(call.Args) != 3 {
+			continue
+		}
+		if description, ok := stringLit(call.Args[1]); ok && description != "" {
+			out[name] = description
+		}
+	}
+	return out
+}
+
+// functionEntries returns the entries of the Functions map in the
+// Library() return literal.
+func functionEntries(fn *ast.FuncDecl) []*ast.KeyValueExpr {
 	if fn.Body == nil {
-		return out
+		return nil
 	}
+	var out []*ast.KeyValueExpr
 	for _, stmt := range fn.Body.List {
 		ret, ok := stmt.(*ast.ReturnStmt)
 		if !ok || len(ret.Results) != 1 {
@@ -1121,12 +1156,8 @@ func extractFunctions(
 				continue
 			}
 			for _, entry := range mapLit.Elts {
-				ekv, ok := entry.(*ast.KeyValueExpr)
-				if !ok {
-					continue
-				}
-				if name, ok := stringLit(ekv.Key); ok {
-					out[name] = functionSig(name, ekv.Value, files, errs)
+				if ekv, ok := entry.(*ast.KeyValueExpr); ok {
+					out = append(out, ekv)
 				}
 			}
 		}
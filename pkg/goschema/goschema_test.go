@@ -1224,6 +1224,7 @@ func Library() *runtime.Library {
 	require.True(t, flag.Params[1].Equal(typecheck.TBoolean()))
 	require.Nil(t, flag.Variadic)
 	require.True(t, flag.Result.Equal(typecheck.TBoolean()))
+	require.Equal(t, map[string]string{"flag": "d"}, extractFunctionDescriptions(fn))
 }
 
 func TestReadConfigurationFromExtraRoot(t *testing.T) {
@@ -38,7 +38,10 @@ type (
 	TypeObjectField    = parse.TypeObjectField
 	TypeTuple          = parse.TypeTuple
 	TypeOptional       = parse.TypeOptional
+	TypeUnion          = parse.TypeUnion
+	TypeUnionVariant   = parse.TypeUnionVariant
 	TypeLibraryConfig  = parse.TypeLibraryConfig
+	TypeNamed          = parse.TypeNamed
 	Comment            = parse.Comment
 	Span               = parse.Span
 	Position           = parse.Position
@@ -76,12 +79,17 @@ const (
 	ErrSchema  = parse.ErrSchema
 	ErrType    = parse.ErrType
 	ErrResolve = parse.ErrResolve
+
+	CodeUnknownImport       = parse.CodeUnknownImport
+	CodeMissingInput        = parse.CodeMissingInput
+	CodeUnknownCoreFunction = parse.CodeUnknownCoreFunction
 )
 
 var (
 	NewErrorList  = parse.NewErrorList
 	Errorf        = parse.Errorf
 	PascalToKebab = parse.PascalToKebab
+	ResolveNamed  = parse.ResolveNamed
 )
 
 // ParseSource reads .ub source from b and returns the parsed File.
@@ -98,6 +106,12 @@ func ParseExpr(path string, b []byte) (Expr, error) {
 	return parse.ParseExpr(path, b)
 }
 
+// ParseTemplate parses b as a template whose `{{ }}` slots use the
+// interpolated-string grammar, with positions in path.
+func ParseTemplate(path string, b []byte) (*InterpolatedString, error) {
+	return parse.ParseTemplate(path, b)
+}
+
 // ParseType parses a single unobin type expression from b.
 func ParseType(path string, b []byte) (TypeExpr, error) {
 	return parse.ParseType(path, b)
@@ -0,0 +1,181 @@
+package lang
+
+import (
+	"fmt"
+	"slices"
+)
+
+// CheckEntry is one resolved entry of a factory's `checks:` block: an
+// assertion evaluated after apply, with the message a failure reports.
+// Levels iterates the assertion the way a predicate constraint's
+// @for-each does, once per element with the level bindings in scope.
+type CheckEntry struct {
+	Assert  Expr
+	Message string
+	Levels  []ForEachLevel
+}
+
+// CheckFailure is one failed check: the entry's position in the block,
+// the element a failing iteration was about, and what went wrong,
+// either the entry's message or the reason the assertion could not be
+// decided.
+type CheckFailure struct {
+	Index   int
+	Element string
+	Message string
+}
+
+// String renders the failure the way a diagnostic names it, like
+// `checks[1]: api must answer (input.hosts[0])`.
+func (f CheckFailure) String() string {
+	if f.Element == "" {
+		return fmt.Sprintf("checks[%d]: %s", f.Index, f.Message)
+	}
+	return fmt.Sprintf("checks[%d]: %s (%s)", f.Index, f.Message, f.Element)
+}
+
+// ReadChecks reads a `checks:` block into entries. An entry that is not
+// an object, has no assertion, or has a malformed chain is skipped;
+// ValidateChecks is the place that reports it. The returned slice keeps
+// each entry at its block index, with a nil Assert for a skipped one,
+// so a failure names the entry as written.
+func ReadChecks(block *ArrayLit) []CheckEntry {
+	if block == nil {
+		return nil
+	}
+	entries := make([]CheckEntry, len(block.Elements))
+	for i, e := range block.Elements {
+		obj, ok := e.(*ObjectLit)
+		if !ok {
+			continue
+		}
+		if c, ok := readCheck(obj); ok {
+			entries[i] = c
+		}
+	}
+	return entries
+}
+
+func readCheck(obj *ObjectLit) (CheckEntry, bool) {
+	var c CheckEntry
+	for _, f := range obj.Fields {
+		if f.Key.Kind != FieldIdent {
+			continue
+		}
+		switch f.Key.Name {
+		case "@for-each":
+			levels, ok := readForEachLevels(f.Value)
+			if !ok {
+				return c, false
+			}
+			c.Levels = levels
+		case "assert":
+			c.Assert = f.Value
+		case "message":
+			if s, ok := f.Value.(*StringLit); ok {
+				c.Message = s.Value
+			}
+		}
+	}
+	return c, c.Assert != nil
+}
+
+// RunChecks evaluates every entry's assertion with eval and returns the
+// failures in block order, iterating elements in the same order a
+// predicate constraint does. An assertion that errors or reduces to
+// something other than a boolean fails its check rather than being
+// skipped, since a check that cannot be decided has not passed.
+func RunChecks(entries []CheckEntry, eval ConstraintEvalFunc) []CheckFailure {
+	var failures []CheckFailure
+	for i, c := range entries {
+		if c.Assert == nil {
+			continue
+		}
+		failures = runCheckLevels(i, c, c.Levels, nil, "", eval, failures)
+	}
+	return failures
+}
+
+func runCheckLevels(
+	idx int,
+	c CheckEntry,
+	levels []ForEachLevel,
+	binds []EachBinding,
+	at string,
+	eval ConstraintEvalFunc,
+	failures []CheckFailure,
+) []CheckFailure {
+	if len(levels) == 0 {
+		return runCheckOnce(idx, c, binds, at, eval, failures)
+	}
+	lv := levels[0]
+	iterable, err := eval(lv.In, binds)
+	if err != nil {
+		return append(failures, CheckFailure{
+			Index: idx, Element: at, Message: fmt.Sprintf("@for-each: %v", err),
+		})
+	}
+	levelText := levelElementText(lv.InText, at, binds, DisplayRooted)
+	descend := func(key, element any, elementAt string) {
+		child := append(slices.Clip(binds), EachBinding{
+			Name:  lv.Name,
+			Key:   key,
+			Value: element,
+		})
+		failures = runCheckLevels(idx, c, levels[1:], child, elementAt, eval, failures)
+	}
+	switch it := iterable.(type) {
+	case []any:
+		for i, el := range it {
+			descend(int64(i), el, fmt.Sprintf("%s[%d]", levelText, i))
+		}
+	case map[string]any:
+		keys := make([]string, 0, len(it))
+		for k := range it {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			descend(k, it[k], fmt.Sprintf("%s['%s']", levelText, k))
+		}
+	default:
+		failures = append(failures, CheckFailure{
+			Index:   idx,
+			Element: at,
+			Message: "@for-each must iterate a list or map, got " + TypeMessage(iterable),
+		})
+	}
+	return failures
+}
+
+func runCheckOnce(
+	idx int,
+	c CheckEntry,
+	binds []EachBinding,
+	at string,
+	eval ConstraintEvalFunc,
+	failures []CheckFailure,
+) []CheckFailure {
+	v, err := eval(c.Assert, binds)
+	if err != nil {
+		return append(failures, CheckFailure{
+			Index: idx, Element: at, Message: fmt.Sprintf("assert: %v", err),
+		})
+	}
+	ok, isBool := v.(bool)
+	if !isBool {
+		return append(failures, CheckFailure{
+			Index:   idx,
+			Element: at,
+			Message: "assert must evaluate to a boolean, got " + TypeMessage(v),
+		})
+	}
+	if ok {
+		return failures
+	}
+	msg := c.Message
+	if msg == "" {
+		msg = "assertion failed"
+	}
+	return append(failures, CheckFailure{Index: idx, Element: at, Message: msg})
+}
@@ -0,0 +1,84 @@
+package lang
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func parseChecksBlock(t *testing.T, src string) *ArrayLit {
+	t.Helper()
+	f, err := ParseSource("", []byte(src))
+	require.NoError(t, err)
+	block := TopLevelArray(f, "checks")
+	require.NotNil(t, block, "expected `checks:` to be an array literal")
+	return block
+}
+
+func TestRunChecks(t *testing.T) {
+	block := parseChecksBlock(t, `checks: [
+  { assert: input.status == 'ok', message: 'status must be ok' },
+  { assert: input.status != 'ok' },
+  { assert: input.status },
+]
+`)
+	values := map[string]any{"status": "degraded"}
+	failures := RunChecks(ReadChecks(block), boolExprEval(values))
+	require.Equal(t, []CheckFailure{
+		{Index: 0, Message: "status must be ok"},
+		{Index: 2, Message: "assert must evaluate to a boolean, got a string"},
+	}, failures)
+	require.Equal(t, "checks[0]: status must be ok", failures[0].String())
+}
+
+func TestRunChecksForEach(t *testing.T) {
+	block := parseChecksBlock(t, `checks: [
+  { @for-each: input.hosts, assert: @each.value != '', message: 'host is empty' },
+  {
+    @for-each: [
+      { @zone: input.zones },
+      { @host: @zone.value.hosts },
+    ]
+    assert:  @host.value.up == true
+    message: 'host is down'
+  },
+]
+`)
+	values := map[string]any{
+		"hosts": []any{"a", "", "c"},
+		"zones": map[string]any{
+			"east": map[string]any{"hosts": []any{
+				map[string]any{"up": true},
+				map[string]any{"up": false},
+			}},
+		},
+	}
+	failures := RunChecks(ReadChecks(block), boolExprEval(values))
+	require.Equal(t, []string{
+		"checks[0]: host is empty (input.hosts[1])",
+		"checks[1]: host is down (input.zones['east'].hosts[1])",
+	}, checkFailureStrings(failures))
+}
+
+// TestRunChecksSkipsMalformed pins the division of labor: a malformed
+// entry is skipped here, with compile validation the place that reports
+// it, while the indexes of the entries that do run stay as written.
+func TestRunChecksSkipsMalformed(t *testing.T) {
+	block := parseChecksBlock(t, `checks: [
+  true,
+  { message: 'no assertion' },
+  { @for-each: [], assert: false },
+  { assert: false },
+]
+`)
+	failures := RunChecks(ReadChecks(block), boolExprEval(nil))
+	require.Equal(t, []string{"checks[3]: assertion failed"}, checkFailureStrings(failures))
+}
+
+func checkFailureStrings(failures []CheckFailure) []string {
+	out := make([]string, 0, len(failures))
+	for _, f := range failures {
+		out = append(out, f.String())
+	}
+	return out
+}
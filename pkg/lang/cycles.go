@@ -0,0 +1,44 @@
+package lang
+
+import "slices"
+
+// FindCycles walks the graph reachable from roots depth first, each
+// name visited once. edges returns the edges out of a name and target
+// the name an edge leads to. For each edge that leads back into the
+// path being walked, cycle is called with the edge and the names that
+// form the cycle, starting and ending with the edge's target.
+func FindCycles[E any](
+	roots []string,
+	edges func(name string) []E,
+	target func(edge E) string,
+	cycle func(edge E, names []string),
+) {
+	const (
+		unvisited = 0
+		active    = 1
+		done      = 2
+	)
+	visiting := map[string]int{}
+	var path []string
+	var visit func(string)
+	visit = func(name string) {
+		visiting[name] = active
+		path = append(path, name)
+		for _, edge := range edges(name) {
+			to := target(edge)
+			switch visiting[to] {
+			case active:
+				cycle(edge, append(slices.Clone(path[slices.Index(path, to):]), to))
+			case unvisited:
+				visit(to)
+			}
+		}
+		path = path[:len(path)-1]
+		visiting[name] = done
+	}
+	for _, root := range roots {
+		if visiting[root] == unvisited {
+			visit(root)
+		}
+	}
+}
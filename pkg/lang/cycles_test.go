@@ -0,0 +1,26 @@
+package lang
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindCyclesReportsEachClosingEdge(t *testing.T) {
+	graph := map[string][]string{
+		"a": {"b", "d"},
+		"b": {"c"},
+		"c": {"a", "c"},
+		"d": {"b"},
+		"e": {},
+	}
+	var cycles [][]string
+	FindCycles([]string{"a", "d", "e"},
+		func(name string) []string { return graph[name] },
+		func(to string) string { return to },
+		func(_ string, names []string) { cycles = append(cycles, names) })
+	require.Equal(t, [][]string{
+		{"a", "b", "c", "a"},
+		{"c", "c"},
+	}, cycles)
+}
@@ -134,6 +134,10 @@ func (s *exprScanner) scanChildren(expr Expr) {
 		for _, elem := range v.Elements {
 			s.scan(elem)
 		}
+	case *TypeUnion:
+		for _, variant := range v.Variants {
+			s.scan(variant.Type)
+		}
 	case *TypeOptional:
 		s.scan(v.Elem)
 	}
@@ -613,6 +613,8 @@ func (w *formatter) typeExprWidth(t TypeExpr) int {
 	switch x := t.(type) {
 	case *TypeAtomic:
 		return len(x.Name)
+	case *TypeNamed:
+		return len(x.QualifiedName())
 	case *TypeList:
 		i := w.typeExprWidth(x.Elem)
 		if i < 0 {
@@ -1867,6 +1869,8 @@ func (w *formatter) writeTypeExpr(t TypeExpr, indent string) error {
 	switch x := t.(type) {
 	case *TypeAtomic:
 		w.buf.WriteString(x.Name)
+	case *TypeNamed:
+		w.buf.WriteString(x.QualifiedName())
 	case *TypeList:
 		w.buf.WriteString("list(")
 		if err := w.writeTypeExpr(x.Elem, indent); err != nil {
@@ -1891,6 +1895,8 @@ func (w *formatter) writeTypeExpr(t TypeExpr, indent string) error {
 			return nil
 		}
 		return w.writeTypeObject(x, indent)
+	case *TypeUnion:
+		return w.writeTypeUnion(x, indent)
 	case *TypeTuple:
 		w.buf.WriteString("tuple(")
 		for i, elem := range x.Elements {
@@ -1950,6 +1956,25 @@ func (w *formatter) writeTypeObject(o *TypeObject, indent string) error {
 	return nil
 }
 
+func (w *formatter) writeTypeUnion(u *TypeUnion, indent string) error {
+	inner := indent + fmtStep
+	w.buf.WriteString("union(")
+	w.buf.WriteString(u.Tag)
+	w.buf.WriteString(", {\n")
+	for _, variant := range u.Variants {
+		w.buf.WriteString(inner)
+		w.buf.WriteString(RenderKey(variant.Name))
+		w.buf.WriteString(": ")
+		if err := w.writeTypeExpr(variant.Type, inner); err != nil {
+			return err
+		}
+		w.buf.WriteByte('\n')
+	}
+	w.buf.WriteString(indent)
+	w.buf.WriteString("})")
+	return nil
+}
+
 // flushBefore emits any pending comments whose start offset is less
 // than target on their own line at the given indent, preserving a
 // blank-line gap from the prior item when the source had one.
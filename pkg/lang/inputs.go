@@ -166,7 +166,7 @@ func extractTypeAndDefault(
 	if !ok {
 		return nil, nil, false, false
 	}
-	if opt, ok := t.(*TypeOptional); ok {
+	if opt, ok := ResolveNamed(t).(*TypeOptional); ok {
 		return opt.Elem, defaultField, true, true
 	}
 	return t, defaultField, false, true
@@ -192,6 +192,8 @@ func checkValue(
 		return checkObject(tt, v, ev, resolve)
 	case *TypeTuple:
 		return checkTuple(tt, v, ev, resolve)
+	case *TypeUnion:
+		return checkUnion(tt, v, ev, resolve)
 	case *TypeOptional:
 		if v == nil {
 			return nil, nil
@@ -199,10 +201,83 @@ func checkValue(
 		return checkValue(tt.Elem, v, ev, resolve)
 	case *TypeLibraryConfig:
 		return checkLibraryConfig(tt, v, ev, resolve)
+	case *TypeNamed:
+		if tt.Resolved == nil {
+			return v, nil
+		}
+		return checkValue(tt.Resolved, v, ev, resolve)
 	}
 	return nil, fmt.Errorf("unsupported type %T", t)
 }
 
+// checkUnion picks the variant v's tag field names and checks the rest
+// of v against that variant's object. The tag is kept in the result so
+// the value still says which variant it is.
+func checkUnion(
+	t *TypeUnion,
+	v any,
+	ev EvalFunc,
+	resolve LibraryConfigResolver,
+) (any, error) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected object, got %s", typeName(v))
+	}
+	raw, present := m[t.Tag]
+	if !present || raw == nil {
+		return nil, fmt.Errorf("field %q: required but not provided", t.Tag)
+	}
+	tag, ok := raw.(string)
+	if !ok {
+		return nil, fmt.Errorf("field %q: expected string, got %s", t.Tag, typeName(raw))
+	}
+	variant, ok := t.Variant(tag)
+	if !ok {
+		return nil, fmt.Errorf("field %q: unknown variant %q (expected %s)",
+			t.Tag, tag, UnionVariantNames(t))
+	}
+	rest := make(map[string]any, len(m)-1)
+	for k, item := range m {
+		if k != t.Tag {
+			rest[k] = item
+		}
+	}
+	coerced, err := checkValue(variant.Type, rest, ev, resolve)
+	if err != nil {
+		return nil, fmt.Errorf("variant %q: %w", tag, err)
+	}
+	out, ok := coerced.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("variant %q: expected object, got %s", tag, typeName(coerced))
+	}
+	out[t.Tag] = tag
+	return out, nil
+}
+
+// UnionVariantNames lists t's variant names for a message: "git, s3,
+// or http".
+func UnionVariantNames(t *TypeUnion) string {
+	names := make([]string, len(t.Variants))
+	for i, variant := range t.Variants {
+		names[i] = variant.Name
+	}
+	return ProseList(names, "or")
+}
+
+// ProseList joins names for a message with the conjunction conj: "a or
+// b", or "a, b, and c" for three or more.
+func ProseList(names []string, conj string) string {
+	switch len(names) {
+	case 0:
+		return ""
+	case 1:
+		return names[0]
+	case 2:
+		return names[0] + " " + conj + " " + names[1]
+	}
+	return strings.Join(names[:len(names)-1], ", ") + ", " + conj + " " + names[len(names)-1]
+}
+
 func checkLibraryConfig(
 	t *TypeLibraryConfig,
 	v any,
@@ -459,12 +534,19 @@ func fieldTypeAndDefault(f *TypeObjectField) (TypeExpr, Expr, bool, bool) {
 	if f.Type == nil {
 		return nil, nil, false, false
 	}
-	if opt, ok := f.Type.(*TypeOptional); ok {
+	if opt, ok := ResolveNamed(f.Type).(*TypeOptional); ok {
 		return opt.Elem, nil, true, true
 	}
 	return f.Type, nil, false, true
 }
 
+// RequiredFieldType reports the value type of f and whether a value
+// must supply it: f is neither optional nor defaulted.
+func RequiredFieldType(f *TypeObjectField) (TypeExpr, bool) {
+	typ, def, optional, ok := fieldTypeAndDefault(f)
+	return typ, ok && !optional && def == nil
+}
+
 func checkTuple(
 	t *TypeTuple,
 	v any,
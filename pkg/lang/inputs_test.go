@@ -21,3 +21,70 @@ func TestValidateInputsRequiresLibraryConfigResolver(t *testing.T) {
 	require.Equal(t, 1, errs.Len())
 	require.Contains(t, errs.Err().Error(), `library-config "github.com/acme/aws"`)
 }
+
+func TestValidateInputsUnion(t *testing.T) {
+	src, err := os.ReadFile("testdata/ub/inputs/valid/union-value.ub")
+	require.NoError(t, err)
+	decl := parseInputsBlock(t, string(src))
+	declErrs := ValidateInputDeclarations(decl)
+	require.Equal(t, 0, declErrs.Len(), declErrs.Error())
+
+	tests := []struct {
+		name    string
+		source  any
+		wantErr string
+	}{
+		{
+			name:   "git variant",
+			source: map[string]any{"kind": "git", "url": "https://example.com/repo", "ref": "v1"},
+		},
+		{
+			name:   "s3 variant",
+			source: map[string]any{"kind": "s3", "bucket": "b", "key": "k"},
+		},
+		{
+			name:    "missing tag",
+			source:  map[string]any{"url": "https://example.com/repo"},
+			wantErr: `field "kind": required but not provided`,
+		},
+		{
+			name:    "tag not a string",
+			source:  map[string]any{"kind": int64(1)},
+			wantErr: `field "kind": expected string, got integer`,
+		},
+		{
+			name:    "unknown variant",
+			source:  map[string]any{"kind": "http"},
+			wantErr: `field "kind": unknown variant "http" (expected git or s3)`,
+		},
+		{
+			name:    "field from another variant",
+			source:  map[string]any{"kind": "git", "url": "u", "bucket": "b"},
+			wantErr: `variant "git": unknown field "bucket"`,
+		},
+		{
+			name:    "missing variant field",
+			source:  map[string]any{"kind": "s3", "bucket": "b"},
+			wantErr: `variant "s3": field "key": required but not provided`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, errs := ValidateInputs(decl, map[string]any{"source": tt.source}, nil)
+			if tt.wantErr != "" {
+				require.Equal(t, 1, errs.Len())
+				require.Contains(t, errs.Err().Error(), tt.wantErr)
+				return
+			}
+			require.Equal(t, 0, errs.Len(), errs.Error())
+			require.Equal(t, tt.source, got["source"])
+		})
+	}
+}
+
+func TestProseList(t *testing.T) {
+	require.Equal(t, "", ProseList(nil, "or"))
+	require.Equal(t, "git", ProseList([]string{"git"}, "or"))
+	require.Equal(t, "git or s3", ProseList([]string{"git", "s3"}, "or"))
+	require.Equal(t, "a, b, and c", ProseList([]string{"a", "b", "c"}, "and"))
+}
@@ -495,6 +495,37 @@ func (n *TypeTuple) Span() Span    { return n.S }
 func (n *TypeTuple) exprNode()     {}
 func (n *TypeTuple) typeExprNode() {}
 
+// TypeUnion is `union(tag, { name1: T1  name2: T2 ... })`, a tagged
+// union: a value is one of the variant objects, told apart by its Tag
+// field, which holds the variant's name as a string. Each variant type
+// is an object that does not declare Tag itself.
+type TypeUnion struct {
+	S        Span
+	Tag      string
+	Variants []*TypeUnionVariant
+}
+
+func (n *TypeUnion) Span() Span    { return n.S }
+func (n *TypeUnion) exprNode()     {}
+func (n *TypeUnion) typeExprNode() {}
+
+// Variant returns the variant named name.
+func (n *TypeUnion) Variant(name string) (*TypeUnionVariant, bool) {
+	for _, v := range n.Variants {
+		if v.Name == name {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// TypeUnionVariant is one `name: T` entry of a TypeUnion.
+type TypeUnionVariant struct {
+	S    Span
+	Name string
+	Type TypeExpr
+}
+
 // TypeOptional is optional(T).
 //
 // Optionality implies nullability - wrapping with optional() allows null
@@ -508,6 +539,42 @@ func (n *TypeOptional) Span() Span    { return n.S }
 func (n *TypeOptional) exprNode()     {}
 func (n *TypeOptional) typeExprNode() {}
 
+// TypeNamed refers to a type declared in a `types:` block: a bare name
+// for a type the file or library declares, or `alias.name` for one an
+// imported UB library exports. Resolved is the declared type once the
+// name is bound; it is nil until then, and for a name that resolves to
+// nothing.
+type TypeNamed struct {
+	S        Span
+	Library  string
+	Name     string
+	Resolved TypeExpr
+}
+
+func (n *TypeNamed) Span() Span    { return n.S }
+func (n *TypeNamed) exprNode()     {}
+func (n *TypeNamed) typeExprNode() {}
+
+// QualifiedName is the name as written: `name` or `alias.name`.
+func (n *TypeNamed) QualifiedName() string {
+	if n.Library == "" {
+		return n.Name
+	}
+	return n.Library + "." + n.Name
+}
+
+// ResolveNamed follows t through named type references to the type
+// they stand for. An unbound reference is returned as is.
+func ResolveNamed(t TypeExpr) TypeExpr {
+	for {
+		named, ok := t.(*TypeNamed)
+		if !ok || named.Resolved == nil {
+			return t
+		}
+		t = named.Resolved
+	}
+}
+
 // TypeLibraryConfig is library-config('<library-path>').
 type TypeLibraryConfig struct {
 	S    Span
@@ -45,6 +45,32 @@ type Error struct {
 	Msg  string
 	// Hint is an optional second line offering a fix or pointer.
 	Hint string
+	// Code names an error a tool can offer a fix for, and Data holds
+	// the names that fix needs, keyed by what they are. Both are empty
+	// for errors without a fix.
+	Code string
+	Data map[string]string
+}
+
+// Codes for the errors an editor can offer a fix for.
+const (
+	// CodeUnknownImport is a library alias no import declares. Data
+	// "alias" is the alias.
+	CodeUnknownImport = "unobin.unknown-import"
+	// CodeMissingInput is a required input a node body leaves out. Data
+	// "input" is its name, "type" its declared type, and "stub" a value
+	// of that type.
+	CodeMissingInput = "unobin.missing-input"
+	// CodeUnknownCoreFunction is a call to a function @core does not
+	// have. Data "suggestion", when present, is the closest one it has.
+	CodeUnknownCoreFunction = "unobin.unknown-core-function"
+)
+
+// WithCode sets e's Code and Data and returns e.
+func (e *Error) WithCode(code string, data map[string]string) *Error {
+	e.Code = code
+	e.Data = data
+	return e
 }
 
 func (e *Error) Error() string {
@@ -142,8 +142,12 @@ func typeExprName(t TypeExpr) string {
 		return "object"
 	case *TypeOptional:
 		return "optional"
+	case *TypeUnion:
+		return "union"
 	case *TypeLibraryConfig:
 		return "library-config"
+	case *TypeNamed:
+		return v.QualifiedName()
 	}
 	return "type"
 }
@@ -466,17 +470,29 @@ func scanInterpolatedSlots(s string) (string, []InterpolatedPart, error) {
 }
 
 // parseInterpolatedSlot parses the slot beginning at s[start] ("{{"). It
-// returns the index just past the closing "}}". The matching "}}" is the
-// first at bracket depth zero outside a string; a depth-zero ':' splits
-// off a printf verb.
+// returns the index just past the closing "}}".
 func parseInterpolatedSlot(s string, start int) (int, InterpolatedPart, error) {
+	colon, close, err := scanInterpolatedSlot(s, start)
+	if err != nil {
+		return 0, InterpolatedPart{}, err
+	}
+	part, err := finishInterpolatedSlot(s, start+2, colon, close)
+	return close + 2, part, err
+}
+
+// scanInterpolatedSlot finds the bounds of the slot beginning at
+// s[start] ("{{"). It returns the index of the closing "}}" and of the
+// depth-zero ':' that splits off a printf verb, or -1 when there is
+// none. The matching "}}" is the first at bracket depth zero outside a
+// string.
+func scanInterpolatedSlot(s string, start int) (colon, close int, err error) {
 	depth := 0
 	inStr := false
-	colon := -1
+	colon = -1
 	for j := start + 2; j < len(s); {
 		c := s[j]
 		if c == '\n' {
-			return 0, InterpolatedPart{}, fmt.Errorf("interpolation slot must be on one line")
+			return 0, 0, fmt.Errorf("interpolation slot must be on one line")
 		}
 		if inStr {
 			if c == '\\' {
@@ -504,36 +520,45 @@ func parseInterpolatedSlot(s string, start int) (int, InterpolatedPart, error) {
 			if depth > 0 {
 				depth--
 			} else if j+1 < len(s) && s[j+1] == '}' {
-				part, err := finishInterpolatedSlot(s, start+2, colon, j)
-				return j + 2, part, err
+				return colon, j, nil
 			}
 		}
 		j++
 	}
-	return 0, InterpolatedPart{}, fmt.Errorf("unterminated interpolation slot")
+	return 0, 0, fmt.Errorf("unterminated interpolation slot")
 }
 
 // finishInterpolatedSlot parses the expression (and optional verb) of a
 // slot whose body runs from exprStart up to the closing "}}" at close.
 func finishInterpolatedSlot(s string, exprStart, colon, close int) (InterpolatedPart, error) {
+	exprText, verb, err := splitInterpolatedSlot(s, exprStart, colon, close)
+	if err != nil {
+		return InterpolatedPart{}, err
+	}
+	expr, err := parseExprFn("", []byte(exprText))
+	if err != nil {
+		return InterpolatedPart{}, err
+	}
+	return InterpolatedPart{Expr: expr, Verb: verb}, nil
+}
+
+// splitInterpolatedSlot returns the trimmed expression text and printf
+// verb of a slot whose body runs from exprStart up to close.
+func splitInterpolatedSlot(s string, exprStart, colon, close int) (string, string, error) {
 	exprEnd := close
 	verb := ""
 	if colon >= 0 {
 		exprEnd = colon
 		verb = strings.TrimSpace(s[colon+1 : close])
 		if verb == "" || verb[0] != '%' {
-			return InterpolatedPart{}, fmt.Errorf("interpolation directive must be a printf verb like %%03d")
+			return "", "", fmt.Errorf("interpolation directive must be a printf verb like %%03d")
 		}
 	}
 	exprText := strings.TrimSpace(s[exprStart:exprEnd])
 	if exprText == "" {
-		return InterpolatedPart{}, fmt.Errorf("empty interpolation slot")
-	}
-	expr, err := parseExprFn("", []byte(exprText))
-	if err != nil {
-		return InterpolatedPart{}, err
+		return "", "", fmt.Errorf("empty interpolation slot")
 	}
-	return InterpolatedPart{Expr: expr, Verb: verb}, nil
+	return exprText, verb, nil
 }
 
 // splitInterpolatedValue splits a laid-out triple-quote value on its
@@ -637,30 +662,30 @@ var g = &grammar{
 	rules: []*rule{
 		{
 			name: "File",
-			pos:  position{line: 631, col: 1, offset: 16628},
+			pos:  position{line: 656, col: 1, offset: 17421},
 			expr: &actionExpr{
-				pos: position{line: 631, col: 9, offset: 16636},
+				pos: position{line: 656, col: 9, offset: 17429},
 				run: (*parser).callonFile1,
 				expr: &seqExpr{
-					pos: position{line: 631, col: 9, offset: 16636},
+					pos: position{line: 656, col: 9, offset: 17429},
 					exprs: []any{
 						&ruleRefExpr{
-							pos:  position{line: 631, col: 9, offset: 16636},
+							pos:  position{line: 656, col: 9, offset: 17429},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 631, col: 11, offset: 16638},
+							pos:   position{line: 656, col: 11, offset: 17431},
 							label: "pairs",
 							expr: &zeroOrMoreExpr{
-								pos: position{line: 631, col: 17, offset: 16644},
+								pos: position{line: 656, col: 17, offset: 17437},
 								expr: &ruleRefExpr{
-									pos:  position{line: 631, col: 17, offset: 16644},
+									pos:  position{line: 656, col: 17, offset: 17437},
 									name: "Pair",
 								},
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 631, col: 23, offset: 16650},
+							pos:  position{line: 656, col: 23, offset: 17443},
 							name: "EOF",
 						},
 					},
@@ -669,31 +694,31 @@ var g = &grammar{
 		},
 		{
 			name: "TypeFile",
-			pos:  position{line: 641, col: 1, offset: 16900},
+			pos:  position{line: 666, col: 1, offset: 17693},
 			expr: &actionExpr{
-				pos: position{line: 641, col: 13, offset: 16912},
+				pos: position{line: 666, col: 13, offset: 17705},
 				run: (*parser).callonTypeFile1,
 				expr: &seqExpr{
-					pos: position{line: 641, col: 13, offset: 16912},
+					pos: position{line: 666, col: 13, offset: 17705},
 					exprs: []any{
 						&ruleRefExpr{
-							pos:  position{line: 641, col: 13, offset: 16912},
+							pos:  position{line: 666, col: 13, offset: 17705},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 641, col: 15, offset: 16914},
+							pos:   position{line: 666, col: 15, offset: 17707},
 							label: "t",
 							expr: &ruleRefExpr{
-								pos:  position{line: 641, col: 17, offset: 16916},
+								pos:  position{line: 666, col: 17, offset: 17709},
 								name: "TypeExprRule",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 641, col: 30, offset: 16929},
+							pos:  position{line: 666, col: 30, offset: 17722},
 							name: "_",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 641, col: 32, offset: 16931},
+							pos:  position{line: 666, col: 32, offset: 17724},
 							name: "EOF",
 						},
 					},
@@ -702,84 +727,92 @@ var g = &grammar{
 		},
 		{
 			name: "TypeExprRule",
-			pos:  position{line: 645, col: 1, offset: 16955},
+			pos:  position{line: 670, col: 1, offset: 17748},
 			expr: &choiceExpr{
-				pos: position{line: 645, col: 17, offset: 16971},
+				pos: position{line: 670, col: 17, offset: 17764},
 				alternatives: []any{
 					&ruleRefExpr{
-						pos:  position{line: 645, col: 17, offset: 16971},
+						pos:  position{line: 670, col: 17, offset: 17764},
 						name: "OpenType",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 645, col: 28, offset: 16982},
+						pos:  position{line: 670, col: 28, offset: 17775},
 						name: "OptionalType",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 645, col: 43, offset: 16997},
+						pos:  position{line: 670, col: 43, offset: 17790},
 						name: "ListType",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 645, col: 54, offset: 17008},
+						pos:  position{line: 670, col: 54, offset: 17801},
 						name: "MapType",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 645, col: 64, offset: 17018},
+						pos:  position{line: 670, col: 64, offset: 17811},
 						name: "TupleType",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 645, col: 76, offset: 17030},
+						pos:  position{line: 670, col: 76, offset: 17823},
 						name: "ObjectType",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 645, col: 89, offset: 17043},
+						pos:  position{line: 670, col: 89, offset: 17836},
+						name: "UnionType",
+					},
+					&ruleRefExpr{
+						pos:  position{line: 670, col: 101, offset: 17848},
 						name: "LibraryConfigType",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 645, col: 109, offset: 17063},
+						pos:  position{line: 670, col: 121, offset: 17868},
 						name: "AtomicType",
 					},
+					&ruleRefExpr{
+						pos:  position{line: 670, col: 134, offset: 17881},
+						name: "NamedType",
+					},
 				},
 			},
 		},
 		{
 			name: "ListType",
-			pos:  position{line: 647, col: 1, offset: 17075},
+			pos:  position{line: 672, col: 1, offset: 17892},
 			expr: &actionExpr{
-				pos: position{line: 647, col: 13, offset: 17087},
+				pos: position{line: 672, col: 13, offset: 17904},
 				run: (*parser).callonListType1,
 				expr: &seqExpr{
-					pos: position{line: 647, col: 13, offset: 17087},
+					pos: position{line: 672, col: 13, offset: 17904},
 					exprs: []any{
 						&litMatcher{
-							pos:        position{line: 647, col: 13, offset: 17087},
+							pos:        position{line: 672, col: 13, offset: 17904},
 							val:        "list",
 							ignoreCase: false,
 							want:       "\"list\"",
 						},
 						&litMatcher{
-							pos:        position{line: 647, col: 20, offset: 17094},
+							pos:        position{line: 672, col: 20, offset: 17911},
 							val:        "(",
 							ignoreCase: false,
 							want:       "\"(\"",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 647, col: 24, offset: 17098},
+							pos:  position{line: 672, col: 24, offset: 17915},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 647, col: 26, offset: 17100},
+							pos:   position{line: 672, col: 26, offset: 17917},
 							label: "args",
 							expr: &ruleRefExpr{
-								pos:  position{line: 647, col: 31, offset: 17105},
+								pos:  position{line: 672, col: 31, offset: 17922},
 								name: "TypeArgs",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 647, col: 40, offset: 17114},
+							pos:  position{line: 672, col: 40, offset: 17931},
 							name: "_",
 						},
 						&litMatcher{
-							pos:        position{line: 647, col: 42, offset: 17116},
+							pos:        position{line: 672, col: 42, offset: 17933},
 							val:        ")",
 							ignoreCase: false,
 							want:       "\")\"",
@@ -790,43 +823,43 @@ var g = &grammar{
 		},
 		{
 			name: "MapType",
-			pos:  position{line: 656, col: 1, offset: 17344},
+			pos:  position{line: 681, col: 1, offset: 18161},
 			expr: &actionExpr{
-				pos: position{line: 656, col: 12, offset: 17355},
+				pos: position{line: 681, col: 12, offset: 18172},
 				run: (*parser).callonMapType1,
 				expr: &seqExpr{
-					pos: position{line: 656, col: 12, offset: 17355},
+					pos: position{line: 681, col: 12, offset: 18172},
 					exprs: []any{
 						&litMatcher{
-							pos:        position{line: 656, col: 12, offset: 17355},
+							pos:        position{line: 681, col: 12, offset: 18172},
 							val:        "map",
 							ignoreCase: false,
 							want:       "\"map\"",
 						},
 						&litMatcher{
-							pos:        position{line: 656, col: 18, offset: 17361},
+							pos:        position{line: 681, col: 18, offset: 18178},
 							val:        "(",
 							ignoreCase: false,
 							want:       "\"(\"",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 656, col: 22, offset: 17365},
+							pos:  position{line: 681, col: 22, offset: 18182},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 656, col: 24, offset: 17367},
+							pos:   position{line: 681, col: 24, offset: 18184},
 							label: "args",
 							expr: &ruleRefExpr{
-								pos:  position{line: 656, col: 29, offset: 17372},
+								pos:  position{line: 681, col: 29, offset: 18189},
 								name: "TypeArgs",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 656, col: 38, offset: 17381},
+							pos:  position{line: 681, col: 38, offset: 18198},
 							name: "_",
 						},
 						&litMatcher{
-							pos:        position{line: 656, col: 40, offset: 17383},
+							pos:        position{line: 681, col: 40, offset: 18200},
 							val:        ")",
 							ignoreCase: false,
 							want:       "\")\"",
@@ -837,43 +870,43 @@ var g = &grammar{
 		},
 		{
 			name: "TupleType",
-			pos:  position{line: 665, col: 1, offset: 17608},
+			pos:  position{line: 690, col: 1, offset: 18425},
 			expr: &actionExpr{
-				pos: position{line: 665, col: 14, offset: 17621},
+				pos: position{line: 690, col: 14, offset: 18438},
 				run: (*parser).callonTupleType1,
 				expr: &seqExpr{
-					pos: position{line: 665, col: 14, offset: 17621},
+					pos: position{line: 690, col: 14, offset: 18438},
 					exprs: []any{
 						&litMatcher{
-							pos:        position{line: 665, col: 14, offset: 17621},
+							pos:        position{line: 690, col: 14, offset: 18438},
 							val:        "tuple",
 							ignoreCase: false,
 							want:       "\"tuple\"",
 						},
 						&litMatcher{
-							pos:        position{line: 665, col: 22, offset: 17629},
+							pos:        position{line: 690, col: 22, offset: 18446},
 							val:        "(",
 							ignoreCase: false,
 							want:       "\"(\"",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 665, col: 26, offset: 17633},
+							pos:  position{line: 690, col: 26, offset: 18450},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 665, col: 28, offset: 17635},
+							pos:   position{line: 690, col: 28, offset: 18452},
 							label: "args",
 							expr: &ruleRefExpr{
-								pos:  position{line: 665, col: 33, offset: 17640},
+								pos:  position{line: 690, col: 33, offset: 18457},
 								name: "TypeArgs",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 665, col: 42, offset: 17649},
+							pos:  position{line: 690, col: 42, offset: 18466},
 							name: "_",
 						},
 						&litMatcher{
-							pos:        position{line: 665, col: 44, offset: 17651},
+							pos:        position{line: 690, col: 44, offset: 18468},
 							val:        ")",
 							ignoreCase: false,
 							want:       "\")\"",
@@ -884,43 +917,43 @@ var g = &grammar{
 		},
 		{
 			name: "OptionalType",
-			pos:  position{line: 674, col: 1, offset: 17884},
+			pos:  position{line: 699, col: 1, offset: 18701},
 			expr: &actionExpr{
-				pos: position{line: 674, col: 17, offset: 17900},
+				pos: position{line: 699, col: 17, offset: 18717},
 				run: (*parser).callonOptionalType1,
 				expr: &seqExpr{
-					pos: position{line: 674, col: 17, offset: 17900},
+					pos: position{line: 699, col: 17, offset: 18717},
 					exprs: []any{
 						&litMatcher{
-							pos:        position{line: 674, col: 17, offset: 17900},
+							pos:        position{line: 699, col: 17, offset: 18717},
 							val:        "optional",
 							ignoreCase: false,
 							want:       "\"optional\"",
 						},
 						&litMatcher{
-							pos:        position{line: 674, col: 28, offset: 17911},
+							pos:        position{line: 699, col: 28, offset: 18728},
 							val:        "(",
 							ignoreCase: false,
 							want:       "\"(\"",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 674, col: 32, offset: 17915},
+							pos:  position{line: 699, col: 32, offset: 18732},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 674, col: 34, offset: 17917},
+							pos:   position{line: 699, col: 34, offset: 18734},
 							label: "args",
 							expr: &ruleRefExpr{
-								pos:  position{line: 674, col: 39, offset: 17922},
+								pos:  position{line: 699, col: 39, offset: 18739},
 								name: "TypeArgs",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 674, col: 48, offset: 17931},
+							pos:  position{line: 699, col: 48, offset: 18748},
 							name: "_",
 						},
 						&litMatcher{
-							pos:        position{line: 674, col: 50, offset: 17933},
+							pos:        position{line: 699, col: 50, offset: 18750},
 							val:        ")",
 							ignoreCase: false,
 							want:       "\")\"",
@@ -931,43 +964,43 @@ var g = &grammar{
 		},
 		{
 			name: "OpenType",
-			pos:  position{line: 683, col: 1, offset: 18173},
+			pos:  position{line: 708, col: 1, offset: 18990},
 			expr: &actionExpr{
-				pos: position{line: 683, col: 13, offset: 18185},
+				pos: position{line: 708, col: 13, offset: 19002},
 				run: (*parser).callonOpenType1,
 				expr: &seqExpr{
-					pos: position{line: 683, col: 13, offset: 18185},
+					pos: position{line: 708, col: 13, offset: 19002},
 					exprs: []any{
 						&litMatcher{
-							pos:        position{line: 683, col: 13, offset: 18185},
+							pos:        position{line: 708, col: 13, offset: 19002},
 							val:        "open",
 							ignoreCase: false,
 							want:       "\"open\"",
 						},
 						&litMatcher{
-							pos:        position{line: 683, col: 20, offset: 18192},
+							pos:        position{line: 708, col: 20, offset: 19009},
 							val:        "(",
 							ignoreCase: false,
 							want:       "\"(\"",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 683, col: 24, offset: 18196},
+							pos:  position{line: 708, col: 24, offset: 19013},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 683, col: 26, offset: 18198},
+							pos:   position{line: 708, col: 26, offset: 19015},
 							label: "args",
 							expr: &ruleRefExpr{
-								pos:  position{line: 683, col: 31, offset: 18203},
+								pos:  position{line: 708, col: 31, offset: 19020},
 								name: "TypeArgs",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 683, col: 40, offset: 18212},
+							pos:  position{line: 708, col: 40, offset: 19029},
 							name: "_",
 						},
 						&litMatcher{
-							pos:        position{line: 683, col: 42, offset: 18214},
+							pos:        position{line: 708, col: 42, offset: 19031},
 							val:        ")",
 							ignoreCase: false,
 							want:       "\")\"",
@@ -978,56 +1011,138 @@ var g = &grammar{
 		},
 		{
 			name: "ObjectType",
-			pos:  position{line: 707, col: 1, offset: 18920},
+			pos:  position{line: 732, col: 1, offset: 19737},
 			expr: &actionExpr{
-				pos: position{line: 707, col: 15, offset: 18934},
+				pos: position{line: 732, col: 15, offset: 19751},
 				run: (*parser).callonObjectType1,
 				expr: &seqExpr{
-					pos: position{line: 707, col: 15, offset: 18934},
+					pos: position{line: 732, col: 15, offset: 19751},
 					exprs: []any{
 						&litMatcher{
-							pos:        position{line: 707, col: 15, offset: 18934},
+							pos:        position{line: 732, col: 15, offset: 19751},
 							val:        "object",
 							ignoreCase: false,
 							want:       "\"object\"",
 						},
 						&litMatcher{
-							pos:        position{line: 707, col: 24, offset: 18943},
+							pos:        position{line: 732, col: 24, offset: 19760},
 							val:        "(",
 							ignoreCase: false,
 							want:       "\"(\"",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 707, col: 28, offset: 18947},
+							pos:  position{line: 732, col: 28, offset: 19764},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 707, col: 30, offset: 18949},
+							pos:   position{line: 732, col: 30, offset: 19766},
 							label: "fields",
 							expr: &ruleRefExpr{
-								pos:  position{line: 707, col: 37, offset: 18956},
+								pos:  position{line: 732, col: 37, offset: 19773},
 								name: "TypeObjectBody",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 707, col: 52, offset: 18971},
+							pos:  position{line: 732, col: 52, offset: 19788},
+							name: "_",
+						},
+						&zeroOrOneExpr{
+							pos: position{line: 732, col: 54, offset: 19790},
+							expr: &litMatcher{
+								pos:        position{line: 732, col: 54, offset: 19790},
+								val:        ",",
+								ignoreCase: false,
+								want:       "\",\"",
+							},
+						},
+						&ruleRefExpr{
+							pos:  position{line: 732, col: 59, offset: 19795},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 732, col: 61, offset: 19797},
+							val:        ")",
+							ignoreCase: false,
+							want:       "\")\"",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "UnionType",
+			pos:  position{line: 736, col: 1, offset: 19881},
+			expr: &actionExpr{
+				pos: position{line: 736, col: 14, offset: 19894},
+				run: (*parser).callonUnionType1,
+				expr: &seqExpr{
+					pos: position{line: 736, col: 14, offset: 19894},
+					exprs: []any{
+						&litMatcher{
+							pos:        position{line: 736, col: 14, offset: 19894},
+							val:        "union",
+							ignoreCase: false,
+							want:       "\"union\"",
+						},
+						&litMatcher{
+							pos:        position{line: 736, col: 22, offset: 19902},
+							val:        "(",
+							ignoreCase: false,
+							want:       "\"(\"",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 736, col: 26, offset: 19906},
+							name: "_",
+						},
+						&labeledExpr{
+							pos:   position{line: 736, col: 28, offset: 19908},
+							label: "tag",
+							expr: &ruleRefExpr{
+								pos:  position{line: 736, col: 32, offset: 19912},
+								name: "IdentText",
+							},
+						},
+						&ruleRefExpr{
+							pos:  position{line: 736, col: 42, offset: 19922},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 736, col: 44, offset: 19924},
+							val:        ",",
+							ignoreCase: false,
+							want:       "\",\"",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 736, col: 48, offset: 19928},
+							name: "_",
+						},
+						&labeledExpr{
+							pos:   position{line: 736, col: 50, offset: 19930},
+							label: "variants",
+							expr: &ruleRefExpr{
+								pos:  position{line: 736, col: 59, offset: 19939},
+								name: "TypeUnionBody",
+							},
+						},
+						&ruleRefExpr{
+							pos:  position{line: 736, col: 73, offset: 19953},
 							name: "_",
 						},
 						&zeroOrOneExpr{
-							pos: position{line: 707, col: 54, offset: 18973},
+							pos: position{line: 736, col: 75, offset: 19955},
 							expr: &litMatcher{
-								pos:        position{line: 707, col: 54, offset: 18973},
+								pos:        position{line: 736, col: 75, offset: 19955},
 								val:        ",",
 								ignoreCase: false,
 								want:       "\",\"",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 707, col: 59, offset: 18978},
+							pos:  position{line: 736, col: 80, offset: 19960},
 							name: "_",
 						},
 						&litMatcher{
-							pos:        position{line: 707, col: 61, offset: 18980},
+							pos:        position{line: 736, col: 82, offset: 19962},
 							val:        ")",
 							ignoreCase: false,
 							want:       "\")\"",
@@ -1036,48 +1151,152 @@ var g = &grammar{
 				},
 			},
 		},
+		{
+			name: "TypeUnionBody",
+			pos:  position{line: 744, col: 1, offset: 20199},
+			expr: &actionExpr{
+				pos: position{line: 744, col: 18, offset: 20216},
+				run: (*parser).callonTypeUnionBody1,
+				expr: &seqExpr{
+					pos: position{line: 744, col: 18, offset: 20216},
+					exprs: []any{
+						&litMatcher{
+							pos:        position{line: 744, col: 18, offset: 20216},
+							val:        "{",
+							ignoreCase: false,
+							want:       "\"{\"",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 744, col: 22, offset: 20220},
+							name: "_",
+						},
+						&labeledExpr{
+							pos:   position{line: 744, col: 24, offset: 20222},
+							label: "variants",
+							expr: &zeroOrMoreExpr{
+								pos: position{line: 744, col: 33, offset: 20231},
+								expr: &ruleRefExpr{
+									pos:  position{line: 744, col: 33, offset: 20231},
+									name: "TypeUnionVariant",
+								},
+							},
+						},
+						&ruleRefExpr{
+							pos:  position{line: 744, col: 51, offset: 20249},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 744, col: 53, offset: 20251},
+							val:        "}",
+							ignoreCase: false,
+							want:       "\"}\"",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "TypeUnionVariant",
+			pos:  position{line: 753, col: 1, offset: 20404},
+			expr: &actionExpr{
+				pos: position{line: 753, col: 21, offset: 20424},
+				run: (*parser).callonTypeUnionVariant1,
+				expr: &seqExpr{
+					pos: position{line: 753, col: 21, offset: 20424},
+					exprs: []any{
+						&labeledExpr{
+							pos:   position{line: 753, col: 21, offset: 20424},
+							label: "key",
+							expr: &ruleRefExpr{
+								pos:  position{line: 753, col: 25, offset: 20428},
+								name: "PlainIdentKey",
+							},
+						},
+						&ruleRefExpr{
+							pos:  position{line: 753, col: 39, offset: 20442},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 753, col: 41, offset: 20444},
+							val:        ":",
+							ignoreCase: false,
+							want:       "\":\"",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 753, col: 45, offset: 20448},
+							name: "_",
+						},
+						&labeledExpr{
+							pos:   position{line: 753, col: 47, offset: 20450},
+							label: "value",
+							expr: &ruleRefExpr{
+								pos:  position{line: 753, col: 53, offset: 20456},
+								name: "TypeExprRule",
+							},
+						},
+						&ruleRefExpr{
+							pos:  position{line: 753, col: 66, offset: 20469},
+							name: "_",
+						},
+						&zeroOrOneExpr{
+							pos: position{line: 753, col: 68, offset: 20471},
+							expr: &litMatcher{
+								pos:        position{line: 753, col: 68, offset: 20471},
+								val:        ",",
+								ignoreCase: false,
+								want:       "\",\"",
+							},
+						},
+						&ruleRefExpr{
+							pos:  position{line: 753, col: 73, offset: 20476},
+							name: "_",
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "LibraryConfigType",
-			pos:  position{line: 711, col: 1, offset: 19064},
+			pos:  position{line: 758, col: 1, offset: 20584},
 			expr: &actionExpr{
-				pos: position{line: 711, col: 22, offset: 19085},
+				pos: position{line: 758, col: 22, offset: 20605},
 				run: (*parser).callonLibraryConfigType1,
 				expr: &seqExpr{
-					pos: position{line: 711, col: 22, offset: 19085},
+					pos: position{line: 758, col: 22, offset: 20605},
 					exprs: []any{
 						&litMatcher{
-							pos:        position{line: 711, col: 22, offset: 19085},
+							pos:        position{line: 758, col: 22, offset: 20605},
 							val:        "library-config",
 							ignoreCase: false,
 							want:       "\"library-config\"",
 						},
 						&litMatcher{
-							pos:        position{line: 711, col: 39, offset: 19102},
+							pos:        position{line: 758, col: 39, offset: 20622},
 							val:        "(",
 							ignoreCase: false,
 							want:       "\"(\"",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 711, col: 43, offset: 19106},
+							pos:  position{line: 758, col: 43, offset: 20626},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 711, col: 45, offset: 19108},
+							pos:   position{line: 758, col: 45, offset: 20628},
 							label: "args",
 							expr: &zeroOrOneExpr{
-								pos: position{line: 711, col: 50, offset: 19113},
+								pos: position{line: 758, col: 50, offset: 20633},
 								expr: &ruleRefExpr{
-									pos:  position{line: 711, col: 50, offset: 19113},
+									pos:  position{line: 758, col: 50, offset: 20633},
 									name: "LibraryConfigArgs",
 								},
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 711, col: 69, offset: 19132},
+							pos:  position{line: 758, col: 69, offset: 20652},
 							name: "_",
 						},
 						&litMatcher{
-							pos:        position{line: 711, col: 71, offset: 19134},
+							pos:        position{line: 758, col: 71, offset: 20654},
 							val:        ")",
 							ignoreCase: false,
 							want:       "\")\"",
@@ -1088,51 +1307,51 @@ var g = &grammar{
 		},
 		{
 			name: "LibraryConfigArgs",
-			pos:  position{line: 730, col: 1, offset: 19712},
+			pos:  position{line: 777, col: 1, offset: 21232},
 			expr: &actionExpr{
-				pos: position{line: 730, col: 22, offset: 19733},
+				pos: position{line: 777, col: 22, offset: 21253},
 				run: (*parser).callonLibraryConfigArgs1,
 				expr: &seqExpr{
-					pos: position{line: 730, col: 22, offset: 19733},
+					pos: position{line: 777, col: 22, offset: 21253},
 					exprs: []any{
 						&labeledExpr{
-							pos:   position{line: 730, col: 22, offset: 19733},
+							pos:   position{line: 777, col: 22, offset: 21253},
 							label: "first",
 							expr: &ruleRefExpr{
-								pos:  position{line: 730, col: 28, offset: 19739},
+								pos:  position{line: 777, col: 28, offset: 21259},
 								name: "Value",
 							},
 						},
 						&labeledExpr{
-							pos:   position{line: 730, col: 34, offset: 19745},
+							pos:   position{line: 777, col: 34, offset: 21265},
 							label: "rest",
 							expr: &zeroOrMoreExpr{
-								pos: position{line: 730, col: 39, offset: 19750},
+								pos: position{line: 777, col: 39, offset: 21270},
 								expr: &actionExpr{
-									pos: position{line: 730, col: 41, offset: 19752},
+									pos: position{line: 777, col: 41, offset: 21272},
 									run: (*parser).callonLibraryConfigArgs7,
 									expr: &seqExpr{
-										pos: position{line: 730, col: 41, offset: 19752},
+										pos: position{line: 777, col: 41, offset: 21272},
 										exprs: []any{
 											&ruleRefExpr{
-												pos:  position{line: 730, col: 41, offset: 19752},
+												pos:  position{line: 777, col: 41, offset: 21272},
 												name: "_",
 											},
 											&litMatcher{
-												pos:        position{line: 730, col: 43, offset: 19754},
+												pos:        position{line: 777, col: 43, offset: 21274},
 												val:        ",",
 												ignoreCase: false,
 												want:       "\",\"",
 											},
 											&ruleRefExpr{
-												pos:  position{line: 730, col: 47, offset: 19758},
+												pos:  position{line: 777, col: 47, offset: 21278},
 												name: "_",
 											},
 											&labeledExpr{
-												pos:   position{line: 730, col: 49, offset: 19760},
+												pos:   position{line: 777, col: 49, offset: 21280},
 												label: "v",
 												expr: &ruleRefExpr{
-													pos:  position{line: 730, col: 51, offset: 19762},
+													pos:  position{line: 777, col: 51, offset: 21282},
 													name: "Value",
 												},
 											},
@@ -1142,13 +1361,13 @@ var g = &grammar{
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 730, col: 78, offset: 19789},
+							pos:  position{line: 777, col: 78, offset: 21309},
 							name: "_",
 						},
 						&zeroOrOneExpr{
-							pos: position{line: 730, col: 80, offset: 19791},
+							pos: position{line: 777, col: 80, offset: 21311},
 							expr: &litMatcher{
-								pos:        position{line: 730, col: 80, offset: 19791},
+								pos:        position{line: 777, col: 80, offset: 21311},
 								val:        ",",
 								ignoreCase: false,
 								want:       "\",\"",
@@ -1160,56 +1379,56 @@ var g = &grammar{
 		},
 		{
 			name: "TypeArgs",
-			pos:  position{line: 736, col: 1, offset: 19875},
+			pos:  position{line: 783, col: 1, offset: 21395},
 			expr: &actionExpr{
-				pos: position{line: 736, col: 13, offset: 19887},
+				pos: position{line: 783, col: 13, offset: 21407},
 				run: (*parser).callonTypeArgs1,
 				expr: &labeledExpr{
-					pos:   position{line: 736, col: 13, offset: 19887},
+					pos:   position{line: 783, col: 13, offset: 21407},
 					label: "args",
 					expr: &zeroOrOneExpr{
-						pos: position{line: 736, col: 18, offset: 19892},
+						pos: position{line: 783, col: 18, offset: 21412},
 						expr: &seqExpr{
-							pos: position{line: 736, col: 20, offset: 19894},
+							pos: position{line: 783, col: 20, offset: 21414},
 							exprs: []any{
 								&labeledExpr{
-									pos:   position{line: 736, col: 20, offset: 19894},
+									pos:   position{line: 783, col: 20, offset: 21414},
 									label: "first",
 									expr: &ruleRefExpr{
-										pos:  position{line: 736, col: 26, offset: 19900},
+										pos:  position{line: 783, col: 26, offset: 21420},
 										name: "TypeExprRule",
 									},
 								},
 								&labeledExpr{
-									pos:   position{line: 736, col: 39, offset: 19913},
+									pos:   position{line: 783, col: 39, offset: 21433},
 									label: "rest",
 									expr: &zeroOrMoreExpr{
-										pos: position{line: 736, col: 44, offset: 19918},
+										pos: position{line: 783, col: 44, offset: 21438},
 										expr: &actionExpr{
-											pos: position{line: 736, col: 46, offset: 19920},
+											pos: position{line: 783, col: 46, offset: 21440},
 											run: (*parser).callonTypeArgs9,
 											expr: &seqExpr{
-												pos: position{line: 736, col: 46, offset: 19920},
+												pos: position{line: 783, col: 46, offset: 21440},
 												exprs: []any{
 													&ruleRefExpr{
-														pos:  position{line: 736, col: 46, offset: 19920},
+														pos:  position{line: 783, col: 46, offset: 21440},
 														name: "_",
 													},
 													&litMatcher{
-														pos:        position{line: 736, col: 48, offset: 19922},
+														pos:        position{line: 783, col: 48, offset: 21442},
 														val:        ",",
 														ignoreCase: false,
 														want:       "\",\"",
 													},
 													&ruleRefExpr{
-														pos:  position{line: 736, col: 52, offset: 19926},
+														pos:  position{line: 783, col: 52, offset: 21446},
 														name: "_",
 													},
 													&labeledExpr{
-														pos:   position{line: 736, col: 54, offset: 19928},
+														pos:   position{line: 783, col: 54, offset: 21448},
 														label: "t",
 														expr: &ruleRefExpr{
-															pos:  position{line: 736, col: 56, offset: 19930},
+															pos:  position{line: 783, col: 56, offset: 21450},
 															name: "TypeExprRule",
 														},
 													},
@@ -1219,13 +1438,13 @@ var g = &grammar{
 									},
 								},
 								&ruleRefExpr{
-									pos:  position{line: 736, col: 90, offset: 19964},
+									pos:  position{line: 783, col: 90, offset: 21484},
 									name: "_",
 								},
 								&zeroOrOneExpr{
-									pos: position{line: 736, col: 92, offset: 19966},
+									pos: position{line: 783, col: 92, offset: 21486},
 									expr: &litMatcher{
-										pos:        position{line: 736, col: 92, offset: 19966},
+										pos:        position{line: 783, col: 92, offset: 21486},
 										val:        ",",
 										ignoreCase: false,
 										want:       "\",\"",
@@ -1239,40 +1458,40 @@ var g = &grammar{
 		},
 		{
 			name: "TypeObjectBody",
-			pos:  position{line: 749, col: 1, offset: 20197},
+			pos:  position{line: 796, col: 1, offset: 21717},
 			expr: &actionExpr{
-				pos: position{line: 749, col: 19, offset: 20215},
+				pos: position{line: 796, col: 19, offset: 21735},
 				run: (*parser).callonTypeObjectBody1,
 				expr: &seqExpr{
-					pos: position{line: 749, col: 19, offset: 20215},
+					pos: position{line: 796, col: 19, offset: 21735},
 					exprs: []any{
 						&litMatcher{
-							pos:        position{line: 749, col: 19, offset: 20215},
+							pos:        position{line: 796, col: 19, offset: 21735},
 							val:        "{",
 							ignoreCase: false,
 							want:       "\"{\"",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 749, col: 23, offset: 20219},
+							pos:  position{line: 796, col: 23, offset: 21739},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 749, col: 25, offset: 20221},
+							pos:   position{line: 796, col: 25, offset: 21741},
 							label: "fields",
 							expr: &zeroOrMoreExpr{
-								pos: position{line: 749, col: 32, offset: 20228},
+								pos: position{line: 796, col: 32, offset: 21748},
 								expr: &ruleRefExpr{
-									pos:  position{line: 749, col: 32, offset: 20228},
+									pos:  position{line: 796, col: 32, offset: 21748},
 									name: "TypeObjectField",
 								},
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 749, col: 49, offset: 20245},
+							pos:  position{line: 796, col: 49, offset: 21765},
 							name: "_",
 						},
 						&litMatcher{
-							pos:        position{line: 749, col: 51, offset: 20247},
+							pos:        position{line: 796, col: 51, offset: 21767},
 							val:        "}",
 							ignoreCase: false,
 							want:       "\"}\"",
@@ -1283,67 +1502,67 @@ var g = &grammar{
 		},
 		{
 			name: "TypeObjectField",
-			pos:  position{line: 758, col: 1, offset: 20396},
+			pos:  position{line: 805, col: 1, offset: 21916},
 			expr: &actionExpr{
-				pos: position{line: 758, col: 20, offset: 20415},
+				pos: position{line: 805, col: 20, offset: 21935},
 				run: (*parser).callonTypeObjectField1,
 				expr: &seqExpr{
-					pos: position{line: 758, col: 20, offset: 20415},
+					pos: position{line: 805, col: 20, offset: 21935},
 					exprs: []any{
 						&labeledExpr{
-							pos:   position{line: 758, col: 20, offset: 20415},
+							pos:   position{line: 805, col: 20, offset: 21935},
 							label: "key",
 							expr: &ruleRefExpr{
-								pos:  position{line: 758, col: 24, offset: 20419},
+								pos:  position{line: 805, col: 24, offset: 21939},
 								name: "PlainIdentKey",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 758, col: 38, offset: 20433},
+							pos:  position{line: 805, col: 38, offset: 21953},
 							name: "_",
 						},
 						&litMatcher{
-							pos:        position{line: 758, col: 40, offset: 20435},
+							pos:        position{line: 805, col: 40, offset: 21955},
 							val:        ":",
 							ignoreCase: false,
 							want:       "\":\"",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 758, col: 44, offset: 20439},
+							pos:  position{line: 805, col: 44, offset: 21959},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 758, col: 46, offset: 20441},
+							pos:   position{line: 805, col: 46, offset: 21961},
 							label: "value",
 							expr: &choiceExpr{
-								pos: position{line: 758, col: 54, offset: 20449},
+								pos: position{line: 805, col: 54, offset: 21969},
 								alternatives: []any{
 									&ruleRefExpr{
-										pos:  position{line: 758, col: 54, offset: 20449},
+										pos:  position{line: 805, col: 54, offset: 21969},
 										name: "TypeInputDecl",
 									},
 									&ruleRefExpr{
-										pos:  position{line: 758, col: 70, offset: 20465},
+										pos:  position{line: 805, col: 70, offset: 21985},
 										name: "TypeExprRule",
 									},
 								},
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 758, col: 85, offset: 20480},
+							pos:  position{line: 805, col: 85, offset: 22000},
 							name: "_",
 						},
 						&zeroOrOneExpr{
-							pos: position{line: 758, col: 87, offset: 20482},
+							pos: position{line: 805, col: 87, offset: 22002},
 							expr: &litMatcher{
-								pos:        position{line: 758, col: 87, offset: 20482},
+								pos:        position{line: 805, col: 87, offset: 22002},
 								val:        ",",
 								ignoreCase: false,
 								want:       "\",\"",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 758, col: 92, offset: 20487},
+							pos:  position{line: 805, col: 92, offset: 22007},
 							name: "_",
 						},
 					},
@@ -1352,40 +1571,40 @@ var g = &grammar{
 		},
 		{
 			name: "TypeInputDecl",
-			pos:  position{line: 770, col: 1, offset: 20685},
+			pos:  position{line: 817, col: 1, offset: 22205},
 			expr: &actionExpr{
-				pos: position{line: 770, col: 18, offset: 20702},
+				pos: position{line: 817, col: 18, offset: 22222},
 				run: (*parser).callonTypeInputDecl1,
 				expr: &seqExpr{
-					pos: position{line: 770, col: 18, offset: 20702},
+					pos: position{line: 817, col: 18, offset: 22222},
 					exprs: []any{
 						&litMatcher{
-							pos:        position{line: 770, col: 18, offset: 20702},
+							pos:        position{line: 817, col: 18, offset: 22222},
 							val:        "{",
 							ignoreCase: false,
 							want:       "\"{\"",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 770, col: 22, offset: 20706},
+							pos:  position{line: 817, col: 22, offset: 22226},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 770, col: 24, offset: 20708},
+							pos:   position{line: 817, col: 24, offset: 22228},
 							label: "fields",
 							expr: &zeroOrMoreExpr{
-								pos: position{line: 770, col: 31, offset: 20715},
+								pos: position{line: 817, col: 31, offset: 22235},
 								expr: &ruleRefExpr{
-									pos:  position{line: 770, col: 31, offset: 20715},
+									pos:  position{line: 817, col: 31, offset: 22235},
 									name: "TypeInputDeclField",
 								},
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 770, col: 51, offset: 20735},
+							pos:  position{line: 817, col: 51, offset: 22255},
 							name: "_",
 						},
 						&litMatcher{
-							pos:        position{line: 770, col: 53, offset: 20737},
+							pos:        position{line: 817, col: 53, offset: 22257},
 							val:        "}",
 							ignoreCase: false,
 							want:       "\"}\"",
@@ -1396,68 +1615,68 @@ var g = &grammar{
 		},
 		{
 			name: "TypeInputDeclField",
-			pos:  position{line: 778, col: 1, offset: 20975},
+			pos:  position{line: 825, col: 1, offset: 22495},
 			expr: &choiceExpr{
-				pos: position{line: 778, col: 23, offset: 20997},
+				pos: position{line: 825, col: 23, offset: 22517},
 				alternatives: []any{
 					&actionExpr{
-						pos: position{line: 778, col: 23, offset: 20997},
+						pos: position{line: 825, col: 23, offset: 22517},
 						run: (*parser).callonTypeInputDeclField2,
 						expr: &seqExpr{
-							pos: position{line: 778, col: 23, offset: 20997},
+							pos: position{line: 825, col: 23, offset: 22517},
 							exprs: []any{
 								&labeledExpr{
-									pos:   position{line: 778, col: 23, offset: 20997},
+									pos:   position{line: 825, col: 23, offset: 22517},
 									label: "key",
 									expr: &ruleRefExpr{
-										pos:  position{line: 778, col: 27, offset: 21001},
+										pos:  position{line: 825, col: 27, offset: 22521},
 										name: "TypeKey",
 									},
 								},
 								&ruleRefExpr{
-									pos:  position{line: 778, col: 35, offset: 21009},
+									pos:  position{line: 825, col: 35, offset: 22529},
 									name: "_",
 								},
 								&litMatcher{
-									pos:        position{line: 778, col: 37, offset: 21011},
+									pos:        position{line: 825, col: 37, offset: 22531},
 									val:        ":",
 									ignoreCase: false,
 									want:       "\":\"",
 								},
 								&ruleRefExpr{
-									pos:  position{line: 778, col: 41, offset: 21015},
+									pos:  position{line: 825, col: 41, offset: 22535},
 									name: "_",
 								},
 								&labeledExpr{
-									pos:   position{line: 778, col: 43, offset: 21017},
+									pos:   position{line: 825, col: 43, offset: 22537},
 									label: "value",
 									expr: &ruleRefExpr{
-										pos:  position{line: 778, col: 49, offset: 21023},
+										pos:  position{line: 825, col: 49, offset: 22543},
 										name: "TypeExprRule",
 									},
 								},
 								&ruleRefExpr{
-									pos:  position{line: 778, col: 62, offset: 21036},
+									pos:  position{line: 825, col: 62, offset: 22556},
 									name: "_",
 								},
 								&zeroOrOneExpr{
-									pos: position{line: 778, col: 64, offset: 21038},
+									pos: position{line: 825, col: 64, offset: 22558},
 									expr: &litMatcher{
-										pos:        position{line: 778, col: 64, offset: 21038},
+										pos:        position{line: 825, col: 64, offset: 22558},
 										val:        ",",
 										ignoreCase: false,
 										want:       "\",\"",
 									},
 								},
 								&ruleRefExpr{
-									pos:  position{line: 778, col: 69, offset: 21043},
+									pos:  position{line: 825, col: 69, offset: 22563},
 									name: "_",
 								},
 							},
 						},
 					},
 					&ruleRefExpr{
-						pos:  position{line: 781, col: 5, offset: 21132},
+						pos:  position{line: 828, col: 5, offset: 22652},
 						name: "ValuePair",
 					},
 				},
@@ -1465,23 +1684,23 @@ var g = &grammar{
 		},
 		{
 			name: "TypeKey",
-			pos:  position{line: 783, col: 1, offset: 21143},
+			pos:  position{line: 830, col: 1, offset: 22663},
 			expr: &actionExpr{
-				pos: position{line: 783, col: 12, offset: 21154},
+				pos: position{line: 830, col: 12, offset: 22674},
 				run: (*parser).callonTypeKey1,
 				expr: &seqExpr{
-					pos: position{line: 783, col: 12, offset: 21154},
+					pos: position{line: 830, col: 12, offset: 22674},
 					exprs: []any{
 						&litMatcher{
-							pos:        position{line: 783, col: 12, offset: 21154},
+							pos:        position{line: 830, col: 12, offset: 22674},
 							val:        "type",
 							ignoreCase: false,
 							want:       "\"type\"",
 						},
 						&notExpr{
-							pos: position{line: 783, col: 19, offset: 21161},
+							pos: position{line: 830, col: 19, offset: 22681},
 							expr: &ruleRefExpr{
-								pos:  position{line: 783, col: 20, offset: 21162},
+								pos:  position{line: 830, col: 20, offset: 22682},
 								name: "IdentChar",
 							},
 						},
@@ -1491,36 +1710,100 @@ var g = &grammar{
 		},
 		{
 			name: "AtomicType",
-			pos:  position{line: 787, col: 1, offset: 21244},
+			pos:  position{line: 834, col: 1, offset: 22764},
 			expr: &actionExpr{
-				pos: position{line: 787, col: 15, offset: 21258},
+				pos: position{line: 834, col: 15, offset: 22778},
 				run: (*parser).callonAtomicType1,
-				expr: &labeledExpr{
-					pos:   position{line: 787, col: 15, offset: 21258},
-					label: "name",
-					expr: &ruleRefExpr{
-						pos:  position{line: 787, col: 20, offset: 21263},
-						name: "IdentText",
+				expr: &seqExpr{
+					pos: position{line: 834, col: 15, offset: 22778},
+					exprs: []any{
+						&labeledExpr{
+							pos:   position{line: 834, col: 15, offset: 22778},
+							label: "name",
+							expr: &ruleRefExpr{
+								pos:  position{line: 834, col: 20, offset: 22783},
+								name: "IdentText",
+							},
+						},
+						&andCodeExpr{
+							pos: position{line: 834, col: 30, offset: 22793},
+							run: (*parser).callonAtomicType5,
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "NamedType",
+			pos:  position{line: 855, col: 1, offset: 23589},
+			expr: &actionExpr{
+				pos: position{line: 855, col: 14, offset: 23602},
+				run: (*parser).callonNamedType1,
+				expr: &seqExpr{
+					pos: position{line: 855, col: 14, offset: 23602},
+					exprs: []any{
+						&andCodeExpr{
+							pos: position{line: 855, col: 14, offset: 23602},
+							run: (*parser).callonNamedType3,
+						},
+						&labeledExpr{
+							pos:   position{line: 858, col: 3, offset: 23675},
+							label: "library",
+							expr: &zeroOrOneExpr{
+								pos: position{line: 858, col: 11, offset: 23683},
+								expr: &actionExpr{
+									pos: position{line: 858, col: 13, offset: 23685},
+									run: (*parser).callonNamedType6,
+									expr: &seqExpr{
+										pos: position{line: 858, col: 13, offset: 23685},
+										exprs: []any{
+											&labeledExpr{
+												pos:   position{line: 858, col: 13, offset: 23685},
+												label: "l",
+												expr: &ruleRefExpr{
+													pos:  position{line: 858, col: 15, offset: 23687},
+													name: "IdentText",
+												},
+											},
+											&litMatcher{
+												pos:        position{line: 858, col: 25, offset: 23697},
+												val:        ".",
+												ignoreCase: false,
+												want:       "\".\"",
+											},
+										},
+									},
+								},
+							},
+						},
+						&labeledExpr{
+							pos:   position{line: 858, col: 50, offset: 23722},
+							label: "name",
+							expr: &ruleRefExpr{
+								pos:  position{line: 858, col: 55, offset: 23727},
+								name: "IdentText",
+							},
+						},
 					},
 				},
 			},
 		},
 		{
 			name: "Pair",
-			pos:  position{line: 804, col: 1, offset: 21922},
+			pos:  position{line: 875, col: 1, offset: 24262},
 			expr: &choiceExpr{
-				pos: position{line: 804, col: 9, offset: 21930},
+				pos: position{line: 875, col: 9, offset: 24270},
 				alternatives: []any{
 					&ruleRefExpr{
-						pos:  position{line: 804, col: 9, offset: 21930},
+						pos:  position{line: 875, col: 9, offset: 24270},
 						name: "NamedSelectorBody",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 804, col: 29, offset: 21950},
+						pos:  position{line: 875, col: 29, offset: 24290},
 						name: "DefaultSelectorBody",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 804, col: 51, offset: 21972},
+						pos:  position{line: 875, col: 51, offset: 24312},
 						name: "ValuePair",
 					},
 				},
@@ -1528,70 +1811,70 @@ var g = &grammar{
 		},
 		{
 			name: "NamedSelectorBody",
-			pos:  position{line: 806, col: 1, offset: 21983},
+			pos:  position{line: 877, col: 1, offset: 24323},
 			expr: &actionExpr{
-				pos: position{line: 806, col: 22, offset: 22004},
+				pos: position{line: 877, col: 22, offset: 24344},
 				run: (*parser).callonNamedSelectorBody1,
 				expr: &seqExpr{
-					pos: position{line: 806, col: 22, offset: 22004},
+					pos: position{line: 877, col: 22, offset: 24344},
 					exprs: []any{
 						&labeledExpr{
-							pos:   position{line: 806, col: 22, offset: 22004},
+							pos:   position{line: 877, col: 22, offset: 24344},
 							label: "key",
 							expr: &ruleRefExpr{
-								pos:  position{line: 806, col: 26, offset: 22008},
+								pos:  position{line: 877, col: 26, offset: 24348},
 								name: "PlainIdentKey",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 806, col: 40, offset: 22022},
+							pos:  position{line: 877, col: 40, offset: 24362},
 							name: "_",
 						},
 						&litMatcher{
-							pos:        position{line: 806, col: 42, offset: 22024},
+							pos:        position{line: 877, col: 42, offset: 24364},
 							val:        ":",
 							ignoreCase: false,
 							want:       "\":\"",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 806, col: 46, offset: 22028},
+							pos:  position{line: 877, col: 46, offset: 24368},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 806, col: 48, offset: 22030},
+							pos:   position{line: 877, col: 48, offset: 24370},
 							label: "sel",
 							expr: &ruleRefExpr{
-								pos:  position{line: 806, col: 52, offset: 22034},
+								pos:  position{line: 877, col: 52, offset: 24374},
 								name: "Selector",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 806, col: 61, offset: 22043},
+							pos:  position{line: 877, col: 61, offset: 24383},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 806, col: 63, offset: 22045},
+							pos:   position{line: 877, col: 63, offset: 24385},
 							label: "body",
 							expr: &ruleRefExpr{
-								pos:  position{line: 806, col: 68, offset: 22050},
+								pos:  position{line: 877, col: 68, offset: 24390},
 								name: "ObjectLitNode",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 806, col: 82, offset: 22064},
+							pos:  position{line: 877, col: 82, offset: 24404},
 							name: "_",
 						},
 						&zeroOrOneExpr{
-							pos: position{line: 806, col: 84, offset: 22066},
+							pos: position{line: 877, col: 84, offset: 24406},
 							expr: &litMatcher{
-								pos:        position{line: 806, col: 84, offset: 22066},
+								pos:        position{line: 877, col: 84, offset: 24406},
 								val:        ",",
 								ignoreCase: false,
 								want:       "\",\"",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 806, col: 89, offset: 22071},
+							pos:  position{line: 877, col: 89, offset: 24411},
 							name: "_",
 						},
 					},
@@ -1600,48 +1883,48 @@ var g = &grammar{
 		},
 		{
 			name: "DefaultSelectorBody",
-			pos:  position{line: 814, col: 1, offset: 22300},
+			pos:  position{line: 885, col: 1, offset: 24640},
 			expr: &actionExpr{
-				pos: position{line: 814, col: 24, offset: 22323},
+				pos: position{line: 885, col: 24, offset: 24663},
 				run: (*parser).callonDefaultSelectorBody1,
 				expr: &seqExpr{
-					pos: position{line: 814, col: 24, offset: 22323},
+					pos: position{line: 885, col: 24, offset: 24663},
 					exprs: []any{
 						&labeledExpr{
-							pos:   position{line: 814, col: 24, offset: 22323},
+							pos:   position{line: 885, col: 24, offset: 24663},
 							label: "sel",
 							expr: &ruleRefExpr{
-								pos:  position{line: 814, col: 28, offset: 22327},
+								pos:  position{line: 885, col: 28, offset: 24667},
 								name: "Selector",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 814, col: 37, offset: 22336},
+							pos:  position{line: 885, col: 37, offset: 24676},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 814, col: 39, offset: 22338},
+							pos:   position{line: 885, col: 39, offset: 24678},
 							label: "body",
 							expr: &ruleRefExpr{
-								pos:  position{line: 814, col: 44, offset: 22343},
+								pos:  position{line: 885, col: 44, offset: 24683},
 								name: "ObjectLitNode",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 814, col: 58, offset: 22357},
+							pos:  position{line: 885, col: 58, offset: 24697},
 							name: "_",
 						},
 						&zeroOrOneExpr{
-							pos: position{line: 814, col: 60, offset: 22359},
+							pos: position{line: 885, col: 60, offset: 24699},
 							expr: &litMatcher{
-								pos:        position{line: 814, col: 60, offset: 22359},
+								pos:        position{line: 885, col: 60, offset: 24699},
 								val:        ",",
 								ignoreCase: false,
 								want:       "\",\"",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 814, col: 65, offset: 22364},
+							pos:  position{line: 885, col: 65, offset: 24704},
 							name: "_",
 						},
 					},
@@ -1650,58 +1933,58 @@ var g = &grammar{
 		},
 		{
 			name: "ValuePair",
-			pos:  position{line: 822, col: 1, offset: 22633},
+			pos:  position{line: 893, col: 1, offset: 24973},
 			expr: &actionExpr{
-				pos: position{line: 822, col: 14, offset: 22646},
+				pos: position{line: 893, col: 14, offset: 24986},
 				run: (*parser).callonValuePair1,
 				expr: &seqExpr{
-					pos: position{line: 822, col: 14, offset: 22646},
+					pos: position{line: 893, col: 14, offset: 24986},
 					exprs: []any{
 						&labeledExpr{
-							pos:   position{line: 822, col: 14, offset: 22646},
+							pos:   position{line: 893, col: 14, offset: 24986},
 							label: "key",
 							expr: &ruleRefExpr{
-								pos:  position{line: 822, col: 18, offset: 22650},
+								pos:  position{line: 893, col: 18, offset: 24990},
 								name: "Key",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 822, col: 22, offset: 22654},
+							pos:  position{line: 893, col: 22, offset: 24994},
 							name: "_",
 						},
 						&litMatcher{
-							pos:        position{line: 822, col: 24, offset: 22656},
+							pos:        position{line: 893, col: 24, offset: 24996},
 							val:        ":",
 							ignoreCase: false,
 							want:       "\":\"",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 822, col: 28, offset: 22660},
+							pos:  position{line: 893, col: 28, offset: 25000},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 822, col: 30, offset: 22662},
+							pos:   position{line: 893, col: 30, offset: 25002},
 							label: "value",
 							expr: &ruleRefExpr{
-								pos:  position{line: 822, col: 36, offset: 22668},
+								pos:  position{line: 893, col: 36, offset: 25008},
 								name: "Value",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 822, col: 42, offset: 22674},
+							pos:  position{line: 893, col: 42, offset: 25014},
 							name: "_",
 						},
 						&zeroOrOneExpr{
-							pos: position{line: 822, col: 44, offset: 22676},
+							pos: position{line: 893, col: 44, offset: 25016},
 							expr: &litMatcher{
-								pos:        position{line: 822, col: 44, offset: 22676},
+								pos:        position{line: 893, col: 44, offset: 25016},
 								val:        ",",
 								ignoreCase: false,
 								want:       "\",\"",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 822, col: 49, offset: 22681},
+							pos:  position{line: 893, col: 49, offset: 25021},
 							name: "_",
 						},
 					},
@@ -1710,24 +1993,24 @@ var g = &grammar{
 		},
 		{
 			name: "Key",
-			pos:  position{line: 828, col: 1, offset: 22770},
+			pos:  position{line: 899, col: 1, offset: 25110},
 			expr: &choiceExpr{
-				pos: position{line: 828, col: 8, offset: 22777},
+				pos: position{line: 899, col: 8, offset: 25117},
 				alternatives: []any{
 					&ruleRefExpr{
-						pos:  position{line: 828, col: 8, offset: 22777},
+						pos:  position{line: 899, col: 8, offset: 25117},
 						name: "DottedKey",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 828, col: 20, offset: 22789},
+						pos:  position{line: 899, col: 20, offset: 25129},
 						name: "IdentKey",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 828, col: 31, offset: 22800},
+						pos:  position{line: 899, col: 31, offset: 25140},
 						name: "StringKey",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 828, col: 43, offset: 22812},
+						pos:  position{line: 899, col: 43, offset: 25152},
 						name: "DoubleQuotedKey",
 					},
 				},
@@ -1735,28 +2018,28 @@ var g = &grammar{
 		},
 		{
 			name: "DottedKey",
-			pos:  position{line: 833, col: 1, offset: 22998},
+			pos:  position{line: 904, col: 1, offset: 25338},
 			expr: &actionExpr{
-				pos: position{line: 833, col: 14, offset: 23011},
+				pos: position{line: 904, col: 14, offset: 25351},
 				run: (*parser).callonDottedKey1,
 				expr: &seqExpr{
-					pos: position{line: 833, col: 14, offset: 23011},
+					pos: position{line: 904, col: 14, offset: 25351},
 					exprs: []any{
 						&labeledExpr{
-							pos:   position{line: 833, col: 14, offset: 23011},
+							pos:   position{line: 904, col: 14, offset: 25351},
 							label: "root",
 							expr: &ruleRefExpr{
-								pos:  position{line: 833, col: 19, offset: 23016},
+								pos:  position{line: 904, col: 19, offset: 25356},
 								name: "IdentText",
 							},
 						},
 						&labeledExpr{
-							pos:   position{line: 833, col: 29, offset: 23026},
+							pos:   position{line: 904, col: 29, offset: 25366},
 							label: "segs",
 							expr: &oneOrMoreExpr{
-								pos: position{line: 833, col: 34, offset: 23031},
+								pos: position{line: 904, col: 34, offset: 25371},
 								expr: &ruleRefExpr{
-									pos:  position{line: 833, col: 34, offset: 23031},
+									pos:  position{line: 904, col: 34, offset: 25371},
 									name: "DottedKeyTail",
 								},
 							},
@@ -1767,24 +2050,24 @@ var g = &grammar{
 		},
 		{
 			name: "DottedKeyTail",
-			pos:  position{line: 841, col: 1, offset: 23223},
+			pos:  position{line: 912, col: 1, offset: 25563},
 			expr: &actionExpr{
-				pos: position{line: 841, col: 18, offset: 23240},
+				pos: position{line: 912, col: 18, offset: 25580},
 				run: (*parser).callonDottedKeyTail1,
 				expr: &seqExpr{
-					pos: position{line: 841, col: 18, offset: 23240},
+					pos: position{line: 912, col: 18, offset: 25580},
 					exprs: []any{
 						&litMatcher{
-							pos:        position{line: 841, col: 18, offset: 23240},
+							pos:        position{line: 912, col: 18, offset: 25580},
 							val:        ".",
 							ignoreCase: false,
 							want:       "\".\"",
 						},
 						&labeledExpr{
-							pos:   position{line: 841, col: 22, offset: 23244},
+							pos:   position{line: 912, col: 22, offset: 25584},
 							label: "name",
 							expr: &ruleRefExpr{
-								pos:  position{line: 841, col: 27, offset: 23249},
+								pos:  position{line: 912, col: 27, offset: 25589},
 								name: "IdentText",
 							},
 						},
@@ -1794,15 +2077,15 @@ var g = &grammar{
 		},
 		{
 			name: "IdentKey",
-			pos:  position{line: 845, col: 1, offset: 23282},
+			pos:  position{line: 916, col: 1, offset: 25622},
 			expr: &actionExpr{
-				pos: position{line: 845, col: 13, offset: 23294},
+				pos: position{line: 916, col: 13, offset: 25634},
 				run: (*parser).callonIdentKey1,
 				expr: &labeledExpr{
-					pos:   position{line: 845, col: 13, offset: 23294},
+					pos:   position{line: 916, col: 13, offset: 25634},
 					label: "name",
 					expr: &ruleRefExpr{
-						pos:  position{line: 845, col: 18, offset: 23299},
+						pos:  position{line: 916, col: 18, offset: 25639},
 						name: "IdentText",
 					},
 				},
@@ -1810,15 +2093,15 @@ var g = &grammar{
 		},
 		{
 			name: "PlainIdentKey",
-			pos:  position{line: 849, col: 1, offset: 23388},
+			pos:  position{line: 920, col: 1, offset: 25728},
 			expr: &actionExpr{
-				pos: position{line: 849, col: 18, offset: 23405},
+				pos: position{line: 920, col: 18, offset: 25745},
 				run: (*parser).callonPlainIdentKey1,
 				expr: &labeledExpr{
-					pos:   position{line: 849, col: 18, offset: 23405},
+					pos:   position{line: 920, col: 18, offset: 25745},
 					label: "name",
 					expr: &ruleRefExpr{
-						pos:  position{line: 849, col: 23, offset: 23410},
+						pos:  position{line: 920, col: 23, offset: 25750},
 						name: "SelectorIdentText",
 					},
 				},
@@ -1826,15 +2109,15 @@ var g = &grammar{
 		},
 		{
 			name: "StringKey",
-			pos:  position{line: 853, col: 1, offset: 23507},
+			pos:  position{line: 924, col: 1, offset: 25847},
 			expr: &actionExpr{
-				pos: position{line: 853, col: 14, offset: 23520},
+				pos: position{line: 924, col: 14, offset: 25860},
 				run: (*parser).callonStringKey1,
 				expr: &labeledExpr{
-					pos:   position{line: 853, col: 14, offset: 23520},
+					pos:   position{line: 924, col: 14, offset: 25860},
 					label: "s",
 					expr: &ruleRefExpr{
-						pos:  position{line: 853, col: 16, offset: 23522},
+						pos:  position{line: 924, col: 16, offset: 25862},
 						name: "StringLitNode",
 					},
 				},
@@ -1842,16 +2125,16 @@ var g = &grammar{
 		},
 		{
 			name: "Value",
-			pos:  position{line: 859, col: 1, offset: 23632},
+			pos:  position{line: 930, col: 1, offset: 25972},
 			expr: &choiceExpr{
-				pos: position{line: 859, col: 10, offset: 23641},
+				pos: position{line: 930, col: 10, offset: 25981},
 				alternatives: []any{
 					&ruleRefExpr{
-						pos:  position{line: 859, col: 10, offset: 23641},
+						pos:  position{line: 930, col: 10, offset: 25981},
 						name: "Conditional",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 859, col: 24, offset: 23655},
+						pos:  position{line: 930, col: 24, offset: 25995},
 						name: "Expr",
 					},
 				},
@@ -1859,93 +2142,93 @@ var g = &grammar{
 		},
 		{
 			name: "Conditional",
-			pos:  position{line: 867, col: 1, offset: 24049},
+			pos:  position{line: 938, col: 1, offset: 26389},
 			expr: &actionExpr{
-				pos: position{line: 867, col: 16, offset: 24064},
+				pos: position{line: 938, col: 16, offset: 26404},
 				run: (*parser).callonConditional1,
 				expr: &seqExpr{
-					pos: position{line: 867, col: 16, offset: 24064},
+					pos: position{line: 938, col: 16, offset: 26404},
 					exprs: []any{
 						&litMatcher{
-							pos:        position{line: 867, col: 16, offset: 24064},
+							pos:        position{line: 938, col: 16, offset: 26404},
 							val:        "if",
 							ignoreCase: false,
 							want:       "\"if\"",
 						},
 						&notExpr{
-							pos: position{line: 867, col: 21, offset: 24069},
+							pos: position{line: 938, col: 21, offset: 26409},
 							expr: &ruleRefExpr{
-								pos:  position{line: 867, col: 22, offset: 24070},
+								pos:  position{line: 938, col: 22, offset: 26410},
 								name: "IdentChar",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 867, col: 32, offset: 24080},
+							pos:  position{line: 938, col: 32, offset: 26420},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 867, col: 34, offset: 24082},
+							pos:   position{line: 938, col: 34, offset: 26422},
 							label: "cond",
 							expr: &ruleRefExpr{
-								pos:  position{line: 867, col: 39, offset: 24087},
+								pos:  position{line: 938, col: 39, offset: 26427},
 								name: "Expr",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 867, col: 44, offset: 24092},
+							pos:  position{line: 938, col: 44, offset: 26432},
 							name: "_",
 						},
 						&litMatcher{
-							pos:        position{line: 867, col: 46, offset: 24094},
+							pos:        position{line: 938, col: 46, offset: 26434},
 							val:        "then",
 							ignoreCase: false,
 							want:       "\"then\"",
 						},
 						&notExpr{
-							pos: position{line: 867, col: 53, offset: 24101},
+							pos: position{line: 938, col: 53, offset: 26441},
 							expr: &ruleRefExpr{
-								pos:  position{line: 867, col: 54, offset: 24102},
+								pos:  position{line: 938, col: 54, offset: 26442},
 								name: "IdentChar",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 867, col: 64, offset: 24112},
+							pos:  position{line: 938, col: 64, offset: 26452},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 867, col: 66, offset: 24114},
+							pos:   position{line: 938, col: 66, offset: 26454},
 							label: "then",
 							expr: &ruleRefExpr{
-								pos:  position{line: 867, col: 71, offset: 24119},
+								pos:  position{line: 938, col: 71, offset: 26459},
 								name: "Value",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 867, col: 77, offset: 24125},
+							pos:  position{line: 938, col: 77, offset: 26465},
 							name: "_",
 						},
 						&litMatcher{
-							pos:        position{line: 867, col: 79, offset: 24127},
+							pos:        position{line: 938, col: 79, offset: 26467},
 							val:        "else",
 							ignoreCase: false,
 							want:       "\"else\"",
 						},
 						&notExpr{
-							pos: position{line: 867, col: 86, offset: 24134},
+							pos: position{line: 938, col: 86, offset: 26474},
 							expr: &ruleRefExpr{
-								pos:  position{line: 867, col: 87, offset: 24135},
+								pos:  position{line: 938, col: 87, offset: 26475},
 								name: "IdentChar",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 867, col: 97, offset: 24145},
+							pos:  position{line: 938, col: 97, offset: 26485},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 867, col: 99, offset: 24147},
+							pos:   position{line: 938, col: 99, offset: 26487},
 							label: "els",
 							expr: &ruleRefExpr{
-								pos:  position{line: 867, col: 103, offset: 24151},
+								pos:  position{line: 938, col: 103, offset: 26491},
 								name: "Value",
 							},
 						},
@@ -1955,28 +2238,28 @@ var g = &grammar{
 		},
 		{
 			name: "Expr",
-			pos:  position{line: 882, col: 1, offset: 24649},
+			pos:  position{line: 953, col: 1, offset: 26989},
 			expr: &actionExpr{
-				pos: position{line: 882, col: 9, offset: 24657},
+				pos: position{line: 953, col: 9, offset: 26997},
 				run: (*parser).callonExpr1,
 				expr: &seqExpr{
-					pos: position{line: 882, col: 9, offset: 24657},
+					pos: position{line: 953, col: 9, offset: 26997},
 					exprs: []any{
 						&labeledExpr{
-							pos:   position{line: 882, col: 9, offset: 24657},
+							pos:   position{line: 953, col: 9, offset: 26997},
 							label: "left",
 							expr: &ruleRefExpr{
-								pos:  position{line: 882, col: 14, offset: 24662},
+								pos:  position{line: 953, col: 14, offset: 27002},
 								name: "OrExpr",
 							},
 						},
 						&labeledExpr{
-							pos:   position{line: 882, col: 21, offset: 24669},
+							pos:   position{line: 953, col: 21, offset: 27009},
 							label: "rest",
 							expr: &zeroOrMoreExpr{
-								pos: position{line: 882, col: 26, offset: 24674},
+								pos: position{line: 953, col: 26, offset: 27014},
 								expr: &ruleRefExpr{
-									pos:  position{line: 882, col: 26, offset: 24674},
+									pos:  position{line: 953, col: 26, offset: 27014},
 									name: "CoalesceTail",
 								},
 							},
@@ -1987,32 +2270,32 @@ var g = &grammar{
 		},
 		{
 			name: "CoalesceTail",
-			pos:  position{line: 886, col: 1, offset: 24730},
+			pos:  position{line: 957, col: 1, offset: 27070},
 			expr: &actionExpr{
-				pos: position{line: 886, col: 17, offset: 24746},
+				pos: position{line: 957, col: 17, offset: 27086},
 				run: (*parser).callonCoalesceTail1,
 				expr: &seqExpr{
-					pos: position{line: 886, col: 17, offset: 24746},
+					pos: position{line: 957, col: 17, offset: 27086},
 					exprs: []any{
 						&ruleRefExpr{
-							pos:  position{line: 886, col: 17, offset: 24746},
+							pos:  position{line: 957, col: 17, offset: 27086},
 							name: "_",
 						},
 						&litMatcher{
-							pos:        position{line: 886, col: 19, offset: 24748},
+							pos:        position{line: 957, col: 19, offset: 27088},
 							val:        "??",
 							ignoreCase: false,
 							want:       "\"??\"",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 886, col: 24, offset: 24753},
+							pos:  position{line: 957, col: 24, offset: 27093},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 886, col: 26, offset: 24755},
+							pos:   position{line: 957, col: 26, offset: 27095},
 							label: "right",
 							expr: &ruleRefExpr{
-								pos:  position{line: 886, col: 32, offset: 24761},
+								pos:  position{line: 957, col: 32, offset: 27101},
 								name: "OrExpr",
 							},
 						},
@@ -2022,28 +2305,28 @@ var g = &grammar{
 		},
 		{
 			name: "OrExpr",
-			pos:  position{line: 890, col: 1, offset: 24831},
+			pos:  position{line: 961, col: 1, offset: 27171},
 			expr: &actionExpr{
-				pos: position{line: 890, col: 11, offset: 24841},
+				pos: position{line: 961, col: 11, offset: 27181},
 				run: (*parser).callonOrExpr1,
 				expr: &seqExpr{
-					pos: position{line: 890, col: 11, offset: 24841},
+					pos: position{line: 961, col: 11, offset: 27181},
 					exprs: []any{
 						&labeledExpr{
-							pos:   position{line: 890, col: 11, offset: 24841},
+							pos:   position{line: 961, col: 11, offset: 27181},
 							label: "left",
 							expr: &ruleRefExpr{
-								pos:  position{line: 890, col: 16, offset: 24846},
+								pos:  position{line: 961, col: 16, offset: 27186},
 								name: "AndExpr",
 							},
 						},
 						&labeledExpr{
-							pos:   position{line: 890, col: 24, offset: 24854},
+							pos:   position{line: 961, col: 24, offset: 27194},
 							label: "rest",
 							expr: &zeroOrMoreExpr{
-								pos: position{line: 890, col: 29, offset: 24859},
+								pos: position{line: 961, col: 29, offset: 27199},
 								expr: &ruleRefExpr{
-									pos:  position{line: 890, col: 29, offset: 24859},
+									pos:  position{line: 961, col: 29, offset: 27199},
 									name: "OrTail",
 								},
 							},
@@ -2054,32 +2337,32 @@ var g = &grammar{
 		},
 		{
 			name: "OrTail",
-			pos:  position{line: 894, col: 1, offset: 24909},
+			pos:  position{line: 965, col: 1, offset: 27249},
 			expr: &actionExpr{
-				pos: position{line: 894, col: 11, offset: 24919},
+				pos: position{line: 965, col: 11, offset: 27259},
 				run: (*parser).callonOrTail1,
 				expr: &seqExpr{
-					pos: position{line: 894, col: 11, offset: 24919},
+					pos: position{line: 965, col: 11, offset: 27259},
 					exprs: []any{
 						&ruleRefExpr{
-							pos:  position{line: 894, col: 11, offset: 24919},
+							pos:  position{line: 965, col: 11, offset: 27259},
 							name: "_",
 						},
 						&litMatcher{
-							pos:        position{line: 894, col: 13, offset: 24921},
+							pos:        position{line: 965, col: 13, offset: 27261},
 							val:        "||",
 							ignoreCase: false,
 							want:       "\"||\"",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 894, col: 18, offset: 24926},
+							pos:  position{line: 965, col: 18, offset: 27266},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 894, col: 20, offset: 24928},
+							pos:   position{line: 965, col: 20, offset: 27268},
 							label: "right",
 							expr: &ruleRefExpr{
-								pos:  position{line: 894, col: 26, offset: 24934},
+								pos:  position{line: 965, col: 26, offset: 27274},
 								name: "AndExpr",
 							},
 						},
@@ -2089,28 +2372,28 @@ var g = &grammar{
 		},
 		{
 			name: "AndExpr",
-			pos:  position{line: 898, col: 1, offset: 25005},
+			pos:  position{line: 969, col: 1, offset: 27345},
 			expr: &actionExpr{
-				pos: position{line: 898, col: 12, offset: 25016},
+				pos: position{line: 969, col: 12, offset: 27356},
 				run: (*parser).callonAndExpr1,
 				expr: &seqExpr{
-					pos: position{line: 898, col: 12, offset: 25016},
+					pos: position{line: 969, col: 12, offset: 27356},
 					exprs: []any{
 						&labeledExpr{
-							pos:   position{line: 898, col: 12, offset: 25016},
+							pos:   position{line: 969, col: 12, offset: 27356},
 							label: "left",
 							expr: &ruleRefExpr{
-								pos:  position{line: 898, col: 17, offset: 25021},
+								pos:  position{line: 969, col: 17, offset: 27361},
 								name: "EqualityExpr",
 							},
 						},
 						&labeledExpr{
-							pos:   position{line: 898, col: 30, offset: 25034},
+							pos:   position{line: 969, col: 30, offset: 27374},
 							label: "rest",
 							expr: &zeroOrMoreExpr{
-								pos: position{line: 898, col: 35, offset: 25039},
+								pos: position{line: 969, col: 35, offset: 27379},
 								expr: &ruleRefExpr{
-									pos:  position{line: 898, col: 35, offset: 25039},
+									pos:  position{line: 969, col: 35, offset: 27379},
 									name: "AndTail",
 								},
 							},
@@ -2121,32 +2404,32 @@ var g = &grammar{
 		},
 		{
 			name: "AndTail",
-			pos:  position{line: 902, col: 1, offset: 25090},
+			pos:  position{line: 973, col: 1, offset: 27430},
 			expr: &actionExpr{
-				pos: position{line: 902, col: 12, offset: 25101},
+				pos: position{line: 973, col: 12, offset: 27441},
 				run: (*parser).callonAndTail1,
 				expr: &seqExpr{
-					pos: position{line: 902, col: 12, offset: 25101},
+					pos: position{line: 973, col: 12, offset: 27441},
 					exprs: []any{
 						&ruleRefExpr{
-							pos:  position{line: 902, col: 12, offset: 25101},
+							pos:  position{line: 973, col: 12, offset: 27441},
 							name: "_",
 						},
 						&litMatcher{
-							pos:        position{line: 902, col: 14, offset: 25103},
+							pos:        position{line: 973, col: 14, offset: 27443},
 							val:        "&&",
 							ignoreCase: false,
 							want:       "\"&&\"",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 902, col: 19, offset: 25108},
+							pos:  position{line: 973, col: 19, offset: 27448},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 902, col: 21, offset: 25110},
+							pos:   position{line: 973, col: 21, offset: 27450},
 							label: "right",
 							expr: &ruleRefExpr{
-								pos:  position{line: 902, col: 27, offset: 25116},
+								pos:  position{line: 973, col: 27, offset: 27456},
 								name: "EqualityExpr",
 							},
 						},
@@ -2156,28 +2439,28 @@ var g = &grammar{
 		},
 		{
 			name: "EqualityExpr",
-			pos:  position{line: 906, col: 1, offset: 25192},
+			pos:  position{line: 977, col: 1, offset: 27532},
 			expr: &actionExpr{
-				pos: position{line: 906, col: 17, offset: 25208},
+				pos: position{line: 977, col: 17, offset: 27548},
 				run: (*parser).callonEqualityExpr1,
 				expr: &seqExpr{
-					pos: position{line: 906, col: 17, offset: 25208},
+					pos: position{line: 977, col: 17, offset: 27548},
 					exprs: []any{
 						&labeledExpr{
-							pos:   position{line: 906, col: 17, offset: 25208},
+							pos:   position{line: 977, col: 17, offset: 27548},
 							label: "left",
 							expr: &ruleRefExpr{
-								pos:  position{line: 906, col: 22, offset: 25213},
+								pos:  position{line: 977, col: 22, offset: 27553},
 								name: "ComparisonExpr",
 							},
 						},
 						&labeledExpr{
-							pos:   position{line: 906, col: 37, offset: 25228},
+							pos:   position{line: 977, col: 37, offset: 27568},
 							label: "rest",
 							expr: &zeroOrMoreExpr{
-								pos: position{line: 906, col: 42, offset: 25233},
+								pos: position{line: 977, col: 42, offset: 27573},
 								expr: &ruleRefExpr{
-									pos:  position{line: 906, col: 42, offset: 25233},
+									pos:  position{line: 977, col: 42, offset: 27573},
 									name: "EqualityTail",
 								},
 							},
@@ -2188,31 +2471,31 @@ var g = &grammar{
 		},
 		{
 			name: "EqualityTail",
-			pos:  position{line: 910, col: 1, offset: 25289},
+			pos:  position{line: 981, col: 1, offset: 27629},
 			expr: &actionExpr{
-				pos: position{line: 910, col: 17, offset: 25305},
+				pos: position{line: 981, col: 17, offset: 27645},
 				run: (*parser).callonEqualityTail1,
 				expr: &seqExpr{
-					pos: position{line: 910, col: 17, offset: 25305},
+					pos: position{line: 981, col: 17, offset: 27645},
 					exprs: []any{
 						&ruleRefExpr{
-							pos:  position{line: 910, col: 17, offset: 25305},
+							pos:  position{line: 981, col: 17, offset: 27645},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 910, col: 19, offset: 25307},
+							pos:   position{line: 981, col: 19, offset: 27647},
 							label: "op",
 							expr: &choiceExpr{
-								pos: position{line: 910, col: 24, offset: 25312},
+								pos: position{line: 981, col: 24, offset: 27652},
 								alternatives: []any{
 									&litMatcher{
-										pos:        position{line: 910, col: 24, offset: 25312},
+										pos:        position{line: 981, col: 24, offset: 27652},
 										val:        "==",
 										ignoreCase: false,
 										want:       "\"==\"",
 									},
 									&litMatcher{
-										pos:        position{line: 910, col: 31, offset: 25319},
+										pos:        position{line: 981, col: 31, offset: 27659},
 										val:        "!=",
 										ignoreCase: false,
 										want:       "\"!=\"",
@@ -2221,14 +2504,14 @@ var g = &grammar{
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 910, col: 38, offset: 25326},
+							pos:  position{line: 981, col: 38, offset: 27666},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 910, col: 40, offset: 25328},
+							pos:   position{line: 981, col: 40, offset: 27668},
 							label: "right",
 							expr: &ruleRefExpr{
-								pos:  position{line: 910, col: 46, offset: 25334},
+								pos:  position{line: 981, col: 46, offset: 27674},
 								name: "ComparisonExpr",
 							},
 						},
@@ -2238,28 +2521,28 @@ var g = &grammar{
 		},
 		{
 			name: "ComparisonExpr",
-			pos:  position{line: 914, col: 1, offset: 25427},
+			pos:  position{line: 985, col: 1, offset: 27767},
 			expr: &actionExpr{
-				pos: position{line: 914, col: 19, offset: 25445},
+				pos: position{line: 985, col: 19, offset: 27785},
 				run: (*parser).callonComparisonExpr1,
 				expr: &seqExpr{
-					pos: position{line: 914, col: 19, offset: 25445},
+					pos: position{line: 985, col: 19, offset: 27785},
 					exprs: []any{
 						&labeledExpr{
-							pos:   position{line: 914, col: 19, offset: 25445},
+							pos:   position{line: 985, col: 19, offset: 27785},
 							label: "left",
 							expr: &ruleRefExpr{
-								pos:  position{line: 914, col: 24, offset: 25450},
+								pos:  position{line: 985, col: 24, offset: 27790},
 								name: "AdditiveExpr",
 							},
 						},
 						&labeledExpr{
-							pos:   position{line: 914, col: 37, offset: 25463},
+							pos:   position{line: 985, col: 37, offset: 27803},
 							label: "rest",
 							expr: &zeroOrMoreExpr{
-								pos: position{line: 914, col: 42, offset: 25468},
+								pos: position{line: 985, col: 42, offset: 27808},
 								expr: &ruleRefExpr{
-									pos:  position{line: 914, col: 42, offset: 25468},
+									pos:  position{line: 985, col: 42, offset: 27808},
 									name: "ComparisonTail",
 								},
 							},
@@ -2270,43 +2553,43 @@ var g = &grammar{
 		},
 		{
 			name: "ComparisonTail",
-			pos:  position{line: 918, col: 1, offset: 25526},
+			pos:  position{line: 989, col: 1, offset: 27866},
 			expr: &actionExpr{
-				pos: position{line: 918, col: 19, offset: 25544},
+				pos: position{line: 989, col: 19, offset: 27884},
 				run: (*parser).callonComparisonTail1,
 				expr: &seqExpr{
-					pos: position{line: 918, col: 19, offset: 25544},
+					pos: position{line: 989, col: 19, offset: 27884},
 					exprs: []any{
 						&ruleRefExpr{
-							pos:  position{line: 918, col: 19, offset: 25544},
+							pos:  position{line: 989, col: 19, offset: 27884},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 918, col: 21, offset: 25546},
+							pos:   position{line: 989, col: 21, offset: 27886},
 							label: "op",
 							expr: &choiceExpr{
-								pos: position{line: 918, col: 26, offset: 25551},
+								pos: position{line: 989, col: 26, offset: 27891},
 								alternatives: []any{
 									&litMatcher{
-										pos:        position{line: 918, col: 26, offset: 25551},
+										pos:        position{line: 989, col: 26, offset: 27891},
 										val:        "<=",
 										ignoreCase: false,
 										want:       "\"<=\"",
 									},
 									&litMatcher{
-										pos:        position{line: 918, col: 33, offset: 25558},
+										pos:        position{line: 989, col: 33, offset: 27898},
 										val:        ">=",
 										ignoreCase: false,
 										want:       "\">=\"",
 									},
 									&litMatcher{
-										pos:        position{line: 918, col: 40, offset: 25565},
+										pos:        position{line: 989, col: 40, offset: 27905},
 										val:        "<",
 										ignoreCase: false,
 										want:       "\"<\"",
 									},
 									&litMatcher{
-										pos:        position{line: 918, col: 46, offset: 25571},
+										pos:        position{line: 989, col: 46, offset: 27911},
 										val:        ">",
 										ignoreCase: false,
 										want:       "\">\"",
@@ -2315,14 +2598,14 @@ var g = &grammar{
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 918, col: 52, offset: 25577},
+							pos:  position{line: 989, col: 52, offset: 27917},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 918, col: 54, offset: 25579},
+							pos:   position{line: 989, col: 54, offset: 27919},
 							label: "right",
 							expr: &ruleRefExpr{
-								pos:  position{line: 918, col: 60, offset: 25585},
+								pos:  position{line: 989, col: 60, offset: 27925},
 								name: "AdditiveExpr",
 							},
 						},
@@ -2332,28 +2615,28 @@ var g = &grammar{
 		},
 		{
 			name: "AdditiveExpr",
-			pos:  position{line: 922, col: 1, offset: 25676},
+			pos:  position{line: 993, col: 1, offset: 28016},
 			expr: &actionExpr{
-				pos: position{line: 922, col: 17, offset: 25692},
+				pos: position{line: 993, col: 17, offset: 28032},
 				run: (*parser).callonAdditiveExpr1,
 				expr: &seqExpr{
-					pos: position{line: 922, col: 17, offset: 25692},
+					pos: position{line: 993, col: 17, offset: 28032},
 					exprs: []any{
 						&labeledExpr{
-							pos:   position{line: 922, col: 17, offset: 25692},
+							pos:   position{line: 993, col: 17, offset: 28032},
 							label: "left",
 							expr: &ruleRefExpr{
-								pos:  position{line: 922, col: 22, offset: 25697},
+								pos:  position{line: 993, col: 22, offset: 28037},
 								name: "MultiplicativeExpr",
 							},
 						},
 						&labeledExpr{
-							pos:   position{line: 922, col: 41, offset: 25716},
+							pos:   position{line: 993, col: 41, offset: 28056},
 							label: "rest",
 							expr: &zeroOrMoreExpr{
-								pos: position{line: 922, col: 46, offset: 25721},
+								pos: position{line: 993, col: 46, offset: 28061},
 								expr: &ruleRefExpr{
-									pos:  position{line: 922, col: 46, offset: 25721},
+									pos:  position{line: 993, col: 46, offset: 28061},
 									name: "AdditiveTail",
 								},
 							},
@@ -2364,31 +2647,31 @@ var g = &grammar{
 		},
 		{
 			name: "AdditiveTail",
-			pos:  position{line: 926, col: 1, offset: 25777},
+			pos:  position{line: 997, col: 1, offset: 28117},
 			expr: &actionExpr{
-				pos: position{line: 926, col: 17, offset: 25793},
+				pos: position{line: 997, col: 17, offset: 28133},
 				run: (*parser).callonAdditiveTail1,
 				expr: &seqExpr{
-					pos: position{line: 926, col: 17, offset: 25793},
+					pos: position{line: 997, col: 17, offset: 28133},
 					exprs: []any{
 						&ruleRefExpr{
-							pos:  position{line: 926, col: 17, offset: 25793},
+							pos:  position{line: 997, col: 17, offset: 28133},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 926, col: 19, offset: 25795},
+							pos:   position{line: 997, col: 19, offset: 28135},
 							label: "op",
 							expr: &choiceExpr{
-								pos: position{line: 926, col: 24, offset: 25800},
+								pos: position{line: 997, col: 24, offset: 28140},
 								alternatives: []any{
 									&litMatcher{
-										pos:        position{line: 926, col: 24, offset: 25800},
+										pos:        position{line: 997, col: 24, offset: 28140},
 										val:        "+",
 										ignoreCase: false,
 										want:       "\"+\"",
 									},
 									&litMatcher{
-										pos:        position{line: 926, col: 30, offset: 25806},
+										pos:        position{line: 997, col: 30, offset: 28146},
 										val:        "-",
 										ignoreCase: false,
 										want:       "\"-\"",
@@ -2397,14 +2680,14 @@ var g = &grammar{
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 926, col: 36, offset: 25812},
+							pos:  position{line: 997, col: 36, offset: 28152},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 926, col: 38, offset: 25814},
+							pos:   position{line: 997, col: 38, offset: 28154},
 							label: "right",
 							expr: &ruleRefExpr{
-								pos:  position{line: 926, col: 44, offset: 25820},
+								pos:  position{line: 997, col: 44, offset: 28160},
 								name: "MultiplicativeExpr",
 							},
 						},
@@ -2414,28 +2697,28 @@ var g = &grammar{
 		},
 		{
 			name: "MultiplicativeExpr",
-			pos:  position{line: 930, col: 1, offset: 25917},
+			pos:  position{line: 1001, col: 1, offset: 28257},
 			expr: &actionExpr{
-				pos: position{line: 930, col: 23, offset: 25939},
+				pos: position{line: 1001, col: 23, offset: 28279},
 				run: (*parser).callonMultiplicativeExpr1,
 				expr: &seqExpr{
-					pos: position{line: 930, col: 23, offset: 25939},
+					pos: position{line: 1001, col: 23, offset: 28279},
 					exprs: []any{
 						&labeledExpr{
-							pos:   position{line: 930, col: 23, offset: 25939},
+							pos:   position{line: 1001, col: 23, offset: 28279},
 							label: "left",
 							expr: &ruleRefExpr{
-								pos:  position{line: 930, col: 28, offset: 25944},
+								pos:  position{line: 1001, col: 28, offset: 28284},
 								name: "UnaryExpr",
 							},
 						},
 						&labeledExpr{
-							pos:   position{line: 930, col: 38, offset: 25954},
+							pos:   position{line: 1001, col: 38, offset: 28294},
 							label: "rest",
 							expr: &zeroOrMoreExpr{
-								pos: position{line: 930, col: 43, offset: 25959},
+								pos: position{line: 1001, col: 43, offset: 28299},
 								expr: &ruleRefExpr{
-									pos:  position{line: 930, col: 43, offset: 25959},
+									pos:  position{line: 1001, col: 43, offset: 28299},
 									name: "MultiplicativeTail",
 								},
 							},
@@ -2446,31 +2729,31 @@ var g = &grammar{
 		},
 		{
 			name: "MultiplicativeTail",
-			pos:  position{line: 934, col: 1, offset: 26021},
+			pos:  position{line: 1005, col: 1, offset: 28361},
 			expr: &actionExpr{
-				pos: position{line: 934, col: 23, offset: 26043},
+				pos: position{line: 1005, col: 23, offset: 28383},
 				run: (*parser).callonMultiplicativeTail1,
 				expr: &seqExpr{
-					pos: position{line: 934, col: 23, offset: 26043},
+					pos: position{line: 1005, col: 23, offset: 28383},
 					exprs: []any{
 						&ruleRefExpr{
-							pos:  position{line: 934, col: 23, offset: 26043},
+							pos:  position{line: 1005, col: 23, offset: 28383},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 934, col: 25, offset: 26045},
+							pos:   position{line: 1005, col: 25, offset: 28385},
 							label: "op",
 							expr: &choiceExpr{
-								pos: position{line: 934, col: 30, offset: 26050},
+								pos: position{line: 1005, col: 30, offset: 28390},
 								alternatives: []any{
 									&litMatcher{
-										pos:        position{line: 934, col: 30, offset: 26050},
+										pos:        position{line: 1005, col: 30, offset: 28390},
 										val:        "*",
 										ignoreCase: false,
 										want:       "\"*\"",
 									},
 									&litMatcher{
-										pos:        position{line: 934, col: 36, offset: 26056},
+										pos:        position{line: 1005, col: 36, offset: 28396},
 										val:        "/",
 										ignoreCase: false,
 										want:       "\"/\"",
@@ -2479,14 +2762,14 @@ var g = &grammar{
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 934, col: 42, offset: 26062},
+							pos:  position{line: 1005, col: 42, offset: 28402},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 934, col: 44, offset: 26064},
+							pos:   position{line: 1005, col: 44, offset: 28404},
 							label: "right",
 							expr: &ruleRefExpr{
-								pos:  position{line: 934, col: 50, offset: 26070},
+								pos:  position{line: 1005, col: 50, offset: 28410},
 								name: "UnaryExpr",
 							},
 						},
@@ -2496,34 +2779,34 @@ var g = &grammar{
 		},
 		{
 			name: "UnaryExpr",
-			pos:  position{line: 938, col: 1, offset: 26158},
+			pos:  position{line: 1009, col: 1, offset: 28498},
 			expr: &choiceExpr{
-				pos: position{line: 938, col: 14, offset: 26171},
+				pos: position{line: 1009, col: 14, offset: 28511},
 				alternatives: []any{
 					&ruleRefExpr{
-						pos:  position{line: 938, col: 14, offset: 26171},
+						pos:  position{line: 1009, col: 14, offset: 28511},
 						name: "Primary",
 					},
 					&actionExpr{
-						pos: position{line: 938, col: 24, offset: 26181},
+						pos: position{line: 1009, col: 24, offset: 28521},
 						run: (*parser).callonUnaryExpr3,
 						expr: &seqExpr{
-							pos: position{line: 938, col: 24, offset: 26181},
+							pos: position{line: 1009, col: 24, offset: 28521},
 							exprs: []any{
 								&labeledExpr{
-									pos:   position{line: 938, col: 24, offset: 26181},
+									pos:   position{line: 1009, col: 24, offset: 28521},
 									label: "op",
 									expr: &choiceExpr{
-										pos: position{line: 938, col: 29, offset: 26186},
+										pos: position{line: 1009, col: 29, offset: 28526},
 										alternatives: []any{
 											&litMatcher{
-												pos:        position{line: 938, col: 29, offset: 26186},
+												pos:        position{line: 1009, col: 29, offset: 28526},
 												val:        "!",
 												ignoreCase: false,
 												want:       "\"!\"",
 											},
 											&litMatcher{
-												pos:        position{line: 938, col: 35, offset: 26192},
+												pos:        position{line: 1009, col: 35, offset: 28532},
 												val:        "-",
 												ignoreCase: false,
 												want:       "\"-\"",
@@ -2532,14 +2815,14 @@ var g = &grammar{
 									},
 								},
 								&ruleRefExpr{
-									pos:  position{line: 938, col: 41, offset: 26198},
+									pos:  position{line: 1009, col: 41, offset: 28538},
 									name: "_",
 								},
 								&labeledExpr{
-									pos:   position{line: 938, col: 43, offset: 26200},
+									pos:   position{line: 1009, col: 43, offset: 28540},
 									label: "inner",
 									expr: &ruleRefExpr{
-										pos:  position{line: 938, col: 49, offset: 26206},
+										pos:  position{line: 1009, col: 49, offset: 28546},
 										name: "UnaryExpr",
 									},
 								},
@@ -2551,60 +2834,60 @@ var g = &grammar{
 		},
 		{
 			name: "Primary",
-			pos:  position{line: 942, col: 1, offset: 26300},
+			pos:  position{line: 1013, col: 1, offset: 28640},
 			expr: &choiceExpr{
-				pos: position{line: 942, col: 12, offset: 26311},
+				pos: position{line: 1013, col: 12, offset: 28651},
 				alternatives: []any{
 					&ruleRefExpr{
-						pos:  position{line: 942, col: 12, offset: 26311},
+						pos:  position{line: 1013, col: 12, offset: 28651},
 						name: "ParenExpr",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 942, col: 24, offset: 26323},
+						pos:  position{line: 1013, col: 24, offset: 28663},
 						name: "MapComp",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 942, col: 34, offset: 26333},
+						pos:  position{line: 1013, col: 34, offset: 28673},
 						name: "ListComp",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 942, col: 45, offset: 26344},
+						pos:  position{line: 1013, col: 45, offset: 28684},
 						name: "ObjectLitNode",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 942, col: 61, offset: 26360},
+						pos:  position{line: 1013, col: 61, offset: 28700},
 						name: "ArrayLitNode",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 942, col: 76, offset: 26375},
+						pos:  position{line: 1013, col: 76, offset: 28715},
 						name: "InterpolatedStringNode",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 942, col: 101, offset: 26400},
+						pos:  position{line: 1013, col: 101, offset: 28740},
 						name: "TripleQuoteStringLitNode",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 942, col: 128, offset: 26427},
+						pos:  position{line: 1013, col: 128, offset: 28767},
 						name: "StringLitNode",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 942, col: 144, offset: 26443},
+						pos:  position{line: 1013, col: 144, offset: 28783},
 						name: "NumberLitNode",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 942, col: 160, offset: 26459},
+						pos:  position{line: 1013, col: 160, offset: 28799},
 						name: "BoolLitNode",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 942, col: 174, offset: 26473},
+						pos:  position{line: 1013, col: 174, offset: 28813},
 						name: "NullLitNode",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 942, col: 188, offset: 26487},
+						pos:  position{line: 1013, col: 188, offset: 28827},
 						name: "NameExpression",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 942, col: 205, offset: 26504},
+						pos:  position{line: 1013, col: 205, offset: 28844},
 						name: "DoubleQuotedString",
 					},
 				},
@@ -2612,37 +2895,37 @@ var g = &grammar{
 		},
 		{
 			name: "ParenExpr",
-			pos:  position{line: 944, col: 1, offset: 26524},
+			pos:  position{line: 1015, col: 1, offset: 28864},
 			expr: &actionExpr{
-				pos: position{line: 944, col: 14, offset: 26537},
+				pos: position{line: 1015, col: 14, offset: 28877},
 				run: (*parser).callonParenExpr1,
 				expr: &seqExpr{
-					pos: position{line: 944, col: 14, offset: 26537},
+					pos: position{line: 1015, col: 14, offset: 28877},
 					exprs: []any{
 						&litMatcher{
-							pos:        position{line: 944, col: 14, offset: 26537},
+							pos:        position{line: 1015, col: 14, offset: 28877},
 							val:        "(",
 							ignoreCase: false,
 							want:       "\"(\"",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 944, col: 18, offset: 26541},
+							pos:  position{line: 1015, col: 18, offset: 28881},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 944, col: 20, offset: 26543},
+							pos:   position{line: 1015, col: 20, offset: 28883},
 							label: "e",
 							expr: &ruleRefExpr{
-								pos:  position{line: 944, col: 22, offset: 26545},
+								pos:  position{line: 1015, col: 22, offset: 28885},
 								name: "Value",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 944, col: 28, offset: 26551},
+							pos:  position{line: 1015, col: 28, offset: 28891},
 							name: "_",
 						},
 						&litMatcher{
-							pos:        position{line: 944, col: 30, offset: 26553},
+							pos:        position{line: 1015, col: 30, offset: 28893},
 							val:        ")",
 							ignoreCase: false,
 							want:       "\")\"",
@@ -2653,24 +2936,24 @@ var g = &grammar{
 		},
 		{
 			name: "NameExpression",
-			pos:  position{line: 948, col: 1, offset: 26577},
+			pos:  position{line: 1019, col: 1, offset: 28917},
 			expr: &choiceExpr{
-				pos: position{line: 948, col: 19, offset: 26595},
+				pos: position{line: 1019, col: 19, offset: 28935},
 				alternatives: []any{
 					&ruleRefExpr{
-						pos:  position{line: 948, col: 19, offset: 26595},
+						pos:  position{line: 1019, col: 19, offset: 28935},
 						name: "LibraryCall",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 948, col: 33, offset: 26609},
+						pos:  position{line: 1019, col: 33, offset: 28949},
 						name: "BareCall",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 948, col: 44, offset: 26620},
+						pos:  position{line: 1019, col: 44, offset: 28960},
 						name: "DotPath",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 948, col: 54, offset: 26630},
+						pos:  position{line: 1019, col: 54, offset: 28970},
 						name: "IdentValue",
 					},
 				},
@@ -2678,59 +2961,59 @@ var g = &grammar{
 		},
 		{
 			name: "LibraryCall",
-			pos:  position{line: 950, col: 1, offset: 26642},
+			pos:  position{line: 1021, col: 1, offset: 28982},
 			expr: &actionExpr{
-				pos: position{line: 950, col: 16, offset: 26657},
+				pos: position{line: 1021, col: 16, offset: 28997},
 				run: (*parser).callonLibraryCall1,
 				expr: &seqExpr{
-					pos: position{line: 950, col: 16, offset: 26657},
+					pos: position{line: 1021, col: 16, offset: 28997},
 					exprs: []any{
 						&labeledExpr{
-							pos:   position{line: 950, col: 16, offset: 26657},
+							pos:   position{line: 1021, col: 16, offset: 28997},
 							label: "lib",
 							expr: &ruleRefExpr{
-								pos:  position{line: 950, col: 20, offset: 26661},
+								pos:  position{line: 1021, col: 20, offset: 29001},
 								name: "IdentText",
 							},
 						},
 						&litMatcher{
-							pos:        position{line: 950, col: 30, offset: 26671},
+							pos:        position{line: 1021, col: 30, offset: 29011},
 							val:        ".",
 							ignoreCase: false,
 							want:       "\".\"",
 						},
 						&labeledExpr{
-							pos:   position{line: 950, col: 34, offset: 26675},
+							pos:   position{line: 1021, col: 34, offset: 29015},
 							label: "fn",
 							expr: &ruleRefExpr{
-								pos:  position{line: 950, col: 37, offset: 26678},
+								pos:  position{line: 1021, col: 37, offset: 29018},
 								name: "IdentText",
 							},
 						},
 						&litMatcher{
-							pos:        position{line: 950, col: 47, offset: 26688},
+							pos:        position{line: 1021, col: 47, offset: 29028},
 							val:        "(",
 							ignoreCase: false,
 							want:       "\"(\"",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 950, col: 51, offset: 26692},
+							pos:  position{line: 1021, col: 51, offset: 29032},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 950, col: 53, offset: 26694},
+							pos:   position{line: 1021, col: 53, offset: 29034},
 							label: "args",
 							expr: &ruleRefExpr{
-								pos:  position{line: 950, col: 58, offset: 26699},
+								pos:  position{line: 1021, col: 58, offset: 29039},
 								name: "CallArgs",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 950, col: 67, offset: 26708},
+							pos:  position{line: 1021, col: 67, offset: 29048},
 							name: "_",
 						},
 						&litMatcher{
-							pos:        position{line: 950, col: 69, offset: 26710},
+							pos:        position{line: 1021, col: 69, offset: 29050},
 							val:        ")",
 							ignoreCase: false,
 							want:       "\")\"",
@@ -2741,45 +3024,45 @@ var g = &grammar{
 		},
 		{
 			name: "BareCall",
-			pos:  position{line: 960, col: 1, offset: 26902},
+			pos:  position{line: 1031, col: 1, offset: 29242},
 			expr: &actionExpr{
-				pos: position{line: 960, col: 13, offset: 26914},
+				pos: position{line: 1031, col: 13, offset: 29254},
 				run: (*parser).callonBareCall1,
 				expr: &seqExpr{
-					pos: position{line: 960, col: 13, offset: 26914},
+					pos: position{line: 1031, col: 13, offset: 29254},
 					exprs: []any{
 						&labeledExpr{
-							pos:   position{line: 960, col: 13, offset: 26914},
+							pos:   position{line: 1031, col: 13, offset: 29254},
 							label: "name",
 							expr: &ruleRefExpr{
-								pos:  position{line: 960, col: 18, offset: 26919},
+								pos:  position{line: 1031, col: 18, offset: 29259},
 								name: "IdentText",
 							},
 						},
 						&litMatcher{
-							pos:        position{line: 960, col: 28, offset: 26929},
+							pos:        position{line: 1031, col: 28, offset: 29269},
 							val:        "(",
 							ignoreCase: false,
 							want:       "\"(\"",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 960, col: 32, offset: 26933},
+							pos:  position{line: 1031, col: 32, offset: 29273},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 960, col: 34, offset: 26935},
+							pos:   position{line: 1031, col: 34, offset: 29275},
 							label: "args",
 							expr: &ruleRefExpr{
-								pos:  position{line: 960, col: 39, offset: 26940},
+								pos:  position{line: 1031, col: 39, offset: 29280},
 								name: "CallArgs",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 960, col: 48, offset: 26949},
+							pos:  position{line: 1031, col: 48, offset: 29289},
 							name: "_",
 						},
 						&litMatcher{
-							pos:        position{line: 960, col: 50, offset: 26951},
+							pos:        position{line: 1031, col: 50, offset: 29291},
 							val:        ")",
 							ignoreCase: false,
 							want:       "\")\"",
@@ -2790,56 +3073,56 @@ var g = &grammar{
 		},
 		{
 			name: "CallArgs",
-			pos:  position{line: 969, col: 1, offset: 27097},
+			pos:  position{line: 1040, col: 1, offset: 29437},
 			expr: &actionExpr{
-				pos: position{line: 969, col: 13, offset: 27109},
+				pos: position{line: 1040, col: 13, offset: 29449},
 				run: (*parser).callonCallArgs1,
 				expr: &labeledExpr{
-					pos:   position{line: 969, col: 13, offset: 27109},
+					pos:   position{line: 1040, col: 13, offset: 29449},
 					label: "args",
 					expr: &zeroOrOneExpr{
-						pos: position{line: 969, col: 18, offset: 27114},
+						pos: position{line: 1040, col: 18, offset: 29454},
 						expr: &seqExpr{
-							pos: position{line: 969, col: 20, offset: 27116},
+							pos: position{line: 1040, col: 20, offset: 29456},
 							exprs: []any{
 								&labeledExpr{
-									pos:   position{line: 969, col: 20, offset: 27116},
+									pos:   position{line: 1040, col: 20, offset: 29456},
 									label: "first",
 									expr: &ruleRefExpr{
-										pos:  position{line: 969, col: 26, offset: 27122},
+										pos:  position{line: 1040, col: 26, offset: 29462},
 										name: "Value",
 									},
 								},
 								&labeledExpr{
-									pos:   position{line: 969, col: 32, offset: 27128},
+									pos:   position{line: 1040, col: 32, offset: 29468},
 									label: "rest",
 									expr: &zeroOrMoreExpr{
-										pos: position{line: 969, col: 37, offset: 27133},
+										pos: position{line: 1040, col: 37, offset: 29473},
 										expr: &actionExpr{
-											pos: position{line: 969, col: 39, offset: 27135},
+											pos: position{line: 1040, col: 39, offset: 29475},
 											run: (*parser).callonCallArgs9,
 											expr: &seqExpr{
-												pos: position{line: 969, col: 39, offset: 27135},
+												pos: position{line: 1040, col: 39, offset: 29475},
 												exprs: []any{
 													&ruleRefExpr{
-														pos:  position{line: 969, col: 39, offset: 27135},
+														pos:  position{line: 1040, col: 39, offset: 29475},
 														name: "_",
 													},
 													&litMatcher{
-														pos:        position{line: 969, col: 41, offset: 27137},
+														pos:        position{line: 1040, col: 41, offset: 29477},
 														val:        ",",
 														ignoreCase: false,
 														want:       "\",\"",
 													},
 													&ruleRefExpr{
-														pos:  position{line: 969, col: 45, offset: 27141},
+														pos:  position{line: 1040, col: 45, offset: 29481},
 														name: "_",
 													},
 													&labeledExpr{
-														pos:   position{line: 969, col: 47, offset: 27143},
+														pos:   position{line: 1040, col: 47, offset: 29483},
 														label: "v",
 														expr: &ruleRefExpr{
-															pos:  position{line: 969, col: 49, offset: 27145},
+															pos:  position{line: 1040, col: 49, offset: 29485},
 															name: "Value",
 														},
 													},
@@ -2849,13 +3132,13 @@ var g = &grammar{
 									},
 								},
 								&ruleRefExpr{
-									pos:  position{line: 969, col: 76, offset: 27172},
+									pos:  position{line: 1040, col: 76, offset: 29512},
 									name: "_",
 								},
 								&zeroOrOneExpr{
-									pos: position{line: 969, col: 78, offset: 27174},
+									pos: position{line: 1040, col: 78, offset: 29514},
 									expr: &litMatcher{
-										pos:        position{line: 969, col: 78, offset: 27174},
+										pos:        position{line: 1040, col: 78, offset: 29514},
 										val:        ",",
 										ignoreCase: false,
 										want:       "\",\"",
@@ -2869,28 +3152,28 @@ var g = &grammar{
 		},
 		{
 			name: "DotPath",
-			pos:  position{line: 982, col: 1, offset: 27405},
+			pos:  position{line: 1053, col: 1, offset: 29745},
 			expr: &actionExpr{
-				pos: position{line: 982, col: 12, offset: 27416},
+				pos: position{line: 1053, col: 12, offset: 29756},
 				run: (*parser).callonDotPath1,
 				expr: &seqExpr{
-					pos: position{line: 982, col: 12, offset: 27416},
+					pos: position{line: 1053, col: 12, offset: 29756},
 					exprs: []any{
 						&labeledExpr{
-							pos:   position{line: 982, col: 12, offset: 27416},
+							pos:   position{line: 1053, col: 12, offset: 29756},
 							label: "root",
 							expr: &ruleRefExpr{
-								pos:  position{line: 982, col: 17, offset: 27421},
+								pos:  position{line: 1053, col: 17, offset: 29761},
 								name: "IdentText",
 							},
 						},
 						&labeledExpr{
-							pos:   position{line: 982, col: 27, offset: 27431},
+							pos:   position{line: 1053, col: 27, offset: 29771},
 							label: "segs",
 							expr: &oneOrMoreExpr{
-								pos: position{line: 982, col: 32, offset: 27436},
+								pos: position{line: 1053, col: 32, offset: 29776},
 								expr: &ruleRefExpr{
-									pos:  position{line: 982, col: 32, offset: 27436},
+									pos:  position{line: 1053, col: 32, offset: 29776},
 									name: "DotSegmentRule",
 								},
 							},
@@ -2901,27 +3184,27 @@ var g = &grammar{
 		},
 		{
 			name: "DotSegmentRule",
-			pos:  position{line: 991, col: 1, offset: 27600},
+			pos:  position{line: 1062, col: 1, offset: 29940},
 			expr: &choiceExpr{
-				pos: position{line: 991, col: 19, offset: 27618},
+				pos: position{line: 1062, col: 19, offset: 29958},
 				alternatives: []any{
 					&actionExpr{
-						pos: position{line: 991, col: 19, offset: 27618},
+						pos: position{line: 1062, col: 19, offset: 29958},
 						run: (*parser).callonDotSegmentRule2,
 						expr: &seqExpr{
-							pos: position{line: 991, col: 19, offset: 27618},
+							pos: position{line: 1062, col: 19, offset: 29958},
 							exprs: []any{
 								&litMatcher{
-									pos:        position{line: 991, col: 19, offset: 27618},
+									pos:        position{line: 1062, col: 19, offset: 29958},
 									val:        "?.",
 									ignoreCase: false,
 									want:       "\"?.\"",
 								},
 								&labeledExpr{
-									pos:   position{line: 991, col: 24, offset: 27623},
+									pos:   position{line: 1062, col: 24, offset: 29963},
 									label: "name",
 									expr: &ruleRefExpr{
-										pos:  position{line: 991, col: 29, offset: 27628},
+										pos:  position{line: 1062, col: 29, offset: 29968},
 										name: "IdentText",
 									},
 								},
@@ -2929,22 +3212,22 @@ var g = &grammar{
 						},
 					},
 					&actionExpr{
-						pos: position{line: 993, col: 5, offset: 27717},
+						pos: position{line: 1064, col: 5, offset: 30057},
 						run: (*parser).callonDotSegmentRule7,
 						expr: &seqExpr{
-							pos: position{line: 993, col: 5, offset: 27717},
+							pos: position{line: 1064, col: 5, offset: 30057},
 							exprs: []any{
 								&litMatcher{
-									pos:        position{line: 993, col: 5, offset: 27717},
+									pos:        position{line: 1064, col: 5, offset: 30057},
 									val:        ".",
 									ignoreCase: false,
 									want:       "\".\"",
 								},
 								&labeledExpr{
-									pos:   position{line: 993, col: 9, offset: 27721},
+									pos:   position{line: 1064, col: 9, offset: 30061},
 									label: "name",
 									expr: &ruleRefExpr{
-										pos:  position{line: 993, col: 14, offset: 27726},
+										pos:  position{line: 1064, col: 14, offset: 30066},
 										name: "IdentText",
 									},
 								},
@@ -2952,33 +3235,33 @@ var g = &grammar{
 						},
 					},
 					&actionExpr{
-						pos: position{line: 995, col: 5, offset: 27800},
+						pos: position{line: 1066, col: 5, offset: 30140},
 						run: (*parser).callonDotSegmentRule12,
 						expr: &seqExpr{
-							pos: position{line: 995, col: 5, offset: 27800},
+							pos: position{line: 1066, col: 5, offset: 30140},
 							exprs: []any{
 								&litMatcher{
-									pos:        position{line: 995, col: 5, offset: 27800},
+									pos:        position{line: 1066, col: 5, offset: 30140},
 									val:        "[",
 									ignoreCase: false,
 									want:       "\"[\"",
 								},
 								&ruleRefExpr{
-									pos:  position{line: 995, col: 9, offset: 27804},
+									pos:  position{line: 1066, col: 9, offset: 30144},
 									name: "_",
 								},
 								&litMatcher{
-									pos:        position{line: 995, col: 11, offset: 27806},
+									pos:        position{line: 1066, col: 11, offset: 30146},
 									val:        "*",
 									ignoreCase: false,
 									want:       "\"*\"",
 								},
 								&ruleRefExpr{
-									pos:  position{line: 995, col: 15, offset: 27810},
+									pos:  position{line: 1066, col: 15, offset: 30150},
 									name: "_",
 								},
 								&litMatcher{
-									pos:        position{line: 995, col: 17, offset: 27812},
+									pos:        position{line: 1066, col: 17, offset: 30152},
 									val:        "]",
 									ignoreCase: false,
 									want:       "\"]\"",
@@ -2987,35 +3270,35 @@ var g = &grammar{
 						},
 					},
 					&actionExpr{
-						pos: position{line: 997, col: 5, offset: 27872},
+						pos: position{line: 1068, col: 5, offset: 30212},
 						run: (*parser).callonDotSegmentRule19,
 						expr: &seqExpr{
-							pos: position{line: 997, col: 5, offset: 27872},
+							pos: position{line: 1068, col: 5, offset: 30212},
 							exprs: []any{
 								&litMatcher{
-									pos:        position{line: 997, col: 5, offset: 27872},
+									pos:        position{line: 1068, col: 5, offset: 30212},
 									val:        "[",
 									ignoreCase: false,
 									want:       "\"[\"",
 								},
 								&ruleRefExpr{
-									pos:  position{line: 997, col: 9, offset: 27876},
+									pos:  position{line: 1068, col: 9, offset: 30216},
 									name: "_",
 								},
 								&labeledExpr{
-									pos:   position{line: 997, col: 11, offset: 27878},
+									pos:   position{line: 1068, col: 11, offset: 30218},
 									label: "key",
 									expr: &ruleRefExpr{
-										pos:  position{line: 997, col: 15, offset: 27882},
+										pos:  position{line: 1068, col: 15, offset: 30222},
 										name: "Value",
 									},
 								},
 								&ruleRefExpr{
-									pos:  position{line: 997, col: 21, offset: 27888},
+									pos:  position{line: 1068, col: 21, offset: 30228},
 									name: "_",
 								},
 								&litMatcher{
-									pos:        position{line: 997, col: 23, offset: 27890},
+									pos:        position{line: 1068, col: 23, offset: 30230},
 									val:        "]",
 									ignoreCase: false,
 									want:       "\"]\"",
@@ -3028,15 +3311,15 @@ var g = &grammar{
 		},
 		{
 			name: "IdentValue",
-			pos:  position{line: 1001, col: 1, offset: 27955},
+			pos:  position{line: 1072, col: 1, offset: 30295},
 			expr: &actionExpr{
-				pos: position{line: 1001, col: 15, offset: 27969},
+				pos: position{line: 1072, col: 15, offset: 30309},
 				run: (*parser).callonIdentValue1,
 				expr: &labeledExpr{
-					pos:   position{line: 1001, col: 15, offset: 27969},
+					pos:   position{line: 1072, col: 15, offset: 30309},
 					label: "name",
 					expr: &ruleRefExpr{
-						pos:  position{line: 1001, col: 20, offset: 27974},
+						pos:  position{line: 1072, col: 20, offset: 30314},
 						name: "IdentText",
 					},
 				},
@@ -3044,43 +3327,43 @@ var g = &grammar{
 		},
 		{
 			name: "Selector",
-			pos:  position{line: 1005, col: 1, offset: 28043},
+			pos:  position{line: 1076, col: 1, offset: 30383},
 			expr: &actionExpr{
-				pos: position{line: 1005, col: 13, offset: 28055},
+				pos: position{line: 1076, col: 13, offset: 30395},
 				run: (*parser).callonSelector1,
 				expr: &seqExpr{
-					pos: position{line: 1005, col: 13, offset: 28055},
+					pos: position{line: 1076, col: 13, offset: 30395},
 					exprs: []any{
 						&labeledExpr{
-							pos:   position{line: 1005, col: 13, offset: 28055},
+							pos:   position{line: 1076, col: 13, offset: 30395},
 							label: "first",
 							expr: &ruleRefExpr{
-								pos:  position{line: 1005, col: 19, offset: 28061},
+								pos:  position{line: 1076, col: 19, offset: 30401},
 								name: "SelectorIdent",
 							},
 						},
 						&labeledExpr{
-							pos:   position{line: 1005, col: 33, offset: 28075},
+							pos:   position{line: 1076, col: 33, offset: 30415},
 							label: "rest",
 							expr: &zeroOrMoreExpr{
-								pos: position{line: 1005, col: 38, offset: 28080},
+								pos: position{line: 1076, col: 38, offset: 30420},
 								expr: &actionExpr{
-									pos: position{line: 1005, col: 40, offset: 28082},
+									pos: position{line: 1076, col: 40, offset: 30422},
 									run: (*parser).callonSelector7,
 									expr: &seqExpr{
-										pos: position{line: 1005, col: 40, offset: 28082},
+										pos: position{line: 1076, col: 40, offset: 30422},
 										exprs: []any{
 											&litMatcher{
-												pos:        position{line: 1005, col: 40, offset: 28082},
+												pos:        position{line: 1076, col: 40, offset: 30422},
 												val:        ".",
 												ignoreCase: false,
 												want:       "\".\"",
 											},
 											&labeledExpr{
-												pos:   position{line: 1005, col: 44, offset: 28086},
+												pos:   position{line: 1076, col: 44, offset: 30426},
 												label: "part",
 												expr: &ruleRefExpr{
-													pos:  position{line: 1005, col: 49, offset: 28091},
+													pos:  position{line: 1076, col: 49, offset: 30431},
 													name: "SelectorIdent",
 												},
 											},
@@ -3095,15 +3378,15 @@ var g = &grammar{
 		},
 		{
 			name: "SelectorIdent",
-			pos:  position{line: 1013, col: 1, offset: 28293},
+			pos:  position{line: 1084, col: 1, offset: 30633},
 			expr: &actionExpr{
-				pos: position{line: 1013, col: 18, offset: 28310},
+				pos: position{line: 1084, col: 18, offset: 30650},
 				run: (*parser).callonSelectorIdent1,
 				expr: &labeledExpr{
-					pos:   position{line: 1013, col: 18, offset: 28310},
+					pos:   position{line: 1084, col: 18, offset: 30650},
 					label: "name",
 					expr: &ruleRefExpr{
-						pos:  position{line: 1013, col: 23, offset: 28315},
+						pos:  position{line: 1084, col: 23, offset: 30655},
 						name: "SelectorIdentText",
 					},
 				},
@@ -3111,36 +3394,36 @@ var g = &grammar{
 		},
 		{
 			name: "ObjectLitNode",
-			pos:  position{line: 1017, col: 1, offset: 28396},
+			pos:  position{line: 1088, col: 1, offset: 30736},
 			expr: &actionExpr{
-				pos: position{line: 1017, col: 18, offset: 28413},
+				pos: position{line: 1088, col: 18, offset: 30753},
 				run: (*parser).callonObjectLitNode1,
 				expr: &seqExpr{
-					pos: position{line: 1017, col: 18, offset: 28413},
+					pos: position{line: 1088, col: 18, offset: 30753},
 					exprs: []any{
 						&litMatcher{
-							pos:        position{line: 1017, col: 18, offset: 28413},
+							pos:        position{line: 1088, col: 18, offset: 30753},
 							val:        "{",
 							ignoreCase: false,
 							want:       "\"{\"",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 1017, col: 22, offset: 28417},
+							pos:  position{line: 1088, col: 22, offset: 30757},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 1017, col: 24, offset: 28419},
+							pos:   position{line: 1088, col: 24, offset: 30759},
 							label: "pairs",
 							expr: &zeroOrMoreExpr{
-								pos: position{line: 1017, col: 30, offset: 28425},
+								pos: position{line: 1088, col: 30, offset: 30765},
 								expr: &ruleRefExpr{
-									pos:  position{line: 1017, col: 30, offset: 28425},
+									pos:  position{line: 1088, col: 30, offset: 30765},
 									name: "Pair",
 								},
 							},
 						},
 						&litMatcher{
-							pos:        position{line: 1017, col: 36, offset: 28431},
+							pos:        position{line: 1088, col: 36, offset: 30771},
 							val:        "}",
 							ignoreCase: false,
 							want:       "\"}\"",
@@ -3151,40 +3434,40 @@ var g = &grammar{
 		},
 		{
 			name: "ArrayLitNode",
-			pos:  position{line: 1021, col: 1, offset: 28533},
+			pos:  position{line: 1092, col: 1, offset: 30873},
 			expr: &actionExpr{
-				pos: position{line: 1021, col: 17, offset: 28549},
+				pos: position{line: 1092, col: 17, offset: 30889},
 				run: (*parser).callonArrayLitNode1,
 				expr: &seqExpr{
-					pos: position{line: 1021, col: 17, offset: 28549},
+					pos: position{line: 1092, col: 17, offset: 30889},
 					exprs: []any{
 						&litMatcher{
-							pos:        position{line: 1021, col: 17, offset: 28549},
+							pos:        position{line: 1092, col: 17, offset: 30889},
 							val:        "[",
 							ignoreCase: false,
 							want:       "\"[\"",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 1021, col: 21, offset: 28553},
+							pos:  position{line: 1092, col: 21, offset: 30893},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 1021, col: 23, offset: 28555},
+							pos:   position{line: 1092, col: 23, offset: 30895},
 							label: "elems",
 							expr: &zeroOrOneExpr{
-								pos: position{line: 1021, col: 29, offset: 28561},
+								pos: position{line: 1092, col: 29, offset: 30901},
 								expr: &ruleRefExpr{
-									pos:  position{line: 1021, col: 29, offset: 28561},
+									pos:  position{line: 1092, col: 29, offset: 30901},
 									name: "ArrayElems",
 								},
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 1021, col: 41, offset: 28573},
+							pos:  position{line: 1092, col: 41, offset: 30913},
 							name: "_",
 						},
 						&litMatcher{
-							pos:        position{line: 1021, col: 43, offset: 28575},
+							pos:        position{line: 1092, col: 43, offset: 30915},
 							val:        "]",
 							ignoreCase: false,
 							want:       "\"]\"",
@@ -3195,51 +3478,51 @@ var g = &grammar{
 		},
 		{
 			name: "ArrayElems",
-			pos:  position{line: 1028, col: 1, offset: 28717},
+			pos:  position{line: 1099, col: 1, offset: 31057},
 			expr: &actionExpr{
-				pos: position{line: 1028, col: 15, offset: 28731},
+				pos: position{line: 1099, col: 15, offset: 31071},
 				run: (*parser).callonArrayElems1,
 				expr: &seqExpr{
-					pos: position{line: 1028, col: 15, offset: 28731},
+					pos: position{line: 1099, col: 15, offset: 31071},
 					exprs: []any{
 						&labeledExpr{
-							pos:   position{line: 1028, col: 15, offset: 28731},
+							pos:   position{line: 1099, col: 15, offset: 31071},
 							label: "first",
 							expr: &ruleRefExpr{
-								pos:  position{line: 1028, col: 21, offset: 28737},
+								pos:  position{line: 1099, col: 21, offset: 31077},
 								name: "Value",
 							},
 						},
 						&labeledExpr{
-							pos:   position{line: 1028, col: 27, offset: 28743},
+							pos:   position{line: 1099, col: 27, offset: 31083},
 							label: "rest",
 							expr: &zeroOrMoreExpr{
-								pos: position{line: 1028, col: 32, offset: 28748},
+								pos: position{line: 1099, col: 32, offset: 31088},
 								expr: &actionExpr{
-									pos: position{line: 1028, col: 34, offset: 28750},
+									pos: position{line: 1099, col: 34, offset: 31090},
 									run: (*parser).callonArrayElems7,
 									expr: &seqExpr{
-										pos: position{line: 1028, col: 34, offset: 28750},
+										pos: position{line: 1099, col: 34, offset: 31090},
 										exprs: []any{
 											&ruleRefExpr{
-												pos:  position{line: 1028, col: 34, offset: 28750},
+												pos:  position{line: 1099, col: 34, offset: 31090},
 												name: "_",
 											},
 											&litMatcher{
-												pos:        position{line: 1028, col: 36, offset: 28752},
+												pos:        position{line: 1099, col: 36, offset: 31092},
 												val:        ",",
 												ignoreCase: false,
 												want:       "\",\"",
 											},
 											&ruleRefExpr{
-												pos:  position{line: 1028, col: 40, offset: 28756},
+												pos:  position{line: 1099, col: 40, offset: 31096},
 												name: "_",
 											},
 											&labeledExpr{
-												pos:   position{line: 1028, col: 42, offset: 28758},
+												pos:   position{line: 1099, col: 42, offset: 31098},
 												label: "v",
 												expr: &ruleRefExpr{
-													pos:  position{line: 1028, col: 44, offset: 28760},
+													pos:  position{line: 1099, col: 44, offset: 31100},
 													name: "Value",
 												},
 											},
@@ -3249,13 +3532,13 @@ var g = &grammar{
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 1028, col: 71, offset: 28787},
+							pos:  position{line: 1099, col: 71, offset: 31127},
 							name: "_",
 						},
 						&zeroOrOneExpr{
-							pos: position{line: 1028, col: 73, offset: 28789},
+							pos: position{line: 1099, col: 73, offset: 31129},
 							expr: &litMatcher{
-								pos:        position{line: 1028, col: 73, offset: 28789},
+								pos:        position{line: 1099, col: 73, offset: 31129},
 								val:        ",",
 								ignoreCase: false,
 								want:       "\",\"",
@@ -3267,116 +3550,116 @@ var g = &grammar{
 		},
 		{
 			name: "ListComp",
-			pos:  position{line: 1040, col: 1, offset: 29213},
+			pos:  position{line: 1111, col: 1, offset: 31553},
 			expr: &actionExpr{
-				pos: position{line: 1040, col: 13, offset: 29225},
+				pos: position{line: 1111, col: 13, offset: 31565},
 				run: (*parser).callonListComp1,
 				expr: &seqExpr{
-					pos: position{line: 1040, col: 13, offset: 29225},
+					pos: position{line: 1111, col: 13, offset: 31565},
 					exprs: []any{
 						&litMatcher{
-							pos:        position{line: 1040, col: 13, offset: 29225},
+							pos:        position{line: 1111, col: 13, offset: 31565},
 							val:        "[",
 							ignoreCase: false,
 							want:       "\"[\"",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 1040, col: 17, offset: 29229},
+							pos:  position{line: 1111, col: 17, offset: 31569},
 							name: "_",
 						},
 						&litMatcher{
-							pos:        position{line: 1040, col: 19, offset: 29231},
+							pos:        position{line: 1111, col: 19, offset: 31571},
 							val:        "for",
 							ignoreCase: false,
 							want:       "\"for\"",
 						},
 						&notExpr{
-							pos: position{line: 1040, col: 25, offset: 29237},
+							pos: position{line: 1111, col: 25, offset: 31577},
 							expr: &ruleRefExpr{
-								pos:  position{line: 1040, col: 26, offset: 29238},
+								pos:  position{line: 1111, col: 26, offset: 31578},
 								name: "IdentChar",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 1040, col: 36, offset: 29248},
+							pos:  position{line: 1111, col: 36, offset: 31588},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 1040, col: 38, offset: 29250},
+							pos:   position{line: 1111, col: 38, offset: 31590},
 							label: "b",
 							expr: &ruleRefExpr{
-								pos:  position{line: 1040, col: 40, offset: 29252},
+								pos:  position{line: 1111, col: 40, offset: 31592},
 								name: "Binding",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 1040, col: 48, offset: 29260},
+							pos:  position{line: 1111, col: 48, offset: 31600},
 							name: "_",
 						},
 						&litMatcher{
-							pos:        position{line: 1040, col: 50, offset: 29262},
+							pos:        position{line: 1111, col: 50, offset: 31602},
 							val:        "in",
 							ignoreCase: false,
 							want:       "\"in\"",
 						},
 						&notExpr{
-							pos: position{line: 1040, col: 55, offset: 29267},
+							pos: position{line: 1111, col: 55, offset: 31607},
 							expr: &ruleRefExpr{
-								pos:  position{line: 1040, col: 56, offset: 29268},
+								pos:  position{line: 1111, col: 56, offset: 31608},
 								name: "IdentChar",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 1040, col: 66, offset: 29278},
+							pos:  position{line: 1111, col: 66, offset: 31618},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 1040, col: 68, offset: 29280},
+							pos:   position{line: 1111, col: 68, offset: 31620},
 							label: "src",
 							expr: &ruleRefExpr{
-								pos:  position{line: 1040, col: 72, offset: 29284},
+								pos:  position{line: 1111, col: 72, offset: 31624},
 								name: "Expr",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 1040, col: 77, offset: 29289},
+							pos:  position{line: 1111, col: 77, offset: 31629},
 							name: "_",
 						},
 						&litMatcher{
-							pos:        position{line: 1040, col: 79, offset: 29291},
+							pos:        position{line: 1111, col: 79, offset: 31631},
 							val:        ":",
 							ignoreCase: false,
 							want:       "\":\"",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 1040, col: 83, offset: 29295},
+							pos:  position{line: 1111, col: 83, offset: 31635},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 1040, col: 85, offset: 29297},
+							pos:   position{line: 1111, col: 85, offset: 31637},
 							label: "body",
 							expr: &ruleRefExpr{
-								pos:  position{line: 1040, col: 90, offset: 29302},
+								pos:  position{line: 1111, col: 90, offset: 31642},
 								name: "Value",
 							},
 						},
 						&labeledExpr{
-							pos:   position{line: 1040, col: 96, offset: 29308},
+							pos:   position{line: 1111, col: 96, offset: 31648},
 							label: "filt",
 							expr: &zeroOrOneExpr{
-								pos: position{line: 1040, col: 101, offset: 29313},
+								pos: position{line: 1111, col: 101, offset: 31653},
 								expr: &ruleRefExpr{
-									pos:  position{line: 1040, col: 101, offset: 29313},
+									pos:  position{line: 1111, col: 101, offset: 31653},
 									name: "Filter",
 								},
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 1040, col: 109, offset: 29321},
+							pos:  position{line: 1111, col: 109, offset: 31661},
 							name: "_",
 						},
 						&litMatcher{
-							pos:        position{line: 1040, col: 111, offset: 29323},
+							pos:        position{line: 1111, col: 111, offset: 31663},
 							val:        "]",
 							ignoreCase: false,
 							want:       "\"]\"",
@@ -3387,149 +3670,149 @@ var g = &grammar{
 		},
 		{
 			name: "MapComp",
-			pos:  position{line: 1054, col: 1, offset: 29541},
+			pos:  position{line: 1125, col: 1, offset: 31881},
 			expr: &actionExpr{
-				pos: position{line: 1054, col: 12, offset: 29552},
+				pos: position{line: 1125, col: 12, offset: 31892},
 				run: (*parser).callonMapComp1,
 				expr: &seqExpr{
-					pos: position{line: 1054, col: 12, offset: 29552},
+					pos: position{line: 1125, col: 12, offset: 31892},
 					exprs: []any{
 						&litMatcher{
-							pos:        position{line: 1054, col: 12, offset: 29552},
+							pos:        position{line: 1125, col: 12, offset: 31892},
 							val:        "{",
 							ignoreCase: false,
 							want:       "\"{\"",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 1054, col: 16, offset: 29556},
+							pos:  position{line: 1125, col: 16, offset: 31896},
 							name: "_",
 						},
 						&litMatcher{
-							pos:        position{line: 1054, col: 18, offset: 29558},
+							pos:        position{line: 1125, col: 18, offset: 31898},
 							val:        "for",
 							ignoreCase: false,
 							want:       "\"for\"",
 						},
 						&notExpr{
-							pos: position{line: 1054, col: 24, offset: 29564},
+							pos: position{line: 1125, col: 24, offset: 31904},
 							expr: &ruleRefExpr{
-								pos:  position{line: 1054, col: 25, offset: 29565},
+								pos:  position{line: 1125, col: 25, offset: 31905},
 								name: "IdentChar",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 1054, col: 35, offset: 29575},
+							pos:  position{line: 1125, col: 35, offset: 31915},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 1054, col: 37, offset: 29577},
+							pos:   position{line: 1125, col: 37, offset: 31917},
 							label: "b",
 							expr: &ruleRefExpr{
-								pos:  position{line: 1054, col: 39, offset: 29579},
+								pos:  position{line: 1125, col: 39, offset: 31919},
 								name: "Binding",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 1054, col: 47, offset: 29587},
+							pos:  position{line: 1125, col: 47, offset: 31927},
 							name: "_",
 						},
 						&litMatcher{
-							pos:        position{line: 1054, col: 49, offset: 29589},
+							pos:        position{line: 1125, col: 49, offset: 31929},
 							val:        "in",
 							ignoreCase: false,
 							want:       "\"in\"",
 						},
 						&notExpr{
-							pos: position{line: 1054, col: 54, offset: 29594},
+							pos: position{line: 1125, col: 54, offset: 31934},
 							expr: &ruleRefExpr{
-								pos:  position{line: 1054, col: 55, offset: 29595},
+								pos:  position{line: 1125, col: 55, offset: 31935},
 								name: "IdentChar",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 1054, col: 65, offset: 29605},
+							pos:  position{line: 1125, col: 65, offset: 31945},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 1054, col: 67, offset: 29607},
+							pos:   position{line: 1125, col: 67, offset: 31947},
 							label: "src",
 							expr: &ruleRefExpr{
-								pos:  position{line: 1054, col: 71, offset: 29611},
+								pos:  position{line: 1125, col: 71, offset: 31951},
 								name: "Expr",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 1054, col: 76, offset: 29616},
+							pos:  position{line: 1125, col: 76, offset: 31956},
 							name: "_",
 						},
 						&litMatcher{
-							pos:        position{line: 1054, col: 78, offset: 29618},
+							pos:        position{line: 1125, col: 78, offset: 31958},
 							val:        ":",
 							ignoreCase: false,
 							want:       "\":\"",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 1054, col: 82, offset: 29622},
+							pos:  position{line: 1125, col: 82, offset: 31962},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 1054, col: 84, offset: 29624},
+							pos:   position{line: 1125, col: 84, offset: 31964},
 							label: "key",
 							expr: &ruleRefExpr{
-								pos:  position{line: 1054, col: 88, offset: 29628},
+								pos:  position{line: 1125, col: 88, offset: 31968},
 								name: "Expr",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 1054, col: 93, offset: 29633},
+							pos:  position{line: 1125, col: 93, offset: 31973},
 							name: "_",
 						},
 						&litMatcher{
-							pos:        position{line: 1054, col: 95, offset: 29635},
+							pos:        position{line: 1125, col: 95, offset: 31975},
 							val:        "=>",
 							ignoreCase: false,
 							want:       "\"=>\"",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 1054, col: 100, offset: 29640},
+							pos:  position{line: 1125, col: 100, offset: 31980},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 1054, col: 102, offset: 29642},
+							pos:   position{line: 1125, col: 102, offset: 31982},
 							label: "val",
 							expr: &ruleRefExpr{
-								pos:  position{line: 1054, col: 106, offset: 29646},
+								pos:  position{line: 1125, col: 106, offset: 31986},
 								name: "Value",
 							},
 						},
 						&labeledExpr{
-							pos:   position{line: 1054, col: 112, offset: 29652},
+							pos:   position{line: 1125, col: 112, offset: 31992},
 							label: "grp",
 							expr: &zeroOrOneExpr{
-								pos: position{line: 1054, col: 116, offset: 29656},
+								pos: position{line: 1125, col: 116, offset: 31996},
 								expr: &ruleRefExpr{
-									pos:  position{line: 1054, col: 116, offset: 29656},
+									pos:  position{line: 1125, col: 116, offset: 31996},
 									name: "Group",
 								},
 							},
 						},
 						&labeledExpr{
-							pos:   position{line: 1054, col: 123, offset: 29663},
+							pos:   position{line: 1125, col: 123, offset: 32003},
 							label: "filt",
 							expr: &zeroOrOneExpr{
-								pos: position{line: 1054, col: 128, offset: 29668},
+								pos: position{line: 1125, col: 128, offset: 32008},
 								expr: &ruleRefExpr{
-									pos:  position{line: 1054, col: 128, offset: 29668},
+									pos:  position{line: 1125, col: 128, offset: 32008},
 									name: "Filter",
 								},
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 1054, col: 136, offset: 29676},
+							pos:  position{line: 1125, col: 136, offset: 32016},
 							name: "_",
 						},
 						&litMatcher{
-							pos:        position{line: 1054, col: 138, offset: 29678},
+							pos:        position{line: 1125, col: 138, offset: 32018},
 							val:        "}",
 							ignoreCase: false,
 							want:       "\"}\"",
@@ -3540,51 +3823,51 @@ var g = &grammar{
 		},
 		{
 			name: "Binding",
-			pos:  position{line: 1070, col: 1, offset: 29938},
+			pos:  position{line: 1141, col: 1, offset: 32278},
 			expr: &actionExpr{
-				pos: position{line: 1070, col: 12, offset: 29949},
+				pos: position{line: 1141, col: 12, offset: 32289},
 				run: (*parser).callonBinding1,
 				expr: &seqExpr{
-					pos: position{line: 1070, col: 12, offset: 29949},
+					pos: position{line: 1141, col: 12, offset: 32289},
 					exprs: []any{
 						&labeledExpr{
-							pos:   position{line: 1070, col: 12, offset: 29949},
+							pos:   position{line: 1141, col: 12, offset: 32289},
 							label: "name1",
 							expr: &ruleRefExpr{
-								pos:  position{line: 1070, col: 18, offset: 29955},
+								pos:  position{line: 1141, col: 18, offset: 32295},
 								name: "IdentText",
 							},
 						},
 						&labeledExpr{
-							pos:   position{line: 1070, col: 28, offset: 29965},
+							pos:   position{line: 1141, col: 28, offset: 32305},
 							label: "sec",
 							expr: &zeroOrOneExpr{
-								pos: position{line: 1070, col: 32, offset: 29969},
+								pos: position{line: 1141, col: 32, offset: 32309},
 								expr: &actionExpr{
-									pos: position{line: 1070, col: 34, offset: 29971},
+									pos: position{line: 1141, col: 34, offset: 32311},
 									run: (*parser).callonBinding7,
 									expr: &seqExpr{
-										pos: position{line: 1070, col: 34, offset: 29971},
+										pos: position{line: 1141, col: 34, offset: 32311},
 										exprs: []any{
 											&ruleRefExpr{
-												pos:  position{line: 1070, col: 34, offset: 29971},
+												pos:  position{line: 1141, col: 34, offset: 32311},
 												name: "_",
 											},
 											&litMatcher{
-												pos:        position{line: 1070, col: 36, offset: 29973},
+												pos:        position{line: 1141, col: 36, offset: 32313},
 												val:        ",",
 												ignoreCase: false,
 												want:       "\",\"",
 											},
 											&ruleRefExpr{
-												pos:  position{line: 1070, col: 40, offset: 29977},
+												pos:  position{line: 1141, col: 40, offset: 32317},
 												name: "_",
 											},
 											&labeledExpr{
-												pos:   position{line: 1070, col: 42, offset: 29979},
+												pos:   position{line: 1141, col: 42, offset: 32319},
 												label: "n",
 												expr: &ruleRefExpr{
-													pos:  position{line: 1070, col: 44, offset: 29981},
+													pos:  position{line: 1141, col: 44, offset: 32321},
 													name: "IdentText",
 												},
 											},
@@ -3599,39 +3882,39 @@ var g = &grammar{
 		},
 		{
 			name: "Filter",
-			pos:  position{line: 1078, col: 1, offset: 30129},
+			pos:  position{line: 1149, col: 1, offset: 32469},
 			expr: &actionExpr{
-				pos: position{line: 1078, col: 11, offset: 30139},
+				pos: position{line: 1149, col: 11, offset: 32479},
 				run: (*parser).callonFilter1,
 				expr: &seqExpr{
-					pos: position{line: 1078, col: 11, offset: 30139},
+					pos: position{line: 1149, col: 11, offset: 32479},
 					exprs: []any{
 						&ruleRefExpr{
-							pos:  position{line: 1078, col: 11, offset: 30139},
+							pos:  position{line: 1149, col: 11, offset: 32479},
 							name: "_",
 						},
 						&litMatcher{
-							pos:        position{line: 1078, col: 13, offset: 30141},
+							pos:        position{line: 1149, col: 13, offset: 32481},
 							val:        "when",
 							ignoreCase: false,
 							want:       "\"when\"",
 						},
 						&notExpr{
-							pos: position{line: 1078, col: 20, offset: 30148},
+							pos: position{line: 1149, col: 20, offset: 32488},
 							expr: &ruleRefExpr{
-								pos:  position{line: 1078, col: 21, offset: 30149},
+								pos:  position{line: 1149, col: 21, offset: 32489},
 								name: "IdentChar",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 1078, col: 31, offset: 30159},
+							pos:  position{line: 1149, col: 31, offset: 32499},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 1078, col: 33, offset: 30161},
+							pos:   position{line: 1149, col: 33, offset: 32501},
 							label: "pred",
 							expr: &ruleRefExpr{
-								pos:  position{line: 1078, col: 38, offset: 30166},
+								pos:  position{line: 1149, col: 38, offset: 32506},
 								name: "Expr",
 							},
 						},
@@ -3641,19 +3924,19 @@ var g = &grammar{
 		},
 		{
 			name: "Group",
-			pos:  position{line: 1082, col: 1, offset: 30194},
+			pos:  position{line: 1153, col: 1, offset: 32534},
 			expr: &actionExpr{
-				pos: position{line: 1082, col: 10, offset: 30203},
+				pos: position{line: 1153, col: 10, offset: 32543},
 				run: (*parser).callonGroup1,
 				expr: &seqExpr{
-					pos: position{line: 1082, col: 10, offset: 30203},
+					pos: position{line: 1153, col: 10, offset: 32543},
 					exprs: []any{
 						&ruleRefExpr{
-							pos:  position{line: 1082, col: 10, offset: 30203},
+							pos:  position{line: 1153, col: 10, offset: 32543},
 							name: "_",
 						},
 						&litMatcher{
-							pos:        position{line: 1082, col: 12, offset: 30205},
+							pos:        position{line: 1153, col: 12, offset: 32545},
 							val:        "...",
 							ignoreCase: false,
 							want:       "\"...\"",
@@ -3664,29 +3947,29 @@ var g = &grammar{
 		},
 		{
 			name: "StringLitNode",
-			pos:  position{line: 1087, col: 1, offset: 30235},
+			pos:  position{line: 1158, col: 1, offset: 32575},
 			expr: &actionExpr{
-				pos: position{line: 1087, col: 18, offset: 30252},
+				pos: position{line: 1158, col: 18, offset: 32592},
 				run: (*parser).callonStringLitNode1,
 				expr: &seqExpr{
-					pos: position{line: 1087, col: 18, offset: 30252},
+					pos: position{line: 1158, col: 18, offset: 32592},
 					exprs: []any{
 						&litMatcher{
-							pos:        position{line: 1087, col: 18, offset: 30252},
+							pos:        position{line: 1158, col: 18, offset: 32592},
 							val:        "'",
 							ignoreCase: false,
 							want:       "\"'\"",
 						},
 						&labeledExpr{
-							pos:   position{line: 1087, col: 22, offset: 30256},
+							pos:   position{line: 1158, col: 22, offset: 32596},
 							label: "body",
 							expr: &ruleRefExpr{
-								pos:  position{line: 1087, col: 27, offset: 30261},
+								pos:  position{line: 1158, col: 27, offset: 32601},
 								name: "StringBody",
 							},
 						},
 						&litMatcher{
-							pos:        position{line: 1087, col: 38, offset: 30272},
+							pos:        position{line: 1158, col: 38, offset: 32612},
 							val:        "'",
 							ignoreCase: false,
 							want:       "\"'\"",
@@ -3697,70 +3980,70 @@ var g = &grammar{
 		},
 		{
 			name: "StringBody",
-			pos:  position{line: 1091, col: 1, offset: 30340},
+			pos:  position{line: 1162, col: 1, offset: 32680},
 			expr: &actionExpr{
-				pos: position{line: 1091, col: 15, offset: 30354},
+				pos: position{line: 1162, col: 15, offset: 32694},
 				run: (*parser).callonStringBody1,
 				expr: &zeroOrMoreExpr{
-					pos: position{line: 1091, col: 15, offset: 30354},
+					pos: position{line: 1162, col: 15, offset: 32694},
 					expr: &choiceExpr{
-						pos: position{line: 1091, col: 17, offset: 30356},
+						pos: position{line: 1162, col: 17, offset: 32696},
 						alternatives: []any{
 							&seqExpr{
-								pos: position{line: 1091, col: 17, offset: 30356},
+								pos: position{line: 1162, col: 17, offset: 32696},
 								exprs: []any{
 									&notExpr{
-										pos: position{line: 1091, col: 17, offset: 30356},
+										pos: position{line: 1162, col: 17, offset: 32696},
 										expr: &litMatcher{
-											pos:        position{line: 1091, col: 18, offset: 30357},
+											pos:        position{line: 1162, col: 18, offset: 32697},
 											val:        "'",
 											ignoreCase: false,
 											want:       "\"'\"",
 										},
 									},
 									&notExpr{
-										pos: position{line: 1091, col: 22, offset: 30361},
+										pos: position{line: 1162, col: 22, offset: 32701},
 										expr: &litMatcher{
-											pos:        position{line: 1091, col: 23, offset: 30362},
+											pos:        position{line: 1162, col: 23, offset: 32702},
 											val:        "\\",
 											ignoreCase: false,
 											want:       "\"\\\\\"",
 										},
 									},
 									&notExpr{
-										pos: position{line: 1091, col: 28, offset: 30367},
+										pos: position{line: 1162, col: 28, offset: 32707},
 										expr: &litMatcher{
-											pos:        position{line: 1091, col: 29, offset: 30368},
+											pos:        position{line: 1162, col: 29, offset: 32708},
 											val:        "\n",
 											ignoreCase: false,
 											want:       "\"\\n\"",
 										},
 									},
 									&notExpr{
-										pos: position{line: 1091, col: 34, offset: 30373},
+										pos: position{line: 1162, col: 34, offset: 32713},
 										expr: &litMatcher{
-											pos:        position{line: 1091, col: 35, offset: 30374},
+											pos:        position{line: 1162, col: 35, offset: 32714},
 											val:        "\r",
 											ignoreCase: false,
 											want:       "\"\\r\"",
 										},
 									},
 									&anyMatcher{
-										line: 1091, col: 40, offset: 30379,
+										line: 1162, col: 40, offset: 32719,
 									},
 								},
 							},
 							&seqExpr{
-								pos: position{line: 1091, col: 44, offset: 30383},
+								pos: position{line: 1162, col: 44, offset: 32723},
 								exprs: []any{
 									&litMatcher{
-										pos:        position{line: 1091, col: 44, offset: 30383},
+										pos:        position{line: 1162, col: 44, offset: 32723},
 										val:        "\\",
 										ignoreCase: false,
 										want:       "\"\\\\\"",
 									},
 									&anyMatcher{
-										line: 1091, col: 49, offset: 30388,
+										line: 1162, col: 49, offset: 32728,
 									},
 								},
 							},
@@ -3771,31 +4054,31 @@ var g = &grammar{
 		},
 		{
 			name: "DoubleQuotedString",
-			pos:  position{line: 1100, col: 1, offset: 30782},
+			pos:  position{line: 1171, col: 1, offset: 33122},
 			expr: &actionExpr{
-				pos: position{line: 1100, col: 23, offset: 30804},
+				pos: position{line: 1171, col: 23, offset: 33144},
 				run: (*parser).callonDoubleQuotedString1,
 				expr: &seqExpr{
-					pos: position{line: 1100, col: 23, offset: 30804},
+					pos: position{line: 1171, col: 23, offset: 33144},
 					exprs: []any{
 						&litMatcher{
-							pos:        position{line: 1100, col: 23, offset: 30804},
+							pos:        position{line: 1171, col: 23, offset: 33144},
 							val:        "\"",
 							ignoreCase: false,
 							want:       "\"\\\"\"",
 						},
 						&labeledExpr{
-							pos:   position{line: 1100, col: 28, offset: 30809},
+							pos:   position{line: 1171, col: 28, offset: 33149},
 							label: "body",
 							expr: &ruleRefExpr{
-								pos:  position{line: 1100, col: 33, offset: 30814},
+								pos:  position{line: 1171, col: 33, offset: 33154},
 								name: "DoubleQuotedBody",
 							},
 						},
 						&zeroOrOneExpr{
-							pos: position{line: 1100, col: 50, offset: 30831},
+							pos: position{line: 1171, col: 50, offset: 33171},
 							expr: &litMatcher{
-								pos:        position{line: 1100, col: 50, offset: 30831},
+								pos:        position{line: 1171, col: 50, offset: 33171},
 								val:        "\"",
 								ignoreCase: false,
 								want:       "\"\\\"\"",
@@ -3807,44 +4090,44 @@ var g = &grammar{
 		},
 		{
 			name: "DoubleQuotedBody",
-			pos:  position{line: 1106, col: 1, offset: 30976},
+			pos:  position{line: 1177, col: 1, offset: 33316},
 			expr: &actionExpr{
-				pos: position{line: 1106, col: 21, offset: 30996},
+				pos: position{line: 1177, col: 21, offset: 33336},
 				run: (*parser).callonDoubleQuotedBody1,
 				expr: &zeroOrMoreExpr{
-					pos: position{line: 1106, col: 21, offset: 30996},
+					pos: position{line: 1177, col: 21, offset: 33336},
 					expr: &seqExpr{
-						pos: position{line: 1106, col: 23, offset: 30998},
+						pos: position{line: 1177, col: 23, offset: 33338},
 						exprs: []any{
 							&notExpr{
-								pos: position{line: 1106, col: 23, offset: 30998},
+								pos: position{line: 1177, col: 23, offset: 33338},
 								expr: &litMatcher{
-									pos:        position{line: 1106, col: 24, offset: 30999},
+									pos:        position{line: 1177, col: 24, offset: 33339},
 									val:        "\"",
 									ignoreCase: false,
 									want:       "\"\\\"\"",
 								},
 							},
 							&notExpr{
-								pos: position{line: 1106, col: 29, offset: 31004},
+								pos: position{line: 1177, col: 29, offset: 33344},
 								expr: &litMatcher{
-									pos:        position{line: 1106, col: 30, offset: 31005},
+									pos:        position{line: 1177, col: 30, offset: 33345},
 									val:        "\n",
 									ignoreCase: false,
 									want:       "\"\\n\"",
 								},
 							},
 							&notExpr{
-								pos: position{line: 1106, col: 35, offset: 31010},
+								pos: position{line: 1177, col: 35, offset: 33350},
 								expr: &litMatcher{
-									pos:        position{line: 1106, col: 36, offset: 31011},
+									pos:        position{line: 1177, col: 36, offset: 33351},
 									val:        "\r",
 									ignoreCase: false,
 									want:       "\"\\r\"",
 								},
 							},
 							&anyMatcher{
-								line: 1106, col: 41, offset: 31016,
+								line: 1177, col: 41, offset: 33356,
 							},
 						},
 					},
@@ -3853,15 +4136,15 @@ var g = &grammar{
 		},
 		{
 			name: "DoubleQuotedKey",
-			pos:  position{line: 1110, col: 1, offset: 31054},
+			pos:  position{line: 1181, col: 1, offset: 33394},
 			expr: &actionExpr{
-				pos: position{line: 1110, col: 20, offset: 31073},
+				pos: position{line: 1181, col: 20, offset: 33413},
 				run: (*parser).callonDoubleQuotedKey1,
 				expr: &labeledExpr{
-					pos:   position{line: 1110, col: 20, offset: 31073},
+					pos:   position{line: 1181, col: 20, offset: 33413},
 					label: "s",
 					expr: &ruleRefExpr{
-						pos:  position{line: 1110, col: 22, offset: 31075},
+						pos:  position{line: 1181, col: 22, offset: 33415},
 						name: "DoubleQuotedString",
 					},
 				},
@@ -3869,20 +4152,20 @@ var g = &grammar{
 		},
 		{
 			name: "InterpolatedStringNode",
-			pos:  position{line: 1124, col: 1, offset: 31805},
+			pos:  position{line: 1195, col: 1, offset: 34145},
 			expr: &choiceExpr{
-				pos: position{line: 1124, col: 27, offset: 31831},
+				pos: position{line: 1195, col: 27, offset: 34171},
 				alternatives: []any{
 					&ruleRefExpr{
-						pos:  position{line: 1124, col: 27, offset: 31831},
+						pos:  position{line: 1195, col: 27, offset: 34171},
 						name: "InterpolatedMultiTriple",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 1124, col: 53, offset: 31857},
+						pos:  position{line: 1195, col: 53, offset: 34197},
 						name: "InterpolatedSingleTriple",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 1124, col: 80, offset: 31884},
+						pos:  position{line: 1195, col: 80, offset: 34224},
 						name: "InterpolatedSingleQuote",
 					},
 				},
@@ -3890,47 +4173,47 @@ var g = &grammar{
 		},
 		{
 			name: "InterpolatedMultiTriple",
-			pos:  position{line: 1126, col: 1, offset: 31909},
+			pos:  position{line: 1197, col: 1, offset: 34249},
 			expr: &actionExpr{
-				pos: position{line: 1126, col: 28, offset: 31936},
+				pos: position{line: 1197, col: 28, offset: 34276},
 				run: (*parser).callonInterpolatedMultiTriple1,
 				expr: &seqExpr{
-					pos: position{line: 1126, col: 28, offset: 31936},
+					pos: position{line: 1197, col: 28, offset: 34276},
 					exprs: []any{
 						&litMatcher{
-							pos:        position{line: 1126, col: 28, offset: 31936},
+							pos:        position{line: 1197, col: 28, offset: 34276},
 							val:        "$'''",
 							ignoreCase: false,
 							want:       "\"$'''\"",
 						},
 						&labeledExpr{
-							pos:   position{line: 1126, col: 35, offset: 31943},
+							pos:   position{line: 1197, col: 35, offset: 34283},
 							label: "s",
 							expr: &ruleRefExpr{
-								pos:  position{line: 1126, col: 37, offset: 31945},
+								pos:  position{line: 1197, col: 37, offset: 34285},
 								name: "Sigil",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 1126, col: 43, offset: 31951},
+							pos:  position{line: 1197, col: 43, offset: 34291},
 							name: "OpeningSpace",
 						},
 						&litMatcher{
-							pos:        position{line: 1126, col: 56, offset: 31964},
+							pos:        position{line: 1197, col: 56, offset: 34304},
 							val:        "\n",
 							ignoreCase: false,
 							want:       "\"\\n\"",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 1126, col: 61, offset: 31969},
+							pos:  position{line: 1197, col: 61, offset: 34309},
 							name: "MultiLineContent",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 1126, col: 78, offset: 31986},
+							pos:  position{line: 1197, col: 78, offset: 34326},
 							name: "Indent",
 						},
 						&litMatcher{
-							pos:        position{line: 1126, col: 85, offset: 31993},
+							pos:        position{line: 1197, col: 85, offset: 34333},
 							val:        "'''",
 							ignoreCase: false,
 							want:       "\"'''\"",
@@ -3941,25 +4224,25 @@ var g = &grammar{
 		},
 		{
 			name: "InterpolatedSingleTriple",
-			pos:  position{line: 1130, col: 1, offset: 32082},
+			pos:  position{line: 1201, col: 1, offset: 34422},
 			expr: &actionExpr{
-				pos: position{line: 1130, col: 29, offset: 32110},
+				pos: position{line: 1201, col: 29, offset: 34450},
 				run: (*parser).callonInterpolatedSingleTriple1,
 				expr: &seqExpr{
-					pos: position{line: 1130, col: 29, offset: 32110},
+					pos: position{line: 1201, col: 29, offset: 34450},
 					exprs: []any{
 						&litMatcher{
-							pos:        position{line: 1130, col: 29, offset: 32110},
+							pos:        position{line: 1201, col: 29, offset: 34450},
 							val:        "$'''",
 							ignoreCase: false,
 							want:       "\"$'''\"",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 1130, col: 36, offset: 32117},
+							pos:  position{line: 1201, col: 36, offset: 34457},
 							name: "SingleLineContent",
 						},
 						&litMatcher{
-							pos:        position{line: 1130, col: 54, offset: 32135},
+							pos:        position{line: 1201, col: 54, offset: 34475},
 							val:        "'''",
 							ignoreCase: false,
 							want:       "\"'''\"",
@@ -3970,41 +4253,41 @@ var g = &grammar{
 		},
 		{
 			name: "InterpolatedSingleQuote",
-			pos:  position{line: 1134, col: 1, offset: 32216},
+			pos:  position{line: 1205, col: 1, offset: 34556},
 			expr: &actionExpr{
-				pos: position{line: 1134, col: 28, offset: 32243},
+				pos: position{line: 1205, col: 28, offset: 34583},
 				run: (*parser).callonInterpolatedSingleQuote1,
 				expr: &seqExpr{
-					pos: position{line: 1134, col: 28, offset: 32243},
+					pos: position{line: 1205, col: 28, offset: 34583},
 					exprs: []any{
 						&litMatcher{
-							pos:        position{line: 1134, col: 28, offset: 32243},
+							pos:        position{line: 1205, col: 28, offset: 34583},
 							val:        "$'",
 							ignoreCase: false,
 							want:       "\"$'\"",
 						},
 						&notExpr{
-							pos: position{line: 1134, col: 33, offset: 32248},
+							pos: position{line: 1205, col: 33, offset: 34588},
 							expr: &litMatcher{
-								pos:        position{line: 1134, col: 34, offset: 32249},
+								pos:        position{line: 1205, col: 34, offset: 34589},
 								val:        "''",
 								ignoreCase: false,
 								want:       "\"''\"",
 							},
 						},
 						&labeledExpr{
-							pos:   position{line: 1134, col: 39, offset: 32254},
+							pos:   position{line: 1205, col: 39, offset: 34594},
 							label: "parts",
 							expr: &zeroOrMoreExpr{
-								pos: position{line: 1134, col: 45, offset: 32260},
+								pos: position{line: 1205, col: 45, offset: 34600},
 								expr: &ruleRefExpr{
-									pos:  position{line: 1134, col: 45, offset: 32260},
+									pos:  position{line: 1205, col: 45, offset: 34600},
 									name: "InterpolatedSinglePart",
 								},
 							},
 						},
 						&litMatcher{
-							pos:        position{line: 1134, col: 69, offset: 32284},
+							pos:        position{line: 1205, col: 69, offset: 34624},
 							val:        "'",
 							ignoreCase: false,
 							want:       "\"'\"",
@@ -4015,16 +4298,16 @@ var g = &grammar{
 		},
 		{
 			name: "InterpolatedSinglePart",
-			pos:  position{line: 1138, col: 1, offset: 32362},
+			pos:  position{line: 1209, col: 1, offset: 34702},
 			expr: &choiceExpr{
-				pos: position{line: 1138, col: 27, offset: 32388},
+				pos: position{line: 1209, col: 27, offset: 34728},
 				alternatives: []any{
 					&ruleRefExpr{
-						pos:  position{line: 1138, col: 27, offset: 32388},
+						pos:  position{line: 1209, col: 27, offset: 34728},
 						name: "InterpolatedSlot",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 1138, col: 46, offset: 32407},
+						pos:  position{line: 1209, col: 46, offset: 34747},
 						name: "InterpolatedSingleLiteral",
 					},
 				},
@@ -4032,52 +4315,52 @@ var g = &grammar{
 		},
 		{
 			name: "InterpolatedSlot",
-			pos:  position{line: 1140, col: 1, offset: 32434},
+			pos:  position{line: 1211, col: 1, offset: 34774},
 			expr: &choiceExpr{
-				pos: position{line: 1140, col: 21, offset: 32454},
+				pos: position{line: 1211, col: 21, offset: 34794},
 				alternatives: []any{
 					&ruleRefExpr{
-						pos:  position{line: 1140, col: 21, offset: 32454},
+						pos:  position{line: 1211, col: 21, offset: 34794},
 						name: "EmptySlot",
 					},
 					&actionExpr{
-						pos: position{line: 1140, col: 33, offset: 32466},
+						pos: position{line: 1211, col: 33, offset: 34806},
 						run: (*parser).callonInterpolatedSlot3,
 						expr: &seqExpr{
-							pos: position{line: 1140, col: 33, offset: 32466},
+							pos: position{line: 1211, col: 33, offset: 34806},
 							exprs: []any{
 								&litMatcher{
-									pos:        position{line: 1140, col: 33, offset: 32466},
+									pos:        position{line: 1211, col: 33, offset: 34806},
 									val:        "{{",
 									ignoreCase: false,
 									want:       "\"{{\"",
 								},
 								&ruleRefExpr{
-									pos:  position{line: 1140, col: 38, offset: 32471},
+									pos:  position{line: 1211, col: 38, offset: 34811},
 									name: "_",
 								},
 								&labeledExpr{
-									pos:   position{line: 1140, col: 40, offset: 32473},
+									pos:   position{line: 1211, col: 40, offset: 34813},
 									label: "e",
 									expr: &ruleRefExpr{
-										pos:  position{line: 1140, col: 42, offset: 32475},
+										pos:  position{line: 1211, col: 42, offset: 34815},
 										name: "Value",
 									},
 								},
 								&labeledExpr{
-									pos:   position{line: 1140, col: 48, offset: 32481},
+									pos:   position{line: 1211, col: 48, offset: 34821},
 									label: "v",
 									expr: &zeroOrOneExpr{
-										pos: position{line: 1140, col: 50, offset: 32483},
+										pos: position{line: 1211, col: 50, offset: 34823},
 										expr: &choiceExpr{
-											pos: position{line: 1140, col: 52, offset: 32485},
+											pos: position{line: 1211, col: 52, offset: 34825},
 											alternatives: []any{
 												&ruleRefExpr{
-													pos:  position{line: 1140, col: 52, offset: 32485},
+													pos:  position{line: 1211, col: 52, offset: 34825},
 													name: "InterpolatedVerb",
 												},
 												&ruleRefExpr{
-													pos:  position{line: 1140, col: 71, offset: 32504},
+													pos:  position{line: 1211, col: 71, offset: 34844},
 													name: "InterpolatedBadVerb",
 												},
 											},
@@ -4085,11 +4368,11 @@ var g = &grammar{
 									},
 								},
 								&ruleRefExpr{
-									pos:  position{line: 1140, col: 94, offset: 32527},
+									pos:  position{line: 1211, col: 94, offset: 34867},
 									name: "_",
 								},
 								&litMatcher{
-									pos:        position{line: 1140, col: 96, offset: 32529},
+									pos:        position{line: 1211, col: 96, offset: 34869},
 									val:        "}}",
 									ignoreCase: false,
 									want:       "\"}}\"",
@@ -4102,25 +4385,25 @@ var g = &grammar{
 		},
 		{
 			name: "EmptySlot",
-			pos:  position{line: 1151, col: 1, offset: 32790},
+			pos:  position{line: 1222, col: 1, offset: 35130},
 			expr: &actionExpr{
-				pos: position{line: 1151, col: 14, offset: 32803},
+				pos: position{line: 1222, col: 14, offset: 35143},
 				run: (*parser).callonEmptySlot1,
 				expr: &seqExpr{
-					pos: position{line: 1151, col: 14, offset: 32803},
+					pos: position{line: 1222, col: 14, offset: 35143},
 					exprs: []any{
 						&litMatcher{
-							pos:        position{line: 1151, col: 14, offset: 32803},
+							pos:        position{line: 1222, col: 14, offset: 35143},
 							val:        "{{",
 							ignoreCase: false,
 							want:       "\"{{\"",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 1151, col: 19, offset: 32808},
+							pos:  position{line: 1222, col: 19, offset: 35148},
 							name: "_",
 						},
 						&litMatcher{
-							pos:        position{line: 1151, col: 21, offset: 32810},
+							pos:        position{line: 1222, col: 21, offset: 35150},
 							val:        "}}",
 							ignoreCase: false,
 							want:       "\"}}\"",
@@ -4131,32 +4414,32 @@ var g = &grammar{
 		},
 		{
 			name: "InterpolatedVerb",
-			pos:  position{line: 1158, col: 1, offset: 33033},
+			pos:  position{line: 1229, col: 1, offset: 35373},
 			expr: &actionExpr{
-				pos: position{line: 1158, col: 21, offset: 33053},
+				pos: position{line: 1229, col: 21, offset: 35393},
 				run: (*parser).callonInterpolatedVerb1,
 				expr: &seqExpr{
-					pos: position{line: 1158, col: 21, offset: 33053},
+					pos: position{line: 1229, col: 21, offset: 35393},
 					exprs: []any{
 						&ruleRefExpr{
-							pos:  position{line: 1158, col: 21, offset: 33053},
+							pos:  position{line: 1229, col: 21, offset: 35393},
 							name: "_",
 						},
 						&litMatcher{
-							pos:        position{line: 1158, col: 23, offset: 33055},
+							pos:        position{line: 1229, col: 23, offset: 35395},
 							val:        ":",
 							ignoreCase: false,
 							want:       "\":\"",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 1158, col: 27, offset: 33059},
+							pos:  position{line: 1229, col: 27, offset: 35399},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 1158, col: 29, offset: 33061},
+							pos:   position{line: 1229, col: 29, offset: 35401},
 							label: "v",
 							expr: &ruleRefExpr{
-								pos:  position{line: 1158, col: 31, offset: 33063},
+								pos:  position{line: 1229, col: 31, offset: 35403},
 								name: "VerbBody",
 							},
 						},
@@ -4166,79 +4449,79 @@ var g = &grammar{
 		},
 		{
 			name: "InterpolatedBadVerb",
-			pos:  position{line: 1162, col: 1, offset: 33092},
+			pos:  position{line: 1233, col: 1, offset: 35432},
 			expr: &actionExpr{
-				pos: position{line: 1162, col: 24, offset: 33115},
+				pos: position{line: 1233, col: 24, offset: 35455},
 				run: (*parser).callonInterpolatedBadVerb1,
 				expr: &seqExpr{
-					pos: position{line: 1162, col: 24, offset: 33115},
+					pos: position{line: 1233, col: 24, offset: 35455},
 					exprs: []any{
 						&ruleRefExpr{
-							pos:  position{line: 1162, col: 24, offset: 33115},
+							pos:  position{line: 1233, col: 24, offset: 35455},
 							name: "_",
 						},
 						&litMatcher{
-							pos:        position{line: 1162, col: 26, offset: 33117},
+							pos:        position{line: 1233, col: 26, offset: 35457},
 							val:        ":",
 							ignoreCase: false,
 							want:       "\":\"",
 						},
 						&zeroOrMoreExpr{
-							pos: position{line: 1162, col: 30, offset: 33121},
+							pos: position{line: 1233, col: 30, offset: 35461},
 							expr: &choiceExpr{
-								pos: position{line: 1162, col: 32, offset: 33123},
+								pos: position{line: 1233, col: 32, offset: 35463},
 								alternatives: []any{
 									&seqExpr{
-										pos: position{line: 1162, col: 32, offset: 33123},
+										pos: position{line: 1233, col: 32, offset: 35463},
 										exprs: []any{
 											&litMatcher{
-												pos:        position{line: 1162, col: 32, offset: 33123},
+												pos:        position{line: 1233, col: 32, offset: 35463},
 												val:        "'",
 												ignoreCase: false,
 												want:       "\"'\"",
 											},
 											&zeroOrMoreExpr{
-												pos: position{line: 1162, col: 36, offset: 33127},
+												pos: position{line: 1233, col: 36, offset: 35467},
 												expr: &choiceExpr{
-													pos: position{line: 1162, col: 38, offset: 33129},
+													pos: position{line: 1233, col: 38, offset: 35469},
 													alternatives: []any{
 														&seqExpr{
-															pos: position{line: 1162, col: 38, offset: 33129},
+															pos: position{line: 1233, col: 38, offset: 35469},
 															exprs: []any{
 																&litMatcher{
-																	pos:        position{line: 1162, col: 38, offset: 33129},
+																	pos:        position{line: 1233, col: 38, offset: 35469},
 																	val:        "\\",
 																	ignoreCase: false,
 																	want:       "\"\\\\\"",
 																},
 																&anyMatcher{
-																	line: 1162, col: 43, offset: 33134,
+																	line: 1233, col: 43, offset: 35474,
 																},
 															},
 														},
 														&seqExpr{
-															pos: position{line: 1162, col: 47, offset: 33138},
+															pos: position{line: 1233, col: 47, offset: 35478},
 															exprs: []any{
 																&notExpr{
-																	pos: position{line: 1162, col: 47, offset: 33138},
+																	pos: position{line: 1233, col: 47, offset: 35478},
 																	expr: &litMatcher{
-																		pos:        position{line: 1162, col: 48, offset: 33139},
+																		pos:        position{line: 1233, col: 48, offset: 35479},
 																		val:        "'",
 																		ignoreCase: false,
 																		want:       "\"'\"",
 																	},
 																},
 																&notExpr{
-																	pos: position{line: 1162, col: 52, offset: 33143},
+																	pos: position{line: 1233, col: 52, offset: 35483},
 																	expr: &litMatcher{
-																		pos:        position{line: 1162, col: 53, offset: 33144},
+																		pos:        position{line: 1233, col: 53, offset: 35484},
 																		val:        "\n",
 																		ignoreCase: false,
 																		want:       "\"\\n\"",
 																	},
 																},
 																&anyMatcher{
-																	line: 1162, col: 58, offset: 33149,
+																	line: 1233, col: 58, offset: 35489,
 																},
 															},
 														},
@@ -4246,7 +4529,7 @@ var g = &grammar{
 												},
 											},
 											&litMatcher{
-												pos:        position{line: 1162, col: 63, offset: 33154},
+												pos:        position{line: 1233, col: 63, offset: 35494},
 												val:        "'",
 												ignoreCase: false,
 												want:       "\"'\"",
@@ -4254,28 +4537,28 @@ var g = &grammar{
 										},
 									},
 									&seqExpr{
-										pos: position{line: 1162, col: 69, offset: 33160},
+										pos: position{line: 1233, col: 69, offset: 35500},
 										exprs: []any{
 											&notExpr{
-												pos: position{line: 1162, col: 69, offset: 33160},
+												pos: position{line: 1233, col: 69, offset: 35500},
 												expr: &litMatcher{
-													pos:        position{line: 1162, col: 70, offset: 33161},
+													pos:        position{line: 1233, col: 70, offset: 35501},
 													val:        "}}",
 													ignoreCase: false,
 													want:       "\"}}\"",
 												},
 											},
 											&notExpr{
-												pos: position{line: 1162, col: 75, offset: 33166},
+												pos: position{line: 1233, col: 75, offset: 35506},
 												expr: &litMatcher{
-													pos:        position{line: 1162, col: 76, offset: 33167},
+													pos:        position{line: 1233, col: 76, offset: 35507},
 													val:        "\n",
 													ignoreCase: false,
 													want:       "\"\\n\"",
 												},
 											},
 											&anyMatcher{
-												line: 1162, col: 81, offset: 33172,
+												line: 1233, col: 81, offset: 35512,
 											},
 										},
 									},
@@ -4288,53 +4571,53 @@ var g = &grammar{
 		},
 		{
 			name: "VerbBody",
-			pos:  position{line: 1166, col: 1, offset: 33265},
+			pos:  position{line: 1237, col: 1, offset: 35605},
 			expr: &actionExpr{
-				pos: position{line: 1166, col: 13, offset: 33277},
+				pos: position{line: 1237, col: 13, offset: 35617},
 				run: (*parser).callonVerbBody1,
 				expr: &seqExpr{
-					pos: position{line: 1166, col: 13, offset: 33277},
+					pos: position{line: 1237, col: 13, offset: 35617},
 					exprs: []any{
 						&litMatcher{
-							pos:        position{line: 1166, col: 13, offset: 33277},
+							pos:        position{line: 1237, col: 13, offset: 35617},
 							val:        "%",
 							ignoreCase: false,
 							want:       "\"%\"",
 						},
 						&zeroOrMoreExpr{
-							pos: position{line: 1166, col: 17, offset: 33281},
+							pos: position{line: 1237, col: 17, offset: 35621},
 							expr: &seqExpr{
-								pos: position{line: 1166, col: 19, offset: 33283},
+								pos: position{line: 1237, col: 19, offset: 35623},
 								exprs: []any{
 									&notExpr{
-										pos: position{line: 1166, col: 19, offset: 33283},
+										pos: position{line: 1237, col: 19, offset: 35623},
 										expr: &litMatcher{
-											pos:        position{line: 1166, col: 20, offset: 33284},
+											pos:        position{line: 1237, col: 20, offset: 35624},
 											val:        "}}",
 											ignoreCase: false,
 											want:       "\"}}\"",
 										},
 									},
 									&notExpr{
-										pos: position{line: 1166, col: 25, offset: 33289},
+										pos: position{line: 1237, col: 25, offset: 35629},
 										expr: &litMatcher{
-											pos:        position{line: 1166, col: 26, offset: 33290},
+											pos:        position{line: 1237, col: 26, offset: 35630},
 											val:        "\n",
 											ignoreCase: false,
 											want:       "\"\\n\"",
 										},
 									},
 									&notExpr{
-										pos: position{line: 1166, col: 31, offset: 33295},
+										pos: position{line: 1237, col: 31, offset: 35635},
 										expr: &litMatcher{
-											pos:        position{line: 1166, col: 32, offset: 33296},
+											pos:        position{line: 1237, col: 32, offset: 35636},
 											val:        "'",
 											ignoreCase: false,
 											want:       "\"'\"",
 										},
 									},
 									&anyMatcher{
-										line: 1166, col: 36, offset: 33300,
+										line: 1237, col: 36, offset: 35640,
 									},
 								},
 							},
@@ -4345,70 +4628,70 @@ var g = &grammar{
 		},
 		{
 			name: "InterpolatedSingleLiteral",
-			pos:  position{line: 1170, col: 1, offset: 33364},
+			pos:  position{line: 1241, col: 1, offset: 35704},
 			expr: &actionExpr{
-				pos: position{line: 1170, col: 30, offset: 33393},
+				pos: position{line: 1241, col: 30, offset: 35733},
 				run: (*parser).callonInterpolatedSingleLiteral1,
 				expr: &oneOrMoreExpr{
-					pos: position{line: 1170, col: 30, offset: 33393},
+					pos: position{line: 1241, col: 30, offset: 35733},
 					expr: &choiceExpr{
-						pos: position{line: 1170, col: 32, offset: 33395},
+						pos: position{line: 1241, col: 32, offset: 35735},
 						alternatives: []any{
 							&seqExpr{
-								pos: position{line: 1170, col: 32, offset: 33395},
+								pos: position{line: 1241, col: 32, offset: 35735},
 								exprs: []any{
 									&litMatcher{
-										pos:        position{line: 1170, col: 32, offset: 33395},
+										pos:        position{line: 1241, col: 32, offset: 35735},
 										val:        "\\",
 										ignoreCase: false,
 										want:       "\"\\\\\"",
 									},
 									&anyMatcher{
-										line: 1170, col: 37, offset: 33400,
+										line: 1241, col: 37, offset: 35740,
 									},
 								},
 							},
 							&seqExpr{
-								pos: position{line: 1170, col: 41, offset: 33404},
+								pos: position{line: 1241, col: 41, offset: 35744},
 								exprs: []any{
 									&notExpr{
-										pos: position{line: 1170, col: 41, offset: 33404},
+										pos: position{line: 1241, col: 41, offset: 35744},
 										expr: &litMatcher{
-											pos:        position{line: 1170, col: 42, offset: 33405},
+											pos:        position{line: 1241, col: 42, offset: 35745},
 											val:        "{{",
 											ignoreCase: false,
 											want:       "\"{{\"",
 										},
 									},
 									&notExpr{
-										pos: position{line: 1170, col: 47, offset: 33410},
+										pos: position{line: 1241, col: 47, offset: 35750},
 										expr: &litMatcher{
-											pos:        position{line: 1170, col: 48, offset: 33411},
+											pos:        position{line: 1241, col: 48, offset: 35751},
 											val:        "'",
 											ignoreCase: false,
 											want:       "\"'\"",
 										},
 									},
 									&notExpr{
-										pos: position{line: 1170, col: 52, offset: 33415},
+										pos: position{line: 1241, col: 52, offset: 35755},
 										expr: &litMatcher{
-											pos:        position{line: 1170, col: 53, offset: 33416},
+											pos:        position{line: 1241, col: 53, offset: 35756},
 											val:        "\n",
 											ignoreCase: false,
 											want:       "\"\\n\"",
 										},
 									},
 									&notExpr{
-										pos: position{line: 1170, col: 58, offset: 33421},
+										pos: position{line: 1241, col: 58, offset: 35761},
 										expr: &litMatcher{
-											pos:        position{line: 1170, col: 59, offset: 33422},
+											pos:        position{line: 1241, col: 59, offset: 35762},
 											val:        "\r",
 											ignoreCase: false,
 											want:       "\"\\r\"",
 										},
 									},
 									&anyMatcher{
-										line: 1170, col: 64, offset: 33427,
+										line: 1241, col: 64, offset: 35767,
 									},
 								},
 							},
@@ -4419,16 +4702,16 @@ var g = &grammar{
 		},
 		{
 			name: "TripleQuoteStringLitNode",
-			pos:  position{line: 1178, col: 1, offset: 33592},
+			pos:  position{line: 1249, col: 1, offset: 35932},
 			expr: &choiceExpr{
-				pos: position{line: 1178, col: 29, offset: 33620},
+				pos: position{line: 1249, col: 29, offset: 35960},
 				alternatives: []any{
 					&ruleRefExpr{
-						pos:  position{line: 1178, col: 29, offset: 33620},
+						pos:  position{line: 1249, col: 29, offset: 35960},
 						name: "MultiLineTripleQuote",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 1178, col: 52, offset: 33643},
+						pos:  position{line: 1249, col: 52, offset: 35983},
 						name: "SingleLineTripleQuote",
 					},
 				},
@@ -4436,47 +4719,47 @@ var g = &grammar{
 		},
 		{
 			name: "MultiLineTripleQuote",
-			pos:  position{line: 1180, col: 1, offset: 33666},
+			pos:  position{line: 1251, col: 1, offset: 36006},
 			expr: &actionExpr{
-				pos: position{line: 1180, col: 25, offset: 33690},
+				pos: position{line: 1251, col: 25, offset: 36030},
 				run: (*parser).callonMultiLineTripleQuote1,
 				expr: &seqExpr{
-					pos: position{line: 1180, col: 25, offset: 33690},
+					pos: position{line: 1251, col: 25, offset: 36030},
 					exprs: []any{
 						&litMatcher{
-							pos:        position{line: 1180, col: 25, offset: 33690},
+							pos:        position{line: 1251, col: 25, offset: 36030},
 							val:        "'''",
 							ignoreCase: false,
 							want:       "\"'''\"",
 						},
 						&labeledExpr{
-							pos:   position{line: 1180, col: 31, offset: 33696},
+							pos:   position{line: 1251, col: 31, offset: 36036},
 							label: "s",
 							expr: &ruleRefExpr{
-								pos:  position{line: 1180, col: 33, offset: 33698},
+								pos:  position{line: 1251, col: 33, offset: 36038},
 								name: "Sigil",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 1180, col: 39, offset: 33704},
+							pos:  position{line: 1251, col: 39, offset: 36044},
 							name: "OpeningSpace",
 						},
 						&litMatcher{
-							pos:        position{line: 1180, col: 52, offset: 33717},
+							pos:        position{line: 1251, col: 52, offset: 36057},
 							val:        "\n",
 							ignoreCase: false,
 							want:       "\"\\n\"",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 1180, col: 57, offset: 33722},
+							pos:  position{line: 1251, col: 57, offset: 36062},
 							name: "MultiLineContent",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 1180, col: 74, offset: 33739},
+							pos:  position{line: 1251, col: 74, offset: 36079},
 							name: "Indent",
 						},
 						&litMatcher{
-							pos:        position{line: 1180, col: 81, offset: 33746},
+							pos:        position{line: 1251, col: 81, offset: 36086},
 							val:        "'''",
 							ignoreCase: false,
 							want:       "\"'''\"",
@@ -4487,25 +4770,25 @@ var g = &grammar{
 		},
 		{
 			name: "SingleLineTripleQuote",
-			pos:  position{line: 1186, col: 1, offset: 33916},
+			pos:  position{line: 1257, col: 1, offset: 36256},
 			expr: &actionExpr{
-				pos: position{line: 1186, col: 26, offset: 33941},
+				pos: position{line: 1257, col: 26, offset: 36281},
 				run: (*parser).callonSingleLineTripleQuote1,
 				expr: &seqExpr{
-					pos: position{line: 1186, col: 26, offset: 33941},
+					pos: position{line: 1257, col: 26, offset: 36281},
 					exprs: []any{
 						&litMatcher{
-							pos:        position{line: 1186, col: 26, offset: 33941},
+							pos:        position{line: 1257, col: 26, offset: 36281},
 							val:        "'''",
 							ignoreCase: false,
 							want:       "\"'''\"",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 1186, col: 32, offset: 33947},
+							pos:  position{line: 1257, col: 32, offset: 36287},
 							name: "SingleLineContent",
 						},
 						&litMatcher{
-							pos:        position{line: 1186, col: 50, offset: 33965},
+							pos:        position{line: 1257, col: 50, offset: 36305},
 							val:        "'''",
 							ignoreCase: false,
 							want:       "\"'''\"",
@@ -4516,30 +4799,30 @@ var g = &grammar{
 		},
 		{
 			name: "Sigil",
-			pos:  position{line: 1191, col: 1, offset: 34114},
+			pos:  position{line: 1262, col: 1, offset: 36454},
 			expr: &actionExpr{
-				pos: position{line: 1191, col: 10, offset: 34123},
+				pos: position{line: 1262, col: 10, offset: 36463},
 				run: (*parser).callonSigil1,
 				expr: &seqExpr{
-					pos: position{line: 1191, col: 10, offset: 34123},
+					pos: position{line: 1262, col: 10, offset: 36463},
 					exprs: []any{
 						&choiceExpr{
-							pos: position{line: 1191, col: 12, offset: 34125},
+							pos: position{line: 1262, col: 12, offset: 36465},
 							alternatives: []any{
 								&litMatcher{
-									pos:        position{line: 1191, col: 12, offset: 34125},
+									pos:        position{line: 1262, col: 12, offset: 36465},
 									val:        "|",
 									ignoreCase: false,
 									want:       "\"|\"",
 								},
 								&litMatcher{
-									pos:        position{line: 1191, col: 18, offset: 34131},
+									pos:        position{line: 1262, col: 18, offset: 36471},
 									val:        ">",
 									ignoreCase: false,
 									want:       "\">\"",
 								},
 								&litMatcher{
-									pos:        position{line: 1191, col: 24, offset: 34137},
+									pos:        position{line: 1262, col: 24, offset: 36477},
 									val:        "\\",
 									ignoreCase: false,
 									want:       "\"\\\\\"",
@@ -4547,9 +4830,9 @@ var g = &grammar{
 							},
 						},
 						&zeroOrOneExpr{
-							pos: position{line: 1191, col: 31, offset: 34144},
+							pos: position{line: 1262, col: 31, offset: 36484},
 							expr: &litMatcher{
-								pos:        position{line: 1191, col: 31, offset: 34144},
+								pos:        position{line: 1262, col: 31, offset: 36484},
 								val:        "-",
 								ignoreCase: false,
 								want:       "\"-\"",
@@ -4561,11 +4844,11 @@ var g = &grammar{
 		},
 		{
 			name: "OpeningSpace",
-			pos:  position{line: 1195, col: 1, offset: 34182},
+			pos:  position{line: 1266, col: 1, offset: 36522},
 			expr: &zeroOrMoreExpr{
-				pos: position{line: 1195, col: 17, offset: 34198},
+				pos: position{line: 1266, col: 17, offset: 36538},
 				expr: &charClassMatcher{
-					pos:        position{line: 1195, col: 17, offset: 34198},
+					pos:        position{line: 1266, col: 17, offset: 36538},
 					val:        "[ \\t]",
 					chars:      []rune{' ', '\t'},
 					ignoreCase: false,
@@ -4575,21 +4858,21 @@ var g = &grammar{
 		},
 		{
 			name: "MultiLineContent",
-			pos:  position{line: 1197, col: 1, offset: 34206},
+			pos:  position{line: 1268, col: 1, offset: 36546},
 			expr: &zeroOrMoreExpr{
-				pos: position{line: 1197, col: 21, offset: 34226},
+				pos: position{line: 1268, col: 21, offset: 36566},
 				expr: &seqExpr{
-					pos: position{line: 1197, col: 23, offset: 34228},
+					pos: position{line: 1268, col: 23, offset: 36568},
 					exprs: []any{
 						&notExpr{
-							pos: position{line: 1197, col: 23, offset: 34228},
+							pos: position{line: 1268, col: 23, offset: 36568},
 							expr: &ruleRefExpr{
-								pos:  position{line: 1197, col: 24, offset: 34229},
+								pos:  position{line: 1268, col: 24, offset: 36569},
 								name: "ContentBreak",
 							},
 						},
 						&anyMatcher{
-							line: 1197, col: 37, offset: 34242,
+							line: 1268, col: 37, offset: 36582,
 						},
 					},
 				},
@@ -4597,20 +4880,20 @@ var g = &grammar{
 		},
 		{
 			name: "ContentBreak",
-			pos:  position{line: 1199, col: 1, offset: 34248},
+			pos:  position{line: 1270, col: 1, offset: 36588},
 			expr: &seqExpr{
-				pos: position{line: 1199, col: 17, offset: 34264},
+				pos: position{line: 1270, col: 17, offset: 36604},
 				exprs: []any{
 					&litMatcher{
-						pos:        position{line: 1199, col: 17, offset: 34264},
+						pos:        position{line: 1270, col: 17, offset: 36604},
 						val:        "\n",
 						ignoreCase: false,
 						want:       "\"\\n\"",
 					},
 					&zeroOrMoreExpr{
-						pos: position{line: 1199, col: 22, offset: 34269},
+						pos: position{line: 1270, col: 22, offset: 36609},
 						expr: &charClassMatcher{
-							pos:        position{line: 1199, col: 22, offset: 34269},
+							pos:        position{line: 1270, col: 22, offset: 36609},
 							val:        "[ \\t]",
 							chars:      []rune{' ', '\t'},
 							ignoreCase: false,
@@ -4618,13 +4901,13 @@ var g = &grammar{
 						},
 					},
 					&litMatcher{
-						pos:        position{line: 1199, col: 29, offset: 34276},
+						pos:        position{line: 1270, col: 29, offset: 36616},
 						val:        "'''",
 						ignoreCase: false,
 						want:       "\"'''\"",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 1199, col: 35, offset: 34282},
+						pos:  position{line: 1270, col: 35, offset: 36622},
 						name: "CloseFollower",
 					},
 				},
@@ -4632,24 +4915,24 @@ var g = &grammar{
 		},
 		{
 			name: "CloseFollower",
-			pos:  position{line: 1201, col: 1, offset: 34297},
+			pos:  position{line: 1272, col: 1, offset: 36637},
 			expr: &choiceExpr{
-				pos: position{line: 1201, col: 18, offset: 34314},
+				pos: position{line: 1272, col: 18, offset: 36654},
 				alternatives: []any{
 					&litMatcher{
-						pos:        position{line: 1201, col: 18, offset: 34314},
+						pos:        position{line: 1272, col: 18, offset: 36654},
 						val:        "\n",
 						ignoreCase: false,
 						want:       "\"\\n\"",
 					},
 					&notExpr{
-						pos: position{line: 1201, col: 25, offset: 34321},
+						pos: position{line: 1272, col: 25, offset: 36661},
 						expr: &anyMatcher{
-							line: 1201, col: 26, offset: 34322,
+							line: 1272, col: 26, offset: 36662,
 						},
 					},
 					&charClassMatcher{
-						pos:        position{line: 1201, col: 30, offset: 34326},
+						pos:        position{line: 1272, col: 30, offset: 36666},
 						val:        "[ \\t,)}\\]#]",
 						chars:      []rune{' ', '\t', ',', ')', '}', ']', '#'},
 						ignoreCase: false,
@@ -4660,20 +4943,20 @@ var g = &grammar{
 		},
 		{
 			name: "Indent",
-			pos:  position{line: 1203, col: 1, offset: 34339},
+			pos:  position{line: 1274, col: 1, offset: 36679},
 			expr: &seqExpr{
-				pos: position{line: 1203, col: 11, offset: 34349},
+				pos: position{line: 1274, col: 11, offset: 36689},
 				exprs: []any{
 					&litMatcher{
-						pos:        position{line: 1203, col: 11, offset: 34349},
+						pos:        position{line: 1274, col: 11, offset: 36689},
 						val:        "\n",
 						ignoreCase: false,
 						want:       "\"\\n\"",
 					},
 					&zeroOrMoreExpr{
-						pos: position{line: 1203, col: 16, offset: 34354},
+						pos: position{line: 1274, col: 16, offset: 36694},
 						expr: &charClassMatcher{
-							pos:        position{line: 1203, col: 16, offset: 34354},
+							pos:        position{line: 1274, col: 16, offset: 36694},
 							val:        "[ \\t]",
 							chars:      []rune{' ', '\t'},
 							ignoreCase: false,
@@ -4685,27 +4968,27 @@ var g = &grammar{
 		},
 		{
 			name: "SingleLineContent",
-			pos:  position{line: 1205, col: 1, offset: 34362},
+			pos:  position{line: 1276, col: 1, offset: 36702},
 			expr: &zeroOrMoreExpr{
-				pos: position{line: 1205, col: 22, offset: 34383},
+				pos: position{line: 1276, col: 22, offset: 36723},
 				expr: &seqExpr{
-					pos: position{line: 1205, col: 24, offset: 34385},
+					pos: position{line: 1276, col: 24, offset: 36725},
 					exprs: []any{
 						&notExpr{
-							pos: position{line: 1205, col: 24, offset: 34385},
+							pos: position{line: 1276, col: 24, offset: 36725},
 							expr: &seqExpr{
-								pos: position{line: 1205, col: 26, offset: 34387},
+								pos: position{line: 1276, col: 26, offset: 36727},
 								exprs: []any{
 									&litMatcher{
-										pos:        position{line: 1205, col: 26, offset: 34387},
+										pos:        position{line: 1276, col: 26, offset: 36727},
 										val:        "'''",
 										ignoreCase: false,
 										want:       "\"'''\"",
 									},
 									&notExpr{
-										pos: position{line: 1205, col: 32, offset: 34393},
+										pos: position{line: 1276, col: 32, offset: 36733},
 										expr: &litMatcher{
-											pos:        position{line: 1205, col: 33, offset: 34394},
+											pos:        position{line: 1276, col: 33, offset: 36734},
 											val:        "'",
 											ignoreCase: false,
 											want:       "\"'\"",
@@ -4715,16 +4998,16 @@ var g = &grammar{
 							},
 						},
 						&notExpr{
-							pos: position{line: 1205, col: 38, offset: 34399},
+							pos: position{line: 1276, col: 38, offset: 36739},
 							expr: &litMatcher{
-								pos:        position{line: 1205, col: 39, offset: 34400},
+								pos:        position{line: 1276, col: 39, offset: 36740},
 								val:        "\n",
 								ignoreCase: false,
 								want:       "\"\\n\"",
 							},
 						},
 						&anyMatcher{
-							line: 1205, col: 44, offset: 34405,
+							line: 1276, col: 44, offset: 36745,
 						},
 					},
 				},
@@ -4732,26 +5015,26 @@ var g = &grammar{
 		},
 		{
 			name: "NumberLitNode",
-			pos:  position{line: 1207, col: 1, offset: 34411},
+			pos:  position{line: 1278, col: 1, offset: 36751},
 			expr: &actionExpr{
-				pos: position{line: 1207, col: 18, offset: 34428},
+				pos: position{line: 1278, col: 18, offset: 36768},
 				run: (*parser).callonNumberLitNode1,
 				expr: &seqExpr{
-					pos: position{line: 1207, col: 20, offset: 34430},
+					pos: position{line: 1278, col: 20, offset: 36770},
 					exprs: []any{
 						&zeroOrOneExpr{
-							pos: position{line: 1207, col: 20, offset: 34430},
+							pos: position{line: 1278, col: 20, offset: 36770},
 							expr: &litMatcher{
-								pos:        position{line: 1207, col: 20, offset: 34430},
+								pos:        position{line: 1278, col: 20, offset: 36770},
 								val:        "-",
 								ignoreCase: false,
 								want:       "\"-\"",
 							},
 						},
 						&oneOrMoreExpr{
-							pos: position{line: 1207, col: 25, offset: 34435},
+							pos: position{line: 1278, col: 25, offset: 36775},
 							expr: &charClassMatcher{
-								pos:        position{line: 1207, col: 25, offset: 34435},
+								pos:        position{line: 1278, col: 25, offset: 36775},
 								val:        "[0-9]",
 								ranges:     []rune{'0', '9'},
 								ignoreCase: false,
@@ -4759,20 +5042,20 @@ var g = &grammar{
 							},
 						},
 						&zeroOrOneExpr{
-							pos: position{line: 1207, col: 32, offset: 34442},
+							pos: position{line: 1278, col: 32, offset: 36782},
 							expr: &seqExpr{
-								pos: position{line: 1207, col: 34, offset: 34444},
+								pos: position{line: 1278, col: 34, offset: 36784},
 								exprs: []any{
 									&litMatcher{
-										pos:        position{line: 1207, col: 34, offset: 34444},
+										pos:        position{line: 1278, col: 34, offset: 36784},
 										val:        ".",
 										ignoreCase: false,
 										want:       "\".\"",
 									},
 									&oneOrMoreExpr{
-										pos: position{line: 1207, col: 38, offset: 34448},
+										pos: position{line: 1278, col: 38, offset: 36788},
 										expr: &charClassMatcher{
-											pos:        position{line: 1207, col: 38, offset: 34448},
+											pos:        position{line: 1278, col: 38, offset: 36788},
 											val:        "[0-9]",
 											ranges:     []rune{'0', '9'},
 											ignoreCase: false,
@@ -4788,26 +5071,26 @@ var g = &grammar{
 		},
 		{
 			name: "BoolLitNode",
-			pos:  position{line: 1227, col: 1, offset: 34816},
+			pos:  position{line: 1298, col: 1, offset: 37156},
 			expr: &choiceExpr{
-				pos: position{line: 1227, col: 16, offset: 34831},
+				pos: position{line: 1298, col: 16, offset: 37171},
 				alternatives: []any{
 					&actionExpr{
-						pos: position{line: 1227, col: 16, offset: 34831},
+						pos: position{line: 1298, col: 16, offset: 37171},
 						run: (*parser).callonBoolLitNode2,
 						expr: &seqExpr{
-							pos: position{line: 1227, col: 16, offset: 34831},
+							pos: position{line: 1298, col: 16, offset: 37171},
 							exprs: []any{
 								&litMatcher{
-									pos:        position{line: 1227, col: 16, offset: 34831},
+									pos:        position{line: 1298, col: 16, offset: 37171},
 									val:        "true",
 									ignoreCase: false,
 									want:       "\"true\"",
 								},
 								&notExpr{
-									pos: position{line: 1227, col: 23, offset: 34838},
+									pos: position{line: 1298, col: 23, offset: 37178},
 									expr: &ruleRefExpr{
-										pos:  position{line: 1227, col: 24, offset: 34839},
+										pos:  position{line: 1298, col: 24, offset: 37179},
 										name: "IdentChar",
 									},
 								},
@@ -4815,21 +5098,21 @@ var g = &grammar{
 						},
 					},
 					&actionExpr{
-						pos: position{line: 1229, col: 5, offset: 34903},
+						pos: position{line: 1300, col: 5, offset: 37243},
 						run: (*parser).callonBoolLitNode7,
 						expr: &seqExpr{
-							pos: position{line: 1229, col: 5, offset: 34903},
+							pos: position{line: 1300, col: 5, offset: 37243},
 							exprs: []any{
 								&litMatcher{
-									pos:        position{line: 1229, col: 5, offset: 34903},
+									pos:        position{line: 1300, col: 5, offset: 37243},
 									val:        "false",
 									ignoreCase: false,
 									want:       "\"false\"",
 								},
 								&notExpr{
-									pos: position{line: 1229, col: 13, offset: 34911},
+									pos: position{line: 1300, col: 13, offset: 37251},
 									expr: &ruleRefExpr{
-										pos:  position{line: 1229, col: 14, offset: 34912},
+										pos:  position{line: 1300, col: 14, offset: 37252},
 										name: "IdentChar",
 									},
 								},
@@ -4841,23 +5124,23 @@ var g = &grammar{
 		},
 		{
 			name: "NullLitNode",
-			pos:  position{line: 1233, col: 1, offset: 34976},
+			pos:  position{line: 1304, col: 1, offset: 37316},
 			expr: &actionExpr{
-				pos: position{line: 1233, col: 16, offset: 34991},
+				pos: position{line: 1304, col: 16, offset: 37331},
 				run: (*parser).callonNullLitNode1,
 				expr: &seqExpr{
-					pos: position{line: 1233, col: 16, offset: 34991},
+					pos: position{line: 1304, col: 16, offset: 37331},
 					exprs: []any{
 						&litMatcher{
-							pos:        position{line: 1233, col: 16, offset: 34991},
+							pos:        position{line: 1304, col: 16, offset: 37331},
 							val:        "null",
 							ignoreCase: false,
 							want:       "\"null\"",
 						},
 						&notExpr{
-							pos: position{line: 1233, col: 23, offset: 34998},
+							pos: position{line: 1304, col: 23, offset: 37338},
 							expr: &ruleRefExpr{
-								pos:  position{line: 1233, col: 24, offset: 34999},
+								pos:  position{line: 1304, col: 24, offset: 37339},
 								name: "IdentChar",
 							},
 						},
@@ -4867,48 +5150,48 @@ var g = &grammar{
 		},
 		{
 			name: "IdentText",
-			pos:  position{line: 1242, col: 1, offset: 35420},
+			pos:  position{line: 1313, col: 1, offset: 37760},
 			expr: &actionExpr{
-				pos: position{line: 1242, col: 14, offset: 35433},
+				pos: position{line: 1313, col: 14, offset: 37773},
 				run: (*parser).callonIdentText1,
 				expr: &seqExpr{
-					pos: position{line: 1242, col: 16, offset: 35435},
+					pos: position{line: 1313, col: 16, offset: 37775},
 					exprs: []any{
 						&zeroOrOneExpr{
-							pos: position{line: 1242, col: 16, offset: 35435},
+							pos: position{line: 1313, col: 16, offset: 37775},
 							expr: &litMatcher{
-								pos:        position{line: 1242, col: 16, offset: 35435},
+								pos:        position{line: 1313, col: 16, offset: 37775},
 								val:        "@",
 								ignoreCase: false,
 								want:       "\"@\"",
 							},
 						},
 						&charClassMatcher{
-							pos:        position{line: 1242, col: 21, offset: 35440},
+							pos:        position{line: 1313, col: 21, offset: 37780},
 							val:        "[a-zA-Z]",
 							ranges:     []rune{'a', 'z', 'A', 'Z'},
 							ignoreCase: false,
 							inverted:   false,
 						},
 						&zeroOrMoreExpr{
-							pos: position{line: 1242, col: 30, offset: 35449},
+							pos: position{line: 1313, col: 30, offset: 37789},
 							expr: &choiceExpr{
-								pos: position{line: 1242, col: 32, offset: 35451},
+								pos: position{line: 1313, col: 32, offset: 37791},
 								alternatives: []any{
 									&seqExpr{
-										pos: position{line: 1242, col: 32, offset: 35451},
+										pos: position{line: 1313, col: 32, offset: 37791},
 										exprs: []any{
 											&oneOrMoreExpr{
-												pos: position{line: 1242, col: 32, offset: 35451},
+												pos: position{line: 1313, col: 32, offset: 37791},
 												expr: &litMatcher{
-													pos:        position{line: 1242, col: 32, offset: 35451},
+													pos:        position{line: 1313, col: 32, offset: 37791},
 													val:        "-",
 													ignoreCase: false,
 													want:       "\"-\"",
 												},
 											},
 											&charClassMatcher{
-												pos:        position{line: 1242, col: 37, offset: 35456},
+												pos:        position{line: 1313, col: 37, offset: 37796},
 												val:        "[a-zA-Z0-9]",
 												ranges:     []rune{'a', 'z', 'A', 'Z', '0', '9'},
 												ignoreCase: false,
@@ -4917,7 +5200,7 @@ var g = &grammar{
 										},
 									},
 									&charClassMatcher{
-										pos:        position{line: 1242, col: 51, offset: 35470},
+										pos:        position{line: 1313, col: 51, offset: 37810},
 										val:        "[a-zA-Z0-9]",
 										ranges:     []rune{'a', 'z', 'A', 'Z', '0', '9'},
 										ignoreCase: false,
@@ -4932,39 +5215,39 @@ var g = &grammar{
 		},
 		{
 			name: "SelectorIdentText",
-			pos:  position{line: 1246, col: 1, offset: 35520},
+			pos:  position{line: 1317, col: 1, offset: 37860},
 			expr: &actionExpr{
-				pos: position{line: 1246, col: 22, offset: 35541},
+				pos: position{line: 1317, col: 22, offset: 37881},
 				run: (*parser).callonSelectorIdentText1,
 				expr: &seqExpr{
-					pos: position{line: 1246, col: 24, offset: 35543},
+					pos: position{line: 1317, col: 24, offset: 37883},
 					exprs: []any{
 						&charClassMatcher{
-							pos:        position{line: 1246, col: 24, offset: 35543},
+							pos:        position{line: 1317, col: 24, offset: 37883},
 							val:        "[a-zA-Z]",
 							ranges:     []rune{'a', 'z', 'A', 'Z'},
 							ignoreCase: false,
 							inverted:   false,
 						},
 						&zeroOrMoreExpr{
-							pos: position{line: 1246, col: 33, offset: 35552},
+							pos: position{line: 1317, col: 33, offset: 37892},
 							expr: &choiceExpr{
-								pos: position{line: 1246, col: 35, offset: 35554},
+								pos: position{line: 1317, col: 35, offset: 37894},
 								alternatives: []any{
 									&seqExpr{
-										pos: position{line: 1246, col: 35, offset: 35554},
+										pos: position{line: 1317, col: 35, offset: 37894},
 										exprs: []any{
 											&oneOrMoreExpr{
-												pos: position{line: 1246, col: 35, offset: 35554},
+												pos: position{line: 1317, col: 35, offset: 37894},
 												expr: &litMatcher{
-													pos:        position{line: 1246, col: 35, offset: 35554},
+													pos:        position{line: 1317, col: 35, offset: 37894},
 													val:        "-",
 													ignoreCase: false,
 													want:       "\"-\"",
 												},
 											},
 											&charClassMatcher{
-												pos:        position{line: 1246, col: 40, offset: 35559},
+												pos:        position{line: 1317, col: 40, offset: 37899},
 												val:        "[a-zA-Z0-9]",
 												ranges:     []rune{'a', 'z', 'A', 'Z', '0', '9'},
 												ignoreCase: false,
@@ -4973,7 +5256,7 @@ var g = &grammar{
 										},
 									},
 									&charClassMatcher{
-										pos:        position{line: 1246, col: 54, offset: 35573},
+										pos:        position{line: 1317, col: 54, offset: 37913},
 										val:        "[a-zA-Z0-9]",
 										ranges:     []rune{'a', 'z', 'A', 'Z', '0', '9'},
 										ignoreCase: false,
@@ -4988,9 +5271,9 @@ var g = &grammar{
 		},
 		{
 			name: "IdentChar",
-			pos:  position{line: 1250, col: 1, offset: 35623},
+			pos:  position{line: 1321, col: 1, offset: 37963},
 			expr: &charClassMatcher{
-				pos:        position{line: 1250, col: 14, offset: 35636},
+				pos:        position{line: 1321, col: 14, offset: 37976},
 				val:        "[a-zA-Z0-9_-]",
 				chars:      []rune{'_', '-'},
 				ranges:     []rune{'a', 'z', 'A', 'Z', '0', '9'},
@@ -5000,21 +5283,21 @@ var g = &grammar{
 		},
 		{
 			name: "_",
-			pos:  position{line: 1253, col: 1, offset: 35652},
+			pos:  position{line: 1324, col: 1, offset: 37992},
 			expr: &zeroOrMoreExpr{
-				pos: position{line: 1253, col: 6, offset: 35657},
+				pos: position{line: 1324, col: 6, offset: 37997},
 				expr: &choiceExpr{
-					pos: position{line: 1253, col: 8, offset: 35659},
+					pos: position{line: 1324, col: 8, offset: 37999},
 					alternatives: []any{
 						&charClassMatcher{
-							pos:        position{line: 1253, col: 8, offset: 35659},
+							pos:        position{line: 1324, col: 8, offset: 37999},
 							val:        "[ \\t\\r\\n]",
 							chars:      []rune{' ', '\t', '\r', '\n'},
 							ignoreCase: false,
 							inverted:   false,
 						},
 						&ruleRefExpr{
-							pos:  position{line: 1253, col: 20, offset: 35671},
+							pos:  position{line: 1324, col: 20, offset: 38011},
 							name: "Comment",
 						},
 					},
@@ -5023,23 +5306,23 @@ var g = &grammar{
 		},
 		{
 			name: "Comment",
-			pos:  position{line: 1255, col: 1, offset: 35683},
+			pos:  position{line: 1326, col: 1, offset: 38023},
 			expr: &actionExpr{
-				pos: position{line: 1255, col: 12, offset: 35694},
+				pos: position{line: 1326, col: 12, offset: 38034},
 				run: (*parser).callonComment1,
 				expr: &seqExpr{
-					pos: position{line: 1255, col: 12, offset: 35694},
+					pos: position{line: 1326, col: 12, offset: 38034},
 					exprs: []any{
 						&litMatcher{
-							pos:        position{line: 1255, col: 12, offset: 35694},
+							pos:        position{line: 1326, col: 12, offset: 38034},
 							val:        "#",
 							ignoreCase: false,
 							want:       "\"#\"",
 						},
 						&zeroOrMoreExpr{
-							pos: position{line: 1255, col: 16, offset: 35698},
+							pos: position{line: 1326, col: 16, offset: 38038},
 							expr: &charClassMatcher{
-								pos:        position{line: 1255, col: 16, offset: 35698},
+								pos:        position{line: 1326, col: 16, offset: 38038},
 								val:        "[^\\r\\n]",
 								chars:      []rune{'\r', '\n'},
 								ignoreCase: false,
@@ -5052,11 +5335,11 @@ var g = &grammar{
 		},
 		{
 			name: "EOF",
-			pos:  position{line: 1260, col: 1, offset: 35761},
+			pos:  position{line: 1331, col: 1, offset: 38101},
 			expr: &notExpr{
-				pos: position{line: 1260, col: 8, offset: 35768},
+				pos: position{line: 1331, col: 8, offset: 38108},
 				expr: &anyMatcher{
-					line: 1260, col: 9, offset: 35769,
+					line: 1331, col: 9, offset: 38109,
 				},
 			},
 		},
@@ -5189,6 +5472,46 @@ func (p *parser) callonObjectType1() (any, error) {
 	return p.cur.onObjectType1(stack["fields"])
 }
 
+func (c *current) onUnionType1(tag, variants any) (any, error) {
+	out := &TypeUnion{S: c.span(), Tag: tag.(string), Variants: variants.([]*TypeUnionVariant)}
+	if len(out.Variants) < 2 {
+		return out, fmt.Errorf("union takes at least 2 variants, got %d", len(out.Variants))
+	}
+	return out, nil
+}
+
+func (p *parser) callonUnionType1() (any, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onUnionType1(stack["tag"], stack["variants"])
+}
+
+func (c *current) onTypeUnionBody1(variants any) (any, error) {
+	in := variants.([]any)
+	out := make([]*TypeUnionVariant, len(in))
+	for i, x := range in {
+		out[i] = x.(*TypeUnionVariant)
+	}
+	return out, nil
+}
+
+func (p *parser) callonTypeUnionBody1() (any, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onTypeUnionBody1(stack["variants"])
+}
+
+func (c *current) onTypeUnionVariant1(key, value any) (any, error) {
+	fk := key.(FieldKey)
+	return &TypeUnionVariant{S: fk.S, Name: fk.Name, Type: value.(TypeExpr)}, nil
+}
+
+func (p *parser) callonTypeUnionVariant1() (any, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onTypeUnionVariant1(stack["key"], stack["value"])
+}
+
 func (c *current) onLibraryConfigType1(args any) (any, error) {
 	span := c.span()
 	if args == nil {
@@ -5333,6 +5656,18 @@ func (p *parser) callonTypeKey1() (any, error) {
 	return p.cur.onTypeKey1()
 }
 
+func (c *current) onAtomicType5(name any) (bool, error) {
+	_, atomic := atomicTypeNames[name.(string)]
+	named, _ := c.globalStore["named-types"].(bool)
+	return atomic || !named, nil
+}
+
+func (p *parser) callonAtomicType5() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onAtomicType5(stack["name"])
+}
+
 func (c *current) onAtomicType1(name any) (any, error) {
 	span := c.span()
 	n := name.(string)
@@ -5353,6 +5688,47 @@ func (p *parser) callonAtomicType1() (any, error) {
 	return p.cur.onAtomicType1(stack["name"])
 }
 
+func (c *current) onNamedType3() (bool, error) {
+	named, _ := c.globalStore["named-types"].(bool)
+	return named, nil
+}
+
+func (p *parser) callonNamedType3() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onNamedType3()
+}
+
+func (c *current) onNamedType6(l any) (any, error) {
+	return l, nil
+}
+
+func (p *parser) callonNamedType6() (any, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onNamedType6(stack["l"])
+}
+
+func (c *current) onNamedType1(library, name any) (any, error) {
+	span := c.span()
+	n := name.(string)
+	if n == "any" {
+		return &TypeAtomic{S: span, Name: "invalid"}, fmt.Errorf(
+			"any is not a type; use opaque for a value passed along unread, or declare the value's type")
+	}
+	out := &TypeNamed{S: span, Name: n}
+	if library != nil {
+		out.Library = library.(string)
+	}
+	return out, nil
+}
+
+func (p *parser) callonNamedType1() (any, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onNamedType1(stack["library"], stack["name"])
+}
+
 func (c *current) onNamedSelectorBody1(key, sel, body any) (any, error) {
 	fk := key.(FieldKey)
 	block := body.(*ObjectLit)
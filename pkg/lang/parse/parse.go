@@ -53,6 +53,17 @@ func ParseType(path string, b []byte) (TypeExpr, error) {
 // ParseTypeAt parses b as a UB type expression whose first byte starts
 // at base in the source file.
 func ParseTypeAt(path string, b []byte, base Position) (TypeExpr, error) {
+	return parseTypeAt(path, b, base, false)
+}
+
+// ParseNamedTypeAt is ParseTypeAt for a type that may name declared
+// types: a name other than an atomic type parses as a TypeNamed, left
+// for the caller to bind.
+func ParseNamedTypeAt(path string, b []byte, base Position) (TypeExpr, error) {
+	return parseTypeAt(path, b, base, true)
+}
+
+func parseTypeAt(path string, b []byte, base Position, named bool) (TypeExpr, error) {
 	if base.File == "" {
 		base.File = path
 	}
@@ -61,6 +72,7 @@ func ParseTypeAt(path string, b []byte, base Position) (TypeExpr, error) {
 		GlobalStore("file", path),
 		GlobalStore("base", base),
 		GlobalStore("source", b),
+		GlobalStore("named-types", named),
 		Recover(false),
 	)
 	if err != nil {
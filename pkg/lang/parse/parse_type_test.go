@@ -22,6 +22,11 @@ func TestParseTypeConstructors(t *testing.T) {
 		{name: "optional", src: "optional(string)", want: &TypeOptional{}},
 		{name: "object", src: "object({ host: string port: integer })", want: &TypeObject{}},
 		{name: "open", src: "open(object({ tags: map(string) }))", want: &TypeObject{}},
+		{
+			name: "union",
+			src:  "union(kind, { git: object({ url: string }), s3: object({ bucket: string }) })",
+			want: &TypeUnion{},
+		},
 		{
 			name: "library config",
 			src:  "library-config('github.com/acme/aws')",
@@ -54,6 +59,23 @@ func TestParseTypeTupleElements(t *testing.T) {
 	assert.Equal(t, "integer", tuple.Elements[1].(*TypeAtomic).Name)
 }
 
+func TestParseTypeUnionVariants(t *testing.T) {
+	got, err := ParseType("type.ub", []byte(`union(kind, {
+  git: object({ url: string })
+  s3:  object({ bucket: string, key: string })
+})`))
+	require.NoError(t, err)
+	union := got.(*TypeUnion)
+	assert.Equal(t, "kind", union.Tag)
+	require.Len(t, union.Variants, 2)
+	assert.Equal(t, "git", union.Variants[0].Name)
+	assert.Equal(t, "s3", union.Variants[1].Name)
+	require.IsType(t, &TypeObject{}, union.Variants[1].Type)
+	variant, ok := union.Variant("s3")
+	require.True(t, ok)
+	assert.Same(t, union.Variants[1], variant)
+}
+
 func TestParseTypeObjectFields(t *testing.T) {
 	got, err := ParseType("type.ub", []byte(`object({
   host: string
@@ -104,3 +126,29 @@ func TestParseTypeInvalidFixtures(t *testing.T) {
 		return "", []string{err.Error()}
 	}, ubtest.Substring())
 }
+
+func TestParseNamedTypeAtReadsNames(t *testing.T) {
+	base := Position{File: "factory.ub", Line: 1, Column: 1}
+	got, err := ParseNamedTypeAt("factory.ub", []byte("list(endpoint)"), base)
+	require.NoError(t, err)
+	named := got.(*TypeList).Elem.(*TypeNamed)
+	assert.Equal(t, "", named.Library)
+	assert.Equal(t, "endpoint", named.Name)
+
+	got, err = ParseNamedTypeAt("factory.ub", []byte("optional(net.endpoint)"), base)
+	require.NoError(t, err)
+	named = got.(*TypeOptional).Elem.(*TypeNamed)
+	assert.Equal(t, "net.endpoint", named.QualifiedName())
+
+	got, err = ParseNamedTypeAt("factory.ub", []byte("integer"), base)
+	require.NoError(t, err)
+	assert.IsType(t, &TypeAtomic{}, got)
+
+	_, err = ParseNamedTypeAt("factory.ub", []byte("any"), base)
+	require.ErrorContains(t, err, "any is not a type")
+}
+
+func TestParseTypeRejectsNames(t *testing.T) {
+	_, err := ParseType("type.ub", []byte("endpoint"))
+	require.ErrorContains(t, err, `unknown atomic type "endpoint"`)
+}
@@ -0,0 +1,112 @@
+package parse
+
+import (
+	"errors"
+	"strings"
+)
+
+// ParseTemplate parses b as a template: plain text carrying the `{{ expr }}`
+// and `{{ expr : %verb }}` slots of an interpolated string. Text outside a
+// slot is taken as written; the only escape is `\{{`, which writes a
+// literal `{{`. Unlike a `$'...'` literal, slot expressions keep their
+// positions in path, so a diagnostic points at the template line and
+// column that produced it.
+func ParseTemplate(path string, b []byte) (*InterpolatedString, error) {
+	src := NewSourceFile(path, LineStarts(b))
+	s := string(b)
+	out := &InterpolatedString{S: src.Span(0, len(s))}
+	var lit strings.Builder
+	litStart := 0
+	flush := func(end int) {
+		if lit.Len() > 0 {
+			out.Parts = append(out.Parts, InterpolatedPart{S: src.Span(litStart, end), Lit: lit.String()})
+			lit.Reset()
+		}
+	}
+	for i := 0; i < len(s); {
+		if strings.HasPrefix(s[i:], `\{{`) {
+			if lit.Len() == 0 {
+				litStart = i
+			}
+			lit.WriteString("{{")
+			i += 3
+			continue
+		}
+		if !strings.HasPrefix(s[i:], "{{") {
+			if lit.Len() == 0 {
+				litStart = i
+			}
+			lit.WriteByte(s[i])
+			i++
+			continue
+		}
+		flush(i)
+		part, end, err := parseTemplateSlot(src, s, i)
+		if err != nil {
+			return nil, err
+		}
+		out.Parts = append(out.Parts, part)
+		i = end
+	}
+	flush(len(s))
+	return out, nil
+}
+
+// parseTemplateSlot parses the slot beginning at s[start] ("{{") and
+// returns it with the index just past its closing "}}".
+func parseTemplateSlot(src SourceFile, s string, start int) (InterpolatedPart, int, error) {
+	colon, close, err := scanInterpolatedSlot(s, start)
+	if err != nil {
+		return InterpolatedPart{}, 0, Errorf(ErrParse, src.Position(start), "%v", err)
+	}
+	exprText, verb, err := splitInterpolatedSlot(s, start+2, colon, close)
+	if err != nil {
+		return InterpolatedPart{}, 0, Errorf(ErrParse, src.Position(start), "%v", err)
+	}
+	exprStart := start + 2 + strings.Index(s[start+2:close], exprText)
+	expr, err := parseTemplateExpr(src, exprStart, exprText)
+	if err != nil {
+		return InterpolatedPart{}, 0, err
+	}
+	part := InterpolatedPart{S: src.Span(start, close+2), Expr: expr, Verb: verb}
+	return part, close + 2, nil
+}
+
+// parseTemplateExpr parses one slot expression through the same
+// synthetic single-field file as ParseExpr, rebased so node positions
+// and parse errors land where the expression sits in the template.
+func parseTemplateExpr(src SourceFile, offset int, text string) (Expr, error) {
+	const prefix = "x: "
+	base := src.Position(offset)
+	base.Column -= len(prefix)
+	base.Offset -= len(prefix)
+	wrapped := []byte(prefix + text + "\n")
+	v, err := Parse(src.File, wrapped,
+		Entrypoint("File"),
+		GlobalStore("file", src.File),
+		GlobalStore("base", base),
+		GlobalStore("source", wrapped),
+		Recover(false),
+	)
+	if err != nil {
+		return nil, templateExprError(err, base, src.Position(offset+len(text)))
+	}
+	return v.(*File).Body.Fields[0].Value, nil
+}
+
+// templateExprError converts a pigeon error from a slot expression into
+// an Error at the matching template position. A failure past the end of
+// the expression, where the synthetic file's trailing newline sits, is
+// reported at the end of the slot's expression.
+func templateExprError(err error, base, end Position) error {
+	var list errList
+	var pe *parserError
+	if !errors.As(err, &list) || len(list) == 0 || !errors.As(list[0], &pe) {
+		return err
+	}
+	pos := end
+	if pe.pos.line == 1 {
+		pos = rebasePosition(Position{Line: 1, Column: pe.pos.col, Offset: pe.pos.offset}, base)
+	}
+	return Errorf(ErrParse, pos, "%v", pe.Inner)
+}
@@ -0,0 +1,73 @@
+package parse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTemplateParts(t *testing.T) {
+	type want struct {
+		lit  string // literal text when this part is not a slot
+		slot string // position of the slot expression, when a slot
+		verb string
+	}
+	tests := []struct {
+		name  string
+		src   string
+		parts []want
+	}{
+		{"literal only", "server {\n}\n", []want{{lit: "server {\n}\n"}}},
+		{"empty", "", nil},
+		{"slot", "{{ port }}", []want{{slot: "t.conf:1:4"}}},
+		{"slot on a later line", "a\n  b {{ x }};\n", []want{
+			{lit: "a\n  b "}, {slot: "t.conf:2:8"}, {lit: ";\n"},
+		}},
+		{"verb", "{{ n : %04d }}", []want{{slot: "t.conf:1:4", verb: "%04d"}}},
+		{"escaped open brace", `\{{ x }} {{ y }}`, []want{{lit: "{{ x }} "}, {slot: "t.conf:1:13"}}},
+		{"other backslashes are literal", `a\nb \t`, []want{{lit: `a\nb \t`}}},
+		{"single brace is literal", "a{b}c", []want{{lit: "a{b}c"}}},
+		{"braces inside slot string", "{{ 'a}}b' }}", []want{{slot: "t.conf:1:4"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tpl, err := ParseTemplate("t.conf", []byte(tt.src))
+			require.NoError(t, err)
+			require.Len(t, tpl.Parts, len(tt.parts))
+			for i, w := range tt.parts {
+				got := tpl.Parts[i]
+				if w.slot != "" {
+					require.NotNil(t, got.Expr, "part %d should be a slot", i)
+					assert.Equal(t, w.slot, got.Expr.Span().Start.String(), "part %d position", i)
+					assert.Equal(t, w.verb, got.Verb, "part %d verb", i)
+				} else {
+					assert.Nil(t, got.Expr, "part %d should be a literal", i)
+					assert.Equal(t, w.lit, got.Lit, "part %d literal", i)
+				}
+			}
+		})
+	}
+}
+
+func TestParseTemplateErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"unterminated", "a\nb {{ x", "t.conf:2:3: parse: unterminated interpolation slot"},
+		{"multi-line slot", "{{ x\n}}", "t.conf:1:1: parse: interpolation slot must be on one line"},
+		{"empty slot", "a {{ }}", "t.conf:1:3: parse: empty interpolation slot"},
+		{"bad verb", "{{ x : 03d }}",
+			"t.conf:1:1: parse: interpolation directive must be a printf verb like %03d"},
+		{"bad expression", "ok\n  {{ a ) b }}", "t.conf:2:8: parse: no match found, expected: "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTemplate("t.conf", []byte(tt.src))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
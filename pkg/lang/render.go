@@ -63,13 +63,15 @@ func renderString(s string) string {
 // valid one, otherwise as a quoted string. Round trips cleanly through
 // the parser either way.
 func RenderKey(k string) string {
-	if isKebabIdent(k) {
+	if IsKebabIdent(k) {
 		return k
 	}
 	return renderString(k)
 }
 
-func isKebabIdent(s string) bool {
+// IsKebabIdent reports whether s is a bare kebab-case identifier,
+// optionally @-prefixed, that can be written without quotes.
+func IsKebabIdent(s string) bool {
 	if s == "" {
 		return false
 	}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/cloudboss/unobin/pkg/lang/parse"
@@ -51,6 +52,17 @@ func lowerFile(f *parse.File, mode lowerMode) (*File, *parse.ErrorList) {
 type lowerMode struct {
 	path   string
 	source []byte
+	types  *typeNames
+}
+
+// typeNames is what a type being lowered may name: the aliases its body
+// imports and the types its body declares. In a library file a bare
+// name may be declared by any of the library's files, so it is checked
+// when the files are merged instead.
+type typeNames struct {
+	declared map[string]bool
+	imports  map[string]bool
+	library  bool
 }
 
 type sourceFileRole struct {
@@ -68,17 +80,17 @@ func lowerSourceDeclaredFile(
 		return false
 	}
 	var roles []sourceFileRole
-	var selectorBody bool
+	var libraryBody bool
 	for _, fld := range f.Body.Fields {
-		if fld.Decl != nil {
-			selectorBody = true
+		if fld.Decl != nil || isFunctionsField(fld) || isTypesField(fld) {
+			libraryBody = true
 			continue
 		}
 		if fld.Key.Kind != parse.FieldIdent {
 			continue
 		}
 		switch fld.Key.Name {
-		case "factory", "stack", "project", "project-lock":
+		case "factory", "stack", "project", "project-lock", "tests":
 			roles = append(roles, sourceFileRole{name: fld.Key.Name, fld: fld})
 		}
 	}
@@ -108,7 +120,7 @@ func lowerSourceDeclaredFile(
 		lowerSourceDeclaredRole(f, out, roles, errs, mode)
 		return true
 	}
-	if selectorBody {
+	if libraryBody {
 		out.Kind = FileLibrary
 		out.Library = lowerLibraryFile(f, errs, mode)
 		return true
@@ -149,9 +161,11 @@ func reservedSourceFileRole(path string) (string, bool) {
 		return "project", true
 	case "project-lock.ub":
 		return "project-lock", true
-	default:
-		return "", false
 	}
+	if strings.HasSuffix(filepath.Base(path), "_test.ub") {
+		return "tests", true
+	}
+	return "", false
 }
 
 func sourceRoleFilename(role string) (string, bool) {
@@ -162,6 +176,8 @@ func sourceRoleFilename(role string) (string, bool) {
 		return "project.ub", true
 	case "project-lock":
 		return "project-lock.ub", true
+	case "tests":
+		return "a *_test.ub file", true
 	default:
 		return "", false
 	}
@@ -207,6 +223,9 @@ func lowerSourceDeclaredRole(
 	case "project-lock":
 		out.Kind = FileProjectLock
 		out.ProjectLock = lowerProjectLockFile(first.fld.S, block, errs)
+	case "tests":
+		out.Kind = FileTest
+		out.Test = lowerTestFile(first.fld.S, block, errs)
 	}
 }
 
@@ -220,6 +239,7 @@ func lowerFactoryBodyWithMode(
 		return body
 	}
 	body.S = block.S
+	mode.types = bodyTypeNames(block, mode.types)
 	for _, fld := range block.Fields {
 		name, ok := fieldName(fld, "factory field", errs)
 		if !ok {
@@ -232,6 +252,10 @@ func lowerFactoryBodyWithMode(
 			if obj := objectValue(fld, "assets", errs); obj != nil {
 				body.Assets = lowerAssets(obj, errs)
 			}
+		case "types":
+			if obj := objectValue(fld, "types", errs); obj != nil {
+				body.Types = lowerTypeDecls(obj, errs, mode)
+			}
 		case "inputs":
 			if obj := objectValue(fld, "inputs", errs); obj != nil {
 				body.Inputs = lowerInputs(obj, errs, mode)
@@ -244,6 +268,10 @@ func lowerFactoryBodyWithMode(
 			if arr := arrayValue(fld, "constraints", errs); arr != nil {
 				body.Constraints = lowerConstraints(arr)
 			}
+		case "checks":
+			if arr := arrayValue(fld, "checks", errs); arr != nil {
+				body.Checks = lowerChecks(arr)
+			}
 		case "imports":
 			if obj := objectValue(fld, "imports", errs); obj != nil {
 				body.Imports = lowerImports(obj, errs)
@@ -277,9 +305,46 @@ func lowerFactoryBodyWithMode(
 				"%q is not a valid factory field", name.Name)
 		}
 	}
+	if !mode.types.library {
+		NewTypeScope(nil, body.Types).Bind(BodyTypes(body), errs)
+	}
 	return body
 }
 
+// bodyTypeNames returns the names a factory or composite body's types
+// may use: the types it declares and the aliases it imports, gathered
+// up front since either block may follow the inputs that use them.
+func bodyTypeNames(block *parse.ObjectLit, outer *typeNames) *typeNames {
+	names := &typeNames{declared: map[string]bool{}, imports: map[string]bool{}}
+	if outer != nil {
+		names.library = outer.library
+	}
+	for _, fld := range block.Fields {
+		if fld.Key.Kind != parse.FieldIdent {
+			continue
+		}
+		obj, ok := fld.Value.(*parse.ObjectLit)
+		if !ok {
+			continue
+		}
+		var into map[string]bool
+		switch fld.Key.Name {
+		case "types":
+			into = names.declared
+		case "imports":
+			into = names.imports
+		default:
+			continue
+		}
+		for _, entry := range obj.Fields {
+			if entry.Key.Kind == parse.FieldIdent {
+				into[entry.Key.Name] = true
+			}
+		}
+	}
+	return names
+}
+
 func lowerStackFile(
 	span parse.Span,
 	block *parse.ObjectLit,
@@ -378,6 +443,10 @@ func lowerProjectFile(
 			if obj := objectValue(fld, "replace", errs); obj != nil {
 				project.Replace = lowerProjectReplace(obj, errs)
 			}
+		case "lint":
+			if obj := objectValue(fld, "lint", errs); obj != nil {
+				project.Lint = lowerProjectLint(obj, errs)
+			}
 		default:
 			errs.Addf(parse.ErrSchema, fld.Key.S.Start,
 				"%q is not a valid project field", name.Name)
@@ -386,6 +455,120 @@ func lowerProjectFile(
 	return project
 }
 
+func lowerTestFile(span parse.Span, block *parse.ObjectLit, errs *parse.ErrorList) *TestFile {
+	file := &TestFile{S: span}
+	if block == nil {
+		return file
+	}
+	for _, fld := range block.Fields {
+		name, ok := stringKey(fld, "test name", errs)
+		if !ok {
+			continue
+		}
+		if body := objectValue(fld, "test "+name.Value, errs); body != nil {
+			file.Cases = append(file.Cases, lowerTestCase(fld.S, name, body, errs))
+		}
+	}
+	return file
+}
+
+func lowerTestCase(
+	span parse.Span,
+	name StringKey,
+	block *parse.ObjectLit,
+	errs *parse.ErrorList,
+) TestCase {
+	tc := TestCase{S: span, Name: name}
+	for _, fld := range block.Fields {
+		field, ok := fieldName(fld, "test field", errs)
+		if !ok {
+			continue
+		}
+		switch field.Name {
+		case "inputs":
+			tc.Inputs = objectValue(fld, "test inputs", errs)
+		case "mocks":
+			if obj := objectValue(fld, "test mocks", errs); obj != nil {
+				tc.Mocks = lowerTestMocks(obj, errs)
+			}
+		case "expect":
+			if obj := objectValue(fld, "test expect", errs); obj != nil {
+				tc.Expect = lowerTestExpect(fld.S, obj, errs)
+			}
+		default:
+			errs.Addf(parse.ErrSchema, fld.Key.S.Start,
+				"%q is not a valid test field", field.Name)
+		}
+	}
+	return tc
+}
+
+func lowerTestMocks(block *parse.ObjectLit, errs *parse.ErrorList) []TestMock {
+	mocks := make([]TestMock, 0, len(block.Fields))
+	for _, fld := range block.Fields {
+		target, ok := stringKey(fld, "mock target", errs)
+		if !ok {
+			continue
+		}
+		outputs := objectValue(fld, "mock "+target.Value, errs)
+		if outputs == nil {
+			continue
+		}
+		mocks = append(mocks, TestMock{S: fld.S, Target: target, Outputs: outputs})
+	}
+	return mocks
+}
+
+func lowerTestExpect(span parse.Span, block *parse.ObjectLit, errs *parse.ErrorList) *TestExpect {
+	expect := &TestExpect{S: span}
+	for _, fld := range block.Fields {
+		name, ok := fieldName(fld, "expect field", errs)
+		if !ok {
+			continue
+		}
+		switch name.Name {
+		case "decisions":
+			obj := objectValue(fld, "expect decisions", errs)
+			if obj == nil {
+				continue
+			}
+			for _, entry := range obj.Fields {
+				addr, ok := stringKey(entry, "step address", errs)
+				if !ok {
+					continue
+				}
+				if decision := stringValue(entry, "decision for "+addr.Value, errs); decision != nil {
+					expect.Decisions = append(expect.Decisions,
+						TestDecision{S: entry.S, Address: addr, Decision: decision})
+				}
+			}
+		case "inputs":
+			obj := objectValue(fld, "expect inputs", errs)
+			if obj == nil {
+				continue
+			}
+			for _, entry := range obj.Fields {
+				addr, ok := stringKey(entry, "step address", errs)
+				if !ok {
+					continue
+				}
+				if inputs := objectValue(entry, "inputs for "+addr.Value, errs); inputs != nil {
+					expect.Inputs = append(expect.Inputs,
+						TestStepInputs{S: entry.S, Address: addr, Inputs: inputs})
+				}
+			}
+		case "outputs":
+			expect.Outputs = objectValue(fld, "expect outputs", errs)
+		case "error":
+			expect.Error = stringValue(fld, "expect error", errs)
+		default:
+			errs.Addf(parse.ErrSchema, fld.Key.S.Start,
+				"%q is not a valid expect field", name.Name)
+		}
+	}
+	return expect
+}
+
 func lowerProjectLockFile(span parse.Span, block *parse.ObjectLit, errs *parse.ErrorList) *ProjectLockFile {
 	projectLock := &ProjectLockFile{S: span}
 	if block == nil {
@@ -466,14 +649,51 @@ func lowerProjectLockToolchain(
 
 func lowerLibraryFile(f *parse.File, errs *parse.ErrorList, mode lowerMode) *LibraryFile {
 	library := &LibraryFile{S: f.S}
-	if hasSelectorBody(f.Body) {
-		library.Exports = lowerCompositeDecls(f.Body, errs, mode)
+	if !hasSelectorBody(f.Body) && !hasLibraryField(f.Body) {
+		errs.Addf(parse.ErrSchema, f.S.Start,
+			"library file must contain composite, function, or type declarations")
 		return library
 	}
-	errs.Addf(parse.ErrSchema, f.S.Start, "library file must contain composite declarations")
+	mode.types = &typeNames{library: true}
+	library.Exports = lowerCompositeDecls(f.Body, errs, mode)
+	for _, fld := range f.Body.Fields {
+		switch {
+		case isFunctionsField(fld):
+			if obj := objectValue(fld, "functions", errs); obj != nil {
+				library.Functions = lowerFunctions(obj, errs, mode)
+			}
+		case isTypesField(fld):
+			if obj := objectValue(fld, "types", errs); obj != nil {
+				library.Types = lowerTypeDecls(obj, errs, mode)
+			}
+		}
+	}
 	return library
 }
 
+// isFunctionsField reports whether fld is a library file's top-level
+// `functions:` block, a plain field a library file may hold beside its
+// composite declarations.
+func isFunctionsField(fld *parse.Field) bool {
+	return fld.Decl == nil && fld.Key.Kind == parse.FieldIdent && fld.Key.Name == "functions"
+}
+
+// isTypesField reports whether fld is a library file's top-level
+// `types:` block, the types the library shares across its files and
+// exports to importers.
+func isTypesField(fld *parse.Field) bool {
+	return fld.Decl == nil && fld.Key.Kind == parse.FieldIdent && fld.Key.Name == "types"
+}
+
+func hasLibraryField(block *parse.ObjectLit) bool {
+	if block == nil {
+		return false
+	}
+	return slices.ContainsFunc(block.Fields, func(fld *parse.Field) bool {
+		return isFunctionsField(fld) || isTypesField(fld)
+	})
+}
+
 func hasSelectorBody(block *parse.ObjectLit) bool {
 	if block == nil {
 		return false
@@ -494,6 +714,9 @@ func lowerCompositeDecls(
 	exports := make([]CompositeDecl, 0, len(block.Fields))
 	seen := make(map[string]parse.Position, len(block.Fields))
 	for _, fld := range block.Fields {
+		if isFunctionsField(fld) || isTypesField(fld) {
+			continue
+		}
 		if fld.Decl == nil {
 			errs.Addf(parse.ErrSchema, fld.Key.S.Start,
 				"library export must be written as name: resource { ... }")
@@ -548,6 +771,168 @@ func compositeKind(sel parse.Selector, errs *parse.ErrorList) (NodeKind, bool) {
 	}
 }
 
+func lowerFunctions(
+	block *parse.ObjectLit,
+	errs *parse.ErrorList,
+	mode lowerMode,
+) []FunctionDecl {
+	functions := make([]FunctionDecl, 0, len(block.Fields))
+	for _, fld := range block.Fields {
+		name, ok := fieldName(fld, "function name", errs)
+		if !ok {
+			continue
+		}
+		body := objectValue(fld, "function "+name.Name, errs)
+		if body == nil {
+			continue
+		}
+		functions = append(functions, lowerFunction(fld.S, name, body, errs, mode))
+	}
+	return functions
+}
+
+func lowerFunction(
+	span parse.Span,
+	name Ident,
+	block *parse.ObjectLit,
+	errs *parse.ErrorList,
+	mode lowerMode,
+) FunctionDecl {
+	fn := FunctionDecl{S: span, Name: name}
+	label := fmt.Sprintf("function %q", name.Name)
+	for i, fld := range block.Fields {
+		key, ok := fieldName(fld, "function field", errs)
+		if !ok {
+			continue
+		}
+		switch key.Name {
+		case "description":
+			fn.Description = stringValue(fld, "description", errs)
+		case "params":
+			if obj := objectValue(fld, "params", errs); obj != nil {
+				fn.Params = lowerFunctionParams(label, obj, errs, mode)
+			}
+		case "returns":
+			fn.Returns = lowerTypeField(label+" returns", block, i, errs, mode)
+		case "body":
+			fn.Body = fld.Value
+		default:
+			errs.Addf(parse.ErrSchema, fld.Key.S.Start,
+				"%s: %q is not a valid function field", label, key.Name)
+		}
+	}
+	if fn.Returns == nil && !hasField(block, "returns") {
+		errs.Addf(parse.ErrSchema, block.S.Start,
+			"%s: missing required `returns:` key", label)
+	}
+	if fn.Body == nil {
+		errs.Addf(parse.ErrSchema, block.S.Start,
+			"%s: missing required `body:` key", label)
+	}
+	return fn
+}
+
+func lowerFunctionParams(
+	label string,
+	block *parse.ObjectLit,
+	errs *parse.ErrorList,
+	mode lowerMode,
+) []FunctionParam {
+	params := make([]FunctionParam, 0, len(block.Fields))
+	for i, fld := range block.Fields {
+		name, ok := fieldName(fld, "parameter name", errs)
+		if !ok {
+			continue
+		}
+		t := lowerTypeField(
+			fmt.Sprintf("%s parameter %q", label, name.Name), block, i, errs, mode)
+		params = append(params, FunctionParam{S: fld.S, Name: name, Type: t})
+	}
+	return params
+}
+
+// lowerTypeField parses block's field at idx as a type expression and
+// stores it back on the field, the way an input's `type:` is lowered.
+// label names the declaration in a diagnostic.
+func lowerTypeField(
+	label string,
+	block *parse.ObjectLit,
+	idx int,
+	errs *parse.ErrorList,
+	mode lowerMode,
+) parse.TypeExpr {
+	fld := block.Fields[idx]
+	t, err := parseTypeValue(label, block, idx, mode)
+	if err != nil {
+		var perr *parse.Error
+		if errors.As(err, &perr) {
+			errs.Add(perr)
+		} else {
+			errs.Addf(parse.ErrType, fld.Value.Span().Start, "%s: %v", label, err)
+		}
+		return nil
+	}
+	fld.Value = t
+	checkTypeNames(label, t, errs, mode)
+	storeNestedTypeFields(label, t, errs, mode)
+	return t
+}
+
+// lowerTypeDecls lowers a `types:` block. A declared name may not be an
+// atomic type or a type constructor, which it could never be told
+// apart from.
+func lowerTypeDecls(
+	block *parse.ObjectLit,
+	errs *parse.ErrorList,
+	mode lowerMode,
+) []TypeDecl {
+	decls := make([]TypeDecl, 0, len(block.Fields))
+	for i, fld := range block.Fields {
+		name, ok := fieldName(fld, "type name", errs)
+		if !ok {
+			continue
+		}
+		if reservedTypeNames[name.Name] {
+			errs.Addf(parse.ErrSchema, fld.Key.S.Start,
+				"type name %q is reserved by the type language", name.Name)
+			continue
+		}
+		t := lowerTypeField(fmt.Sprintf("type %q", name.Name), block, i, errs, mode)
+		if t == nil {
+			continue
+		}
+		decls = append(decls, TypeDecl{S: fld.S, Name: name, Type: t})
+	}
+	return decls
+}
+
+var reservedTypeNames = map[string]bool{
+	"string":         true,
+	"number":         true,
+	"integer":        true,
+	"boolean":        true,
+	"null":           true,
+	"opaque":         true,
+	"any":            true,
+	"list":           true,
+	"map":            true,
+	"tuple":          true,
+	"object":         true,
+	"open":           true,
+	"optional":       true,
+	"union":          true,
+	"library-config": true,
+}
+
+func hasField(block *parse.ObjectLit, name string) bool {
+	for _, fld := range block.Fields {
+		if fld.Key.Kind == parse.FieldIdent && fld.Key.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 func lowerInputs(
 	block *parse.ObjectLit,
 	errs *parse.ErrorList,
@@ -611,6 +996,7 @@ func lowerInputType(
 		}
 		fld.Value = t
 		out = t
+		checkTypeNames(fmt.Sprintf("input %q", name), t, errs, mode)
 		storeNestedTypeFields(name, t, errs, mode)
 	}
 	if !found {
@@ -625,16 +1011,29 @@ func parseInputTypeValue(
 	block *parse.ObjectLit,
 	idx int,
 	mode lowerMode,
+) (parse.TypeExpr, error) {
+	return parseTypeValue(fmt.Sprintf("input %q", name), block, idx, mode)
+}
+
+func parseTypeValue(
+	label string,
+	block *parse.ObjectLit,
+	idx int,
+	mode lowerMode,
 ) (parse.TypeExpr, error) {
 	fld := block.Fields[idx]
 	if t, ok := fld.Value.(parse.TypeExpr); ok {
 		return t, nil
 	}
 	if src, ok := fieldValueSource(block, idx, mode.source); ok {
-		t, err := parse.ParseTypeAt(mode.path, src, fld.Value.Span().Start)
+		parseType := parse.ParseTypeAt
+		if mode.types != nil {
+			parseType = parse.ParseNamedTypeAt
+		}
+		t, err := parseType(mode.path, src, fld.Value.Span().Start)
 		if err != nil {
 			return nil, parse.Errorf(parse.ErrType, fld.Value.Span().Start,
-				"input %q: %s", name, typeParseMessage(err))
+				"%s: %s", label, typeParseMessage(err))
 		}
 		return t, nil
 	}
@@ -705,6 +1104,10 @@ func storeNestedTypeFields(
 		for _, elem := range v.Elements {
 			storeNestedTypeFields(name, elem, errs, mode)
 		}
+	case *parse.TypeUnion:
+		for _, variant := range v.Variants {
+			storeNestedTypeFields(name, variant.Type, errs, mode)
+		}
 	case *parse.TypeObject:
 		for _, field := range v.Fields {
 			fieldName := name + "." + field.Name
@@ -719,6 +1122,35 @@ func storeNestedTypeFields(
 	}
 }
 
+// checkTypeNames reports each type name in t that cannot resolve: an
+// alias the body does not import, or a bare name the body does not
+// declare. In a library file only the alias is checked here. It runs
+// before storeNestedTypeFields lowers nested declarations, which check
+// their own names.
+func checkTypeNames(label string, t parse.TypeExpr, errs *parse.ErrorList, mode lowerMode) {
+	if mode.types == nil {
+		return
+	}
+	for _, named := range NamedTypes(t) {
+		checkTypeName(label, named, mode.types, errs)
+	}
+}
+
+func checkTypeName(label string, t *parse.TypeNamed, names *typeNames, errs *parse.ErrorList) {
+	if t.Library != "" {
+		if !names.imports[t.Library] {
+			errs.Addf(parse.ErrResolve, t.S.Start,
+				"%s: library %q is not imported (type %s)", label, t.Library, t.QualifiedName())
+		}
+		return
+	}
+	if names.library || names.declared[t.Name] {
+		return
+	}
+	errs.Addf(parse.ErrType, t.S.Start,
+		"%s: %s", label, unknownTypeMessage(t.Name, len(names.declared) > 0))
+}
+
 func lowerLocals(block *parse.ObjectLit, what string, errs *parse.ErrorList) []LocalDecl {
 	locals := make([]LocalDecl, 0, len(block.Fields))
 	for _, fld := range block.Fields {
@@ -742,6 +1174,17 @@ func lowerConstraints(arr *parse.ArrayLit) []ConstraintDecl {
 	return constraints
 }
 
+func lowerChecks(arr *parse.ArrayLit) []CheckDecl {
+	checks := make([]CheckDecl, 0, len(arr.Elements))
+	for _, elem := range arr.Elements {
+		checks = append(checks, CheckDecl{
+			S:     elem.Span(),
+			Value: elem,
+		})
+	}
+	return checks
+}
+
 func lowerAssets(block *parse.ObjectLit, errs *parse.ErrorList) []AssetDecl {
 	assets := make([]AssetDecl, 0, len(block.Fields))
 	for _, fld := range block.Fields {
@@ -1075,6 +1518,10 @@ func lowerProjectRequire(
 			require.Version = stringValue(fld, "require "+id.Value+": version", errs)
 		case "indirect":
 			require.Indirect = boolValue(fld, "require "+id.Value+": indirect", errs)
+		case "allow-prerelease":
+			require.AllowPrerelease = boolValue(fld, "require "+id.Value+": allow-prerelease", errs)
+		case "exclude":
+			require.Exclude = arrayValue(fld, "require "+id.Value+": exclude", errs)
 		default:
 			errs.Addf(parse.ErrSchema, fld.Key.S.Start,
 				"requires: dependency %q: %q is not a valid require field",
@@ -1107,6 +1554,25 @@ func lowerProjectReplace(
 	return replacements
 }
 
+func lowerProjectLint(
+	block *parse.ObjectLit,
+	errs *parse.ErrorList,
+) []ProjectLint {
+	rules := make([]ProjectLint, 0, len(block.Fields))
+	for _, fld := range block.Fields {
+		code, ok := fieldName(fld, "lint rule code", errs)
+		if !ok {
+			continue
+		}
+		severity := stringValue(fld, "lint "+code.Name, errs)
+		if severity == nil {
+			continue
+		}
+		rules = append(rules, ProjectLint{S: fld.S, Code: code, Severity: severity})
+	}
+	return rules
+}
+
 func lowerProjectLockDeps(block *parse.ObjectLit, errs *parse.ErrorList) []ProjectLockDep {
 	deps := make([]ProjectLockDep, 0, len(block.Fields))
 	seen := make(map[string]parse.Position, len(block.Fields))
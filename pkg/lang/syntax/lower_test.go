@@ -290,6 +290,52 @@ func TestLowerSourceDeclaredProjectLockFile(t *testing.T) {
 	assert.Equal(t, "sha256:789abc", got.ProjectLock.Deps[1].Hash.Value)
 }
 
+func TestLowerSourceDeclaredTestFile(t *testing.T) {
+	f := parseFile(t, "web_test.ub", lowerFixture(t, "source-tests"), parse.FileUnknown)
+
+	got, errs := LowerFile(f)
+	require.Equal(t, 0, errs.Len(), errs.Error())
+	require.Equal(t, FileTest, got.Kind)
+	require.NotNil(t, got.Test)
+	requireSpan(t, got.Test.S)
+	require.Len(t, got.Test.Cases, 2)
+
+	first := got.Test.Cases[0]
+	assert.Equal(t, "names the instance", first.Name.Value)
+	require.NotNil(t, first.Inputs)
+	require.Len(t, first.Mocks, 2)
+	assert.Equal(t, "aws.instance", first.Mocks[0].Target.Value)
+	assert.Equal(t, "resource.web", first.Mocks[1].Target.Value)
+	require.NotNil(t, first.Expect)
+	require.Len(t, first.Expect.Decisions, 1)
+	assert.Equal(t, "resource.web", first.Expect.Decisions[0].Address.Value)
+	assert.Equal(t, "create", first.Expect.Decisions[0].Decision.Value)
+	require.Len(t, first.Expect.Inputs, 1)
+	assert.Equal(t, "resource.web", first.Expect.Inputs[0].Address.Value)
+	require.NotNil(t, first.Expect.Outputs)
+	assert.Nil(t, first.Expect.Error)
+
+	second := got.Test.Cases[1]
+	require.NotNil(t, second.Expect)
+	require.NotNil(t, second.Expect.Error)
+	assert.Equal(t, "name must not be empty", second.Expect.Error.Value)
+}
+
+func TestLowerReportsTestSchemaErrors(t *testing.T) {
+	f := parseFile(t, "web_test.ub", lowerInvalidFixture(t, "tests-schema-errors"), parse.FileUnknown)
+
+	_, errs := LowerFile(f)
+	require.NotEqual(t, 0, errs.Len())
+	got := errs.Error()
+	assert.Contains(t, got, "test name must be a quoted string")
+	assert.Contains(t, got, "test inputs must be an object")
+	assert.Contains(t, got, `"setup" is not a valid test field`)
+	assert.Contains(t, got, "mock aws.instance must be an object")
+	assert.Contains(t, got, "decision for resource.web must be a string literal")
+	assert.Contains(t, got, "inputs for resource.web must be an object")
+	assert.Contains(t, got, `"plan" is not a valid expect field`)
+}
+
 func TestLowerSourceDeclaredLibraryFile(t *testing.T) {
 	f := parseFile(t, "library.ub", lowerFixture(t, "source-library"), parse.FileUnknown)
 
@@ -305,6 +351,49 @@ func TestLowerSourceDeclaredLibraryFile(t *testing.T) {
 	assert.Equal(t, "lookup", got.Library.Exports[1].Name.Name)
 }
 
+func TestParseSourceLowersLibraryFunctions(t *testing.T) {
+	src := []byte(lowerFixture(t, "source-library-functions"))
+
+	got, err := ParseSource("library.ub", src)
+	require.NoError(t, err)
+	require.Equal(t, FileLibrary, got.Kind)
+	require.Len(t, got.Library.Exports, 1)
+	require.Len(t, got.Library.Functions, 3)
+
+	name := got.Library.Functions[0]
+	assert.Equal(t, "bucket-name", name.Name.Name)
+	require.NotNil(t, name.Description)
+	assert.Equal(t, "Name a bucket after its environment.", name.Description.Value)
+	require.Len(t, name.Params, 2)
+	assert.Equal(t, "env", name.Params[0].Name.Name)
+	assert.IsType(t, &parse.TypeAtomic{}, name.Params[0].Type)
+	assert.Equal(t, "suffix", name.Params[1].Name.Name)
+	assert.IsType(t, &parse.TypeAtomic{}, name.Returns)
+	assert.IsType(t, &parse.InterpolatedString{}, name.Body)
+
+	names := got.Library.Functions[1]
+	require.Len(t, names.Params, 2)
+	assert.IsType(t, &parse.TypeList{}, names.Params[0].Type)
+	assert.IsType(t, &parse.TypeOptional{}, names.Params[1].Type)
+	assert.IsType(t, &parse.Comprehension{}, names.Body)
+
+	zones := got.Library.Functions[2]
+	assert.Empty(t, zones.Params)
+	assert.IsType(t, &parse.TypeObject{}, zones.Returns)
+}
+
+func TestParseSourceReportsLibraryFunctionErrors(t *testing.T) {
+	src := []byte(lowerInvalidFixture(t, "library-function-errors"))
+
+	_, err := ParseSource("library.ub", src)
+	require.Error(t, err)
+	got := err.Error()
+	assert.Contains(t, got, `function "no-body": missing required `+"`body:`"+` key`)
+	assert.Contains(t, got, `function "no-returns": missing required `+"`returns:`"+` key`)
+	assert.Contains(t, got, `function "extra": "pure" is not a valid function field`)
+	assert.Contains(t, got, `function "bad-type" parameter "v": list takes exactly 1 type argument, got 2`)
+}
+
 func TestLowerRejectsDataBlock(t *testing.T) {
 	f := parseFile(t, "factory.ub", lowerInvalidFixture(t, "factory-data-block"), parse.FileUnknown)
 
@@ -532,6 +621,12 @@ func TestLowerReportsReservedFilenameMismatch(t *testing.T) {
 			fixture: "reserved-project-lock-with-project",
 			want:    "project-lock.ub must declare project-lock",
 		},
+		{
+			name:    "test file with factory declaration",
+			path:    "web_test.ub",
+			fixture: "reserved-test-file-with-factory",
+			want:    "web_test.ub must declare tests",
+		},
 		{
 			name:    "factory declaration outside factory file",
 			path:    "app.ub",
@@ -550,6 +645,12 @@ func TestLowerReportsReservedFilenameMismatch(t *testing.T) {
 			fixture: "reserved-project-lock-outside-project-lock",
 			want:    "project-lock declaration must be in project-lock.ub",
 		},
+		{
+			name:    "tests declaration outside test file",
+			path:    "app.ub",
+			fixture: "reserved-tests-outside-test-file",
+			want:    "tests declaration must be in a *_test.ub file",
+		},
 	}
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
@@ -629,3 +730,59 @@ func TestLowerReportsProjectLockToolchainSchemaErrors(t *testing.T) {
 	require.NotEqual(t, 0, errs.Len())
 	assert.Contains(t, errs.Error(), "project-lock toolchain: missing unobin-version")
 }
+
+func TestParseSourceBindsFactoryTypes(t *testing.T) {
+	src := []byte(lowerFixture(t, "source-factory-types"))
+
+	got, err := ParseSource("factory.ub", src)
+	require.NoError(t, err)
+	body := got.Factory.Body
+	require.Len(t, body.Types, 2)
+	assert.Equal(t, "endpoint", body.Types[0].Name.Name)
+
+	endpoints := body.Inputs[0].Type.(*parse.TypeList).Elem.(*parse.TypeNamed)
+	assert.Equal(t, "endpoint", endpoints.Name)
+	assert.Same(t, body.Types[0].Type, endpoints.Resolved)
+	port := body.Types[0].Type.(*parse.TypeObject).Fields[1].Type.(*parse.TypeNamed)
+	assert.Same(t, body.Types[1].Type, port.Resolved)
+
+	primary := body.Inputs[1].Type.(*parse.TypeNamed)
+	assert.Equal(t, "net.address", primary.QualifiedName())
+	assert.Nil(t, primary.Resolved, "imported types are bound by the import walk")
+}
+
+func TestParseSourceLowersLibraryTypes(t *testing.T) {
+	src := []byte(lowerFixture(t, "source-library-types"))
+
+	got, err := ParseSource("library.ub", src)
+	require.NoError(t, err)
+	require.Equal(t, FileLibrary, got.Kind)
+	require.Len(t, got.Library.Types, 1)
+	assert.Equal(t, "address", got.Library.Types[0].Name.Name)
+	param := got.Library.Functions[0].Params[0].Type.(*parse.TypeNamed)
+	assert.Nil(t, param.Resolved, "library names are bound when the library's files are merged")
+}
+
+func TestParseSourceReportsTypeDeclErrors(t *testing.T) {
+	src := []byte(lowerInvalidFixture(t, "factory-type-errors"))
+
+	_, err := ParseSource("factory.ub", src)
+	require.Error(t, err)
+	got := err.Error()
+	assert.Contains(t, got, `type "loop" refers to itself: loop -> loop`)
+	assert.Contains(t, got, `type "ping" refers to itself: ping -> pong -> ping`)
+	assert.Contains(t, got, `type name "string" is reserved by the type language`)
+	assert.Contains(t, got, `input "a": unknown type "endpont"`)
+	assert.Contains(t, got, `input "b": library "missing" is not imported (type missing.endpoint)`)
+}
+
+func TestParseSourceReportsUnionErrors(t *testing.T) {
+	src := []byte(lowerInvalidFixture(t, "factory-union-errors"))
+
+	_, err := ParseSource("factory.ub", src)
+	require.Error(t, err)
+	got := err.Error()
+	assert.Contains(t, got, `union variant "http" must be an object type`)
+	assert.Contains(t, got, `union variant "file" declares the tag field "kind"`)
+	assert.NotContains(t, got, `union variant "s3"`)
+}
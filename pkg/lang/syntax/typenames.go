@@ -0,0 +1,198 @@
+package syntax
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/cloudboss/unobin/pkg/lang"
+	"github.com/cloudboss/unobin/pkg/lang/parse"
+)
+
+// TypeScope is the set of types a declaration may name bare: a body's
+// own `types:` over those of the library that declares it.
+type TypeScope struct {
+	parent *TypeScope
+	decls  []TypeDecl
+}
+
+// NewTypeScope returns a scope holding decls inside parent. A name
+// decls declares hides the same name in parent.
+func NewTypeScope(parent *TypeScope, decls []TypeDecl) *TypeScope {
+	return &TypeScope{parent: parent, decls: decls}
+}
+
+// Lookup returns the declaration name refers to in s.
+func (s *TypeScope) Lookup(name string) (TypeDecl, bool) {
+	for scope := s; scope != nil; scope = scope.parent {
+		for _, decl := range scope.decls {
+			if decl.Name.Name == name {
+				return decl, true
+			}
+		}
+	}
+	return TypeDecl{}, false
+}
+
+// Bind sets Resolved on every bare type name in types and in s's own
+// declarations, then reports each declaration that reaches itself
+// through its names and unbinds the reference that closes the loop, so
+// nothing that follows Resolved runs forever. Names written as
+// alias.name are left for the import walk. Each union written in types
+// or the declarations is then checked against the variants it names.
+// Bind returns the bare references s does not declare, for the caller
+// to report.
+func (s *TypeScope) Bind(types []parse.TypeExpr, errs *parse.ErrorList) []*parse.TypeNamed {
+	var unbound []*parse.TypeNamed
+	all := slices.Clone(types)
+	for _, decl := range s.decls {
+		all = append(all, decl.Type)
+	}
+	for _, t := range all {
+		for _, ref := range NamedTypes(t) {
+			if ref.Library != "" {
+				continue
+			}
+			decl, ok := s.Lookup(ref.Name)
+			if !ok {
+				if !slices.Contains(unbound, ref) {
+					unbound = append(unbound, ref)
+				}
+				continue
+			}
+			ref.Resolved = decl.Type
+		}
+	}
+	s.checkCycles(errs)
+	for _, t := range all {
+		checkUnions(t, errs)
+	}
+	return unbound
+}
+
+// checkUnions reports a union in t whose variants cannot be told apart
+// by its tag: a variant that is not an object, or a variant object that
+// declares the tag field itself. A variant
+// named from a library the import walk has yet to bind is left alone.
+func checkUnions(t parse.TypeExpr, errs *parse.ErrorList) {
+	lang.Walk(t, func(e parse.Expr) {
+		union, ok := e.(*parse.TypeUnion)
+		if !ok {
+			return
+		}
+		for _, variant := range union.Variants {
+			resolved := parse.ResolveNamed(variant.Type)
+			if named, ok := resolved.(*parse.TypeNamed); ok && named.Resolved == nil {
+				continue
+			}
+			obj, ok := resolved.(*parse.TypeObject)
+			if !ok {
+				errs.Addf(parse.ErrType, variant.Type.Span().Start,
+					"union variant %q must be an object type", variant.Name)
+				continue
+			}
+			for _, field := range obj.Fields {
+				if field.Name == union.Tag {
+					errs.Addf(parse.ErrType, field.S.Start,
+						"union variant %q declares the tag field %q; the tag is implied by the variant name",
+						variant.Name, union.Tag)
+				}
+			}
+		}
+	})
+}
+
+// ReportUnboundTypes reports each reference in refs, bare names Bind
+// found no declaration for, as an unknown type.
+func ReportUnboundTypes(refs []*parse.TypeNamed, errs *parse.ErrorList) {
+	for _, ref := range refs {
+		errs.Addf(parse.ErrType, ref.S.Start, "%s", unknownTypeMessage(ref.Name, true))
+	}
+}
+
+// unknownTypeMessage describes a bare type name that names nothing.
+// declared tells whether any types could have been named, so a file
+// that declares none keeps the atomic-type wording.
+func unknownTypeMessage(name string, declared bool) string {
+	if !declared {
+		return fmt.Sprintf(
+			"unknown atomic type %q (expected string, number, integer, boolean, null, opaque)", name)
+	}
+	return fmt.Sprintf("unknown type %q (expected string, number, integer, boolean, null, opaque, "+
+		"or a name declared in types)", name)
+}
+
+// checkCycles reports a declaration of s that refers to itself, directly
+// or through other declarations of s. Each cycle is reported once, at
+// the reference where the walk closes the loop.
+func (s *TypeScope) checkCycles(errs *parse.ErrorList) {
+	own := make(map[string]TypeDecl, len(s.decls))
+	names := make([]string, 0, len(s.decls))
+	for _, decl := range s.decls {
+		own[decl.Name.Name] = decl
+		names = append(names, decl.Name.Name)
+	}
+	refs := func(name string) []*parse.TypeNamed {
+		var out []*parse.TypeNamed
+		for _, ref := range NamedTypes(own[name].Type) {
+			target, ok := own[ref.Name]
+			if ref.Library == "" && ok && ref.Resolved == target.Type {
+				out = append(out, ref)
+			}
+		}
+		return out
+	}
+	lang.FindCycles(names, refs,
+		func(ref *parse.TypeNamed) string { return ref.Name },
+		func(ref *parse.TypeNamed, cycle []string) {
+			errs.Addf(parse.ErrType, ref.S.Start,
+				"type %q refers to itself: %s", ref.Name, strings.Join(cycle, " -> "))
+			ref.Resolved = nil
+		})
+}
+
+// NamedTypes returns the named type references written in t, in source
+// order. It does not follow a reference into the type it names.
+func NamedTypes(t parse.TypeExpr) []*parse.TypeNamed {
+	if t == nil {
+		return nil
+	}
+	var out []*parse.TypeNamed
+	lang.Walk(t, func(e parse.Expr) {
+		if named, ok := e.(*parse.TypeNamed); ok {
+			out = append(out, named)
+		}
+	})
+	return out
+}
+
+// BodyTypes returns the type expressions a factory or composite body
+// writes: its `types:` declarations and its inputs' types.
+func BodyTypes(body FactoryBody) []parse.TypeExpr {
+	out := make([]parse.TypeExpr, 0, len(body.Types)+len(body.Inputs))
+	for _, decl := range body.Types {
+		out = append(out, decl.Type)
+	}
+	for _, input := range body.Inputs {
+		if input.Type != nil {
+			out = append(out, input.Type)
+		}
+	}
+	return out
+}
+
+// FunctionTypes returns the parameter and return types of decls.
+func FunctionTypes(decls []FunctionDecl) []parse.TypeExpr {
+	var out []parse.TypeExpr
+	for _, fn := range decls {
+		for _, p := range fn.Params {
+			if p.Type != nil {
+				out = append(out, p.Type)
+			}
+		}
+		if fn.Returns != nil {
+			out = append(out, fn.Returns)
+		}
+	}
+	return out
+}
@@ -14,6 +14,7 @@ const (
 	FileProject
 	FileProjectLock
 	FileLibrary
+	FileTest
 )
 
 type NodeKind string
@@ -51,6 +52,7 @@ type File struct {
 	Project     *ProjectFile
 	ProjectLock *ProjectLockFile
 	Library     *LibraryFile
+	Test        *TestFile
 	Comments    []parse.Comment
 }
 
@@ -73,6 +75,7 @@ type ProjectFile struct {
 	UnobinVersion *parse.StringLit
 	Requires      []ProjectRequire
 	Replace       []ProjectReplace
+	Lint          []ProjectLint
 }
 
 type ProjectLockFile struct {
@@ -87,18 +90,72 @@ type ProjectLockToolchain struct {
 	UnobinVersion *parse.StringLit
 }
 
-type LibraryFile struct {
+// TestFile is a `*_test.ub` file: named cases that run the factory
+// beside it against mocked libraries.
+type TestFile struct {
+	S     parse.Span
+	Cases []TestCase
+}
+
+// TestCase is one entry of a `tests:` block. Inputs are the stack
+// inputs the case runs with, Mocks the outputs its mocked library calls
+// return, and Expect what the plan and the simulated apply must show.
+type TestCase struct {
+	S      parse.Span
+	Name   StringKey
+	Inputs *parse.ObjectLit
+	Mocks  []TestMock
+	Expect *TestExpect
+}
+
+// TestMock gives the outputs of the library calls Target names: either
+// a node address such as `resource.web`, or an `<alias>.<type>` pair
+// that covers every node of that type.
+type TestMock struct {
 	S       parse.Span
-	Exports []CompositeDecl
+	Target  StringKey
+	Outputs *parse.ObjectLit
+}
+
+// TestExpect holds a test case's assertions. Decisions and Inputs are
+// keyed by plan step address; Outputs by factory output name. Error,
+// when set, expects the run to fail with a message containing it.
+type TestExpect struct {
+	S         parse.Span
+	Decisions []TestDecision
+	Inputs    []TestStepInputs
+	Outputs   *parse.ObjectLit
+	Error     *parse.StringLit
+}
+
+type TestDecision struct {
+	S        parse.Span
+	Address  StringKey
+	Decision *parse.StringLit
+}
+
+type TestStepInputs struct {
+	S       parse.Span
+	Address StringKey
+	Inputs  *parse.ObjectLit
+}
+
+type LibraryFile struct {
+	S         parse.Span
+	Types     []TypeDecl
+	Exports   []CompositeDecl
+	Functions []FunctionDecl
 }
 
 type FactoryBody struct {
 	S              parse.Span
 	Description    *parse.StringLit
 	Assets         []AssetDecl
+	Types          []TypeDecl
 	Inputs         []InputDecl
 	Locals         []LocalDecl
 	Constraints    []ConstraintDecl
+	Checks         []CheckDecl
 	Imports        []ImportDecl
 	LibraryConfigs []LibraryConfigDecl
 	StateMoves     []StateMoveDecl
@@ -120,6 +177,15 @@ type StackFactoryBlock struct {
 	Inputs *parse.ObjectLit
 }
 
+// TypeDecl is one entry of a `types:` block: a name for a type that
+// inputs, composite interfaces, function signatures, and other types
+// may use in its place.
+type TypeDecl struct {
+	S    parse.Span
+	Name Ident
+	Type parse.TypeExpr
+}
+
 type InputDecl struct {
 	S    parse.Span
 	Name Ident
@@ -138,6 +204,13 @@ type ConstraintDecl struct {
 	Value parse.Expr
 }
 
+// CheckDecl is one entry of a factory's `checks:` block, an assertion
+// the factory evaluates over its results after apply.
+type CheckDecl struct {
+	S     parse.Span
+	Value parse.Expr
+}
+
 type ImportDecl struct {
 	S     parse.Span
 	Alias Ident
@@ -200,11 +273,28 @@ type CompositeDecl struct {
 	Body FactoryBody
 }
 
+type FunctionDecl struct {
+	S           parse.Span
+	Name        Ident
+	Description *parse.StringLit
+	Params      []FunctionParam
+	Returns     parse.TypeExpr
+	Body        parse.Expr
+}
+
+type FunctionParam struct {
+	S    parse.Span
+	Name Ident
+	Type parse.TypeExpr
+}
+
 type ProjectRequire struct {
-	S        parse.Span
-	ID       StringKey
-	Version  *parse.StringLit
-	Indirect *parse.BoolLit
+	S               parse.Span
+	ID              StringKey
+	Version         *parse.StringLit
+	Indirect        *parse.BoolLit
+	AllowPrerelease *parse.BoolLit
+	Exclude         *parse.ArrayLit
 }
 
 type ProjectReplace struct {
@@ -213,6 +303,14 @@ type ProjectReplace struct {
 	Path *parse.StringLit
 }
 
+// ProjectLint is one entry of a project `lint:` block: a lint rule
+// code and the severity its findings report at.
+type ProjectLint struct {
+	S        parse.Span
+	Code     Ident
+	Severity *parse.StringLit
+}
+
 type ProjectLockDep struct {
 	S       parse.Span
 	ID      StringKey
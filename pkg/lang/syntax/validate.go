@@ -54,6 +54,8 @@ func ValidateFile(f *File) *parse.ErrorList {
 		validateProjectLockFile(f.ProjectLock, f.S.Start, errs)
 	case FileLibrary:
 		validateLibraryFile(f.Library, f.S.Start, errs)
+	case FileTest:
+		validateTestFile(f.Test, f.S.Start, errs)
 	default:
 		errs.Addf(parse.ErrSchema, f.S.Start,
 			"cannot validate UB syntax file: file kind is unknown")
@@ -66,12 +68,14 @@ func validateFactoryBody(body FactoryBody, errs *parse.ErrorList) {
 }
 
 func validateFactoryBodyTyped(body FactoryBody, errs *parse.ErrorList) {
+	validateTypeDecls(body.Types, errs)
 	inputs := inputDeclsObject(body.Inputs)
 	mergeErrors(errs, lang.ValidateInputDeclarations(inputs))
 	mergeErrors(errs, lang.ValidateLocals(localDeclsObject(body.Locals)))
 	constraints := constraintDeclsArray(body.Constraints)
 	mergeErrors(errs, lang.ValidateConstraints(constraints))
 	mergeErrors(errs, lang.ValidateConstraintReferences(constraints, inputs))
+	mergeErrors(errs, lang.ValidateChecks(checkDeclsArray(body.Checks)))
 	mergeErrors(errs, lang.ValidateImports(importDeclsObject(body.Imports)))
 	validateLibraryConfigTypePlacement(body.Inputs, errs)
 	validateLibraryConfigDecls(body.LibraryConfigs, errs)
@@ -184,6 +188,7 @@ func validateProjectFile(project *ProjectFile, pos parse.Position, errs *parse.E
 	}
 	mergeErrors(errs, lang.ValidateProjectRequires(projectRequiresObject(project.Requires)))
 	mergeErrors(errs, lang.ValidateProjectReplace(projectReplaceObject(project.Replace)))
+	mergeErrors(errs, lang.ValidateProjectLint(projectLintObject(project.Lint)))
 }
 
 func validateProjectLockFile(projectLock *ProjectLockFile, pos parse.Position, errs *parse.ErrorList) {
@@ -208,6 +213,44 @@ func validateProjectLockFile(projectLock *ProjectLockFile, pos parse.Position, e
 	}
 }
 
+func validateTestFile(file *TestFile, pos parse.Position, errs *parse.ErrorList) {
+	if file == nil {
+		errs.Addf(parse.ErrSchema, pos, "test file is missing tests body")
+		return
+	}
+	if len(file.Cases) == 0 {
+		errs.Addf(parse.ErrSchema, file.S.Start, "tests must declare at least one test")
+	}
+	for _, tc := range file.Cases {
+		for _, mock := range tc.Mocks {
+			if !validTestMockTarget(mock.Target.Value) {
+				errs.Addf(parse.ErrSchema, mock.Target.S.Start,
+					"test %q: mock target %q must be a node address or <alias>.<type>",
+					tc.Name.Value, mock.Target.Value)
+			}
+		}
+		if tc.Expect == nil {
+			continue
+		}
+		if tc.Expect.Error != nil && tc.Expect.Outputs != nil {
+			errs.Addf(parse.ErrSchema, tc.Expect.S.Start,
+				"test %q: expect must not declare both error and outputs", tc.Name.Value)
+		}
+	}
+}
+
+// validTestMockTarget reports whether target names a node address,
+// which starts with its kind, or an <alias>.<type> pair.
+func validTestMockTarget(target string) bool {
+	for _, kind := range []string{"resource.", "data-source.", "action."} {
+		if rest, ok := strings.CutPrefix(target, kind); ok {
+			return rest != ""
+		}
+	}
+	alias, typ, ok := strings.Cut(target, ".")
+	return ok && lang.IsKebabIdent(alias) && lang.IsKebabIdent(typ)
+}
+
 func hasHashAlgorithm(hash string) bool {
 	for i, r := range hash {
 		if r == ':' {
@@ -231,8 +274,122 @@ func validateLibraryFile(library *LibraryFile, pos parse.Position, errs *parse.E
 			continue
 		}
 		seen[key] = export.Name.S.Start
+		if len(export.Body.Checks) > 0 {
+			errs.Addf(parse.ErrSchema, export.Body.Checks[0].S.Start,
+				"library export %s: checks are only valid in a factory", key)
+		}
 		validateFactoryBody(export.Body, errs)
 	}
+	validateTypeDecls(library.Types, errs)
+	validateFunctionDecls(library.Functions, errs)
+}
+
+// validateTypeDecls validates the declarations nested in each declared
+// type. library-config names one library's configuration and is only
+// valid as an input's own type, so a declared type may not hold it.
+func validateTypeDecls(decls []TypeDecl, errs *parse.ErrorList) {
+	for _, decl := range decls {
+		mergeErrors(errs, lang.ValidateTypeDeclaration(decl.Name.Name, decl.Type))
+		lang.Walk(decl.Type, func(e parse.Expr) {
+			if cfg, ok := e.(*parse.TypeLibraryConfig); ok {
+				errs.Addf(parse.ErrSchema, cfg.S.Start,
+					"type %q: library-config is only valid as the direct input type",
+					decl.Name.Name)
+			}
+		})
+	}
+}
+
+func validateFunctionDecls(decls []FunctionDecl, errs *parse.ErrorList) {
+	for _, fn := range decls {
+		validateFunctionDecl(fn, errs)
+	}
+}
+
+// validateFunctionDecl checks that a function body refers only to its
+// own parameters and comprehension bindings and calls only @core or a
+// sibling function. A function is pure: it sees nothing of the factory
+// that calls it.
+func validateFunctionDecl(fn FunctionDecl, errs *parse.ErrorList) {
+	params := make(map[string]parse.Position, len(fn.Params))
+	for _, p := range fn.Params {
+		params[p.Name.Name] = p.Name.S.Start
+	}
+	if fn.Body == nil {
+		return
+	}
+	checkFactoryComprehensionBindings(fn.Body, map[string]parse.Position{}, errs)
+	checkFunctionScope(fn.Name.Name, fn.Body, params, errs)
+}
+
+func checkFunctionScope(
+	fn string,
+	e parse.Expr,
+	bound map[string]parse.Position,
+	errs *parse.ErrorList,
+) {
+	checkName := func(id *parse.Ident) {
+		if id == nil {
+			return
+		}
+		if _, ok := bound[id.Name]; !ok {
+			errs.Addf(parse.ErrResolve, id.S.Start,
+				"function %q: %q is not a parameter; a function body reads"+
+					" only its parameters", fn, id.Name)
+		}
+	}
+	switch v := e.(type) {
+	case nil:
+		return
+	case *parse.Ident:
+		checkName(v)
+	case *parse.DotPath:
+		checkName(v.Root)
+		for _, seg := range v.Segments {
+			checkFunctionScope(fn, seg.Index, bound, errs)
+		}
+	case *parse.ObjectLit:
+		for _, fld := range v.Fields {
+			checkFunctionScope(fn, fld.Value, bound, errs)
+		}
+	case *parse.ArrayLit:
+		for _, el := range v.Elements {
+			checkFunctionScope(fn, el, bound, errs)
+		}
+	case *parse.Call:
+		if v.Library != nil && v.Library.Name != lang.CoreNamespace {
+			errs.Addf(parse.ErrResolve, v.Library.S.Start,
+				"function %q: %s.%s is not callable here; function bodies may"+
+					" call only %s functions and functions of the same library",
+				fn, v.Library.Name, v.Func.Name, lang.CoreNamespace)
+		}
+		for _, a := range v.Args {
+			checkFunctionScope(fn, a, bound, errs)
+		}
+	case *parse.Infix:
+		checkFunctionScope(fn, v.Left, bound, errs)
+		checkFunctionScope(fn, v.Right, bound, errs)
+	case *parse.Prefix:
+		checkFunctionScope(fn, v.Expr, bound, errs)
+	case *parse.Conditional:
+		checkFunctionScope(fn, v.Cond, bound, errs)
+		checkFunctionScope(fn, v.Then, bound, errs)
+		checkFunctionScope(fn, v.Else, bound, errs)
+	case *parse.Comprehension:
+		checkFunctionScope(fn, v.Source, bound, errs)
+		inner := make(map[string]parse.Position, len(bound)+len(v.Names))
+		maps.Copy(inner, bound)
+		for _, n := range v.Names {
+			inner[n] = v.S.Start
+		}
+		checkFunctionScope(fn, v.Key, inner, errs)
+		checkFunctionScope(fn, v.Value, inner, errs)
+		checkFunctionScope(fn, v.Filter, inner, errs)
+	case *parse.InterpolatedString:
+		for _, part := range v.Parts {
+			checkFunctionScope(fn, part.Expr, bound, errs)
+		}
+	}
 }
 
 func validateLibraryConfigTypePlacement(inputs []InputDecl, errs *parse.ErrorList) {
@@ -266,6 +423,10 @@ func validateNestedLibraryConfigTypes(
 		for _, elem := range v.Elements {
 			validateNestedLibraryConfigTypes(input, elem, errs)
 		}
+	case *parse.TypeUnion:
+		for _, variant := range v.Variants {
+			validateNestedLibraryConfigTypes(input, variant.Type, errs)
+		}
 	case *parse.TypeObject:
 		for _, field := range v.Fields {
 			if field.Type != nil {
@@ -615,9 +776,10 @@ func validateFactoryCalls(body FactoryBody, errs *parse.ErrorList) {
 				return
 			}
 			if !imports[c.Library.Name] {
-				errs.Addf(parse.ErrResolve, c.Library.S.Start,
+				errs.Add(parse.Errorf(parse.ErrResolve, c.Library.S.Start,
 					"library %q is not imported (called as %s.%s)",
-					c.Library.Name, c.Library.Name, c.Func.Name)
+					c.Library.Name, c.Library.Name, c.Func.Name,
+				).WithCode(parse.CodeUnknownImport, map[string]string{"alias": c.Library.Name}))
 			}
 		})
 	})
@@ -641,6 +803,9 @@ func factoryBodyExprs(body FactoryBody, visit func(parse.Expr)) {
 	for _, constraint := range body.Constraints {
 		visit(constraint.Value)
 	}
+	for _, check := range body.Checks {
+		visit(check.Value)
+	}
 	for _, cfg := range body.LibraryConfigs {
 		visit(cfg.Value)
 	}
@@ -691,6 +856,17 @@ func constraintDeclsArray(decls []ConstraintDecl) *parse.ArrayLit {
 	return arr
 }
 
+func checkDeclsArray(decls []CheckDecl) *parse.ArrayLit {
+	arr := &parse.ArrayLit{}
+	if len(decls) > 0 {
+		arr.S = decls[0].S
+	}
+	for _, decl := range decls {
+		arr.Elements = append(arr.Elements, decl.Value)
+	}
+	return arr
+}
+
 func importDeclsObject(decls []ImportDecl) *parse.ObjectLit {
 	obj := &parse.ObjectLit{}
 	if len(decls) > 0 {
@@ -728,6 +904,14 @@ func projectRequiresObject(decls []ProjectRequire) *parse.ObjectLit {
 			body.Fields = append(body.Fields,
 				identField("indirect", decl.Indirect.S, decl.Indirect))
 		}
+		if decl.AllowPrerelease != nil {
+			body.Fields = append(body.Fields,
+				identField("allow-prerelease", decl.AllowPrerelease.S, decl.AllowPrerelease))
+		}
+		if decl.Exclude != nil {
+			body.Fields = append(body.Fields,
+				identField("exclude", decl.Exclude.S, decl.Exclude))
+		}
 		obj.Fields = append(obj.Fields, stringField(decl.ID.Value, decl.ID.S, body))
 	}
 	return obj
@@ -744,6 +928,17 @@ func projectReplaceObject(decls []ProjectReplace) *parse.ObjectLit {
 	return obj
 }
 
+func projectLintObject(decls []ProjectLint) *parse.ObjectLit {
+	obj := &parse.ObjectLit{}
+	if len(decls) > 0 {
+		obj.S = decls[0].S
+	}
+	for _, decl := range decls {
+		obj.Fields = append(obj.Fields, identField(decl.Code.Name, decl.Code.S, decl.Severity))
+	}
+	return obj
+}
+
 func stackLocalNames(decls []LocalDecl) map[string]bool {
 	names := make(map[string]bool, len(decls))
 	for _, decl := range decls {
@@ -31,6 +31,8 @@ func validateFixturePath(name string) string {
 		return "project.ub"
 	case "stack":
 		return "dev.ub"
+	case "test":
+		return parts[len(parts)-1] + "_test.ub"
 	default:
 		return parts[len(parts)-1] + ".ub"
 	}
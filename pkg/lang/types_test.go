@@ -212,6 +212,22 @@ func TestParseTypeValidFixtures(t *testing.T) {
 				requireAtomic(t, retries.Type, "integer")
 			},
 		},
+		{
+			name:    "union",
+			fixture: "union",
+			check: func(t *testing.T, te TypeExpr) {
+				union, ok := te.(*TypeUnion)
+				require.True(t, ok, "got %T", te)
+				require.Equal(t, "kind", union.Tag)
+				require.Len(t, union.Variants, 2)
+				git, ok := union.Variant("git")
+				require.True(t, ok)
+				requireObjectField(t, requireObject(t, git.Type), 1, "ref")
+				s3, ok := union.Variant("s3")
+				require.True(t, ok)
+				require.Len(t, requireObject(t, s3.Type).Fields, 2)
+			},
+		},
 		{
 			name:    "deep nesting",
 			fixture: "deep",
@@ -199,9 +199,17 @@ func typeParseMessage(err error) string {
 	return out
 }
 
+// ValidateTypeDeclaration validates the object fields written as full
+// declarations inside t, the type a `types:` block declares as name.
+func ValidateTypeDeclaration(name string, t TypeExpr) *ErrorList {
+	errs := NewErrorList(0)
+	validateNestedDecls(name, t, errs)
+	return errs
+}
+
 // validateNestedDecls walks a parsed type for object fields written as
 // full declarations and validates each one, named by its dotted path
-// from the input.
+// from the input. A named type is validated where it is declared.
 func validateNestedDecls(name string, t TypeExpr, errs *ErrorList) {
 	switch v := t.(type) {
 	case *TypeList:
@@ -214,6 +222,10 @@ func validateNestedDecls(name string, t TypeExpr, errs *ErrorList) {
 		for _, e := range v.Elements {
 			validateNestedDecls(name, e, errs)
 		}
+	case *TypeUnion:
+		for _, variant := range v.Variants {
+			validateNestedDecls(name, variant.Type, errs)
+		}
 	case *TypeObject:
 		for _, f := range v.Fields {
 			if f.Decl != nil {
@@ -249,8 +261,8 @@ func checkDeclaredDefault(name string, decl *ObjectLit, t TypeExpr, errs *ErrorL
 	if !ok {
 		return
 	}
-	checkType := t
-	if opt, ok := t.(*TypeOptional); ok {
+	checkType := ResolveNamed(t)
+	if opt, ok := checkType.(*TypeOptional); ok {
 		if val == nil {
 			return
 		}
@@ -570,7 +582,7 @@ func validatePredicateConstraint(idx int, obj *ObjectLit, errs *ErrorList) {
 			}
 			seen[f.Key.Name] = f.Key.S.Start
 			if arr, ok := f.Value.(*ArrayLit); ok {
-				validateForEachChain(idx, arr, errs)
+				validateForEachChain(fmt.Sprintf("constraints[%d]", idx), arr, errs)
 			}
 			continue
 		}
@@ -602,11 +614,12 @@ func validatePredicateConstraint(idx int, obj *ObjectLit, errs *ErrorList) {
 }
 
 // validateForEachChain checks the chained @for-each form: one level
-// after another, each binding one fresh @-name to an iterable.
-func validateForEachChain(idx int, arr *ArrayLit, errs *ErrorList) {
+// after another, each binding one fresh @-name to an iterable. entry
+// names the constraint or check the chain belongs to.
+func validateForEachChain(entry string, arr *ArrayLit, errs *ErrorList) {
 	if len(arr.Elements) == 0 {
 		errs.Addf(ErrSchema, arr.S.Start,
-			"constraints[%d]: a chained @for-each needs at least one level", idx)
+			"%s: a chained @for-each needs at least one level", entry)
 		return
 	}
 	declared := make(map[string]Position, len(arr.Elements))
@@ -614,29 +627,29 @@ func validateForEachChain(idx int, arr *ArrayLit, errs *ErrorList) {
 		obj, ok := el.(*ObjectLit)
 		if !ok || len(obj.Fields) != 1 || obj.Fields[0].Key.Kind != FieldIdent {
 			errs.Addf(ErrSchema, el.Span().Start,
-				"constraints[%d]: a chain level binds one @-name to an iterable,"+
-					" like { @rule: input.rules }", idx)
+				"%s: a chain level binds one @-name to an iterable,"+
+					" like { @rule: input.rules }", entry)
 			continue
 		}
 		key := obj.Fields[0].Key
 		switch {
 		case !strings.HasPrefix(key.Name, "@"):
 			errs.Addf(ErrSchema, key.S.Start,
-				"constraints[%d]: a chain level's binding must be @-named, like @%s",
-				idx, key.Name)
+				"%s: a chain level's binding must be @-named, like @%s",
+				entry, key.Name)
 		case key.Name == "@each":
 			errs.Addf(ErrSchema, key.S.Start,
-				"constraints[%d]: @each is the bare form's binding; give this level"+
-					" its own name", idx)
+				"%s: @each is the bare form's binding; give this level"+
+					" its own name", entry)
 		case key.Name == CoreNamespace:
 			errs.Addf(ErrSchema, key.S.Start,
-				"constraints[%d]: %s is reserved; choose another binding name",
-				idx, CoreNamespace)
+				"%s: %s is reserved; choose another binding name",
+				entry, CoreNamespace)
 		default:
 			if prev, dup := declared[key.Name]; dup {
 				errs.Addf(ErrSchema, key.S.Start,
-					"constraints[%d]: duplicate binding %q (first defined at %s)",
-					idx, key.Name, prev)
+					"%s: duplicate binding %q (first defined at %s)",
+					entry, key.Name, prev)
 				continue
 			}
 			declared[key.Name] = key.S.Start
@@ -644,6 +657,68 @@ func validateForEachChain(idx int, arr *ArrayLit, errs *ErrorList) {
 	}
 }
 
+// ValidateChecks walks a `checks:` array. Each entry is an object with
+// a required `assert:` expression, an optional string-literal
+// `message:`, and an optional `@for-each` in the bare or chained form
+// a predicate constraint takes.
+func ValidateChecks(arr *ArrayLit) *ErrorList {
+	errs := NewErrorList(0)
+	for i, e := range arr.Elements {
+		validateCheck(i, e, errs)
+	}
+	return errs
+}
+
+func validateCheck(idx int, e Expr, errs *ErrorList) {
+	obj, ok := e.(*ObjectLit)
+	if !ok {
+		errs.Addf(ErrSchema, e.Span().Start,
+			"checks[%d]: entry must be an object, got %s", idx, exprKind(e))
+		return
+	}
+	var hasAssert bool
+	seen := make(map[string]Position, len(obj.Fields))
+	for _, f := range obj.Fields {
+		if f.Key.Kind == FieldString {
+			errs.Addf(ErrSchema, f.Key.S.Start,
+				"checks[%d]: key must be an identifier, got quoted string %q",
+				idx, f.Key.String)
+			continue
+		}
+		if prev, dup := seen[f.Key.Name]; dup {
+			errs.Addf(ErrSchema, f.Key.S.Start,
+				"checks[%d]: duplicate key %q (first defined at %s)", idx, f.Key.Name, prev)
+			continue
+		}
+		seen[f.Key.Name] = f.Key.S.Start
+		switch f.Key.Name {
+		case "@for-each":
+			if arr, ok := f.Value.(*ArrayLit); ok {
+				validateForEachChain(fmt.Sprintf("checks[%d]", idx), arr, errs)
+			}
+		case "assert":
+			hasAssert = true
+		case "message":
+			if _, ok := f.Value.(*StringLit); !ok {
+				errs.Addf(ErrSchema, f.Value.Span().Start,
+					"checks[%d]: `message:` must be a string literal", idx)
+			}
+		default:
+			if f.Key.IsMeta() {
+				errs.Addf(ErrSchema, f.Key.S.Start,
+					"checks[%d]: meta key %q not allowed", idx, f.Key.Name)
+				continue
+			}
+			errs.Addf(ErrSchema, f.Key.S.Start,
+				"checks[%d]: unknown key %q", idx, f.Key.Name)
+		}
+	}
+	if !hasAssert {
+		errs.Addf(ErrSchema, obj.S.Start,
+			"checks[%d]: missing required `assert:` expression", idx)
+	}
+}
+
 // ValidateOutputs checks an `outputs:` block. Every entry is a
 // bare identifier name bound to an object wrapper of the form
 // `{ value: expr }`, optionally carrying `description: '...'` and
@@ -870,9 +945,10 @@ func ValidateCalls(f *File) *ErrorList {
 			return
 		}
 		if _, declared := imports[c.Library.Name]; !declared {
-			errs.Addf(ErrResolve, c.Library.S.Start,
+			errs.Add(Errorf(ErrResolve, c.Library.S.Start,
 				"library %q is not imported (called as %s.%s)",
-				c.Library.Name, c.Library.Name, c.Func.Name)
+				c.Library.Name, c.Library.Name, c.Func.Name,
+			).WithCode(CodeUnknownImport, map[string]string{"alias": c.Library.Name}))
 		}
 	})
 	return errs
@@ -1380,9 +1456,10 @@ func ValidateImports(block *ObjectLit) *ErrorList {
 
 // ValidateProjectRequires checks a project `requires:` block: every
 // entry binds a quoted dependency id (a repo URL with an optional
-// `//subdir`) to an object with a quoted version floor and an optional
-// indirect flag. The id and version strings are not parsed here; resolution
-// validates the URL and the semver floor.
+// `//subdir`) to an object with a quoted version constraint, an optional
+// indirect flag, an optional allow-prerelease flag, and an optional array
+// of quoted versions to exclude. The id and version strings are not parsed
+// here; resolution validates the URL and the constraint.
 func ValidateProjectRequires(block *ObjectLit) *ErrorList {
 	errs := NewErrorList(0)
 	seen := make(map[string]Position, len(block.Fields))
@@ -1440,12 +1517,14 @@ func validateProjectRequireBody(errs *ErrorList, id string, body *ObjectLit) {
 					"requires: dependency %q: version must be a quoted string, got %s",
 					id, exprKind(fld.Value))
 			}
-		case "indirect":
+		case "indirect", "allow-prerelease":
 			if _, ok := fld.Value.(*BoolLit); !ok {
 				errs.Addf(ErrSchema, fld.Value.Span().Start,
-					"requires: dependency %q: indirect must be a boolean, got %s",
-					id, exprKind(fld.Value))
+					"requires: dependency %q: %s must be a boolean, got %s",
+					id, name, exprKind(fld.Value))
 			}
+		case "exclude":
+			validateProjectRequireExclude(errs, id, fld.Value)
 		default:
 			errs.Addf(ErrSchema, fld.Key.S.Start,
 				"requires: dependency %q: %q is not a valid require field", id, name)
@@ -1457,6 +1536,23 @@ func validateProjectRequireBody(errs *ErrorList, id string, body *ObjectLit) {
 	}
 }
 
+func validateProjectRequireExclude(errs *ErrorList, id string, value Expr) {
+	arr, ok := value.(*ArrayLit)
+	if !ok {
+		errs.Addf(ErrSchema, value.Span().Start,
+			"requires: dependency %q: exclude must be an array of quoted versions, got %s",
+			id, exprKind(value))
+		return
+	}
+	for i, elem := range arr.Elements {
+		if _, ok := elem.(*StringLit); !ok {
+			errs.Addf(ErrSchema, elem.Span().Start,
+				"requires: dependency %q: exclude[%d] must be a quoted version, got %s",
+				id, i, exprKind(elem))
+		}
+	}
+}
+
 // ValidateProjectReplace checks a project `replace:` block: every entry
 // binds a quoted dependency id (a repo URL) to a quoted local path. The
 // id and path strings are not parsed here; resolution validates the URL
@@ -1465,6 +1561,27 @@ func ValidateProjectReplace(block *ObjectLit) *ErrorList {
 	return validateProjectEntries(block, "replace", "local path")
 }
 
+// LintSeverities are the severities a project `lint:` block may set
+// for a rule. "off" disables the rule.
+var LintSeverities = []string{"error", "warning", "info", "off"}
+
+// ValidateProjectLint checks a project `lint:` block: every entry binds
+// a bare rule code to a quoted severity from LintSeverities. The codes
+// are not checked here; pkg/lint owns the rule registry.
+func ValidateProjectLint(block *ObjectLit) *ErrorList {
+	errs := validateAliasToString(block, "lint rule", "severity")
+	for _, fld := range block.Fields {
+		severity, ok := fld.Value.(*StringLit)
+		if !ok || slices.Contains(LintSeverities, severity.Value) {
+			continue
+		}
+		errs.Addf(ErrSchema, severity.S.Start,
+			"lint rule %q: severity must be one of %s, got %q",
+			fld.Key.Name, strings.Join(LintSeverities, ", "), severity.Value)
+	}
+	return errs
+}
+
 // validateProjectEntries checks a project block whose entries bind a
 // quoted dependency id to a quoted string value. blockName names the block
 // and valueDesc names the value in error messages.
@@ -67,7 +67,39 @@ func Walk(e Expr, visit func(Expr)) {
 		for _, elem := range v.Elements {
 			Walk(elem, visit)
 		}
+	case *TypeUnion:
+		for _, variant := range v.Variants {
+			Walk(variant.Type, visit)
+		}
 	case *TypeOptional:
 		Walk(v.Elem, visit)
 	}
 }
+
+// FreeNames returns the bare names e reads, in source order: each
+// identifier expression and each dot path's root. A name that any
+// comprehension inside e binds is left out wherever it appears.
+func FreeNames(e Expr) []*Ident {
+	bound := map[string]bool{}
+	Walk(e, func(x Expr) {
+		if c, ok := x.(*Comprehension); ok {
+			for _, name := range c.Names {
+				bound[name] = true
+			}
+		}
+	})
+	var out []*Ident
+	Walk(e, func(x Expr) {
+		var id *Ident
+		switch v := x.(type) {
+		case *Ident:
+			id = v
+		case *DotPath:
+			id = v.Root
+		}
+		if id != nil && !bound[id.Name] {
+			out = append(out, id)
+		}
+	})
+	return out
+}
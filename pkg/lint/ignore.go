@@ -0,0 +1,66 @@
+package lint
+
+import (
+	"strings"
+
+	"github.com/cloudboss/unobin/pkg/lang/parse"
+)
+
+// ignoreDirective starts a comment that suppresses lint findings.
+const ignoreDirective = "lint:ignore"
+
+// ignores maps a source line to the rule codes suppressed on it. An
+// empty set suppresses every rule.
+type ignores map[int]map[string]bool
+
+// ignoreComments reads the `# lint:ignore code...` comments in
+// comments. Each names the rules it suppresses, separated by spaces or
+// commas, by rule code or full diagnostic code; with no code it
+// suppresses every rule. A comment applies to its own line, for a
+// trailing comment, and to the line after it, for a comment above the
+// finding.
+func ignoreComments(comments []parse.Comment) ignores {
+	out := ignores{}
+	for _, comment := range comments {
+		text := strings.TrimSpace(strings.TrimPrefix(comment.Text, "#"))
+		rest, ok := strings.CutPrefix(text, ignoreDirective)
+		if !ok || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+			continue
+		}
+		codes := map[string]bool{}
+		for _, code := range strings.FieldsFunc(rest, func(r rune) bool {
+			return r == ' ' || r == '\t' || r == ','
+		}) {
+			codes[strings.TrimPrefix(code, CodePrefix)] = true
+		}
+		line := comment.S.Start.Line
+		out.add(line, codes)
+		out.add(line+1, codes)
+	}
+	return out
+}
+
+func (ig ignores) add(line int, codes map[string]bool) {
+	existing, ok := ig[line]
+	if !ok {
+		ig[line] = codes
+		return
+	}
+	if len(existing) == 0 || len(codes) == 0 {
+		ig[line] = map[string]bool{}
+		return
+	}
+	merged := make(map[string]bool, len(existing)+len(codes))
+	for code := range existing {
+		merged[code] = true
+	}
+	for code := range codes {
+		merged[code] = true
+	}
+	ig[line] = merged
+}
+
+func (ig ignores) suppresses(code string, line int) bool {
+	codes, ok := ig[line]
+	return ok && (len(codes) == 0 || codes[code])
+}
@@ -0,0 +1,155 @@
+// Package lint runs style and best-practice rules over factory and
+// library files. A finding is advice, not a correctness error: the
+// checker in pkg/check decides whether a file compiles, and a file
+// with lint findings still does. Rules only read the parsed source,
+// so a lint run never resolves imports or reads library schemas.
+//
+// Each rule has a stable code. A project's `lint:` block sets the
+// severity a rule reports at, or turns it off, and a `# lint:ignore
+// code` comment suppresses a rule's findings on the comment's line
+// and the line after it.
+package lint
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/cloudboss/unobin/pkg/deps"
+	"github.com/cloudboss/unobin/pkg/diagnostic"
+	"github.com/cloudboss/unobin/pkg/lang/parse"
+	"github.com/cloudboss/unobin/pkg/lang/syntax"
+)
+
+// CodePrefix starts the diagnostic code of every lint finding. The
+// rest of the code is the rule's code.
+const CodePrefix = "unobin.lint."
+
+// SeverityOff is the project `lint:` severity that disables a rule.
+const SeverityOff diagnostic.Severity = "off"
+
+// Options configures a lint run.
+type Options struct {
+	// Path is written to each finding's diagnostic path.
+	Path string
+	// Project is the project the file belongs to, or nil. Its `lint:`
+	// block overrides rule severities, and its requires decide whether
+	// an import is pinned.
+	Project *deps.Project
+}
+
+// File runs the enabled rules over file and returns the findings no
+// `# lint:ignore` comment suppresses, sorted by position. It fails
+// when the project's `lint:` block names a rule that does not exist.
+func File(file *syntax.File, opts Options) ([]diagnostic.Diagnostic, error) {
+	severities, err := Severities(opts.Project)
+	if err != nil {
+		return nil, err
+	}
+	if file == nil {
+		return []diagnostic.Diagnostic{}, nil
+	}
+	rules := Rules()
+	p := &pass{file: file, project: opts.Project}
+	for _, code := range slices.Sorted(maps.Keys(severities)) {
+		p.rule = code
+		rules[code].check(p)
+	}
+	ignores := ignoreComments(file.Comments)
+	out := make([]diagnostic.Diagnostic, 0, len(p.findings))
+	for _, f := range p.findings {
+		if ignores.suppresses(f.rule, f.span.Start.Line) {
+			continue
+		}
+		out = append(out, diagnostic.Diagnostic{
+			Code:     CodePrefix + f.rule,
+			Severity: severities[f.rule],
+			Message:  f.message,
+			Hint:     f.hint,
+			Path:     opts.Path,
+			Span:     diagnosticSpan(f.span),
+		})
+	}
+	return diagnostic.Normalize(out), nil
+}
+
+// Severities returns the severity of every enabled rule: its default,
+// unless project's `lint:` block sets another. A rule set to "off" is
+// left out.
+func Severities(project *deps.Project) (map[string]diagnostic.Severity, error) {
+	rules := Rules()
+	out := make(map[string]diagnostic.Severity, len(rules))
+	for code, rule := range rules {
+		out[code] = rule.Severity
+	}
+	if project == nil {
+		return out, nil
+	}
+	for _, code := range slices.Sorted(maps.Keys(project.Lint)) {
+		if _, ok := rules[code]; !ok {
+			return nil, fmt.Errorf("project: lint: unknown rule %q; available: %s",
+				code, strings.Join(slices.Sorted(maps.Keys(rules)), ", "))
+		}
+		severity := diagnostic.Severity(project.Lint[code])
+		if severity == SeverityOff {
+			delete(out, code)
+			continue
+		}
+		out[code] = severity
+	}
+	return out, nil
+}
+
+// finding is one rule's report before severity and suppression apply.
+type finding struct {
+	rule    string
+	span    parse.Span
+	message string
+	hint    string
+}
+
+// pass is the state one lint run shares across rules: the file being
+// linted, its project, the rule running now, and what rules found.
+type pass struct {
+	file     *syntax.File
+	project  *deps.Project
+	rule     string
+	findings []finding
+}
+
+func (p *pass) report(span parse.Span, hint string, format string, args ...any) {
+	p.findings = append(p.findings, finding{
+		rule:    p.rule,
+		span:    span,
+		message: fmt.Sprintf(format, args...),
+		hint:    hint,
+	})
+}
+
+// bodies returns each factory body in the file: the factory's own, or
+// every composite a library exports.
+func (p *pass) bodies() []*syntax.FactoryBody {
+	var out []*syntax.FactoryBody
+	if p.file.Factory != nil {
+		out = append(out, &p.file.Factory.Body)
+	}
+	if p.file.Library != nil {
+		for i := range p.file.Library.Exports {
+			out = append(out, &p.file.Library.Exports[i].Body)
+		}
+	}
+	return out
+}
+
+func diagnosticSpan(span parse.Span) *diagnostic.Span {
+	out := &diagnostic.Span{Start: diagnostic.Position{
+		Line: span.Start.Line, Column: span.Start.Column, Offset: span.Start.Offset,
+	}}
+	if span.End.Line > 0 {
+		out.End = &diagnostic.Position{
+			Line: span.End.Line, Column: span.End.Column, Offset: span.End.Offset,
+		}
+	}
+	return out
+}
@@ -0,0 +1,102 @@
+package lint
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudboss/unobin/internal/ubtest"
+	"github.com/cloudboss/unobin/pkg/deps"
+	"github.com/cloudboss/unobin/pkg/diagnostic"
+	"github.com/cloudboss/unobin/pkg/lang/syntax"
+)
+
+func TestLintFixtures(t *testing.T) {
+	ubtest.RequireInvalidFixtureGoldens(t, "testdata/ub/rules")
+	ubtest.Run(t, "testdata/ub/rules", func(name string, src []byte) (string, []string) {
+		file, err := syntax.ParseSource(fixtureSourcePath(name), src)
+		if err != nil {
+			return "", []string{err.Error()}
+		}
+		findings, err := File(file, Options{Project: pinnedProject()})
+		if err != nil {
+			return "", []string{err.Error()}
+		}
+		return "", findingMessages(findings)
+	})
+}
+
+func TestLintProjectSeverities(t *testing.T) {
+	file := parseFixture(t, "invalid", "unused")
+	project := pinnedProject()
+	project.Lint = map[string]string{UnusedInputCode: "error", UnusedLocalCode: "off"}
+
+	findings, err := File(file, Options{Path: "factory.ub", Project: project})
+
+	require.NoError(t, err)
+	require.Len(t, findings, 2)
+	require.Equal(t, "unobin.lint.unused-asset", findings[0].Code)
+	require.Equal(t, diagnostic.SeverityWarning, findings[0].Severity)
+	require.Equal(t, "factory.ub", findings[0].Path)
+	require.Equal(t, "unobin.lint.unused-input", findings[1].Code)
+	require.Equal(t, diagnostic.SeverityError, findings[1].Severity)
+}
+
+func TestLintRejectsUnknownRule(t *testing.T) {
+	project := pinnedProject()
+	project.Lint = map[string]string{"no-such-rule": "off"}
+
+	_, err := File(parseFixture(t, "valid", "factory"), Options{Project: project})
+
+	require.ErrorContains(t, err, `project: lint: unknown rule "no-such-rule"`)
+}
+
+func TestLintUnpinnedImportNeedsProject(t *testing.T) {
+	findings, err := File(parseFixture(t, "invalid", "unpinned-import"), Options{})
+
+	require.NoError(t, err)
+	require.Empty(t, findings)
+}
+
+func TestRulesCodesMatchKeys(t *testing.T) {
+	for code, rule := range Rules() {
+		require.Equal(t, code, rule.Code)
+		require.NotEmpty(t, rule.Description, code)
+		require.NotNil(t, rule.check, code)
+	}
+}
+
+func pinnedProject() *deps.Project {
+	project := &deps.Project{}
+	project.SetRequire(deps.Dependency{URL: "example.com/pinned"}, "v1.0.0", false)
+	return project
+}
+
+func parseFixture(t *testing.T, kind, name string) *syntax.File {
+	t.Helper()
+	src := ubtest.ReadFixture(t, fmt.Sprintf("testdata/ub/rules/%s/%s.ub", kind, name))
+	file, err := syntax.ParseSource(fixtureSourcePath(name), []byte(src))
+	require.NoError(t, err)
+	return file
+}
+
+// fixtureSourcePath names the file a fixture parses as: library.ub for
+// fixtures named library-*, factory.ub for the rest.
+func fixtureSourcePath(name string) string {
+	if strings.HasPrefix(path.Base(name), "library-") {
+		return "library.ub"
+	}
+	return "factory.ub"
+}
+
+func findingMessages(findings []diagnostic.Diagnostic) []string {
+	out := make([]string, 0, len(findings))
+	for _, f := range findings {
+		out = append(out, fmt.Sprintf("%d:%d: %s: %s: %s",
+			f.Span.Start.Line, f.Span.Start.Column, f.Severity, f.Code, f.Message))
+	}
+	return out
+}
@@ -0,0 +1,357 @@
+package lint
+
+import (
+	"github.com/cloudboss/unobin/pkg/deps"
+	"github.com/cloudboss/unobin/pkg/diagnostic"
+	"github.com/cloudboss/unobin/pkg/lang"
+	"github.com/cloudboss/unobin/pkg/lang/parse"
+	"github.com/cloudboss/unobin/pkg/lang/syntax"
+	"github.com/cloudboss/unobin/pkg/resolve"
+	"github.com/cloudboss/unobin/pkg/toolchain"
+)
+
+// Rule codes, the registry keys a project `lint:` block and a
+// `# lint:ignore` comment name.
+const (
+	UnusedInputCode      = "unused-input"
+	UnusedLocalCode      = "unused-local"
+	UnusedAssetCode      = "unused-asset"
+	InputDescriptionCode = "input-description"
+	SensitiveOutputCode  = "sensitive-output"
+	TriggerAlwaysCode    = "trigger-always"
+	CompositeOutputsCode = "composite-outputs"
+	UnpinnedImportCode   = "unpinned-import"
+)
+
+// Rule is one lint rule: what it looks for, the severity it reports at
+// when the project does not set one, and the check that finds it.
+type Rule struct {
+	Code        string
+	Description string
+	Severity    diagnostic.Severity
+	check       func(*pass)
+}
+
+// Rules returns the lint rules keyed by code. Codes are unique by
+// construction: this is one map literal, so a duplicate is a compile
+// error.
+func Rules() map[string]Rule {
+	return map[string]Rule{
+		UnusedInputCode: {
+			Code:        UnusedInputCode,
+			Description: "An input is declared but nothing reads it.",
+			Severity:    diagnostic.SeverityWarning,
+			check:       checkUnusedInputs,
+		},
+		UnusedLocalCode: {
+			Code:        UnusedLocalCode,
+			Description: "A local is declared but nothing reads it.",
+			Severity:    diagnostic.SeverityWarning,
+			check:       checkUnusedLocals,
+		},
+		UnusedAssetCode: {
+			Code:        UnusedAssetCode,
+			Description: "An asset is declared but nothing reads it.",
+			Severity:    diagnostic.SeverityWarning,
+			check:       checkUnusedAssets,
+		},
+		InputDescriptionCode: {
+			Code:        InputDescriptionCode,
+			Description: "An input has no description for operators to read.",
+			Severity:    diagnostic.SeverityWarning,
+			check:       checkInputDescriptions,
+		},
+		SensitiveOutputCode: {
+			Code:        SensitiveOutputCode,
+			Description: "An output reads a sensitive input but is not marked sensitive.",
+			Severity:    diagnostic.SeverityError,
+			check:       checkSensitiveOutputs,
+		},
+		TriggerAlwaysCode: {
+			Code:        TriggerAlwaysCode,
+			Description: "An action reruns on every apply.",
+			Severity:    diagnostic.SeverityWarning,
+			check:       checkTriggerAlways,
+		},
+		CompositeOutputsCode: {
+			Code:        CompositeOutputsCode,
+			Description: "A composite declares no outputs.",
+			Severity:    diagnostic.SeverityWarning,
+			check:       checkCompositeOutputs,
+		},
+		UnpinnedImportCode: {
+			Code:        UnpinnedImportCode,
+			Description: "A remote import has no version in project.ub requires.",
+			Severity:    diagnostic.SeverityWarning,
+			check:       checkUnpinnedImports,
+		},
+	}
+}
+
+func checkUnusedInputs(p *pass) {
+	for _, body := range p.bodies() {
+		used := bodyRefs(body, "input")
+		for _, decl := range body.Inputs {
+			if !used[decl.Name.Name] {
+				p.report(decl.Name.S, "remove it, or read it as input."+decl.Name.Name,
+					"input %q is never used", decl.Name.Name)
+			}
+		}
+	}
+}
+
+func checkUnusedLocals(p *pass) {
+	for _, body := range p.bodies() {
+		used := bodyRefs(body, "local")
+		for _, decl := range body.Locals {
+			if !used[decl.Name.Name] {
+				p.report(decl.Name.S, "remove it, or read it as local."+decl.Name.Name,
+					"local %q is never used", decl.Name.Name)
+			}
+		}
+	}
+}
+
+func checkUnusedAssets(p *pass) {
+	for _, body := range p.bodies() {
+		used := bodyRefs(body, "asset")
+		for _, decl := range body.Assets {
+			if !used[decl.Name.Name] {
+				p.report(decl.Name.S, "remove it, or read it as asset."+decl.Name.Name,
+					"asset %q is never used", decl.Name.Name)
+			}
+		}
+	}
+}
+
+func checkInputDescriptions(p *pass) {
+	for _, body := range p.bodies() {
+		for _, decl := range body.Inputs {
+			if objectField(decl.Body, "description") == nil {
+				p.report(decl.Name.S, "add a description: field to the input",
+					"input %q has no description", decl.Name.Name)
+			}
+		}
+	}
+}
+
+func checkSensitiveOutputs(p *pass) {
+	for _, body := range p.bodies() {
+		sensitive := map[string]bool{}
+		for _, decl := range body.Inputs {
+			if metaTrue(decl.Body, "@sensitive") {
+				sensitive[decl.Name.Name] = true
+			}
+		}
+		if len(sensitive) == 0 {
+			continue
+		}
+		flow := newSensitiveFlow(body, sensitive)
+		for _, decl := range body.Outputs {
+			if metaTrue(decl.Body, "@sensitive") {
+				continue
+			}
+			if input, ok := flow.reads(lang.OutputValueExpr(decl.Body)); ok {
+				p.report(decl.Name.S, "add @sensitive: true to the output",
+					"output %q reads sensitive input %q but is not marked sensitive",
+					decl.Name.Name, input)
+			}
+		}
+	}
+}
+
+func checkTriggerAlways(p *pass) {
+	for _, body := range p.bodies() {
+		for _, decl := range body.Actions {
+			trigger, ok := objectField(decl.Body, "@trigger").(*parse.StringLit)
+			if ok && trigger.Value == "always" {
+				p.report(decl.Name.S, "trigger it on the values whose change should rerun it",
+					"action %q has @trigger: 'always' and reruns on every apply",
+					decl.Name.Name)
+			}
+		}
+	}
+}
+
+func checkCompositeOutputs(p *pass) {
+	if p.file.Library == nil {
+		return
+	}
+	for _, decl := range p.file.Library.Exports {
+		if len(decl.Body.Outputs) == 0 {
+			p.report(decl.Name.S, "declare the values callers can read in an outputs: block",
+				"composite %q declares no outputs", decl.Name.Name)
+		}
+	}
+}
+
+// checkUnpinnedImports reports remote imports that no project.ub
+// requires entry gives a version floor. Without a project there is
+// nothing to pin against, so the rule finds nothing.
+func checkUnpinnedImports(p *pass) {
+	if p.project == nil {
+		return
+	}
+	projects := deps.ProjectIDsFromDependencies(p.project.Requires)
+	for _, body := range p.bodies() {
+		for _, decl := range body.Imports {
+			if decl.Ref == nil {
+				continue
+			}
+			ref, err := resolve.ParseImportRef(decl.Ref.Value)
+			if err != nil {
+				continue
+			}
+			remote, ok := ref.(*resolve.RemoteImport)
+			if !ok || remote.URL == toolchain.UnobinModulePath {
+				continue
+			}
+			pkg := deps.RemotePackage{URL: remote.URL, Subdir: remote.Subdir}
+			if _, ok := deps.MostSpecificProject(projects, pkg); ok {
+				continue
+			}
+			p.report(decl.Alias.S, "add its project to project.ub requires, or run unobin deps get",
+				"import %q (%s) has no version in project.ub requires",
+				decl.Alias.Name, decl.Ref.Value)
+		}
+	}
+}
+
+// sensitiveFlow decides whether an expression reads a sensitive input,
+// directly or through the body's locals.
+type sensitiveFlow struct {
+	sensitive map[string]bool
+	locals    map[string]parse.Expr
+	memo      map[string]string
+	visiting  map[string]bool
+}
+
+func newSensitiveFlow(body *syntax.FactoryBody, sensitive map[string]bool) *sensitiveFlow {
+	locals := make(map[string]parse.Expr, len(body.Locals))
+	for _, decl := range body.Locals {
+		locals[decl.Name.Name] = decl.Value
+	}
+	return &sensitiveFlow{
+		sensitive: sensitive,
+		locals:    locals,
+		memo:      map[string]string{},
+		visiting:  map[string]bool{},
+	}
+}
+
+// reads returns the first sensitive input e reads.
+func (f *sensitiveFlow) reads(e parse.Expr) (string, bool) {
+	var found string
+	lang.Walk(e, func(x parse.Expr) {
+		if found != "" {
+			return
+		}
+		root, name, ok := refName(x)
+		if !ok {
+			return
+		}
+		switch root {
+		case "input":
+			if f.sensitive[name] {
+				found = name
+			}
+		case "local":
+			found = f.local(name)
+		}
+	})
+	return found, found != ""
+}
+
+func (f *sensitiveFlow) local(name string) string {
+	if input, ok := f.memo[name]; ok {
+		return input
+	}
+	if f.visiting[name] {
+		return ""
+	}
+	f.visiting[name] = true
+	input, _ := f.reads(f.locals[name])
+	delete(f.visiting, name)
+	f.memo[name] = input
+	return input
+}
+
+// bodyRefs returns the names body reads under root, such as the x of
+// every `input.x`.
+func bodyRefs(body *syntax.FactoryBody, root string) map[string]bool {
+	used := map[string]bool{}
+	visit := func(x parse.Expr) {
+		if r, name, ok := refName(x); ok && r == root {
+			used[name] = true
+		}
+	}
+	for _, e := range bodyExprs(body) {
+		lang.Walk(e, visit)
+	}
+	return used
+}
+
+// bodyExprs returns every expression in body a reference can appear in.
+func bodyExprs(body *syntax.FactoryBody) []parse.Expr {
+	var out []parse.Expr
+	for _, decl := range body.Inputs {
+		out = append(out, objectExpr(decl.Body))
+	}
+	for _, decl := range body.Locals {
+		out = append(out, decl.Value)
+	}
+	for _, decl := range body.Constraints {
+		out = append(out, decl.Value)
+	}
+	for _, decl := range body.Checks {
+		out = append(out, decl.Value)
+	}
+	for _, decl := range body.LibraryConfigs {
+		out = append(out, decl.Value)
+	}
+	for _, nodes := range [][]syntax.NodeDecl{body.Resources, body.Data, body.Actions} {
+		for _, decl := range nodes {
+			out = append(out, objectExpr(decl.Body))
+		}
+	}
+	for _, decl := range body.Outputs {
+		out = append(out, objectExpr(decl.Body))
+	}
+	return out
+}
+
+// refName splits a reference like `input.x.y` into its root and first
+// name.
+func refName(e parse.Expr) (string, string, bool) {
+	path, ok := e.(*parse.DotPath)
+	if !ok || path.Root == nil || len(path.Segments) == 0 || path.Segments[0].Name == "" {
+		return "", "", false
+	}
+	return path.Root.Name, path.Segments[0].Name, true
+}
+
+// objectExpr returns obj as an expression, keeping a nil object a nil
+// interface so walking it is a no-op.
+func objectExpr(obj *parse.ObjectLit) parse.Expr {
+	if obj == nil {
+		return nil
+	}
+	return obj
+}
+
+func objectField(obj *parse.ObjectLit, name string) parse.Expr {
+	if obj == nil {
+		return nil
+	}
+	for _, fld := range obj.Fields {
+		if fld.Key.Kind == parse.FieldIdent && fld.Key.Name == name {
+			return fld.Value
+		}
+	}
+	return nil
+}
+
+func metaTrue(obj *parse.ObjectLit, name string) bool {
+	b, ok := objectField(obj, name).(*parse.BoolLit)
+	return ok && b.Value
+}
@@ -75,30 +75,9 @@ func assetPathContainsOffset(
 }
 
 func walkFactoryBodyExpressions(body *syntax.FactoryBody, visit func(parse.Expr)) {
-	if body == nil {
-		return
-	}
-	walk := func(expr parse.Expr) {
+	factoryBodyRoots(body, func(expr parse.Expr) {
 		lang.Walk(expr, visit)
-	}
-	for _, input := range body.Inputs {
-		walk(input.Body)
-	}
-	for _, local := range body.Locals {
-		walk(local.Value)
-	}
-	for _, constraint := range body.Constraints {
-		walk(constraint.Value)
-	}
-	for _, config := range body.LibraryConfigs {
-		walk(config.Value)
-	}
-	for _, node := range allNodes(*body) {
-		walk(node.Body)
-	}
-	for _, output := range body.Outputs {
-		walk(output.Body)
-	}
+	})
 }
 
 func assetCompletionAtOffset(
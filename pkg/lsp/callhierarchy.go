@@ -0,0 +1,416 @@
+package lsp
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"slices"
+
+	"github.com/cloudboss/unobin/pkg/lang"
+	"github.com/cloudboss/unobin/pkg/lang/parse"
+	"github.com/cloudboss/unobin/pkg/lang/syntax"
+	"github.com/cloudboss/unobin/pkg/lsp/protocol"
+	"github.com/cloudboss/unobin/pkg/resolve"
+)
+
+// Call hierarchy items are the factory of a file, a library composite,
+// a UB library function, or a Go library function. A call is a node
+// whose selector names a composite, or a call to a library function.
+const (
+	callFactory    = "factory"
+	callComposite  = "composite"
+	callFunction   = "function"
+	callGoFunction = "go-function"
+)
+
+// callKey identifies a call hierarchy item, and is the data the item
+// carries through the client. Path is the .ub file that declares the
+// item, or the directory of a Go library.
+type callKey struct {
+	Kind     string `json:"kind"`
+	Path     string `json:"path"`
+	Name     string `json:"name,omitempty"`
+	NodeKind string `json:"nodeKind,omitempty"`
+}
+
+// callSite is one call from a caller to a callee, written at from in
+// the caller's file.
+type callSite struct {
+	caller     callKey
+	callerItem protocol.CallHierarchyItem
+	callee     callKey
+	calleeItem protocol.CallHierarchyItem
+	from       protocol.Range
+}
+
+// PrepareCallHierarchyForText returns the item at a position: the
+// composite or function declared or called there, or else the factory,
+// composite, or function whose body holds the position.
+func PrepareCallHierarchyForText(
+	path string,
+	text string,
+	pos protocol.Position,
+	projects *ProjectCache,
+	documents *DocumentStore,
+) ([]protocol.CallHierarchyItem, *protocol.ResponseError) {
+	offset, ok := LSPToOffset(text, pos)
+	if !ok {
+		return nil, protocol.InvalidParams("invalid document position")
+	}
+	refs := newRefIndex(projects, documents)
+	src, ok := refs.add(path, text)
+	if !ok {
+		return []protocol.CallHierarchyItem{}, nil
+	}
+	for _, site := range refs.callSites(src) {
+		start, _ := LSPToOffset(text, site.from.Start)
+		end, _ := LSPToOffset(text, site.from.End)
+		if offset >= start && offset <= end {
+			return []protocol.CallHierarchyItem{site.calleeItem}, nil
+		}
+	}
+	if item, ok := callerAt(src, offset); ok {
+		return []protocol.CallHierarchyItem{item}, nil
+	}
+	return []protocol.CallHierarchyItem{}, nil
+}
+
+// IncomingCallsForItem lists the factories, composites, and functions
+// in the workspace that call item, each with the ranges of its calls.
+func IncomingCallsForItem(
+	item protocol.CallHierarchyItem,
+	projects *ProjectCache,
+	documents *DocumentStore,
+) ([]protocol.CallHierarchyIncomingCall, *protocol.ResponseError) {
+	key, ok := callKeyFromData(item.Data)
+	if !ok {
+		return nil, protocol.InvalidParams("call hierarchy item has no data")
+	}
+	refs := newRefIndex(projects, documents)
+	paths, err := refs.workspaceFiles()
+	if err != nil {
+		return nil, protocol.InternalError(err)
+	}
+	if more, err := refs.projectFiles(key.Path); err == nil {
+		paths = append(paths, more...)
+	}
+	slices.Sort(paths)
+	paths = slices.Compact(paths)
+	calls := []protocol.CallHierarchyIncomingCall{}
+	index := map[callKey]int{}
+	for _, path := range paths {
+		src, ok := refs.load(path)
+		if !ok {
+			continue
+		}
+		for _, site := range refs.callSites(src) {
+			if site.callee != key {
+				continue
+			}
+			i, ok := index[site.caller]
+			if !ok {
+				i = len(calls)
+				index[site.caller] = i
+				calls = append(calls, protocol.CallHierarchyIncomingCall{From: site.callerItem})
+			}
+			calls[i].FromRanges = append(calls[i].FromRanges, site.from)
+		}
+	}
+	return calls, nil
+}
+
+// OutgoingCallsForItem lists the composites and functions item calls,
+// each with the ranges in item's file that call it.
+func OutgoingCallsForItem(
+	item protocol.CallHierarchyItem,
+	projects *ProjectCache,
+	documents *DocumentStore,
+) ([]protocol.CallHierarchyOutgoingCall, *protocol.ResponseError) {
+	key, ok := callKeyFromData(item.Data)
+	if !ok {
+		return nil, protocol.InvalidParams("call hierarchy item has no data")
+	}
+	calls := []protocol.CallHierarchyOutgoingCall{}
+	if key.Kind == callGoFunction {
+		return calls, nil
+	}
+	refs := newRefIndex(projects, documents)
+	src, ok := refs.load(key.Path)
+	if !ok {
+		return calls, nil
+	}
+	index := map[callKey]int{}
+	for _, site := range refs.callSites(src) {
+		if site.caller != key {
+			continue
+		}
+		i, ok := index[site.callee]
+		if !ok {
+			i = len(calls)
+			index[site.callee] = i
+			calls = append(calls, protocol.CallHierarchyOutgoingCall{To: site.calleeItem})
+		}
+		calls[i].FromRanges = append(calls[i].FromRanges, site.from)
+	}
+	return calls, nil
+}
+
+// callKeyFromData reads back the key an item was sent with. The client
+// returns it as decoded JSON, so it is encoded again to decode it.
+func callKeyFromData(data any) (callKey, bool) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return callKey{}, false
+	}
+	var key callKey
+	if err := json.Unmarshal(raw, &key); err != nil || key.Kind == "" || key.Path == "" {
+		return callKey{}, false
+	}
+	return key, true
+}
+
+// callSites lists the calls written in src: those from its factory, its
+// composites, and its functions, in file order.
+func (x *refIndex) callSites(src *refSource) []callSite {
+	var sites []callSite
+	if src.file.Factory != nil {
+		caller := callKey{Kind: callFactory, Path: src.path}
+		item := factoryCallItem(src, caller)
+		sites = x.bodyCallSites(src, &src.file.Factory.Body, caller, item, sites)
+	}
+	if library := src.file.Library; library != nil {
+		for i := range library.Exports {
+			decl := &library.Exports[i]
+			caller, item := compositeCallItem(src, decl)
+			sites = x.bodyCallSites(src, &decl.Body, caller, item, sites)
+		}
+		siblings := x.library(filepath.Dir(src.path)).functions
+		for i := range library.Functions {
+			decl := &library.Functions[i]
+			caller, item := functionCallItem(src, decl)
+			lang.Walk(decl.Body, func(e parse.Expr) {
+				call, ok := e.(*parse.Call)
+				if !ok || call.Callee == nil {
+					return
+				}
+				target, ok := siblings[call.Callee.Name]
+				if !ok {
+					return
+				}
+				callee, calleeItem := functionCallItem(target.source, target.decl)
+				start := call.Callee.S.Start.Offset
+				sites = append(sites, callSite{
+					caller: caller, callerItem: item, callee: callee, calleeItem: calleeItem,
+					from: offsetRange(src.text, start, start+len(call.Callee.Name)),
+				})
+			})
+		}
+	}
+	slices.SortStableFunc(sites, func(a, b callSite) int {
+		if a.from.Start.Line != b.from.Start.Line {
+			return int(a.from.Start.Line) - int(b.from.Start.Line)
+		}
+		return int(a.from.Start.Character) - int(b.from.Start.Character)
+	})
+	return sites
+}
+
+// bodyCallSites adds the calls one factory or composite body makes: its
+// nodes that call a UB composite, and its calls to library functions.
+func (x *refIndex) bodyCallSites(
+	src *refSource,
+	body *syntax.FactoryBody,
+	caller callKey,
+	callerItem protocol.CallHierarchyItem,
+	sites []callSite,
+) []callSite {
+	decls := definitionDeclsForBody(body)
+	for _, node := range allNodes(*body) {
+		export := node.Selector.Export
+		if export.Name == "" {
+			continue
+		}
+		resolved, ok := x.libraryFor(src.path, node.Selector.Alias.Name, decls)
+		if !ok {
+			continue
+		}
+		target, ok := x.library(resolved.source.Path).composites[string(node.Kind)+"."+export.Name]
+		if !ok {
+			continue
+		}
+		callee, calleeItem := compositeCallItem(target.source, target.decl)
+		start := export.S.Start.Offset
+		sites = append(sites, callSite{
+			caller: caller, callerItem: callerItem, callee: callee, calleeItem: calleeItem,
+			from: offsetRange(src.text, start, start+len(export.Name)),
+		})
+	}
+	factoryBodyRoots(body, func(root parse.Expr) {
+		lang.Walk(root, func(e parse.Expr) {
+			call, ok := e.(*parse.Call)
+			if !ok || call.Library == nil || call.Func == nil ||
+				call.Library.Name == lang.CoreNamespace {
+				return
+			}
+			resolved, ok := x.libraryFor(src.path, call.Library.Name, decls)
+			if !ok {
+				return
+			}
+			callee, calleeItem, ok := x.functionCallee(resolved, call.Func.Name)
+			if !ok {
+				return
+			}
+			start := call.Library.S.Start.Offset
+			end := start + len(call.Library.Name)
+			if name, ok := callFuncOffset(src.text, call); ok {
+				end = name + len(call.Func.Name)
+			}
+			sites = append(sites, callSite{
+				caller: caller, callerItem: callerItem, callee: callee, calleeItem: calleeItem,
+				from: offsetRange(src.text, start, end),
+			})
+		})
+	})
+	return sites
+}
+
+// functionCallee returns the UB or Go function name that a resolved
+// library exports.
+func (x *refIndex) functionCallee(
+	resolved resolvedImport,
+	name string,
+) (callKey, protocol.CallHierarchyItem, bool) {
+	if target, ok := x.library(resolved.source.Path).functions[name]; ok {
+		key, item := functionCallItem(target.source, target.decl)
+		return key, item, true
+	}
+	if !resolve.IsGoLibrary(resolved.source) {
+		return callKey{}, protocol.CallHierarchyItem{}, false
+	}
+	index, found, err := goIndexForResolved(resolved)
+	if err != nil || !found {
+		return callKey{}, protocol.CallHierarchyItem{}, false
+	}
+	loc, ok := index.Functions[name]
+	if !ok {
+		return callKey{}, protocol.CallHierarchyItem{}, false
+	}
+	locations, _, err := goLocationDefinition(loc)
+	if err != nil || len(locations) == 0 {
+		return callKey{}, protocol.CallHierarchyItem{}, false
+	}
+	key := callKey{Kind: callGoFunction, Path: filepath.Clean(resolved.source.Path), Name: name}
+	detail := resolved.source.GoImportPath
+	if detail == "" {
+		detail = filepath.Base(resolved.source.Path)
+	}
+	return key, protocol.CallHierarchyItem{
+		Name:           name,
+		Kind:           protocol.SymbolKindFunction,
+		Detail:         detail,
+		URI:            locations[0].URI,
+		Range:          locations[0].Range,
+		SelectionRange: locations[0].Range,
+		Data:           key,
+	}, true
+}
+
+// callerAt returns the item whose declaration or body holds offset.
+func callerAt(src *refSource, offset int) (protocol.CallHierarchyItem, bool) {
+	if factory := src.file.Factory; factory != nil {
+		start := factory.S.Start.Offset
+		if offset >= start && offset <= factory.Body.S.End.Offset {
+			return factoryCallItem(src, callKey{Kind: callFactory, Path: src.path}), true
+		}
+	}
+	library := src.file.Library
+	if library == nil {
+		return protocol.CallHierarchyItem{}, false
+	}
+	for i := range library.Exports {
+		decl := &library.Exports[i]
+		if offset >= decl.S.Start.Offset && offset <= decl.Body.S.End.Offset {
+			_, item := compositeCallItem(src, decl)
+			return item, true
+		}
+	}
+	for i := range library.Functions {
+		decl := &library.Functions[i]
+		if offset >= decl.S.Start.Offset && offset <= functionEnd(decl) {
+			_, item := functionCallItem(src, decl)
+			return item, true
+		}
+	}
+	return protocol.CallHierarchyItem{}, false
+}
+
+func factoryCallItem(src *refSource, key callKey) protocol.CallHierarchyItem {
+	start := src.file.Factory.S.Start.Offset
+	return protocol.CallHierarchyItem{
+		Name:           callFactory,
+		Kind:           protocol.SymbolKindModule,
+		Detail:         filepath.Base(src.path),
+		URI:            PathToFileURI(src.path),
+		Range:          offsetRange(src.text, start, src.file.Factory.Body.S.End.Offset),
+		SelectionRange: offsetRange(src.text, start, start+len(callFactory)),
+		Data:           key,
+	}
+}
+
+func compositeCallItem(
+	src *refSource,
+	decl *syntax.CompositeDecl,
+) (callKey, protocol.CallHierarchyItem) {
+	key := callKey{
+		Kind: callComposite, Path: src.path, Name: decl.Name.Name, NodeKind: string(decl.Kind),
+	}
+	name := identOccurrence(src, decl.Name, true)
+	return key, protocol.CallHierarchyItem{
+		Name:           string(decl.Kind) + "." + decl.Name.Name,
+		Kind:           protocol.SymbolKindClass,
+		Detail:         filepath.Base(filepath.Dir(src.path)),
+		URI:            PathToFileURI(src.path),
+		Range:          offsetRange(src.text, decl.S.Start.Offset, decl.Body.S.End.Offset),
+		SelectionRange: name.lspRange(),
+		Data:           key,
+	}
+}
+
+func functionCallItem(
+	src *refSource,
+	decl *syntax.FunctionDecl,
+) (callKey, protocol.CallHierarchyItem) {
+	key := callKey{Kind: callFunction, Path: src.path, Name: decl.Name.Name}
+	name := identOccurrence(src, decl.Name, true)
+	return key, protocol.CallHierarchyItem{
+		Name:           decl.Name.Name,
+		Kind:           protocol.SymbolKindFunction,
+		Detail:         filepath.Base(filepath.Dir(src.path)),
+		URI:            PathToFileURI(src.path),
+		Range:          offsetRange(src.text, decl.S.Start.Offset, functionEnd(decl)),
+		SelectionRange: name.lspRange(),
+		Data:           key,
+	}
+}
+
+// functionEnd returns where a function declaration ends: at the end of
+// its span when that is known, else at the end of its body.
+func functionEnd(decl *syntax.FunctionDecl) int {
+	if !decl.S.End.IsZero() {
+		return decl.S.End.Offset
+	}
+	if decl.Body != nil {
+		if end := decl.Body.Span().End; !end.IsZero() {
+			return end.Offset
+		}
+	}
+	return decl.Name.S.Start.Offset + len(decl.Name.Name)
+}
+
+// offsetRange returns the range from start to end, or an empty range
+// at start when end is not past it.
+func offsetRange(text string, start, end int) protocol.Range {
+	if end < start {
+		end = start
+	}
+	return protocol.Range{Start: OffsetToLSP(text, start), End: OffsetToLSP(text, end)}
+}
@@ -0,0 +1,217 @@
+package lsp
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudboss/unobin/internal/ubtest"
+	"github.com/cloudboss/unobin/pkg/lsp/protocol"
+)
+
+func TestCallHierarchyFixtureHasNoDiagnostics(t *testing.T) {
+	root, path, source := callHierarchyProject(t)
+
+	diagnostics := DiagnosticsForTextWithProjects(path, source, NewProjectCache(root))
+	require.Empty(t, diagnostics)
+}
+
+func TestPrepareCallHierarchyAtCompositeCallSite(t *testing.T) {
+	root, path, source := callHierarchyProject(t)
+
+	items := prepareCallHierarchy(t, root, path, source,
+		positionInText(source, "server: bundle.web", "web"))
+	require.Len(t, items, 1)
+	require.Equal(t, "resource.web", items[0].Name)
+	require.Equal(t, protocol.SymbolKindClass, items[0].Kind)
+	require.Equal(t, PathToFileURI(filepath.Join(root, "bundle", "library.ub")), items[0].URI)
+}
+
+func TestPrepareCallHierarchyInFactoryBody(t *testing.T) {
+	root, path, source := callHierarchyProject(t)
+
+	items := prepareCallHierarchy(t, root, path, source,
+		positionInText(source, "env: { type", "type"))
+	require.Len(t, items, 1)
+	require.Equal(t, "factory", items[0].Name)
+	require.Equal(t, PathToFileURI(path), items[0].URI)
+}
+
+func TestIncomingCallsForComposite(t *testing.T) {
+	root, path, source := callHierarchyProject(t)
+	projects := NewProjectCache(root)
+	item := prepareCallHierarchy(t, root, path, source,
+		positionInText(source, "server: bundle.web", "web"))[0]
+
+	calls, rpcErr := IncomingCallsForItem(roundTripItem(t, item), projects, nil)
+	require.Nil(t, rpcErr)
+	require.Len(t, calls, 1)
+	require.Equal(t, "factory", calls[0].From.Name)
+	require.Equal(t, PathToFileURI(path), calls[0].From.URI)
+	require.Equal(t, []protocol.Range{
+		rangeOfText(source, "server: bundle.web", "web"),
+		rangeOfText(source, "backup: bundle.web", "web"),
+	}, calls[0].FromRanges)
+}
+
+func TestIncomingCallsForFunction(t *testing.T) {
+	root, _, source := callHierarchyProject(t)
+	namesPath := filepath.Join(root, "names", "library.ub")
+	namesSource := readTestFile(t, namesPath)
+	projects := NewProjectCache(root)
+	items, rpcErr := PrepareCallHierarchyForText(namesPath, namesSource,
+		positionInText(namesSource, "label: {", "label"), projects, nil)
+	require.Nil(t, rpcErr)
+	require.Len(t, items, 1)
+	require.Equal(t, "label", items[0].Name)
+
+	calls, rpcErr := IncomingCallsForItem(roundTripItem(t, items[0]), projects, nil)
+	require.Nil(t, rpcErr)
+	var callers []string
+	for _, call := range calls {
+		callers = append(callers, call.From.Name)
+	}
+	require.Equal(t, []string{"resource.web", "factory"}, callers)
+	require.Equal(t, []protocol.Range{
+		rangeOfText(source, "names.label(input.env)", "names.label"),
+		rangeOfText(source, "names.label('backup')", "names.label"),
+	}, calls[1].FromRanges)
+}
+
+func TestOutgoingCallsFromFactory(t *testing.T) {
+	root, path, source := callHierarchyProject(t)
+	item := prepareCallHierarchy(t, root, path, source,
+		positionInText(source, "env: { type", "type"))[0]
+
+	calls, rpcErr := OutgoingCallsForItem(roundTripItem(t, item), NewProjectCache(root), nil)
+	require.Nil(t, rpcErr)
+	var callees []string
+	for _, call := range calls {
+		callees = append(callees, call.To.Name)
+	}
+	require.Equal(t, []string{"label", "resource.web"}, callees)
+	require.Len(t, calls[0].FromRanges, 2)
+	require.Len(t, calls[1].FromRanges, 2)
+}
+
+func TestOutgoingCallsFromFunctionToSibling(t *testing.T) {
+	root, _, _ := callHierarchyProject(t)
+	namesPath := filepath.Join(root, "names", "library.ub")
+	namesSource := readTestFile(t, namesPath)
+	projects := NewProjectCache(root)
+	items, rpcErr := PrepareCallHierarchyForText(namesPath, namesSource,
+		positionInText(namesSource, "body: prefix(value)", "value"), projects, nil)
+	require.Nil(t, rpcErr)
+	require.Len(t, items, 1)
+	require.Equal(t, "label", items[0].Name)
+
+	calls, rpcErr := OutgoingCallsForItem(roundTripItem(t, items[0]), projects, nil)
+	require.Nil(t, rpcErr)
+	require.Len(t, calls, 1)
+	require.Equal(t, "prefix", calls[0].To.Name)
+	require.Equal(t, []protocol.Range{rangeOfText(namesSource, "body: prefix(value)", "prefix")},
+		calls[0].FromRanges)
+}
+
+func TestOutgoingCallsToGoFunction(t *testing.T) {
+	root, path, source, goDir := goDefinitionProject(t)
+	item := prepareCallHierarchy(t, root, path, source,
+		positionInText(source, "'input-id'", "input-id"))[0]
+
+	calls, rpcErr := OutgoingCallsForItem(roundTripItem(t, item), NewProjectCache(root), nil)
+	require.Nil(t, rpcErr)
+	require.Len(t, calls, 1)
+	require.Equal(t, "slug", calls[0].To.Name)
+	require.Equal(t, protocol.SymbolKindFunction, calls[0].To.Kind)
+	uri, err := FileURIToPath(calls[0].To.URI)
+	require.NoError(t, err)
+	require.True(t, pathInDir(uri, goDir))
+	require.Equal(t, []protocol.Range{rangeOfText(source, "def.slug('v1')", "def.slug")},
+		calls[0].FromRanges)
+}
+
+func TestIncomingCallsRejectsItemWithoutData(t *testing.T) {
+	_, rpcErr := IncomingCallsForItem(protocol.CallHierarchyItem{Name: "factory"}, nil, nil)
+	require.NotNil(t, rpcErr)
+	require.Equal(t, protocol.ErrorCodeInvalidParams, rpcErr.Code)
+}
+
+func TestSessionCallHierarchyRoundTrip(t *testing.T) {
+	root, path, source := callHierarchyProject(t)
+	session := NewSession("dev")
+	session.projects.SetWorkspaceRoots([]string{root})
+	uri := PathToFileURI(path)
+	rpcErr := openDocument(t, session, uri, 1, source)
+	require.Nil(t, rpcErr)
+
+	result, rpcErr := sessionRequest(t, session, "textDocument/prepareCallHierarchy",
+		protocol.CallHierarchyPrepareParams{
+			TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+			Position:     positionInText(source, "server: bundle.web", "web"),
+		})
+	require.Nil(t, rpcErr)
+	items, ok := result.([]protocol.CallHierarchyItem)
+	require.True(t, ok)
+	require.Len(t, items, 1)
+
+	result, rpcErr = sessionRequest(t, session, "callHierarchy/incomingCalls",
+		protocol.CallHierarchyIncomingCallsParams{Item: roundTripItem(t, items[0])})
+	require.Nil(t, rpcErr)
+	incoming, ok := result.([]protocol.CallHierarchyIncomingCall)
+	require.True(t, ok)
+	require.Len(t, incoming, 1)
+
+	result, rpcErr = sessionRequest(t, session, "callHierarchy/outgoingCalls",
+		protocol.CallHierarchyOutgoingCallsParams{Item: roundTripItem(t, items[0])})
+	require.Nil(t, rpcErr)
+	outgoing, ok := result.([]protocol.CallHierarchyOutgoingCall)
+	require.True(t, ok)
+	require.Len(t, outgoing, 1)
+	require.Equal(t, "label", outgoing[0].To.Name)
+}
+
+// callHierarchyProject writes the call hierarchy fixture tree: a
+// factory, a `bundle` library of composites, and a `names` library of
+// functions.
+func callHierarchyProject(t *testing.T) (string, string, string) {
+	t.Helper()
+	root := writeUBProject(t, nil, nil)
+	for _, name := range []string{"factory.ub", "bundle/library.ub", "names/library.ub"} {
+		source := ubtest.ReadFixture(t, filepath.Join("testdata/ub/callhierarchy/valid", name))
+		path := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(source), 0o644))
+	}
+	path := filepath.Join(root, "factory.ub")
+	return root, path, readTestFile(t, path)
+}
+
+func prepareCallHierarchy(
+	t *testing.T,
+	root, path, source string,
+	pos protocol.Position,
+) []protocol.CallHierarchyItem {
+	t.Helper()
+	items, rpcErr := PrepareCallHierarchyForText(path, source, pos, NewProjectCache(root), nil)
+	require.Nil(t, rpcErr)
+	return items
+}
+
+// roundTripItem returns item as the client sends it back, with its data
+// decoded from JSON.
+func roundTripItem(t *testing.T, item protocol.CallHierarchyItem) protocol.CallHierarchyItem {
+	t.Helper()
+	raw, err := json.Marshal(item)
+	require.NoError(t, err)
+	var out protocol.CallHierarchyItem
+	require.NoError(t, json.Unmarshal(raw, &out))
+	return out
+}
+
+func rangeOfText(text, contextText, target string) protocol.Range {
+	start := offsetInText(text, contextText, target)
+	return offsetRange(text, start, start+len(target))
+}
@@ -0,0 +1,284 @@
+package lsp
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/cloudboss/unobin/pkg/lang"
+	"github.com/cloudboss/unobin/pkg/lang/syntax"
+	"github.com/cloudboss/unobin/pkg/lsp/protocol"
+)
+
+// codeActionFixes maps each diagnostic code to the fixes it offers.
+var codeActionFixes = map[protocol.DiagnosticCode]func(*codeActionSource, fixData) []quickFix{
+	lang.CodeUnknownImport:       fixUnknownImport,
+	lang.CodeMissingInput:        fixMissingInput,
+	lang.CodeUnknownCoreFunction: fixUnknownCoreFunction,
+	codeUnformatted:              fixUnformatted,
+	codeRenamedNode:              fixRenamedNode,
+}
+
+// CodeActionsForText returns the quick fixes for the diagnostics a code
+// action request carries. A diagnostic offers fixes only through its
+// code, so each fix stays tied to the diagnostic it repairs.
+func CodeActionsForText(
+	path string,
+	text string,
+	diagnostics []protocol.Diagnostic,
+	projects *ProjectCache,
+	documents *DocumentStore,
+) ([]protocol.CodeAction, *protocol.ResponseError) {
+	src := &codeActionSource{path: path, text: text, projects: projects, documents: documents}
+	actions := []protocol.CodeAction{}
+	for _, diagnostic := range diagnostics {
+		fixes, ok := codeActionFixes[diagnostic.Code]
+		if !ok {
+			continue
+		}
+		offset, ok := LSPToOffset(text, diagnostic.Range.Start)
+		if !ok {
+			continue
+		}
+		for _, fix := range fixes(src, fixData{offset: offset, data: diagnosticData(diagnostic)}) {
+			actions = append(actions, protocol.CodeAction{
+				Title:       fix.title,
+				Kind:        protocol.CodeActionKindQuickFix,
+				Diagnostics: []protocol.Diagnostic{diagnostic},
+				IsPreferred: fix.preferred,
+				Edit: &protocol.WorkspaceEdit{
+					Changes: map[string][]protocol.TextEdit{PathToFileURI(path): fix.edits},
+				},
+			})
+		}
+	}
+	return actions, nil
+}
+
+// codeActionSource is the document a code action request is for,
+// parsed on first use.
+type codeActionSource struct {
+	path      string
+	text      string
+	projects  *ProjectCache
+	documents *DocumentStore
+	raw       *lang.File
+	parsed    bool
+}
+
+func (s *codeActionSource) file() (*lang.File, bool) {
+	if !s.parsed {
+		s.parsed = true
+		s.raw, _ = lang.ParseSource(s.path, []byte(s.text))
+	}
+	return s.raw, s.raw != nil
+}
+
+// fixData is where a diagnostic starts and the names it carries.
+type fixData struct {
+	offset int
+	data   map[string]string
+}
+
+// quickFix is one way to fix a diagnostic.
+type quickFix struct {
+	title     string
+	edits     []protocol.TextEdit
+	preferred bool
+}
+
+// diagnosticData reads back the data a diagnostic was published with.
+func diagnosticData(diagnostic protocol.Diagnostic) map[string]string {
+	out := map[string]string{}
+	switch data := diagnostic.Data.(type) {
+	case map[string]string:
+		for k, v := range data {
+			out[k] = v
+		}
+	case map[string]any:
+		for k, v := range data {
+			if s, ok := v.(string); ok {
+				out[k] = s
+			}
+		}
+	}
+	return out
+}
+
+// fixUnknownImport adds the missing alias to the imports of the body
+// the diagnostic is in, once for each library the alias likely names.
+func fixUnknownImport(src *codeActionSource, fix fixData) []quickFix {
+	alias := fix.data["alias"]
+	file, ok := src.file()
+	if alias == "" || !ok {
+		return nil
+	}
+	body := bodyObjectContaining(file, fix.offset)
+	if body == nil {
+		return nil
+	}
+	var fixes []quickFix
+	for _, path := range importCandidates(src.path, alias, src.projects) {
+		entry := alias + ": " + lang.Render(path)
+		var at int
+		var insert string
+		if imports, ok := importsObject(body); ok {
+			at, insert = objectFieldInsertion(src.text, imports, entry, nil)
+		} else {
+			at, insert = objectFieldInsertion(src.text, body,
+				"imports: {\n  "+entry+"\n}", blocksAfter("imports"))
+		}
+		fixes = append(fixes, quickFix{
+			title: fmt.Sprintf("Import %s as %s", path, alias),
+			edits: []protocol.TextEdit{insertEdit(src.text, at, insert)},
+		})
+	}
+	return fixes
+}
+
+func importsObject(body *lang.ObjectLit) (*lang.ObjectLit, bool) {
+	field := objectField(body, "imports")
+	if field == nil {
+		return nil, false
+	}
+	obj, ok := field.Value.(*lang.ObjectLit)
+	return obj, ok
+}
+
+// importCandidates lists the import paths alias likely stands for: a
+// directory of that name beside the file, then each required
+// dependency whose last path element is the alias, with or without an
+// `unobin-library-` prefix.
+func importCandidates(path string, alias string, projects *ProjectCache) []string {
+	var out []string
+	if info, err := os.Stat(filepath.Join(filepath.Dir(path), alias)); err == nil && info.IsDir() {
+		out = append(out, "./"+alias)
+	}
+	if projects == nil {
+		return out
+	}
+	project, err := projects.ProjectForPath(path)
+	if err != nil || project.DepsProject == nil {
+		return out
+	}
+	var deps []string
+	for dep := range project.DepsProject.Requires {
+		id := dep.String()
+		last := id[strings.LastIndexAny(id, "/")+1:]
+		if last == alias || last == "unobin-library-"+alias {
+			deps = append(deps, id)
+		}
+	}
+	slices.Sort(deps)
+	return append(out, deps...)
+}
+
+// bodyObjectContaining returns the factory or composite body that
+// holds offset.
+func bodyObjectContaining(file *lang.File, offset int) *lang.ObjectLit {
+	if file.Body == nil {
+		return nil
+	}
+	for _, field := range file.Body.Fields {
+		var body *lang.ObjectLit
+		switch {
+		case field.Decl != nil:
+			body = field.Decl.Body
+		case field.Key.Name == "factory":
+			body, _ = field.Value.(*lang.ObjectLit)
+		}
+		if body != nil && body.S.Start.Offset <= offset && offset < body.S.End.Offset {
+			return body
+		}
+	}
+	return nil
+}
+
+// fixMissingInput adds the missing input to the node body the
+// diagnostic starts at, set to a value of its declared type.
+func fixMissingInput(src *codeActionSource, fix fixData) []quickFix {
+	name, stub := fix.data["input"], fix.data["stub"]
+	file, ok := src.file()
+	if name == "" || stub == "" || !ok {
+		return nil
+	}
+	body := rawObjectAt(file.Body, fix.offset)
+	if body == nil {
+		return nil
+	}
+	at, insert := objectFieldInsertion(src.text, body, name+": "+stub, nil)
+	title := "Add required input " + name
+	if typ := fix.data["type"]; typ != "" {
+		title += " (" + typ + ")"
+	}
+	return []quickFix{{
+		title:     title,
+		edits:     []protocol.TextEdit{insertEdit(src.text, at, insert)},
+		preferred: true,
+	}}
+}
+
+// fixUnknownCoreFunction replaces a misspelled @core function name with
+// the one the checker suggested. The diagnostic starts at the call's
+// namespace, so the name follows it.
+func fixUnknownCoreFunction(src *codeActionSource, fix fixData) []quickFix {
+	name, suggestion := fix.data["function"], fix.data["suggestion"]
+	start := fix.offset
+	if strings.HasPrefix(src.text[start:], lang.CoreNamespace+".") {
+		start += len(lang.CoreNamespace) + 1
+	}
+	end := start + len(name)
+	if name == "" || suggestion == "" || end > len(src.text) || src.text[start:end] != name {
+		return nil
+	}
+	return []quickFix{{
+		title: "Change to " + lang.CoreNamespace + "." + suggestion,
+		edits: []protocol.TextEdit{{
+			Range: protocol.Range{
+				Start: OffsetToLSP(src.text, start),
+				End:   OffsetToLSP(src.text, end),
+			},
+			NewText: suggestion,
+		}},
+		preferred: true,
+	}}
+}
+
+// fixUnformatted formats the file.
+func fixUnformatted(src *codeActionSource, _ fixData) []quickFix {
+	edits, err := FormatText(src.path, src.text)
+	if err != nil || len(edits) == 0 {
+		return nil
+	}
+	return []quickFix{{title: "Format file", edits: edits, preferred: true}}
+}
+
+// fixRenamedNode adds the state move that carries a renamed node's
+// state to its new name.
+func fixRenamedNode(src *codeActionSource, fix fixData) []quickFix {
+	kind, from, to := fix.data["kind"], fix.data["from"], fix.data["to"]
+	if kind == "" || from == "" || to == "" {
+		return nil
+	}
+	refs := newRefIndex(src.projects, src.documents)
+	current, ok := refs.add(src.path, src.text)
+	if !ok {
+		return nil
+	}
+	entry, ok := refs.entryAt(current, fix.offset)
+	if !ok || entry.key.kind != refNode || entry.key.name != to ||
+		entry.key.nodeKind != syntax.NodeKind(kind) {
+		return nil
+	}
+	move, ok := refs.stateMoveEdit(entry.key, from, to)
+	if !ok {
+		return nil
+	}
+	return []quickFix{{
+		title:     fmt.Sprintf("Add state move from %s.%s to %s.%s", kind, from, kind, to),
+		edits:     []protocol.TextEdit{move.edit},
+		preferred: true,
+	}}
+}
@@ -0,0 +1,199 @@
+package lsp
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudboss/unobin/internal/ubtest"
+	"github.com/cloudboss/unobin/pkg/lang"
+	"github.com/cloudboss/unobin/pkg/lsp/protocol"
+)
+
+func TestCodeActionAddsUnknownImport(t *testing.T) {
+	root, path, source := codeActionProject(t, "unknown-import")
+
+	actions := codeActionsFor(t, root, path, source, lang.CodeUnknownImport)
+	require.Len(t, actions, 1)
+	require.Equal(t, "Import ./bundle as bundle", actions[0].Title)
+	require.Equal(t, protocol.CodeActionKindQuickFix, actions[0].Kind)
+	fixed := applyTextEdits(t, source, actions[0].Edit.Changes[PathToFileURI(path)])
+	require.Equal(t, codeActionGolden(t, "unknown-import"), fixed)
+	requireNoDiagnostic(t, root, path, fixed, lang.CodeUnknownImport)
+}
+
+func TestCodeActionStubsMissingInput(t *testing.T) {
+	root, path, source := codeActionProject(t, "missing-input")
+
+	actions := codeActionsFor(t, root, path, source, lang.CodeMissingInput)
+	require.Len(t, actions, 1)
+	require.Equal(t, "Add required input name (string)", actions[0].Title)
+	require.True(t, actions[0].IsPreferred)
+	fixed := applyTextEdits(t, source, actions[0].Edit.Changes[PathToFileURI(path)])
+	require.Equal(t, codeActionGolden(t, "missing-input"), fixed)
+	requireNoDiagnostic(t, root, path, fixed, lang.CodeMissingInput)
+}
+
+func TestCodeActionReplacesUnknownCoreFunction(t *testing.T) {
+	root, path, source := codeActionProject(t, "unknown-core-function")
+
+	actions := codeActionsFor(t, root, path, source, lang.CodeUnknownCoreFunction)
+	require.Len(t, actions, 1)
+	require.Equal(t, "Change to @core.to-string", actions[0].Title)
+	fixed := applyTextEdits(t, source, actions[0].Edit.Changes[PathToFileURI(path)])
+	require.Equal(t, codeActionGolden(t, "unknown-core-function"), fixed)
+}
+
+func TestCodeActionFormatsUnformattedFile(t *testing.T) {
+	root, path, source := codeActionProject(t, "unformatted")
+	documents := NewDocumentStore()
+	doc, err := documents.Open(PathToFileURI(path), 1, source)
+	require.NoError(t, err)
+
+	diagnostic := requireDiagnostic(t,
+		DocumentDiagnostics(doc, NewProjectCache(root), documents), codeUnformatted)
+	require.Equal(t, protocol.DiagnosticSeverityHint, diagnostic.Severity)
+	actions, rpcErr := CodeActionsForText(path, source,
+		[]protocol.Diagnostic{diagnostic}, NewProjectCache(root), documents)
+	require.Nil(t, rpcErr)
+	require.Len(t, actions, 1)
+	require.Equal(t, "Format file", actions[0].Title)
+	fixed := applyTextEdits(t, source, actions[0].Edit.Changes[PathToFileURI(path)])
+	require.Equal(t, codeActionGolden(t, "unformatted"), fixed)
+	edits, rpcErr := FormatText(path, fixed)
+	require.Nil(t, rpcErr)
+	require.Empty(t, edits)
+}
+
+func TestCodeActionAddsStateMoveForRenamedNode(t *testing.T) {
+	root, path, source := codeActionProject(t, "renamed-node")
+	documents := NewDocumentStore()
+	uri := PathToFileURI(path)
+	_, err := documents.Open(uri, 1, source)
+	require.NoError(t, err)
+	renamed := replaceOnce(t, source, "server: bundle.web", "api: bundle.web")
+	renamed = replaceOnce(t, renamed, "resource.server.id", "resource.api.id")
+	doc, err := documents.Change(uri, 2, renamed)
+	require.NoError(t, err)
+
+	diagnostic := requireDiagnostic(t,
+		DocumentDiagnostics(doc, NewProjectCache(root), documents), codeRenamedNode)
+	start, ok := LSPToOffset(renamed, diagnostic.Range.Start)
+	require.True(t, ok)
+	require.Equal(t, offsetInText(renamed, "api: bundle", "api"), start)
+
+	actions, rpcErr := CodeActionsForText(path, renamed,
+		[]protocol.Diagnostic{roundTrip(t, diagnostic)}, NewProjectCache(root), documents)
+	require.Nil(t, rpcErr)
+	require.Len(t, actions, 1)
+	require.Equal(t, "Add state move from resource.server to resource.api", actions[0].Title)
+	fixed := applyTextEdits(t, renamed, actions[0].Edit.Changes[uri])
+	require.Equal(t, codeActionGolden(t, "renamed-node"), fixed)
+
+	doc, err = documents.Change(uri, 3, fixed)
+	require.NoError(t, err)
+	for _, diagnostic := range DocumentDiagnostics(doc, NewProjectCache(root), documents) {
+		require.NotEqual(t, protocol.DiagnosticCode(codeRenamedNode), diagnostic.Code)
+	}
+}
+
+func TestCodeActionIgnoresUncodedDiagnostics(t *testing.T) {
+	root, path, source := codeActionProject(t, "unformatted")
+
+	actions, rpcErr := CodeActionsForText(path, source, []protocol.Diagnostic{{
+		Severity: protocol.DiagnosticSeverityError,
+		Message:  "file is not formatted",
+	}}, NewProjectCache(root), nil)
+	require.Nil(t, rpcErr)
+	require.Empty(t, actions)
+}
+
+func TestSessionCodeActionReturnsQuickFixes(t *testing.T) {
+	root, path, source := codeActionProject(t, "unknown-core-function")
+	session := NewSession("dev")
+	session.projects = NewProjectCache(root)
+	uri := PathToFileURI(path)
+	require.Nil(t, openDocument(t, session, uri, 1, source))
+	diagnostic := requireDiagnostic(t,
+		DiagnosticsForTextWithProjects(path, source, session.projects), lang.CodeUnknownCoreFunction)
+
+	result, rpcErr := sessionRequest(t, session, "textDocument/codeAction", protocol.CodeActionParams{
+		TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+		Range:        diagnostic.Range,
+		Context: protocol.CodeActionContext{
+			Diagnostics: []protocol.Diagnostic{roundTrip(t, diagnostic)},
+		},
+	})
+	require.Nil(t, rpcErr)
+	actions, ok := result.([]protocol.CodeAction)
+	require.True(t, ok)
+	require.Len(t, actions, 1)
+	require.Equal(t, "Change to @core.to-string", actions[0].Title)
+}
+
+// codeActionProject writes the named code action fixture as the
+// factory of the references project, beside its `bundle` library.
+func codeActionProject(t *testing.T, name string) (string, string, string) {
+	t.Helper()
+	root, path, _, _ := referencesProject(t)
+	source := ubtest.ReadFixture(t, filepath.Join("testdata/ub/codeactions/valid", name+".ub"))
+	require.NoError(t, os.WriteFile(path, []byte(source), 0o644))
+	return root, path, source
+}
+
+// codeActionGolden returns the named code action fixture as its fix
+// leaves it.
+func codeActionGolden(t *testing.T, name string) string {
+	t.Helper()
+	return ubtest.ReadFixture(t, filepath.Join("testdata/ub/codeactions/valid", name+".ub.out"))
+}
+
+// codeActionsFor returns the code actions for the diagnostic with code
+// that checking source reports, sent as the client would send it back.
+func codeActionsFor(t *testing.T, root, path, source, code string) []protocol.CodeAction {
+	t.Helper()
+	projects := NewProjectCache(root)
+	diagnostic := requireDiagnostic(t, DiagnosticsForTextWithProjects(path, source, projects), code)
+	actions, rpcErr := CodeActionsForText(path, source,
+		[]protocol.Diagnostic{roundTrip(t, diagnostic)}, projects, nil)
+	require.Nil(t, rpcErr)
+	return actions
+}
+
+func requireDiagnostic(t *testing.T, diagnostics []protocol.Diagnostic, code string) protocol.Diagnostic {
+	t.Helper()
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Code == protocol.DiagnosticCode(code) {
+			return diagnostic
+		}
+	}
+	require.Failf(t, "missing diagnostic", "no %s in %v", code, diagnostics)
+	return protocol.Diagnostic{}
+}
+
+func requireNoDiagnostic(t *testing.T, root, path, source, code string) {
+	t.Helper()
+	for _, diagnostic := range DiagnosticsForTextWithProjects(path, source, NewProjectCache(root)) {
+		require.NotEqual(t, protocol.DiagnosticCode(code), diagnostic.Code, diagnostic.Message)
+	}
+}
+
+// roundTrip passes diagnostic through JSON, so its data arrives as a
+// client sends it back.
+func roundTrip(t *testing.T, diagnostic protocol.Diagnostic) protocol.Diagnostic {
+	t.Helper()
+	raw, err := json.Marshal(diagnostic)
+	require.NoError(t, err)
+	var out protocol.Diagnostic
+	require.NoError(t, json.Unmarshal(raw, &out))
+	return out
+}
+
+func replaceOnce(t *testing.T, text, old, new string) string {
+	t.Helper()
+	start := offsetInText(text, old, old)
+	return text[:start] + new + text[start+len(old):]
+}
@@ -1,14 +1,17 @@
 package lsp
 
 import (
+	"maps"
 	"os"
 	"path/filepath"
 	"slices"
 	"strings"
 
 	"github.com/cloudboss/unobin/pkg/check"
+	"github.com/cloudboss/unobin/pkg/lang"
 	"github.com/cloudboss/unobin/pkg/lang/parse"
 	"github.com/cloudboss/unobin/pkg/lang/syntax"
+	"github.com/cloudboss/unobin/pkg/lint"
 	"github.com/cloudboss/unobin/pkg/lsp/protocol"
 	"github.com/cloudboss/unobin/pkg/resolve"
 	ubruntime "github.com/cloudboss/unobin/pkg/runtime"
@@ -29,6 +32,9 @@ func CompleteForText(
 	if projects == nil {
 		projects = NewProjectCache("")
 	}
+	if list, ok := stackCompletions(path, text, offset); ok {
+		return list, nil
+	}
 	if list, ok := completionForSourceContext(text, offset); ok {
 		return list, nil
 	}
@@ -84,6 +90,12 @@ func completionForSourceContext(
 	if projectRequirementCompletionContext(text, offset) {
 		return completionList(projectRequirementCompletionItems()), true
 	}
+	if projectLintCompletionContext(text, offset) {
+		return completionList(projectLintCompletionItems()), true
+	}
+	if projectLintSeverityCompletionContext(text, offset) {
+		return completionList(projectLintSeverityCompletionItems()), true
+	}
 	if projectBlockCompletionContext(text, offset) {
 		return completionList(projectBlockCompletionItems()), true
 	}
@@ -419,11 +431,29 @@ func skipSingleQuotedString(text string, offset int) int {
 	return offset
 }
 
+var projectRequirementFields = []string{"version", "indirect", "allow-prerelease", "exclude"}
+
 func projectRequirementCompletionContext(text string, offset int) bool {
 	if !insideNamedBlock(text, offset, "requires") {
 		return false
 	}
-	return completionCandidateMatches(text, offset, []string{"version", "indirect"})
+	return completionCandidateMatches(text, offset, projectRequirementFields)
+}
+
+func projectLintCompletionContext(text string, offset int) bool {
+	return insideNamedBlock(text, offset, "lint") &&
+		nearestProjectChildBlockName(text, offset) == "lint" &&
+		strings.TrimSpace(currentLinePrefix(text, offset)) == ""
+}
+
+func projectLintSeverityCompletionContext(text string, offset int) bool {
+	if !insideNamedBlock(text, offset, "lint") ||
+		nearestProjectChildBlockName(text, offset) != "lint" {
+		return false
+	}
+	code, ok := strings.CutSuffix(strings.TrimSpace(currentLinePrefix(text, offset)), ":")
+	_, known := lint.Rules()[code]
+	return ok && known
 }
 
 func projectBlockCompletionContext(text string, offset int) bool {
@@ -929,7 +959,7 @@ func fileRoleCompletionItems() []protocol.CompletionItem {
 func factoryBlockCompletionItems() []protocol.CompletionItem {
 	return keywordCompletionItems(
 		"assets", "inputs", "imports", "library-configs", "resources", "data-sources",
-		"actions", "outputs", "constraints", "state-moves", "locals",
+		"actions", "outputs", "constraints", "checks", "state-moves", "locals", "types",
 	)
 }
 
@@ -938,19 +968,49 @@ func stackBlockCompletionItems() []protocol.CompletionItem {
 }
 
 func projectBlockCompletionItems() []protocol.CompletionItem {
-	return keywordCompletionItems("unobin-version", "requires", "replace")
+	return keywordCompletionItems("unobin-version", "requires", "replace", "lint")
+}
+
+// projectLintCompletionItems offers the lint rule codes a project
+// `lint:` block can set a severity for.
+func projectLintCompletionItems() []protocol.CompletionItem {
+	rules := lint.Rules()
+	items := make([]protocol.CompletionItem, 0, len(rules))
+	for _, code := range slices.Sorted(maps.Keys(rules)) {
+		rule := rules[code]
+		items = append(items, protocol.CompletionItem{
+			Label:  code,
+			Kind:   protocol.CompletionItemKindField,
+			Detail: string(rule.Severity),
+			Documentation: &protocol.MarkupContent{
+				Kind: protocol.MarkupKindPlainText, Value: rule.Description,
+			},
+		})
+	}
+	return items
+}
+
+func projectLintSeverityCompletionItems() []protocol.CompletionItem {
+	items := make([]protocol.CompletionItem, 0, len(lang.LintSeverities))
+	for _, severity := range lang.LintSeverities {
+		items = append(items, protocol.CompletionItem{
+			Label: "'" + severity + "'",
+			Kind:  protocol.CompletionItemKindEnumMember,
+		})
+	}
+	return items
 }
 
 func projectRequirementCompletionItems() []protocol.CompletionItem {
-	return keywordCompletionItems("version", "indirect")
+	return keywordCompletionItems(projectRequirementFields...)
 }
 
 func stackStateCompletionItems() []protocol.CompletionItem {
-	return keywordCompletionItems("gcs", "local", "s3")
+	return stackResolverCompletionItems("state")
 }
 
 func stackEncryptionCompletionItems() []protocol.CompletionItem {
-	return keywordCompletionItems("env-key", "gcp-kms", "kms", "noop")
+	return stackResolverCompletionItems("encryption")
 }
 
 func inputDeclarationCompletionItems(text string, offset int) []protocol.CompletionItem {
@@ -1115,7 +1175,7 @@ func selectorKindAtOffset(body *syntax.FactoryBody, offset int) syntax.NodeKind
 func nearestFactoryChildBlockName(text string, offset int) string {
 	return nearestBlockNameFrom(text, offset, []string{
 		"assets", "inputs", "imports", "library-configs", "resources", "data-sources",
-		"actions", "outputs", "constraints", "state-moves", "locals",
+		"actions", "outputs", "constraints", "checks", "state-moves", "locals", "types",
 	})
 }
 
@@ -1130,7 +1190,7 @@ func factoryBodyTopLevelCompletionContext(
 	}
 	for _, name := range []string{
 		"assets", "inputs", "imports", "library-configs", "resources", "data-sources",
-		"actions", "outputs", "constraints", "state-moves", "locals",
+		"actions", "outputs", "constraints", "checks", "state-moves", "locals", "types",
 	} {
 		if insideNamedBlock(text, offset, name) {
 			return false
@@ -1147,7 +1207,7 @@ func nearestStackChildBlockName(text string, offset int) string {
 
 func nearestProjectChildBlockName(text string, offset int) string {
 	return nearestBlockNameFrom(text, offset, []string{
-		"unobin-version", "requires", "replace",
+		"unobin-version", "requires", "replace", "lint",
 	})
 }
 
@@ -90,7 +90,7 @@ func TestCompletionProjectKeys(t *testing.T) {
 
 	list, rpcErr := CompleteForText(path, source, pos, NewProjectCache(root))
 	require.Nil(t, rpcErr)
-	requireCompletionLabels(t, list, "unobin-version", "requires", "replace")
+	requireCompletionLabels(t, list, "unobin-version", "requires", "replace", "lint")
 }
 
 func TestCompletionProjectRequirementKeys(t *testing.T) {
@@ -101,7 +101,7 @@ func TestCompletionProjectRequirementKeys(t *testing.T) {
 
 	list, rpcErr := CompleteForText(path, source, pos, NewProjectCache(root))
 	require.Nil(t, rpcErr)
-	requireCompletionLabels(t, list, "version", "indirect")
+	requireCompletionLabels(t, list, "version", "indirect", "allow-prerelease", "exclude")
 }
 
 func TestCompletionInputDeclarationKeys(t *testing.T) {
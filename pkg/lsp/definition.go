@@ -31,6 +31,9 @@ func DefinitionForText(
 		projects = NewProjectCache("")
 	}
 	body, hasScope := definitionBodyForOffset(file, offset)
+	if locations, ok := typeNameDefinition(path, text, offset, file, body); ok {
+		return locations, nil
+	}
 	decls := definitionDeclsForBody(body)
 	if hasScope {
 		locations, found, err := definitionAtOffset(
@@ -259,6 +262,12 @@ func typeLibraryConfigAtOffset(
 				return lib, true
 			}
 		}
+	case *parse.TypeUnion:
+		for _, variant := range v.Variants {
+			if lib, ok := typeLibraryConfigAtOffset(text, variant.Type, offset); ok {
+				return lib, true
+			}
+		}
 	}
 	return nil, false
 }
@@ -38,6 +38,24 @@ func TestDefinitionLocalName(t *testing.T) {
 		"name: input.region", "name")
 }
 
+func TestDefinitionTypeName(t *testing.T) {
+	root := writeUBProject(t, nil, nil)
+	source := ubtest.ReadFixture(t, "testdata/ub/definition/valid/types-factory.ub")
+	path := filepath.Join(root, "factory.ub")
+	require.NoError(t, os.WriteFile(path, []byte(source), 0o644))
+	cache := NewProjectCache(root)
+
+	locations, rpcErr := DefinitionForText(path, source,
+		positionInText(source, "list(listener)", "listener"), cache)
+	require.Nil(t, rpcErr)
+	requireDefinitionLocation(t, locations, path, source, "listener: object", "listener")
+
+	locations, rpcErr = DefinitionForText(path, source,
+		positionInText(source, "port: port,", "port,"), cache)
+	require.Nil(t, rpcErr)
+	requireDefinitionLocation(t, locations, path, source, "port: integer", "port")
+}
+
 func TestDefinitionAssetName(t *testing.T) {
 	root, path, source := assetEditorProject(t, "factory")
 
@@ -2,9 +2,11 @@ package lsp
 
 import (
 	"errors"
+	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/cloudboss/unobin/pkg/check"
@@ -43,11 +45,188 @@ func DiagnosticsForTextWithProjects(
 		return diagnosticsForFactoryBody(path, text, file.Factory.Body, projects)
 	case syntax.FileLibrary:
 		return diagnosticsForLibraryFile(path, text, file.Library, projects)
+	case syntax.FileStack:
+		return diagnosticsForStackFile(path, text, file.Stack)
+	case syntax.FileProject:
+		return diagnosticsForProjectLint(text, file.Project)
 	default:
 		return nil
 	}
 }
 
+// Codes for the diagnostics the language server adds to the checker's
+// for an open document.
+const (
+	// codeUnformatted is a document that formatting would change.
+	codeUnformatted = "unobin.unformatted"
+	// codeRenamedNode is a node renamed since the document was opened
+	// with no state move to follow it. Data "kind", "from" and "to"
+	// name the node kind and its old and new names.
+	codeRenamedNode = "unobin.renamed-node"
+)
+
+// DocumentDiagnostics returns the diagnostics for an open document: the
+// checker's, and when it has no errors, lint findings and hints that it
+// is unformatted or that a node with state was renamed since the
+// document was opened.
+func DocumentDiagnostics(
+	doc *Document,
+	projects *ProjectCache,
+	documents *DocumentStore,
+) []protocol.Diagnostic {
+	diagnostics := DiagnosticsForTextWithProjects(doc.Path, doc.Text, projects)
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Severity == protocol.DiagnosticSeverityError {
+			return diagnostics
+		}
+	}
+	diagnostics = append(diagnostics, lintDiagnostics(doc.Path, doc.Text, projects)...)
+	if edits, err := FormatText(doc.Path, doc.Text); err == nil && len(edits) > 0 {
+		diagnostics = append(diagnostics, protocol.Diagnostic{
+			Severity: protocol.DiagnosticSeverityHint,
+			Code:     codeUnformatted,
+			Source:   "unobin",
+			Message:  "file is not formatted",
+		})
+	}
+	return append(diagnostics,
+		renamedNodeDiagnostics(doc.Path, doc.Opened, doc.Text, projects, documents)...)
+}
+
+// renamedNodeDiagnostics reports each node with state that was renamed
+// since opened: a node whose name opened did not declare, with the
+// selector and body of a node opened declared that text no longer
+// does. State moves already written from the old name silence it.
+func renamedNodeDiagnostics(
+	path string,
+	opened string,
+	text string,
+	projects *ProjectCache,
+	documents *DocumentStore,
+) []protocol.Diagnostic {
+	if opened == text {
+		return nil
+	}
+	before, err := syntax.ParseSource(path, []byte(opened))
+	if err != nil {
+		return nil
+	}
+	refs := newRefIndex(projects, documents)
+	src, ok := refs.add(path, text)
+	if !ok {
+		return nil
+	}
+	var out []protocol.Diagnostic
+	for _, pair := range renamedBodyPairs(before, src.file) {
+		out = append(out, renamedNodes(refs, src, opened, pair)...)
+	}
+	return out
+}
+
+// renamedBodyPair is one body as opened and as it is now, with the
+// body's refKey id in the current text.
+type renamedBodyPair struct {
+	before *syntax.FactoryBody
+	after  *syntax.FactoryBody
+	id     int
+}
+
+// renamedBodyPairs pairs the factory bodies, and the composites of the
+// same kind and name, of two versions of a file.
+func renamedBodyPairs(before, after *syntax.File) []renamedBodyPair {
+	var pairs []renamedBodyPair
+	if before.Factory != nil && after.Factory != nil {
+		pairs = append(pairs, renamedBodyPair{
+			before: &before.Factory.Body, after: &after.Factory.Body, id: -1,
+		})
+	}
+	if before.Library == nil || after.Library == nil {
+		return pairs
+	}
+	for i := range after.Library.Exports {
+		now := &after.Library.Exports[i]
+		for j := range before.Library.Exports {
+			then := &before.Library.Exports[j]
+			if then.Kind == now.Kind && then.Name.Name == now.Name.Name {
+				pairs = append(pairs, renamedBodyPair{
+					before: &then.Body, after: &now.Body, id: now.Name.S.Start.Offset,
+				})
+				break
+			}
+		}
+	}
+	return pairs
+}
+
+func renamedNodes(
+	refs *refIndex,
+	src *refSource,
+	opened string,
+	pair renamedBodyPair,
+) []protocol.Diagnostic {
+	declared := func(nodes []syntax.NodeDecl, node syntax.NodeDecl) bool {
+		return slices.ContainsFunc(nodes, func(other syntax.NodeDecl) bool {
+			return other.Kind == node.Kind && other.Name.Name == node.Name.Name
+		})
+	}
+	beforeNodes := allNodes(*pair.before)
+	afterNodes := allNodes(*pair.after)
+	var out []protocol.Diagnostic
+	for _, node := range afterNodes {
+		if declared(beforeNodes, node) {
+			continue
+		}
+		var from []string
+		for _, old := range beforeNodes {
+			if old.Kind == node.Kind && !declared(afterNodes, old) &&
+				old.Selector.Alias.Name == node.Selector.Alias.Name &&
+				old.Selector.Export.Name == node.Selector.Export.Name &&
+				nodeBodyText(opened, old) == nodeBodyText(src.text, node) {
+				from = append(from, old.Name.Name)
+			}
+		}
+		if len(from) != 1 || nodeBodyText(src.text, node) == "" {
+			continue
+		}
+		key := refKey{
+			kind: refNode, name: node.Name.Name, nodeKind: node.Kind,
+			path: src.path, body: pair.id, scope: -1,
+		}
+		if !refs.hasState(key) || slices.ContainsFunc(pair.after.StateMoves,
+			func(move syntax.StateMoveDecl) bool {
+				return move.From != nil && move.From.Ref.String() == string(node.Kind)+"."+from[0]
+			}) {
+			continue
+		}
+		out = append(out, protocol.Diagnostic{
+			Range:    identOccurrence(src, node.Name, true).lspRange(),
+			Severity: protocol.DiagnosticSeverityInformation,
+			Code:     codeRenamedNode,
+			Source:   "unobin",
+			Message: fmt.Sprintf(
+				"%s.%s was renamed to %s.%s; without a state move, apply replaces it",
+				node.Kind, from[0], node.Kind, node.Name.Name),
+			Data: map[string]string{
+				"kind": string(node.Kind), "from": from[0], "to": node.Name.Name,
+			},
+		})
+	}
+	return out
+}
+
+// nodeBodyText returns a node body's source with whitespace collapsed,
+// so a body that was only reindented still matches.
+func nodeBodyText(text string, node syntax.NodeDecl) string {
+	if node.Body == nil {
+		return ""
+	}
+	start, end := node.Body.S.Start.Offset, node.Body.S.End.Offset
+	if start < 0 || end <= start || end > len(text) {
+		return ""
+	}
+	return strings.Join(strings.Fields(text[start:end]), " ")
+}
+
 func diagnosticsForFactoryBody(
 	path string,
 	text string,
@@ -126,6 +305,7 @@ func diagnosticSourceCheckOptions(
 		Resolver:       project.Resolver,
 		Versions:       versions,
 		SchemaCache:    diagnosticSchemaCache(project),
+		Cache:          project.Checks,
 		Mode:           sourcecheck.ModeNoFetch,
 	}, true, nil
 }
@@ -332,15 +512,20 @@ func diagnosticsForParseFailure(text string, err error) []protocol.Diagnostic {
 
 func diagnosticFromParseError(text string, err *parse.Error) protocol.Diagnostic {
 	pos := OffsetToLSP(text, err.Pos.Offset)
-	return protocol.Diagnostic{
+	diagnostic := protocol.Diagnostic{
 		Range: protocol.Range{
 			Start: pos,
 			End:   pos,
 		},
 		Severity: diagnosticSeverity(err.Kind),
+		Code:     protocol.DiagnosticCode(err.Code),
 		Source:   "unobin",
 		Message:  diagnosticMessage(err),
 	}
+	if len(err.Data) > 0 {
+		diagnostic.Data = err.Data
+	}
+	return diagnostic
 }
 
 func diagnosticSeverity(kind parse.ErrorKind) protocol.DiagnosticSeverity {
@@ -17,6 +17,7 @@ import (
 	"github.com/cloudboss/unobin/pkg/lang/parse"
 	"github.com/cloudboss/unobin/pkg/lsp/protocol"
 	"github.com/cloudboss/unobin/pkg/resolve"
+	"github.com/cloudboss/unobin/pkg/sourcecheck"
 )
 
 func TestDiagnosticFromSingleParseError(t *testing.T) {
@@ -176,6 +177,34 @@ func BenchmarkDiagnosticsForTextWithProjectsLargeFactory(b *testing.B) {
 	}
 }
 
+// BenchmarkDiagnosticsForTextWithProjectsUBLibrary re-checks a factory
+// that imports a large UB library, as each didChange does. The cold run
+// drops the project's check cache before every check.
+func BenchmarkDiagnosticsForTextWithProjectsUBLibrary(b *testing.B) {
+	root, sourcePath, source := largeDiagnosticUBProject(b, 200)
+	for _, cold := range []bool{true, false} {
+		name := "cached"
+		if cold {
+			name = "cold"
+		}
+		b.Run(name, func(b *testing.B) {
+			cache := NewProjectCache(root)
+			project, err := cache.ProjectForPath(sourcePath)
+			require.NoError(b, err)
+			b.ReportAllocs()
+			for b.Loop() {
+				if cold {
+					project.Checks = sourcecheck.NewCache()
+				}
+				diags := DiagnosticsForTextWithProjects(sourcePath, source, cache)
+				if len(diags) > 0 {
+					b.Fatalf("unexpected diagnostics: %v", diagnosticMessages(diags))
+				}
+			}
+		})
+	}
+}
+
 func TestSessionDidOpenPublishesParseDiagnostic(t *testing.T) {
 	session, sent := newDiagnosticSession(t)
 	src := ubtest.ReadFixture(t, "testdata/ub/diagnostics/invalid/parse-error.ub")
@@ -492,6 +521,32 @@ func largeDiagnosticProject(b *testing.B, nodes int) (string, string, string) {
 	return root, sourcePath, source
 }
 
+func largeDiagnosticUBProject(b *testing.B, composites int) (string, string, string) {
+	b.Helper()
+	root := b.TempDir()
+	require.NoError(b, deps.WriteProject(filepath.Join(root, deps.ProjectFileName), &deps.Project{
+		Requires: map[deps.Dependency]deps.Requirement{},
+	}))
+	composite, err := os.ReadFile(filepath.Join("testdata", "large-diagnostic-library.ub.tmpl"))
+	require.NoError(b, err)
+	factory, err := os.ReadFile(filepath.Join("testdata", "large-diagnostic-ub-factory.ub.tmpl"))
+	require.NoError(b, err)
+	var library, nodeText strings.Builder
+	for i := range composites {
+		name := fmt.Sprintf("item-%03d", i)
+		library.WriteString(strings.ReplaceAll(string(composite), "{{name}}", name))
+		fmt.Fprintf(&nodeText, "    %s: lib.%s {}\n", name, name)
+	}
+	libraryDir := filepath.Join(root, "lib")
+	require.NoError(b, os.MkdirAll(libraryDir, 0o755))
+	require.NoError(b, os.WriteFile(filepath.Join(libraryDir, "library.ub"),
+		[]byte(library.String()), 0o644))
+	source := strings.ReplaceAll(string(factory), "{{nodes}}", nodeText.String())
+	sourcePath := filepath.Join(root, "factory.ub")
+	require.NoError(b, os.WriteFile(sourcePath, []byte(source), 0o644))
+	return root, sourcePath, source
+}
+
 func largeDiagnosticFactory(b *testing.B, nodes int) string {
 	b.Helper()
 	template, err := os.ReadFile(filepath.Join("testdata", "large-diagnostic-factory.ub.tmpl"))
@@ -6,12 +6,14 @@ import (
 	"path/filepath"
 )
 
-// Document is the open-text state for one LSP text document.
+// Document is the open-text state for one LSP text document. Opened
+// is its text when it was opened, which edits since are compared with.
 type Document struct {
 	URI     string
 	Path    string
 	Version int32
 	Text    string
+	Opened  string
 	Lines   []LineInfo
 }
 
@@ -43,13 +45,15 @@ func (s *DocumentStore) Open(uri string, version int32, text string) (*Document,
 
 // Change replaces an open document with a full text snapshot.
 func (s *DocumentStore) Change(uri string, version int32, text string) (*Document, error) {
-	if _, ok := s.documents[uri]; !ok {
+	prev, ok := s.documents[uri]
+	if !ok {
 		return nil, fmt.Errorf("document is not open: %s", uri)
 	}
 	doc, err := newDocument(uri, version, text)
 	if err != nil {
 		return nil, err
 	}
+	doc.Opened = prev.Opened
 	s.documents[uri] = doc
 	return doc, nil
 }
@@ -65,6 +69,20 @@ func (s *DocumentStore) Get(uri string) (*Document, bool) {
 	return doc, ok
 }
 
+// GetPath returns the open document snapshot for a local path.
+func (s *DocumentStore) GetPath(path string) (*Document, bool) {
+	if s == nil {
+		return nil, false
+	}
+	path = filepath.Clean(path)
+	for _, doc := range s.documents {
+		if filepath.Clean(doc.Path) == path {
+			return doc, true
+		}
+	}
+	return nil, false
+}
+
 // FileURIToPath converts a file URI to a local path.
 func FileURIToPath(rawURI string) (string, error) {
 	u, err := url.Parse(rawURI)
@@ -102,6 +120,7 @@ func newDocument(uri string, version int32, text string) (*Document, error) {
 		Path:    path,
 		Version: version,
 		Text:    text,
+		Opened:  text,
 		Lines:   buildLineInfo(text),
 	}, nil
 }
@@ -0,0 +1,133 @@
+package lsp
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/cloudboss/unobin/pkg/lang"
+	"github.com/cloudboss/unobin/pkg/lang/syntax"
+	"github.com/cloudboss/unobin/pkg/lsp/protocol"
+)
+
+// nodeBlockNames maps a node kind to the body block that declares it,
+// and bodyBlockOrder lists the body blocks in the order they are
+// written, for placing a new block among them.
+var (
+	nodeBlockNames = map[syntax.NodeKind]string{
+		syntax.NodeResource:   "resources",
+		syntax.NodeDataSource: "data-sources",
+		syntax.NodeAction:     "actions",
+	}
+	bodyBlockOrder = []string{
+		"description", "assets", "types", "inputs", "imports", "library-configs",
+		"locals", "constraints", "state-moves", "data-sources", "resources",
+		"actions", "outputs", "checks",
+	}
+)
+
+// blocksAfter returns the body blocks written after block.
+func blocksAfter(block string) []string {
+	for i, name := range bodyBlockOrder {
+		if name == block {
+			return bodyBlockOrder[i+1:]
+		}
+	}
+	return nil
+}
+
+// insertEdit returns the edit that inserts insert at offset at.
+func insertEdit(text string, at int, insert string) protocol.TextEdit {
+	pos := OffsetToLSP(text, at)
+	return protocol.TextEdit{Range: protocol.Range{Start: pos, End: pos}, NewText: insert}
+}
+
+// rawObjectAt finds the parsed object that starts at offset.
+func rawObjectAt(root lang.Expr, offset int) *lang.ObjectLit {
+	var found *lang.ObjectLit
+	lang.Walk(root, func(e lang.Expr) {
+		if obj, ok := e.(*lang.ObjectLit); ok && found == nil && obj.S.Start.Offset == offset {
+			found = obj
+		}
+	})
+	return found
+}
+
+// objectField returns the field of obj named name.
+func objectField(obj *lang.ObjectLit, name string) *lang.Field {
+	for _, field := range obj.Fields {
+		if field.Key.Kind == lang.FieldIdent && field.Key.Name == name {
+			return field
+		}
+	}
+	return nil
+}
+
+// objectFieldInsertion returns where to insert field, which may span
+// lines, into obj and the text to insert: before the first field named
+// in before, or else after the last field. A field placed on its own
+// line takes the indentation of its neighbours, and a block spanning
+// lines is set off from the next one by a blank line.
+func objectFieldInsertion(
+	text string,
+	obj *lang.ObjectLit,
+	field string,
+	before []string,
+) (int, string) {
+	for _, next := range obj.Fields {
+		if next.Key.Kind != lang.FieldIdent || !slices.Contains(before, next.Key.Name) {
+			continue
+		}
+		at := next.Key.S.Start.Offset
+		lineStart := strings.LastIndexByte(text[:at], '\n') + 1
+		if strings.TrimSpace(text[lineStart:at]) != "" {
+			return at, strings.ReplaceAll(field, "\n", " ") + " "
+		}
+		indent := text[lineStart:at]
+		insert := indent + indentLines(field, indent) + "\n"
+		if strings.Contains(field, "\n") {
+			insert += "\n"
+		}
+		return lineStart, insert
+	}
+	closing := obj.S.End.Offset - 1
+	if closing < obj.S.Start.Offset || closing >= len(text) || text[closing] != '}' {
+		closing = obj.S.Start.Offset + 1
+	}
+	lineStart := strings.LastIndexByte(text[:closing], '\n') + 1
+	if lineStart <= obj.S.Start.Offset || strings.TrimSpace(text[lineStart:closing]) != "" {
+		insert := strings.ReplaceAll(field, "\n", " ") + " "
+		if closing > 0 && !isSpaceByte(text[closing-1]) {
+			insert = " " + insert
+		}
+		return closing, insert
+	}
+	indent := text[lineStart:closing] + "  "
+	if len(obj.Fields) > 0 {
+		indent = lineIndent(text, obj.Fields[0].Key.S.Start.Offset)
+	}
+	return lineStart, indent + indentLines(field, indent) + "\n"
+}
+
+// indentLines indents every line of s after the first by indent.
+func indentLines(s string, indent string) string {
+	return strings.ReplaceAll(s, "\n", "\n"+indent)
+}
+
+// lineIndent returns the whitespace that starts the line holding offset.
+func lineIndent(text string, offset int) string {
+	start := strings.LastIndexByte(text[:offset], '\n') + 1
+	end := start
+	for end < offset && (text[end] == ' ' || text[end] == '\t') {
+		end++
+	}
+	return text[start:end]
+}
+
+// skipBlank returns the first offset at or after i that is not
+// whitespace.
+func skipBlank(text string, i int) int {
+	for i < len(text) && isSpaceByte(text[i]) {
+		i++
+	}
+	return i
+}
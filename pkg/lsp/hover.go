@@ -26,10 +26,16 @@ func HoverForText(
 	if err != nil {
 		return nil, nil
 	}
+	if file.Kind == syntax.FileStack && file.Stack != nil {
+		return stackHover(path, text, file.Stack, offset), nil
+	}
 	if projects == nil {
 		projects = NewProjectCache("")
 	}
 	body, hasScope := definitionBodyForOffset(file, offset)
+	if hover, ok := typeNameHover(file, body, offset); ok {
+		return hover, nil
+	}
 	decls := definitionDeclsForBody(body)
 	if hasScope {
 		hover, found, err := hoverAtOffset(
@@ -10,6 +10,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/cloudboss/unobin/internal/ubtest"
 	"github.com/cloudboss/unobin/pkg/lsp/protocol"
 )
 
@@ -24,6 +25,20 @@ func TestHoverInputTypeAndDescription(t *testing.T) {
 	require.Contains(t, hover.Contents.Value, "AWS region to use.")
 }
 
+func TestHoverTypeName(t *testing.T) {
+	root := writeUBProject(t, nil, nil)
+	source := ubtest.ReadFixture(t, "testdata/ub/definition/valid/types-factory.ub")
+	path := filepath.Join(root, "factory.ub")
+	require.NoError(t, os.WriteFile(path, []byte(source), 0o644))
+
+	hover, rpcErr := HoverForText(path, source,
+		positionInText(source, "list(listener)", "listener"), NewProjectCache(root))
+	require.Nil(t, rpcErr)
+	require.NotNil(t, hover)
+	require.Equal(t,
+		"type listener: object({\n  port: port\n  protocol: optional(string)\n})", hover.Contents.Value)
+}
+
 func TestHoverLocalInferredType(t *testing.T) {
 	root, path, source := completionProject(t)
 
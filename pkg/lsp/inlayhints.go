@@ -0,0 +1,116 @@
+package lsp
+
+import (
+	"slices"
+
+	"github.com/cloudboss/unobin/pkg/check"
+	"github.com/cloudboss/unobin/pkg/lang"
+	"github.com/cloudboss/unobin/pkg/lang/parse"
+	"github.com/cloudboss/unobin/pkg/lang/syntax"
+	"github.com/cloudboss/unobin/pkg/lsp/protocol"
+	"github.com/cloudboss/unobin/pkg/typecheck"
+)
+
+// InlayHintsForText shows the inferred type of each local, after its
+// name, and of each comprehension that is not itself a local's value,
+// after its closing bracket. Only hints inside rng are returned.
+func InlayHintsForText(
+	path string,
+	text string,
+	rng protocol.Range,
+	projects *ProjectCache,
+) ([]protocol.InlayHint, *protocol.ResponseError) {
+	start, ok := LSPToOffset(text, rng.Start)
+	if !ok {
+		return nil, protocol.InvalidParams("invalid document range")
+	}
+	end, ok := LSPToOffset(text, rng.End)
+	if !ok {
+		return nil, protocol.InvalidParams("invalid document range")
+	}
+	file, err := syntax.ParseSource(path, []byte(text))
+	if err != nil {
+		return []protocol.InlayHint{}, nil
+	}
+	if projects == nil {
+		projects = NewProjectCache("")
+	}
+	var bodies []*syntax.FactoryBody
+	if file.Factory != nil {
+		bodies = append(bodies, &file.Factory.Body)
+	}
+	if file.Library != nil {
+		for i := range file.Library.Exports {
+			bodies = append(bodies, &file.Library.Exports[i].Body)
+		}
+	}
+	hints := []protocol.InlayHint{}
+	for _, body := range bodies {
+		if body.S.End.Offset > 0 && (body.S.End.Offset < start || body.S.Start.Offset > end) {
+			continue
+		}
+		bodyHints, err := bodyInlayHints(path, text, body, projects)
+		if err != nil {
+			return nil, protocol.InternalError(err)
+		}
+		for _, hint := range bodyHints {
+			if hint.offset >= start && hint.offset <= end {
+				hints = append(hints, protocol.InlayHint{
+					Position: OffsetToLSP(text, hint.offset),
+					Label:    ": " + hint.typ.String(),
+					Kind:     protocol.InlayHintKindType,
+				})
+			}
+		}
+	}
+	return hints, nil
+}
+
+type inlayHint struct {
+	offset int
+	typ    typecheck.Type
+}
+
+// bodyInlayHints infers the types of one body's expressions and returns
+// the hints for its locals and comprehensions, in file order.
+func bodyInlayHints(
+	path string,
+	text string,
+	body *syntax.FactoryBody,
+	projects *ProjectCache,
+) ([]inlayHint, error) {
+	libs, err := diagnosticLibraries(path, *body, projects)
+	if err != nil {
+		return nil, err
+	}
+	inferred := map[parse.Expr]typecheck.Type{}
+	_ = check.NewSyntax(*body, libs, nil, "").References(func(e parse.Expr, t typecheck.Type) {
+		inferred[e] = t
+	})
+	known := func(e parse.Expr) (typecheck.Type, bool) {
+		t, ok := inferred[e]
+		return t, ok && t.Kind != typecheck.Unknown
+	}
+	var hints []inlayHint
+	localValues := map[parse.Expr]bool{}
+	for _, local := range body.Locals {
+		localValues[local.Value] = true
+		if t, ok := known(local.Value); ok {
+			start := local.Name.S.Start.Offset
+			hints = append(hints, inlayHint{offset: start + len(local.Name.Name), typ: t})
+		}
+	}
+	factoryBodyRoots(body, func(root parse.Expr) {
+		lang.Walk(root, func(e parse.Expr) {
+			comp, ok := e.(*parse.Comprehension)
+			if !ok || localValues[e] || comp.S.End.Offset <= 0 || comp.S.End.Offset > len(text) {
+				return
+			}
+			if t, ok := known(e); ok {
+				hints = append(hints, inlayHint{offset: comp.S.End.Offset, typ: t})
+			}
+		})
+	})
+	slices.SortStableFunc(hints, func(a, b inlayHint) int { return a.offset - b.offset })
+	return hints, nil
+}
@@ -0,0 +1,94 @@
+package lsp
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudboss/unobin/internal/ubtest"
+	"github.com/cloudboss/unobin/pkg/lsp/protocol"
+)
+
+func TestInlayHintsShowInferredTypes(t *testing.T) {
+	root, path, source := inlayHintsProject(t)
+
+	hints, rpcErr := InlayHintsForText(path, source, wholeDocument(source), NewProjectCache(root))
+	require.Nil(t, rpcErr)
+	require.Equal(t, map[int]string{
+		offsetInText(source, "name:       input", ":"):           ": string",
+		offsetInText(source, "zone-count: @core", ":"):           ": integer",
+		offsetInText(source, "zone-names: [", ":"):               ": list(string)",
+		offsetInText(source, "zone => input.region }", " }") + 2: ": map(string)",
+	}, inlayHintLabels(t, source, hints))
+	for _, hint := range hints {
+		require.Equal(t, protocol.InlayHintKindType, hint.Kind)
+	}
+}
+
+func TestInlayHintsLimitedToRange(t *testing.T) {
+	root, path, source := inlayHintsProject(t)
+	line := positionInText(source, "zone-names: [", "zone-names").Line
+	rng := protocol.Range{
+		Start: protocol.Position{Line: line},
+		End:   protocol.Position{Line: line + 1},
+	}
+
+	hints, rpcErr := InlayHintsForText(path, source, rng, NewProjectCache(root))
+	require.Nil(t, rpcErr)
+	require.Len(t, hints, 1)
+	require.Equal(t, ": list(string)", hints[0].Label)
+}
+
+func TestInlayHintsInvalidSourceReturnsNoHints(t *testing.T) {
+	root, path, source := inlayHintsProject(t)
+	source = source[:len(source)/2]
+
+	hints, rpcErr := InlayHintsForText(path, source, wholeDocument(source), NewProjectCache(root))
+	require.Nil(t, rpcErr)
+	require.Empty(t, hints)
+}
+
+func TestSessionInlayHintReturnsHints(t *testing.T) {
+	_, path, source := inlayHintsProject(t)
+	session := NewSession("dev")
+	uri := PathToFileURI(path)
+	rpcErr := openDocument(t, session, uri, 1, source)
+	require.Nil(t, rpcErr)
+
+	result, rpcErr := sessionRequest(t, session, "textDocument/inlayHint",
+		protocol.InlayHintParams{
+			TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+			Range:        wholeDocument(source),
+		})
+	require.Nil(t, rpcErr)
+	hints, ok := result.([]protocol.InlayHint)
+	require.True(t, ok)
+	require.Len(t, hints, 4)
+}
+
+func inlayHintsProject(t *testing.T) (string, string, string) {
+	t.Helper()
+	root := writeUBProject(t, nil, nil)
+	source := ubtest.ReadFixture(t, "testdata/ub/inlayhints/valid/factory.ub")
+	path := filepath.Join(root, "factory.ub")
+	require.NoError(t, os.WriteFile(path, []byte(source), 0o644))
+	return root, path, source
+}
+
+func wholeDocument(text string) protocol.Range {
+	return protocol.Range{End: OffsetToLSP(text, len(text))}
+}
+
+// inlayHintLabels returns each hint's label by the offset it is at.
+func inlayHintLabels(t *testing.T, text string, hints []protocol.InlayHint) map[int]string {
+	t.Helper()
+	out := map[int]string{}
+	for _, hint := range hints {
+		offset, ok := LSPToOffset(text, hint.Position)
+		require.True(t, ok)
+		out[offset] = hint.Label
+	}
+	return out
+}
@@ -0,0 +1,97 @@
+package lsp
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/cloudboss/unobin/pkg/deps"
+	"github.com/cloudboss/unobin/pkg/diagnostic"
+	"github.com/cloudboss/unobin/pkg/lang/syntax"
+	"github.com/cloudboss/unobin/pkg/lint"
+	"github.com/cloudboss/unobin/pkg/lsp/protocol"
+)
+
+// lintDiagnostics runs the lint rules over a factory or library
+// document, with the severities of the project it belongs to.
+func lintDiagnostics(path string, text string, projects *ProjectCache) []protocol.Diagnostic {
+	file, err := syntax.ParseSource(path, []byte(text))
+	if err != nil || (file.Kind != syntax.FileFactory && file.Kind != syntax.FileLibrary) {
+		return nil
+	}
+	var project *deps.Project
+	if projects != nil {
+		if p, err := projects.ProjectForPath(path); err == nil {
+			project = p.DepsProject
+		}
+	}
+	findings, err := lint.File(file, lint.Options{Path: path, Project: project})
+	if err != nil {
+		return nil
+	}
+	out := make([]protocol.Diagnostic, 0, len(findings))
+	for _, finding := range findings {
+		out = append(out, lintDiagnostic(text, finding))
+	}
+	return out
+}
+
+func lintDiagnostic(text string, finding diagnostic.Diagnostic) protocol.Diagnostic {
+	var rng protocol.Range
+	if finding.Span != nil {
+		rng.Start = OffsetToLSP(text, finding.Span.Start.Offset)
+		rng.End = rng.Start
+		if finding.Span.End != nil {
+			rng.End = OffsetToLSP(text, finding.Span.End.Offset)
+		}
+	}
+	message := finding.Message
+	if finding.Hint != "" {
+		message += "\n  hint: " + finding.Hint
+	}
+	return protocol.Diagnostic{
+		Range:    rng,
+		Severity: lintSeverity(finding.Severity),
+		Code:     protocol.DiagnosticCode(finding.Code),
+		Source:   "unobin",
+		Message:  message,
+	}
+}
+
+func lintSeverity(severity diagnostic.Severity) protocol.DiagnosticSeverity {
+	switch severity {
+	case diagnostic.SeverityError:
+		return protocol.DiagnosticSeverityError
+	case diagnostic.SeverityInfo:
+		return protocol.DiagnosticSeverityInformation
+	default:
+		return protocol.DiagnosticSeverityWarning
+	}
+}
+
+// diagnosticsForProjectLint reports the rules a project's `lint:`
+// block names that the lint registry does not have.
+func diagnosticsForProjectLint(text string, project *syntax.ProjectFile) []protocol.Diagnostic {
+	if project == nil {
+		return nil
+	}
+	rules := lint.Rules()
+	var out []protocol.Diagnostic
+	for _, decl := range project.Lint {
+		if _, ok := rules[decl.Code.Name]; ok {
+			continue
+		}
+		out = append(out, protocol.Diagnostic{
+			Range: protocol.Range{
+				Start: OffsetToLSP(text, decl.Code.S.Start.Offset),
+				End:   OffsetToLSP(text, decl.Code.S.End.Offset),
+			},
+			Severity: protocol.DiagnosticSeverityError,
+			Source:   "unobin",
+			Message: fmt.Sprintf("lint: unknown rule %q; available: %s", decl.Code.Name,
+				strings.Join(slices.Sorted(maps.Keys(rules)), ", ")),
+		})
+	}
+	return out
+}
@@ -0,0 +1,77 @@
+package lsp
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudboss/unobin/internal/ubtest"
+	"github.com/cloudboss/unobin/pkg/deps"
+	"github.com/cloudboss/unobin/pkg/lint"
+	"github.com/cloudboss/unobin/pkg/lsp/protocol"
+)
+
+func TestLintDocumentDiagnostics(t *testing.T) {
+	root := writeUBProject(t, &deps.Project{
+		Lint: map[string]string{lint.UnusedInputCode: "error"},
+	}, nil)
+	source := ubtest.ReadValidFixture(t, "testdata/ub/lint", "factory")
+	path := filepath.Join(root, "factory.ub")
+	require.NoError(t, os.WriteFile(path, []byte(source), 0o644))
+	documents := NewDocumentStore()
+	doc, err := documents.Open(PathToFileURI(path), 1, source)
+	require.NoError(t, err)
+
+	diags := DocumentDiagnostics(doc, NewProjectCache(root), documents)
+
+	unused := requireDiagnostic(t, diags, lint.CodePrefix+lint.UnusedInputCode)
+	require.Equal(t, protocol.DiagnosticSeverityError, unused.Severity)
+	require.Equal(t, positionInText(source, "extra:", "extra"), unused.Range.Start)
+	require.True(t, strings.HasPrefix(unused.Message, `input "extra" is never used`))
+	description := requireDiagnostic(t, diags, lint.CodePrefix+lint.InputDescriptionCode)
+	require.Equal(t, protocol.DiagnosticSeverityWarning, description.Severity)
+	require.Equal(t, positionInText(source, "name:  {", "name"), description.Range.Start)
+	for _, diag := range diags {
+		require.NotContains(t, diag.Message, `"spare"`)
+	}
+}
+
+func TestLintProjectUnknownRule(t *testing.T) {
+	root := writeUBProject(t, nil, nil)
+	source := ubtest.ReadInvalidFixture(t, "testdata/ub/lint", "project")
+	path := filepath.Join(root, "project.ub")
+
+	diags := DiagnosticsForTextWithProjects(path, source, nil)
+
+	require.Len(t, diags, 1)
+	require.Equal(t, positionInText(source, "no-such-rule", "no-such-rule"), diags[0].Range.Start)
+	require.True(t, strings.HasPrefix(diags[0].Message, `lint: unknown rule "no-such-rule"`))
+}
+
+func TestCompletionProjectLintRules(t *testing.T) {
+	root := writeUBProject(t, nil, nil)
+	path := filepath.Join(root, "project.ub")
+	source := ubtest.ReadValidFixture(t, "testdata/ub/completion", "project-lint")
+	offset := strings.Index(source, "\n\n") + 1
+
+	list, rpcErr := CompleteForText(path, source, OffsetToLSP(source, offset), NewProjectCache(root))
+	require.Nil(t, rpcErr)
+	requireCompletionLabels(t, list, lint.UnusedInputCode, lint.TriggerAlwaysCode)
+	item := requireCompletionItem(t, list, lint.SensitiveOutputCode)
+	require.Equal(t, "error", item.Detail)
+}
+
+func TestCompletionProjectLintSeverities(t *testing.T) {
+	root := writeUBProject(t, nil, nil)
+	path := filepath.Join(root, "project.ub")
+	source := ubtest.ReadValidFixture(t, "testdata/ub/completion", "project-lint")
+	source = strings.Replace(source, "unused-input: 'error'", "unused-input: ", 1)
+	offset := strings.Index(source, "unused-input: ") + len("unused-input: ")
+
+	list, rpcErr := CompleteForText(path, source, OffsetToLSP(source, offset), NewProjectCache(root))
+	require.Nil(t, rpcErr)
+	requireOnlyCompletionLabels(t, list, "'error'", "'info'", "'off'", "'warning'")
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	stdruntime "runtime"
+	"slices"
 	"strings"
 
 	"github.com/cloudboss/unobin/pkg/asset"
@@ -14,6 +15,7 @@ import (
 	"github.com/cloudboss/unobin/pkg/goschema"
 	"github.com/cloudboss/unobin/pkg/projectmarker"
 	"github.com/cloudboss/unobin/pkg/resolve"
+	"github.com/cloudboss/unobin/pkg/sourcecheck"
 	"github.com/cloudboss/unobin/pkg/toolchain"
 )
 
@@ -27,6 +29,10 @@ type Project struct {
 	GoSchemas     *compile.SchemaCache
 	GoIndex       *goschema.SourceIndexCache
 	GoModuleRoots []goschema.ModuleRoot
+	// Checks keeps parsed UB libraries and clean composite checks
+	// between diagnostics runs, so an edit re-checks only what it
+	// changed and what imports it.
+	Checks *sourcecheck.Cache
 }
 
 // ProjectCache stores project data by marker root.
@@ -97,6 +103,27 @@ func (c *ProjectCache) SetWorkspaceRoots(roots []string) {
 	}
 }
 
+// Roots returns the workspace roots and the roots of cached projects,
+// sorted, leaving out any root inside another.
+func (c *ProjectCache) Roots() []string {
+	if c == nil {
+		return nil
+	}
+	roots := slices.Clone(c.workspaceRoots)
+	for root := range c.projects {
+		roots = append(roots, root)
+	}
+	roots = cleanWorkspaceRoots(roots)
+	slices.Sort(roots)
+	var out []string
+	for _, root := range roots {
+		if !slices.ContainsFunc(out, func(outer string) bool { return pathInDir(root, outer) }) {
+			out = append(out, root)
+		}
+	}
+	return out
+}
+
 // ProjectForPath returns cached project data for path's nearest marker root.
 func (c *ProjectCache) ProjectForPath(path string) (*Project, error) {
 	root, marker, err := deps.FindProjectMarkerDir(path)
@@ -167,10 +194,16 @@ func (c *ProjectCache) readProject(root string, marker projectmarker.Marker) (*P
 			return nil, err
 		}
 	}
-	remote, err := c.remoteFactory()
+	var remote cachedRemoteSource
+	vendor, err := c.openVendor(root, marker, projectLock)
 	if err != nil {
 		return nil, err
 	}
+	if vendor != nil {
+		remote = vendor
+	} else if remote, err = c.remoteFactory(); err != nil {
+		return nil, err
+	}
 	roots := schemaRootsForProject(root, marker, c.schemaRoots)
 	return &Project{
 		Root:          root,
@@ -181,9 +214,21 @@ func (c *ProjectCache) readProject(root string, marker projectmarker.Marker) (*P
 		GoSchemas:     compile.NewSchemaCache(roots...),
 		GoIndex:       goschema.NewSourceIndexCache(roots...),
 		GoModuleRoots: roots,
+		Checks:        sourcecheck.NewCache(),
 	}, nil
 }
 
+// openVendor returns the vendor directory of a UB project, so its
+// imports read from the vendored sources instead of the import cache.
+func (c *ProjectCache) openVendor(
+	root string, marker projectmarker.Marker, projectLock *deps.ProjectLock,
+) (*compile.Vendor, error) {
+	if marker.Kind != projectmarker.UB {
+		return nil, nil
+	}
+	return compile.OpenVendor(root, projectLock)
+}
+
 // EnsureGoModuleRoot adds the Go module root for source when it can be found.
 func (p *Project) EnsureGoModuleRoot(source *resolve.Source) {
 	root, ok := goModuleRootForSource(source)
@@ -193,6 +238,7 @@ func (p *Project) EnsureGoModuleRoot(source *resolve.Source) {
 	p.GoModuleRoots = append(p.GoModuleRoots, root)
 	p.GoSchemas = compile.NewSchemaCache(p.GoModuleRoots...)
 	p.GoIndex = goschema.NewSourceIndexCache(p.GoModuleRoots...)
+	p.Checks = sourcecheck.NewCache()
 }
 
 func (p *Project) hasGoModuleRoot(root goschema.ModuleRoot) bool {
@@ -209,6 +255,7 @@ func (p *Project) invalidateGoPath(path string) {
 		if pathInDir(path, root.Dir) {
 			p.GoSchemas = compile.NewSchemaCache(p.GoModuleRoots...)
 			p.GoIndex = goschema.NewSourceIndexCache(p.GoModuleRoots...)
+			p.Checks = sourcecheck.NewCache()
 			return
 		}
 	}
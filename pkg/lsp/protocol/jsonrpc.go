@@ -15,6 +15,7 @@ const (
 	ErrorCodeInvalidParams  = -32602
 	ErrorCodeInternalError  = -32603
 	ErrorCodeRequestCancel  = -32800
+	ErrorCodeRequestFailed  = -32803
 )
 
 type idKind int
@@ -154,6 +155,12 @@ func InternalError(err error) *ResponseError {
 	return &ResponseError{Code: ErrorCodeInternalError, Message: err.Error()}
 }
 
+// RequestFailed returns the LSP error for a valid request the server
+// could not carry out, with a message the client can show.
+func RequestFailed(message string) *ResponseError {
+	return &ResponseError{Code: ErrorCodeRequestFailed, Message: message}
+}
+
 // Handler serves JSON-RPC requests.
 type Handler interface {
 	HandleRequest(context.Context, *RequestMessage) (any, *ResponseError)
@@ -1,5 +1,7 @@
 package protocol
 
+import "encoding/json"
+
 const (
 	TextDocumentSyncKindNone        TextDocumentSyncKind = 0
 	TextDocumentSyncKindFull        TextDocumentSyncKind = 1
@@ -14,12 +16,13 @@ const (
 )
 
 const (
-	CompletionItemKindText     CompletionItemKind = 1
-	CompletionItemKindMethod   CompletionItemKind = 2
-	CompletionItemKindFunction CompletionItemKind = 3
-	CompletionItemKindField    CompletionItemKind = 5
-	CompletionItemKindVariable CompletionItemKind = 6
-	CompletionItemKindKeyword  CompletionItemKind = 14
+	CompletionItemKindText       CompletionItemKind = 1
+	CompletionItemKindMethod     CompletionItemKind = 2
+	CompletionItemKindFunction   CompletionItemKind = 3
+	CompletionItemKindField      CompletionItemKind = 5
+	CompletionItemKindVariable   CompletionItemKind = 6
+	CompletionItemKindKeyword    CompletionItemKind = 14
+	CompletionItemKindEnumMember CompletionItemKind = 20
 )
 
 const (
@@ -59,8 +62,28 @@ type InitializeParams struct {
 	WorkspaceFolders []WorkspaceFolder  `json:"workspaceFolders,omitempty"`
 }
 
-// ClientCapabilities is present for initialize compatibility.
-type ClientCapabilities struct{}
+// ClientCapabilities is the subset of client capabilities Unobin reads.
+type ClientCapabilities struct {
+	Workspace *WorkspaceClientCapabilities `json:"workspace,omitempty"`
+}
+
+// WorkspaceClientCapabilities describes the client's workspace features.
+type WorkspaceClientCapabilities struct {
+	WorkspaceEdit *WorkspaceEditClientCapabilities `json:"workspaceEdit,omitempty"`
+}
+
+// WorkspaceEditClientCapabilities describes the workspace edits a client
+// can apply.
+type WorkspaceEditClientCapabilities struct {
+	DocumentChanges         bool                     `json:"documentChanges,omitempty"`
+	ChangeAnnotationSupport *ChangeAnnotationSupport `json:"changeAnnotationSupport,omitempty"`
+}
+
+// ChangeAnnotationSupport is present when a client can show change
+// annotations and ask the user to confirm annotated edits.
+type ChangeAnnotationSupport struct {
+	GroupsOnLabel bool `json:"groupsOnLabel,omitempty"`
+}
 
 // WorkspaceFolder is an LSP workspace folder.
 type WorkspaceFolder struct {
@@ -82,12 +105,50 @@ type ServerInfo struct {
 
 // ServerCapabilities advertises implemented LSP features.
 type ServerCapabilities struct {
-	TextDocumentSync           TextDocumentSyncKind `json:"textDocumentSync,omitempty"`
-	DocumentFormattingProvider bool                 `json:"documentFormattingProvider,omitempty"`
-	DefinitionProvider         bool                 `json:"definitionProvider,omitempty"`
-	DocumentSymbolProvider     bool                 `json:"documentSymbolProvider,omitempty"`
-	CompletionProvider         *CompletionOptions   `json:"completionProvider,omitempty"`
-	HoverProvider              bool                 `json:"hoverProvider,omitempty"`
+	TextDocumentSync           TextDocumentSyncKind   `json:"textDocumentSync,omitempty"`
+	DocumentFormattingProvider bool                   `json:"documentFormattingProvider,omitempty"`
+	DefinitionProvider         bool                   `json:"definitionProvider,omitempty"`
+	DocumentSymbolProvider     bool                   `json:"documentSymbolProvider,omitempty"`
+	CompletionProvider         *CompletionOptions     `json:"completionProvider,omitempty"`
+	HoverProvider              bool                   `json:"hoverProvider,omitempty"`
+	ReferencesProvider         bool                   `json:"referencesProvider,omitempty"`
+	RenameProvider             *RenameOptions         `json:"renameProvider,omitempty"`
+	CodeActionProvider         *CodeActionOptions     `json:"codeActionProvider,omitempty"`
+	SemanticTokensProvider     *SemanticTokensOptions `json:"semanticTokensProvider,omitempty"`
+	SignatureHelpProvider      *SignatureHelpOptions  `json:"signatureHelpProvider,omitempty"`
+	InlayHintProvider          bool                   `json:"inlayHintProvider,omitempty"`
+	WorkspaceSymbolProvider    bool                   `json:"workspaceSymbolProvider,omitempty"`
+	CallHierarchyProvider      bool                   `json:"callHierarchyProvider,omitempty"`
+}
+
+// SemanticTokensOptions configures semantic token requests. Only full
+// document requests are served.
+type SemanticTokensOptions struct {
+	Legend SemanticTokensLegend `json:"legend"`
+	Full   bool                 `json:"full,omitempty"`
+}
+
+// SemanticTokensLegend names the token types and modifiers that
+// semantic token data indexes.
+type SemanticTokensLegend struct {
+	TokenTypes     []string `json:"tokenTypes"`
+	TokenModifiers []string `json:"tokenModifiers"`
+}
+
+// SignatureHelpOptions configures signature help requests.
+type SignatureHelpOptions struct {
+	TriggerCharacters   []string `json:"triggerCharacters,omitempty"`
+	RetriggerCharacters []string `json:"retriggerCharacters,omitempty"`
+}
+
+// CodeActionOptions lists the kinds of code actions the server offers.
+type CodeActionOptions struct {
+	CodeActionKinds []string `json:"codeActionKinds,omitempty"`
+}
+
+// RenameOptions configures rename requests.
+type RenameOptions struct {
+	PrepareProvider bool `json:"prepareProvider,omitempty"`
 }
 
 // CompletionOptions configures completion requests.
@@ -175,18 +236,42 @@ type FormattingOptions struct {
 	InsertSpaces bool   `json:"insertSpaces"`
 }
 
-// TextEdit replaces a range with new text.
+// TextEdit replaces a range with new text. AnnotationID names the
+// workspace edit's change annotation the edit belongs to.
 type TextEdit struct {
-	Range   Range  `json:"range"`
-	NewText string `json:"newText"`
+	Range        Range  `json:"range"`
+	NewText      string `json:"newText"`
+	AnnotationID string `json:"annotationId,omitempty"`
 }
 
 // Diagnostic is an LSP diagnostic.
 type Diagnostic struct {
 	Range    Range              `json:"range"`
 	Severity DiagnosticSeverity `json:"severity,omitempty"`
+	Code     DiagnosticCode     `json:"code,omitempty"`
 	Source   string             `json:"source,omitempty"`
 	Message  string             `json:"message"`
+	Data     any                `json:"data,omitempty"`
+}
+
+// DiagnosticCode is a diagnostic's code. Unobin's codes are strings; a
+// numeric code another server attached to a diagnostic the client
+// sends back decodes as its decimal text.
+type DiagnosticCode string
+
+// UnmarshalJSON accepts a string or a number.
+func (c *DiagnosticCode) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*c = DiagnosticCode(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	*c = DiagnosticCode(n.String())
+	return nil
 }
 
 // PublishDiagnosticsParams sends diagnostics for one document.
@@ -248,10 +333,12 @@ type CompletionList struct {
 
 // CompletionItem is one completion candidate.
 type CompletionItem struct {
-	Label      string             `json:"label"`
-	Kind       CompletionItemKind `json:"kind,omitempty"`
-	FilterText string             `json:"filterText,omitempty"`
-	TextEdit   *TextEdit          `json:"textEdit,omitempty"`
+	Label         string             `json:"label"`
+	Kind          CompletionItemKind `json:"kind,omitempty"`
+	Detail        string             `json:"detail,omitempty"`
+	Documentation *MarkupContent     `json:"documentation,omitempty"`
+	FilterText    string             `json:"filterText,omitempty"`
+	TextEdit      *TextEdit          `json:"textEdit,omitempty"`
 }
 
 // HoverParams is a hover request.
@@ -271,3 +358,205 @@ type MarkupContent struct {
 	Kind  MarkupKind `json:"kind"`
 	Value string     `json:"value"`
 }
+
+// SemanticTokensParams is a full document semantic tokens request.
+type SemanticTokensParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+}
+
+// SemanticTokens is the encoded token data of a document: five
+// integers per token, each position relative to the token before it.
+type SemanticTokens struct {
+	Data []uint32 `json:"data"`
+}
+
+// SignatureHelpParams is a signature help request.
+type SignatureHelpParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Position     Position               `json:"position"`
+}
+
+// SignatureHelp describes the call the cursor is in.
+type SignatureHelp struct {
+	Signatures      []SignatureInformation `json:"signatures"`
+	ActiveSignature uint32                 `json:"activeSignature"`
+	ActiveParameter uint32                 `json:"activeParameter"`
+}
+
+// SignatureInformation is one callable signature.
+type SignatureInformation struct {
+	Label         string                 `json:"label"`
+	Documentation *MarkupContent         `json:"documentation,omitempty"`
+	Parameters    []ParameterInformation `json:"parameters,omitempty"`
+}
+
+// ParameterInformation is one parameter of a signature. Label holds
+// the parameter's start and end offsets in the signature label.
+type ParameterInformation struct {
+	Label [2]uint32 `json:"label"`
+}
+
+// InlayHintParams is an inlay hint request for a range of a document.
+type InlayHintParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Range        Range                  `json:"range"`
+}
+
+// InlayHintKind classifies an inlay hint.
+type InlayHintKind int
+
+// InlayHintKindType marks a hint that shows an inferred type.
+const InlayHintKindType InlayHintKind = 1
+
+// InlayHint is a label the editor shows inline at a position.
+type InlayHint struct {
+	Position     Position      `json:"position"`
+	Label        string        `json:"label"`
+	Kind         InlayHintKind `json:"kind,omitempty"`
+	PaddingLeft  bool          `json:"paddingLeft,omitempty"`
+	PaddingRight bool          `json:"paddingRight,omitempty"`
+}
+
+// ReferenceParams is a find-references request.
+type ReferenceParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Position     Position               `json:"position"`
+	Context      ReferenceContext       `json:"context"`
+}
+
+// ReferenceContext says whether references include the declaration.
+type ReferenceContext struct {
+	IncludeDeclaration bool `json:"includeDeclaration"`
+}
+
+// PrepareRenameParams is a prepare-rename request.
+type PrepareRenameParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Position     Position               `json:"position"`
+}
+
+// PrepareRenameResult is the range a rename replaces and the name it
+// starts from.
+type PrepareRenameResult struct {
+	Range       Range  `json:"range"`
+	Placeholder string `json:"placeholder"`
+}
+
+// RenameParams is a rename request.
+type RenameParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Position     Position               `json:"position"`
+	NewName      string                 `json:"newName"`
+}
+
+// WorkspaceEdit is a set of edits across documents. Changes is used
+// by clients without document-changes support.
+type WorkspaceEdit struct {
+	Changes           map[string][]TextEdit       `json:"changes,omitempty"`
+	DocumentChanges   []TextDocumentEdit          `json:"documentChanges,omitempty"`
+	ChangeAnnotations map[string]ChangeAnnotation `json:"changeAnnotations,omitempty"`
+}
+
+// TextDocumentEdit is the edits to one document.
+type TextDocumentEdit struct {
+	TextDocument OptionalVersionedTextDocumentIdentifier `json:"textDocument"`
+	Edits        []TextEdit                              `json:"edits"`
+}
+
+// OptionalVersionedTextDocumentIdentifier identifies a document by URI
+// and, when it is open, its version.
+type OptionalVersionedTextDocumentIdentifier struct {
+	URI     string `json:"uri"`
+	Version *int32 `json:"version"`
+}
+
+// ChangeAnnotation describes a group of edits a client may ask the
+// user to confirm.
+type ChangeAnnotation struct {
+	Label             string `json:"label"`
+	NeedsConfirmation bool   `json:"needsConfirmation,omitempty"`
+	Description       string `json:"description,omitempty"`
+}
+
+// CodeActionKindQuickFix is the kind of a code action that fixes a
+// diagnostic.
+const CodeActionKindQuickFix = "quickfix"
+
+// CodeActionParams is a code action request for a range of a document.
+type CodeActionParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Range        Range                  `json:"range"`
+	Context      CodeActionContext      `json:"context"`
+}
+
+// CodeActionContext carries the diagnostics at the requested range.
+type CodeActionContext struct {
+	Diagnostics []Diagnostic `json:"diagnostics"`
+	Only        []string     `json:"only,omitempty"`
+}
+
+// CodeAction is one fix the client can apply.
+type CodeAction struct {
+	Title       string         `json:"title"`
+	Kind        string         `json:"kind,omitempty"`
+	Diagnostics []Diagnostic   `json:"diagnostics,omitempty"`
+	IsPreferred bool           `json:"isPreferred,omitempty"`
+	Edit        *WorkspaceEdit `json:"edit,omitempty"`
+}
+
+// WorkspaceSymbolParams is a workspace symbol request. An empty query
+// asks for every symbol.
+type WorkspaceSymbolParams struct {
+	Query string `json:"query"`
+}
+
+// SymbolInformation is a symbol found anywhere in the workspace.
+type SymbolInformation struct {
+	Name          string     `json:"name"`
+	Kind          SymbolKind `json:"kind"`
+	Location      Location   `json:"location"`
+	ContainerName string     `json:"containerName,omitempty"`
+}
+
+// CallHierarchyPrepareParams asks for the call hierarchy item at a
+// position.
+type CallHierarchyPrepareParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Position     Position               `json:"position"`
+}
+
+// CallHierarchyItem is one caller or callee. Data is sent back
+// unchanged in the incoming and outgoing call requests for the item.
+type CallHierarchyItem struct {
+	Name           string     `json:"name"`
+	Kind           SymbolKind `json:"kind"`
+	Detail         string     `json:"detail,omitempty"`
+	URI            string     `json:"uri"`
+	Range          Range      `json:"range"`
+	SelectionRange Range      `json:"selectionRange"`
+	Data           any        `json:"data,omitempty"`
+}
+
+// CallHierarchyIncomingCallsParams asks for the callers of an item.
+type CallHierarchyIncomingCallsParams struct {
+	Item CallHierarchyItem `json:"item"`
+}
+
+// CallHierarchyIncomingCall is a caller and the ranges in it that call
+// the item.
+type CallHierarchyIncomingCall struct {
+	From       CallHierarchyItem `json:"from"`
+	FromRanges []Range           `json:"fromRanges"`
+}
+
+// CallHierarchyOutgoingCallsParams asks for the callees of an item.
+type CallHierarchyOutgoingCallsParams struct {
+	Item CallHierarchyItem `json:"item"`
+}
+
+// CallHierarchyOutgoingCall is a callee and the ranges in the item that
+// call it.
+type CallHierarchyOutgoingCall struct {
+	To         CallHierarchyItem `json:"to"`
+	FromRanges []Range           `json:"fromRanges"`
+}
@@ -0,0 +1,850 @@
+package lsp
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/cloudboss/unobin/pkg/lang/parse"
+	"github.com/cloudboss/unobin/pkg/lang/syntax"
+	"github.com/cloudboss/unobin/pkg/lsp/protocol"
+)
+
+// ReferencesForText resolves a find-references request. A symbol
+// declared in a library composite's body, and the composite itself,
+// is also found at its call sites in the workspace's other files.
+func ReferencesForText(
+	path string,
+	text string,
+	pos protocol.Position,
+	includeDeclaration bool,
+	projects *ProjectCache,
+	documents *DocumentStore,
+) ([]protocol.Location, *protocol.ResponseError) {
+	offset, ok := LSPToOffset(text, pos)
+	if !ok {
+		return nil, protocol.InvalidParams("invalid document position")
+	}
+	refs := newRefIndex(projects, documents)
+	src, ok := refs.add(path, text)
+	if !ok {
+		return []protocol.Location{}, nil
+	}
+	entry, ok := refs.entryAt(src, offset)
+	if !ok {
+		return []protocol.Location{}, nil
+	}
+	occurrences, err := refs.occurrences(src, entry.key)
+	if err != nil {
+		return nil, protocol.InternalError(err)
+	}
+	locations := make([]protocol.Location, 0, len(occurrences))
+	for _, occ := range occurrences {
+		if occ.decl && !includeDeclaration {
+			continue
+		}
+		locations = append(locations, occ.location())
+	}
+	return locations, nil
+}
+
+// refKind is what a referenced name is. Names of different kinds never
+// refer to each other, even when they are spelled the same.
+type refKind int
+
+const (
+	refInput refKind = iota
+	refLocal
+	refAsset
+	refNode
+	refOutput
+	refComposite
+	refEach
+	refBinding
+	refComprehension
+)
+
+// refKey identifies one declared name. Path and body pick the file and
+// the body in it that declare the name: -1 for a factory body, else
+// the offset of the composite's name. Scope picks the @for-each object
+// or comprehension that binds an iteration name, and is -1 otherwise.
+type refKey struct {
+	kind     refKind
+	name     string
+	nodeKind syntax.NodeKind
+	path     string
+	body     int
+	scope    int
+}
+
+// shared reports whether the name can be referenced from other files:
+// a composite is called, and its inputs set and outputs read, from
+// wherever its library is imported.
+func (k refKey) shared() bool {
+	switch k.kind {
+	case refComposite:
+		return true
+	case refInput, refOutput:
+		return k.body >= 0
+	default:
+		return false
+	}
+}
+
+// refSource is one parsed .ub file that a request reads.
+type refSource struct {
+	path string
+	text string
+	file *syntax.File
+}
+
+// refOccurrence is where a name is written: its declaration, when decl
+// is set, or one reference to it.
+type refOccurrence struct {
+	source *refSource
+	start  int
+	end    int
+	decl   bool
+}
+
+func (o refOccurrence) location() protocol.Location {
+	return protocol.Location{URI: PathToFileURI(o.source.path), Range: o.lspRange()}
+}
+
+func (o refOccurrence) lspRange() protocol.Range {
+	return protocol.Range{
+		Start: OffsetToLSP(o.source.text, o.start),
+		End:   OffsetToLSP(o.source.text, o.end),
+	}
+}
+
+func (o refOccurrence) contains(offset int) bool {
+	return offset >= o.start && offset <= o.end
+}
+
+type refEntry struct {
+	key refKey
+	occ refOccurrence
+}
+
+// refCompositeTarget is the library composite a call site resolved to.
+type refCompositeTarget struct {
+	source *refSource
+	decl   *syntax.CompositeDecl
+}
+
+// refIndex parses each file a request reads once, taking an open
+// document's text over the file on disk, and lists the names each
+// file declares and references. Calls records the nodes that call a
+// local library composite.
+type refIndex struct {
+	projects  *ProjectCache
+	documents *DocumentStore
+	sources   map[string]*refSource
+	entries   map[string][]refEntry
+	libraries map[string]*refLibrary
+	calls     map[refKey]bool
+}
+
+func newRefIndex(projects *ProjectCache, documents *DocumentStore) *refIndex {
+	if projects == nil {
+		projects = NewProjectCache("")
+	}
+	return &refIndex{
+		projects:  projects,
+		documents: documents,
+		sources:   map[string]*refSource{},
+		entries:   map[string][]refEntry{},
+		libraries: map[string]*refLibrary{},
+		calls:     map[refKey]bool{},
+	}
+}
+
+func (x *refIndex) add(path string, text string) (*refSource, bool) {
+	path = filepath.Clean(path)
+	file, err := syntax.ParseSource(path, []byte(text))
+	if err != nil {
+		x.sources[path] = nil
+		return nil, false
+	}
+	src := &refSource{path: path, text: text, file: file}
+	x.sources[path] = src
+	return src, true
+}
+
+func (x *refIndex) load(path string) (*refSource, bool) {
+	path = filepath.Clean(path)
+	if src, ok := x.sources[path]; ok {
+		return src, src != nil
+	}
+	if doc, ok := x.documents.GetPath(path); ok {
+		return x.add(path, doc.Text)
+	}
+	text, err := os.ReadFile(path)
+	if err != nil {
+		x.sources[path] = nil
+		return nil, false
+	}
+	return x.add(path, string(text))
+}
+
+// entryAt returns the name written at offset in src, preferring the
+// innermost occurrence when one name is written inside another.
+func (x *refIndex) entryAt(src *refSource, offset int) (refEntry, bool) {
+	var best refEntry
+	found := false
+	for _, entry := range x.entriesFor(src) {
+		if !entry.occ.contains(offset) {
+			continue
+		}
+		if !found || entry.occ.end-entry.occ.start < best.occ.end-best.occ.start {
+			best = entry
+			found = true
+		}
+	}
+	return best, found
+}
+
+// occurrences lists every place key is written, in file order. A name
+// only its own body can read is looked for in the declaring file; a
+// shared one is also looked for in every .ub file of the project.
+func (x *refIndex) occurrences(from *refSource, key refKey) ([]refOccurrence, error) {
+	paths := []string{key.path}
+	if key.shared() {
+		more, err := x.projectFiles(from.path)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, more...)
+	}
+	slices.Sort(paths)
+	paths = slices.Compact(paths)
+	var out []refOccurrence
+	for _, path := range paths {
+		src, ok := x.load(path)
+		if !ok {
+			continue
+		}
+		for _, entry := range x.entriesFor(src) {
+			if entry.key == key {
+				out = append(out, entry.occ)
+			}
+		}
+	}
+	slices.SortStableFunc(out, func(a, b refOccurrence) int {
+		if c := strings.Compare(a.source.path, b.source.path); c != 0 {
+			return c
+		}
+		return a.start - b.start
+	})
+	return slices.CompactFunc(out, func(a, b refOccurrence) bool {
+		return a.source == b.source && a.start == b.start
+	}), nil
+}
+
+// projectFiles lists the .ub files under the project root of path,
+// skipping hidden directories.
+func (x *refIndex) projectFiles(path string) ([]string, error) {
+	project, err := x.projects.ProjectForPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return ubFilesUnder(project.Root)
+}
+
+// workspaceFiles lists the .ub files under every root of the project
+// cache, skipping hidden directories and roots that no longer exist.
+func (x *refIndex) workspaceFiles() ([]string, error) {
+	var paths []string
+	for _, root := range x.projects.Roots() {
+		more, err := ubFilesUnder(root)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, more...)
+	}
+	return paths, nil
+}
+
+func ubFilesUnder(root string) ([]string, error) {
+	var paths []string
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != root && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(p) == ".ub" {
+			paths = append(paths, filepath.Clean(p))
+		}
+		return nil
+	})
+	return paths, err
+}
+
+func (x *refIndex) entriesFor(src *refSource) []refEntry {
+	if entries, ok := x.entries[src.path]; ok {
+		return entries
+	}
+	var entries []refEntry
+	if src.file.Factory != nil {
+		entries = x.bodyEntries(src, &src.file.Factory.Body, -1, entries)
+	}
+	if src.file.Library != nil {
+		for i := range src.file.Library.Exports {
+			composite := &src.file.Library.Exports[i]
+			id := composite.Name.S.Start.Offset
+			entries = append(entries, refEntry{
+				key: refKey{
+					kind: refComposite, name: composite.Name.Name,
+					nodeKind: composite.Kind, path: src.path, body: id, scope: -1,
+				},
+				occ: identOccurrence(src, composite.Name, true),
+			})
+			entries = x.bodyEntries(src, &composite.Body, id, entries)
+		}
+	}
+	x.entries[src.path] = entries
+	return entries
+}
+
+func (x *refIndex) bodyEntries(
+	src *refSource,
+	body *syntax.FactoryBody,
+	bodyID int,
+	entries []refEntry,
+) []refEntry {
+	key := func(kind refKind, name string) refKey {
+		return refKey{kind: kind, name: name, path: src.path, body: bodyID, scope: -1}
+	}
+	decls := definitionDeclsForBody(body)
+	for _, input := range body.Inputs {
+		entries = append(entries, refEntry{
+			key: key(refInput, input.Name.Name), occ: identOccurrence(src, input.Name, true),
+		})
+	}
+	for _, local := range body.Locals {
+		entries = append(entries, refEntry{
+			key: key(refLocal, local.Name.Name), occ: identOccurrence(src, local.Name, true),
+		})
+	}
+	for _, item := range body.Assets {
+		entries = append(entries, refEntry{
+			key: key(refAsset, item.Name.Name), occ: identOccurrence(src, item.Name, true),
+		})
+	}
+	for _, output := range body.Outputs {
+		entries = append(entries, refEntry{
+			key: key(refOutput, output.Name.Name), occ: identOccurrence(src, output.Name, true),
+		})
+	}
+	targets := map[string]refCompositeTarget{}
+	for _, node := range allNodes(*body) {
+		nodeKey := key(refNode, node.Name.Name)
+		nodeKey.nodeKind = node.Kind
+		entries = append(entries, refEntry{key: nodeKey, occ: identOccurrence(src, node.Name, true)})
+		target, ok := x.compositeFor(src.path, node, decls)
+		if !ok {
+			continue
+		}
+		targets[string(node.Kind)+"."+node.Name.Name] = target
+		x.calls[nodeKey] = true
+		entries = append(entries, callSiteEntries(src, node, target)...)
+	}
+	for _, move := range body.StateMoves {
+		if entry, ok := stateMoveEntry(src, move.To, decls, key); ok {
+			entries = append(entries, entry)
+		}
+	}
+	w := refWalker{src: src, body: bodyID, decls: decls, targets: targets}
+	factoryBodyRoots(body, func(e parse.Expr) {
+		w.walk(e, nil)
+	})
+	return append(entries, w.entries...)
+}
+
+// callSiteEntries lists the composite's names a call site writes: the
+// export in its selector and the inputs its body sets.
+func callSiteEntries(src *refSource, node syntax.NodeDecl, target refCompositeTarget) []refEntry {
+	body := target.decl.Name.S.Start.Offset
+	if node.Selector.Export.Name == "" {
+		return nil
+	}
+	entries := []refEntry{{
+		key: refKey{
+			kind: refComposite, name: target.decl.Name.Name, nodeKind: target.decl.Kind,
+			path: target.source.path, body: body, scope: -1,
+		},
+		occ: identOccurrence(src, node.Selector.Export, false),
+	}}
+	if node.Body == nil {
+		return entries
+	}
+	for _, field := range node.Body.Fields {
+		if field.Key.Kind != parse.FieldIdent || field.Key.IsMeta() {
+			continue
+		}
+		if !slices.ContainsFunc(target.decl.Body.Inputs, func(in syntax.InputDecl) bool {
+			return in.Name.Name == field.Key.Name
+		}) {
+			continue
+		}
+		start := field.Key.S.Start.Offset
+		entries = append(entries, refEntry{
+			key: refKey{
+				kind: refInput, name: field.Key.Name,
+				path: target.source.path, body: body, scope: -1,
+			},
+			occ: refOccurrence{source: src, start: start, end: start + len(field.Key.Name)},
+		})
+	}
+	return entries
+}
+
+// stateMoveEntry returns the node a state move's `to:` names, since
+// that ref follows the node it moves state to.
+func stateMoveEntry(
+	src *refSource,
+	ref *syntax.StateMoveRef,
+	decls definitionDecls,
+	key func(refKind, string) refKey,
+) (refEntry, bool) {
+	if ref == nil {
+		return refEntry{}, false
+	}
+	for kind, nodes := range decls.nodes {
+		prefix := string(kind) + "."
+		start := ref.S.Start.Offset
+		if start < 0 || !strings.HasPrefix(src.text[start:], prefix) {
+			continue
+		}
+		nameStart := start + len(prefix)
+		name := src.text[nameStart:symbolNameEnd(src.text, nameStart)]
+		if _, ok := nodes[name]; !ok {
+			continue
+		}
+		nodeKey := key(refNode, name)
+		nodeKey.nodeKind = kind
+		return refEntry{
+			key: nodeKey,
+			occ: refOccurrence{source: src, start: nameStart, end: nameStart + len(name)},
+		}, true
+	}
+	return refEntry{}, false
+}
+
+// compositeFor resolves a node's selector to the UB composite it calls
+// when the library is a local directory, the only kind a rename can
+// edit.
+func (x *refIndex) compositeFor(
+	path string,
+	node syntax.NodeDecl,
+	decls definitionDecls,
+) (refCompositeTarget, bool) {
+	resolved, ok := x.libraryFor(path, node.Selector.Alias.Name, decls)
+	if !ok || resolved.source.LocalPath == "" {
+		return refCompositeTarget{}, false
+	}
+	composites := x.library(resolved.source.Path).composites
+	target, ok := composites[string(node.Kind)+"."+node.Selector.Export.Name]
+	return target, ok
+}
+
+// libraryFor resolves an import alias to the library source it names,
+// when that source is on disk.
+func (x *refIndex) libraryFor(
+	path string,
+	alias string,
+	decls definitionDecls,
+) (resolvedImport, bool) {
+	resolved, err := resolveImportAlias(path, alias, decls, x.projects)
+	if err != nil || !resolved.found || !resolved.sourceOK || resolved.source == nil ||
+		resolved.source.Path == "" {
+		return resolvedImport{}, false
+	}
+	return resolved, true
+}
+
+// refLibrary is the composites and functions the .ub files of one
+// library directory export.
+type refLibrary struct {
+	composites map[string]refCompositeTarget
+	functions  map[string]refFunctionTarget
+}
+
+// refFunctionTarget is a UB library function.
+type refFunctionTarget struct {
+	source *refSource
+	decl   *syntax.FunctionDecl
+}
+
+// library reads the .ub files of a library directory once.
+func (x *refIndex) library(dir string) *refLibrary {
+	dir = filepath.Clean(dir)
+	if lib, ok := x.libraries[dir]; ok {
+		return lib
+	}
+	lib := &refLibrary{
+		composites: map[string]refCompositeTarget{},
+		functions:  map[string]refFunctionTarget{},
+	}
+	matches, _ := filepath.Glob(filepath.Join(dir, "*.ub"))
+	for _, match := range matches {
+		src, ok := x.load(match)
+		if !ok || src.file.Library == nil {
+			continue
+		}
+		for i := range src.file.Library.Exports {
+			decl := &src.file.Library.Exports[i]
+			lib.composites[string(decl.Kind)+"."+decl.Name.Name] = refCompositeTarget{
+				source: src, decl: decl,
+			}
+		}
+		for i := range src.file.Library.Functions {
+			decl := &src.file.Library.Functions[i]
+			lib.functions[decl.Name.Name] = refFunctionTarget{source: src, decl: decl}
+		}
+	}
+	x.libraries[dir] = lib
+	return lib
+}
+
+// refScope is one level of iteration names: the @each and chained
+// bindings of an object with @for-each, or the names a comprehension
+// binds.
+type refScope struct {
+	parent   *refScope
+	start    int
+	forEach  bool
+	bindings []string
+	names    []string
+}
+
+func (s *refScope) each() (*refScope, bool) {
+	for ; s != nil; s = s.parent {
+		if s.forEach {
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+func (s *refScope) binding(name string) (*refScope, bool) {
+	for ; s != nil; s = s.parent {
+		if slices.Contains(s.bindings, name) {
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+func (s *refScope) comprehension(name string) (*refScope, bool) {
+	for ; s != nil; s = s.parent {
+		if slices.Contains(s.names, name) {
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+// refWalker collects the names one body's expressions reference,
+// tracking the iteration names in scope at each point.
+type refWalker struct {
+	src     *refSource
+	body    int
+	decls   definitionDecls
+	targets map[string]refCompositeTarget
+	entries []refEntry
+}
+
+func (w *refWalker) key(kind refKind, name string) refKey {
+	return refKey{kind: kind, name: name, path: w.src.path, body: w.body, scope: -1}
+}
+
+func (w *refWalker) add(key refKey, start int, name string, decl bool) {
+	w.entries = append(w.entries, refEntry{
+		key: key,
+		occ: refOccurrence{source: w.src, start: start, end: start + len(name), decl: decl},
+	})
+}
+
+func (w *refWalker) scoped(kind refKind, name string, scope *refScope) refKey {
+	key := w.key(kind, name)
+	key.scope = scope.start
+	return key
+}
+
+func (w *refWalker) walk(e parse.Expr, scope *refScope) {
+	switch v := e.(type) {
+	case nil:
+		return
+	case *parse.ObjectLit:
+		if v == nil {
+			return
+		}
+		inner := scope
+		if forEach := objectForEachField(v); forEach != nil {
+			inner = &refScope{parent: scope, start: v.S.Start.Offset, forEach: true}
+			w.add(w.scoped(refEach, "@each", inner), forEach.Key.S.Start.Offset, "@for-each", true)
+			if levels, ok := forEach.Value.(*parse.ArrayLit); ok {
+				for _, level := range levels.Elements {
+					obj, ok := level.(*parse.ObjectLit)
+					if !ok {
+						w.walk(level, inner)
+						continue
+					}
+					for _, field := range obj.Fields {
+						if field.Key.Kind == parse.FieldIdent && field.Key.IsMeta() {
+							inner.bindings = append(inner.bindings, field.Key.Name)
+							w.add(w.scoped(refBinding, field.Key.Name, inner),
+								field.Key.S.Start.Offset, field.Key.Name, true)
+						}
+						w.walk(field.Value, inner)
+					}
+				}
+			} else {
+				w.walk(forEach.Value, scope)
+			}
+		}
+		for _, field := range v.Fields {
+			if field.Key.Kind == parse.FieldIdent && field.Key.Name == "@for-each" {
+				continue
+			}
+			if field.Decl != nil {
+				w.walk(field.Decl.Body, inner)
+				continue
+			}
+			w.walk(field.Value, inner)
+		}
+	case *parse.ArrayLit:
+		for _, el := range v.Elements {
+			w.walk(el, scope)
+		}
+	case *parse.Call:
+		for _, arg := range v.Args {
+			w.walk(arg, scope)
+		}
+	case *parse.Infix:
+		w.walk(v.Left, scope)
+		w.walk(v.Right, scope)
+	case *parse.Prefix:
+		w.walk(v.Expr, scope)
+	case *parse.Conditional:
+		w.walk(v.Cond, scope)
+		w.walk(v.Then, scope)
+		w.walk(v.Else, scope)
+	case *parse.InterpolatedString:
+		for _, part := range v.Parts {
+			w.walk(part.Expr, scope)
+		}
+	case *parse.Comprehension:
+		w.walk(v.Source, scope)
+		inner := &refScope{parent: scope, start: v.S.Start.Offset, names: v.Names}
+		for name, start := range comprehensionNameOffsets(w.src.text, v) {
+			w.add(w.scoped(refComprehension, name, inner), start, name, true)
+		}
+		w.walk(v.Key, inner)
+		w.walk(v.Value, inner)
+		w.walk(v.Filter, inner)
+	case *parse.Ident:
+		if s, ok := scope.comprehension(v.Name); ok {
+			w.add(w.scoped(refComprehension, v.Name, s), v.S.Start.Offset, v.Name, false)
+		}
+	case *parse.DotPath:
+		w.path(v, scope)
+		for _, seg := range v.Segments {
+			w.walk(seg.Index, scope)
+		}
+	}
+}
+
+// path records the names a dot path references: its root when that is
+// an iteration name, otherwise the declaration its first segment names
+// and, for a composite call site, the output a later segment reads.
+func (w *refWalker) path(p *parse.DotPath, scope *refScope) {
+	if p.Root == nil {
+		return
+	}
+	root := p.Root.Name
+	rootStart := p.Root.S.Start.Offset
+	switch {
+	case root == "@each":
+		if s, ok := scope.each(); ok {
+			w.add(w.scoped(refEach, root, s), rootStart, root, false)
+		}
+		return
+	case strings.HasPrefix(root, "@"):
+		if s, ok := scope.binding(root); ok {
+			w.add(w.scoped(refBinding, root, s), rootStart, root, false)
+		}
+		return
+	}
+	if s, ok := scope.comprehension(root); ok {
+		w.add(w.scoped(refComprehension, root, s), rootStart, root, false)
+		return
+	}
+	if len(p.Segments) == 0 || p.Segments[0].Name == "" {
+		return
+	}
+	name := p.Segments[0].Name
+	start := segmentNameOffset(w.src.text, p.Segments[0])
+	switch root {
+	case "input":
+		if _, ok := w.decls.inputs[name]; ok {
+			w.add(w.key(refInput, name), start, name, false)
+		}
+	case "local":
+		if _, ok := w.decls.locals[name]; ok {
+			w.add(w.key(refLocal, name), start, name, false)
+		}
+	case "asset":
+		if _, ok := w.decls.assets[name]; ok {
+			w.add(w.key(refAsset, name), start, name, false)
+		}
+	case string(syntax.NodeResource), string(syntax.NodeDataSource), string(syntax.NodeAction):
+		kind := syntax.NodeKind(root)
+		if _, ok := w.decls.nodes[kind][name]; !ok {
+			return
+		}
+		key := w.key(refNode, name)
+		key.nodeKind = kind
+		w.add(key, start, name, false)
+		w.output(p, w.targets[root+"."+name])
+	}
+}
+
+// output records the composite output a call site's dot path reads:
+// the first named segment after the node, past any instance index.
+func (w *refWalker) output(p *parse.DotPath, target refCompositeTarget) {
+	if target.decl == nil {
+		return
+	}
+	for _, seg := range p.Segments[1:] {
+		if seg.Name == "" {
+			continue
+		}
+		if !slices.ContainsFunc(target.decl.Body.Outputs, func(out syntax.OutputDecl) bool {
+			return out.Name.Name == seg.Name
+		}) {
+			return
+		}
+		w.add(refKey{
+			kind: refOutput, name: seg.Name, path: target.source.path,
+			body: target.decl.Name.S.Start.Offset, scope: -1,
+		}, segmentNameOffset(w.src.text, seg), seg.Name, false)
+		return
+	}
+}
+
+func objectForEachField(obj *parse.ObjectLit) *parse.Field {
+	for _, field := range obj.Fields {
+		if field.Key.Kind == parse.FieldIdent && field.Key.Name == "@for-each" {
+			return field
+		}
+	}
+	return nil
+}
+
+// factoryBodyRoots calls visit with each top-level expression of body.
+func factoryBodyRoots(body *syntax.FactoryBody, visit func(parse.Expr)) {
+	if body == nil {
+		return
+	}
+	for _, input := range body.Inputs {
+		visit(input.Body)
+	}
+	for _, local := range body.Locals {
+		visit(local.Value)
+	}
+	for _, constraint := range body.Constraints {
+		visit(constraint.Value)
+	}
+	for _, config := range body.LibraryConfigs {
+		visit(config.Value)
+	}
+	for _, node := range allNodes(*body) {
+		visit(node.Body)
+	}
+	for _, output := range body.Outputs {
+		visit(output.Body)
+	}
+	for _, check := range body.Checks {
+		visit(check.Value)
+	}
+}
+
+func identOccurrence(src *refSource, ident syntax.Ident, decl bool) refOccurrence {
+	start := ident.S.Start.Offset
+	return refOccurrence{source: src, start: start, end: start + len(ident.Name), decl: decl}
+}
+
+// segmentNameOffset returns where a `.name` or `?.name` segment's name
+// starts.
+func segmentNameOffset(text string, seg parse.DotSegment) int {
+	start := seg.S.Start.Offset
+	for start < len(text) && (text[start] == '?' || text[start] == '.') {
+		start++
+	}
+	return start
+}
+
+// symbolNameEnd returns the end of the identifier starting at start,
+// which unlike symbolEnd stops at a dot.
+func symbolNameEnd(text string, start int) int {
+	end := start
+	for end < len(text) && text[end] != '.' && isSymbolByte(text[end]) {
+		end++
+	}
+	return end
+}
+
+// comprehensionNameOffsets finds where each name a comprehension binds
+// is written in its `for a, b in` header, which the parser does not
+// record.
+func comprehensionNameOffsets(text string, comp *parse.Comprehension) map[string]int {
+	out := make(map[string]int, len(comp.Names))
+	i := comp.S.Start.Offset
+	if i < 0 || i >= len(text) {
+		return out
+	}
+	i++
+	skip := func() {
+		for i < len(text) && isSpaceByte(text[i]) {
+			i++
+		}
+	}
+	skip()
+	if !strings.HasPrefix(text[i:], "for") {
+		return out
+	}
+	i += len("for")
+	for range comp.Names {
+		skip()
+		end := symbolNameEnd(text, i)
+		if end == i {
+			break
+		}
+		name := text[i:end]
+		if slices.Contains(comp.Names, name) {
+			if _, dup := out[name]; !dup {
+				out[name] = i
+			}
+		}
+		i = end
+		skip()
+		if i < len(text) && text[i] == ',' {
+			i++
+		}
+	}
+	return out
+}
@@ -0,0 +1,193 @@
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudboss/unobin/internal/ubtest"
+	"github.com/cloudboss/unobin/pkg/lsp/protocol"
+)
+
+func TestReferencesInputName(t *testing.T) {
+	root, path, source, _ := referencesProject(t)
+
+	locations, rpcErr := ReferencesForText(path, source,
+		positionInText(source, "input.region", "region"), true, NewProjectCache(root), nil)
+	require.Nil(t, rpcErr)
+	require.Equal(t, []int{
+		offsetInText(source, "region: { type", "region"),
+		offsetInText(source, "name:       input.region", "region"),
+		offsetInText(source, "}}-{{ input.region", "region"),
+	}, locationOffsets(t, locations, path, source))
+
+	locations, rpcErr = ReferencesForText(path, source,
+		positionInText(source, "region: { type", "region"), false, NewProjectCache(root), nil)
+	require.Nil(t, rpcErr)
+	require.Len(t, locations, 2)
+}
+
+func TestReferencesResourceIncludesOutputReads(t *testing.T) {
+	root, path, source, _ := referencesProject(t)
+
+	locations, rpcErr := ReferencesForText(path, source,
+		positionInText(source, "server: bundle", "server"), true, NewProjectCache(root), nil)
+	require.Nil(t, rpcErr)
+	require.Equal(t, []int{
+		offsetInText(source, "server: bundle", "server"),
+		offsetInText(source, "resource.server.id", "server"),
+	}, locationOffsets(t, locations, path, source))
+}
+
+func TestReferencesCompositeAcrossFiles(t *testing.T) {
+	root, path, source, libraryPath := referencesProject(t)
+	librarySource := ubtest.ReadFixture(t, libraryPath)
+
+	locations, rpcErr := ReferencesForText(libraryPath, librarySource,
+		positionInText(librarySource, "web: resource", "web"), true, NewProjectCache(root), nil)
+	require.Nil(t, rpcErr)
+	require.Equal(t, []int{
+		offsetInText(source, "server: bundle.web", "web"),
+		offsetInText(source, "zonal: bundle.web", "web"),
+	}, locationOffsets(t, locations, path, source))
+	require.Equal(t, []int{
+		offsetInText(librarySource, "web: resource", "web"),
+	}, locationOffsets(t, locations, libraryPath, librarySource))
+}
+
+func TestReferencesCompositeInputAndOutput(t *testing.T) {
+	root, path, source, libraryPath := referencesProject(t)
+	librarySource := ubtest.ReadFixture(t, libraryPath)
+	cache := NewProjectCache(root)
+
+	locations, rpcErr := ReferencesForText(path, source,
+		positionInText(source, "{ name: local.name", "name"), true, cache, nil)
+	require.Nil(t, rpcErr)
+	require.Equal(t, []int{
+		offsetInText(source, "{ name: local.name", "name"),
+		offsetInText(source, "name:      @each", "name"),
+	}, locationOffsets(t, locations, path, source))
+	require.Equal(t, []int{
+		offsetInText(librarySource, "name: { type", "name"),
+		offsetInText(librarySource, "input.name", "name"),
+	}, locationOffsets(t, locations, libraryPath, librarySource))
+
+	locations, rpcErr = ReferencesForText(libraryPath, librarySource,
+		positionInText(librarySource, "id: { value", "id"), true, cache, nil)
+	require.Nil(t, rpcErr)
+	require.Equal(t, []int{
+		offsetInText(source, "resource.server.id", "id"),
+	}, locationOffsets(t, locations, path, source))
+}
+
+func TestReferencesIterationNames(t *testing.T) {
+	root, path, source, _ := referencesProject(t)
+	cache := NewProjectCache(root)
+
+	locations, rpcErr := ReferencesForText(path, source,
+		positionInText(source, "@each.value", "@each"), true, cache, nil)
+	require.Nil(t, rpcErr)
+	require.Equal(t, []int{
+		offsetInText(source, "@for-each: input.zones", "@for-each"),
+		offsetInText(source, "@each.value", "@each"),
+	}, locationOffsets(t, locations, path, source))
+
+	locations, rpcErr = ReferencesForText(path, source,
+		positionInText(source, "@zone.value", "@zone"), true, cache, nil)
+	require.Nil(t, rpcErr)
+	require.Equal(t, []int{
+		offsetInText(source, "{ @zone:", "@zone"),
+		offsetInText(source, "@zone.value", "@zone"),
+	}, locationOffsets(t, locations, path, source))
+
+	locations, rpcErr = ReferencesForText(path, source,
+		positionInText(source, "{{ zone }}", "zone"), true, cache, nil)
+	require.Nil(t, rpcErr)
+	require.Equal(t, []int{
+		offsetInText(source, "for zone in", "zone"),
+		offsetInText(source, "{{ zone }}", "zone"),
+	}, locationOffsets(t, locations, path, source))
+}
+
+func TestReferencesPrefersOpenDocumentText(t *testing.T) {
+	root, path, source, libraryPath := referencesProject(t)
+	librarySource := ubtest.ReadFixture(t, libraryPath)
+	documents := NewDocumentStore()
+	edited := source + "\n"
+	_, err := documents.Open(PathToFileURI(path), 2, edited)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, []byte("not: ["), 0o644))
+
+	locations, rpcErr := ReferencesForText(libraryPath, librarySource,
+		positionInText(librarySource, "web: resource", "web"), false, NewProjectCache(root), documents)
+	require.Nil(t, rpcErr)
+	require.Len(t, locationOffsets(t, locations, path, edited), 2)
+}
+
+func TestSessionReferencesReturnsLocations(t *testing.T) {
+	root, path, source, _ := referencesProject(t)
+	session := NewSession("dev")
+	session.projects = NewProjectCache(root)
+	uri := PathToFileURI(path)
+	require.Nil(t, openDocument(t, session, uri, 1, source))
+
+	result, rpcErr := sessionRequest(t, session, "textDocument/references", protocol.ReferenceParams{
+		TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+		Position:     positionInText(source, "local.name", "name"),
+		Context:      protocol.ReferenceContext{IncludeDeclaration: true},
+	})
+	require.Nil(t, rpcErr)
+	locations, ok := result.([]protocol.Location)
+	require.True(t, ok)
+	require.Equal(t, []int{
+		offsetInText(source, "name:       input", "name"),
+		offsetInText(source, "local.name", "name"),
+	}, locationOffsets(t, locations, path, source))
+}
+
+func referencesProject(t *testing.T) (string, string, string, string) {
+	t.Helper()
+	root := writeUBProject(t, nil, nil)
+	source := ubtest.ReadFixture(t, "testdata/ub/references/valid/factory.ub")
+	path := filepath.Join(root, "factory.ub")
+	require.NoError(t, os.WriteFile(path, []byte(source), 0o644))
+	bundleDir := filepath.Join(root, "bundle")
+	require.NoError(t, os.MkdirAll(bundleDir, 0o755))
+	librarySource := ubtest.ReadFixture(t, "testdata/ub/references/valid/bundle/library.ub")
+	libraryPath := filepath.Join(bundleDir, "library.ub")
+	require.NoError(t, os.WriteFile(libraryPath, []byte(librarySource), 0o644))
+	return root, path, source, libraryPath
+}
+
+// locationOffsets returns the start offsets of the locations in path.
+func locationOffsets(t *testing.T, locations []protocol.Location, path, text string) []int {
+	t.Helper()
+	var offsets []int
+	for _, location := range locations {
+		if location.URI != PathToFileURI(path) {
+			continue
+		}
+		offset, ok := LSPToOffset(text, location.Range.Start)
+		require.True(t, ok)
+		offsets = append(offsets, offset)
+	}
+	return offsets
+}
+
+func sessionRequest(
+	t *testing.T,
+	session *Session,
+	method string,
+	params any,
+) (any, *protocol.ResponseError) {
+	t.Helper()
+	body, err := json.Marshal(params)
+	require.NoError(t, err)
+	return session.HandleRequest(context.Background(), &protocol.RequestMessage{
+		JSONRPC: "2.0", Method: method, Params: body,
+	})
+}
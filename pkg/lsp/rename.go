@@ -0,0 +1,320 @@
+package lsp
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/cloudboss/unobin/pkg/lang"
+	"github.com/cloudboss/unobin/pkg/lang/syntax"
+	"github.com/cloudboss/unobin/pkg/lsp/protocol"
+)
+
+// stateMoveAnnotation is the change annotation that marks the
+// state-moves entry a rename offers to add.
+const stateMoveAnnotation = "state-move"
+
+// RenameClient is what a client can do with a rename's workspace edit.
+// A state-moves entry is offered only to a client that can show it as
+// an annotated change for the user to confirm.
+type RenameClient struct {
+	DocumentChanges   bool
+	ChangeAnnotations bool
+}
+
+// PrepareRenameForText resolves a prepare-rename request to the name
+// under the cursor, or nil when there is nothing to rename there.
+func PrepareRenameForText(
+	path string,
+	text string,
+	pos protocol.Position,
+	projects *ProjectCache,
+	documents *DocumentStore,
+) (*protocol.PrepareRenameResult, *protocol.ResponseError) {
+	offset, ok := LSPToOffset(text, pos)
+	if !ok {
+		return nil, protocol.InvalidParams("invalid document position")
+	}
+	refs := newRefIndex(projects, documents)
+	src, ok := refs.add(path, text)
+	if !ok {
+		return nil, nil
+	}
+	entry, ok := refs.entryAt(src, offset)
+	if !ok {
+		return nil, nil
+	}
+	if entry.key.kind == refEach {
+		return nil, protocol.RequestFailed("@each is built in and cannot be renamed")
+	}
+	return &protocol.PrepareRenameResult{
+		Range:       entry.occ.lspRange(),
+		Placeholder: entry.key.name,
+	}, nil
+}
+
+// RenameForText resolves a rename request to the edits that rename the
+// name under the cursor everywhere it is written. Renaming a resource,
+// or any node that calls a local composite, also offers a state-moves
+// entry so its state follows the new name.
+func RenameForText(
+	path string,
+	text string,
+	pos protocol.Position,
+	newName string,
+	projects *ProjectCache,
+	documents *DocumentStore,
+	client RenameClient,
+) (*protocol.WorkspaceEdit, *protocol.ResponseError) {
+	offset, ok := LSPToOffset(text, pos)
+	if !ok {
+		return nil, protocol.InvalidParams("invalid document position")
+	}
+	refs := newRefIndex(projects, documents)
+	src, ok := refs.add(path, text)
+	if !ok {
+		return nil, protocol.RequestFailed("cannot rename in a file with syntax errors")
+	}
+	entry, ok := refs.entryAt(src, offset)
+	if !ok {
+		return nil, protocol.RequestFailed("no symbol to rename at this position")
+	}
+	key := entry.key
+	if err := validateRenameName(key, newName); err != nil {
+		return nil, err
+	}
+	if err := refs.renameConflict(key, newName); err != nil {
+		return nil, err
+	}
+	occurrences, err := refs.occurrences(src, key)
+	if err != nil {
+		return nil, protocol.InternalError(err)
+	}
+	edits := map[string][]protocol.TextEdit{}
+	var order []*refSource
+	for _, occ := range occurrences {
+		if _, ok := edits[occ.source.path]; !ok {
+			order = append(order, occ.source)
+		}
+		edits[occ.source.path] = append(edits[occ.source.path], protocol.TextEdit{
+			Range:   occ.lspRange(),
+			NewText: newName,
+		})
+	}
+	var annotations map[string]protocol.ChangeAnnotation
+	if client.DocumentChanges && client.ChangeAnnotations && refs.hasState(key) {
+		if move, ok := refs.stateMoveEdit(key, key.name, newName); ok {
+			move.edit.AnnotationID = stateMoveAnnotation
+			if _, ok := edits[move.source.path]; !ok {
+				order = append(order, move.source)
+			}
+			edits[move.source.path] = append(edits[move.source.path], move.edit)
+			annotations = map[string]protocol.ChangeAnnotation{
+				stateMoveAnnotation: {
+					Label:             "Add state move",
+					NeedsConfirmation: true,
+					Description: fmt.Sprintf("Move state from %s.%s to %s.%s so it is not replaced.",
+						key.nodeKind, key.name, key.nodeKind, newName),
+				},
+			}
+		}
+	}
+	if !client.DocumentChanges {
+		return &protocol.WorkspaceEdit{Changes: uriEdits(edits)}, nil
+	}
+	changes := make([]protocol.TextDocumentEdit, 0, len(order))
+	for _, src := range order {
+		change := protocol.TextDocumentEdit{
+			TextDocument: protocol.OptionalVersionedTextDocumentIdentifier{
+				URI: PathToFileURI(src.path),
+			},
+			Edits: edits[src.path],
+		}
+		if doc, ok := documents.GetPath(src.path); ok {
+			version := doc.Version
+			change.TextDocument.Version = &version
+		}
+		changes = append(changes, change)
+	}
+	return &protocol.WorkspaceEdit{DocumentChanges: changes, ChangeAnnotations: annotations}, nil
+}
+
+func uriEdits(edits map[string][]protocol.TextEdit) map[string][]protocol.TextEdit {
+	out := make(map[string][]protocol.TextEdit, len(edits))
+	for path, list := range edits {
+		out[PathToFileURI(path)] = list
+	}
+	return out
+}
+
+// renameRoots are the names that start a reference path, which a
+// comprehension name would hide.
+var renameRoots = []string{
+	"input", "local", "asset",
+	string(syntax.NodeResource), string(syntax.NodeDataSource), string(syntax.NodeAction),
+	"true", "false", "null",
+}
+
+// validateRenameName checks that newName can be written where key's
+// name is: a binding is @-named, every other name is not.
+func validateRenameName(key refKey, newName string) *protocol.ResponseError {
+	switch {
+	case key.kind == refEach:
+		return protocol.RequestFailed("@each is built in and cannot be renamed")
+	case !lang.IsKebabIdent(newName):
+		return protocol.RequestFailed(fmt.Sprintf("%q is not a valid name", newName))
+	case key.kind == refBinding:
+		if !strings.HasPrefix(newName, "@") {
+			return protocol.RequestFailed(fmt.Sprintf("binding %q must be @-named", newName))
+		}
+		if newName == "@each" || newName == "@for-each" || newName == lang.CoreNamespace {
+			return protocol.RequestFailed(fmt.Sprintf("%s is reserved", newName))
+		}
+	case strings.HasPrefix(newName, "@"):
+		return protocol.RequestFailed(fmt.Sprintf("%q cannot be @-named", newName))
+	case key.kind == refComprehension && slices.Contains(renameRoots, newName):
+		return protocol.RequestFailed(fmt.Sprintf("%s is reserved", newName))
+	}
+	return nil
+}
+
+// renameConflict rejects a new name that another declaration of the
+// same kind already uses where key's name is declared. A composite's
+// name must be unused across the files of its library.
+func (x *refIndex) renameConflict(key refKey, newName string) *protocol.ResponseError {
+	paths := []string{key.path}
+	if key.kind == refComposite {
+		matches, _ := filepath.Glob(filepath.Join(filepath.Dir(key.path), "*.ub"))
+		paths = append(paths, matches...)
+	}
+	for _, path := range paths {
+		src, ok := x.load(path)
+		if !ok {
+			continue
+		}
+		for _, entry := range x.entriesFor(src) {
+			other := entry.key
+			if !entry.occ.decl || other.kind != key.kind || other.name != newName ||
+				other.nodeKind != key.nodeKind {
+				continue
+			}
+			if key.kind != refComposite &&
+				(other.path != key.path || other.body != key.body || other.scope != key.scope) {
+				continue
+			}
+			return protocol.RequestFailed(fmt.Sprintf("%s is already declared", newName))
+		}
+	}
+	return nil
+}
+
+// refEdit is one edit to the file src.
+type refEdit struct {
+	source *refSource
+	edit   protocol.TextEdit
+}
+
+// hasState reports whether key names a node whose state a rename would
+// lose: a resource, or a node that calls a composite.
+func (x *refIndex) hasState(key refKey) bool {
+	if key.kind != refNode {
+		return false
+	}
+	if src, ok := x.load(key.path); ok {
+		x.entriesFor(src)
+	}
+	return key.nodeKind == syntax.NodeResource || x.calls[key]
+}
+
+// stateMoveEdit returns the edit that adds `{ from: kind.from, to:
+// kind.to }` to the state-moves of the body declaring the node key
+// names, creating the block before the node's block when there is
+// none. There is no edit when a move from kind.from already exists.
+func (x *refIndex) stateMoveEdit(key refKey, from, to string) (refEdit, bool) {
+	src, ok := x.load(key.path)
+	if !ok {
+		return refEdit{}, false
+	}
+	body := refBody(src.file, key.body)
+	if body == nil {
+		return refEdit{}, false
+	}
+	from = string(key.nodeKind) + "." + from
+	for _, move := range body.StateMoves {
+		if move.From != nil && move.From.Ref.String() == from {
+			return refEdit{}, false
+		}
+	}
+	raw, err := lang.ParseSource(src.path, []byte(src.text))
+	if err != nil {
+		return refEdit{}, false
+	}
+	obj := rawObjectAt(raw.Body, body.S.Start.Offset)
+	if obj == nil {
+		return refEdit{}, false
+	}
+	entry := fmt.Sprintf("{ from: %s, to: %s.%s }", from, key.nodeKind, to)
+	at, insert, ok := stateMoveInsertion(src.text, obj, key.nodeKind, entry)
+	if !ok {
+		return refEdit{}, false
+	}
+	return refEdit{source: src, edit: insertEdit(src.text, at, insert)}, true
+}
+
+// refBody returns the factory body, for body -1, or the body of the
+// composite whose name starts at offset body.
+func refBody(file *syntax.File, body int) *syntax.FactoryBody {
+	if body < 0 {
+		if file.Factory == nil {
+			return nil
+		}
+		return &file.Factory.Body
+	}
+	if file.Library == nil {
+		return nil
+	}
+	for i := range file.Library.Exports {
+		if file.Library.Exports[i].Name.S.Start.Offset == body {
+			return &file.Library.Exports[i].Body
+		}
+	}
+	return nil
+}
+
+// stateMoveInsertion returns where to insert entry into the state-moves
+// array of body, and the text to insert. Without a state-moves array
+// the block is added before the block that declares nodes of kind.
+func stateMoveInsertion(
+	text string,
+	body *lang.ObjectLit,
+	kind syntax.NodeKind,
+	entry string,
+) (int, string, bool) {
+	moves := objectField(body, "state-moves")
+	if moves == nil {
+		at, insert := objectFieldInsertion(text, body,
+			"state-moves: [\n  "+entry+",\n]", []string{nodeBlockNames[kind]})
+		return at, insert, true
+	}
+	arr, ok := moves.Value.(*lang.ArrayLit)
+	if !ok {
+		return 0, "", false
+	}
+	if len(arr.Elements) == 0 {
+		open := arr.S.Start.Offset
+		indent := lineIndent(text, moves.Key.S.Start.Offset)
+		return open + 1, "\n" + indent + "  " + entry + ",\n" + indent, true
+	}
+	last := arr.Elements[len(arr.Elements)-1]
+	end := last.Span().End.Offset
+	if end <= last.Span().Start.Offset {
+		return 0, "", false
+	}
+	indent := lineIndent(text, last.Span().Start.Offset)
+	next := skipBlank(text, end)
+	if next < len(text) && text[next] == ',' {
+		return next + 1, "\n" + indent + entry + ",", true
+	}
+	return end, ",\n" + indent + entry, true
+}
@@ -0,0 +1,211 @@
+package lsp
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudboss/unobin/internal/ubtest"
+	"github.com/cloudboss/unobin/pkg/lsp/protocol"
+)
+
+var annotatedRenameClient = RenameClient{DocumentChanges: true, ChangeAnnotations: true}
+
+func TestPrepareRenameReturnsNameRange(t *testing.T) {
+	root, path, source, _ := referencesProject(t)
+
+	result, rpcErr := PrepareRenameForText(path, source,
+		positionInText(source, "local.name", "name"), NewProjectCache(root), nil)
+	require.Nil(t, rpcErr)
+	require.NotNil(t, result)
+	require.Equal(t, "name", result.Placeholder)
+	start := offsetInText(source, "local.name", "name")
+	require.Equal(t, protocol.Range{
+		Start: OffsetToLSP(source, start),
+		End:   OffsetToLSP(source, start+len("name")),
+	}, result.Range)
+}
+
+func TestPrepareRenameRejectsEach(t *testing.T) {
+	root, path, source, _ := referencesProject(t)
+
+	result, rpcErr := PrepareRenameForText(path, source,
+		positionInText(source, "@each.value", "@each"), NewProjectCache(root), nil)
+	require.Nil(t, result)
+	require.NotNil(t, rpcErr)
+	require.Equal(t, protocol.ErrorCodeRequestFailed, rpcErr.Code)
+}
+
+func TestRenameCompositeInputAcrossFiles(t *testing.T) {
+	root, path, source, libraryPath := referencesProject(t)
+	librarySource := ubtest.ReadFixture(t, libraryPath)
+
+	edit, rpcErr := RenameForText(path, source,
+		positionInText(source, "{ name: local.name", "name"), "title",
+		NewProjectCache(root), nil, RenameClient{})
+	require.Nil(t, rpcErr)
+	require.Len(t, edit.Changes, 2)
+	factory := applyTextEdits(t, source, edit.Changes[PathToFileURI(path)])
+	require.Contains(t, factory, "server: bundle.web { title: local.name }")
+	require.Contains(t, factory, "title:      @each.value")
+	library := applyTextEdits(t, librarySource, edit.Changes[PathToFileURI(libraryPath)])
+	require.Contains(t, library, "title: { type: string }")
+	require.Contains(t, library, "{{ input.title }}")
+}
+
+func TestRenameBindingAndComprehensionName(t *testing.T) {
+	root, path, source, _ := referencesProject(t)
+	cache := NewProjectCache(root)
+
+	edit, rpcErr := RenameForText(path, source,
+		positionInText(source, "@zone.value", "@zone"), "@az", cache, nil, RenameClient{})
+	require.Nil(t, rpcErr)
+	renamed := applyTextEdits(t, source, edit.Changes[PathToFileURI(path)])
+	require.Contains(t, renamed, "{ @az: input.zones }")
+	require.Contains(t, renamed, "assert: @az.value != ''")
+
+	edit, rpcErr = RenameForText(path, source,
+		positionInText(source, "for zone in", "zone"), "z", cache, nil, RenameClient{})
+	require.Nil(t, rpcErr)
+	renamed = applyTextEdits(t, source, edit.Changes[PathToFileURI(path)])
+	require.Contains(t, renamed, "[ for z in input.zones : $'{{ z }}-{{ input.region }}' ]")
+}
+
+func TestRenameRejectsInvalidNames(t *testing.T) {
+	root, path, source, _ := referencesProject(t)
+	cache := NewProjectCache(root)
+	tests := []struct {
+		context string
+		target  string
+		newName string
+	}{
+		{context: "@zone.value", target: "@zone", newName: "zone"},
+		{context: "@zone.value", target: "@zone", newName: "@each"},
+		{context: "local.name", target: "name", newName: "@name"},
+		{context: "local.name", target: "name", newName: "zone-names"},
+		{context: "input.region", target: "region", newName: "bad name"},
+		{context: "for zone in", target: "zone", newName: "input"},
+		{context: "@each.value", target: "@each", newName: "@item"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.newName, func(t *testing.T) {
+			edit, rpcErr := RenameForText(path, source,
+				positionInText(source, tt.context, tt.target), tt.newName, cache, nil, RenameClient{})
+			require.Nil(t, edit)
+			require.NotNil(t, rpcErr)
+			require.Equal(t, protocol.ErrorCodeRequestFailed, rpcErr.Code)
+		})
+	}
+}
+
+func TestRenameResourceOffersStateMove(t *testing.T) {
+	root, path, source, _ := referencesProject(t)
+	documents := NewDocumentStore()
+	_, err := documents.Open(PathToFileURI(path), 3, source)
+	require.NoError(t, err)
+
+	edit, rpcErr := RenameForText(path, source,
+		positionInText(source, "resource.server.id", "server"), "api",
+		NewProjectCache(root), documents, annotatedRenameClient)
+	require.Nil(t, rpcErr)
+	require.Empty(t, edit.Changes)
+	require.Len(t, edit.DocumentChanges, 1)
+	change := edit.DocumentChanges[0]
+	require.Equal(t, PathToFileURI(path), change.TextDocument.URI)
+	require.NotNil(t, change.TextDocument.Version)
+	require.Equal(t, int32(3), *change.TextDocument.Version)
+	require.Contains(t, edit.ChangeAnnotations, stateMoveAnnotation)
+	require.True(t, edit.ChangeAnnotations[stateMoveAnnotation].NeedsConfirmation)
+	require.True(t, slices.ContainsFunc(change.Edits, func(e protocol.TextEdit) bool {
+		return e.AnnotationID == stateMoveAnnotation
+	}))
+
+	renamed := applyTextEdits(t, source, change.Edits)
+	want := ubtest.ReadFixture(t, "testdata/ub/references/valid/factory-renamed.ub.out")
+	require.Equal(t, want, renamed)
+}
+
+func TestRenameAppendsToExistingStateMoves(t *testing.T) {
+	root, _, _, _ := referencesProject(t)
+	source := ubtest.ReadFixture(t, "testdata/ub/references/valid/state-moves-factory.ub")
+	path := filepath.Join(root, "factory.ub")
+	require.NoError(t, os.WriteFile(path, []byte(source), 0o644))
+
+	edit, rpcErr := RenameForText(path, source,
+		positionInText(source, "server: bundle", "server"), "api",
+		NewProjectCache(root), nil, annotatedRenameClient)
+	require.Nil(t, rpcErr)
+	require.Len(t, edit.DocumentChanges, 1)
+	require.Nil(t, edit.DocumentChanges[0].TextDocument.Version)
+
+	renamed := applyTextEdits(t, source, edit.DocumentChanges[0].Edits)
+	want := ubtest.ReadFixture(t, "testdata/ub/references/valid/state-moves-factory-renamed.ub.out")
+	require.Equal(t, want, renamed)
+}
+
+func TestRenameWithoutAnnotationSupportSkipsStateMove(t *testing.T) {
+	root, path, source, _ := referencesProject(t)
+
+	edit, rpcErr := RenameForText(path, source,
+		positionInText(source, "server: bundle", "server"), "api",
+		NewProjectCache(root), nil, RenameClient{DocumentChanges: true})
+	require.Nil(t, rpcErr)
+	require.Empty(t, edit.ChangeAnnotations)
+	require.Len(t, edit.DocumentChanges, 1)
+	renamed := applyTextEdits(t, source, edit.DocumentChanges[0].Edits)
+	require.NotContains(t, renamed, "state-moves")
+	require.Contains(t, renamed, "api: bundle.web")
+}
+
+func TestSessionRenameUsesClientCapabilities(t *testing.T) {
+	root, path, source, _ := referencesProject(t)
+	session := NewSession("dev")
+	_, rpcErr := sessionRequest(t, session, "initialize", protocol.InitializeParams{
+		Capabilities: protocol.ClientCapabilities{
+			Workspace: &protocol.WorkspaceClientCapabilities{
+				WorkspaceEdit: &protocol.WorkspaceEditClientCapabilities{
+					DocumentChanges:         true,
+					ChangeAnnotationSupport: &protocol.ChangeAnnotationSupport{},
+				},
+			},
+		},
+	})
+	require.Nil(t, rpcErr)
+	session.projects = NewProjectCache(root)
+	uri := PathToFileURI(path)
+	require.Nil(t, openDocument(t, session, uri, 1, source))
+
+	result, rpcErr := sessionRequest(t, session, "textDocument/rename", protocol.RenameParams{
+		TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+		Position:     positionInText(source, "zonal: bundle", "zonal"),
+		NewName:      "zones",
+	})
+	require.Nil(t, rpcErr)
+	edit, ok := result.(*protocol.WorkspaceEdit)
+	require.True(t, ok)
+	require.Contains(t, edit.ChangeAnnotations, stateMoveAnnotation)
+}
+
+// applyTextEdits applies edits, which must not overlap, to text.
+func applyTextEdits(t *testing.T, text string, edits []protocol.TextEdit) string {
+	t.Helper()
+	sorted := slices.Clone(edits)
+	slices.SortStableFunc(sorted, func(a, b protocol.TextEdit) int {
+		start, ok := LSPToOffset(text, a.Range.Start)
+		require.True(t, ok)
+		other, ok := LSPToOffset(text, b.Range.Start)
+		require.True(t, ok)
+		return other - start
+	})
+	for _, edit := range sorted {
+		start, ok := LSPToOffset(text, edit.Range.Start)
+		require.True(t, ok)
+		end, ok := LSPToOffset(text, edit.Range.End)
+		require.True(t, ok)
+		text = text[:start] + edit.NewText + text[end:]
+	}
+	return text
+}
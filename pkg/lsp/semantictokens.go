@@ -0,0 +1,310 @@
+package lsp
+
+import (
+	"slices"
+	"unicode/utf8"
+
+	"github.com/cloudboss/unobin/pkg/lang"
+	"github.com/cloudboss/unobin/pkg/lang/parse"
+	"github.com/cloudboss/unobin/pkg/lang/syntax"
+	"github.com/cloudboss/unobin/pkg/lsp/protocol"
+)
+
+// semanticTokenTypes is the token type legend. A token's type is its
+// index here, so the order is part of the protocol the server
+// advertises.
+var semanticTokenTypes = []string{
+	"namespace",
+	"type",
+	"parameter",
+	"variable",
+	"property",
+	"function",
+	"keyword",
+	"class",
+}
+
+const (
+	tokenNamespace uint32 = iota
+	tokenType
+	tokenParameter
+	tokenVariable
+	tokenProperty
+	tokenFunction
+	tokenKeyword
+	tokenClass
+)
+
+// semanticTokenModifiers is the token modifier legend. A modifier's
+// bit in a token's modifier set is its index here.
+var semanticTokenModifiers = []string{
+	"declaration",
+	"readonly",
+	"defaultLibrary",
+}
+
+const (
+	modDeclaration uint32 = 1 << iota
+	modReadonly
+	modDefaultLibrary
+)
+
+// SemanticTokensLegend returns the legend the server's semantic token
+// data indexes.
+func SemanticTokensLegend() protocol.SemanticTokensLegend {
+	return protocol.SemanticTokensLegend{
+		TokenTypes:     slices.Clone(semanticTokenTypes),
+		TokenModifiers: slices.Clone(semanticTokenModifiers),
+	}
+}
+
+// semanticRefTokens gives each kind of resolved name its token type
+// and modifiers.
+var semanticRefTokens = map[refKind]struct{ typ, mods uint32 }{
+	refInput:         {tokenParameter, 0},
+	refLocal:         {tokenVariable, modReadonly},
+	refAsset:         {tokenVariable, modReadonly},
+	refNode:          {tokenClass, 0},
+	refOutput:        {tokenProperty, 0},
+	refComposite:     {tokenType, 0},
+	refEach:          {tokenVariable, modReadonly},
+	refBinding:       {tokenVariable, modReadonly},
+	refComprehension: {tokenVariable, modReadonly},
+}
+
+// referenceRoots are the dot path roots that name a kind of
+// declaration.
+var referenceRoots = map[string]bool{
+	"input":                       true,
+	"local":                       true,
+	"asset":                       true,
+	string(syntax.NodeResource):   true,
+	string(syntax.NodeDataSource): true,
+	string(syntax.NodeAction):     true,
+}
+
+// SemanticTokensForText classifies the names written in a .ub file by
+// what they resolve to: an input, a local, a node, a library, a type,
+// or a function. Names the file does not resolve are left to the
+// editor's syntax highlighting.
+func SemanticTokensForText(
+	path string,
+	text string,
+	projects *ProjectCache,
+	documents *DocumentStore,
+) (*protocol.SemanticTokens, *protocol.ResponseError) {
+	refs := newRefIndex(projects, documents)
+	src, ok := refs.add(path, text)
+	if !ok {
+		return &protocol.SemanticTokens{Data: []uint32{}}, nil
+	}
+	tokens := &semanticTokenSet{text: text, seen: map[int]bool{}}
+	for _, entry := range refs.entriesFor(src) {
+		if entry.occ.source != src {
+			continue
+		}
+		tok := semanticRefTokens[entry.key.kind]
+		mods := tok.mods
+		if entry.occ.decl {
+			mods |= modDeclaration
+		}
+		tokens.add(entry.occ.start, entry.occ.end, tok.typ, mods)
+	}
+	if src.file.Factory != nil {
+		tokens.body(&src.file.Factory.Body)
+	}
+	if library := src.file.Library; library != nil {
+		tokens.typeDecls(library.Types)
+		for _, t := range syntax.FunctionTypes(library.Functions) {
+			tokens.typeRefs(t)
+		}
+		for _, fn := range library.Functions {
+			tokens.function(fn)
+		}
+		for i := range library.Exports {
+			tokens.body(&library.Exports[i].Body)
+		}
+	}
+	return &protocol.SemanticTokens{Data: tokens.encode()}, nil
+}
+
+type semanticToken struct {
+	start int
+	end   int
+	typ   uint32
+	mods  uint32
+}
+
+// semanticTokenSet collects one file's tokens. The first token added
+// at an offset wins, so the resolved names go in first.
+type semanticTokenSet struct {
+	text   string
+	tokens []semanticToken
+	seen   map[int]bool
+}
+
+func (s *semanticTokenSet) add(start, end int, typ, mods uint32) {
+	if start < 0 || end <= start || end > len(s.text) || s.seen[start] {
+		return
+	}
+	s.seen[start] = true
+	s.tokens = append(s.tokens, semanticToken{start: start, end: end, typ: typ, mods: mods})
+}
+
+func (s *semanticTokenSet) ident(ident syntax.Ident, typ, mods uint32) {
+	start := ident.S.Start.Offset
+	s.add(start, start+len(ident.Name), typ, mods)
+}
+
+func (s *semanticTokenSet) body(body *syntax.FactoryBody) {
+	for _, imp := range body.Imports {
+		s.ident(imp.Alias, tokenNamespace, modDeclaration)
+	}
+	for _, config := range body.LibraryConfigs {
+		s.ident(config.Alias, tokenNamespace, 0)
+	}
+	s.typeDecls(body.Types)
+	for _, t := range syntax.BodyTypes(*body) {
+		s.typeRefs(t)
+	}
+	for _, node := range allNodes(*body) {
+		s.ident(node.Selector.Alias, tokenNamespace, 0)
+		s.ident(node.Selector.Export, tokenType, 0)
+	}
+	factoryBodyRoots(body, func(e parse.Expr) {
+		lang.Walk(e, s.expr)
+	})
+}
+
+func (s *semanticTokenSet) typeDecls(decls []syntax.TypeDecl) {
+	for _, decl := range decls {
+		s.ident(decl.Name, tokenType, modDeclaration)
+	}
+}
+
+// typeRefs adds the bare type names written in t. A name qualified
+// with a library is left to syntax highlighting.
+func (s *semanticTokenSet) typeRefs(t parse.TypeExpr) {
+	for _, ref := range syntax.NamedTypes(t) {
+		if ref.Library == "" {
+			start := ref.S.Start.Offset
+			s.add(start, start+len(ref.Name), tokenType, 0)
+		}
+	}
+}
+
+// function adds a library function's name, its parameters, and the
+// parameters its body reads.
+func (s *semanticTokenSet) function(fn syntax.FunctionDecl) {
+	s.ident(fn.Name, tokenFunction, modDeclaration)
+	params := map[string]bool{}
+	for _, param := range fn.Params {
+		params[param.Name.Name] = true
+		s.ident(param.Name, tokenParameter, modDeclaration)
+	}
+	lang.Walk(fn.Body, func(e parse.Expr) {
+		switch v := e.(type) {
+		case *parse.Ident:
+			if params[v.Name] {
+				start := v.S.Start.Offset
+				s.add(start, start+len(v.Name), tokenParameter, 0)
+			}
+		case *parse.DotPath:
+			if v.Root != nil && params[v.Root.Name] {
+				start := v.Root.S.Start.Offset
+				s.add(start, start+len(v.Root.Name), tokenParameter, 0)
+			}
+		}
+		s.expr(e)
+	})
+}
+
+// expr adds the tokens one expression writes outside its resolved
+// names: the root keyword of a reference, and the library and name of
+// a function call.
+func (s *semanticTokenSet) expr(e parse.Expr) {
+	switch v := e.(type) {
+	case *parse.DotPath:
+		if v.Root != nil && referenceRoots[v.Root.Name] {
+			start := v.Root.S.Start.Offset
+			s.add(start, start+len(v.Root.Name), tokenKeyword, 0)
+		}
+	case *parse.Call:
+		switch {
+		case v.Library != nil:
+			var mods uint32
+			if v.Library.Name == lang.CoreNamespace {
+				mods = modDefaultLibrary
+			}
+			start := v.Library.S.Start.Offset
+			s.add(start, start+len(v.Library.Name), tokenNamespace, mods)
+			if v.Func != nil {
+				if name, ok := callFuncOffset(s.text, v); ok {
+					s.add(name, name+len(v.Func.Name), tokenFunction, mods)
+				}
+			}
+		case v.Callee != nil:
+			start := v.Callee.S.Start.Offset
+			s.add(start, start+len(v.Callee.Name), tokenFunction, 0)
+		}
+	}
+}
+
+// callFuncOffset returns where a library-qualified call's function
+// name starts: just past the library alias and its dot.
+func callFuncOffset(text string, call *parse.Call) (int, bool) {
+	start := call.Library.S.Start.Offset + len(call.Library.Name)
+	if start >= len(text) || text[start] != '.' {
+		return 0, false
+	}
+	start++
+	end := start + len(call.Func.Name)
+	if end > len(text) || text[start:end] != call.Func.Name {
+		return 0, false
+	}
+	return start, true
+}
+
+// encode returns the tokens in the protocol's relative encoding, in
+// file order. A token that overlaps the one before it or spans a line
+// break is dropped.
+func (s *semanticTokenSet) encode() []uint32 {
+	slices.SortFunc(s.tokens, func(a, b semanticToken) int { return a.start - b.start })
+	data := make([]uint32, 0, len(s.tokens)*5)
+	var line, char, prevLine, prevChar uint32
+	offset, prevEnd := 0, 0
+	for _, tok := range s.tokens {
+		if tok.start < prevEnd {
+			continue
+		}
+		for offset < tok.start {
+			r, size := utf8.DecodeRuneInString(s.text[offset:])
+			if r == '\n' {
+				line++
+				char = 0
+			} else {
+				char += uint32(runeUTF16Len(r))
+			}
+			offset += size
+		}
+		length := 0
+		multiline := false
+		for _, r := range s.text[tok.start:tok.end] {
+			if r == '\n' {
+				multiline = true
+				break
+			}
+			length += runeUTF16Len(r)
+		}
+		if multiline {
+			continue
+		}
+		deltaChar := char
+		if line == prevLine {
+			deltaChar = char - prevChar
+		}
+		data = append(data, line-prevLine, deltaChar, uint32(length), tok.typ, tok.mods)
+		prevLine, prevChar, prevEnd = line, char, tok.end
+	}
+	return data
+}
@@ -0,0 +1,121 @@
+package lsp
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudboss/unobin/internal/ubtest"
+	"github.com/cloudboss/unobin/pkg/lsp/protocol"
+)
+
+func TestSemanticTokensClassifyResolvedNames(t *testing.T) {
+	root, path, source := semanticTokensProject(t)
+
+	tokens := semanticTokensFor(t, root, path, source)
+	cases := []struct {
+		context string
+		target  string
+		want    decodedToken
+	}{
+		{"region: { type", "region", decodedToken{"region", "parameter", []string{"declaration"}}},
+		{"input.region)", "input", decodedToken{"input", "keyword", nil}},
+		{"input.region)", "region", decodedToken{"region", "parameter", nil}},
+		{"name: @core", "name", decodedToken{"name", "variable", []string{"declaration", "readonly"}}},
+		{"@core.to-string", "@core", decodedToken{"@core", "namespace", []string{"defaultLibrary"}}},
+		{"@core.to-string", "to-string", decodedToken{"to-string", "function", []string{"defaultLibrary"}}},
+		{"bundle: './bundle'", "bundle", decodedToken{"bundle", "namespace", []string{"declaration"}}},
+		{"bundle.web {", "web", decodedToken{"web", "type", nil}},
+		{"local.name }", "name", decodedToken{"name", "variable", []string{"readonly"}}},
+		{"resource.server.id", "resource", decodedToken{"resource", "keyword", nil}},
+		{"resource.server.id", "server", decodedToken{"server", "class", nil}},
+		{"resource.server.id", "id", decodedToken{"id", "property", nil}},
+	}
+	for _, tc := range cases {
+		pos := positionInText(source, tc.context, tc.target)
+		require.Equal(t, tc.want, tokens[pos], "%s in %q", tc.target, tc.context)
+	}
+}
+
+func TestSemanticTokensInvalidSourceReturnsNoTokens(t *testing.T) {
+	root, path, source := semanticTokensProject(t)
+
+	tokens, rpcErr := SemanticTokensForText(path, source[:len(source)/2],
+		NewProjectCache(root), nil)
+	require.Nil(t, rpcErr)
+	require.Empty(t, tokens.Data)
+}
+
+func TestSessionSemanticTokensUsesLegend(t *testing.T) {
+	_, path, source := semanticTokensProject(t)
+	session := NewSession("dev")
+	uri := PathToFileURI(path)
+	rpcErr := openDocument(t, session, uri, 1, source)
+	require.Nil(t, rpcErr)
+
+	result, rpcErr := sessionRequest(t, session, "textDocument/semanticTokens/full",
+		protocol.SemanticTokensParams{
+			TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+		})
+	require.Nil(t, rpcErr)
+	tokens, ok := result.(*protocol.SemanticTokens)
+	require.True(t, ok)
+	require.NotEmpty(t, tokens.Data)
+	require.Zero(t, len(tokens.Data)%5)
+	legend := SemanticTokensLegend()
+	for i := 3; i < len(tokens.Data); i += 5 {
+		require.Less(t, int(tokens.Data[i]), len(legend.TokenTypes))
+		require.Less(t, tokens.Data[i+1], uint32(1)<<len(legend.TokenModifiers))
+	}
+}
+
+// semanticTokensProject writes the semantic tokens fixture as the
+// factory of the references project, beside its `bundle` library.
+func semanticTokensProject(t *testing.T) (string, string, string) {
+	t.Helper()
+	root, path, _, _ := referencesProject(t)
+	source := ubtest.ReadFixture(t, filepath.Join("testdata/ub/semantictokens/valid/factory.ub"))
+	require.NoError(t, os.WriteFile(path, []byte(source), 0o644))
+	return root, path, source
+}
+
+type decodedToken struct {
+	text      string
+	typ       string
+	modifiers []string
+}
+
+// semanticTokensFor decodes the semantic tokens for source by the
+// position each starts at.
+func semanticTokensFor(t *testing.T, root, path, source string) map[protocol.Position]decodedToken {
+	t.Helper()
+	tokens, rpcErr := SemanticTokensForText(path, source, NewProjectCache(root), nil)
+	require.Nil(t, rpcErr)
+	require.Zero(t, len(tokens.Data)%5)
+	legend := SemanticTokensLegend()
+	out := map[protocol.Position]decodedToken{}
+	var pos protocol.Position
+	for i := 0; i < len(tokens.Data); i += 5 {
+		if tokens.Data[i] > 0 {
+			pos = protocol.Position{Line: pos.Line + tokens.Data[i], Character: tokens.Data[i+1]}
+		} else {
+			pos.Character += tokens.Data[i+1]
+		}
+		start, ok := LSPToOffset(source, pos)
+		require.True(t, ok)
+		end, ok := LSPToOffset(source, protocol.Position{
+			Line: pos.Line, Character: pos.Character + tokens.Data[i+2],
+		})
+		require.True(t, ok)
+		var modifiers []string
+		for bit, name := range legend.TokenModifiers {
+			if tokens.Data[i+4]&(1<<bit) != 0 {
+				modifiers = append(modifiers, name)
+			}
+		}
+		out[pos] = decodedToken{source[start:end], legend.TokenTypes[tokens.Data[i+3]], modifiers}
+	}
+	return out
+}
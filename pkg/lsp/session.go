@@ -20,6 +20,7 @@ type Session struct {
 	version   string
 	documents *DocumentStore
 	projects  *ProjectCache
+	rename    RenameClient
 	shutdown  bool
 	exiting   bool
 	sender    protocol.Sender
@@ -112,6 +113,28 @@ func (s *Session) HandleRequest(
 		return s.handleCompletion(req.Params)
 	case "textDocument/hover":
 		return s.handleHover(req.Params)
+	case "textDocument/references":
+		return s.handleReferences(req.Params)
+	case "textDocument/prepareRename":
+		return s.handlePrepareRename(req.Params)
+	case "textDocument/rename":
+		return s.handleRename(req.Params)
+	case "textDocument/codeAction":
+		return s.handleCodeAction(req.Params)
+	case "textDocument/semanticTokens/full":
+		return s.handleSemanticTokens(req.Params)
+	case "textDocument/signatureHelp":
+		return s.handleSignatureHelp(req.Params)
+	case "textDocument/inlayHint":
+		return s.handleInlayHint(req.Params)
+	case "workspace/symbol":
+		return s.handleWorkspaceSymbol(req.Params)
+	case "textDocument/prepareCallHierarchy":
+		return s.handlePrepareCallHierarchy(req.Params)
+	case "callHierarchy/incomingCalls":
+		return s.handleIncomingCalls(req.Params)
+	case "callHierarchy/outgoingCalls":
+		return s.handleOutgoingCalls(req.Params)
 	default:
 		return nil, protocol.MethodNotFound(req.Method)
 	}
@@ -127,6 +150,12 @@ func (s *Session) handleInitialize(params json.RawMessage) (any, *protocol.Respo
 		return nil, protocol.InvalidParams(err.Error())
 	}
 	s.projects.SetWorkspaceRoots(roots)
+	if workspace := initialize.Capabilities.Workspace; workspace != nil && workspace.WorkspaceEdit != nil {
+		s.rename = RenameClient{
+			DocumentChanges:   workspace.WorkspaceEdit.DocumentChanges,
+			ChangeAnnotations: workspace.WorkspaceEdit.ChangeAnnotationSupport != nil,
+		}
+	}
 	return protocol.InitializeResult{
 		Capabilities: protocol.ServerCapabilities{
 			TextDocumentSync:           protocol.TextDocumentSyncKindFull,
@@ -136,7 +165,23 @@ func (s *Session) handleInitialize(params json.RawMessage) (any, *protocol.Respo
 			CompletionProvider: &protocol.CompletionOptions{
 				TriggerCharacters: []string{".", "@", ":", " "},
 			},
-			HoverProvider: true,
+			HoverProvider:      true,
+			ReferencesProvider: true,
+			RenameProvider:     &protocol.RenameOptions{PrepareProvider: true},
+			CodeActionProvider: &protocol.CodeActionOptions{
+				CodeActionKinds: []string{protocol.CodeActionKindQuickFix},
+			},
+			SemanticTokensProvider: &protocol.SemanticTokensOptions{
+				Legend: SemanticTokensLegend(),
+				Full:   true,
+			},
+			SignatureHelpProvider: &protocol.SignatureHelpOptions{
+				TriggerCharacters:   []string{"("},
+				RetriggerCharacters: []string{","},
+			},
+			InlayHintProvider:       true,
+			WorkspaceSymbolProvider: true,
+			CallHierarchyProvider:   true,
 		},
 		ServerInfo: &protocol.ServerInfo{Name: "unobin", Version: s.version},
 	}, nil
@@ -280,6 +325,134 @@ func (s *Session) handleHover(params json.RawMessage) (any, *protocol.ResponseEr
 	return HoverForText(doc.Path, doc.Text, hover.Position, s.projects)
 }
 
+func (s *Session) handleReferences(params json.RawMessage) (any, *protocol.ResponseError) {
+	var references protocol.ReferenceParams
+	if err := decodeParams(params, &references); err != nil {
+		return nil, err
+	}
+	doc, ok := s.documents.Get(references.TextDocument.URI)
+	if !ok {
+		return nil, protocol.InvalidParams("document is not open: " + references.TextDocument.URI)
+	}
+	return ReferencesForText(doc.Path, doc.Text, references.Position,
+		references.Context.IncludeDeclaration, s.projects, s.documents)
+}
+
+func (s *Session) handlePrepareRename(params json.RawMessage) (any, *protocol.ResponseError) {
+	var prepare protocol.PrepareRenameParams
+	if err := decodeParams(params, &prepare); err != nil {
+		return nil, err
+	}
+	doc, ok := s.documents.Get(prepare.TextDocument.URI)
+	if !ok {
+		return nil, protocol.InvalidParams("document is not open: " + prepare.TextDocument.URI)
+	}
+	result, err := PrepareRenameForText(doc.Path, doc.Text, prepare.Position, s.projects, s.documents)
+	if err != nil || result == nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *Session) handleRename(params json.RawMessage) (any, *protocol.ResponseError) {
+	var rename protocol.RenameParams
+	if err := decodeParams(params, &rename); err != nil {
+		return nil, err
+	}
+	doc, ok := s.documents.Get(rename.TextDocument.URI)
+	if !ok {
+		return nil, protocol.InvalidParams("document is not open: " + rename.TextDocument.URI)
+	}
+	return RenameForText(doc.Path, doc.Text, rename.Position, rename.NewName,
+		s.projects, s.documents, s.rename)
+}
+
+func (s *Session) handleCodeAction(params json.RawMessage) (any, *protocol.ResponseError) {
+	var codeAction protocol.CodeActionParams
+	if err := decodeParams(params, &codeAction); err != nil {
+		return nil, err
+	}
+	doc, ok := s.documents.Get(codeAction.TextDocument.URI)
+	if !ok {
+		return nil, protocol.InvalidParams("document is not open: " + codeAction.TextDocument.URI)
+	}
+	return CodeActionsForText(doc.Path, doc.Text, codeAction.Context.Diagnostics,
+		s.projects, s.documents)
+}
+
+func (s *Session) handleSemanticTokens(params json.RawMessage) (any, *protocol.ResponseError) {
+	var tokens protocol.SemanticTokensParams
+	if err := decodeParams(params, &tokens); err != nil {
+		return nil, err
+	}
+	doc, ok := s.documents.Get(tokens.TextDocument.URI)
+	if !ok {
+		return nil, protocol.InvalidParams("document is not open: " + tokens.TextDocument.URI)
+	}
+	return SemanticTokensForText(doc.Path, doc.Text, s.projects, s.documents)
+}
+
+func (s *Session) handleSignatureHelp(params json.RawMessage) (any, *protocol.ResponseError) {
+	var help protocol.SignatureHelpParams
+	if err := decodeParams(params, &help); err != nil {
+		return nil, err
+	}
+	doc, ok := s.documents.Get(help.TextDocument.URI)
+	if !ok {
+		return nil, protocol.InvalidParams("document is not open: " + help.TextDocument.URI)
+	}
+	return SignatureHelpForText(doc.Path, doc.Text, help.Position, s.projects)
+}
+
+func (s *Session) handleInlayHint(params json.RawMessage) (any, *protocol.ResponseError) {
+	var hints protocol.InlayHintParams
+	if err := decodeParams(params, &hints); err != nil {
+		return nil, err
+	}
+	doc, ok := s.documents.Get(hints.TextDocument.URI)
+	if !ok {
+		return nil, protocol.InvalidParams("document is not open: " + hints.TextDocument.URI)
+	}
+	return InlayHintsForText(doc.Path, doc.Text, hints.Range, s.projects)
+}
+
+func (s *Session) handleWorkspaceSymbol(params json.RawMessage) (any, *protocol.ResponseError) {
+	var symbols protocol.WorkspaceSymbolParams
+	if err := decodeParams(params, &symbols); err != nil {
+		return nil, err
+	}
+	return WorkspaceSymbolsForQuery(symbols.Query, s.projects, s.documents)
+}
+
+func (s *Session) handlePrepareCallHierarchy(params json.RawMessage) (any, *protocol.ResponseError) {
+	var prepare protocol.CallHierarchyPrepareParams
+	if err := decodeParams(params, &prepare); err != nil {
+		return nil, err
+	}
+	doc, ok := s.documents.Get(prepare.TextDocument.URI)
+	if !ok {
+		return nil, protocol.InvalidParams("document is not open: " + prepare.TextDocument.URI)
+	}
+	return PrepareCallHierarchyForText(doc.Path, doc.Text, prepare.Position,
+		s.projects, s.documents)
+}
+
+func (s *Session) handleIncomingCalls(params json.RawMessage) (any, *protocol.ResponseError) {
+	var incoming protocol.CallHierarchyIncomingCallsParams
+	if err := decodeParams(params, &incoming); err != nil {
+		return nil, err
+	}
+	return IncomingCallsForItem(incoming.Item, s.projects, s.documents)
+}
+
+func (s *Session) handleOutgoingCalls(params json.RawMessage) (any, *protocol.ResponseError) {
+	var outgoing protocol.CallHierarchyOutgoingCallsParams
+	if err := decodeParams(params, &outgoing); err != nil {
+		return nil, err
+	}
+	return OutgoingCallsForItem(outgoing.Item, s.projects, s.documents)
+}
+
 func (s *Session) invalidateURI(uri string) error {
 	path, err := FileURIToPath(uri)
 	if err != nil {
@@ -316,7 +489,7 @@ func (s *Session) publishDiagnostics(doc *Document) *protocol.ResponseError {
 		return nil
 	}
 	version := doc.Version
-	diagnostics := DiagnosticsForTextWithProjects(doc.Path, doc.Text, s.projects)
+	diagnostics := DocumentDiagnostics(doc, s.projects, s.documents)
 	if diagnostics == nil {
 		diagnostics = []protocol.Diagnostic{}
 	}
@@ -44,6 +44,22 @@ func TestSessionInitializeCapabilities(t *testing.T) {
 	require.Contains(t,
 		initialize.Capabilities.CompletionProvider.TriggerCharacters, " ")
 	require.True(t, initialize.Capabilities.HoverProvider)
+	require.True(t, initialize.Capabilities.ReferencesProvider)
+	require.NotNil(t, initialize.Capabilities.RenameProvider)
+	require.True(t, initialize.Capabilities.RenameProvider.PrepareProvider)
+	require.NotNil(t, initialize.Capabilities.CodeActionProvider)
+	require.Equal(t, []string{protocol.CodeActionKindQuickFix},
+		initialize.Capabilities.CodeActionProvider.CodeActionKinds)
+	require.NotNil(t, initialize.Capabilities.SemanticTokensProvider)
+	require.True(t, initialize.Capabilities.SemanticTokensProvider.Full)
+	require.Equal(t, SemanticTokensLegend(),
+		initialize.Capabilities.SemanticTokensProvider.Legend)
+	require.NotNil(t, initialize.Capabilities.SignatureHelpProvider)
+	require.Equal(t, []string{"("},
+		initialize.Capabilities.SignatureHelpProvider.TriggerCharacters)
+	require.True(t, initialize.Capabilities.InlayHintProvider)
+	require.True(t, initialize.Capabilities.WorkspaceSymbolProvider)
+	require.True(t, initialize.Capabilities.CallHierarchyProvider)
 	require.Equal(t, "unobin", initialize.ServerInfo.Name)
 	require.Equal(t, "dev", initialize.ServerInfo.Version)
 
@@ -72,7 +88,7 @@ func TestServeStopsAfterExit(t *testing.T) {
 		`{"jsonrpc":"2.0","method":"exit"}`,
 	)))
 	require.NoError(t, protocol.WriteMessage(&input, []byte(
-		`{"jsonrpc":"2.0","id":99,"method":"workspace/symbol"}`,
+		`{"jsonrpc":"2.0","id":99,"method":"workspace/executeCommand"}`,
 	)))
 	var output bytes.Buffer
 
@@ -257,7 +273,7 @@ func TestSessionUnknownMethod(t *testing.T) {
 	_, rpcErr := session.HandleRequest(context.Background(), &protocol.RequestMessage{
 		JSONRPC: "2.0",
 		ID:      protocol.NewNumberID(1),
-		Method:  "workspace/symbol",
+		Method:  "workspace/executeCommand",
 	})
 	require.NotNil(t, rpcErr)
 	require.Equal(t, protocol.ErrorCodeMethodNotFound, rpcErr.Code)
@@ -0,0 +1,176 @@
+package lsp
+
+import (
+	"strings"
+
+	"github.com/cloudboss/unobin/pkg/lang"
+	"github.com/cloudboss/unobin/pkg/lang/syntax"
+	"github.com/cloudboss/unobin/pkg/lsp/protocol"
+	ubruntime "github.com/cloudboss/unobin/pkg/runtime"
+	"github.com/cloudboss/unobin/pkg/typecheck"
+)
+
+// SignatureHelpForText describes the `@core` or Go library function
+// call the position is inside, with the argument at the position
+// active.
+func SignatureHelpForText(
+	path string,
+	text string,
+	pos protocol.Position,
+	projects *ProjectCache,
+) (*protocol.SignatureHelp, *protocol.ResponseError) {
+	offset, ok := LSPToOffset(text, pos)
+	if !ok {
+		return nil, protocol.InvalidParams("invalid document position")
+	}
+	call, ok := openCallAt(text, offset)
+	if !ok {
+		return nil, nil
+	}
+	alias, name, ok := strings.Cut(call.callee, ".")
+	if !ok || alias == "" || name == "" {
+		return nil, nil
+	}
+	if alias == lang.CoreNamespace {
+		sig, ok := ubruntime.CoreFunctionSigs()[name]
+		if !ok {
+			return nil, nil
+		}
+		fn, _ := ubruntime.CoreFunction(name)
+		return signatureHelp(call.callee, sig, fn.Description, call.arg), nil
+	}
+	if projects == nil {
+		projects = NewProjectCache("")
+	}
+	file, err := parseSignatureSource(path, text, offset)
+	if err != nil {
+		return nil, nil
+	}
+	body, _ := definitionBodyForOffset(file, offset)
+	resolved, err := resolveImportAlias(path, alias, definitionDeclsForBody(body), projects)
+	if err != nil || !resolved.found {
+		return nil, nil
+	}
+	schema, found, err := goSchemaForResolved(resolved)
+	if err != nil || !found || schema == nil {
+		return nil, nil
+	}
+	sig, ok := schema.Functions[name]
+	if !ok {
+		return nil, nil
+	}
+	return signatureHelp(call.callee, sig, schema.FunctionDescriptions[name], call.arg), nil
+}
+
+// signatureHelp describes sig as the one signature of a call to name,
+// with argument arg active. Every argument past the fixed parameters
+// of a variadic function is the variadic parameter.
+func signatureHelp(
+	name string,
+	sig typecheck.FuncSig,
+	description string,
+	arg int,
+) *protocol.SignatureHelp {
+	var label strings.Builder
+	label.WriteString(name + "(")
+	var params []protocol.ParameterInformation
+	addParam := func(text string) {
+		if len(params) > 0 {
+			label.WriteString(", ")
+		}
+		start := utf16Len(label.String())
+		label.WriteString(text)
+		params = append(params, protocol.ParameterInformation{
+			Label: [2]uint32{uint32(start), uint32(utf16Len(label.String()))},
+		})
+	}
+	for _, param := range sig.Params {
+		addParam(param.String())
+	}
+	if sig.Variadic != nil {
+		addParam("..." + sig.Variadic.String())
+	}
+	label.WriteString(") " + sig.Result.String())
+	info := protocol.SignatureInformation{Label: label.String(), Parameters: params}
+	if description != "" {
+		info.Documentation = &protocol.MarkupContent{
+			Kind: protocol.MarkupKindPlainText, Value: description,
+		}
+	}
+	active := arg
+	if sig.Variadic != nil && active > len(sig.Params) {
+		active = len(sig.Params)
+	}
+	return &protocol.SignatureHelp{
+		Signatures:      []protocol.SignatureInformation{info},
+		ActiveParameter: uint32(active),
+	}
+}
+
+// openCall is a call whose argument list is open at some offset: the
+// callee written before its `(` and the index of the argument the
+// offset is in.
+type openCall struct {
+	callee string
+	arg    int
+}
+
+// openCallAt finds the innermost call whose argument list is still
+// open at offset, looking past the objects, lists, and grouping
+// parentheses an argument may open. It scans the text rather than the parsed file, since
+// a call being typed rarely parses.
+func openCallAt(text string, offset int) (openCall, bool) {
+	type open struct {
+		at     int
+		commas int
+	}
+	var stack []open
+	for i := 0; i < offset && i < len(text); i++ {
+		switch text[i] {
+		case '#':
+			i = skipLineComment(text, i) - 1
+		case '\'':
+			i = skipSingleQuotedString(text, i) - 1
+		case '(', '[', '{':
+			stack = append(stack, open{at: i})
+		case ')', ']', '}':
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		case ',':
+			if len(stack) > 0 {
+				stack[len(stack)-1].commas++
+			}
+		}
+	}
+	for i := len(stack) - 1; i >= 0; i-- {
+		top := stack[i]
+		if text[top.at] != '(' {
+			continue
+		}
+		start := top.at
+		for start > 0 && isSymbolByte(text[start-1]) {
+			start--
+		}
+		if start < top.at {
+			return openCall{callee: text[start:top.at], arg: top.commas}, true
+		}
+	}
+	return openCall{}, false
+}
+
+// parseSignatureSource parses text, closing the call at offset when
+// the text does not parse as written.
+func parseSignatureSource(path string, text string, offset int) (*syntax.File, error) {
+	file, err := syntax.ParseSource(path, []byte(text))
+	if err == nil {
+		return file, nil
+	}
+	for _, insertion := range []string{")", "null)"} {
+		repaired := text[:offset] + insertion + text[offset:]
+		if file, err := syntax.ParseSource(path, []byte(repaired)); err == nil {
+			return file, nil
+		}
+	}
+	return nil, err
+}
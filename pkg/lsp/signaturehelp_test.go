@@ -0,0 +1,117 @@
+package lsp
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudboss/unobin/internal/ubtest"
+	"github.com/cloudboss/unobin/pkg/lsp/protocol"
+)
+
+func TestSignatureHelpCoreFunctionActiveParameter(t *testing.T) {
+	root, path, source := signatureHelpProject(t)
+
+	help, rpcErr := SignatureHelpForText(path, source,
+		positionInText(source, "input.zones, ','", "','"), NewProjectCache(root))
+	require.Nil(t, rpcErr)
+	require.NotNil(t, help)
+	require.Len(t, help.Signatures, 1)
+	signature := help.Signatures[0]
+	require.Equal(t, "@core.join(list(opaque), string) string", signature.Label)
+	require.NotNil(t, signature.Documentation)
+	require.Contains(t, signature.Documentation.Value, "Join a list's elements")
+	require.Equal(t, uint32(1), help.ActiveParameter)
+	require.Equal(t, "string", parameterLabel(signature, 1))
+}
+
+func TestSignatureHelpVariadicParameterStaysActive(t *testing.T) {
+	root, path, source := signatureHelpProject(t)
+
+	help, rpcErr := SignatureHelpForText(path, source,
+		positionInText(source, "{ c: 3 }", "c"), NewProjectCache(root))
+	require.Nil(t, rpcErr)
+	require.NotNil(t, help)
+	require.Equal(t, uint32(0), help.ActiveParameter)
+	require.Len(t, help.Signatures[0].Parameters, 1)
+	require.Equal(t, "...", parameterLabel(help.Signatures[0], 0)[:3])
+}
+
+func TestSignatureHelpInnermostCall(t *testing.T) {
+	root, path, source := signatureHelpProject(t)
+
+	help, rpcErr := SignatureHelpForText(path, source,
+		positionInText(source, "@core.length(input.zones)", "input"), NewProjectCache(root))
+	require.Nil(t, rpcErr)
+	require.NotNil(t, help)
+	require.Contains(t, help.Signatures[0].Label, "@core.length(")
+}
+
+func TestSignatureHelpGoFunction(t *testing.T) {
+	root, path, source, _ := goDefinitionProject(t)
+
+	help, rpcErr := SignatureHelpForText(path, source,
+		positionInText(source, "def.slug('v1')", "'v1'"), NewProjectCache(root))
+	require.Nil(t, rpcErr)
+	require.NotNil(t, help)
+	require.Equal(t, "def.slug(string) string", help.Signatures[0].Label)
+	require.NotNil(t, help.Signatures[0].Documentation)
+	require.Equal(t, "build slug", help.Signatures[0].Documentation.Value)
+}
+
+func TestSignatureHelpWhileTypingArgument(t *testing.T) {
+	root, path, source := signatureHelpProject(t)
+	offset := offsetInText(source, "input.zones, ','", "','")
+	source = source[:offset] + source[offset+len("',')"):]
+
+	help, rpcErr := SignatureHelpForText(path, source,
+		OffsetToLSP(source, offset), NewProjectCache(root))
+	require.Nil(t, rpcErr)
+	require.NotNil(t, help)
+	require.Equal(t, uint32(1), help.ActiveParameter)
+}
+
+func TestSignatureHelpOutsideCallReturnsNil(t *testing.T) {
+	root, path, source := signatureHelpProject(t)
+
+	help, rpcErr := SignatureHelpForText(path, source,
+		positionInText(source, "zones: { type", "type"), NewProjectCache(root))
+	require.Nil(t, rpcErr)
+	require.Nil(t, help)
+}
+
+func TestSessionSignatureHelpReturnsSignature(t *testing.T) {
+	_, path, source := signatureHelpProject(t)
+	session := NewSession("dev")
+	uri := PathToFileURI(path)
+	rpcErr := openDocument(t, session, uri, 1, source)
+	require.Nil(t, rpcErr)
+
+	result, rpcErr := sessionRequest(t, session, "textDocument/signatureHelp",
+		protocol.SignatureHelpParams{
+			TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+			Position:     positionInText(source, "input.zones, ','", "','"),
+		})
+	require.Nil(t, rpcErr)
+	help, ok := result.(*protocol.SignatureHelp)
+	require.True(t, ok)
+	require.Equal(t, uint32(1), help.ActiveParameter)
+}
+
+func signatureHelpProject(t *testing.T) (string, string, string) {
+	t.Helper()
+	root := writeUBProject(t, nil, nil)
+	source := ubtest.ReadFixture(t, "testdata/ub/signaturehelp/valid/factory.ub")
+	path := filepath.Join(root, "factory.ub")
+	require.NoError(t, os.WriteFile(path, []byte(source), 0o644))
+	return root, path, source
+}
+
+// parameterLabel returns the text of a signature's parameter i. The
+// labels tested are ASCII, so UTF-16 offsets are byte offsets.
+func parameterLabel(signature protocol.SignatureInformation, i int) string {
+	label := signature.Parameters[i].Label
+	return signature.Label[label[0]:label[1]]
+}
@@ -0,0 +1,509 @@
+package lsp
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/cloudboss/unobin/pkg/backends"
+	"github.com/cloudboss/unobin/pkg/deps"
+	"github.com/cloudboss/unobin/pkg/encrypters"
+	"github.com/cloudboss/unobin/pkg/lang/parse"
+	"github.com/cloudboss/unobin/pkg/lang/syntax"
+	"github.com/cloudboss/unobin/pkg/lsp/protocol"
+	"github.com/cloudboss/unobin/pkg/sdk/cfg"
+	"github.com/cloudboss/unobin/pkg/typecheck"
+)
+
+// stackObject is one object in a stack file whose keys a schema
+// declares: the factory.inputs block, or the body of the state backend
+// or encryption key source the file selects. Fields maps each key to
+// its type, wrapped in optional when the key may be left out, and docs
+// maps a field path to its description.
+type stackObject struct {
+	obj    *parse.ObjectLit
+	fields map[string]typecheck.Type
+	docs   map[string]string
+	// inputs holds the factory's input declarations, for the
+	// factory.inputs block only.
+	inputs map[string]syntax.InputDecl
+}
+
+// stackFactoryInputs finds the factory.ub next to or above the stack
+// file at path, up to the stack's project root, and returns the inputs
+// it declares the way `schema show` reads them: from the parsed source,
+// without compiling. The boolean is false when there is no factory to
+// find or its source does not parse.
+func stackFactoryInputs(path string) ([]syntax.InputDecl, bool) {
+	dir, err := filepath.Abs(filepath.Dir(path))
+	if err != nil {
+		return nil, false
+	}
+	limit := ""
+	if root, _, err := deps.FindProjectMarkerDir(dir); err == nil {
+		limit = filepath.Clean(root)
+	}
+	for {
+		candidate := filepath.Join(dir, "factory.ub")
+		if src, err := os.ReadFile(candidate); err == nil {
+			file, err := syntax.ParseSource(candidate, src)
+			if err != nil || file.Factory == nil {
+				return nil, false
+			}
+			return file.Factory.Body.Inputs, true
+		}
+		parent := filepath.Dir(dir)
+		if dir == limit || parent == dir {
+			return nil, false
+		}
+		dir = parent
+	}
+}
+
+// stackInputsObject describes a stack's factory.inputs block by the
+// inputs of the factory the stack supplies.
+func stackInputsObject(path string, stack *syntax.StackFile) (stackObject, bool) {
+	decls, ok := stackFactoryInputs(path)
+	if !ok {
+		return stackObject{}, false
+	}
+	out := stackObject{
+		fields: map[string]typecheck.Type{},
+		docs:   map[string]string{},
+		inputs: map[string]syntax.InputDecl{},
+	}
+	if stack.Factory != nil {
+		out.obj = stack.Factory.Inputs
+	}
+	for _, decl := range decls {
+		name := decl.Name.Name
+		out.fields[name] = typecheck.FromLang(decl.Type)
+		out.docs[name] = inputDescription(decl.Body)
+		out.inputs[name] = decl
+	}
+	return out, true
+}
+
+// stackResolver is one name a stack's state or encryption block may
+// select: a state backend or an encryption key source.
+type stackResolver struct {
+	description string
+	config      cfg.Registration
+}
+
+// stackResolverNoun names what role's selector picks, for messages.
+var stackResolverNoun = map[string]string{
+	"state":      "backend",
+	"encryption": "key-source",
+}
+
+// stackResolvers returns the registry role selects from, keyed by the
+// bare name an operator writes.
+func stackResolvers(role string) map[string]stackResolver {
+	out := map[string]stackResolver{}
+	switch role {
+	case "state":
+		for name, bt := range backends.Backends() {
+			out[name] = stackResolver{description: bt.Description, config: bt.Configuration}
+		}
+	case "encryption":
+		for name, et := range encrypters.Encrypters() {
+			out[name] = stackResolver{description: et.Description, config: et.Configuration}
+		}
+	}
+	return out
+}
+
+// stackResolverObject describes the body of the resolver selector
+// names, from the configuration its registry entry declares.
+func stackResolverObject(
+	role string,
+	selector syntax.Ident,
+	body *parse.ObjectLit,
+) (stackObject, bool) {
+	resolver, ok := stackResolvers(role)[selector.Name]
+	if !ok {
+		return stackObject{}, false
+	}
+	out := stackObject{obj: body, fields: map[string]typecheck.Type{}, docs: map[string]string{}}
+	for _, field := range configObjectFields(cfg.Describe(resolver.config), "", out.docs) {
+		typ := field.Type
+		if field.Optional {
+			typ = typecheck.TOptional(typ)
+		}
+		out.fields[field.Name] = typ
+	}
+	return out, true
+}
+
+// configObjectFields converts described configuration fields to object
+// fields, recording each field's description in docs under its path.
+func configObjectFields(
+	fields []cfg.Field,
+	prefix string,
+	docs map[string]string,
+) []typecheck.ObjectField {
+	out := make([]typecheck.ObjectField, 0, len(fields))
+	for _, field := range fields {
+		path := prefix + field.Name
+		if field.Description != "" {
+			docs[path] = field.Description
+		}
+		typ := configTypeLabel(field.Type)
+		if len(field.Fields) > 0 {
+			typ = typecheck.TObject(configObjectFields(field.Fields, path+".", docs))
+		}
+		out = append(out, typecheck.ObjectField{
+			Name:     field.Name,
+			Type:     typ,
+			Optional: field.Optional,
+		})
+	}
+	return out
+}
+
+// configTypeLabel reads back a type label cfg.Describe writes. An
+// object whose fields the label does not carry reads as an open object.
+func configTypeLabel(label string) typecheck.Type {
+	switch label {
+	case "string":
+		return typecheck.TString()
+	case "integer":
+		return typecheck.TInteger()
+	case "number":
+		return typecheck.TNumber()
+	case "boolean":
+		return typecheck.TBoolean()
+	case "opaque":
+		return typecheck.TOpaque()
+	case "object":
+		return typecheck.TOpenObject(nil)
+	}
+	if inner, ok := strings.CutPrefix(label, "list("); ok {
+		return typecheck.TList(configTypeLabel(strings.TrimSuffix(inner, ")")))
+	}
+	if inner, ok := strings.CutPrefix(label, "map("); ok {
+		return typecheck.TMap(configTypeLabel(strings.TrimSuffix(inner, ")")))
+	}
+	return typecheck.TUnknown()
+}
+
+// stackScope types the references a stack value may make: the locals
+// the stack file declares. A local that reaches itself reads as
+// unknown; validation reports the cycle.
+func stackScope(stack *syntax.StackFile) *typecheck.Scope {
+	values := map[string]parse.Expr{}
+	for _, local := range stack.Locals {
+		values[local.Name.Name] = local.Value
+	}
+	inferred := map[string]typecheck.Type{}
+	visiting := map[string]bool{}
+	scope := &typecheck.Scope{}
+	scope.LookupLocal = func(name string) (typecheck.Type, bool) {
+		if t, ok := inferred[name]; ok {
+			return t, true
+		}
+		value, ok := values[name]
+		if !ok || visiting[name] {
+			return typecheck.TUnknown(), ok
+		}
+		visiting[name] = true
+		t := typecheck.Infer(value, typecheck.TUnknown(), scope, parse.NewErrorList(0))
+		delete(visiting, name)
+		inferred[name] = t
+		return t, true
+	}
+	return scope
+}
+
+// diagnosticsForStackFile checks a stack file against what it
+// configures: its factory.inputs against the inputs of the factory
+// found next to or above it, and its state and encryption bodies
+// against the configuration of the backend and key source they select.
+func diagnosticsForStackFile(path string, text string, stack *syntax.StackFile) []protocol.Diagnostic {
+	if stack == nil {
+		return nil
+	}
+	errs := parse.NewErrorList(0)
+	scope := stackScope(stack)
+	if inputs, ok := stackInputsObject(path, stack); ok {
+		checkStackInputs(stack, inputs, scope, errs)
+	}
+	if stack.State != nil {
+		checkStackResolver("state", stack.State.Selector, stack.State.Body, scope, errs)
+	}
+	if stack.Encryption != nil {
+		checkStackResolver(
+			"encryption", stack.Encryption.Selector, stack.Encryption.Body, scope, errs,
+		)
+	}
+	if errs.Len() == 0 {
+		return nil
+	}
+	return DiagnosticsForError(text, errs)
+}
+
+// checkStackInputs reports each value in factory.inputs the factory
+// does not declare or whose type does not fit the declaration, and
+// each required input the block leaves out.
+func checkStackInputs(
+	stack *syntax.StackFile,
+	inputs stackObject,
+	scope *typecheck.Scope,
+	errs *parse.ErrorList,
+) {
+	at := stack.S.Start
+	provided := map[string]bool{}
+	if stack.Factory != nil {
+		at = stack.Factory.S.Start
+	}
+	if inputs.obj != nil {
+		at = inputs.obj.S.Start
+		for _, field := range inputs.obj.Fields {
+			name, ok := fieldKeyName(field.Key)
+			if !ok {
+				continue
+			}
+			provided[name] = true
+			typ, ok := inputs.fields[name]
+			if !ok {
+				errs.Addf(parse.ErrSchema, field.Key.S.Start,
+					"unknown input %q: not declared in the factory's `inputs:` block", name)
+				continue
+			}
+			typecheck.Check(field.Value, typ, scope, errs)
+		}
+	}
+	names := slices.Sorted(maps.Keys(inputs.inputs))
+	for _, name := range names {
+		decl := inputs.inputs[name]
+		if provided[name] || inputDefaultField(decl.Body) != nil {
+			continue
+		}
+		if _, ok := parse.ResolveNamed(decl.Type).(*parse.TypeOptional); ok {
+			continue
+		}
+		errs.Addf(parse.ErrSchema, at, "input %q: required but not provided", name)
+	}
+}
+
+// checkStackResolver reports a state backend or encryption key source
+// the registry does not hold, and a body that does not fit the
+// configuration the selected one declares.
+func checkStackResolver(
+	role string,
+	selector syntax.Ident,
+	body *parse.ObjectLit,
+	scope *typecheck.Scope,
+	errs *parse.ErrorList,
+) {
+	resolvers := stackResolvers(role)
+	resolver, ok := resolvers[selector.Name]
+	if !ok {
+		errs.Addf(parse.ErrSchema, selector.S.Start, "%s: no %s named %q; available: %s",
+			role, stackResolverNoun[role], selector.Name,
+			strings.Join(slices.Sorted(maps.Keys(resolvers)), ", "))
+		return
+	}
+	if body == nil {
+		body = &parse.ObjectLit{S: selector.S}
+	}
+	if resolver.config == nil {
+		if len(body.Fields) > 0 {
+			errs.Addf(parse.ErrSchema, body.S.Start,
+				"%s: %q accepts no configuration fields", role, selector.Name)
+		}
+		return
+	}
+	docs := map[string]string{}
+	fields := configObjectFields(cfg.Describe(resolver.config), "", docs)
+	typecheck.Check(body, typecheck.TObject(fields), scope, errs)
+}
+
+// inputDefaultField returns the default: field of an input
+// declaration, or nil when the input has none.
+func inputDefaultField(body *parse.ObjectLit) *parse.Field {
+	if body == nil {
+		return nil
+	}
+	for _, field := range body.Fields {
+		if name, ok := fieldKeyName(field.Key); ok && name == "default" {
+			return field
+		}
+	}
+	return nil
+}
+
+// stackObjects returns the objects of stack whose keys a schema
+// declares and whose span holds offset.
+func stackObjects(path string, stack *syntax.StackFile, offset int) []stackObject {
+	var out []stackObject
+	if stack.Factory != nil && stack.Factory.Inputs != nil &&
+		spanContainsOffset(stack.Factory.Inputs.S, offset) {
+		if inputs, ok := stackInputsObject(path, stack); ok {
+			out = append(out, inputs)
+		}
+	}
+	if stack.State != nil && stack.State.Body != nil &&
+		spanContainsOffset(stack.State.Body.S, offset) {
+		if state, ok := stackResolverObject("state", stack.State.Selector, stack.State.Body); ok {
+			out = append(out, state)
+		}
+	}
+	if enc := stack.Encryption; enc != nil && enc.Body != nil &&
+		spanContainsOffset(enc.Body.S, offset) {
+		if encryption, ok := stackResolverObject("encryption", enc.Selector, enc.Body); ok {
+			out = append(out, encryption)
+		}
+	}
+	return out
+}
+
+// stackCompletions completes inside a stack file's factory.inputs block
+// and its state and encryption bodies: the keys the schema declares,
+// and the values a key's type closes over.
+func stackCompletions(path string, text string, offset int) (protocol.CompletionList, bool) {
+	file, err := parseCompletionSource(path, text, offset)
+	if err != nil || file.Kind != syntax.FileStack || file.Stack == nil {
+		return protocol.CompletionList{}, false
+	}
+	for _, obj := range stackObjects(path, file.Stack, offset) {
+		if fieldPath, ok := objectKeyPathAtOffset(text, obj.obj, offset); ok {
+			return completionList(obj.fieldItems(fieldPath)), true
+		}
+		if valuePath, ok := objectValuePathAtOffset(obj.obj, offset); ok {
+			inner := objectAtPath(obj.obj, valuePath)
+			if inner == nil {
+				return completionList(obj.valueItems(valuePath)), true
+			}
+			if objectBodyKeyCompletionContext(text, inner, offset) {
+				fieldPrefix := strings.TrimSpace(currentObjectEntryPrefix(text, offset))
+				return completionList(obj.fieldItems(valuePath + "." + fieldPrefix)), true
+			}
+		}
+		if objectBodyKeyCompletionContext(text, obj.obj, offset) {
+			fieldPrefix := strings.TrimSpace(currentObjectEntryPrefix(text, offset))
+			return completionList(obj.fieldItems(fieldPrefix)), true
+		}
+	}
+	return protocol.CompletionList{}, false
+}
+
+// fieldItems lists the keys the schema declares beside fieldPath, each
+// with its type and description.
+func (o stackObject) fieldItems(fieldPath string) []protocol.CompletionItem {
+	items := fieldCompletionItems(o.fields, fieldPath, o.obj)
+	parent := fieldParentPath(fieldPath)
+	for i := range items {
+		path := items[i].Label
+		if parent != "" {
+			path = parent + "." + path
+		}
+		if typ, ok := typeForFieldPath(o.fields, path); ok && typ.IsKnown() {
+			items[i].Detail = typ.String()
+		}
+		if doc := o.docs[path]; doc != "" {
+			items[i].Documentation = &protocol.MarkupContent{
+				Kind: protocol.MarkupKindPlainText, Value: doc,
+			}
+		}
+	}
+	return items
+}
+
+// valueItems lists the values the type at fieldPath closes over: the
+// variant names of a union's tag field, and the keywords a boolean or
+// nullable slot takes.
+func (o stackObject) valueItems(fieldPath string) []protocol.CompletionItem {
+	if parent := fieldParentPath(fieldPath); parent != "" {
+		if typ, ok := typeForFieldPath(o.fields, parent); ok {
+			union := typ.Unwrap()
+			if union.Kind == typecheck.TaggedUnion && union.Tag == fieldLeafPrefix(fieldPath) {
+				items := make([]protocol.CompletionItem, 0, len(union.Variants))
+				for _, name := range union.VariantNames() {
+					items = append(items, protocol.CompletionItem{
+						Label: "'" + strings.ReplaceAll(name, "'", `\'`) + "'",
+						Kind:  protocol.CompletionItemKindEnumMember,
+					})
+				}
+				return items
+			}
+		}
+	}
+	typ, ok := typeForFieldPath(o.fields, fieldPath)
+	if !ok {
+		return []protocol.CompletionItem{}
+	}
+	return staticValueCompletionItems(typ)
+}
+
+// hoverText describes the key at fieldPath: a factory input as its
+// declaration reads, anything else by its type and description.
+func (o stackObject) hoverText(fieldPath string) (string, bool) {
+	if decl, ok := o.inputs[fieldPath]; ok {
+		return inputHoverText(decl), true
+	}
+	typ, ok := typeForFieldPath(o.fields, fieldPath)
+	if !ok {
+		return "", false
+	}
+	text := fieldHoverText(fieldPath, typ)
+	if doc := o.docs[fieldPath]; doc != "" {
+		text += "\n" + doc
+	}
+	return text, true
+}
+
+// stackHover describes the state backend or encryption key source a
+// stack file selects, or the key at offset in one of the objects a
+// schema declares.
+func stackHover(path string, text string, stack *syntax.StackFile, offset int) *protocol.Hover {
+	if stack.State != nil && identContainsOffset(text, stack.State.Selector, offset) {
+		return stackResolverHover("state", stack.State.Selector.Name)
+	}
+	if stack.Encryption != nil && identContainsOffset(text, stack.Encryption.Selector, offset) {
+		return stackResolverHover("encryption", stack.Encryption.Selector.Name)
+	}
+	for _, obj := range stackObjects(path, stack, offset) {
+		fieldPath, ok := objectKeyPathAtOffset(text, obj.obj, offset)
+		if !ok {
+			continue
+		}
+		if text, ok := obj.hoverText(fieldPath); ok {
+			return plainHover(text)
+		}
+	}
+	return nil
+}
+
+func stackResolverHover(role string, name string) *protocol.Hover {
+	resolver, ok := stackResolvers(role)[name]
+	if !ok {
+		return nil
+	}
+	text := fmt.Sprintf("%s %s %s", role, stackResolverNoun[role], name)
+	if resolver.description != "" {
+		text += "\n" + resolver.description
+	}
+	return plainHover(text)
+}
+
+// stackResolverCompletionItems lists the names role's selector may
+// take, each with its description.
+func stackResolverCompletionItems(role string) []protocol.CompletionItem {
+	resolvers := stackResolvers(role)
+	items := make([]protocol.CompletionItem, 0, len(resolvers))
+	for _, name := range slices.Sorted(maps.Keys(resolvers)) {
+		item := protocol.CompletionItem{Label: name, Kind: protocol.CompletionItemKindEnumMember}
+		if description := resolvers[name].description; description != "" {
+			item.Documentation = &protocol.MarkupContent{
+				Kind: protocol.MarkupKindPlainText, Value: description,
+			}
+		}
+		items = append(items, item)
+	}
+	return items
+}
@@ -0,0 +1,202 @@
+package lsp
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudboss/unobin/internal/ubtest"
+	"github.com/cloudboss/unobin/pkg/lsp/protocol"
+)
+
+func TestStackDiagnosticsFixtures(t *testing.T) {
+	ubtest.RequireInvalidFixtureGoldens(t, "testdata/ub/stack/stacks")
+	ubtest.Run(t, "testdata/ub/stack/stacks", func(name string, src []byte) (string, []string) {
+		path := stackProject(t, string(src))
+		diags := DiagnosticsForTextWithProjects(path, string(src), nil)
+		return "", diagnosticMessages(diags)
+	})
+}
+
+func TestStackDiagnosticsWithoutFactory(t *testing.T) {
+	root := writeUBProject(t, nil, nil)
+	source := ubtest.ReadInvalidFixture(t, "testdata/ub/stack/stacks", "unknown-input")
+	path := filepath.Join(root, "dev.ub")
+
+	require.Empty(t, DiagnosticsForTextWithProjects(path, source, nil))
+}
+
+func TestStackDiagnosticsRange(t *testing.T) {
+	source := ubtest.ReadInvalidFixture(t, "testdata/ub/stack/stacks", "unknown-input")
+	path := stackProject(t, source)
+
+	diags := DiagnosticsForTextWithProjects(path, source, nil)
+
+	require.Len(t, diags, 1)
+	require.Equal(t, positionInText(source, "mesage:", "mesage"), diags[0].Range.Start)
+}
+
+func TestStackCompletionInputNames(t *testing.T) {
+	source := ubtest.ReadValidFixture(t, "testdata/ub/stack/stacks", "dev")
+	path := stackProject(t, source)
+	source = strings.Replace(source, "verbose: true", "", 1)
+	offset := strings.Index(source, "message: local.greeting\n") + len("message: local.greeting\n      ")
+
+	list, rpcErr := CompleteForText(path, source, OffsetToLSP(source, offset), nil)
+	require.Nil(t, rpcErr)
+	requireOnlyCompletionLabels(t, list, "path", "verbose")
+	pathItem := requireCompletionItem(t, list, "path")
+	require.Equal(t, "string", pathItem.Detail)
+	verbose := requireCompletionItem(t, list, "verbose")
+	require.Equal(t, "optional(boolean)", verbose.Detail)
+}
+
+func TestStackCompletionInputDocumentation(t *testing.T) {
+	source := ubtest.ReadValidFixture(t, "testdata/ub/stack/stacks", "dev")
+	path := stackProject(t, source)
+	offset := strings.Index(source, "message: local.greeting")
+	source = strings.Replace(source, "message: local.greeting", "", 1)
+
+	list, rpcErr := CompleteForText(path, source, OffsetToLSP(source, offset), nil)
+	require.Nil(t, rpcErr)
+	message := requireCompletionItem(t, list, "message")
+	require.NotNil(t, message.Documentation)
+	require.Equal(t, "Text written to the file", message.Documentation.Value)
+}
+
+func TestStackCompletionInputValues(t *testing.T) {
+	source := ubtest.ReadValidFixture(t, "testdata/ub/stack/stacks", "dev")
+	path := stackProject(t, source)
+	source = strings.Replace(source, "verbose: true", "verbose: ", 1)
+	offset := strings.Index(source, "verbose: ") + len("verbose: ")
+
+	list, rpcErr := CompleteForText(path, source, OffsetToLSP(source, offset), nil)
+	require.Nil(t, rpcErr)
+	requireOnlyCompletionLabels(t, list, "false", "null", "true")
+}
+
+func TestStackCompletionUnionTag(t *testing.T) {
+	source := ubtest.ReadValidFixture(t, "testdata/ub/stack/stacks", "dev")
+	path := stackProject(t, source)
+	source = strings.Replace(source, "kind: 'git'", "kind: ", 1)
+	offset := strings.Index(source, "kind: ") + len("kind: ")
+
+	list, rpcErr := CompleteForText(path, source, OffsetToLSP(source, offset), nil)
+	require.Nil(t, rpcErr)
+	requireOnlyCompletionLabels(t, list, "'git'", "'local'")
+	require.Equal(t, protocol.CompletionItemKindEnumMember, list.Items[0].Kind)
+}
+
+func TestStackCompletionStateFields(t *testing.T) {
+	source := ubtest.ReadValidFixture(t, "testdata/ub/stack/stacks", "dev")
+	path := stackProject(t, source)
+	source = strings.Replace(source, "use-path-style: true", "", 1)
+	offset := strings.Index(source, "bucket: 'unobin-state'\n") + len("bucket: 'unobin-state'\n    ")
+
+	list, rpcErr := CompleteForText(path, source, OffsetToLSP(source, offset), nil)
+	require.Nil(t, rpcErr)
+	requireOnlyCompletionLabels(t, list, "kms-key-id", "prefix", "use-path-style")
+	for _, item := range list.Items {
+		require.NotEqual(t, "bucket", item.Label)
+	}
+}
+
+func TestStackCompletionNestedStateFields(t *testing.T) {
+	source := ubtest.ReadValidFixture(t, "testdata/ub/stack/stacks", "dev")
+	path := stackProject(t, source)
+	source = strings.Replace(source, "region: 'us-east-1'", "", 1)
+	offset := strings.Index(source, "aws: {\n") + len("aws: {\n      ")
+
+	list, rpcErr := CompleteForText(path, source, OffsetToLSP(source, offset), nil)
+	require.Nil(t, rpcErr)
+	requireCompletionLabels(t, list, "profile", "retry-mode", "assume-role")
+}
+
+func TestStackCompletionStateValues(t *testing.T) {
+	source := ubtest.ReadValidFixture(t, "testdata/ub/stack/stacks", "dev")
+	path := stackProject(t, source)
+	source = strings.Replace(source, "use-path-style: true", "use-path-style: ", 1)
+	offset := strings.Index(source, "use-path-style: ") + len("use-path-style: ")
+
+	list, rpcErr := CompleteForText(path, source, OffsetToLSP(source, offset), nil)
+	require.Nil(t, rpcErr)
+	requireOnlyCompletionLabels(t, list, "false", "null", "true")
+}
+
+func TestStackCompletionEncryptionFields(t *testing.T) {
+	source := ubtest.ReadValidFixture(t, "testdata/ub/stack/stacks", "dev")
+	path := stackProject(t, source)
+	source = strings.Replace(source, "env-var: 'UNOBIN_KEY'", "", 1)
+	offset := strings.Index(source, "env-key {\n") + len("env-key {\n    ")
+
+	list, rpcErr := CompleteForText(path, source, OffsetToLSP(source, offset), nil)
+	require.Nil(t, rpcErr)
+	requireOnlyCompletionLabels(t, list, "env-var")
+}
+
+func TestStackCompletionSelectorDocumentation(t *testing.T) {
+	root := writeUBProject(t, nil, nil)
+	path := filepath.Join(root, "stack.ub")
+	source := ubtest.ReadValidFixture(t, "testdata/ub/completion", "stack-state-selector")
+
+	list, rpcErr := CompleteForText(path, source,
+		positionInText(source, "state: local", "local"), NewProjectCache(root))
+	require.Nil(t, rpcErr)
+	local := requireCompletionItem(t, list, "local")
+	require.Equal(t, protocol.CompletionItemKindEnumMember, local.Kind)
+	require.NotNil(t, local.Documentation)
+	require.Equal(t, "Local filesystem state backend.", local.Documentation.Value)
+}
+
+func TestStackHoverInput(t *testing.T) {
+	source := ubtest.ReadValidFixture(t, "testdata/ub/stack/stacks", "dev")
+	path := stackProject(t, source)
+
+	hover, rpcErr := HoverForText(path, source,
+		positionInText(source, "message: local", "message"), nil)
+	require.Nil(t, rpcErr)
+	require.NotNil(t, hover)
+	require.Equal(t, "input message: string\nText written to the file", hover.Contents.Value)
+}
+
+func TestStackHoverStateField(t *testing.T) {
+	source := ubtest.ReadValidFixture(t, "testdata/ub/stack/stacks", "dev")
+	path := stackProject(t, source)
+
+	hover, rpcErr := HoverForText(path, source,
+		positionInText(source, "use-path-style", "use-path-style"), nil)
+	require.Nil(t, rpcErr)
+	require.NotNil(t, hover)
+	require.Equal(t, "use-path-style: optional(boolean)", hover.Contents.Value)
+}
+
+func TestStackHoverSelector(t *testing.T) {
+	source := ubtest.ReadValidFixture(t, "testdata/ub/stack/stacks", "dev")
+	path := stackProject(t, source)
+
+	hover, rpcErr := HoverForText(path, source,
+		positionInText(source, "encryption: env-key", "env-key"), nil)
+	require.Nil(t, rpcErr)
+	require.NotNil(t, hover)
+	require.Equal(t,
+		"encryption key-source env-key\nAES-256-GCM with a base64 key read from an env input.",
+		hover.Contents.Value)
+}
+
+// stackProject writes the stack fixtures' factory at a project root
+// and source as a stack file one directory below it, and returns the
+// stack file's path.
+func stackProject(t *testing.T, source string) string {
+	t.Helper()
+	root := writeUBProject(t, nil, nil)
+	factory := ubtest.ReadValidFixture(t, "testdata/ub/stack/factory", "factory")
+	require.NoError(t, os.WriteFile(filepath.Join(root, "factory.ub"), []byte(factory), 0o644))
+	dir := filepath.Join(root, "stacks")
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	path := filepath.Join(dir, "dev.ub")
+	require.NoError(t, os.WriteFile(path, []byte(source), 0o644))
+	return path
+}
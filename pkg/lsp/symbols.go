@@ -48,7 +48,7 @@ func factorySymbols(text string, body syntax.FactoryBody) []protocol.DocumentSym
 		len(body.Assets)+len(body.Inputs)+len(body.Locals)+len(body.Constraints)+
 			len(body.Imports)+
 			len(body.LibraryConfigs)+len(body.StateMoves)+len(body.Resources)+
-			len(body.Data)+len(body.Actions)+len(body.Outputs))
+			len(body.Data)+len(body.Actions)+len(body.Outputs)+len(body.Checks))
 	for _, item := range body.Assets {
 		symbols = append(symbols, symbolFromSpan(text, "asset."+item.Name.Name,
 			protocol.SymbolKindVariable, item.Name.S))
@@ -90,6 +90,10 @@ func factorySymbols(text string, body syntax.FactoryBody) []protocol.DocumentSym
 		symbols = append(symbols, symbolFromSpan(text, "output."+output.Name.Name,
 			protocol.SymbolKindVariable, output.Name.S))
 	}
+	for _, check := range body.Checks {
+		symbols = append(symbols, symbolFromSpan(text, "check",
+			protocol.SymbolKindFunction, check.S))
+	}
 	return symbols
 }
 
@@ -142,6 +146,10 @@ func projectSymbols(text string, project *syntax.ProjectFile) []protocol.Documen
 		symbols = append(symbols, symbolFromSpan(text, "replace."+replace.ID.Value,
 			protocol.SymbolKindModule, replace.ID.S))
 	}
+	for _, rule := range project.Lint {
+		symbols = append(symbols, symbolFromSpan(text, "lint."+rule.Code.Name,
+			protocol.SymbolKindField, rule.Code.S))
+	}
 	return symbols
 }
 
@@ -0,0 +1,72 @@
+package lsp
+
+import (
+	"fmt"
+
+	"github.com/cloudboss/unobin/pkg/lang"
+	"github.com/cloudboss/unobin/pkg/lang/parse"
+	"github.com/cloudboss/unobin/pkg/lang/syntax"
+	"github.com/cloudboss/unobin/pkg/lsp/protocol"
+)
+
+// typeNameDefinition resolves a bare type name at offset to the
+// `types:` declaration in the same file that it names.
+func typeNameDefinition(
+	path string,
+	text string,
+	offset int,
+	file *syntax.File,
+	body *syntax.FactoryBody,
+) ([]protocol.Location, bool) {
+	decl, ok := typeDeclAtOffset(file, body, offset)
+	if !ok {
+		return nil, false
+	}
+	return locationsForTargets(definitionTarget{path: path, text: text, span: decl.Name.S}), true
+}
+
+// typeNameHover describes the declared type a bare type name at
+// offset names.
+func typeNameHover(file *syntax.File, body *syntax.FactoryBody, offset int) (*protocol.Hover, bool) {
+	decl, ok := typeDeclAtOffset(file, body, offset)
+	if !ok {
+		return nil, false
+	}
+	formatted, err := lang.FormatTypeExpr(decl.Type)
+	if err != nil {
+		return nil, false
+	}
+	return plainHover(fmt.Sprintf("type %s: %s", decl.Name.Name, formatted)), true
+}
+
+// typeDeclAtOffset finds the bare type name written at offset in the
+// types of body or, in a library file, the library's own types and
+// function signatures, and returns the declaration it names. A body's
+// declaration hides the library's one of the same name.
+func typeDeclAtOffset(file *syntax.File, body *syntax.FactoryBody, offset int) (syntax.TypeDecl, bool) {
+	var scope *syntax.TypeScope
+	var types []parse.TypeExpr
+	if file != nil && file.Library != nil {
+		scope = syntax.NewTypeScope(nil, file.Library.Types)
+		for _, decl := range file.Library.Types {
+			types = append(types, decl.Type)
+		}
+		types = append(types, syntax.FunctionTypes(file.Library.Functions)...)
+	}
+	if body != nil {
+		scope = syntax.NewTypeScope(scope, body.Types)
+		types = append(types, syntax.BodyTypes(*body)...)
+	}
+	if scope == nil {
+		return syntax.TypeDecl{}, false
+	}
+	for _, t := range types {
+		for _, ref := range syntax.NamedTypes(t) {
+			if ref.Library != "" || !spanContainsOffset(ref.S, offset) {
+				continue
+			}
+			return scope.Lookup(ref.Name)
+		}
+	}
+	return syntax.TypeDecl{}, false
+}
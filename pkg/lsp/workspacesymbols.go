@@ -0,0 +1,81 @@
+package lsp
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/cloudboss/unobin/pkg/lang/syntax"
+	"github.com/cloudboss/unobin/pkg/lsp/protocol"
+)
+
+// WorkspaceSymbolsForQuery lists the composites, library functions,
+// and factory nodes declared in the .ub files under every root the
+// project cache knows, keeping those whose name contains query,
+// ignoring case. Each symbol's container is its file's path from its
+// project's root.
+func WorkspaceSymbolsForQuery(
+	query string,
+	projects *ProjectCache,
+	documents *DocumentStore,
+) ([]protocol.SymbolInformation, *protocol.ResponseError) {
+	refs := newRefIndex(projects, documents)
+	paths, err := refs.workspaceFiles()
+	if err != nil {
+		return nil, protocol.InternalError(err)
+	}
+	query = strings.ToLower(query)
+	symbols := []protocol.SymbolInformation{}
+	for _, path := range paths {
+		src, ok := refs.load(path)
+		if !ok {
+			continue
+		}
+		container := workspaceSymbolContainer(refs.projects, path)
+		add := func(name string, kind protocol.SymbolKind, ident syntax.Ident) {
+			if !strings.Contains(strings.ToLower(name), query) {
+				return
+			}
+			symbols = append(symbols, protocol.SymbolInformation{
+				Name:          name,
+				Kind:          kind,
+				Location:      identOccurrence(src, ident, true).location(),
+				ContainerName: container,
+			})
+		}
+		if src.file.Factory != nil {
+			for _, node := range allNodes(src.file.Factory.Body) {
+				add(string(node.Kind)+"."+node.Name.Name, protocol.SymbolKindClass, node.Name)
+			}
+		}
+		if library := src.file.Library; library != nil {
+			for _, export := range library.Exports {
+				add(string(export.Kind)+"."+export.Name.Name, protocol.SymbolKindClass, export.Name)
+			}
+			for _, fn := range library.Functions {
+				add(fn.Name.Name, protocol.SymbolKindFunction, fn.Name)
+			}
+		}
+	}
+	slices.SortStableFunc(symbols, func(a, b protocol.SymbolInformation) int {
+		if c := strings.Compare(a.Name, b.Name); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Location.URI, b.Location.URI)
+	})
+	return symbols, nil
+}
+
+// workspaceSymbolContainer names the file a symbol is in by its path
+// from its project's root, or from the workspace root of a loose file.
+func workspaceSymbolContainer(projects *ProjectCache, path string) string {
+	project, err := projects.ProjectForPath(path)
+	if err != nil {
+		return filepath.Base(path)
+	}
+	rel, err := filepath.Rel(project.Root, path)
+	if err != nil {
+		return filepath.Base(path)
+	}
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,71 @@
+package lsp
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudboss/unobin/pkg/lsp/protocol"
+)
+
+func TestWorkspaceSymbolsListCompositesFunctionsAndNodes(t *testing.T) {
+	root, _, _ := callHierarchyProject(t)
+
+	symbols, rpcErr := WorkspaceSymbolsForQuery("", NewProjectCache(root), nil)
+	require.Nil(t, rpcErr)
+	require.Equal(t, []string{
+		"label names/library.ub",
+		"prefix names/library.ub",
+		"resource.backup factory.ub",
+		"resource.server factory.ub",
+		"resource.web bundle/library.ub",
+	}, workspaceSymbolNames(symbols))
+}
+
+func TestWorkspaceSymbolsMatchQueryIgnoringCase(t *testing.T) {
+	root, _, _ := callHierarchyProject(t)
+
+	symbols, rpcErr := WorkspaceSymbolsForQuery("WEB", NewProjectCache(root), nil)
+	require.Nil(t, rpcErr)
+	require.Len(t, symbols, 1)
+	symbol := symbols[0]
+	require.Equal(t, "resource.web", symbol.Name)
+	require.Equal(t, protocol.SymbolKindClass, symbol.Kind)
+	libraryPath := filepath.Join(root, "bundle", "library.ub")
+	require.Equal(t, PathToFileURI(libraryPath), symbol.Location.URI)
+	require.Equal(t, rangeOfText(readTestFile(t, libraryPath), "web: resource", "web"),
+		symbol.Location.Range)
+}
+
+func TestWorkspaceSymbolsSpanWorkspaceRoots(t *testing.T) {
+	first, _, _ := callHierarchyProject(t)
+	second, _, _, _ := referencesProject(t)
+	projects := NewProjectCache("")
+	projects.SetWorkspaceRoots([]string{first, second})
+
+	symbols, rpcErr := WorkspaceSymbolsForQuery("resource.web", projects, nil)
+	require.Nil(t, rpcErr)
+	require.Len(t, symbols, 2)
+}
+
+func TestSessionWorkspaceSymbolReturnsSymbols(t *testing.T) {
+	root, _, _ := callHierarchyProject(t)
+	session := NewSession("dev")
+	session.projects.SetWorkspaceRoots([]string{root})
+
+	result, rpcErr := sessionRequest(t, session, "workspace/symbol",
+		protocol.WorkspaceSymbolParams{Query: "label"})
+	require.Nil(t, rpcErr)
+	symbols, ok := result.([]protocol.SymbolInformation)
+	require.True(t, ok)
+	require.Equal(t, []string{"label names/library.ub"}, workspaceSymbolNames(symbols))
+}
+
+func workspaceSymbolNames(symbols []protocol.SymbolInformation) []string {
+	names := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		names = append(names, symbol.Name+" "+symbol.ContainerName)
+	}
+	return names
+}
@@ -0,0 +1,123 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ErrNotFound is returned when a proxy does not have what was asked for.
+var ErrNotFound = errors.New("not found")
+
+// Client fetches from one proxy.
+type Client struct {
+	// BaseURL is the proxy's URL, without a trailing slash.
+	BaseURL string
+	// HTTP is the client requests go through; nil uses http.DefaultClient.
+	HTTP *http.Client
+}
+
+// List returns the version tags the proxy has for a repository.
+func (c *Client) List(ctx context.Context, repo string) ([]string, error) {
+	body, err := c.get(ctx, repo, "list")
+	if err != nil {
+		return nil, err
+	}
+	var tags []string
+	for line := range strings.SplitSeq(string(body), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			tags = append(tags, line)
+		}
+	}
+	return tags, nil
+}
+
+// Info returns the commit ref names in a repository.
+func (c *Client) Info(ctx context.Context, repo, ref string) (Info, error) {
+	body, err := c.get(ctx, repo, ref+".info")
+	if err != nil {
+		return Info{}, err
+	}
+	var info Info
+	if err := json.Unmarshal(body, &info); err != nil {
+		return Info{}, fmt.Errorf("%s: %s@%s: bad info: %w", c.BaseURL, repo, ref, err)
+	}
+	if info.Commit == "" || info.Zip == "" {
+		return Info{}, fmt.Errorf("%s: %s@%s: info has no commit or zip hash", c.BaseURL, repo, ref)
+	}
+	return info, nil
+}
+
+// Download fetches the repository tree at info's commit and extracts it
+// into dest, which must not exist. A zip whose hash differs from the one
+// info records is refused.
+func (c *Client) Download(ctx context.Context, repo string, info Info, dest string) error {
+	body, err := c.get(ctx, repo, info.Commit+".zip")
+	if err != nil {
+		return err
+	}
+	if hash := ZipHash(body); hash != info.Zip {
+		return fmt.Errorf("%s: %s@%s: zip hash mismatch (info %s, got %s)",
+			c.BaseURL, repo, info.Commit, info.Zip, hash)
+	}
+	if err := extractZip(body, dest); err != nil {
+		return fmt.Errorf("%s: %s@%s: %w", c.BaseURL, repo, info.Commit, err)
+	}
+	return nil
+}
+
+// ProjectFile returns the project.ub of the project at subdir of a
+// repository, at commit. An empty subdir is the repository root.
+func (c *Client) ProjectFile(ctx context.Context, repo, subdir, commit string) ([]byte, error) {
+	name := commit + "/project.ub"
+	if subdir != "" {
+		name = commit + "/" + subdir + "/project.ub"
+	}
+	return c.get(ctx, repo, name)
+}
+
+func (c *Client) get(ctx context.Context, repo, name string) ([]byte, error) {
+	target := c.BaseURL + "/" + escapePath(repo) + "/@v/" + escapePath(name)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	client := c.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("GET %s: %w", target, err)
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return body, nil
+	case http.StatusNotFound, http.StatusGone:
+		return nil, fmt.Errorf("GET %s: %w", target, ErrNotFound)
+	}
+	message := string(bytes.TrimSpace(body))
+	if message == "" {
+		message = resp.Status
+	}
+	return nil, fmt.Errorf("GET %s: %s", target, message)
+}
+
+func escapePath(p string) string {
+	parts := strings.Split(p, "/")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+	return strings.Join(parts, "/")
+}
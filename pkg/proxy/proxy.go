@@ -0,0 +1,220 @@
+// Package proxy implements the HTTP protocol unobin uses to fetch UB
+// project sources from a proxy instead of the git host, in the manner
+// of GOPROXY. A proxy serves each repository under its URL without a
+// scheme, such as `github.com/owner/repo`:
+//
+//	GET <repo>/@v/list                        the version tags, one per line
+//	GET <repo>/@v/<ref>.info                  the commit a tag, branch, or commit names, as Info
+//	GET <repo>/@v/<commit>.zip                the repository tree at the commit
+//	GET <repo>/@v/<commit>/[<subdir>/]project.ub  a project's project.ub at the commit
+//
+// Info carries the SHA-256 of the zip, and a client refuses a zip that
+// does not match it. A missing repository, ref, or file is a 404 or
+// 410, which sends the client on to the next source in UNOBIN_PROXY.
+package proxy
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Env is the environment variable listing where UB project sources are
+// fetched from.
+const Env = "UNOBIN_PROXY"
+
+const (
+	// Direct names the git host itself as a source.
+	Direct = "direct"
+	// Off refuses to fetch from anywhere.
+	Off = "off"
+)
+
+// Info describes the commit a ref names in a repository.
+type Info struct {
+	Ref    string `json:"ref"`
+	Commit string `json:"commit"`
+	// Zip is the algorithm-prefixed hash of the commit's zip.
+	Zip string `json:"zip"`
+}
+
+// ParseList parses an UNOBIN_PROXY value: a comma-separated list of
+// proxy URLs, `direct`, and `off`, tried in order. An empty value is
+// `direct`.
+func ParseList(value string) ([]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return []string{Direct}, nil
+	}
+	var sources []string
+	for field := range strings.SplitSeq(value, ",") {
+		field = strings.TrimSpace(field)
+		switch field {
+		case "":
+			continue
+		case Direct, Off:
+			sources = append(sources, field)
+			continue
+		}
+		u, err := url.Parse(field)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return nil, fmt.Errorf("%s: %q is not an http or https URL, %s, or %s",
+				Env, field, Direct, Off)
+		}
+		sources = append(sources, strings.TrimSuffix(field, "/"))
+	}
+	return sources, nil
+}
+
+// FromEnv returns the sources UNOBIN_PROXY lists.
+func FromEnv() ([]string, error) {
+	return ParseList(os.Getenv(Env))
+}
+
+// Proxied reports whether a repository URL can be fetched through a
+// proxy. Only host-and-path URLs, with or without an https scheme, can;
+// local paths and other schemes are always fetched directly.
+func Proxied(repoURL string) bool {
+	rest := repoURL
+	if scheme, after, ok := strings.Cut(repoURL, "://"); ok {
+		if scheme != "https" {
+			return false
+		}
+		rest = after
+	}
+	if strings.HasPrefix(rest, "/") || strings.HasPrefix(rest, ".") {
+		return false
+	}
+	if _, after, ok := strings.Cut(rest, "@"); ok && strings.Contains(after, ":") {
+		return false
+	}
+	host, _, ok := strings.Cut(rest, "/")
+	return ok && strings.Contains(host, ".")
+}
+
+// RepoPath returns the path a proxy serves a repository URL under.
+func RepoPath(repoURL string) string {
+	p := repoURL
+	if _, after, ok := strings.Cut(p, "://"); ok {
+		p = after
+	}
+	return strings.Trim(p, "/")
+}
+
+// zipEpoch is the modification time of every zip entry, so the same
+// tree always yields the same zip.
+var zipEpoch = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// WriteZip writes the tree at dir as a zip, leaving out hidden files and
+// directories such as .git. Entries are sorted with fixed times, so the
+// same tree always yields the same bytes.
+func WriteZip(w io.Writer, dir string) error {
+	fsys := os.DirFS(dir)
+	var paths []string
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p != "." && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.Type()&fs.ModeSymlink != 0 {
+			return fmt.Errorf("%s: symlink is not supported", p)
+		}
+		if d.Type().IsRegular() {
+			paths = append(paths, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	slices.Sort(paths)
+	zw := zip.NewWriter(w)
+	for _, p := range paths {
+		info, err := fs.Stat(fsys, p)
+		if err != nil {
+			return err
+		}
+		header := &zip.FileHeader{Name: p, Method: zip.Deflate, Modified: zipEpoch}
+		header.SetMode(zipMode(info.Mode()))
+		out, err := zw.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		body, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+		if _, err := out.Write(body); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func zipMode(mode fs.FileMode) fs.FileMode {
+	if mode.Perm()&0o111 != 0 {
+		return 0o755
+	}
+	return 0o644
+}
+
+// ZipHash returns the algorithm-prefixed hash of a zip.
+func ZipHash(body []byte) string {
+	sum := sha256.Sum256(body)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// extractZip writes the files of a zip into dest, which must not exist.
+func extractZip(body []byte, dest string) error {
+	zr, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dest, 0o755); err != nil {
+		return err
+	}
+	for _, f := range zr.File {
+		if !filepath.IsLocal(f.Name) || strings.HasSuffix(f.Name, "/") || !f.Mode().IsRegular() {
+			return fmt.Errorf("zip entry %q is not a file in the tree", f.Name)
+		}
+		target := filepath.Join(dest, filepath.FromSlash(f.Name))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return err
+		}
+		if err := extractFile(f, target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func extractFile(f *zip.File, target string) error {
+	in, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY, zipMode(f.Mode()))
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,178 @@
+package proxy
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseList(t *testing.T) {
+	cases := []struct {
+		value string
+		want  []string
+		err   string
+	}{
+		{value: "", want: []string{Direct}},
+		{value: "direct", want: []string{Direct}},
+		{
+			value: "https://proxy.example.com/, direct",
+			want:  []string{"https://proxy.example.com", Direct},
+		},
+		{value: "http://localhost:7070,off", want: []string{"http://localhost:7070", Off}},
+		{
+			value: "proxy.example.com",
+			err:   `UNOBIN_PROXY: "proxy.example.com" is not an http or https URL, direct, or off`,
+		},
+	}
+	for _, tt := range cases {
+		got, err := ParseList(tt.value)
+		if tt.err != "" {
+			require.EqualError(t, err, tt.err)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, tt.want, got, tt.value)
+	}
+}
+
+func TestProxied(t *testing.T) {
+	for url, want := range map[string]bool{
+		"github.com/x/lib":            true,
+		"https://github.com/x/lib":    true,
+		"ssh://git@github.com/x/lib":  false,
+		"git@github.com:x/lib":        false,
+		"/srv/git/lib":                false,
+		"./lib":                       false,
+		"file:///srv/git/lib":         false,
+		"localhost/lib":               false,
+		"example.com/x/lib//sub/path": true,
+	} {
+		require.Equal(t, want, Proxied(url), url)
+	}
+}
+
+// writeCache writes a repository cached at commit c1 with the version
+// tags v1.0.0 and lib/v1.0.0 and the branch main recorded.
+func writeCache(t *testing.T) string {
+	t.Helper()
+	imports := t.TempDir()
+	repoDir := filepath.Join(imports, "example.com", "x", "repo")
+	for name, body := range map[string]string{
+		"c1/project.ub":         "# root project\n",
+		"c1/lib/library.ub":     "# library\n",
+		"c1/app/project.ub":     "# app project\n",
+		"c1/tool.sh":            "#!/bin/sh\n",
+		"c1/.git/HEAD":          "ref: refs/heads/main\n",
+		"c1/nested/.hidden.txt": "hidden\n",
+	} {
+		path := filepath.Join(repoDir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(body), 0o644))
+	}
+	require.NoError(t, os.Chmod(filepath.Join(repoDir, "c1", "tool.sh"), 0o755))
+	for _, ref := range []string{"v1.0.0", "lib/v1.0.0", "main"} {
+		require.NoError(t, RecordRef(repoDir, ref, "c1"))
+	}
+	return imports
+}
+
+func TestServeCache(t *testing.T) {
+	srv := httptest.NewServer(NewHandler(writeCache(t)))
+	defer srv.Close()
+	client := &Client{BaseURL: srv.URL}
+	ctx := context.Background()
+	repo := RepoPath("https://example.com/x/repo")
+
+	tags, err := client.List(ctx, repo)
+	require.NoError(t, err)
+	require.Equal(t, []string{"lib/v1.0.0", "v1.0.0"}, tags)
+
+	info, err := client.Info(ctx, repo, "lib/v1.0.0")
+	require.NoError(t, err)
+	require.Equal(t, "lib/v1.0.0", info.Ref)
+	require.Equal(t, "c1", info.Commit)
+	byCommit, err := client.Info(ctx, repo, "c1")
+	require.NoError(t, err)
+	require.Equal(t, info.Zip, byCommit.Zip)
+
+	dest := filepath.Join(t.TempDir(), "c1")
+	require.NoError(t, client.Download(ctx, repo, info, dest))
+	body, err := os.ReadFile(filepath.Join(dest, "lib", "library.ub"))
+	require.NoError(t, err)
+	require.Equal(t, "# library\n", string(body))
+	tool, err := os.Stat(filepath.Join(dest, "tool.sh"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o755), tool.Mode().Perm())
+	require.NoDirExists(t, filepath.Join(dest, ".git"))
+	require.NoFileExists(t, filepath.Join(dest, "nested", ".hidden.txt"))
+
+	for _, err := range []error{
+		func() error { _, err := client.List(ctx, "example.com/x/other"); return err }(),
+		func() error { _, err := client.Info(ctx, repo, "v2.0.0"); return err }(),
+		func() error { _, err := client.Info(ctx, repo, "../repo/c1"); return err }(),
+	} {
+		require.ErrorIs(t, err, ErrNotFound)
+	}
+
+	info.Zip = "sha256:0000"
+	err = client.Download(ctx, repo, info, filepath.Join(t.TempDir(), "c1"))
+	require.ErrorContains(t, err, "zip hash mismatch (info sha256:0000, got sha256:")
+}
+
+func TestServeProjectFile(t *testing.T) {
+	srv := httptest.NewServer(NewHandler(writeCache(t)))
+	defer srv.Close()
+	client := &Client{BaseURL: srv.URL}
+	ctx := context.Background()
+	repo := RepoPath("https://example.com/x/repo")
+
+	cases := []struct {
+		subdir string
+		commit string
+		want   string
+	}{
+		{subdir: "", commit: "c1", want: "# root project\n"},
+		{subdir: "app", commit: "c1", want: "# app project\n"},
+		{subdir: "lib", commit: "c1"},
+		{subdir: ".git", commit: "c1"},
+		{subdir: "../repo/c1", commit: "c1"},
+		{subdir: "", commit: "c2"},
+	}
+	for _, tt := range cases {
+		got, err := client.ProjectFile(ctx, repo, tt.subdir, tt.commit)
+		if tt.want == "" {
+			require.ErrorIs(t, err, ErrNotFound, tt.subdir)
+			continue
+		}
+		require.NoError(t, err, tt.subdir)
+		require.Equal(t, tt.want, string(got), tt.subdir)
+	}
+}
+
+func TestWriteZipIsDeterministic(t *testing.T) {
+	dir := filepath.Join(writeCache(t), "example.com", "x", "repo", "c1")
+	var first, second bytes.Buffer
+	require.NoError(t, WriteZip(&first, dir))
+	later := time.Now().Add(time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "project.ub"), later, later))
+	require.NoError(t, WriteZip(&second, dir))
+	require.Equal(t, ZipHash(first.Bytes()), ZipHash(second.Bytes()))
+}
+
+func TestExtractZipRejectsEscapingEntries(t *testing.T) {
+	var b bytes.Buffer
+	zw := zip.NewWriter(&b)
+	_, err := zw.Create("../escape.txt")
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	err = extractZip(b.Bytes(), filepath.Join(t.TempDir(), "dest"))
+	require.EqualError(t, err, `zip entry "../escape.txt" is not a file in the tree`)
+}
@@ -0,0 +1,192 @@
+package proxy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	pathpkg "path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// RefsDir is the directory beside a repository's cached commits that
+// records which commit each fetched version tag named, one file per tag.
+const RefsDir = ".refs"
+
+// RecordRef records that ref named commit in the cached repository at
+// repoDir, so a proxy serving the cache can answer for ref.
+func RecordRef(repoDir, ref, commit string) error {
+	if !filepath.IsLocal(ref) {
+		return fmt.Errorf("ref %q cannot be recorded", ref)
+	}
+	path := filepath.Join(repoDir, RefsDir, filepath.FromSlash(ref))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(commit+"\n"), 0o644)
+}
+
+// NewHandler returns a proxy serving the repositories cached under
+// importsDir, laid out as the remote resolver caches them: each commit's
+// tree at `<repo>/<commit>` and its version tags under `<repo>/.refs`.
+// It serves only what is cached and never fetches.
+func NewHandler(importsDir string) http.Handler {
+	return &server{dir: importsDir}
+}
+
+type server struct {
+	dir string
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	repo, name, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/@v/")
+	if !ok || !filepath.IsLocal(repo) {
+		http.NotFound(w, r)
+		return
+	}
+	repoDir := filepath.Join(s.dir, filepath.FromSlash(repo))
+	var (
+		body        []byte
+		contentType string
+		err         error
+	)
+	switch {
+	case name == "list":
+		body, err = s.list(repoDir)
+		contentType = "text/plain; charset=utf-8"
+	case strings.HasSuffix(name, ".info"):
+		body, err = s.info(repoDir, strings.TrimSuffix(name, ".info"))
+		contentType = "application/json"
+	case strings.HasSuffix(name, ".zip"):
+		body, err = s.zip(repoDir, strings.TrimSuffix(name, ".zip"))
+		contentType = "application/zip"
+	case pathpkg.Base(name) == "project.ub":
+		body, err = s.projectFile(repoDir, name)
+		contentType = "text/plain; charset=utf-8"
+	default:
+		err = fs.ErrNotExist
+	}
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		http.Error(w, fmt.Sprintf("%s: %s is not cached", repo, name), http.StatusNotFound)
+		return
+	case err != nil:
+		http.Error(w, fmt.Sprintf("%s: %s: %v", repo, name, err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	_, _ = w.Write(body)
+}
+
+// list returns the recorded version tags, one per line.
+func (s *server) list(repoDir string) ([]byte, error) {
+	refsDir := filepath.Join(repoDir, RefsDir)
+	if _, err := os.Stat(refsDir); err != nil {
+		return nil, err
+	}
+	var tags []string
+	err := fs.WalkDir(os.DirFS(refsDir), ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() && semver.IsValid(pathpkg.Base(p)) {
+			tags = append(tags, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(tags)
+	var b bytes.Buffer
+	for _, tag := range tags {
+		b.WriteString(tag + "\n")
+	}
+	return b.Bytes(), nil
+}
+
+func (s *server) info(repoDir, ref string) ([]byte, error) {
+	commit, err := s.commit(repoDir, ref)
+	if err != nil {
+		return nil, err
+	}
+	body, err := s.zip(repoDir, commit)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(Info{Ref: ref, Commit: commit, Zip: ZipHash(body)})
+}
+
+// commit returns the commit ref names: a recorded tag, or a cached
+// commit itself.
+func (s *server) commit(repoDir, ref string) (string, error) {
+	if !filepath.IsLocal(ref) {
+		return "", fs.ErrNotExist
+	}
+	recorded, err := os.ReadFile(filepath.Join(repoDir, RefsDir, filepath.FromSlash(ref)))
+	if err == nil {
+		return strings.TrimSpace(string(recorded)), nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return "", err
+	}
+	if _, err := s.commitDir(repoDir, ref); err != nil {
+		return "", err
+	}
+	return ref, nil
+}
+
+func (s *server) commitDir(repoDir, commit string) (string, error) {
+	if commit == "" || strings.ContainsAny(commit, `/\`) || strings.HasPrefix(commit, ".") {
+		return "", fs.ErrNotExist
+	}
+	dir := filepath.Join(repoDir, commit)
+	info, err := os.Stat(dir)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return "", fs.ErrNotExist
+	}
+	return dir, nil
+}
+
+func (s *server) zip(repoDir, commit string) ([]byte, error) {
+	dir, err := s.commitDir(repoDir, commit)
+	if err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	if err := WriteZip(&b, dir); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func (s *server) projectFile(repoDir, name string) ([]byte, error) {
+	commit, rest, ok := strings.Cut(name, "/")
+	if !ok || !filepath.IsLocal(rest) {
+		return nil, fs.ErrNotExist
+	}
+	for part := range strings.SplitSeq(rest, "/") {
+		if strings.HasPrefix(part, ".") {
+			return nil, fs.ErrNotExist
+		}
+	}
+	dir, err := s.commitDir(repoDir, commit)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(filepath.Join(dir, filepath.FromSlash(rest)))
+}
@@ -0,0 +1,211 @@
+package resolve
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/cloudboss/unobin/pkg/lang"
+	"github.com/cloudboss/unobin/pkg/lang/syntax"
+)
+
+// Cache keeps parsed UB source between walks so a long-lived caller, such
+// as the language server, parses a library again only when its content
+// changes. Files are kept by location with a SHA-256 of their content.
+// Walked libraries are keyed by where they were reached from and carry
+// a fingerprint of their own files and of everything they import, so a
+// change to one library invalidates the libraries that import it and
+// leaves the rest alone.
+//
+// A Cache is not safe for concurrent use.
+type Cache struct {
+	files     map[cacheFileKey]*cachedFile
+	libraries map[cacheLibraryKey]*cachedLibrary
+}
+
+type cacheFileKey struct {
+	dir  string
+	name string
+}
+
+// cachedFile is what classification learned from one parse of a file.
+// parsed holds that parse until the library walker takes it, so a file
+// is parsed once per content even though classification and library
+// lowering both read it. The walker takes it rather than sharing it
+// because type binding writes into the lowered syntax.
+type cachedFile struct {
+	hash    string
+	parsed  *lang.File
+	err     error
+	kind    syntax.FileKind
+	factory bool
+}
+
+type cacheLibraryKey struct {
+	canonical     string
+	ref           string
+	path          string
+	localPath     string
+	projectPath   string
+	packageSubdir string
+}
+
+type cachedLibrary struct {
+	content     string
+	fingerprint string
+	lib         *UBLibrary
+}
+
+// NewCache returns an empty cache.
+func NewCache() *Cache {
+	return &Cache{
+		files:     map[cacheFileKey]*cachedFile{},
+		libraries: map[cacheLibraryKey]*cachedLibrary{},
+	}
+}
+
+// ClassifySource is ClassifySource with file parses kept in c. A nil
+// cache classifies without caching.
+func (c *Cache) ClassifySource(source *Source) SourceClassification {
+	return classifySource(source, c)
+}
+
+// file returns the cached facts for the file name in source with content
+// src, parsing it when the content is new.
+func (c *Cache) file(source *Source, name string, src []byte) *cachedFile {
+	key := cacheFileKey{dir: sourceLocalPath(source), name: name}
+	hash := contentHash(src)
+	if f, ok := c.files[key]; ok && f.hash == hash {
+		return f
+	}
+	f := &cachedFile{hash: hash}
+	f.parsed, f.err = lang.ParseSource(name, src)
+	if f.err == nil {
+		sf, err := syntax.LowerParsedSource(name, src, f.parsed)
+		if err != nil {
+			f.err = err
+			f.parsed = nil
+		} else {
+			f.kind = sf.Kind
+			f.factory = sf.Kind == syntax.FileFactory && sf.Factory != nil
+		}
+	}
+	c.files[key] = f
+	return f
+}
+
+// parse returns the parse of name for lowering into a library, taking a
+// parse left by classification when there is one.
+func (c *Cache) parse(source *Source, name string, src []byte) (*lang.File, error) {
+	if c == nil {
+		return lang.ParseSource(name, src)
+	}
+	f, ok := c.files[cacheFileKey{dir: sourceLocalPath(source), name: name}]
+	if !ok || f.parsed == nil || f.hash != contentHash(src) {
+		return lang.ParseSource(name, src)
+	}
+	parsed := f.parsed
+	f.parsed = nil
+	return parsed, nil
+}
+
+func (c *Cache) library(key cacheLibraryKey, content string) (*cachedLibrary, bool) {
+	if c == nil || content == "" {
+		return nil, false
+	}
+	entry, ok := c.libraries[key]
+	if !ok || entry.content != content {
+		return nil, false
+	}
+	return entry, true
+}
+
+func (c *Cache) storeLibrary(key cacheLibraryKey, content string, lib *UBLibrary) {
+	if c == nil || content == "" {
+		return
+	}
+	c.libraries[key] = &cachedLibrary{content: content, fingerprint: lib.Fingerprint, lib: lib}
+}
+
+func libraryCacheKey(canonicalKey string, ref ImportRef, source *Source) cacheLibraryKey {
+	key := cacheLibraryKey{canonical: canonicalKey, ref: UBKey(ref)}
+	if source != nil {
+		key.path = source.Path
+		key.localPath = source.LocalPath
+		key.projectPath = source.ProjectPath
+		key.packageSubdir = source.PackageSubdir
+	}
+	return key
+}
+
+// LibraryContentHash returns a SHA-256 over the names and contents of the
+// `.ub` files at the root of source.
+func LibraryContentHash(source *Source) (string, error) {
+	if source == nil || source.FS == nil {
+		return "", nil
+	}
+	matches, err := fs.Glob(source.FS, "*.ub")
+	if err != nil {
+		return "", err
+	}
+	slices.Sort(matches)
+	h := sha256.New()
+	for _, name := range matches {
+		b, err := readSourceFile(source, name)
+		if err != nil {
+			return "", fmt.Errorf("read %s: %w", name, err)
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00", filepath.ToSlash(name), len(b))
+		h.Write(b)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// libraryFingerprint hashes a library's content with what each of its
+// composites imports. UB imports contribute their own fingerprints, so
+// the result changes when anything the library reaches changes.
+func libraryFingerprint(
+	content string,
+	entries []CompositeEntry,
+	bodyImports map[string]map[string][]Resolution,
+	libraries map[string]*UBLibrary,
+) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00", content)
+	for _, entry := range entries {
+		fmt.Fprintf(h, "%s\x00%s\x00", entry.Kind, entry.Name)
+		for _, res := range bodyImports[entry.Kind][entry.Name] {
+			fmt.Fprintf(h, "%s\x00", ResolutionFingerprint(res, libraries))
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ResolutionFingerprint renders res as a string that changes when the
+// import it describes resolves differently. A UB import includes the
+// fingerprint of its library in libraries.
+func ResolutionFingerprint(res Resolution, libraries map[string]*UBLibrary) string {
+	switch res.Kind {
+	case ResolutionUB:
+		var library string
+		if lib := libraries[res.CanonicalKey]; lib != nil {
+			library = lib.Fingerprint
+		}
+		return strings.Join([]string{"ub", res.LocalAlias, res.CanonicalKey, library}, "\x00")
+	case ResolutionGo:
+		return strings.Join([]string{
+			"go", res.LocalAlias, res.Path, res.ModulePath, res.Version, res.SourcePath,
+		}, "\x00")
+	default:
+		return ""
+	}
+}
+
+func contentHash(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
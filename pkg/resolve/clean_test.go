@@ -13,12 +13,15 @@ func TestCleanImports(t *testing.T) {
 	r := &RemoteResolver{CacheRoot: root}
 	cached := filepath.Join(r.ImportsDir(), "github.com", "x", "y", "abc123")
 	require.NoError(t, os.MkdirAll(cached, 0o755))
+	proxied := filepath.Join(r.ProxyImportsDir(), "proxy.example.com", "github.com", "x", "y", "abc123")
+	require.NoError(t, os.MkdirAll(proxied, 0o755))
 
 	dir, err := r.CleanImports()
 	require.NoError(t, err)
 	require.Equal(t, r.ImportsDir(), dir)
 	_, statErr := os.Stat(r.ImportsDir())
 	require.True(t, os.IsNotExist(statErr))
+	require.NoDirExists(t, r.ProxyImportsDir())
 }
 
 func TestCleanImportsNoCache(t *testing.T) {
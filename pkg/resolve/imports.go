@@ -25,13 +25,16 @@ func (*LocalImport) isImportRef() {}
 // package subdir within the repo. The import string has no version; Version is
 // filled in from project-lock.ub as the walk descends. ProjectSubdir and PackageSubdir
 // are set after project or project-lock lookup when the owning project differs from
-// the imported package.
+// the imported package. Hash, when set, is the content hash project-lock.ub
+// records for the owning project; a tree fetched through a proxy must match
+// it before it is cached.
 type RemoteImport struct {
 	URL           string
 	Subdir        string
 	ProjectSubdir string
 	PackageSubdir string
 	Version       string
+	Hash          string
 }
 
 func (*RemoteImport) isImportRef() {}
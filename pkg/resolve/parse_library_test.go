@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/cloudboss/unobin/pkg/lang"
 )
 
 func TestParseUBLibrarySource(t *testing.T) {
@@ -15,3 +17,24 @@ func TestParseUBLibrarySource(t *testing.T) {
 	require.Len(t, entries, 1)
 	require.Equal(t, "library.ub", entries[0].SourceFile.PackageRelPath)
 }
+
+func TestParseUBLibrarySourceBindsTypes(t *testing.T) {
+	lib, err := ParseUBLibrarySource(newUBFixtureSource(t, "parse-library/valid/types"))
+
+	require.NoError(t, err)
+	require.Len(t, lib.Types, 2)
+	body := lib.SyntaxBodies["resource"]["server"]
+	require.Len(t, body.Inputs, 2)
+	primary, ok := body.Inputs[0].Type.(*lang.TypeNamed)
+	require.True(t, ok)
+	require.Same(t, lib.Types[1].Type, primary.Resolved)
+	name, ok := body.Inputs[1].Type.(*lang.TypeNamed)
+	require.True(t, ok)
+	require.Same(t, body.Types[0].Type, name.Resolved)
+}
+
+func TestParseUBLibrarySourceRejectsDuplicateTypes(t *testing.T) {
+	_, err := ParseUBLibrarySource(newUBFixtureSource(t, "parse-library/invalid/duplicate-types"))
+
+	require.ErrorContains(t, err, `type "endpoint" is declared by more than one file`)
+}
@@ -4,12 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	neturl "net/url"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/cloudboss/unobin/pkg/git"
 	"github.com/cloudboss/unobin/pkg/projectmarker"
+	"github.com/cloudboss/unobin/pkg/proxy"
 	"golang.org/x/mod/semver"
 )
 
@@ -17,24 +19,46 @@ import (
 // git repo at the requested constraint, caching the working tree
 // under CacheRoot, and exposing the requested subdir as a Source.
 //
-// CacheRoot is the directory holding `imports/<host>/<path>/<commit>/`.
+// CacheRoot is the directory holding `imports/<host>/<path>/<commit>/`,
+// and `proxy-imports/<proxy>/<host>/<path>/<commit>/` for trees fetched
+// through a proxy.
 // `NewRemoteResolver` defaults it to `<user-cache-dir>/unobin`.
+//
+// Proxy lists where sources are fetched from, in order, as UNOBIN_PROXY
+// does: proxy URLs, `direct` for the git host, and `off`. A proxy that
+// does not have a repository or ref, or that fails to answer, passes it
+// on to the next entry. An empty Proxy fetches directly.
+//
+// Credentials authenticates direct fetches from private repositories;
+// nil fetches without credentials.
 type RemoteResolver struct {
-	CacheRoot string
+	CacheRoot   string
+	Proxy       []string
+	Credentials *git.Credentials
 }
 
 // NewRemoteResolver returns a RemoteResolver with CacheRoot set to
 // UNOBIN_CACHE_ROOT when present, otherwise the user's cache directory
-// joined with `unobin`.
+// joined with `unobin`, Proxy set from UNOBIN_PROXY, and Credentials
+// set from UNOBIN_GIT_AUTH.
 func NewRemoteResolver() (*RemoteResolver, error) {
-	if cacheRoot := os.Getenv("UNOBIN_CACHE_ROOT"); cacheRoot != "" {
-		return &RemoteResolver{CacheRoot: cacheRoot}, nil
+	sources, err := proxy.FromEnv()
+	if err != nil {
+		return nil, err
 	}
-	cache, err := os.UserCacheDir()
+	creds, err := git.CredentialsFromEnv()
 	if err != nil {
 		return nil, err
 	}
-	return &RemoteResolver{CacheRoot: filepath.Join(cache, "unobin")}, nil
+	r := &RemoteResolver{CacheRoot: os.Getenv("UNOBIN_CACHE_ROOT"), Proxy: sources, Credentials: creds}
+	if r.CacheRoot == "" {
+		cache, err := os.UserCacheDir()
+		if err != nil {
+			return nil, err
+		}
+		r.CacheRoot = filepath.Join(cache, "unobin")
+	}
+	return r, nil
 }
 
 // GitRef returns the first git ref used to fetch ref.
@@ -104,33 +128,181 @@ func (r *RemoteResolver) Resolve(ref ImportRef) (*Source, error) {
 	}
 	ctx := context.Background()
 
-	cloneURL := WithDefaultScheme(ri.URL)
-	gitRef, commit, err := resolveRemoteRef(ctx, cloneURL, gitRefs(ri))
+	found, err := r.lookup(ctx, ri.URL, gitRefs(ri))
 	if err != nil {
 		return nil, err
 	}
 
-	dir := r.cacheDir(ri.URL, commit)
+	dir := filepath.Join(found.repoDir, found.commit)
 	if !dirExists(dir) {
-		if err := r.fetchInto(ctx, cloneURL, gitRef, dir); err != nil {
+		fetch := found.fetch
+		if found.proxied && ri.Hash != "" {
+			fetch = verifiedFetch(ri, found.fetch)
+		}
+		if err := fetchInto(dir, fetch); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := unprefixedVersion(remoteProjectSubdir(ri), found.ref); ok {
+		if err := proxy.RecordRef(found.repoDir, found.ref, found.commit); err != nil {
 			return nil, err
 		}
 	}
 
-	return sourceFromRemoteCache(ri, commit, dir)
+	return sourceFromRemoteCache(ri, found.commit, dir)
+}
+
+// remoteRef is a ref found at one of the resolver's sources, with the
+// commit it names, how to fetch that commit's tree, and the cached
+// repository directory the tree goes in. proxied is set when the tree
+// comes from a proxy, which names the commit itself.
+type remoteRef struct {
+	ref     string
+	commit  string
+	fetch   func(dest string) error
+	repoDir string
+	proxied bool
+}
+
+// verifiedFetch wraps fetch so that the fetched tree of ri's project
+// must have the content hash ri records, or it is not cached.
+func verifiedFetch(ri *RemoteImport, fetch func(dest string) error) func(dest string) error {
+	return func(dest string) error {
+		if err := fetch(dest); err != nil {
+			return err
+		}
+		project, projectDir := ri.URL, dest
+		if subdir := remoteProjectSubdir(ri); subdir != "" {
+			project += "//" + subdir
+			projectDir = filepath.Join(dest, filepath.FromSlash(subdir))
+		}
+		hash, err := HashUBProject(os.DirFS(projectDir))
+		if err != nil {
+			return fmt.Errorf("fetch %s: %w", project, err)
+		}
+		if hash != ri.Hash {
+			return fmt.Errorf("fetch %s: hash mismatch (selected %s, got %s)",
+				project, ri.Hash, hash)
+		}
+		return nil
+	}
+}
+
+// lookup finds the first of refs at the first source that has it. A
+// proxy that cannot be reached or fails to answer passes the lookup on
+// like one that does not have the ref, and its error is reported only
+// when no later source has the ref either.
+func (r *RemoteResolver) lookup(ctx context.Context, url string, refs []string) (remoteRef, error) {
+	var errs []error
+	for _, source := range r.sources(url) {
+		switch source {
+		case proxy.Off:
+			return remoteRef{}, errors.Join(
+				append(errs, fmt.Errorf("fetch %s: %s=%s", url, proxy.Env, proxy.Off))...)
+		case proxy.Direct:
+			cloneURL := WithDefaultScheme(url)
+			ref, commit, err := resolveRemoteRef(ctx, cloneURL, refs, r.Credentials)
+			if err != nil {
+				return remoteRef{}, errors.Join(append(errs, err)...)
+			}
+			return remoteRef{ref: ref, commit: commit, repoDir: r.repoDir(url),
+				fetch: func(dest string) error {
+					_, err := git.Clone(ctx, cloneURL, ref, dest, r.Credentials)
+					return err
+				}}, nil
+		}
+		client := &proxy.Client{BaseURL: source}
+		repo := proxy.RepoPath(url)
+		for _, ref := range refs {
+			info, err := client.Info(ctx, repo, ref)
+			if errors.Is(err, proxy.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				errs = append(errs, err)
+				break
+			}
+			// The proxy names the commit and hashes the zip itself, so
+			// its trees are cached apart from those fetched from git
+			// hosts and other proxies.
+			return remoteRef{ref: ref, commit: info.Commit, repoDir: r.proxyRepoDir(source, url),
+				proxied: true,
+				fetch: func(dest string) error {
+					return client.Download(ctx, repo, info, dest)
+				}}, nil
+		}
+	}
+	return remoteRef{}, errors.Join(append(errs, fmt.Errorf("fetch %s: no source in %s has %s",
+		url, proxy.Env, strings.Join(refs, " or ")))...)
 }
 
-// CachedSource returns source data for an existing cached commit without contacting git.
+// sources returns where url is fetched from: the resolver's proxy list,
+// or directly for a URL no proxy can serve.
+func (r *RemoteResolver) sources(url string) []string {
+	if len(r.Proxy) == 0 || !proxy.Proxied(url) {
+		return []string{proxy.Direct}
+	}
+	return r.Proxy
+}
+
+// ListTags returns the tags of the repository at url from the first
+// source that has it. A proxy that fails to answer passes the listing
+// on, as in lookup.
+func (r *RemoteResolver) ListTags(ctx context.Context, url string) ([]string, error) {
+	var errs []error
+	for _, source := range r.sources(url) {
+		switch source {
+		case proxy.Off:
+			return nil, errors.Join(
+				append(errs, fmt.Errorf("list tags of %s: %s=%s", url, proxy.Env, proxy.Off))...)
+		case proxy.Direct:
+			tags, err := git.ListTags(ctx, WithDefaultScheme(url), r.Credentials)
+			if err != nil {
+				return nil, errors.Join(append(errs, err)...)
+			}
+			return tags, nil
+		}
+		client := &proxy.Client{BaseURL: source}
+		tags, err := client.List(ctx, proxy.RepoPath(url))
+		if errors.Is(err, proxy.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		return tags, nil
+	}
+	return nil, errors.Join(append(errs,
+		fmt.Errorf("list tags of %s: no source in %s has it", url, proxy.Env))...)
+}
+
+// CachedSource returns source data for an existing cached commit without
+// contacting git. A commit fetched from git comes first, then one fetched
+// from each proxy the resolver lists, in order.
 func (r *RemoteResolver) CachedSource(ref *RemoteImport, commit string) (*Source, bool, error) {
-	dir := r.cacheDir(ref.URL, commit)
-	if !dirExists(dir) {
-		return nil, false, nil
+	for _, dir := range r.cacheDirs(ref.URL, commit) {
+		if !dirExists(dir) {
+			continue
+		}
+		src, err := sourceFromRemoteCache(ref, commit, dir)
+		if err != nil {
+			return nil, true, err
+		}
+		return src, true, nil
 	}
-	src, err := sourceFromRemoteCache(ref, commit, dir)
-	if err != nil {
-		return nil, true, err
+	return nil, false, nil
+}
+
+// cacheDirs returns the directories a commit of url may be cached in.
+func (r *RemoteResolver) cacheDirs(url, commit string) []string {
+	dirs := []string{r.cacheDir(url, commit)}
+	for _, source := range r.sources(url) {
+		if source != proxy.Direct && source != proxy.Off {
+			dirs = append(dirs, filepath.Join(r.proxyRepoDir(source, url), commit))
+		}
 	}
-	return src, true, nil
+	return dirs
 }
 
 func sourceFromRemoteCache(ref *RemoteImport, commit, dir string) (*Source, error) {
@@ -197,10 +369,12 @@ func goImportPath(modulePath, packageSubdir string) string {
 	return modulePath + "/" + packageSubdir
 }
 
-func resolveRemoteRef(ctx context.Context, url string, refs []string) (string, string, error) {
+func resolveRemoteRef(
+	ctx context.Context, url string, refs []string, creds *git.Credentials,
+) (string, string, error) {
 	var errs []error
 	for _, ref := range refs {
-		commit, err := git.LsRemote(ctx, url, ref)
+		commit, err := git.LsRemote(ctx, url, ref, creds)
 		if err == nil {
 			return ref, commit, nil
 		}
@@ -209,10 +383,13 @@ func resolveRemoteRef(ctx context.Context, url string, refs []string) (string, s
 	return "", "", errors.Join(errs...)
 }
 
-func (r *RemoteResolver) fetchInto(ctx context.Context, url, ref, dir string) error {
+// fetchInto fetches a tree with fetch into a temporary directory beside
+// dir and moves it into place, so dir exists only once it is complete.
+func fetchInto(dir string, fetch func(dest string) error) error {
 	tmp := dir + ".tmp"
 	_ = os.RemoveAll(tmp)
-	if _, err := git.Clone(ctx, url, ref, tmp); err != nil {
+	if err := fetch(tmp); err != nil {
+		_ = os.RemoveAll(tmp)
 		return err
 	}
 	if err := os.MkdirAll(filepath.Dir(dir), 0o755); err != nil {
@@ -227,7 +404,19 @@ func (r *RemoteResolver) fetchInto(ctx context.Context, url, ref, dir string) er
 }
 
 func (r *RemoteResolver) cacheDir(url, commit string) string {
-	return filepath.Join(r.ImportsDir(), normalizeURL(url), commit)
+	return filepath.Join(r.repoDir(url), commit)
+}
+
+func (r *RemoteResolver) repoDir(url string) string {
+	return filepath.Join(r.ImportsDir(), normalizeURL(url))
+}
+
+// proxyRepoDir returns the directory caching url's commits fetched from
+// the proxy at source, one per proxy so that no proxy can replace a tree
+// fetched from elsewhere.
+func (r *RemoteResolver) proxyRepoDir(source, url string) string {
+	return filepath.Join(r.ProxyImportsDir(),
+		neturl.PathEscape(proxy.RepoPath(source)), normalizeURL(url))
 }
 
 // ImportsDir is the directory holding cached import sources, a sibling of
@@ -236,13 +425,23 @@ func (r *RemoteResolver) ImportsDir() string {
 	return filepath.Join(r.CacheRoot, "imports")
 }
 
-// CleanImports removes the cached import sources and returns the directory
-// that was removed. It is a no-op when nothing is cached.
+// ProxyImportsDir is the directory holding cached import sources fetched
+// from proxies, kept apart from ImportsDir.
+func (r *RemoteResolver) ProxyImportsDir() string {
+	return filepath.Join(r.CacheRoot, "proxy-imports")
+}
+
+// CleanImports removes the cached import sources, including those fetched
+// from proxies, and returns ImportsDir. It is a no-op when nothing is
+// cached.
 func (r *RemoteResolver) CleanImports() (string, error) {
 	dir := r.ImportsDir()
 	if err := os.RemoveAll(dir); err != nil {
 		return "", err
 	}
+	if err := os.RemoveAll(r.ProxyImportsDir()); err != nil {
+		return "", err
+	}
 	return dir, nil
 }
 
@@ -1,7 +1,10 @@
 package resolve
 
 import (
+	"context"
 	"io/fs"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -11,6 +14,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/cloudboss/unobin/internal/ubtest"
+	"github.com/cloudboss/unobin/pkg/proxy"
 )
 
 func remoteResolverFixture(t testing.TB, name string) string {
@@ -367,3 +371,129 @@ func TestRemoteResolverNormalizesURL(t *testing.T) {
 		require.Equal(t, c.want, got, "normalizeURL(%q)", c.in)
 	}
 }
+
+// proxyCache writes a repository proxied at example.com/x/lib, cached at
+// commit c1 with the tag v1.0.0 recorded, and serves it.
+func proxyCache(t *testing.T) *httptest.Server {
+	t.Helper()
+	imports := t.TempDir()
+	repoDir := filepath.Join(imports, "example.com", "x", "lib")
+	require.NoError(t, os.MkdirAll(filepath.Join(repoDir, "c1"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "c1", "library.ub"),
+		[]byte("cluster: resource { description: 'proxied' }\n"), 0o644))
+	require.NoError(t, proxy.RecordRef(repoDir, "v1.0.0", "c1"))
+	srv := httptest.NewServer(proxy.NewHandler(imports))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestRemoteResolverFetchesThroughProxy(t *testing.T) {
+	srv := proxyCache(t)
+	r := &RemoteResolver{CacheRoot: t.TempDir(), Proxy: []string{srv.URL, proxy.Off}}
+
+	got, err := r.Resolve(&RemoteImport{URL: "example.com/x/lib", Version: "v1.0.0"})
+	require.NoError(t, err)
+	require.Equal(t, "c1", got.Commit)
+	body, err := fs.ReadFile(got.FS, "library.ub")
+	require.NoError(t, err)
+	require.Contains(t, string(body), "proxied")
+
+	// The tree and its tag go in the proxy's own cache, never the one
+	// trees fetched from the git host are in.
+	require.NoDirExists(t, r.cacheDir("example.com/x/lib", "c1"))
+	proxyDir := r.proxyRepoDir(srv.URL, "example.com/x/lib")
+	require.Equal(t, filepath.Join(proxyDir, "c1"), got.ProjectPath)
+	recorded, err := os.ReadFile(filepath.Join(proxyDir, proxy.RefsDir, "v1.0.0"))
+	require.NoError(t, err)
+	require.Equal(t, "c1\n", string(recorded))
+
+	cached, ok, err := r.CachedSource(&RemoteImport{URL: "example.com/x/lib"}, "c1")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, got.ProjectPath, cached.ProjectPath)
+	direct := &RemoteResolver{CacheRoot: r.CacheRoot}
+	_, ok, err = direct.CachedSource(&RemoteImport{URL: "example.com/x/lib"}, "c1")
+	require.NoError(t, err)
+	require.False(t, ok, "a proxy's tree is not read without the proxy")
+
+	tags, err := r.ListTags(context.Background(), "example.com/x/lib")
+	require.NoError(t, err)
+	require.Equal(t, []string{"v1.0.0"}, tags)
+}
+
+func TestRemoteResolverChecksProxiedTreeAgainstHash(t *testing.T) {
+	srv := proxyCache(t)
+	r := &RemoteResolver{CacheRoot: t.TempDir(), Proxy: []string{srv.URL, proxy.Off}}
+	dir := filepath.Join(r.proxyRepoDir(srv.URL, "example.com/x/lib"), "c1")
+
+	_, err := r.Resolve(&RemoteImport{
+		URL: "example.com/x/lib", Version: "v1.0.0", Hash: "sha256:0000",
+	})
+	require.ErrorContains(t, err,
+		"fetch example.com/x/lib: hash mismatch (selected sha256:0000, got sha256:")
+	require.NoDirExists(t, dir, "a tree that fails the check is not cached")
+
+	unchecked, err := r.Resolve(&RemoteImport{URL: "example.com/x/lib", Version: "v1.0.0"})
+	require.NoError(t, err)
+	hash, err := HashUBProject(unchecked.ProjectFS)
+	require.NoError(t, err)
+	require.NoError(t, os.RemoveAll(dir))
+
+	got, err := r.Resolve(&RemoteImport{URL: "example.com/x/lib", Version: "v1.0.0", Hash: hash})
+	require.NoError(t, err)
+	require.Equal(t, dir, got.ProjectPath)
+}
+
+func TestRemoteResolverProxyPassesMissingOn(t *testing.T) {
+	srv := proxyCache(t)
+	r := &RemoteResolver{CacheRoot: t.TempDir(), Proxy: []string{srv.URL, proxy.Off}}
+
+	_, err := r.Resolve(&RemoteImport{URL: "example.com/x/lib", Version: "v2.0.0"})
+	require.EqualError(t, err, "fetch example.com/x/lib: UNOBIN_PROXY=off")
+	_, err = r.ListTags(context.Background(), "example.com/x/other")
+	require.EqualError(t, err, "list tags of example.com/x/other: UNOBIN_PROXY=off")
+
+	r.Proxy = []string{srv.URL}
+	_, err = r.Resolve(&RemoteImport{URL: "example.com/x/lib", Version: "v2.0.0"})
+	require.EqualError(t, err, "fetch example.com/x/lib: no source in UNOBIN_PROXY has v2.0.0")
+}
+
+func TestRemoteResolverProxyPassesFailuresOn(t *testing.T) {
+	srv := proxyCache(t)
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "upstream unavailable", http.StatusBadGateway)
+	}))
+	t.Cleanup(broken.Close)
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+	r := &RemoteResolver{
+		CacheRoot: t.TempDir(),
+		Proxy:     []string{down.URL, broken.URL, srv.URL, proxy.Off},
+	}
+
+	got, err := r.Resolve(&RemoteImport{URL: "example.com/x/lib", Version: "v1.0.0"})
+	require.NoError(t, err)
+	require.Equal(t, "c1", got.Commit)
+	tags, err := r.ListTags(context.Background(), "example.com/x/lib")
+	require.NoError(t, err)
+	require.Equal(t, []string{"v1.0.0"}, tags)
+
+	r.Proxy = []string{broken.URL}
+	_, err = r.Resolve(&RemoteImport{URL: "example.com/x/lib", Version: "v1.0.0"})
+	require.ErrorContains(t, err, "upstream unavailable")
+	require.ErrorContains(t, err, "fetch example.com/x/lib: no source in UNOBIN_PROXY has v1.0.0")
+	_, err = r.ListTags(context.Background(), "example.com/x/lib")
+	require.ErrorContains(t, err, "upstream unavailable")
+}
+
+func TestRemoteResolverFetchesLocalRepoDirectly(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "src")
+	wantSHA := makeRemoteRepo(t, src, map[string]string{
+		"library.ub": "cluster: resource { description: 'remote' }\n",
+	})
+
+	r := &RemoteResolver{CacheRoot: t.TempDir(), Proxy: []string{proxy.Off}}
+	got, err := r.Resolve(&RemoteImport{URL: src, Version: "v1"})
+	require.NoError(t, err)
+	require.Equal(t, wantSHA, got.Commit)
+}
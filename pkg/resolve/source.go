@@ -58,8 +58,12 @@ type SourceClassification struct {
 
 // ClassifySource classifies a resolved source for import walkers.
 func ClassifySource(source *Source) SourceClassification {
-	hasExports := HasCompositeExports(source)
-	if ContainsFactorySource(source) {
+	return classifySource(source, nil)
+}
+
+func classifySource(source *Source, cache *Cache) SourceClassification {
+	hasExports := hasCompositeExports(source, cache)
+	if containsFactorySource(source, cache) {
 		return SourceClassification{Kind: SourceFactory, HasCompositeExports: hasExports}
 	}
 	if hasExports {
@@ -94,6 +98,10 @@ func IsGoLibrary(s *Source) bool {
 
 // HasCompositeExports reports whether s has source-declared composite exports.
 func HasCompositeExports(s *Source) bool {
+	return hasCompositeExports(s, nil)
+}
+
+func hasCompositeExports(s *Source, cache *Cache) bool {
 	if s == nil || s.FS == nil {
 		return false
 	}
@@ -102,18 +110,25 @@ func HasCompositeExports(s *Source) bool {
 		return false
 	}
 	for _, name := range matches {
-		if sourceFileMayBeLibrary(s.FS, name) {
+		if sourceFileMayBeLibrary(s, name, cache) {
 			return true
 		}
 	}
 	return false
 }
 
-func sourceFileMayBeLibrary(fsys fs.FS, name string) bool {
-	b, err := fs.ReadFile(fsys, name)
+func sourceFileMayBeLibrary(s *Source, name string, cache *Cache) bool {
+	b, err := fs.ReadFile(s.FS, name)
 	if err != nil {
 		return true
 	}
+	if cache != nil {
+		f := cache.file(s, name, b)
+		if f.err != nil {
+			return !isMetadataFileName(name)
+		}
+		return f.kind == syntax.FileLibrary
+	}
 	f, err := syntax.ParseSource(name, b)
 	if err != nil {
 		return !isMetadataFileName(name)
@@ -146,6 +161,10 @@ func cleanSourceName(name string) string {
 // ContainsFactorySource reports whether s has a root file that declares a
 // runnable factory instead of an importable library.
 func ContainsFactorySource(s *Source) bool {
+	return containsFactorySource(s, nil)
+}
+
+func containsFactorySource(s *Source, cache *Cache) bool {
 	if s == nil || s.FS == nil {
 		return false
 	}
@@ -153,6 +172,10 @@ func ContainsFactorySource(s *Source) bool {
 	if err != nil {
 		return false
 	}
+	if cache != nil {
+		f := cache.file(s, "factory.ub", b)
+		return f.err == nil && f.factory
+	}
 	f, err := syntax.ParseSource("factory.ub", b)
 	if err != nil {
 		return false
@@ -0,0 +1,63 @@
+package resolve
+
+import (
+	"github.com/cloudboss/unobin/pkg/lang/parse"
+	"github.com/cloudboss/unobin/pkg/lang/syntax"
+)
+
+// BindImportedTypes binds each alias.name type reference in types to
+// the type declared under that name by the UB library the alias
+// resolves to in resols. libraries is keyed by canonical key. An alias
+// the body does not import was already reported when the body was
+// lowered and is skipped here.
+func BindImportedTypes(
+	types []parse.TypeExpr,
+	resols []Resolution,
+	libraries map[string]*UBLibrary,
+) error {
+	byAlias := make(map[string]Resolution, len(resols))
+	for _, res := range resols {
+		byAlias[res.LocalAlias] = res
+	}
+	errs := parse.NewErrorList(0)
+	for _, t := range types {
+		for _, ref := range syntax.NamedTypes(t) {
+			if ref.Library == "" {
+				continue
+			}
+			res, ok := byAlias[ref.Library]
+			if !ok {
+				continue
+			}
+			if res.Kind != ResolutionUB {
+				errs.Addf(parse.ErrResolve, ref.S.Start,
+					"type %s: library %q is a Go library, which declares no types",
+					ref.QualifiedName(), ref.Library)
+				continue
+			}
+			decl, ok := libraryType(libraries[res.CanonicalKey], ref.Name)
+			if !ok {
+				errs.Addf(parse.ErrResolve, ref.S.Start,
+					"library %q declares no type %q", ref.Library, ref.Name)
+				continue
+			}
+			ref.Resolved = decl.Type
+		}
+	}
+	if errs.Len() > 0 {
+		return errs.Err()
+	}
+	return nil
+}
+
+func libraryType(lib *UBLibrary, name string) (syntax.TypeDecl, bool) {
+	if lib == nil {
+		return syntax.TypeDecl{}, false
+	}
+	for _, decl := range lib.Types {
+		if decl.Name.Name == name {
+			return decl, true
+		}
+	}
+	return syntax.TypeDecl{}, false
+}
@@ -11,7 +11,6 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/cloudboss/unobin/pkg/lang"
 	"github.com/cloudboss/unobin/pkg/lang/parse"
 	"github.com/cloudboss/unobin/pkg/lang/syntax"
 	"github.com/cloudboss/unobin/pkg/projectmarker"
@@ -82,7 +81,16 @@ type UBLibrary struct {
 	SourceFiles  map[string]syntax.SourceFileSpec
 	BodyImports  map[string]map[string][]Resolution
 	Entries      []CompositeEntry
-	Source       *Source
+	// Functions holds the library's `functions:` declarations from
+	// every file, in file order.
+	Functions []syntax.FunctionDecl
+	// Types holds the library's `types:` declarations from every file,
+	// in file order. Importers name them as alias.name.
+	Types  []syntax.TypeDecl
+	Source *Source
+	// Fingerprint changes when the library's files or anything it
+	// imports change. It is set only by walks with a Cache.
+	Fingerprint string
 }
 
 type CompositeEntry struct {
@@ -143,12 +151,28 @@ func WalkUBFrom(
 	v UBVisitor,
 	versions map[string]string,
 	source *Source,
+) ([]Resolution, error) {
+	return WalkUBFromCache(refs, resolver, v, versions, source, nil)
+}
+
+// WalkUBFromCache is WalkUBFrom with libraries kept in cache between
+// walks. A library whose files and imports are unchanged since the last
+// walk is handed to the visitor as it was, without parsing it again. A
+// nil cache walks without caching.
+func WalkUBFromCache(
+	refs map[string]ImportRef,
+	resolver Resolver,
+	v UBVisitor,
+	versions map[string]string,
+	source *Source,
+	cache *Cache,
 ) ([]Resolution, error) {
 	w := &ubWalker{
 		resolver:         resolver,
 		visitor:          v,
 		versions:         versions,
-		factoryRoot:      factoryRootSource(source),
+		cache:            cache,
+		factoryRoot:      factoryRootSource(source, cache),
 		parsed:           map[string]*UBLibrary{},
 		inProgress:       map[string]bool{},
 		goModuleProjects: map[string]string{},
@@ -161,6 +185,7 @@ type ubWalker struct {
 	resolver         Resolver
 	visitor          UBVisitor
 	versions         map[string]string
+	cache            *Cache
 	factoryRoot      *Source
 	parsed           map[string]*UBLibrary
 	inProgress       map[string]bool
@@ -196,8 +221,8 @@ type remoteOwner struct {
 	version       string
 }
 
-func factoryRootSource(source *Source) *Source {
-	if ContainsFactorySource(source) {
+func factoryRootSource(source *Source, cache *Cache) *Source {
+	if containsFactorySource(source, cache) {
 		return source
 	}
 	return nil
@@ -336,7 +361,7 @@ func (w *ubWalker) walkOne(
 		}
 	}
 	_, local := ref.(*LocalImport)
-	classification := ClassifySource(source)
+	classification := classifySource(source, w.cache)
 	switch classification.Kind {
 	case SourceFactory:
 		if !local || !w.isSelfFactoryImport(ref, parent, source) {
@@ -632,13 +657,28 @@ func (w *ubWalker) handleUBImport(
 	w.inProgress[key] = true
 	defer delete(w.inProgress, key)
 
-	lib, err := w.parseLibrary(source)
-	if err != nil {
-		return Resolution{}, fmt.Errorf("import %q: %w", alias, err)
+	var content string
+	if w.cache != nil {
+		var err error
+		if content, err = LibraryContentHash(source); err != nil {
+			return Resolution{}, fmt.Errorf("import %q: %w", alias, err)
+		}
 	}
-	applyUBLibrarySourceDisplayPaths(lib, ref, resolvedUBLibraryPath(ref, source))
-	lib.BodyImports = map[string]map[string][]Resolution{}
-	for _, entry := range lib.CompositeEntries() {
+	cacheKey := libraryCacheKey(key, ref, source)
+	cached, hit := w.cache.library(cacheKey, content)
+	var lib *UBLibrary
+	if hit {
+		lib = cached.lib
+		lib.Source = source
+	} else {
+		var err error
+		if lib, err = w.parseUBLibrary(source, ref); err != nil {
+			return Resolution{}, fmt.Errorf("import %q: %w", alias, err)
+		}
+	}
+	bodyImports := map[string]map[string][]Resolution{}
+	entries := lib.CompositeEntries()
+	for _, entry := range entries {
 		bodyRefs, errs := libraryBodyImports(entry)
 		if len(errs) > 0 {
 			return Resolution{}, errors.Join(errs...)
@@ -648,11 +688,38 @@ func (w *ubWalker) handleUBImport(
 			return Resolution{}, fmt.Errorf(
 				"import %q: composite %q: %w", alias, entry.Name, err)
 		}
-		if lib.BodyImports[entry.Kind] == nil {
-			lib.BodyImports[entry.Kind] = map[string][]Resolution{}
+		if bodyImports[entry.Kind] == nil {
+			bodyImports[entry.Kind] = map[string][]Resolution{}
+		}
+		bodyImports[entry.Kind][entry.Name] = resols
+	}
+	fingerprint := ""
+	if w.cache != nil {
+		fingerprint = libraryFingerprint(content, entries, bodyImports, w.parsed)
+	}
+	if hit && cached.fingerprint != fingerprint {
+		// An import changed under unchanged files. The cached syntax is
+		// bound to the old import's types, so parse it again.
+		hit = false
+		var err error
+		if lib, err = w.parseUBLibrary(source, ref); err != nil {
+			return Resolution{}, fmt.Errorf("import %q: %w", alias, err)
+		}
+		entries = lib.CompositeEntries()
+	}
+	if !hit {
+		for _, entry := range entries {
+			types := syntax.BodyTypes(entry.SyntaxBody)
+			resols := bodyImports[entry.Kind][entry.Name]
+			if err := BindImportedTypes(types, resols, w.parsed); err != nil {
+				return Resolution{}, fmt.Errorf(
+					"import %q: composite %q: %w", alias, entry.Name, err)
+			}
 		}
-		lib.BodyImports[entry.Kind][entry.Name] = resols
 	}
+	lib.BodyImports = bodyImports
+	lib.Fingerprint = fingerprint
+	w.cache.storeLibrary(cacheKey, content, lib)
 	w.parsed[key] = lib
 	if err := w.visitor.OnUBLibrary(alias, key, ref, lib); err != nil {
 		return Resolution{}, fmt.Errorf("import %q: %w", alias, err)
@@ -671,6 +738,17 @@ func ParseUBLibrarySource(source *Source) (*UBLibrary, error) {
 	return (&ubWalker{}).parseLibrary(source)
 }
 
+// parseUBLibrary parses the library at source with display paths for the
+// import ref that reached it.
+func (w *ubWalker) parseUBLibrary(source *Source, ref ImportRef) (*UBLibrary, error) {
+	lib, err := w.parseLibrary(source)
+	if err != nil {
+		return nil, err
+	}
+	applyUBLibrarySourceDisplayPaths(lib, ref, resolvedUBLibraryPath(ref, source))
+	return lib, nil
+}
+
 // parseLibrary reads a UB library's composite bodies from source-declared
 // composite export files.
 func libraryBodyImports(entry CompositeEntry) (map[string]ImportRef, []error) {
@@ -683,37 +761,60 @@ func (w *ubWalker) parseLibrary(source *Source) (*UBLibrary, error) {
 		return nil, err
 	}
 	slices.Sort(matches)
-	syntaxBodies := make(map[string]map[string]syntax.FactoryBody, len(matches))
-	sourceFiles := make(map[string]syntax.SourceFileSpec, len(matches))
-	var entries []CompositeEntry
+	var files []libraryFile
 	for _, filename := range matches {
 		b, err := readSourceFile(source, filename)
 		if err != nil {
 			return nil, fmt.Errorf("read %s: %w", filename, err)
 		}
-		sourceFile := librarySourceFileSpec(source, filename, b)
-		added, err := addSourceDeclaredLibraryFile(
-			filename,
-			b,
-			sourceFile,
-			syntaxBodies,
-			&entries,
-		)
+		sf, err := w.lowerSourceDeclaredLibraryFile(source, filename, b)
 		if err != nil {
 			return nil, err
 		}
-		if added {
-			sourceFiles[filename] = sourceFile
+		if sf != nil {
+			files = append(files, libraryFile{
+				name:   filename,
+				spec:   librarySourceFileSpec(source, filename, b),
+				syntax: sf,
+			})
 		}
 	}
+	types, err := bindLibraryTypes(files)
+	if err != nil {
+		return nil, err
+	}
+	syntaxBodies := make(map[string]map[string]syntax.FactoryBody, len(files))
+	sourceFiles := make(map[string]syntax.SourceFileSpec, len(files))
+	var entries []CompositeEntry
+	var functions []syntax.FunctionDecl
+	for _, file := range files {
+		if err := addSourceDeclaredLibraryFile(
+			file,
+			syntaxBodies,
+			&entries,
+			&functions,
+		); err != nil {
+			return nil, err
+		}
+		sourceFiles[file.name] = file.spec
+	}
 	return &UBLibrary{
 		SyntaxBodies: syntaxBodies,
 		SourceFiles:  sourceFiles,
 		Entries:      entries,
+		Functions:    functions,
+		Types:        types,
 		Source:       source,
 	}, nil
 }
 
+// libraryFile is one lowered file of a UB library package.
+type libraryFile struct {
+	name   string
+	spec   syntax.SourceFileSpec
+	syntax *syntax.File
+}
+
 func librarySourceFileSpec(source *Source, filename string, src []byte) syntax.SourceFileSpec {
 	packageRelPath := filepath.ToSlash(filename)
 	return syntax.SourceFileSpec{
@@ -867,51 +968,116 @@ func sourceLocalPath(source *Source) string {
 	return source.Path
 }
 
-func addSourceDeclaredLibraryFile(
-	filename string,
-	src []byte,
-	sourceFile syntax.SourceFileSpec,
-	syntaxBodies map[string]map[string]syntax.FactoryBody,
-	entries *[]CompositeEntry,
-) (bool, error) {
-	f, err := lang.ParseSource(filename, src)
+// lowerSourceDeclaredLibraryFile parses and lowers one file of a
+// library package. It returns nil for a factory, project, or stack file
+// the package may also hold, and for a library file that declares
+// nothing.
+func (w *ubWalker) lowerSourceDeclaredLibraryFile(
+	source *Source, filename string, src []byte,
+) (*syntax.File, error) {
+	f, err := w.cache.parse(source, filename, src)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 	sf, serrs := syntax.LowerFile(f)
 	if serrs.Len() > 0 {
 		if isReservedSourceFileName(filename) {
-			return false, serrs.Err()
+			return nil, serrs.Err()
 		}
-		return false, fmt.Errorf("library file %q must contain composite declarations", filename)
+		return nil, fmt.Errorf(
+			"library file %q must contain composite, function, or type declarations", filename)
 	}
 	if sf.Kind != syntax.FileLibrary || sf.Library == nil {
 		if skippableLibraryPackageFile(sf.Kind) {
-			return false, nil
+			return nil, nil
+		}
+		return nil, fmt.Errorf(
+			"library file %q must contain composite, function, or type declarations", filename)
+	}
+	if !hasLibraryDeclarations(sf) {
+		return nil, nil
+	}
+	return sf, nil
+}
+
+func hasLibraryDeclarations(sf *syntax.File) bool {
+	if sf == nil || sf.Library == nil {
+		return false
+	}
+	lib := sf.Library
+	return len(lib.Exports)+len(lib.Functions)+len(lib.Types) > 0
+}
+
+// bindLibraryTypes merges the `types:` declared across a library's
+// files and binds the bare type names in the library's types, function
+// signatures, and composite bodies, where a composite's own `types:`
+// hide the library's. A name declared by no file is reported.
+func bindLibraryTypes(files []libraryFile) ([]syntax.TypeDecl, error) {
+	var types []syntax.TypeDecl
+	var functions []syntax.FunctionDecl
+	for _, file := range files {
+		for _, decl := range file.syntax.Library.Types {
+			if slices.ContainsFunc(types, func(prev syntax.TypeDecl) bool {
+				return prev.Name.Name == decl.Name.Name
+			}) {
+				return nil, fmt.Errorf("type %q is declared by more than one file", decl.Name.Name)
+			}
+			types = append(types, decl)
 		}
-		return false, fmt.Errorf("library file %q must contain composite declarations", filename)
+		functions = append(functions, file.syntax.Library.Functions...)
+	}
+	errs := parse.NewErrorList(0)
+	library := syntax.NewTypeScope(nil, types)
+	syntax.ReportUnboundTypes(library.Bind(syntax.FunctionTypes(functions), errs), errs)
+	for _, file := range files {
+		for _, export := range file.syntax.Library.Exports {
+			body := syntax.NewTypeScope(library, export.Body.Types)
+			syntax.ReportUnboundTypes(body.Bind(syntax.BodyTypes(export.Body), errs), errs)
+		}
+	}
+	if errs.Len() > 0 {
+		return nil, errs.Err()
 	}
+	return types, nil
+}
+
+func addSourceDeclaredLibraryFile(
+	file libraryFile,
+	syntaxBodies map[string]map[string]syntax.FactoryBody,
+	entries *[]CompositeEntry,
+	functions *[]syntax.FunctionDecl,
+) error {
+	sf := file.syntax
 	if verrs := syntax.ValidateFile(sf); verrs.Len() > 0 {
-		return false, verrs.Err()
+		return verrs.Err()
 	}
 	for _, export := range sf.Library.Exports {
 		kind := string(export.Kind)
 		if err := addSyntaxLibraryBody(export.Name.Name, kind, export.Body, syntaxBodies); err != nil {
-			return false, err
+			return err
 		}
 		*entries = append(*entries, CompositeEntry{
 			Kind:       kind,
 			Name:       export.Name.Name,
 			SyntaxBody: export.Body,
-			SourceFile: sourceFile,
+			SourceFile: file.spec,
 		})
 	}
-	return len(sf.Library.Exports) > 0, nil
+	for _, fn := range sf.Library.Functions {
+		if slices.ContainsFunc(*functions, func(prev syntax.FunctionDecl) bool {
+			return prev.Name.Name == fn.Name.Name
+		}) {
+			return fmt.Errorf("function %q is declared by more than one file", fn.Name.Name)
+		}
+		*functions = append(*functions, fn)
+	}
+	return nil
 }
 
 func skippableLibraryPackageFile(kind syntax.FileKind) bool {
 	switch kind {
-	case syntax.FileFactory, syntax.FileProject, syntax.FileProjectLock, syntax.FileStack:
+	case syntax.FileFactory, syntax.FileProject, syntax.FileProjectLock, syntax.FileStack,
+		syntax.FileTest:
 		return true
 	default:
 		return false
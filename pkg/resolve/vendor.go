@@ -0,0 +1,24 @@
+package resolve
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// VendorRepoDir returns the directory under vendorRoot that holds the
+// vendored copy of the repository at url, laid out the way the import
+// cache lays it out without the commit level.
+func VendorRepoDir(vendorRoot, url string) string {
+	return filepath.Join(vendorRoot, normalizeURL(url))
+}
+
+// VendoredSource returns the source of ref from a vendored repository
+// directory. commit is the commit the copy was taken at; it is recorded
+// on the source, not checked against the tree.
+func VendoredSource(ref *RemoteImport, commit, repoDir string) (*Source, error) {
+	dir := filepath.Join(repoDir, remotePackageSubdir(ref))
+	if !dirExists(dir) {
+		return nil, fmt.Errorf("%s is not vendored in %s", remoteImportID(ref), repoDir)
+	}
+	return sourceFromRemoteCache(ref, commit, repoDir)
+}
@@ -30,7 +30,7 @@ type preparedApplyCommand struct {
 type applyRunView interface {
 	URL() string
 	Observe(runtime.ApplyEvent)
-	Complete(bool, string)
+	Complete(ok bool, message string, checks []string)
 	WaitServed(time.Duration) bool
 	Close()
 }
@@ -267,12 +267,22 @@ func executeApplyText(
 		}
 	}
 	if view != nil {
-		view.Complete(err == nil, runViewMessage(err))
+		view.Complete(err == nil, runViewMessage(err), runViewChecks(err))
 	}
 	if err != nil {
 		if applyError, ok := errors.AsType[*runtime.ApplyError](err); ok {
 			renderApplyError(command.ErrOrStderr(), applyError, FormatText)
 		}
+		// A failed check comes with the result the apply recorded, so its
+		// outputs are still printed.
+		if result != nil {
+			if writeErr := writeApplyOutputs(
+				command.OutOrStdout(), FormatText, result.Outputs,
+				rootSensitiveOutputs(prepared.parsed),
+			); writeErr != nil {
+				return errors.Join(err, writeErr)
+			}
+		}
 		return err
 	}
 	return writeApplyOutputs(
@@ -435,7 +445,9 @@ func coordinateApplyMachine(
 ) (returnErr error) {
 	defer func() {
 		if view != nil {
-			view.Complete(returnErr == nil, runViewMessage(returnErr))
+			view.Complete(
+				returnErr == nil, runViewMessage(returnErr), runViewChecks(returnErr),
+			)
 		}
 	}()
 	requests := make(chan applyMachineRequest, len(prepared.plan.Steps)*3+16)
@@ -517,6 +529,11 @@ func coordinateApplyMachine(
 	failure := enrichApplyFailure(
 		runtimeOutcome.failure, controller.SignalCause(), prepared.store, stream,
 	)
+	if failure != nil && runtimeOutcome.result != nil {
+		// A failed check comes with the revision the apply wrote.
+		rev := runtimeOutcome.result.WrittenRev
+		stream.stateRev = &rev
+	}
 	if streamErr != nil {
 		return finishApplyEncodingOrWriteError(stream, prepared.store, streamErr, failure)
 	}
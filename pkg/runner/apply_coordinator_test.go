@@ -15,6 +15,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/cloudboss/unobin/internal/cmdout"
+	"github.com/cloudboss/unobin/pkg/lang"
 	"github.com/cloudboss/unobin/pkg/runtime"
 	"github.com/cloudboss/unobin/pkg/sdk/state"
 )
@@ -43,6 +44,7 @@ func TestApplyMachineCoordinatorGolden(t *testing.T) {
 		applyCoordinatorBrowserFailure(t, false),
 		applyCoordinatorBrowserFailure(t, true),
 		applyCoordinatorStepFailure(t),
+		applyCoordinatorCheckFailure(t),
 		applyCoordinatorInterruption(t),
 		applyCoordinatorRevisionFailure(t),
 		applyCoordinatorCancellationDrain(t),
@@ -139,6 +141,29 @@ func applyCoordinatorStepFailure(t *testing.T) applyCoordinatorCaseGolden {
 	}, false, nil)
 }
 
+func applyCoordinatorCheckFailure(t *testing.T) applyCoordinatorCaseGolden {
+	t.Helper()
+	view := &applyCoordinatorView{url: "http://127.0.0.1/run"}
+	return runApplyCoordinatorCase(t, "check failure", view, applyMachineOptions{
+		openBrowser: func(context.Context, string) error { return nil },
+		apply: func(
+			context.Context,
+			*runtime.Executor,
+			*runtime.PlanFile,
+		) (*runtime.ExecResult, error) {
+			return &runtime.ExecResult{
+				WrittenRev: "written-revision",
+				Outputs:    map[string]any{"id": "value"},
+			}, runtime.NewApplyFailure(
+				runtime.ApplyFailureCheck,
+				&runtime.CheckError{Failures: []lang.CheckFailure{
+					{Index: 1, Message: "id must be set"},
+				}},
+			)
+		},
+	}, false, nil)
+}
+
 func applyCoordinatorRevisionFailure(t *testing.T) applyCoordinatorCaseGolden {
 	t.Helper()
 	entry := runApplyCoordinatorCase(t, "revision read failure", nil, applyMachineOptions{
@@ -366,7 +391,7 @@ func (v *applyCoordinatorView) Observe(event runtime.ApplyEvent) {
 	v.events = append(v.events, string(event.Stage)+":"+event.Address)
 }
 
-func (v *applyCoordinatorView) Complete(ok bool, message string) {
+func (v *applyCoordinatorView) Complete(ok bool, message string, _ []string) {
 	v.mu.Lock()
 	defer v.mu.Unlock()
 	v.ok = ok
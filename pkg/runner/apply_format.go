@@ -115,6 +115,16 @@ type applyErrorRecord struct {
 	Library       string                  `json:"library,omitempty"   ub:"library,omitempty"`
 	Skipped       *int                    `json:"skipped,omitempty"   ub:"skipped,omitempty"`
 	Succeeded     *int                    `json:"succeeded,omitempty" ub:"succeeded,omitempty"`
+	Checks        []applyCheckRecord      `json:"checks,omitempty"    ub:"checks,omitempty"`
+}
+
+// applyCheckRecord is one failed factory check in an apply-error
+// record. Element names the iterated element for a check with
+// @for-each.
+type applyCheckRecord struct {
+	Index   int    `json:"index"             ub:"index"`
+	Element string `json:"element,omitempty" ub:"element,omitempty"`
+	Message string `json:"message"           ub:"message"`
 }
 
 type preparedApplyOutput struct {
@@ -324,7 +334,8 @@ func (s *applyStream) applyErrorRecord(
 	switch failure.Stage {
 	case runtime.ApplyFailureSetup,
 		runtime.ApplyFailureExecute,
-		runtime.ApplyFailureFinalize:
+		runtime.ApplyFailureFinalize,
+		runtime.ApplyFailureCheck:
 	default:
 		return applyErrorRecord{}, fmt.Errorf(
 			"apply stream: unsupported failure stage %q", failure.Stage,
@@ -337,6 +348,8 @@ func (s *applyStream) applyErrorRecord(
 	interrupted := errors.Is(failure.Cause, runtime.ErrInterrupted)
 	var step *runtime.ApplyError
 	hasStep := errors.As(failure.Cause, &step)
+	var checks *runtime.CheckError
+	hasChecks := errors.As(failure.Cause, &checks)
 	switch {
 	case interrupted:
 		record.Code = "unobin.apply.interrupted"
@@ -350,6 +363,9 @@ func (s *applyStream) applyErrorRecord(
 	case failure.Stage == runtime.ApplyFailureFinalize:
 		record.Code = "unobin.apply.finalize-failed"
 		record.Message = "apply finalization failed"
+	case failure.Stage == runtime.ApplyFailureCheck && hasChecks:
+		record.Code = "unobin.apply.check-failed"
+		record.Message = checks.Summary()
 	default:
 		return applyErrorRecord{}, fmt.Errorf(
 			"apply stream: execute failure has no step or interruption",
@@ -365,6 +381,13 @@ func (s *applyStream) applyErrorRecord(
 		record.Skipped = &skipped
 		record.Succeeded = &succeeded
 	}
+	if hasChecks {
+		for _, f := range checks.Failures {
+			record.Checks = append(record.Checks, applyCheckRecord{
+				Index: f.Index, Element: f.Element, Message: f.Message,
+			})
+		}
+	}
 	return record, nil
 }
 
@@ -14,6 +14,7 @@ import (
 
 	"github.com/cloudboss/unobin/internal/cmdout"
 	"github.com/cloudboss/unobin/pkg/diagnostic"
+	"github.com/cloudboss/unobin/pkg/lang"
 	"github.com/cloudboss/unobin/pkg/runtime"
 )
 
@@ -96,6 +97,16 @@ func writeApplyErrorStreams(t *testing.T, out io.Writer, format cmdout.Format) {
 			&runtime.StateUnlockError{Cause: errors.New("unlock failed")},
 		),
 	)))
+
+	checked := newApplyStream(out, format, applyTestClock())
+	checked.stateRev = &revision
+	require.NoError(t, checked.Error(runtime.NewApplyFailure(
+		runtime.ApplyFailureCheck,
+		&runtime.CheckError{Failures: []lang.CheckFailure{
+			{Index: 0, Message: "api must answer"},
+			{Index: 2, Element: "input.hosts[1]", Message: "host is down"},
+		}},
+	)))
 }
 
 type applyStreamStateGolden struct {
@@ -48,7 +48,11 @@ type Info struct {
 	LibraryConfigSchemas map[string]runtime.LibraryConfigSchema
 	AssetBundle          []byte
 	RootAssetSetID       string
-	options              *rootOptions
+	// Materials is the sbom.Manifest compile embedded, which `version
+	// --sbom` and `version --provenance` describe the binary with. Nil
+	// describes only what the Go build info records.
+	Materials []byte
+	options   *rootOptions
 
 	// UnobinVersion is the unobin version the factory was compiled
 	// against, stamped at link time the way FactoryVersion is. Run
@@ -271,6 +275,7 @@ func beginCommandResult(
 }
 
 func newVersionCmd(info Info) *cobra.Command {
+	var printSBOM, printProvenance bool
 	cmd := &cobra.Command{
 		Use:   "version",
 		Short: "Print factory identity",
@@ -288,6 +293,12 @@ func newVersionCmd(info Info) *cobra.Command {
 			if err := checkLinkedUnobin(cmd, info.UnobinVersion, format, collector); err != nil {
 				return err
 			}
+			if printSBOM {
+				return writeSBOM(cmd, info)
+			}
+			if printProvenance {
+				return writeProvenance(cmd, info)
+			}
 			if format == cmdout.FormatText {
 				_, err := fmt.Fprintf(
 					cmd.OutOrStdout(),
@@ -307,6 +318,11 @@ func newVersionCmd(info Info) *cobra.Command {
 		},
 	}
 	cmd.Flags().String("format", "text", cmdout.FormatHelp())
+	cmd.Flags().BoolVar(&printSBOM, "sbom", false,
+		"Print the factory's CycloneDX software bill of materials.")
+	cmd.Flags().BoolVar(&printProvenance, "provenance", false,
+		"Print an in-toto provenance statement for this binary.")
+	cmd.MarkFlagsMutuallyExclusive("format", "sbom", "provenance")
 	return cmd
 }
 
@@ -419,9 +435,26 @@ func runViewMessage(err error) string {
 	if _, ok := errors.AsType[*runtime.ApplyError](err); ok {
 		return ""
 	}
+	if checks, ok := errors.AsType[*runtime.CheckError](err); ok {
+		return checks.Summary()
+	}
 	return err.Error()
 }
 
+// runViewChecks returns the failed checks the run view lists under
+// its status line, or nil when err is not a check failure.
+func runViewChecks(err error) []string {
+	checks, ok := errors.AsType[*runtime.CheckError](err)
+	if !ok {
+		return nil
+	}
+	out := make([]string, 0, len(checks.Failures))
+	for _, f := range checks.Failures {
+		out = append(out, f.String())
+	}
+	return out
+}
+
 // writeApplyOutputs prints the final outputs in the requested
 // format. Text emits `name: value` lines; json and unobin emit one
 // apply-output envelope per name in alphabetical order. Names in
@@ -1104,20 +1137,32 @@ func buildInputs(
 	libs map[string]*runtime.Library,
 	libraryConfigSchemas map[string]runtime.LibraryConfigSchema,
 ) (map[string]any, error) {
-	decl := parsed.inputBlock()
-	constraints := parsed.constraints()
 	inputs, err := loadStackInputs(config, configPath)
 	if err != nil {
 		return nil, err
 	}
-	if err := fillMissingEnvInputs(inputs, decl); err != nil {
+	if err := fillMissingEnvInputs(inputs, parsed.inputBlock()); err != nil {
 		return nil, err
 	}
+	return ValidateInputs(parsed.syntaxBody, inputs, libs, libraryConfigSchemas)
+}
+
+// ValidateInputs checks inputs against the factory body's input
+// declarations and constraints, and returns them with declared defaults
+// filled in. It is the check a stack's inputs pass before a plan, for
+// callers that take inputs from somewhere other than a stack file.
+func ValidateInputs(
+	body *syntax.FactoryBody,
+	inputs map[string]any,
+	libs map[string]*runtime.Library,
+	libraryConfigSchemas map[string]runtime.LibraryConfigSchema,
+) (map[string]any, error) {
+	decl := syntaxInputBlock(body.Inputs)
 	validated, errs := lang.ValidateInputsWithLibraryConfigs(
 		decl,
 		inputs,
 		defaultEval,
-		libraryConfigInputResolver(parsed.syntaxBody, libs, libraryConfigSchemas),
+		libraryConfigInputResolver(body, libs, libraryConfigSchemas),
 	)
 	if errs.Len() > 0 {
 		return nil, errs.Err()
@@ -1125,13 +1170,13 @@ func buildInputs(
 	if err := checkLibraryConfigInputConstraints(
 		decl,
 		validated,
-		parsed.syntaxBody,
+		body,
 		libs,
 		libraryConfigSchemas,
 	); err != nil {
 		return nil, err
 	}
-	cerrs := lang.CheckConstraints(constraints, validated,
+	cerrs := lang.CheckConstraints(syntaxConstraints(body.Constraints), validated,
 		predicateEval(validated, libs), lang.DisplayRooted)
 	if cerrs.Len() > 0 {
 		return nil, cerrs.Err()
@@ -1365,6 +1410,7 @@ func envInputDeclType(decl *lang.ObjectLit) lang.TypeExpr {
 }
 
 func parseEnvValueAs(raw string, typ lang.TypeExpr) (any, error) {
+	typ = lang.ResolveNamed(typ)
 	if opt, ok := typ.(*lang.TypeOptional); ok {
 		if value, ok := parseEnvValueLiteral(raw); ok && value == nil {
 			return nil, nil
@@ -1409,6 +1455,8 @@ func parseEnvValueLiteral(raw string) (any, bool) {
 
 func envValueMatchesType(value any, typ lang.TypeExpr) bool {
 	switch tt := typ.(type) {
+	case *lang.TypeNamed:
+		return tt.Resolved == nil || envValueMatchesType(value, tt.Resolved)
 	case *lang.TypeOptional:
 		return value == nil || envValueMatchesType(value, tt.Elem)
 	case *lang.TypeAtomic:
@@ -1436,7 +1484,7 @@ func envValueMatchesType(value any, typ lang.TypeExpr) bool {
 	case *lang.TypeList, *lang.TypeTuple:
 		_, ok := value.([]any)
 		return ok
-	case *lang.TypeMap, *lang.TypeObject, *lang.TypeLibraryConfig:
+	case *lang.TypeMap, *lang.TypeObject, *lang.TypeLibraryConfig, *lang.TypeUnion:
 		_, ok := value.(map[string]any)
 		return ok
 	}
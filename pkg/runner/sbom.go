@@ -0,0 +1,74 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+
+	ufs "github.com/cloudboss/unobin/pkg/fs"
+	"github.com/cloudboss/unobin/pkg/sbom"
+	"github.com/spf13/cobra"
+)
+
+// executablePath is swapped by tests so provenance hashes a known file.
+var executablePath = os.Executable
+
+// describeBuild combines the embedded materials manifest with the
+// binary's own Go build info.
+func describeBuild(info Info) (sbom.Build, error) {
+	manifest := sbom.Manifest{
+		FormatVersion: sbom.ManifestFormatVersion,
+		Factory:       sbom.Factory{Name: info.FactoryName, LibraryPath: info.LibraryPath},
+	}
+	if len(info.Materials) > 0 {
+		var err error
+		manifest, err = sbom.DecodeManifest(info.Materials)
+		if err != nil {
+			return sbom.Build{}, err
+		}
+	}
+	build := sbom.Build{
+		Manifest:        manifest,
+		Version:         info.FactoryVersion,
+		ContentRevision: info.ContentRevision,
+	}
+	if bi, ok := readBuildInfo(); ok {
+		build.Info = bi
+	}
+	return build, nil
+}
+
+func writeSBOM(cmd *cobra.Command, info Info) error {
+	build, err := describeBuild(info)
+	if err != nil {
+		return err
+	}
+	out, err := sbom.CycloneDX(build)
+	if err != nil {
+		return err
+	}
+	_, err = cmd.OutOrStdout().Write(out)
+	return err
+}
+
+// writeProvenance writes a provenance statement whose subject is the
+// running executable.
+func writeProvenance(cmd *cobra.Command, info Info) error {
+	build, err := describeBuild(info)
+	if err != nil {
+		return err
+	}
+	path, err := executablePath()
+	if err != nil {
+		return err
+	}
+	sum, err := ufs.FileSHA256(path)
+	if err != nil {
+		return err
+	}
+	out, err := sbom.Provenance(build, []sbom.Subject{{Name: filepath.Base(path), SHA256: sum}})
+	if err != nil {
+		return err
+	}
+	_, err = cmd.OutOrStdout().Write(out)
+	return err
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/cloudboss/unobin/pkg/encrypters"
 	ufs "github.com/cloudboss/unobin/pkg/fs"
 	"github.com/cloudboss/unobin/pkg/lang"
+	"github.com/cloudboss/unobin/pkg/lang/syntax"
 	"github.com/spf13/cobra"
 )
 
@@ -23,6 +24,11 @@ type schemaInput struct {
 	Sensitive   bool    `json:"sensitive"   ub:"sensitive"`
 }
 
+type schemaType struct {
+	Name string `json:"name" ub:"name"`
+	Type string `json:"type" ub:"type"`
+}
+
 type schemaOutput struct {
 	Name        string `json:"name"        ub:"name"`
 	Description string `json:"description" ub:"description"`
@@ -34,6 +40,7 @@ type schemaResult struct {
 	FormatVersion int                     `json:"format-version" ub:"format-version"`
 	Factory       factoryIdentity         `json:"factory"        ub:"factory"`
 	Inputs        []schemaInput           `json:"inputs"         ub:"inputs"`
+	Types         []schemaType            `json:"types"          ub:"types"`
 	Outputs       []schemaOutput          `json:"outputs"        ub:"outputs"`
 	Diagnostics   []diagnostic.Diagnostic `json:"diagnostics"    ub:"diagnostics"`
 }
@@ -115,6 +122,7 @@ func buildSchemaResult(
 		FormatVersion: 1,
 		Factory:       factoryIdentityFor(info),
 		Inputs:        []schemaInput{},
+		Types:         []schemaType{},
 		Outputs:       []schemaOutput{},
 		Diagnostics:   diagnostic.Normalize(diagnostics),
 	}
@@ -139,6 +147,11 @@ func buildSchemaResult(
 		}
 		result.Inputs = append(result.Inputs, item)
 	}
+	types, err := inputSchemaTypes(parsed.inputs())
+	if err != nil {
+		return schemaResult{}, err
+	}
+	result.Types = append(result.Types, types...)
 	sensitiveOutputs := rootSensitiveOutputs(parsed)
 	for _, output := range parsed.outputs() {
 		result.Outputs = append(result.Outputs, schemaOutput{
@@ -150,6 +163,40 @@ func buildSchemaResult(
 	return result, nil
 }
 
+// inputSchemaTypes lists the named types the inputs' types refer to,
+// directly or through another named type, once each in the order they
+// are first written, so a reader can expand every name in an input's
+// type.
+func inputSchemaTypes(inputs []factoryInputDecl) ([]schemaType, error) {
+	var out []schemaType
+	seen := map[string]bool{}
+	var visit func(lang.TypeExpr) error
+	visit = func(t lang.TypeExpr) error {
+		for _, ref := range syntax.NamedTypes(t) {
+			name := ref.QualifiedName()
+			if ref.Resolved == nil || seen[name] {
+				continue
+			}
+			seen[name] = true
+			typeText, err := lang.FormatTypeExpr(ref.Resolved)
+			if err != nil {
+				return err
+			}
+			out = append(out, schemaType{Name: name, Type: typeText})
+			if err := visit(ref.Resolved); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, input := range inputs {
+		if err := visit(input.typeExpr); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
 func writeSchemaText(out io.Writer, result schemaResult) error {
 	var buf strings.Builder
 	if len(result.Inputs) == 0 {
@@ -170,6 +217,12 @@ func writeSchemaText(out io.Writer, result schemaResult) error {
 			buf.WriteByte('\n')
 		}
 	}
+	if len(result.Types) > 0 {
+		buf.WriteString("types:\n")
+		for _, typ := range result.Types {
+			fmt.Fprintf(&buf, "  %s: %s\n", typ.Name, indentSchemaExpr(typ.Type))
+		}
+	}
 	if len(result.Outputs) == 0 {
 		buf.WriteString("outputs: none\n")
 	} else {
@@ -252,6 +305,12 @@ func renderInputsTemplate(out io.Writer, parsed *parsedFactory) error {
 		if input.description != "" {
 			fmt.Fprintf(out, "# %s\n", input.description)
 		}
+		if union, ok := lang.ResolveNamed(input.typeExpr).(*lang.TypeUnion); ok {
+			fmt.Fprintln(out, "# one of:")
+			for _, variant := range union.Variants {
+				fmt.Fprintf(out, "#   %s\n", unionVariantExample(union, variant))
+			}
+		}
 		fmt.Fprintf(out, "%s: %s  # type: %s\n",
 			input.name,
 			placeholderForType(input.typeExpr),
@@ -262,7 +321,27 @@ func renderInputsTemplate(out io.Writer, parsed *parsedFactory) error {
 	return nil
 }
 
+// unionVariantExample renders an object literal for one variant of a
+// tagged union: the tag set to the variant name, plus a placeholder for
+// each required field.
+func unionVariantExample(union *lang.TypeUnion, variant *lang.TypeUnionVariant) string {
+	parts := []string{fmt.Sprintf("%s: '%s'", union.Tag, variant.Name)}
+	if obj, ok := lang.ResolveNamed(variant.Type).(*lang.TypeObject); ok {
+		for _, field := range obj.Fields {
+			typ, required := lang.RequiredFieldType(field)
+			if !required {
+				continue
+			}
+			parts = append(parts, fmt.Sprintf("%s: %s", field.Name, placeholderForType(typ)))
+		}
+	}
+	return "{ " + strings.Join(parts, ", ") + " }"
+}
+
 func placeholderForType(e lang.Expr) string {
+	if t, ok := e.(lang.TypeExpr); ok {
+		e = lang.ResolveNamed(t)
+	}
 	switch v := e.(type) {
 	case *lang.TypeAtomic:
 		switch v.Name {
@@ -277,6 +356,10 @@ func placeholderForType(e lang.Expr) string {
 		return "[]"
 	case *lang.TypeMap, *lang.TypeObject:
 		return "{}"
+	case *lang.TypeUnion:
+		if len(v.Variants) > 0 {
+			return unionVariantExample(v, v.Variants[0])
+		}
 	case *lang.Ident:
 		switch v.Name {
 		case "string":
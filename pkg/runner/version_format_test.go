@@ -4,10 +4,12 @@ import (
 	"bytes"
 	"encoding/json"
 	"os"
+	"path/filepath"
 	"runtime/debug"
 	"testing"
 
 	"github.com/cloudboss/unobin/internal/cmdout"
+	"github.com/cloudboss/unobin/pkg/sbom"
 	"github.com/cloudboss/unobin/pkg/toolchain"
 	"github.com/stretchr/testify/require"
 )
@@ -69,3 +71,88 @@ func TestVersionFormatBoundaryGolden(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, string(want), string(got))
 }
+
+func runVersionBillOfMaterials(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	previous := readBuildInfo
+	t.Cleanup(func() { readBuildInfo = previous })
+	readBuildInfo = func() (*debug.BuildInfo, bool) {
+		return &debug.BuildInfo{GoVersion: "go1.26.2", Deps: []*debug.Module{{
+			Path: toolchain.UnobinModulePath, Version: "v0.1.0", Sum: "h1:unobin=",
+		}}}, true
+	}
+	materials, err := sbom.Manifest{
+		FormatVersion: sbom.ManifestFormatVersion,
+		Factory:       sbom.Factory{Name: "factory"},
+		Projects: []sbom.Project{{
+			ID: "github.com/example/lib", Kind: "ub", Version: "v1.0.0",
+			Commit: "0123456789abcdef0123456789abcdef01234567", Hash: "sha256:abcd",
+		}},
+	}.Encode()
+	require.NoError(t, err)
+	root := newRootCmd(Info{
+		FactoryName: "factory", FactoryVersion: "v1.0.0",
+		ContentRevision: "012345abcdef", UnobinVersion: "v0.1.0", Materials: materials,
+	})
+	root.SetArgs(append([]string{"version"}, args...))
+	var stdout bytes.Buffer
+	root.SetOut(&stdout)
+	root.SetErr(&bytes.Buffer{})
+	err = root.Execute()
+	return stdout.String(), err
+}
+
+func TestVersionPrintsSBOM(t *testing.T) {
+	out, err := runVersionBillOfMaterials(t, "--sbom")
+	require.NoError(t, err)
+	var bom struct {
+		BOMFormat string `json:"bomFormat"`
+		Metadata  struct {
+			Component struct {
+				Name    string `json:"name"`
+				Version string `json:"version"`
+			} `json:"component"`
+		} `json:"metadata"`
+		Components []struct {
+			Name string `json:"name"`
+			PURL string `json:"purl"`
+		} `json:"components"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out), &bom))
+	require.Equal(t, "CycloneDX", bom.BOMFormat)
+	require.Equal(t, "factory", bom.Metadata.Component.Name)
+	require.Equal(t, "v1.0.0", bom.Metadata.Component.Version)
+	require.Len(t, bom.Components, 2)
+	require.Equal(t, "github.com/example/lib", bom.Components[0].Name)
+	require.Equal(t, "pkg:golang/github.com/cloudboss/unobin@v0.1.0", bom.Components[1].PURL)
+}
+
+func TestVersionPrintsProvenanceForExecutable(t *testing.T) {
+	previous := executablePath
+	t.Cleanup(func() { executablePath = previous })
+	path := filepath.Join(t.TempDir(), "factory")
+	require.NoError(t, os.WriteFile(path, nil, 0o755))
+	executablePath = func() (string, error) { return path, nil }
+
+	out, err := runVersionBillOfMaterials(t, "--provenance")
+	require.NoError(t, err)
+	var statement struct {
+		Type    string `json:"_type"`
+		Subject []struct {
+			Name   string            `json:"name"`
+			Digest map[string]string `json:"digest"`
+		} `json:"subject"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out), &statement))
+	require.Equal(t, "https://in-toto.io/Statement/v1", statement.Type)
+	require.Len(t, statement.Subject, 1)
+	require.Equal(t, "factory", statement.Subject[0].Name)
+	require.Equal(t,
+		"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+		statement.Subject[0].Digest["sha256"])
+}
+
+func TestVersionSBOMExcludesFormat(t *testing.T) {
+	_, err := runVersionBillOfMaterials(t, "--sbom", "--format", "json")
+	require.ErrorContains(t, err, "none of the others can be")
+}
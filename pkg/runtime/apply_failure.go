@@ -8,6 +8,7 @@ const (
 	ApplyFailureSetup    ApplyFailureStage = "setup"
 	ApplyFailureExecute  ApplyFailureStage = "execute"
 	ApplyFailureFinalize ApplyFailureStage = "finalize"
+	ApplyFailureCheck    ApplyFailureStage = "check"
 )
 
 type ApplyFailure struct {
@@ -19,6 +19,8 @@ import (
 // bodies come from the plan. The plan's stack identity must match the
 // Executor's, and the prior state's rev must match what the plan was
 // computed against. The stack's lock is held for the duration.
+// When a post-apply check fails, the result of the recorded apply is
+// returned along with the check failure.
 func (e *Executor) ApplyPlan(ctx context.Context, pf *PlanFile) (result *ExecResult, err error) {
 	if e.Store == nil {
 		return nil, NewApplyFailure(
@@ -144,15 +146,25 @@ func (e *Executor) ApplyPlan(ctx context.Context, pf *PlanFile) (result *ExecRes
 	if err != nil {
 		return nil, NewApplyFailure(ApplyFailureFinalize, err)
 	}
-	return &ExecResult{
+	result = &ExecResult{
 		Outputs:    rs.outputs,
 		Actions:    rs.eval.Actions,
 		Data:       rs.eval.Data,
 		WrittenRev: rev,
-	}, nil
+	}
+	// Checks run once the new state is written, so a failed check
+	// reports on a result that is already recorded and a later plan
+	// starts from it. The result comes back with the failure.
+	if !pf.Destroy {
+		if err := e.runChecks(rs); err != nil {
+			return result, NewApplyFailure(ApplyFailureCheck, err)
+		}
+	}
+	return result, nil
 }
 
 func (e *Executor) applyStep(ctx context.Context, rs *runState, step *PlanStep) error {
+	ctx = withStepAddress(ctx, step.Address)
 	// A node's @timeout bounds how long its step may run. The deadline is
 	// read from the DAG node, so an orphan destroy (whose source node is
 	// gone) is never bounded. On expiry the operation sees a cancelled
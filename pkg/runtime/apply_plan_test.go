@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/cloudboss/unobin/internal/ubtest"
+	"github.com/cloudboss/unobin/pkg/lang"
 	"github.com/cloudboss/unobin/pkg/sdk/cfg"
 	"github.com/cloudboss/unobin/pkg/sdk/state"
 	"github.com/cloudboss/unobin/pkg/state/local"
@@ -846,6 +847,35 @@ func TestApplyPlanForEachResource(t *testing.T) {
 	require.True(t, addrs["resource.many['beta']"])
 }
 
+func TestApplyPlanReportsFailedChecksAfterPersist(t *testing.T) {
+	src := applyPlanFixture(t, "apply-plan-checks")
+	var c resourceCounters
+	store := newStateStore(t)
+	stack := state.FactoryInfo{Name: "test-stack", Version: "v0", ContentRevision: "c0"}
+	exec := applyPlanTestExecutor(t, src, resourceModules(&c), store, stack)
+	exec.Inputs = map[string]any{"configs": map[string]any{"alpha": int64(1), "beta": int64(2)}}
+
+	res, err := planAndApply(exec)
+	failure, ok := errors.AsType[*ApplyFailure](err)
+	require.True(t, ok, "want *ApplyFailure, got %T: %v", err, err)
+	require.Equal(t, ApplyFailureCheck, failure.Stage)
+	require.NotNil(t, res, "a failed check keeps the recorded result")
+	require.NotEmpty(t, res.WrittenRev)
+	checks, ok := errors.AsType[*CheckError](err)
+	require.True(t, ok, "want *CheckError, got %T: %v", err, err)
+	require.Equal(t, []lang.CheckFailure{
+		{Index: 1, Message: "beta must reuse alpha"},
+		{Index: 2, Element: "resource.many['beta']", Message: "id mismatch"},
+	}, checks.Failures)
+
+	snap, err := store.Current()
+	require.NoError(t, err)
+	require.Len(t, snap.Entries, 2, "checks run after the applied state is written")
+	rev, err := store.CurrentRev()
+	require.NoError(t, err)
+	require.Equal(t, rev, res.WrittenRev)
+}
+
 func TestApplyPlanForEachAction(t *testing.T) {
 	src := applyPlanFixture(t, "apply-plan-for-each-action")
 	store := newStateStore(t)
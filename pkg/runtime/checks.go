@@ -0,0 +1,69 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cloudboss/unobin/pkg/lang"
+)
+
+// CheckError reports the factory's `checks:` entries that failed after
+// an apply. The apply itself finished and its state was written; the
+// failures say the result is not what the factory expects of it.
+type CheckError struct {
+	Failures []lang.CheckFailure
+}
+
+func (e *CheckError) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Summary())
+	for _, f := range e.Failures {
+		b.WriteString("\n  ")
+		b.WriteString(f.String())
+	}
+	return b.String()
+}
+
+// Summary counts the failures without listing them, like
+// `2 checks failed after apply`.
+func (e *CheckError) Summary() string {
+	noun := "checks"
+	if len(e.Failures) == 1 {
+		noun = "check"
+	}
+	return fmt.Sprintf("%d %s failed after apply", len(e.Failures), noun)
+}
+
+// Unwrap returns one error per failure, so each converts to its own
+// diagnostic.
+func (e *CheckError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Failures))
+	for _, f := range e.Failures {
+		errs = append(errs, errors.New(f.String()))
+	}
+	return errs
+}
+
+// runChecks evaluates the factory's checks against the finished run,
+// the same scope its outputs read, and returns a *CheckError when any
+// fails.
+func (e *Executor) runChecks(rs *runState) error {
+	if e.SyntaxSource == nil || len(e.SyntaxSource.Checks) == 0 {
+		return nil
+	}
+	block := &lang.ArrayLit{}
+	for _, decl := range e.SyntaxSource.Checks {
+		block.Elements = append(block.Elements, decl.Value)
+	}
+	failures := lang.RunChecks(lang.ReadChecks(block),
+		func(ex lang.Expr, binds []lang.EachBinding) (any, error) {
+			ctx := *rs.eval
+			ApplyBindings(&ctx, binds)
+			return Eval(ex, &ctx)
+		})
+	if len(failures) == 0 {
+		return nil
+	}
+	return &CheckError{Failures: failures}
+}
@@ -49,6 +49,17 @@ var coreRegistrations = []struct {
 			Result: typecheck.TString(),
 		}},
 	{"b64-decode", "Base64-decode a string.", fnB64Decode, nil},
+	{"render-template",
+		"Render a template's {{ }} slots against an object of variables.",
+		fnRenderTemplate,
+		&typecheck.FuncSig{
+			Params: []typecheck.Type{
+				typecheck.TUnion([]typecheck.Type{typecheck.TString(), typecheck.TBytes()}),
+				typecheck.TOpenObject(nil),
+			},
+			Result: typecheck.TString(),
+			Check:  typecheck.CheckTemplate,
+		}},
 	{"range", "Return the integers [0, n) as a list.", fnRange, nil},
 	{"length", "Return the number of elements in a string, list, or map.", fnLength,
 		&typecheck.FuncSig{
@@ -131,6 +142,12 @@ func CoreFunctionSigs() map[string]typecheck.FuncSig {
 	return coreSigs
 }
 
+// CoreFunction returns the registration of the @core function name.
+func CoreFunction(name string) (FunctionType, bool) {
+	fn, ok := coreFunctions[name]
+	return fn, ok
+}
+
 // sigFromFunc reads a registered function's reflected signature into
 // the form the inferrer checks calls against.
 func sigFromFunc(fn any) typecheck.FuncSig {
@@ -256,6 +273,47 @@ func fnB64Decode(s string) (string, error) {
 	return string(decoded), nil
 }
 
+// evalTemplate indirects evalInterpolated for fnRenderTemplate. A
+// template slot may call @core functions, so a direct reference would
+// make the registration list depend on itself.
+var evalTemplate func(*lang.InterpolatedString, *EvalContext) (any, error)
+
+func init() { evalTemplate = evalInterpolated }
+
+// fnRenderTemplate renders a template, a string or an asset's bytes,
+// whose {{ }} slots are interpolated-string slots evaluated against
+// vars. A slot reads only the variables: a name vars does not hold is
+// an error here, where elsewhere a bare name evaluates to itself.
+func fnRenderTemplate(template any, vars map[string]any) (string, error) {
+	var text []byte
+	switch typed := template.(type) {
+	case string:
+		text = []byte(typed)
+	case []byte:
+		text = typed
+	default:
+		return "", fmt.Errorf(
+			"render-template: template must be a string or bytes, got %s",
+			lang.TypeMessage(template),
+		)
+	}
+	tpl, err := lang.ParseTemplate("", text)
+	if err != nil {
+		return "", diagnostic.Context("render-template", err)
+	}
+	for _, id := range lang.FreeNames(tpl) {
+		if _, ok := vars[id.Name]; !ok {
+			return "", fmt.Errorf("render-template: %s: template variable %q is not set",
+				id.S.Start, id.Name)
+		}
+	}
+	out, err := evalTemplate(tpl, &EvalContext{Bindings: vars})
+	if err != nil {
+		return "", diagnostic.Context("render-template", err)
+	}
+	return out.(string), nil
+}
+
 // fnRange returns the integers [0, n) as a list. The result is a list,
 // so it is not a valid @for-each iterable; callers wanting fan-out write
 // a map literal with intentional keys.
@@ -507,3 +507,36 @@ func TestFunctionToBooleanErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestFunctionRenderTemplate(t *testing.T) {
+	tpl := []byte("listen {{ port }};\nname {{ name }}-{{ @core.join(tags, '.') }}\n" +
+		"workers {{ workers : %04d }} \\{{ kept }}\n")
+	got, err := fnRenderTemplate(tpl, map[string]any{
+		"port":    int64(8080),
+		"name":    "web",
+		"tags":    []any{"a", "b"},
+		"workers": int64(16),
+	})
+	require.NoError(t, err)
+	require.Equal(t, "listen 8080;\nname web-a.b\nworkers 0016 {{ kept }}\n", got)
+
+	evaluated, err := evalCore(t, "@core.render-template('hi {{ who }}', { who: input.name })",
+		map[string]any{"name": "there"})
+	require.NoError(t, err)
+	require.Equal(t, "hi there", evaluated)
+}
+
+func TestFunctionRenderTemplateErrors(t *testing.T) {
+	_, err := fnRenderTemplate("a\nb {{ host }}", map[string]any{})
+	require.EqualError(t, err, `render-template: 2:6: template variable "host" is not set`)
+
+	_, err = fnRenderTemplate("{{ n + }}", map[string]any{"n": int64(1)})
+	require.ErrorContains(t, err, "render-template: 1:7: parse: no match found")
+
+	_, err = fnRenderTemplate("{{ xs }}", map[string]any{"xs": []any{"a"}})
+	require.ErrorContains(t, err, "interpolation slot must be a scalar, got a list")
+
+	_, err = evalCore(t, "@core.render-template(1, {})", nil)
+	require.EqualError(t, err,
+		"render-template: template must be a string or bytes, got an integer")
+}
@@ -112,6 +112,14 @@ func TestCoreFunctionSet(t *testing.T) {
 			Params: []typecheck.Type{integer},
 			Result: typecheck.TList(integer),
 		},
+		"render-template": {
+			Params: []typecheck.Type{
+				typecheck.TUnion([]typecheck.Type{str, bytes}),
+				typecheck.TOpenObject(nil),
+			},
+			Result: str,
+			Check:  typecheck.CheckTemplate,
+		},
 		"to-boolean": {
 			Params: []typecheck.Type{typecheck.TUnion([]typecheck.Type{str, boolean})},
 			Result: boolean,
@@ -157,6 +165,30 @@ func requireSigEqual(t *testing.T, name string, want, got typecheck.FuncSig) {
 	require.True(t, want.Result.Equal(got.Result),
 		"%s result: want %s, got %s", name, want.Result, got.Result)
 	require.Equal(t, want.Infer == nil, got.Infer == nil, "%s: result hook presence", name)
+	require.Equal(t, want.Check == nil, got.Check == nil, "%s: argument hook presence", name)
+}
+
+func TestRenderTemplateUnionMatchesRuntime(t *testing.T) {
+	union := CoreFunctionSigs()["render-template"].Params[0]
+	cases := []struct {
+		name string
+		typ  typecheck.Type
+		val  any
+	}{
+		{"string", typecheck.TString(), "a"},
+		{"bytes", typecheck.TBytes(), []byte("a")},
+		{"integer", typecheck.TInteger(), int64(1)},
+		{"list", typecheck.TList(typecheck.TString()), []any{"a"}},
+		{"null", typecheck.TNull(), nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, rtErr := fnRenderTemplate(c.val, map[string]any{})
+			staticOK := typecheck.Assignable(union, c.typ)
+			require.Equal(t, rtErr == nil, staticOK,
+				"runtime and static faces disagree on %s", c.typ)
+		})
+	}
 }
 
 func TestB64EncodeUnionMatchesRuntime(t *testing.T) {
@@ -61,6 +61,11 @@ type EvalContext struct {
 	// child contexts so a comprehension inside the scope sees the
 	// same locals and reuses their memoized values.
 	locals *localScope
+
+	// functions holds a UB library's own functions while one of them
+	// is evaluated, so its body can call a sibling bare. Nil
+	// everywhere else, where a bare call is an error.
+	functions map[string]FunctionType
 }
 
 // withBindings returns a shallow copy of ctx whose Bindings merges the
@@ -366,8 +371,10 @@ func evalObject(o *lang.ObjectLit, ctx *EvalContext) (map[string]any, error) {
 }
 
 // evalCall evaluates a function call. A call is qualified by @core,
-// the language's own namespace, or by an imported library's alias; a
-// bare call has nothing to resolve against and is rejected.
+// the language's own namespace, or by an imported library's alias. A
+// bare call resolves only inside a UB library function, against its
+// siblings; anywhere else it has nothing to resolve against and is
+// rejected.
 func evalCall(c *lang.Call, ctx *EvalContext) (any, error) {
 	if c.Library != nil {
 		return evalLibraryCall(c, ctx)
@@ -376,6 +383,13 @@ func evalCall(c *lang.Call, ctx *EvalContext) (any, error) {
 	if c.Callee != nil {
 		name = c.Callee.Name
 	}
+	if fn, ok := ctx.functions[name]; ok {
+		args, err := evalArgs(name, c.Args, ctx)
+		if err != nil {
+			return nil, err
+		}
+		return fn.Func(args)
+	}
 	return nil, fmt.Errorf(
 		"eval: function %q must be qualified with %s or an imported library, e.g. %s.%s(...)",
 		name, lang.CoreNamespace, lang.CoreNamespace, name)
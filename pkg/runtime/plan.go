@@ -614,6 +614,7 @@ func (e *Executor) readDestroySteps(ctx context.Context, steps []*PlanStep) erro
 // needs no DAG node, so it works for an orphan whose source has been
 // removed as well as for a full teardown.
 func (e *Executor) readDestroyTarget(ctx context.Context, step *PlanStep) (bool, error) {
+	ctx = withStepAddress(ctx, step.Address)
 	alias, typeName, ok := stepBindingParts(step)
 	if !ok {
 		return false, fmt.Errorf("missing binding for %q", step.Address)
@@ -716,6 +717,7 @@ func (e *Executor) dataRegistration(n *Node) (DataSourceRegistration, error) {
 func (e *Executor) planOneInstance(
 	ctx context.Context, rs *runState, n *Node, scope *EvalContext, addr string,
 ) (*PlanStep, error) {
+	ctx = withStepAddress(ctx, addr)
 	switch n.Kind {
 	case NodeResource:
 		rt, err := e.resourceRegistration(n)
@@ -1429,6 +1431,7 @@ func (e *Executor) runPendingReads(ctx context.Context, rs *runState) error {
 		sem <- struct{}{}
 		wg.Go(func() {
 			defer func() { <-sem }()
+			ctx := withStepAddress(ctx, pr.step.Address)
 			pr.observed, pr.err = guard("reading this resource", true, func() (map[string]any, error) {
 				return e.readObserved(ctx, pr.rt, pr.alias, pr.cfg, pr.inputs, pr.priorOutputs)
 			})
@@ -124,6 +124,7 @@ func (e *Executor) refreshLeaf(
 	ctx context.Context,
 	ent *state.Entry,
 ) (*state.Entry, bool, error) {
+	ctx = withStepAddress(ctx, ent.Address)
 	alias, typeName, ok := entryBindingParts(ent)
 	if !ok {
 		return nil, false, fmt.Errorf("missing binding for resource %q", ent.Address)
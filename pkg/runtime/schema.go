@@ -22,6 +22,9 @@ type LibrarySchema struct {
 	// without declared types reads as all-Unknown, which counts
 	// arguments but checks no types.
 	Functions map[string]typecheck.FuncSig
+	// FunctionDescriptions maps a function name to the description its
+	// registration gives, for editors to show beside the signature.
+	FunctionDescriptions map[string]string
 
 	// Configuration describes the fields of the library's Configuration
 	// struct, keyed by kebab-case field name. Nil when the library
@@ -0,0 +1,21 @@
+package runtime
+
+import "context"
+
+type stepAddressKey struct{}
+
+// withStepAddress returns ctx carrying the address of the step whose
+// library call it is about to make.
+func withStepAddress(ctx context.Context, addr string) context.Context {
+	return context.WithValue(ctx, stepAddressKey{}, addr)
+}
+
+// StepAddress returns the address of the node a library call is made
+// for, such as `resource.app` or `resource.app['a']/resource.inner`. The
+// executor sets it on the context of every Create, Read, Update, Delete,
+// and Run it makes, so a registration shared by many nodes, such as a
+// test double, can tell them apart. It reports false outside such a call.
+func StepAddress(ctx context.Context) (string, bool) {
+	addr, ok := ctx.Value(stepAddressKey{}).(string)
+	return addr, ok
+}
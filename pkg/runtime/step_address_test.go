@@ -0,0 +1,85 @@
+package runtime
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudboss/unobin/internal/ubtest"
+	"github.com/cloudboss/unobin/pkg/sdk/state"
+)
+
+// namedResource records the step address its Create sees.
+type namedResource struct {
+	Name string
+
+	mu   *sync.Mutex
+	seen *[]string
+}
+
+func (r *namedResource) SchemaVersion() int { return 1 }
+
+func (r *namedResource) Create(ctx context.Context, _ any) (any, error) {
+	addr, _ := StepAddress(ctx)
+	r.mu.Lock()
+	*r.seen = append(*r.seen, addr)
+	r.mu.Unlock()
+	return map[string]any{"name": r.Name}, nil
+}
+
+func (r *namedResource) Read(_ context.Context, _, prior any) (any, error) {
+	if prior == nil {
+		return nil, ErrNotFound
+	}
+	return prior, nil
+}
+
+func (r *namedResource) Update(
+	_ context.Context, _ any, _ Prior[namedResource, any],
+) (any, error) {
+	return map[string]any{"name": r.Name}, nil
+}
+
+func (r *namedResource) Delete(_ context.Context, _, _ any) error { return nil }
+func (r *namedResource) ReplaceFields() []string                  { return nil }
+
+func TestStepAddressNamesEachInstance(t *testing.T) {
+	var mu sync.Mutex
+	var seen []string
+	libs := map[string]*Library{
+		"core": {
+			Name: "core",
+			Resources: map[string]ResourceRegistration{
+				"named": MakeResourceWith[namedResource, any, any](
+					func() *namedResource { return &namedResource{mu: &mu, seen: &seen} },
+				),
+			},
+		},
+	}
+	g, syntaxSource := syntaxDAGAndBody(t,
+		ubtest.ReadValidFixture(t, "testdata/ub/step-address", "for-each"), libs)
+
+	applyOnce(t, &Executor{
+		DAG:          g,
+		SyntaxSource: syntaxSource,
+		Libraries:    libs,
+		Inputs:       map[string]any{"names": map[string]any{"a": "first", "b": "second"}},
+		Store:        newStateStore(t),
+		Factory:      state.FactoryInfo{Name: "test-stack", Version: "v0", ContentRevision: "c0"},
+	})
+
+	slices.Sort(seen)
+	require.Equal(t, []string{
+		"resource.many['a']",
+		"resource.many['b']",
+		"resource.one",
+	}, seen)
+}
+
+func TestStepAddressOutsideLibraryCall(t *testing.T) {
+	_, ok := StepAddress(context.Background())
+	require.False(t, ok)
+}
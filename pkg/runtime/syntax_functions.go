@@ -0,0 +1,81 @@
+package runtime
+
+import (
+	"github.com/cloudboss/unobin/pkg/lang/syntax"
+	"github.com/cloudboss/unobin/pkg/typecheck"
+)
+
+// SyntaxFunctions builds the function table for a UB library's
+// `functions:` declarations. Each function evaluates its body with its
+// parameters bound by name and its siblings callable bare, and sees
+// nothing else: no inputs, nodes, locals, or other libraries. The
+// compiler rejects recursion, so evaluation always terminates.
+func SyntaxFunctions(decls []syntax.FunctionDecl) map[string]FunctionType {
+	if len(decls) == 0 {
+		return nil
+	}
+	table := make(map[string]FunctionType, len(decls))
+	for _, decl := range decls {
+		table[decl.Name.Name] = syntaxFunction(decl, table)
+	}
+	return table
+}
+
+func syntaxFunction(decl syntax.FunctionDecl, siblings map[string]FunctionType) FunctionType {
+	name := decl.Name.Name
+	description := ""
+	if decl.Description != nil {
+		description = decl.Description.Value
+	}
+	params := make([]string, len(decl.Params))
+	for i, p := range decl.Params {
+		params[i] = p.Name.Name
+	}
+	body := decl.Body
+	return FunctionType{
+		Name:        name,
+		Description: description,
+		ArgCount:    len(params),
+		Func: func(args []any) (any, error) {
+			if err := wantArgs(name, args, len(params), false); err != nil {
+				return nil, err
+			}
+			bindings := make(map[string]any, len(params))
+			for i, p := range params {
+				bindings[p] = args[i]
+			}
+			return Eval(body, &EvalContext{Bindings: bindings, functions: siblings})
+		},
+	}
+}
+
+// SyntaxFunctionSigs returns the declared signature of each function in
+// decls, keyed by name, for the library's schema.
+func SyntaxFunctionSigs(decls []syntax.FunctionDecl) map[string]typecheck.FuncSig {
+	if len(decls) == 0 {
+		return nil
+	}
+	out := make(map[string]typecheck.FuncSig, len(decls))
+	for _, decl := range decls {
+		out[decl.Name.Name] = SyntaxFunctionSig(decl)
+	}
+	return out
+}
+
+// SyntaxFunctionSig reads one function declaration's parameter and
+// return types into the form the inferrer checks calls against. A
+// missing type reads as Unknown.
+func SyntaxFunctionSig(decl syntax.FunctionDecl) typecheck.FuncSig {
+	sig := typecheck.FuncSig{Result: typecheck.TUnknown()}
+	if decl.Returns != nil {
+		sig.Result = typecheck.FromLang(decl.Returns)
+	}
+	for _, p := range decl.Params {
+		t := typecheck.TUnknown()
+		if p.Type != nil {
+			t = typecheck.FromLang(p.Type)
+		}
+		sig.Params = append(sig.Params, t)
+	}
+	return sig
+}
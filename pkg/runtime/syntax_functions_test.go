@@ -0,0 +1,53 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudboss/unobin/internal/ubtest"
+	"github.com/cloudboss/unobin/pkg/lang/syntax"
+	"github.com/cloudboss/unobin/pkg/typecheck"
+)
+
+func syntaxFunctionsFixture(t *testing.T, name string) []syntax.FunctionDecl {
+	t.Helper()
+	src := ubtest.ReadValidFixture(t, "testdata/ub/syntax-functions", name)
+	f, err := syntax.ParseSource("library.ub", []byte(src))
+	require.NoError(t, err)
+	require.NotNil(t, f.Library)
+	return f.Library.Functions
+}
+
+func TestSyntaxFunctionsCallSiblings(t *testing.T) {
+	lib := &Library{Functions: SyntaxFunctions(syntaxFunctionsFixture(t, "siblings"))}
+	ctx := &EvalContext{Libraries: map[string]*Library{"names": lib}}
+
+	got, err := Eval(parseValue(t, "names.bucket-names(['dev', 'prod'])"), ctx)
+	require.NoError(t, err)
+	require.Equal(t, []any{"dev-logs", "prod-logs"}, got)
+
+	got, err = Eval(parseValue(t, "names.bucket-count(['dev', 'prod'])"), ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), got)
+}
+
+func TestSyntaxFunctionsSeeOnlyTheirParameters(t *testing.T) {
+	fns := SyntaxFunctions(syntaxFunctionsFixture(t, "siblings"))
+
+	got, err := fns["prefix"].Func([]any{"dev"})
+	require.NoError(t, err)
+	require.Equal(t, "dev-", got)
+
+	_, err = fns["prefix"].Func(nil)
+	require.ErrorContains(t, err, "prefix: expected 1 argument, got 0")
+}
+
+func TestSyntaxFunctionSigs(t *testing.T) {
+	sigs := SyntaxFunctionSigs(syntaxFunctionsFixture(t, "siblings"))
+
+	sig := sigs["bucket-name"]
+	require.Equal(t, []typecheck.Type{typecheck.TString(), typecheck.TString()}, sig.Params)
+	require.Equal(t, typecheck.TString(), sig.Result)
+	require.Equal(t, typecheck.TList(typecheck.TString()), sigs["bucket-names"].Result)
+}
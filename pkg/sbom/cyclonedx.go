@@ -0,0 +1,140 @@
+package sbom
+
+import "strings"
+
+// CycloneDX renders b as a CycloneDX 1.5 JSON bill of materials. The
+// factory is the metadata component; UB projects, Go modules, and asset
+// files are its components, and the Go toolchain and unobin are the
+// tools that built it. The document carries no timestamp or serial
+// number, so the same binary always yields the same bytes.
+func CycloneDX(b Build) ([]byte, error) {
+	bom := cdxBOM{
+		BOMFormat:   "CycloneDX",
+		SpecVersion: "1.5",
+		Version:     1,
+		Metadata: cdxMetadata{
+			Tools: cdxTools{Components: []cdxComponent{}},
+			Component: cdxComponent{
+				Type:       "application",
+				BOMRef:     "factory",
+				Name:       b.Manifest.Factory.Name,
+				Version:    b.Version,
+				Properties: factoryProperties(b),
+			},
+		},
+		Components: []cdxComponent{},
+	}
+	if v := b.goVersion(); v != "" {
+		bom.Metadata.Tools.Components = append(bom.Metadata.Tools.Components, cdxComponent{
+			Type: "application", Name: "go", Version: v,
+		})
+	}
+	if v := b.unobinVersion(); v != "" {
+		bom.Metadata.Tools.Components = append(bom.Metadata.Tools.Components, cdxComponent{
+			Type: "application", Name: "unobin", Version: v,
+		})
+	}
+	for _, p := range b.Manifest.Projects {
+		component := cdxComponent{
+			Type:    "library",
+			BOMRef:  "project:" + p.ID + "@" + p.Version,
+			Name:    p.ID,
+			Version: p.Version,
+			Properties: []cdxProperty{
+				{Name: "unobin:project-kind", Value: p.Kind},
+				{Name: "unobin:commit", Value: p.Commit},
+			},
+		}
+		if digest, ok := sha256Hex(p.Hash); ok {
+			component.Hashes = []cdxHash{{Alg: "SHA-256", Content: digest}}
+		} else if p.Hash != "" {
+			component.Properties = append(component.Properties,
+				cdxProperty{Name: "unobin:hash", Value: p.Hash})
+		}
+		bom.Components = append(bom.Components, component)
+	}
+	for _, m := range b.modules() {
+		purl := "pkg:golang/" + m.Path
+		if m.Version != "" && m.Version != "(devel)" {
+			purl += "@" + m.Version
+		}
+		component := cdxComponent{
+			Type:    "library",
+			BOMRef:  purl,
+			Name:    m.Path,
+			Version: m.Version,
+			PURL:    purl,
+		}
+		if m.Sum != "" {
+			component.Properties = []cdxProperty{{Name: "unobin:go-sum", Value: m.Sum}}
+		}
+		bom.Components = append(bom.Components, component)
+	}
+	for _, a := range b.Manifest.Assets {
+		bom.Components = append(bom.Components, cdxComponent{
+			Type:   "file",
+			BOMRef: "asset:" + a.Set + "/" + a.file(),
+			Name:   a.file(),
+			Hashes: []cdxHash{{Alg: "SHA-256", Content: a.SHA256}},
+			Properties: []cdxProperty{
+				{Name: "unobin:asset-set", Value: a.Set},
+			},
+		})
+	}
+	return marshal(bom)
+}
+
+func factoryProperties(b Build) []cdxProperty {
+	var properties []cdxProperty
+	if b.ContentRevision != "" {
+		properties = append(properties,
+			cdxProperty{Name: "unobin:content-revision", Value: b.ContentRevision})
+	}
+	if b.Manifest.Factory.LibraryPath != "" {
+		properties = append(properties,
+			cdxProperty{Name: "unobin:library-path", Value: b.Manifest.Factory.LibraryPath})
+	}
+	for _, key := range []string{"GOOS", "GOARCH"} {
+		if v := b.setting(key); v != "" {
+			properties = append(properties, cdxProperty{Name: "unobin:" + strings.ToLower(key), Value: v})
+		}
+	}
+	return properties
+}
+
+type cdxBOM struct {
+	BOMFormat   string         `json:"bomFormat"`
+	SpecVersion string         `json:"specVersion"`
+	Version     int            `json:"version"`
+	Metadata    cdxMetadata    `json:"metadata"`
+	Components  []cdxComponent `json:"components"`
+}
+
+type cdxMetadata struct {
+	Tools     cdxTools     `json:"tools"`
+	Component cdxComponent `json:"component"`
+}
+
+type cdxTools struct {
+	Components []cdxComponent `json:"components"`
+}
+
+type cdxComponent struct {
+	Type       string        `json:"type"`
+	BOMRef     string        `json:"bom-ref,omitempty"`
+	Name       string        `json:"name"`
+	Version    string        `json:"version,omitempty"`
+	PURL       string        `json:"purl,omitempty"`
+	Hashes     []cdxHash     `json:"hashes,omitempty"`
+	Properties []cdxProperty `json:"properties,omitempty"`
+}
+
+type cdxHash struct {
+	Alg     string `json:"alg"`
+	Content string `json:"content"`
+}
+
+type cdxProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
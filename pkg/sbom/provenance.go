@@ -0,0 +1,127 @@
+package sbom
+
+import "strings"
+
+// BuildType identifies the build a provenance statement describes.
+const BuildType = "https://github.com/cloudboss/unobin/compile/v1"
+
+// builderID identifies unobin compile as the builder.
+const builderID = "https://github.com/cloudboss/unobin"
+
+// Subject is a built artifact a provenance statement is about.
+type Subject struct {
+	Name   string
+	SHA256 string
+}
+
+// Provenance renders b as an in-toto v1 statement with a SLSA v1
+// provenance predicate about subjects. Every material of the SBOM is a
+// resolved dependency, and the statement carries no timestamps, so the
+// same binary always yields the same bytes.
+func Provenance(b Build, subjects []Subject) ([]byte, error) {
+	statement := intotoStatement{
+		Type:          "https://in-toto.io/Statement/v1",
+		Subject:       make([]resourceDescriptor, 0, len(subjects)),
+		PredicateType: "https://slsa.dev/provenance/v1",
+		Predicate: slsaProvenance{
+			BuildDefinition: slsaBuildDefinition{
+				BuildType: BuildType,
+				ExternalParameters: map[string]string{
+					"factory": b.Manifest.Factory.Name,
+					"version": b.Version,
+				},
+				InternalParameters:   map[string]string{},
+				ResolvedDependencies: []resourceDescriptor{},
+			},
+			RunDetails: slsaRunDetails{
+				Builder: slsaBuilder{ID: builderID, Version: map[string]string{}},
+			},
+		},
+	}
+	for _, s := range subjects {
+		statement.Subject = append(statement.Subject, resourceDescriptor{
+			Name: s.Name, Digest: map[string]string{"sha256": s.SHA256},
+		})
+	}
+	definition := &statement.Predicate.BuildDefinition
+	if path := b.Manifest.Factory.LibraryPath; path != "" {
+		definition.ExternalParameters["library-path"] = path
+	}
+	if b.ContentRevision != "" {
+		definition.InternalParameters["content-revision"] = b.ContentRevision
+	}
+	for _, key := range []string{"GOOS", "GOARCH"} {
+		if v := b.setting(key); v != "" {
+			definition.InternalParameters[strings.ToLower(key)] = v
+		}
+	}
+	for _, p := range b.Manifest.Projects {
+		repo, _, _ := strings.Cut(p.ID, "//")
+		dep := resourceDescriptor{
+			Name:   p.ID,
+			URI:    "git+https://" + repo + "@" + p.Version,
+			Digest: map[string]string{"gitCommit": p.Commit},
+		}
+		if digest, ok := sha256Hex(p.Hash); ok {
+			dep.Digest["sha256"] = digest
+		}
+		definition.ResolvedDependencies = append(definition.ResolvedDependencies, dep)
+	}
+	for _, m := range b.modules() {
+		dep := resourceDescriptor{Name: m.Path, URI: "pkg:golang/" + m.Path + "@" + m.Version}
+		if m.Sum != "" {
+			dep.Annotations = map[string]string{"go-sum": m.Sum}
+		}
+		definition.ResolvedDependencies = append(definition.ResolvedDependencies, dep)
+	}
+	for _, a := range b.Manifest.Assets {
+		definition.ResolvedDependencies = append(definition.ResolvedDependencies,
+			resourceDescriptor{
+				Name:   "asset:" + a.Set + "/" + a.file(),
+				Digest: map[string]string{"sha256": a.SHA256},
+			})
+	}
+	builder := &statement.Predicate.RunDetails.Builder
+	if v := b.unobinVersion(); v != "" {
+		builder.Version["unobin"] = v
+	}
+	if v := b.goVersion(); v != "" {
+		builder.Version["go"] = v
+	}
+	return marshal(statement)
+}
+
+type intotoStatement struct {
+	Type          string               `json:"_type"`
+	Subject       []resourceDescriptor `json:"subject"`
+	PredicateType string               `json:"predicateType"`
+	Predicate     slsaProvenance       `json:"predicate"`
+}
+
+type resourceDescriptor struct {
+	Name        string            `json:"name,omitempty"`
+	URI         string            `json:"uri,omitempty"`
+	Digest      map[string]string `json:"digest,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type slsaProvenance struct {
+	BuildDefinition slsaBuildDefinition `json:"buildDefinition"`
+	RunDetails      slsaRunDetails      `json:"runDetails"`
+}
+
+type slsaBuildDefinition struct {
+	BuildType            string               `json:"buildType"`
+	ExternalParameters   map[string]string    `json:"externalParameters"`
+	InternalParameters   map[string]string    `json:"internalParameters"`
+	ResolvedDependencies []resourceDescriptor `json:"resolvedDependencies"`
+}
+
+type slsaRunDetails struct {
+	Builder slsaBuilder `json:"builder"`
+}
+
+type slsaBuilder struct {
+	ID      string            `json:"id"`
+	Version map[string]string `json:"version"`
+}
@@ -0,0 +1,177 @@
+// Package sbom describes what a compiled factory is built from. Compile
+// embeds a Manifest of the materials only it knows about, the UB
+// projects of project-lock.ub and the captured assets, and the Go build
+// info every binary carries supplies the Go modules and toolchain. From
+// the two, CycloneDX renders a software bill of materials and Provenance
+// an in-toto statement, the same way for `unobin compile` and for a
+// factory's `version --sbom`.
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime/debug"
+	"strings"
+
+	"github.com/cloudboss/unobin/pkg/toolchain"
+)
+
+// ManifestFile is the manifest compile writes beside main.go and embeds
+// in the factory binary.
+const ManifestFile = "factory.materials.json"
+
+// ManifestFormatVersion is the Manifest schema version.
+const ManifestFormatVersion = 1
+
+// Manifest is the part of a factory's materials its Go build info does
+// not record.
+type Manifest struct {
+	FormatVersion int       `json:"format-version"`
+	Factory       Factory   `json:"factory"`
+	Projects      []Project `json:"projects"`
+	Assets        []Asset   `json:"assets"`
+}
+
+// Factory identifies the factory a manifest was compiled for. Its
+// version and content revision are stamped at link time, so they are
+// not part of the manifest.
+type Factory struct {
+	Name        string `json:"name"`
+	LibraryPath string `json:"library-path,omitempty"`
+}
+
+// Project is one dependency selected in project-lock.ub. Hash, an
+// algorithm-prefixed content hash, is set for UB projects only.
+type Project struct {
+	ID      string `json:"id"`
+	Kind    string `json:"kind"`
+	Version string `json:"version"`
+	Commit  string `json:"commit"`
+	Hash    string `json:"hash,omitempty"`
+}
+
+// Asset is one captured file of the factory's asset bundle. Path is the
+// file's path inside a directory asset and empty for a file asset.
+type Asset struct {
+	Set    string `json:"set"`
+	Name   string `json:"name"`
+	Path   string `json:"path,omitempty"`
+	SHA256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+}
+
+// file returns the asset file's name within its set.
+func (a Asset) file() string {
+	if a.Path == "" {
+		return a.Name
+	}
+	return a.Name + "/" + a.Path
+}
+
+// Encode returns m as the indented JSON compile writes to ManifestFile.
+func (m Manifest) Encode() ([]byte, error) {
+	if m.Projects == nil {
+		m.Projects = []Project{}
+	}
+	if m.Assets == nil {
+		m.Assets = []Asset{}
+	}
+	out, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+// DecodeManifest parses an embedded manifest.
+func DecodeManifest(body []byte) (Manifest, error) {
+	var m Manifest
+	if err := json.Unmarshal(body, &m); err != nil {
+		return Manifest{}, fmt.Errorf("materials manifest: %w", err)
+	}
+	if m.FormatVersion != ManifestFormatVersion {
+		return Manifest{}, fmt.Errorf(
+			"materials manifest: format version %d is not supported", m.FormatVersion)
+	}
+	return m, nil
+}
+
+// Build is everything known about one factory binary.
+type Build struct {
+	Manifest        Manifest
+	Version         string
+	ContentRevision string
+	// Info is the binary's Go build info, from debug.ReadBuildInfo in
+	// the factory itself or debug/buildinfo.ReadFile for a built file.
+	// Nil leaves out the Go modules and toolchain.
+	Info *debug.BuildInfo
+}
+
+// setting returns the build setting named key, or "".
+func (b Build) setting(key string) string {
+	if b.Info == nil {
+		return ""
+	}
+	for _, s := range b.Info.Settings {
+		if s.Key == key {
+			return s.Value
+		}
+	}
+	return ""
+}
+
+// modules returns the Go modules linked into the binary, with any
+// replacement applied.
+func (b Build) modules() []*debug.Module {
+	if b.Info == nil {
+		return nil
+	}
+	modules := make([]*debug.Module, 0, len(b.Info.Deps))
+	for _, dep := range b.Info.Deps {
+		if dep.Replace != nil {
+			dep = dep.Replace
+		}
+		modules = append(modules, dep)
+	}
+	return modules
+}
+
+// unobinVersion returns the version of the unobin module the binary
+// links, or "".
+func (b Build) unobinVersion() string {
+	if b.Info == nil {
+		return ""
+	}
+	for _, dep := range b.Info.Deps {
+		if dep.Path == toolchain.UnobinModulePath {
+			return dep.Version
+		}
+	}
+	return ""
+}
+
+// goVersion returns the Go toolchain version without its `go` prefix.
+func (b Build) goVersion() string {
+	if b.Info == nil {
+		return ""
+	}
+	return strings.TrimPrefix(b.Info.GoVersion, "go")
+}
+
+// sha256Hex returns the hex digest of an algorithm-prefixed hash when
+// the algorithm is SHA-256.
+func sha256Hex(hash string) (string, bool) {
+	algorithm, digest, ok := strings.Cut(hash, ":")
+	if !ok || algorithm != "sha256" || digest == "" {
+		return "", false
+	}
+	return digest, true
+}
+
+func marshal(v any) ([]byte, error) {
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
@@ -0,0 +1,109 @@
+package sbom
+
+import (
+	"os"
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testBuild() Build {
+	return Build{
+		Manifest: Manifest{
+			FormatVersion: ManifestFormatVersion,
+			Factory:       Factory{Name: "demo", LibraryPath: "example.com/demo"},
+			Projects: []Project{
+				{
+					ID: "github.com/example/net//lib", Kind: "ub", Version: "v1.2.0",
+					Commit: "0123456789abcdef0123456789abcdef01234567",
+					Hash:   "sha256:7d865e959b2466918c9863afca942d0fb89d7c9ac0c99bafc3749504ded97730",
+				},
+				{
+					ID: "github.com/example/unobin-library-std", Kind: "go", Version: "v0.2.3",
+					Commit: "08a835b5aa58844c5dccc7b1ddd4329737bbed27",
+				},
+			},
+			Assets: []Asset{
+				{
+					Set: "247fd93c", Name: "message",
+					SHA256: "d7d53aa54d2a4cbfc6c34afa0ea66a3b04bd6849c665e7f69ce642f6fca6b2c8", Size: 15,
+				},
+				{
+					Set: "247fd93c", Name: "site", Path: "index.html",
+					SHA256: "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae", Size: 3,
+				},
+			},
+		},
+		Version:         "v1.0.0",
+		ContentRevision: "abc123def456",
+		Info: &debug.BuildInfo{
+			GoVersion: "go1.26.2",
+			Path:      "demo",
+			Main:      debug.Module{Path: "demo", Version: "(devel)"},
+			Deps: []*debug.Module{
+				{Path: "github.com/cloudboss/unobin", Version: "v0.4.0", Sum: "h1:unobin="},
+				{Path: "github.com/example/unobin-library-std", Version: "v0.2.3", Sum: "h1:std="},
+				{
+					Path: "github.com/example/patched", Version: "v1.0.0",
+					Replace: &debug.Module{
+						Path: "github.com/fork/patched", Version: "v1.0.1", Sum: "h1:fork=",
+					},
+				},
+			},
+			Settings: []debug.BuildSetting{
+				{Key: "GOARCH", Value: "arm64"},
+				{Key: "GOOS", Value: "darwin"},
+			},
+		},
+	}
+}
+
+func TestCycloneDXGolden(t *testing.T) {
+	got, err := CycloneDX(testBuild())
+	require.NoError(t, err)
+	want, err := os.ReadFile("testdata/cyclonedx.json")
+	require.NoError(t, err)
+	require.Equal(t, string(want), string(got))
+}
+
+func TestProvenanceGolden(t *testing.T) {
+	got, err := Provenance(testBuild(), []Subject{{
+		Name:   "demo-darwin-arm64",
+		SHA256: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+	}})
+	require.NoError(t, err)
+	want, err := os.ReadFile("testdata/provenance.json")
+	require.NoError(t, err)
+	require.Equal(t, string(want), string(got))
+}
+
+func TestWithoutBuildInfo(t *testing.T) {
+	build := testBuild()
+	build.Info = nil
+	got, err := CycloneDX(build)
+	require.NoError(t, err)
+	require.NotContains(t, string(got), "pkg:golang/")
+	require.NotContains(t, string(got), `"name": "go"`)
+	require.Contains(t, string(got), "project:github.com/example/net//lib@v1.2.0")
+}
+
+func TestManifestRoundTrip(t *testing.T) {
+	manifest := testBuild().Manifest
+	body, err := manifest.Encode()
+	require.NoError(t, err)
+	decoded, err := DecodeManifest(body)
+	require.NoError(t, err)
+	require.Equal(t, manifest, decoded)
+
+	empty, err := Manifest{FormatVersion: ManifestFormatVersion}.Encode()
+	require.NoError(t, err)
+	require.Contains(t, string(empty), `"projects": []`)
+}
+
+func TestDecodeManifestRejectsOtherVersions(t *testing.T) {
+	_, err := DecodeManifest([]byte(`{"format-version": 2}`))
+	require.EqualError(t, err, "materials manifest: format version 2 is not supported")
+	_, err = DecodeManifest([]byte(`{`))
+	require.ErrorContains(t, err, "materials manifest:")
+}
@@ -0,0 +1,79 @@
+package sourcecheck
+
+import (
+	"github.com/cloudboss/unobin/pkg/lang/syntax"
+	"github.com/cloudboss/unobin/pkg/resolve"
+)
+
+// Cache keeps UB libraries and their check results between source checks.
+// Parsed libraries live in a resolve.Cache. A composite body that checks
+// clean is remembered with the fingerprint of its library, and later
+// checks skip its body-level checks until that fingerprint changes,
+// which happens when the library or anything it imports changes. Go
+// library schemas and asset files are not part of a fingerprint, so
+// callers replace the cache when those change.
+//
+// A Cache is not safe for concurrent use.
+type Cache struct {
+	libraries *resolve.Cache
+	clean     map[compositeID]string
+}
+
+// compositeID names one composite of one UB library.
+type compositeID struct {
+	library string
+	kind    string
+	name    string
+}
+
+// compositeBody is a composite body reached by an import analysis, with
+// the fingerprint of the library it was read from.
+type compositeBody struct {
+	id          compositeID
+	fingerprint string
+}
+
+// NewCache returns an empty cache.
+func NewCache() *Cache {
+	return &Cache{
+		libraries: resolve.NewCache(),
+		clean:     map[compositeID]string{},
+	}
+}
+
+// Libraries returns the cache of parsed UB libraries. A nil cache has
+// none.
+func (c *Cache) Libraries() *resolve.Cache {
+	if c == nil {
+		return nil
+	}
+	return c.libraries
+}
+
+// checked reports the bodies in bodies that checked clean at their
+// current fingerprint.
+func (c *Cache) checked(
+	bodies map[*syntax.FactoryBody]compositeBody,
+) func(*syntax.FactoryBody) bool {
+	return func(body *syntax.FactoryBody) bool {
+		composite, ok := bodies[body]
+		if !ok {
+			return false
+		}
+		fingerprint, ok := c.clean[composite.id]
+		return ok && fingerprint == composite.fingerprint
+	}
+}
+
+// storeClean records every body in checked as clean at its current
+// fingerprint.
+func (c *Cache) storeClean(
+	bodies map[*syntax.FactoryBody]compositeBody,
+	checked []*syntax.FactoryBody,
+) {
+	for _, body := range checked {
+		if composite, ok := bodies[body]; ok {
+			c.clean[composite.id] = composite.fingerprint
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package sourcecheck
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudboss/unobin/pkg/resolve"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckFactoryCacheReusesUnchangedLibraries(t *testing.T) {
+	root := copyCacheFixture(t)
+	factoryDir := filepath.Join(root, "factory")
+	body := parseFactoryAt(t, filepath.Join(factoryDir, "factory.ub"))
+	cache := NewCache()
+	opts := cacheCheckOptions(t, factoryDir, cache)
+
+	first, err := AnalyzeImports(factoryImportRefs(t, factoryDir), cacheAnalysisOptions(opts))
+	require.NoError(t, err)
+	_, err = CheckFactoryBody(body, opts)
+	require.NoError(t, err)
+	second, err := AnalyzeImports(factoryImportRefs(t, factoryDir), cacheAnalysisOptions(opts))
+	require.NoError(t, err)
+
+	require.Same(t,
+		first.Libraries["outer"].ResourceComposites["wrapper"].SyntaxBody.Resources[0].Body,
+		second.Libraries["outer"].ResourceComposites["wrapper"].SyntaxBody.Resources[0].Body,
+		"an unchanged library should not be parsed again")
+}
+
+func TestCheckFactoryCacheInvalidatesImporters(t *testing.T) {
+	root := copyCacheFixture(t)
+	factoryDir := filepath.Join(root, "factory")
+	body := parseFactoryAt(t, filepath.Join(factoryDir, "factory.ub"))
+	cache := NewCache()
+
+	_, err := CheckFactoryBody(body, cacheCheckOptions(t, factoryDir, cache))
+	require.NoError(t, err)
+
+	copyImportAnalysisFixture(t,
+		"valid/cache-invalidation/inner-changed/library.ub",
+		filepath.Join(root, "inner", "library.ub"))
+	_, cachedErr := CheckFactoryBody(body, cacheCheckOptions(t, factoryDir, cache))
+	_, uncachedErr := CheckFactoryBody(body, cacheCheckOptions(t, factoryDir, nil))
+
+	require.Error(t, uncachedErr)
+	require.EqualError(t, cachedErr, uncachedErr.Error(),
+		"a changed import should invalidate the libraries that use it")
+}
+
+func copyCacheFixture(t testing.TB) string {
+	t.Helper()
+	root := t.TempDir()
+	for _, dir := range []string{"factory", "outer", "inner"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, dir), 0o755))
+	}
+	copyImportAnalysisFixture(t,
+		"valid/cache-invalidation/factory/factory.ub",
+		filepath.Join(root, "factory", "factory.ub"))
+	copyImportAnalysisFixture(t,
+		"valid/cache-invalidation/outer/library.ub",
+		filepath.Join(root, "outer", "library.ub"))
+	copyImportAnalysisFixture(t,
+		"valid/cache-invalidation/inner/library.ub",
+		filepath.Join(root, "inner", "library.ub"))
+	return root
+}
+
+func cacheCheckOptions(t testing.TB, factoryDir string, cache *Cache) Options {
+	t.Helper()
+	return Options{
+		ProjectDir:  factoryDir,
+		Source:      &resolve.Source{FS: os.DirFS(factoryDir), Path: factoryDir},
+		Resolver:    newTestResolver(t, factoryDir),
+		SchemaCache: NewSchemaCache(),
+		Cache:       cache,
+	}
+}
+
+func cacheAnalysisOptions(opts Options) ImportAnalysisOptions {
+	return ImportAnalysisOptions{
+		ProjectDir:  opts.ProjectDir,
+		Source:      opts.Source,
+		Resolver:    opts.Resolver,
+		SchemaCache: opts.SchemaCache,
+		Cache:       opts.Cache,
+	}
+}
+
+func factoryImportRefs(t testing.TB, factoryDir string) map[string]resolve.ImportRef {
+	t.Helper()
+	body := parseFactoryAt(t, filepath.Join(factoryDir, "factory.ub"))
+	refs, errs := resolve.ExtractSyntaxBodyImports(body)
+	require.Empty(t, errs)
+	return refs
+}
@@ -8,6 +8,7 @@ import (
 	"slices"
 
 	"github.com/cloudboss/unobin/pkg/asset"
+	"github.com/cloudboss/unobin/pkg/check"
 	"github.com/cloudboss/unobin/pkg/codegen"
 	"github.com/cloudboss/unobin/pkg/deps"
 	"github.com/cloudboss/unobin/pkg/diagnostic"
@@ -29,6 +30,8 @@ type ImportAnalysis struct {
 	UBPackages           map[string][]byte
 	Assets               *asset.Collection
 	RootAssetSetID       string
+
+	compositeBodies map[*syntax.FactoryBody]compositeBody
 }
 
 // ImportAnalysisOptions configures AnalyzeImports.
@@ -38,6 +41,7 @@ type ImportAnalysisOptions struct {
 	Resolver                resolve.Resolver
 	Versions                map[string]string
 	SchemaCache             *SchemaCache
+	Cache                   *Cache
 	Reporter                diagnostic.Reporter
 	Mode                    Mode
 	StackName               string
@@ -71,11 +75,17 @@ func AnalyzeImports(
 	visitorOpts := opts
 	visitorOpts.Resolver = resolver
 	visitor := newImportVisitor(visitorOpts, schemas)
-	top, err := resolve.WalkUBFrom(refs, resolver, visitor, opts.Versions,
-		importSourceForOptions(opts))
+	top, err := resolve.WalkUBFromCache(refs, resolver, visitor, opts.Versions,
+		importSourceForOptions(opts), opts.Cache.Libraries())
 	if err != nil {
 		return nil, err
 	}
+	if opts.Body != nil {
+		err := resolve.BindImportedTypes(syntax.BodyTypes(*opts.Body), top, visitor.ubLibraries)
+		if err != nil {
+			return nil, err
+		}
+	}
 	rootSet, err := asset.Capture(
 		opts.Source,
 		opts.RootSourceFile,
@@ -97,6 +107,7 @@ func AnalyzeImports(
 		UBImports:            map[string]string{},
 		UBPackages:           visitor.packages,
 		Assets:               visitor.assets,
+		compositeBodies:      visitor.compositeBodies,
 	}
 	if rootSet != nil {
 		analysis.RootAssetSetID = rootSet.ID
@@ -178,8 +189,11 @@ type importVisitor struct {
 	packages                map[string][]byte
 	goModules               map[string]string
 	runtimeLibraries        map[string]*runtime.Library
+	ubLibraries             map[string]*resolve.UBLibrary
+	compositeBodies         map[*syntax.FactoryBody]compositeBody
 	reporter                diagnostic.Reporter
 	schemas                 *SchemaCache
+	libraries               *resolve.Cache
 	assets                  *asset.Collection
 	generatedOutputPath     string
 }
@@ -207,8 +221,11 @@ func newImportVisitor(opts ImportAnalysisOptions, schemas *SchemaCache) *importV
 		packages:                packages,
 		goModules:               map[string]string{},
 		runtimeLibraries:        map[string]*runtime.Library{},
+		ubLibraries:             map[string]*resolve.UBLibrary{},
+		compositeBodies:         map[*syntax.FactoryBody]compositeBody{},
 		reporter:                opts.Reporter,
 		schemas:                 schemas,
+		libraries:               opts.Cache.Libraries(),
 		assets:                  &asset.Collection{},
 		generatedOutputPath:     opts.GeneratedOutputPath,
 	}
@@ -291,7 +308,7 @@ func (v *importVisitor) resolveLibraryConfigDep(
 			return runtime.LibraryConfigSchema{}, err
 		}
 	}
-	classification := resolve.ClassifySource(source)
+	classification := v.libraries.ClassifySource(source)
 	if classification.Kind != resolve.SourceGoLibrary {
 		return runtime.LibraryConfigSchema{}, libraryConfigSourceError(dep.Path, classification)
 	}
@@ -350,6 +367,10 @@ func (v *importVisitor) OnUBLibrary(
 		}
 	}
 
+	if errs := check.Functions(lib.Functions); errs.Len() > 0 {
+		return errs.Err()
+	}
+
 	packageID := ""
 	if v.generatePackages {
 		packageID = v.packageIDs.ID(alias, canonicalKey)
@@ -359,26 +380,57 @@ func (v *importVisitor) OnUBLibrary(
 		return err
 	}
 	runtimeLib := runtimeLibraryForCompiledComposites(alias, composites)
+	addRuntimeLibraryFunctions(runtimeLib, lib.Functions)
+	v.recordCompositeBodies(canonicalKey, lib.Fingerprint, runtimeLib)
 	if v.generatePackages {
-		src, err := codegen.GenerateUBLibraryPackageWithAssetsAndConfigSchemas(
-			packageID,
-			alias,
-			syntaxBodiesForCompiledComposites(composites),
-			codegenImportsForCompiledComposites(composites),
-			goSpecsForCompiledComposites(composites),
-			lib.SourceFiles,
-			assetSetIDsForCompiledComposites(composites),
-			libraryConfigSchemasForCompiledComposites(composites),
-		)
+		src, err := codegen.GenerateUBLibraryPackage(codegen.UBLibraryInput{
+			PackageID:            packageID,
+			LibraryName:          alias,
+			SyntaxBodies:         syntaxBodiesForCompiledComposites(composites),
+			Imports:              codegenImportsForCompiledComposites(composites),
+			GoSpecs:              goSpecsForCompiledComposites(composites),
+			SourceFiles:          lib.SourceFiles,
+			AssetSetIDs:          assetSetIDsForCompiledComposites(composites),
+			LibraryConfigSchemas: libraryConfigSchemasForCompiledComposites(composites),
+			Functions:            lib.Functions,
+		})
 		if err != nil {
 			return err
 		}
 		v.packages[packageID] = src
 	}
 	v.runtimeLibraries[canonicalKey] = runtimeLib
+	v.ubLibraries[canonicalKey] = lib
 	return nil
 }
 
+// recordCompositeBodies keeps the identity of each composite body in
+// runtimeLib, so a check with a Cache can tell which bodies it has seen
+// before. Libraries walked without a fingerprint are not recorded.
+func (v *importVisitor) recordCompositeBodies(
+	canonicalKey, fingerprint string, runtimeLib *runtime.Library,
+) {
+	if fingerprint == "" {
+		return
+	}
+	for _, composites := range []map[string]*runtime.CompositeType{
+		runtimeLib.ResourceComposites,
+		runtimeLib.DataComposites,
+		runtimeLib.ActionComposites,
+	} {
+		for name, composite := range composites {
+			v.compositeBodies[composite.SyntaxBody] = compositeBody{
+				id: compositeID{
+					library: canonicalKey,
+					kind:    string(composite.Kind),
+					name:    name,
+				},
+				fingerprint: fingerprint,
+			}
+		}
+	}
+}
+
 type compiledComposite struct {
 	entry                resolve.CompositeEntry
 	bodyLibs             map[string]*runtime.Library
@@ -521,6 +573,17 @@ func runtimeLibraryForCompiledComposites(
 	return lib
 }
 
+// addRuntimeLibraryFunctions registers a UB library's functions on lib
+// along with their declared signatures, so calls against the library
+// check the way calls against a Go library do.
+func addRuntimeLibraryFunctions(lib *runtime.Library, decls []syntax.FunctionDecl) {
+	if len(decls) == 0 {
+		return
+	}
+	lib.Functions = runtime.SyntaxFunctions(decls)
+	lib.Schema = &runtime.LibrarySchema{Functions: runtime.SyntaxFunctionSigs(decls)}
+}
+
 func syntaxBodiesForCompiledComposites(
 	composites []compiledComposite,
 ) map[string]map[string]syntax.FactoryBody {
@@ -94,14 +94,13 @@ func TestImportVisitorBuildsCompositeArtifactsTogether(t *testing.T) {
 	require.Contains(t, goSpecs["example.com/std"].Schema.DataSources, "query")
 	require.NotContains(t, goSpecs["example.com/std"].Schema.Resources, "unused")
 
-	generated, err := codegen.GenerateUBLibraryPackage(
-		"bundle",
-		"bundle",
-		syntaxBodiesForCompiledComposites(composites),
-		imports,
-		goSpecs,
-		nil,
-	)
+	generated, err := codegen.GenerateUBLibraryPackage(codegen.UBLibraryInput{
+		PackageID:    "bundle",
+		LibraryName:  "bundle",
+		SyntaxBodies: syntaxBodiesForCompiledComposites(composites),
+		Imports:      imports,
+		GoSpecs:      goSpecs,
+	})
 	require.NoError(t, err)
 	_, err = parser.ParseFile(token.NewFileSet(), "bundle.go", generated, parser.AllErrors)
 	require.NoError(t, err, "generated source should parse:\n%s", generated)
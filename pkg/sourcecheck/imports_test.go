@@ -352,12 +352,24 @@ func BenchmarkImportAnalysisNestedUBLibraries(b *testing.B) {
 		},
 	}
 
-	b.ReportAllocs()
-	for b.Loop() {
-		if _, err := AnalyzeImports(refs, opts); err != nil {
-			b.Fatal(err)
+	b.Run("cold", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := AnalyzeImports(refs, opts); err != nil {
+				b.Fatal(err)
+			}
 		}
-	}
+	})
+	b.Run("cached", func(b *testing.B) {
+		cached := opts
+		cached.Cache = NewCache()
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := AnalyzeImports(refs, cached); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
 }
 
 func sourcecheckFactoryHeader(name string) string {
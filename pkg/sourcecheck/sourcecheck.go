@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"testing/fstest"
 
+	"github.com/cloudboss/unobin/pkg/asset"
 	"github.com/cloudboss/unobin/pkg/check"
 	"github.com/cloudboss/unobin/pkg/diagnostic"
 	"github.com/cloudboss/unobin/pkg/lang/syntax"
@@ -30,6 +31,7 @@ type Options struct {
 	Resolver       resolve.Resolver
 	Versions       map[string]string
 	SchemaCache    *SchemaCache
+	Cache          *Cache
 	Reporter       diagnostic.Reporter
 	Mode           Mode
 }
@@ -39,6 +41,8 @@ type Result struct {
 	Libraries            map[string]*runtime.Library
 	LibraryConfigSchemas map[string]runtime.LibraryConfigSchema
 	DAG                  *runtime.DAG
+	AssetCatalog         *asset.Catalog
+	RootAssetSetID       string
 }
 
 // CheckFactoryBody resolves imports and runs compile-time checks for body.
@@ -51,13 +55,17 @@ func CheckFactoryBody(body syntax.FactoryBody, opts Options) (*Result, error) {
 	if err != nil {
 		return nil, err
 	}
+	catalog := analysis.Assets.Catalog()
 	checker := check.NewSyntaxWithLibraryConfigSchemas(
 		body,
 		analysis.Libraries,
 		analysis.LibraryConfigSchemas,
-		analysis.Assets.Catalog(),
+		catalog,
 		analysis.RootAssetSetID,
 	)
+	if opts.Cache != nil {
+		checker.SkipBodies(opts.Cache.checked(analysis.compositeBodies))
+	}
 	if errs := checker.References(nil); errs.Len() > 0 {
 		return nil, errs.Err()
 	}
@@ -67,10 +75,15 @@ func CheckFactoryBody(body syntax.FactoryBody, opts Options) (*Result, error) {
 	if errs := checker.ForEachNesting(); errs.Len() > 0 {
 		return nil, errs.Err()
 	}
+	if opts.Cache != nil {
+		opts.Cache.storeClean(analysis.compositeBodies, checker.CompositeBodies())
+	}
 	return &Result{
 		Libraries:            analysis.Libraries,
 		LibraryConfigSchemas: analysis.LibraryConfigSchemas,
 		DAG:                  checker.DAG(),
+		AssetCatalog:         catalog,
+		RootAssetSetID:       analysis.RootAssetSetID,
 	}, nil
 }
 
@@ -85,14 +98,21 @@ func CheckUBLibrary(source *resolve.Source, opts Options) error {
 	if checkOpts.ProjectDir == "" && source != nil {
 		checkOpts.ProjectDir = source.Path
 	}
+	if errs := check.Functions(lib.Functions); errs.Len() > 0 {
+		return errs.Err()
+	}
 	return checkCompositeEntries(lib.CompositeEntries(), checkOpts)
 }
 
-// CheckLibraryFile checks every exported composite body in file.
+// CheckLibraryFile checks every exported composite body and function
+// in file.
 func CheckLibraryFile(file *syntax.LibraryFile, opts Options) error {
 	if file == nil {
 		return errors.New("sourcecheck: library file is nil")
 	}
+	if errs := check.Functions(file.Functions); errs.Len() > 0 {
+		return errs.Err()
+	}
 	entries := make([]resolve.CompositeEntry, 0, len(file.Exports))
 	for _, export := range file.Exports {
 		entries = append(entries, resolve.CompositeEntry{
@@ -141,6 +161,7 @@ func analyzeFactoryImports(
 		Resolver:       opts.Resolver,
 		Versions:       opts.Versions,
 		SchemaCache:    opts.SchemaCache,
+		Cache:          opts.Cache,
 		Reporter:       opts.Reporter,
 		Mode:           opts.Mode,
 		Body:           &body,
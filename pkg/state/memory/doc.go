@@ -0,0 +1,4 @@
+// Package memory stores state snapshots in memory for the life of a
+// process. It backs runs that must not touch real state, such as
+// `unobin test`.
+package memory
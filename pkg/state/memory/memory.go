@@ -0,0 +1,143 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	sdkstate "github.com/cloudboss/unobin/pkg/sdk/state"
+)
+
+var _ sdkstate.Backend = (*Store)(nil)
+
+// Store holds a stack's snapshots in memory. Snapshots are kept in
+// their encoded form, so a caller that changes a snapshot after Write or
+// Get does not change the stored copy. Revisions count up from 1 in
+// write order.
+type Store struct {
+	stack string
+
+	mu        sync.Mutex
+	snapshots map[string][]byte
+	revs      []string
+	current   string
+	locked    chan struct{}
+}
+
+// NewStore returns an empty Store for stack.
+func NewStore(stack string) *Store {
+	return &Store{
+		stack:     stack,
+		snapshots: map[string][]byte{},
+		locked:    make(chan struct{}, 1),
+	}
+}
+
+// Stack returns the stack name this store was constructed for.
+func (s *Store) Stack() string { return s.stack }
+
+// Current returns the snapshot named by the current pointer, or
+// sdkstate.ErrNoCurrent when none has been set.
+func (s *Store) Current() (*sdkstate.Snapshot, error) {
+	rev, err := s.CurrentRev()
+	if err != nil {
+		return nil, err
+	}
+	return s.Get(rev)
+}
+
+// CurrentRev returns the rev the current pointer names, or
+// sdkstate.ErrNoCurrent.
+func (s *Store) CurrentRev() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.current == "" {
+		return "", sdkstate.ErrNoCurrent
+	}
+	return s.current, nil
+}
+
+// Get returns the snapshot with the given rev.
+func (s *Store) Get(rev string) (*sdkstate.Snapshot, error) {
+	s.mu.Lock()
+	body, ok := s.snapshots[rev]
+	s.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("memory store: no snapshot %s", rev)
+	}
+	return sdkstate.DecodeSnapshot(body)
+}
+
+// Write stores snap and returns its rev. The caller advances the
+// current pointer with SetCurrent.
+func (s *Store) Write(snap *sdkstate.Snapshot) (string, error) {
+	body, err := sdkstate.EncodeSnapshot(snap)
+	if err != nil {
+		return "", err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rev := fmt.Sprint(len(s.revs) + 1)
+	s.snapshots[rev] = body
+	s.revs = append(s.revs, rev)
+	return rev, nil
+}
+
+// SetCurrent points current at the named rev, which must exist.
+func (s *Store) SetCurrent(rev string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.snapshots[rev]; !ok {
+		return fmt.Errorf("set-current %s: memory store: no such snapshot", rev)
+	}
+	s.current = rev
+	return nil
+}
+
+// List returns the revs of every stored snapshot in write order.
+func (s *Store) List() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.revs), nil
+}
+
+// Delete removes the snapshot with the given rev. Removing a rev that
+// does not exist is not an error.
+func (s *Store) Delete(rev string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.snapshots, rev)
+	s.revs = slices.DeleteFunc(s.revs, func(r string) bool { return r == rev })
+	return nil
+}
+
+// Lock acquires the stack's exclusive lock, blocking until it is free
+// or ctx is canceled.
+func (s *Store) Lock(ctx context.Context) (sdkstate.Lock, error) {
+	select {
+	case s.locked <- struct{}{}:
+		return &lock{store: s}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// ForceUnlock releases the lock whoever holds it.
+func (s *Store) ForceUnlock() error {
+	select {
+	case <-s.locked:
+	default:
+	}
+	return nil
+}
+
+type lock struct {
+	store *Store
+	once  sync.Once
+}
+
+func (l *lock) Unlock() error {
+	l.once.Do(func() { _ = l.store.ForceUnlock() })
+	return nil
+}
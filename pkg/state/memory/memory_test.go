@@ -0,0 +1,62 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sdkstate "github.com/cloudboss/unobin/pkg/sdk/state"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreRoundTrip(t *testing.T) {
+	s := NewStore("dev")
+	_, err := s.Current()
+	require.ErrorIs(t, err, sdkstate.ErrNoCurrent)
+
+	snap := &sdkstate.Snapshot{
+		FormatVersion: sdkstate.CurrentFormatVersion,
+		Stack:         "dev",
+		Entries: []*sdkstate.Entry{{
+			Address:  "resource.main",
+			Type:     sdkstate.EntryLeaf,
+			Category: "resource",
+			Binding:  &sdkstate.Binding{Alias: "aws", Export: "vpc"},
+			Outputs:  map[string]any{"id": "vpc-abc"},
+		}},
+	}
+	rev, err := s.Write(snap)
+	require.NoError(t, err)
+	require.NoError(t, s.SetCurrent(rev))
+	snap.Entries[0].Outputs["id"] = "changed"
+
+	got, err := s.Current()
+	require.NoError(t, err)
+	require.Equal(t, "vpc-abc", got.Entries[0].Outputs["id"])
+	second, err := s.Write(snap)
+	require.NoError(t, err)
+	revs, err := s.List()
+	require.NoError(t, err)
+	require.Equal(t, []string{rev, second}, revs)
+	require.NoError(t, s.Delete(rev))
+	revs, err = s.List()
+	require.NoError(t, err)
+	require.Equal(t, []string{second}, revs)
+	require.Error(t, s.SetCurrent("missing"))
+}
+
+func TestStoreLockExcludes(t *testing.T) {
+	s := NewStore("dev")
+	held, err := s.Lock(context.Background())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = s.Lock(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	require.NoError(t, held.Unlock())
+	again, err := s.Lock(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, again.Unlock())
+}
@@ -0,0 +1,233 @@
+package sumdb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/cloudboss/unobin/pkg/deps"
+	"golang.org/x/mod/sumdb/note"
+	"golang.org/x/mod/sumdb/tlog"
+)
+
+// Client checks hashes against one checksum database.
+type Client struct {
+	Config Config
+	// StateDir holds the newest tree head the client has verified, so a
+	// database that forks or rewrites its log is caught on the next
+	// lookup.
+	StateDir string
+	// NoSumDB is the UNOBIN_NOSUMDB patterns of ids that are not checked.
+	NoSumDB string
+	// HTTP is the client requests go through; nil uses http.DefaultClient.
+	HTTP *http.Client
+}
+
+// CheckLock checks every UB hash in lock that prev does not already
+// record for the same version against the database. Hashes prev records
+// were checked when they were written.
+func (c *Client) CheckLock(ctx context.Context, prev, lock *deps.ProjectLock) error {
+	for _, id := range lock.SortedIDs() {
+		entry := lock.Deps[id]
+		if entry.Kind != deps.ProjectLockKindUB || !Checked(id, entry.Version, c.NoSumDB) {
+			continue
+		}
+		if prev != nil {
+			if old, ok := prev.Deps[id]; ok && old.Version == entry.Version && old.Hash == entry.Hash {
+				continue
+			}
+		}
+		if err := c.Check(ctx, id, entry.Version, entry.Hash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Check verifies that the database records hash for id at version.
+func (c *Client) Check(ctx context.Context, id, version, hash string) error {
+	n, record, tree, signed, err := c.lookup(ctx, id, version)
+	if err != nil {
+		return fmt.Errorf("verifying %s@%s: %w", id, version, err)
+	}
+	proof, err := c.proof(ctx, "record", n, tree.N)
+	if err != nil {
+		return fmt.Errorf("verifying %s@%s: %w", id, version, err)
+	}
+	if err := tlog.CheckRecord(proof, tree.N, tree.Hash, n, tlog.RecordHash([]byte(record))); err != nil {
+		return c.securityError(fmt.Errorf("record %d is not in the signed tree: %w", n, err))
+	}
+	if err := c.advance(ctx, tree, signed); err != nil {
+		return err
+	}
+	gotID, gotVersion, want, err := parseRecord(record)
+	if err != nil {
+		return c.securityError(err)
+	}
+	if gotID != id || gotVersion != version {
+		return c.securityError(fmt.Errorf("lookup of %s@%s returned %q", id, version, record))
+	}
+	if want != hash {
+		return fmt.Errorf("verifying %s@%s: checksum mismatch\n\tproject-lock: %s\n\t%s: %s\n"+
+			"the project changed since the database recorded it; do not trust it until the cause is known",
+			id, version, hash, c.Config.URL, want)
+	}
+	return nil
+}
+
+func (c *Client) lookup(
+	ctx context.Context, id, version string,
+) (int64, string, tlog.Tree, []byte, error) {
+	body, err := c.get(ctx, "/lookup/"+url.PathEscape(id+"@"+version))
+	if err != nil {
+		return 0, "", tlog.Tree{}, nil, err
+	}
+	first, rest, ok := bytes.Cut(body, []byte("\n"))
+	recordLine, signed, ok2 := bytes.Cut(rest, []byte("\n"))
+	n, errN := strconv.ParseInt(string(first), 10, 64)
+	if !ok || !ok2 || errN != nil {
+		return 0, "", tlog.Tree{}, nil, c.securityError(fmt.Errorf("malformed lookup response"))
+	}
+	tree, err := c.openTree(signed)
+	if err != nil {
+		return 0, "", tlog.Tree{}, nil, err
+	}
+	if n >= tree.N {
+		return 0, "", tlog.Tree{}, nil, c.securityError(
+			fmt.Errorf("record %d is beyond the signed tree of size %d", n, tree.N))
+	}
+	return n, string(recordLine) + "\n", tree, signed, nil
+}
+
+// openTree verifies a signed tree head.
+func (c *Client) openTree(signed []byte) (tlog.Tree, error) {
+	verifier, err := note.NewVerifier(c.Config.Key)
+	if err != nil {
+		return tlog.Tree{}, err
+	}
+	msg, err := note.Open(signed, note.VerifierList(verifier))
+	if err != nil {
+		return tlog.Tree{}, c.securityError(fmt.Errorf("tree head: %w", err))
+	}
+	tree, err := tlog.ParseTree([]byte(msg.Text))
+	if err != nil {
+		return tlog.Tree{}, c.securityError(fmt.Errorf("tree head: %w", err))
+	}
+	return tree, nil
+}
+
+// advance proves that tree and the newest tree the client has verified
+// are one log, and remembers the newer of the two.
+func (c *Client) advance(ctx context.Context, tree tlog.Tree, signed []byte) error {
+	path := c.statePath()
+	prevSigned, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if err == nil {
+		prev, err := c.openTree(prevSigned)
+		if err != nil {
+			return err
+		}
+		older, newer := prev, tree
+		if prev.N > tree.N {
+			older, newer = tree, prev
+		}
+		if older.N == newer.N {
+			if older.Hash != newer.Hash {
+				return c.securityError(fmt.Errorf("two trees of size %d differ", older.N))
+			}
+		} else if older.N > 0 {
+			proof, err := c.proof(ctx, "tree", older.N, newer.N)
+			if err != nil && newer == prev {
+				return c.securityError(fmt.Errorf(
+					"log of size %d cannot prove the tree of size %d already verified: %w",
+					tree.N, prev.N, err))
+			}
+			if err != nil {
+				return err
+			}
+			if err := tlog.CheckTree(proof, newer.N, newer.Hash, older.N, older.Hash); err != nil {
+				return c.securityError(fmt.Errorf("tree of size %d is not a prefix of size %d: %w",
+					older.N, newer.N, err))
+			}
+		}
+		if prev.N >= tree.N {
+			return nil
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, signed, 0o644)
+}
+
+func (c *Client) statePath() string {
+	name := c.Config.Key
+	if verifier, err := note.NewVerifier(c.Config.Key); err == nil {
+		name = verifier.Name()
+	}
+	return filepath.Join(c.StateDir, filepath.FromSlash(name), "latest")
+}
+
+func (c *Client) proof(ctx context.Context, kind string, n, t int64) ([]tlog.Hash, error) {
+	body, err := c.get(ctx, fmt.Sprintf("/proof/%s/%d/%d", kind, n, t))
+	if err != nil {
+		return nil, err
+	}
+	var hashes []tlog.Hash
+	for line := range strings.SplitSeq(strings.TrimSpace(string(body)), "\n") {
+		if line == "" {
+			continue
+		}
+		h, err := tlog.ParseHash(line)
+		if err != nil {
+			return nil, c.securityError(fmt.Errorf("malformed %s proof", kind))
+		}
+		hashes = append(hashes, h)
+	}
+	return hashes, nil
+}
+
+func (c *Client) get(ctx context.Context, path string) ([]byte, error) {
+	target := c.Config.URL + path
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	client := c.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("GET %s: %w", target, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		message := string(bytes.TrimSpace(body))
+		if message == "" {
+			message = resp.Status
+		}
+		return nil, fmt.Errorf("GET %s: %s", target, message)
+	}
+	return body, nil
+}
+
+// securityError reports a database that cannot be trusted.
+func (c *Client) securityError(err error) error {
+	return fmt.Errorf("SECURITY ERROR: checksum database %s: %w", c.Config.URL, err)
+}
@@ -0,0 +1,208 @@
+package sumdb
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/mod/sumdb/tlog"
+)
+
+const (
+	recordsFile = "records"
+	hashesFile  = "hashes"
+)
+
+// Log is an append-only transparency log stored in a directory: the
+// records one per line in `records`, and the tree's stored hashes in
+// `hashes`. Records are never rewritten; hashes are rebuilt from the
+// records if a crash left them behind.
+type Log struct {
+	dir string
+
+	mu      sync.Mutex
+	records []string
+	index   map[string]int64
+	hashes  []tlog.Hash
+}
+
+// OpenLog opens the log in dir, creating an empty one if dir has none.
+func OpenLog(dir string) (*Log, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	l := &Log{dir: dir, index: map[string]int64{}}
+	body, err := os.ReadFile(filepath.Join(dir, recordsFile))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		record := scanner.Text() + "\n"
+		id, version, _, err := parseRecord(record)
+		if err != nil {
+			return nil, fmt.Errorf("%s: record %d: %w", filepath.Join(dir, recordsFile), len(l.records), err)
+		}
+		l.index[id+"@"+version] = int64(len(l.records))
+		l.records = append(l.records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(body) > 0 && body[len(body)-1] != '\n' {
+		return nil, fmt.Errorf("%s: last record is incomplete", filepath.Join(dir, recordsFile))
+	}
+	if err := l.loadHashes(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// loadHashes reads the stored hashes, rebuilding them from the records
+// when they do not cover every record.
+func (l *Log) loadHashes() error {
+	path := filepath.Join(l.dir, hashesFile)
+	body, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	want := tlog.StoredHashCount(int64(len(l.records)))
+	if int64(len(body)) == want*tlog.HashSize {
+		for i := 0; i < len(body); i += tlog.HashSize {
+			l.hashes = append(l.hashes, tlog.Hash(body[i:i+tlog.HashSize]))
+		}
+		return nil
+	}
+	var b bytes.Buffer
+	for n, record := range l.records {
+		stored, err := tlog.StoredHashes(int64(n), []byte(record), l.reader())
+		if err != nil {
+			return err
+		}
+		l.hashes = append(l.hashes, stored...)
+		for _, h := range stored {
+			b.Write(h[:])
+		}
+	}
+	return writeSynced(path, b.Bytes(), os.O_CREATE|os.O_TRUNC|os.O_WRONLY)
+}
+
+// Size returns the number of records in the log.
+func (l *Log) Size() int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return int64(len(l.records))
+}
+
+// Lookup returns the number and text of the record for id at version.
+func (l *Log) Lookup(id, version string) (int64, string, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	n, ok := l.index[id+"@"+version]
+	if !ok {
+		return 0, "", false
+	}
+	return n, l.records[n], true
+}
+
+// Append adds the record for id at version and returns its number. A
+// version already in the log keeps its first record.
+func (l *Log) Append(id, version, hash string) (int64, string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if n, ok := l.index[id+"@"+version]; ok {
+		return n, l.records[n], nil
+	}
+	record := Record(id, version, hash)
+	if _, _, _, err := parseRecord(record); err != nil {
+		return 0, "", err
+	}
+	n := int64(len(l.records))
+	stored, err := tlog.StoredHashes(n, []byte(record), l.reader())
+	if err != nil {
+		return 0, "", err
+	}
+	flags := os.O_CREATE | os.O_APPEND | os.O_WRONLY
+	if err := writeSynced(filepath.Join(l.dir, recordsFile), []byte(record), flags); err != nil {
+		return 0, "", err
+	}
+	var b bytes.Buffer
+	for _, h := range stored {
+		b.Write(h[:])
+	}
+	if err := writeSynced(filepath.Join(l.dir, hashesFile), b.Bytes(), flags); err != nil {
+		return 0, "", err
+	}
+	l.records = append(l.records, record)
+	l.hashes = append(l.hashes, stored...)
+	l.index[id+"@"+version] = n
+	return n, record, nil
+}
+
+// Tree returns the log's current tree head.
+func (l *Log) Tree() (tlog.Tree, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	n := int64(len(l.records))
+	h, err := tlog.TreeHash(n, l.reader())
+	if err != nil {
+		return tlog.Tree{}, err
+	}
+	return tlog.Tree{N: n, Hash: h}, nil
+}
+
+// ProveRecord returns the proof that record n is in the tree of size t.
+func (l *Log) ProveRecord(t, n int64) (tlog.RecordProof, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if t > int64(len(l.records)) {
+		return nil, fmt.Errorf("tree size %d is larger than the log", t)
+	}
+	return tlog.ProveRecord(t, n, l.reader())
+}
+
+// ProveTree returns the proof that the tree of size n is a prefix of the
+// tree of size t.
+func (l *Log) ProveTree(t, n int64) (tlog.TreeProof, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if t > int64(len(l.records)) {
+		return nil, fmt.Errorf("tree size %d is larger than the log", t)
+	}
+	return tlog.ProveTree(t, n, l.reader())
+}
+
+// reader reads stored hashes; the caller holds mu.
+func (l *Log) reader() tlog.HashReader {
+	return tlog.HashReaderFunc(func(indexes []int64) ([]tlog.Hash, error) {
+		out := make([]tlog.Hash, len(indexes))
+		for i, index := range indexes {
+			if index < 0 || index >= int64(len(l.hashes)) {
+				return nil, fmt.Errorf("stored hash %d is not in the log", index)
+			}
+			out[i] = l.hashes[index]
+		}
+		return out, nil
+	})
+}
+
+func writeSynced(path string, body []byte, flags int) error {
+	f, err := os.OpenFile(path, flags, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(body); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
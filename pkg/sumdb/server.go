@@ -0,0 +1,140 @@
+package sumdb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/semver"
+	"golang.org/x/mod/sumdb/note"
+	"golang.org/x/mod/sumdb/tlog"
+)
+
+// HashFunc returns the hash of the UB project id names at version, as
+// project-lock records it. The server calls it for a version not yet in
+// its log.
+type HashFunc func(ctx context.Context, id, version string) (string, error)
+
+// NewHandler returns a checksum database serving log, signing tree heads
+// with signer. A lookup of a version the log does not have hashes it
+// with hash and appends it; a version that cannot be hashed is a 404.
+func NewHandler(log *Log, signer note.Signer, hash HashFunc) http.Handler {
+	return &server{log: log, signer: signer, hash: hash}
+}
+
+type server struct {
+	log    *Log
+	signer note.Signer
+	hash   HashFunc
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	path := r.URL.EscapedPath()
+	var (
+		body []byte
+		err  error
+	)
+	switch {
+	case path == "/latest":
+		body, err = s.signedTree()
+	case strings.HasPrefix(path, "/lookup/"):
+		body, err = s.lookup(r.Context(), strings.TrimPrefix(path, "/lookup/"))
+	case strings.HasPrefix(path, "/proof/record/"):
+		body, err = s.proof(strings.TrimPrefix(path, "/proof/record/"), func(t, n int64) ([]tlog.Hash, error) {
+			return s.log.ProveRecord(t, n)
+		})
+	case strings.HasPrefix(path, "/proof/tree/"):
+		body, err = s.proof(strings.TrimPrefix(path, "/proof/tree/"), func(t, n int64) ([]tlog.Hash, error) {
+			return s.log.ProveTree(t, n)
+		})
+	default:
+		err = errNotFound{fmt.Errorf("%s is not a checksum database path", path)}
+	}
+	var notFound errNotFound
+	switch {
+	case errors.As(err, &notFound):
+		http.Error(w, notFound.Error(), http.StatusNotFound)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = w.Write(body)
+}
+
+// errNotFound marks a request the log cannot answer.
+type errNotFound struct{ error }
+
+func (s *server) signedTree() ([]byte, error) {
+	tree, err := s.log.Tree()
+	if err != nil {
+		return nil, err
+	}
+	return note.Sign(&note.Note{Text: string(tlog.FormatTree(tree))}, s.signer)
+}
+
+func (s *server) lookup(ctx context.Context, escaped string) ([]byte, error) {
+	key, err := url.PathUnescape(escaped)
+	if err != nil {
+		return nil, errNotFound{err}
+	}
+	at := strings.LastIndex(key, "@")
+	if at < 0 {
+		return nil, errNotFound{fmt.Errorf("%q is not <id>@<version>", key)}
+	}
+	id, version := key[:at], key[at+1:]
+	if !semver.IsValid(version) {
+		return nil, errNotFound{fmt.Errorf("%s: %s is not a semantic version", id, version)}
+	}
+	n, record, ok := s.log.Lookup(id, version)
+	if !ok {
+		hash, err := s.hash(ctx, id, version)
+		if err != nil {
+			return nil, errNotFound{fmt.Errorf("%s@%s: %w", id, version, err)}
+		}
+		n, record, err = s.log.Append(id, version, hash)
+		if err != nil {
+			return nil, err
+		}
+	}
+	signed, err := s.signedTree()
+	if err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%d\n%s", n, record)
+	b.Write(signed)
+	return b.Bytes(), nil
+}
+
+// proof answers `<n>/<size>` with the hashes prove returns.
+func (s *server) proof(rest string, prove func(t, n int64) ([]tlog.Hash, error)) ([]byte, error) {
+	first, second, ok := strings.Cut(rest, "/")
+	n, errN := strconv.ParseInt(first, 10, 64)
+	t, errT := strconv.ParseInt(second, 10, 64)
+	if !ok || errN != nil || errT != nil || n < 0 || t < 0 {
+		return nil, errNotFound{fmt.Errorf("%q is not <n>/<size>", rest)}
+	}
+	if t > s.log.Size() {
+		return nil, errNotFound{fmt.Errorf("tree size %d is larger than the log", t)}
+	}
+	hashes, err := prove(t, n)
+	if err != nil {
+		return nil, errNotFound{err}
+	}
+	var b bytes.Buffer
+	for _, h := range hashes {
+		b.WriteString(h.String() + "\n")
+	}
+	return b.Bytes(), nil
+}
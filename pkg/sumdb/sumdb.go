@@ -0,0 +1,97 @@
+// Package sumdb implements a checksum database for UB project hashes in
+// the manner of sum.golang.org: a server keeps an append-only
+// transparency log of `<id> <version> <hash>` records and signs each tree
+// head, and a client checks a project-lock hash against the log before
+// trusting it, proving the record is in the log and the log only grows.
+//
+// The server answers over HTTP:
+//
+//	GET /latest                       the signed tree head
+//	GET /lookup/<id>@<version>        the record number, the record, and the signed tree head
+//	GET /proof/record/<n>/<size>      the proof that record n is in the tree of size
+//	GET /proof/tree/<old>/<size>      the proof that the tree of old is a prefix of size
+//
+// `<id>@<version>` is path-escaped as one element. Proofs are base64
+// hashes, one per line.
+package sumdb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cloudboss/unobin/pkg/deps"
+	"github.com/cloudboss/unobin/pkg/proxy"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+	"golang.org/x/mod/sumdb/note"
+)
+
+// Env names the checksum database: its verifier key and URL, separated
+// by a space, or `off`. Unset is off.
+const Env = "UNOBIN_SUMDB"
+
+// NoSumDBEnv is a comma-separated list of glob prefixes, in the manner
+// of GONOSUMDB, of project ids that are never checked, such as private
+// repositories the database cannot fetch.
+const NoSumDBEnv = "UNOBIN_NOSUMDB"
+
+// Off disables the checksum database.
+const Off = "off"
+
+// Config is a parsed UNOBIN_SUMDB.
+type Config struct {
+	// Key is the verifier key tree heads are signed with.
+	Key string
+	// URL is the database's URL, without a trailing slash.
+	URL string
+}
+
+// ParseConfig parses an UNOBIN_SUMDB value. An empty value and `off`
+// return nil.
+func ParseConfig(value string) (*Config, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == Off {
+		return nil, nil
+	}
+	key, url, ok := strings.Cut(value, " ")
+	url = strings.TrimSpace(url)
+	if !ok || url == "" {
+		return nil, fmt.Errorf("%s: want %q or %s", Env, "<verifier-key> <url>", Off)
+	}
+	if _, err := note.NewVerifier(key); err != nil {
+		return nil, fmt.Errorf("%s: verifier key: %w", Env, err)
+	}
+	if !strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "http://") {
+		return nil, fmt.Errorf("%s: %q is not an http or https URL", Env, url)
+	}
+	return &Config{Key: key, URL: strings.TrimSuffix(url, "/")}, nil
+}
+
+// Record returns the log record for a project version's hash.
+func Record(id, version, hash string) string {
+	return fmt.Sprintf("%s %s %s\n", id, version, hash)
+}
+
+// parseRecord splits a log record into its id, version, and hash.
+func parseRecord(record string) (id, version, hash string, err error) {
+	fields := strings.Fields(record)
+	if len(fields) != 3 || !strings.HasSuffix(record, "\n") || strings.Count(record, "\n") != 1 {
+		return "", "", "", fmt.Errorf("malformed record %q", record)
+	}
+	return fields[0], fields[1], fields[2], nil
+}
+
+// Checked reports whether a project version is checked against a
+// database: a UB project at a semantic version, fetched from a host, whose
+// id no NoSumDB pattern matches. Branches and commits move or are not
+// shared, and local paths are not visible to a database.
+func Checked(id, version, noSumDB string) bool {
+	dep, err := deps.ParseDependency(id)
+	if err != nil || !semver.IsValid(version) {
+		return false
+	}
+	if !proxy.Proxied(dep.URL) {
+		return false
+	}
+	return noSumDB == "" || !module.MatchPrefixPatterns(noSumDB, id)
+}
@@ -0,0 +1,191 @@
+package sumdb
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudboss/unobin/pkg/deps"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/mod/sumdb/note"
+)
+
+func TestParseConfig(t *testing.T) {
+	_, vkey, err := note.GenerateKey(rand.Reader, "localhost")
+	require.NoError(t, err)
+
+	for _, value := range []string{"", " off "} {
+		cfg, err := ParseConfig(value)
+		require.NoError(t, err)
+		require.Nil(t, cfg, value)
+	}
+	cfg, err := ParseConfig(vkey + " http://localhost:7071/")
+	require.NoError(t, err)
+	require.Equal(t, &Config{Key: vkey, URL: "http://localhost:7071"}, cfg)
+
+	for value, want := range map[string]string{
+		vkey:                        `UNOBIN_SUMDB: want "<verifier-key> <url>" or off`,
+		vkey + " localhost:7071":    `UNOBIN_SUMDB: "localhost:7071" is not an http or https URL`,
+		"bad-key http://localhost/": "UNOBIN_SUMDB: verifier key: malformed verifier id",
+	} {
+		_, err := ParseConfig(value)
+		require.EqualError(t, err, want, value)
+	}
+}
+
+func TestChecked(t *testing.T) {
+	const noSumDB = "github.com/acme,*.corp.example.com"
+	for key, want := range map[[2]string]bool{
+		{"github.com/x/lib", "v1.2.3"}:                true,
+		{"github.com/x/lib", "main"}:                  false,
+		{"github.com/acme/lib", "v1.2.3"}:             false,
+		{"git.corp.example.com/team/lib", "v1.2.3"}:   false,
+		{"/srv/git/lib", "v1.2.3"}:                    false,
+		{"git@github.com:x/lib.git", "v1.2.3"}:        false,
+		{"github.com/x/lib//net", "net/v1.2.3"}:       false,
+		{"github.com/x/lib//net/inner", "v0.1.0-rc1"}: true,
+	} {
+		require.Equal(t, want, Checked(key[0], key[1], noSumDB), key)
+	}
+}
+
+func TestLogReopens(t *testing.T) {
+	dir := t.TempDir()
+	log, err := OpenLog(dir)
+	require.NoError(t, err)
+	for i := range 5 {
+		n, _, err := log.Append(fmt.Sprintf("example.com/x/lib%d", i), "v1.0.0", "sha256:00")
+		require.NoError(t, err)
+		require.Equal(t, int64(i), n)
+	}
+	n, record, err := log.Append("example.com/x/lib2", "v1.0.0", "sha256:ff")
+	require.NoError(t, err)
+	require.Equal(t, int64(2), n, "a version keeps its first record")
+	require.Equal(t, "example.com/x/lib2 v1.0.0 sha256:00\n", record)
+	tree, err := log.Tree()
+	require.NoError(t, err)
+	require.Equal(t, int64(5), tree.N)
+
+	reopened, err := OpenLog(dir)
+	require.NoError(t, err)
+	got, err := reopened.Tree()
+	require.NoError(t, err)
+	require.Equal(t, tree, got)
+
+	// Hashes a crash left behind are rebuilt from the records.
+	require.NoError(t, os.Remove(filepath.Join(dir, hashesFile)))
+	rebuilt, err := OpenLog(dir)
+	require.NoError(t, err)
+	got, err = rebuilt.Tree()
+	require.NoError(t, err)
+	require.Equal(t, tree, got)
+	n, _, ok := rebuilt.Lookup("example.com/x/lib3", "v1.0.0")
+	require.True(t, ok)
+	require.Equal(t, int64(3), n)
+}
+
+// sumServer serves a log in dir signed by skey, hashing every project
+// version by looking it up in hashes.
+func sumServer(t *testing.T, dir, skey string, hashes map[string]string) *httptest.Server {
+	t.Helper()
+	log, err := OpenLog(dir)
+	require.NoError(t, err)
+	signer, err := note.NewSigner(skey)
+	require.NoError(t, err)
+	hash := func(_ context.Context, id, version string) (string, error) {
+		h, ok := hashes[id+"@"+version]
+		if !ok {
+			return "", fmt.Errorf("unknown revision %s", version)
+		}
+		return h, nil
+	}
+	srv := httptest.NewServer(NewHandler(log, signer, hash))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestClientChecksAgainstServer(t *testing.T) {
+	skey, vkey, err := note.GenerateKey(rand.Reader, "localhost")
+	require.NoError(t, err)
+	hashes := map[string]string{
+		"example.com/x/lib@v1.0.0":     "sha256:aa",
+		"example.com/x/lib@v1.1.0":     "sha256:bb",
+		"example.com/x/mono//a@v0.1.0": "sha256:cc",
+	}
+	srv := sumServer(t, t.TempDir(), skey, hashes)
+	client := &Client{Config: Config{Key: vkey, URL: srv.URL}, StateDir: t.TempDir()}
+	ctx := context.Background()
+
+	require.NoError(t, client.Check(ctx, "example.com/x/lib", "v1.0.0", "sha256:aa"))
+	require.NoError(t, client.Check(ctx, "example.com/x/mono//a", "v0.1.0", "sha256:cc"))
+	require.NoError(t, client.Check(ctx, "example.com/x/lib", "v1.1.0", "sha256:bb"))
+	// An older record is proved against the newest tree the client has seen.
+	require.NoError(t, client.Check(ctx, "example.com/x/lib", "v1.0.0", "sha256:aa"))
+
+	err = client.Check(ctx, "example.com/x/lib", "v1.1.0", "sha256:tampered")
+	require.ErrorContains(t, err, "verifying example.com/x/lib@v1.1.0: checksum mismatch\n"+
+		"\tproject-lock: sha256:tampered\n\t"+srv.URL+": sha256:bb\n")
+
+	err = client.Check(ctx, "example.com/x/lib", "v9.0.0", "sha256:aa")
+	require.ErrorContains(t, err, "example.com/x/lib@v9.0.0: unknown revision v9.0.0")
+
+	lock := deps.NewProjectLock()
+	lock.Deps["example.com/x/lib"] = &deps.ProjectLockDep{
+		Kind: deps.ProjectLockKindUB, Version: "v1.1.0", Commit: "c2", Hash: "sha256:wrong",
+	}
+	lock.Deps["example.com/x/branch"] = &deps.ProjectLockDep{
+		Kind: deps.ProjectLockKindUB, Version: "main", Commit: "c3", Hash: "sha256:dd",
+	}
+	lock.Deps["example.com/x/golib"] = &deps.ProjectLockDep{Kind: deps.ProjectLockKindGo, Version: "v1.0.0"}
+	require.ErrorContains(t, client.CheckLock(ctx, nil, lock), "checksum mismatch")
+	// A hash the previous lock already recorded was checked when written.
+	require.NoError(t, client.CheckLock(ctx, lock, lock))
+}
+
+func TestClientRejectsForkedLog(t *testing.T) {
+	skey, vkey, err := note.GenerateKey(rand.Reader, "localhost")
+	require.NoError(t, err)
+	hashes := map[string]string{
+		"example.com/x/lib@v1.0.0": "sha256:aa",
+		"example.com/x/lib@v1.1.0": "sha256:bb",
+	}
+	stateDir := t.TempDir()
+	ctx := context.Background()
+
+	first := sumServer(t, t.TempDir(), skey, hashes)
+	client := &Client{Config: Config{Key: vkey, URL: first.URL}, StateDir: stateDir}
+	require.NoError(t, client.Check(ctx, "example.com/x/lib", "v1.0.0", "sha256:aa"))
+	require.NoError(t, client.Check(ctx, "example.com/x/lib", "v1.1.0", "sha256:bb"))
+
+	// The same key signing a different log of the same size is a fork.
+	forkedDir := t.TempDir()
+	forkedLog, err := OpenLog(forkedDir)
+	require.NoError(t, err)
+	_, _, err = forkedLog.Append("example.com/x/lib", "v1.1.0", "sha256:bb")
+	require.NoError(t, err)
+	_, _, err = forkedLog.Append("example.com/x/lib", "v1.0.0", "sha256:aa")
+	require.NoError(t, err)
+	forked := sumServer(t, forkedDir, skey, hashes)
+	client.Config.URL = forked.URL
+	err = client.Check(ctx, "example.com/x/lib", "v1.0.0", "sha256:aa")
+	require.ErrorContains(t, err, "SECURITY ERROR: checksum database "+forked.URL+": two trees of size 2 differ")
+
+	// So is a log smaller than a tree already verified.
+	truncated := sumServer(t, t.TempDir(), skey, hashes)
+	client.Config.URL = truncated.URL
+	err = client.Check(ctx, "example.com/x/lib", "v1.0.0", "sha256:aa")
+	require.ErrorContains(t, err, "SECURITY ERROR: checksum database "+truncated.URL+
+		": log of size 1 cannot prove the tree of size 2 already verified")
+
+	// A tree signed by another key is refused.
+	otherSkey, _, err := note.GenerateKey(rand.Reader, "localhost")
+	require.NoError(t, err)
+	other := sumServer(t, t.TempDir(), otherSkey, hashes)
+	client.Config.URL = other.URL
+	err = client.Check(ctx, "example.com/x/lib", "v1.0.0", "sha256:aa")
+	require.ErrorContains(t, err, "SECURITY ERROR: checksum database "+other.URL+": tree head:")
+}
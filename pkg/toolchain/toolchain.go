@@ -4,9 +4,13 @@
 package toolchain
 
 import (
+	"cmp"
+	"fmt"
 	"io"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"time"
 
 	"github.com/cloudboss/cachedeps"
@@ -42,6 +46,14 @@ var Go = cachedeps.Dependency{
 // the running platform.
 var All = []cachedeps.Dependency{Go}
 
+// Platforms returns the platforms Go pins an archive for, sorted by OS
+// and then architecture. They are the platforms a factory is built for.
+func Platforms() []cachedeps.Platform {
+	return slices.SortedFunc(maps.Keys(Go.URLs), func(a, b cachedeps.Platform) int {
+		return cmp.Or(cmp.Compare(a.OS, b.OS), cmp.Compare(a.Arch, b.Arch))
+	})
+}
+
 const (
 	cacheLockPoll       = 50 * time.Millisecond
 	cacheLockStaleAfter = 30 * time.Minute
@@ -64,6 +76,21 @@ func Ensure(out io.Writer) (string, error) {
 	return filepath.Join(dir, "go", "bin", "go"), nil
 }
 
+// Installed returns the path to the pinned `go` executable when the
+// toolchain is already cached, without fetching it. A toolchain missing
+// from the cache is an error naming where it belongs.
+func Installed() (string, error) {
+	dir, err := Go.Path(cachedeps.New("unobin"))
+	if err != nil {
+		return "", err
+	}
+	goBin := filepath.Join(dir, "go", "bin", "go")
+	if _, err := os.Stat(goBin); err != nil {
+		return "", fmt.Errorf("go %s is not in the toolchain cache at %s", Go.Version, dir)
+	}
+	return goBin, nil
+}
+
 func ensureDependency(
 	cache *cachedeps.Cache,
 	dep cachedeps.Dependency,
@@ -3,6 +3,8 @@ package toolchain
 import (
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"runtime"
 	"sync"
 	"testing"
@@ -25,6 +27,15 @@ func TestRegistryHasCurrentPlatform(t *testing.T) {
 	}
 }
 
+func TestPlatformsSorted(t *testing.T) {
+	assert.Equal(t, []cachedeps.Platform{
+		{OS: "darwin", Arch: "amd64"},
+		{OS: "darwin", Arch: "arm64"},
+		{OS: "linux", Arch: "amd64"},
+		{OS: "linux", Arch: "arm64"},
+	}, Platforms())
+}
+
 func TestEnsureDependencyLocksConcurrentCalls(t *testing.T) {
 	setTestCacheRoot(t)
 	cache := cachedeps.New("unobin-test")
@@ -88,6 +99,23 @@ func TestEnsureDependencyReleasesLockAfterError(t *testing.T) {
 	assert.Equal(t, "cached", got)
 }
 
+func TestInstalledDoesNotFetch(t *testing.T) {
+	setTestCacheRoot(t)
+	dir, err := Go.Path(cachedeps.New("unobin"))
+	require.NoError(t, err)
+
+	_, err = Installed()
+	require.EqualError(t, err, fmt.Sprintf(
+		"go %s is not in the toolchain cache at %s", Go.Version, dir))
+
+	goBin := filepath.Join(dir, "go", "bin", "go")
+	require.NoError(t, os.MkdirAll(filepath.Dir(goBin), 0o755))
+	require.NoError(t, os.WriteFile(goBin, nil, 0o755))
+	got, err := Installed()
+	require.NoError(t, err)
+	require.Equal(t, goBin, got)
+}
+
 func setTestCacheRoot(t *testing.T) {
 	t.Helper()
 	root := t.TempDir()
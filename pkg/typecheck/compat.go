@@ -20,6 +20,10 @@ package typecheck
 //     must have a compatible src field; extra src fields are
 //     allowed (open against the source) and missing-optional dst
 //     fields are tolerated.
+//   - a tagged union accepts a union with the same tag whose every
+//     variant fits the variant of the same name, or an object that
+//     fits one of its variants; a union flows into any other slot
+//     when every variant does.
 func Assignable(dst, src Type) bool {
 	if !dst.IsKnown() || !src.IsKnown() {
 		return true
@@ -47,6 +51,14 @@ func Assignable(dst, src Type) bool {
 	if src.Kind == Null {
 		return dst.Kind == Null
 	}
+	if src.Kind == TaggedUnion && dst.Kind != TaggedUnion {
+		for _, v := range src.Variants {
+			if !Assignable(dst, v.Type) {
+				return false
+			}
+		}
+		return len(src.Variants) > 0
+	}
 
 	switch dst.Kind {
 	case LibraryConfig:
@@ -76,6 +88,29 @@ func Assignable(dst, src Type) bool {
 		return tupleAssignable(dst, src)
 	case Object:
 		return objectAssignable(dst, src)
+	case TaggedUnion:
+		return taggedUnionAssignable(dst, src)
+	}
+	return false
+}
+
+func taggedUnionAssignable(dst, src Type) bool {
+	if src.Kind == TaggedUnion {
+		if src.Tag != dst.Tag {
+			return false
+		}
+		for _, sv := range src.Variants {
+			dv, ok := dst.Variant(sv.Name)
+			if !ok || !Assignable(dv.Type, sv.Type) {
+				return false
+			}
+		}
+		return true
+	}
+	for _, v := range dst.Variants {
+		if Assignable(v.Type, src) {
+			return true
+		}
 	}
 	return false
 }
@@ -184,6 +184,21 @@ func TestAssignableUnion(t *testing.T) {
 		"a possibly-null value wants a null test first")
 }
 
+func TestAssignableTaggedUnion(t *testing.T) {
+	union, git, s3, _ := sourceUnion()
+	narrower := TTaggedUnion("kind", []Variant{{Name: "git", Type: git}, {Name: "s3", Type: s3}})
+	renamed := TTaggedUnion("type", []Variant{{Name: "git", Type: git}})
+
+	assert.True(t, Assignable(union, union))
+	assert.True(t, Assignable(union, narrower), "a union of fewer variants fits")
+	assert.False(t, Assignable(narrower, union))
+	assert.False(t, Assignable(union, renamed), "the tags must match")
+	assert.True(t, Assignable(union, s3), "a narrowed variant fits its union")
+	assert.False(t, Assignable(union, TObject([]ObjectField{{Name: "bucket", Type: TString()}})))
+	assert.True(t, Assignable(TOpenObject(nil), union), "every variant is an object")
+	assert.False(t, Assignable(TString(), union))
+}
+
 func TestAssignableObjectFromMap(t *testing.T) {
 	dst := TObject([]ObjectField{
 		{Name: "id", Type: TString()},
@@ -223,3 +223,45 @@ func TestInferComprehensionElementAgainstTarget(t *testing.T) {
 	require.Len(t, errs.Errors(), 1)
 	assert.Contains(t, errs.Errors()[0].Msg, "expected integer, got string")
 }
+
+func TestInferTaggedUnionFieldNeedsNarrowing(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"input.source.bucket", `field "bucket" is only in variant s3 of input.source; test input.source.kind == 's3' first`},
+		{"input.source.url", `field "url" is only in variants git and http of input.source; test input.source.kind first`},
+		{"input.source.bogus", `unknown field "bogus"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			errs := lang.NewErrorList(0)
+			Infer(parseExpr(t, tt.src), TUnknown(), unionScope(), errs)
+			require.Len(t, errs.Errors(), 1)
+			assert.Contains(t, errs.Errors()[0].Msg, tt.want)
+		})
+	}
+
+	errs := lang.NewErrorList(0)
+	got := Infer(parseExpr(t, "input.source.kind"), TUnknown(), unionScope(), errs)
+	assert.True(t, got.Equal(TString()), "got %s", got)
+	assert.Empty(t, errs.Errors())
+}
+
+func TestInferConditionalNarrowsTaggedUnion(t *testing.T) {
+	errs := lang.NewErrorList(0)
+	got := Infer(parseExpr(t,
+		"if input.source.kind == 's3' then input.source.bucket else input.source.url"),
+		TUnknown(), unionScope(), errs)
+	assert.True(t, got.Equal(TString()), "got %s", got)
+	assert.Empty(t, errs.Errors())
+}
+
+func TestInferComprehensionWhenNarrowsTaggedUnion(t *testing.T) {
+	errs := lang.NewErrorList(0)
+	got := Infer(parseExpr(t,
+		"[ for s in input.sources : s.key when s.kind == 's3' ]"),
+		TUnknown(), unionScope(), errs)
+	assert.True(t, got.Equal(TList(TString())), "got %s", got)
+	assert.Empty(t, errs.Errors())
+}
@@ -4,8 +4,9 @@ import (
 	"github.com/cloudboss/unobin/pkg/lang"
 )
 
-// FromLang turns a parsed lang.TypeExpr into a semantic Type.
-// Returns TUnknown when the input is nil or names a constructor the
+// FromLang turns a parsed lang.TypeExpr into a semantic Type. A named
+// type converts as the type it names. Returns TUnknown when the input
+// is nil, is a name that was never bound, or names a constructor the
 // converter does not understand; the checker treats Unknown as a
 // silent skip.
 func FromLang(t lang.TypeExpr) Type {
@@ -35,10 +36,33 @@ func FromLang(t lang.TypeExpr) Type {
 		return TObject(fields)
 	case *lang.TypeOptional:
 		return TOptional(FromLang(v.Elem))
+	case *lang.TypeUnion:
+		return taggedUnionFromLang(v)
+	case *lang.TypeNamed:
+		return FromLang(v.Resolved)
 	}
 	return TUnknown()
 }
 
+// taggedUnionFromLang converts a union, adding the tag to each variant
+// object as a required string field. A variant that is not an object
+// leaves the whole union Unknown.
+func taggedUnionFromLang(u *lang.TypeUnion) Type {
+	variants := make([]Variant, 0, len(u.Variants))
+	for _, v := range u.Variants {
+		obj := FromLang(v.Type)
+		if obj.Kind != Object {
+			return TUnknown()
+		}
+		fields := make([]ObjectField, 0, len(obj.Fields)+1)
+		fields = append(fields, ObjectField{Name: u.Tag, Type: TString()})
+		fields = append(fields, obj.Fields...)
+		obj.Fields = fields
+		variants = append(variants, Variant{Name: v.Name, Type: obj})
+	}
+	return TTaggedUnion(u.Tag, variants)
+}
+
 func atomicFromLang(name string) Type {
 	switch name {
 	case "string":
@@ -81,7 +105,7 @@ func objectFieldFromLang(f *lang.TypeObjectField) ObjectField {
 }
 
 func peelOptional(t lang.TypeExpr) (lang.TypeExpr, bool, bool) {
-	if opt, ok := t.(*lang.TypeOptional); ok {
+	if opt, ok := lang.ResolveNamed(t).(*lang.TypeOptional); ok {
 		return opt.Elem, true, false
 	}
 	return t, false, false
@@ -103,7 +127,7 @@ func typeFromInputDecl(decl *lang.ObjectLit) (lang.TypeExpr, bool, bool) {
 		if !ok {
 			return nil, false, false
 		}
-		if opt, ok := t.(*lang.TypeOptional); ok {
+		if opt, ok := lang.ResolveNamed(t).(*lang.TypeOptional); ok {
 			return opt.Elem, true, false
 		}
 		if hasInputDefault(decl) {
@@ -79,6 +79,44 @@ func TestFromLangContainers(t *testing.T) {
 	)
 }
 
+func TestFromLangNamed(t *testing.T) {
+	endpoint := &lang.TypeObject{Fields: []*lang.TypeObjectField{
+		{Name: "host", Type: &lang.TypeAtomic{Name: "string"}},
+	}}
+	named := &lang.TypeNamed{Name: "endpoint", Resolved: endpoint}
+	want := TObject([]ObjectField{{Name: "host", Type: TString()}})
+
+	got := FromLang(&lang.TypeList{Elem: named})
+	assert.True(t, got.Equal(TList(want)), "got %s want %s", got, TList(want))
+	assert.True(t, FromLang(&lang.TypeNamed{Name: "unbound"}).Equal(TUnknown()))
+
+	optional := &lang.TypeNamed{Name: "maybe", Resolved: &lang.TypeOptional{Elem: named}}
+	field := objectFieldFromLang(&lang.TypeObjectField{Name: "peer", Type: optional})
+	assert.True(t, field.Optional, "a name for optional(T) reads as an optional field")
+	assert.True(t, field.Type.Equal(want), "got %s want %s", field.Type, want)
+}
+
+func TestFromLangTaggedUnion(t *testing.T) {
+	in := &lang.TypeUnion{Tag: "kind", Variants: []*lang.TypeUnionVariant{
+		{Name: "git", Type: &lang.TypeObject{Fields: []*lang.TypeObjectField{
+			{Name: "url", Type: &lang.TypeAtomic{Name: "string"}},
+		}}},
+		{Name: "empty", Type: &lang.TypeObject{}},
+	}}
+	want := TTaggedUnion("kind", []Variant{
+		{Name: "git", Type: TObject([]ObjectField{
+			{Name: "kind", Type: TString()},
+			{Name: "url", Type: TString()},
+		})},
+		{Name: "empty", Type: TObject([]ObjectField{{Name: "kind", Type: TString()}})},
+	})
+	got := FromLang(in)
+	assert.True(t, got.Equal(want), "got %s want %s", got, want)
+
+	in.Variants[1].Type = &lang.TypeAtomic{Name: "string"}
+	assert.True(t, FromLang(in).Equal(TUnknown()), "a variant that is not an object fails open")
+}
+
 func TestFromLangObjectBareFields(t *testing.T) {
 	in := &lang.TypeObject{Fields: []*lang.TypeObjectField{
 		{Name: "id", Type: &lang.TypeAtomic{Name: "string"}},
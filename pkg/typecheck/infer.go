@@ -1,6 +1,7 @@
 package typecheck
 
 import (
+	"fmt"
 	"maps"
 	"strings"
 
@@ -21,10 +22,17 @@ type Scope struct {
 	// selected for the current lexical body. Reference validation checks the
 	// path grammar and reports unknown entries before inference.
 	LookupAsset LookupAssetFn
+	// LookupTemplate reads the template a @core.render-template call
+	// renders when its argument is a static asset, parsed with positions
+	// in the asset file. Nil, or a nil template, leaves the slots
+	// unchecked.
+	LookupTemplate LookupTemplateFn
 	// LookupFunction resolves a library-qualified function to its
-	// signature so a call's arguments and result type-check. Nil, or a
-	// false return, leaves the call inferring Unknown; existence and
-	// argument count are the reference checker's to enforce.
+	// signature so a call's arguments and result type-check. A bare
+	// call to a sibling library function looks up with an empty
+	// library. Nil, or a false return, leaves the call inferring
+	// Unknown; existence and argument count are the reference checker's
+	// to enforce.
 	LookupFunction func(library, name string) (FuncSig, bool)
 	// Bindings holds comprehension-bound names. They resolve as bare
 	// values and as dot-path roots ahead of input/resource/data/action.
@@ -58,11 +66,16 @@ type Scope struct {
 // place of Result. Arguments of such a call are inferred without a
 // target so the hook sees their natural types: an object literal stays
 // the precise object it spells, not the parameter type it flowed into.
+//
+// Check, when set, runs after the arguments type-check and receives
+// them with their inferred types, for a function whose arguments
+// constrain each other beyond what parameter types say.
 type FuncSig struct {
 	Params   []Type
 	Variadic *Type
 	Result   Type
 	Infer    func(args []Type) Type
+	Check    func(args []lang.Expr, types []Type, scope *Scope, errs *lang.ErrorList)
 }
 
 // withBindings returns a child scope that adds the comprehension
@@ -108,6 +121,11 @@ type LookupLocalFn func(name string) (Type, bool)
 // LookupAssetFn returns the semantic type of a validated asset reference.
 type LookupAssetFn func(path *lang.DotPath) (Type, bool)
 
+// LookupTemplateFn returns the parsed template e names, or nil when e
+// is not a static template. A template that fails to parse returns the
+// parse error.
+type LookupTemplateFn func(e lang.Expr) (*lang.InterpolatedString, error)
+
 // Infer walks e and returns its inferred type. The target steers
 // how ambiguous literals decide between list/tuple and how object
 // literals match against a declared type; pass TUnknown when no
@@ -170,6 +188,19 @@ func infer(e lang.Expr, target Type, scope *Scope, errs *lang.ErrorList) Type {
 	return TUnknown()
 }
 
+// callName splits a call into the library and function names handed to
+// LookupFunction. A bare call, which only a library function's body may
+// make to a sibling, looks up with an empty library.
+func callName(c *lang.Call) (string, string, bool) {
+	switch {
+	case c.Library != nil && c.Func != nil:
+		return c.Library.Name, c.Func.Name, true
+	case c.Library == nil && c.Callee != nil:
+		return "", c.Callee.Name, true
+	}
+	return "", "", false
+}
+
 // inferCall types a library function call when the scope can describe
 // the function. Each argument checks against its parameter type and a
 // variadic tail against the tail's element type; the call's type is
@@ -178,15 +209,20 @@ func infer(e lang.Expr, target Type, scope *Scope, errs *lang.ErrorList) Type {
 // freely, since the argument count is the reference checker's to
 // report. An unresolvable call infers Unknown.
 func inferCall(c *lang.Call, scope *Scope, errs *lang.ErrorList) Type {
-	if scope == nil || scope.LookupFunction == nil || c.Library == nil || c.Func == nil {
+	if scope == nil || scope.LookupFunction == nil {
 		return TUnknown()
 	}
-	sig, ok := scope.LookupFunction(c.Library.Name, c.Func.Name)
+	library, name, ok := callName(c)
+	if !ok {
+		return TUnknown()
+	}
+	sig, ok := scope.LookupFunction(library, name)
 	if !ok {
 		return TUnknown()
 	}
 	var argTypes []Type
-	if sig.Infer != nil {
+	collect := sig.Infer != nil || sig.Check != nil
+	if collect {
 		argTypes = make([]Type, 0, len(c.Args))
 	}
 	for i, arg := range c.Args {
@@ -197,16 +233,16 @@ func inferCall(c *lang.Call, scope *Scope, errs *lang.ErrorList) Type {
 		case sig.Variadic != nil:
 			target = *sig.Variadic
 		}
-		if c.Library.Name == lang.CoreNamespace {
+		if library == lang.CoreNamespace {
 			got := inferCoreArgument(
-				c.Func.Name,
+				name,
 				arg,
 				target,
 				sig.Infer != nil,
 				scope,
 				errs,
 			)
-			if sig.Infer != nil {
+			if collect {
 				argTypes = append(argTypes, got)
 			}
 			continue
@@ -219,11 +255,20 @@ func inferCall(c *lang.Call, scope *Scope, errs *lang.ErrorList) Type {
 			}
 			argTypes = append(argTypes, got)
 		case target.Kind == Union:
-			checkUnionArg(c.Func.Name, arg, target, scope, errs)
+			checkUnionArg(name, arg, target, scope, errs)
+			if collect {
+				argTypes = append(argTypes, TUnknown())
+			}
 		default:
-			Check(arg, target, scope, errs)
+			got := Check(arg, target, scope, errs)
+			if collect {
+				argTypes = append(argTypes, got)
+			}
 		}
 	}
+	if sig.Check != nil {
+		sig.Check(c.Args, argTypes, scope, errs)
+	}
 	if sig.Infer != nil {
 		return sig.Infer(argTypes)
 	}
@@ -370,7 +415,7 @@ func inferConditional(
 	c *lang.Conditional, target Type, scope *Scope, errs *lang.ErrorList,
 ) Type {
 	Check(c.Cond, TBoolean(), scope, errs)
-	tFacts, fFacts := nullFacts(c.Cond, scope)
+	tFacts, fFacts := conditionFacts(c.Cond, scope)
 	thenT := Infer(c.Then, target, scope.narrowed(tFacts), errs)
 	elseT := Infer(c.Else, target, scope.narrowed(fFacts), errs)
 	if j, ok := join(thenT, elseT); ok {
@@ -443,7 +488,7 @@ func inferComprehension(
 	child := scope.withBindings(c.Names, key, elem)
 	if c.Filter != nil {
 		Check(c.Filter, TBoolean(), child, errs)
-		facts, _ := nullFacts(c.Filter, child)
+		facts, _ := conditionFacts(c.Filter, child)
 		child = child.narrowed(facts)
 	}
 	if c.Kind == lang.CompMap {
@@ -622,7 +667,8 @@ func literalEnforced(e lang.Expr, target Type) bool {
 	case *lang.ArrayLit:
 		return target.Kind == List || target.Kind == Tuple
 	case *lang.ObjectLit:
-		return target.Kind == Object || target.Kind == LibraryConfig || target.Kind == Map
+		return target.Kind == Object || target.Kind == LibraryConfig || target.Kind == Map ||
+			target.Kind == TaggedUnion
 	}
 	return false
 }
@@ -675,6 +721,8 @@ func inferObject(
 	switch target.Kind {
 	case Object, LibraryConfig:
 		return inferObjectAgainstObject(o, target, scope, errs)
+	case TaggedUnion:
+		return inferObjectAgainstUnion(o, target, scope, errs)
 	case Map:
 		if target.Elem == nil {
 			return inferObjectFree(o, scope, errs)
@@ -738,6 +786,42 @@ func inferObjectAgainstObject(
 	return TObject(fields)
 }
 
+// inferObjectAgainstUnion checks an object literal against the variant
+// its tag field names. The tag must be a string literal for the
+// variant to be known; a computed tag checks as a string and leaves
+// the rest of the literal to the runtime.
+func inferObjectAgainstUnion(
+	o *lang.ObjectLit, target Type, scope *Scope, errs *lang.ErrorList,
+) Type {
+	var tagField *lang.Field
+	for _, fld := range o.Fields {
+		if name, ok := objectLiteralFieldName(fld.Key); ok && name == target.Tag {
+			tagField = fld
+		}
+	}
+	if tagField == nil {
+		errs.Addf(lang.ErrType, o.S.Start,
+			"missing tag field %q on %s", target.Tag, target)
+		inferObjectFree(o, scope, errs)
+		return TUnknown()
+	}
+	lit, ok := tagField.Value.(*lang.StringLit)
+	if !ok {
+		Check(tagField.Value, TString(), scope, errs)
+		inferObjectFree(o, scope, errs)
+		return TUnknown()
+	}
+	variant, ok := target.Variant(lit.Value)
+	if !ok {
+		errs.Addf(lang.ErrType, lit.S.Start,
+			"unknown variant %q for tag field %q (expected %s)",
+			lit.Value, target.Tag, lang.ProseList(target.VariantNames(), "or"))
+		inferObjectFree(o, scope, errs)
+		return TUnknown()
+	}
+	return inferObjectAgainstObject(o, variant.Type, scope, errs)
+}
+
 func inferObjectFree(
 	o *lang.ObjectLit, scope *Scope, errs *lang.ErrorList,
 ) Type {
@@ -1021,6 +1105,16 @@ func traverseSegments(
 				return TUnknown()
 			}
 			current = *current.Elem
+		case TaggedUnion:
+			field, ok := current.Field(seg.Name)
+			if !ok {
+				errs.Addf(lang.ErrType, seg.S.Start, "%s", variantFieldMessage(current, seg.Name, at))
+				return TUnknown()
+			}
+			current = field.Type
+			if field.Optional && !field.Defaulted {
+				current = TOptional(current)
+			}
 		case Optional:
 			errs.Addf(lang.ErrType, seg.S.Start,
 				"%s may be null; read it with %s?.%s, or test it first (got %s)",
@@ -1050,6 +1144,30 @@ func traverseSegments(
 	return current
 }
 
+// variantFieldMessage explains why field cannot be read from the
+// tagged union at: which variants declare it, and the tag test that
+// narrows the value to one of them.
+func variantFieldMessage(t Type, field, at string) string {
+	var having []string
+	for _, v := range t.Variants {
+		if _, ok := v.Type.Field(field); ok {
+			having = append(having, v.Name)
+		}
+	}
+	switch len(having) {
+	case 0:
+		return fmt.Sprintf("unknown field %q on %s", field, t)
+	case 1:
+		return fmt.Sprintf("field %q is only in variant %s of %s; test %s.%s == '%s' first",
+			field, having[0], at, at, t.Tag, having[0])
+	case len(t.Variants):
+		return fmt.Sprintf("field %q has a different type in each variant of %s; test %s.%s first",
+			field, at, at, t.Tag)
+	}
+	return fmt.Sprintf("field %q is only in variants %s of %s; test %s.%s first",
+		field, lang.ProseList(having, "and"), at, at, t.Tag)
+}
+
 // openFieldHint explains an unknown field on an open object: the
 // field may well be present, but open admits fields without making
 // them readable.
@@ -1157,10 +1275,10 @@ func inferInfix(in *lang.Infix, scope *Scope, errs *lang.ErrorList) Type {
 	rightScope := scope
 	switch in.Op {
 	case "&&":
-		facts, _ := nullFacts(in.Left, scope)
+		facts, _ := conditionFacts(in.Left, scope)
 		rightScope = scope.narrowed(facts)
 	case "||":
-		_, facts := nullFacts(in.Left, scope)
+		_, facts := conditionFacts(in.Left, scope)
 		rightScope = scope.narrowed(facts)
 	}
 	right := Infer(in.Right, TUnknown(), rightScope, errs)
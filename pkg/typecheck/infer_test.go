@@ -919,3 +919,28 @@ func TestCheckMapComprehensionValueTarget(t *testing.T) {
 	require.Len(t, errs.Errors(), 1)
 	assert.Contains(t, errs.Errors()[0].Msg, "expected string, got integer")
 }
+
+func TestInferObjectAgainstTaggedUnion(t *testing.T) {
+	union, _, s3, _ := sourceUnion()
+	errs := lang.NewErrorList(0)
+	got := Check(parseExpr(t, "{ kind: 's3', bucket: 'b', key: 'k' }"), union, &Scope{}, errs)
+	assert.True(t, got.Equal(s3), "got %s", got)
+	assert.Empty(t, errs.Errors())
+
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"{ bucket: 'b', key: 'k' }", `missing tag field "kind"`},
+		{"{ kind: 'ftp' }", `unknown variant "ftp" for tag field "kind" (expected git, s3, or http)`},
+		{"{ kind: 'git', url: 'u', bucket: 'b' }", `unknown field "bucket"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			errs := lang.NewErrorList(0)
+			Check(parseExpr(t, tt.src), union, &Scope{}, errs)
+			require.NotEmpty(t, errs.Errors())
+			assert.Contains(t, errs.Errors()[0].Msg, tt.want)
+		})
+	}
+}
@@ -11,7 +11,7 @@ import (
 // puts in force, or the scope itself when the condition proves
 // nothing. A constraint's require is checked this way under its when.
 func NarrowedWhere(scope *Scope, cond lang.Expr) *Scope {
-	facts, _ := nullFacts(cond, scope)
+	facts, _ := conditionFacts(cond, scope)
 	return scope.narrowed(facts)
 }
 
@@ -29,29 +29,32 @@ func (s *Scope) narrowed(facts map[string]Type) *Scope {
 	return &child
 }
 
-// nullFacts derives the reference paths a condition proves null or
-// non-null: whenTrue holds the narrowings in force where cond held,
-// whenFalse where it did not. The condition forms that produce facts
-// are `path == null`, `path != null`, their `!` negations, and `&&`
-// conjunctions, mirroring the evaluator's short circuit: everything
-// else proves nothing. Only exact, index-free paths narrow.
-func nullFacts(cond lang.Expr, scope *Scope) (whenTrue, whenFalse map[string]Type) {
+// conditionFacts derives the reference paths a condition narrows:
+// whenTrue holds the narrowings in force where cond held, whenFalse
+// where it did not. The condition forms that produce facts are
+// `path == null` and `path != null`, which prove a path null or
+// non-null; `path.tag == 'name'` and `!=`, which narrow a tagged union
+// to its named variant or to the others; their `!` negations; and `&&`
+// conjunctions, whose right side is read under the left side's facts,
+// mirroring the evaluator's short circuit: everything else proves
+// nothing. Only exact, index-free paths narrow.
+func conditionFacts(cond lang.Expr, scope *Scope) (whenTrue, whenFalse map[string]Type) {
 	switch v := cond.(type) {
 	case *lang.Prefix:
 		if v.Op == "!" {
-			t, f := nullFacts(v.Expr, scope)
+			t, f := conditionFacts(v.Expr, scope)
 			return f, t
 		}
 	case *lang.Infix:
 		switch v.Op {
 		case "&&":
-			lt, _ := nullFacts(v.Left, scope)
-			rt, _ := nullFacts(v.Right, scope)
+			lt, _ := conditionFacts(v.Left, scope)
+			rt, _ := conditionFacts(v.Right, scope.narrowed(lt))
 			return mergeFacts(lt, rt), nil
 		case "==", "!=":
 			path := nullComparedPath(v)
 			if path == nil {
-				return nil, nil
+				return tagFacts(v, scope)
 			}
 			key, ok := narrowKey(path)
 			if !ok {
@@ -72,6 +75,66 @@ func nullFacts(cond lang.Expr, scope *Scope) (whenTrue, whenFalse map[string]Typ
 	return nil, nil
 }
 
+// tagFacts derives the facts of comparing a tagged union's tag with a
+// string literal. Equal narrows the union to the variant the literal
+// names; unequal narrows it to the remaining variants, a single one of
+// which stands alone. A literal no variant is named proves nothing.
+func tagFacts(in *lang.Infix, scope *Scope) (whenTrue, whenFalse map[string]Type) {
+	dp, lit := tagComparison(in)
+	if dp == nil || len(dp.Segments) == 0 {
+		return nil, nil
+	}
+	last := dp.Segments[len(dp.Segments)-1]
+	if last.Splat || last.Index != nil || last.Guarded || last.Name == "" {
+		return nil, nil
+	}
+	parent := &lang.DotPath{S: dp.S, Root: dp.Root, Segments: dp.Segments[:len(dp.Segments)-1]}
+	key, ok := narrowKey(parent)
+	if !ok {
+		return nil, nil
+	}
+	union := Infer(parent, TUnknown(), scope, lang.NewErrorList(0))
+	if union.Kind != TaggedUnion || union.Tag != last.Name {
+		return nil, nil
+	}
+	variant, ok := union.Variant(lit.Value)
+	if !ok {
+		return nil, nil
+	}
+	var rest []Variant
+	for _, v := range union.Variants {
+		if v.Name != variant.Name {
+			rest = append(rest, v)
+		}
+	}
+	other := TTaggedUnion(union.Tag, rest)
+	if len(rest) == 1 {
+		other = rest[0].Type
+	}
+	is := map[string]Type{key: variant.Type}
+	isNot := map[string]Type{key: other}
+	if in.Op == "==" {
+		return is, isNot
+	}
+	return isNot, is
+}
+
+// tagComparison returns the path and string literal of a comparison
+// between the two, in either order.
+func tagComparison(in *lang.Infix) (*lang.DotPath, *lang.StringLit) {
+	if lit, ok := in.Right.(*lang.StringLit); ok {
+		if dp, ok := in.Left.(*lang.DotPath); ok {
+			return dp, lit
+		}
+	}
+	if lit, ok := in.Left.(*lang.StringLit); ok {
+		if dp, ok := in.Right.(*lang.DotPath); ok {
+			return dp, lit
+		}
+	}
+	return nil, nil
+}
+
 // nullComparedPath returns the reference side of a comparison against
 // null, or nil when the comparison is not a null test.
 func nullComparedPath(in *lang.Infix) lang.Expr {
@@ -64,7 +64,7 @@ func TestNullFacts(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.src, func(t *testing.T) {
-			whenTrue, whenFalse := nullFacts(parseExpr(t, tt.src), narrowScope())
+			whenTrue, whenFalse := conditionFacts(parseExpr(t, tt.src), narrowScope())
 			require.Equal(t, tt.whenTrue, whenTrue)
 			require.Equal(t, tt.whenFalse, whenFalse)
 		})
@@ -498,3 +498,61 @@ func TestNarrowDoesNotInvent(t *testing.T) {
 		})
 	}
 }
+
+func sourceUnion() (union, git, s3, http Type) {
+	kind := ObjectField{Name: "kind", Type: TString()}
+	git = TObject([]ObjectField{kind, {Name: "url", Type: TString()}})
+	s3 = TObject([]ObjectField{kind, {Name: "bucket", Type: TString()}, {Name: "key", Type: TString()}})
+	http = TObject([]ObjectField{kind, {Name: "url", Type: TString()}})
+	union = TTaggedUnion("kind", []Variant{
+		{Name: "git", Type: git},
+		{Name: "s3", Type: s3},
+		{Name: "http", Type: http},
+	})
+	return union, git, s3, http
+}
+
+func unionScope() *Scope {
+	union, _, _, _ := sourceUnion()
+	return &Scope{
+		Inputs: []ObjectField{
+			{Name: "source", Type: union},
+			{Name: "sources", Type: TList(union)},
+		},
+	}
+}
+
+func TestTagFacts(t *testing.T) {
+	_, git, s3, http := sourceUnion()
+	notGit := TTaggedUnion("kind", []Variant{{Name: "s3", Type: s3}, {Name: "http", Type: http}})
+	tests := []struct {
+		src       string
+		whenTrue  map[string]Type
+		whenFalse map[string]Type
+	}{
+		{
+			src:       "input.source.kind == 'git'",
+			whenTrue:  map[string]Type{"input.source": git},
+			whenFalse: map[string]Type{"input.source": notGit},
+		},
+		{
+			src:       "'git' != input.source.kind",
+			whenTrue:  map[string]Type{"input.source": notGit},
+			whenFalse: map[string]Type{"input.source": git},
+		},
+		{
+			src:      "input.source.kind != 'git' && input.source.kind != 's3'",
+			whenTrue: map[string]Type{"input.source": http},
+		},
+		{src: "input.source.kind == 'ftp'"},
+		{src: "input.source.url == 'git'"},
+		{src: "input.sources[0].kind == 'git'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			whenTrue, whenFalse := conditionFacts(parseExpr(t, tt.src), unionScope())
+			require.Equal(t, tt.whenTrue, whenTrue)
+			require.Equal(t, tt.whenFalse, whenFalse)
+		})
+	}
+}
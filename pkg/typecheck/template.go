@@ -0,0 +1,88 @@
+package typecheck
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/cloudboss/unobin/pkg/lang"
+)
+
+// CheckTemplate is @core.render-template's argument check. When
+// scope.LookupTemplate resolves the template argument, each slot
+// type-checks as an interpolation slot with the variables object's
+// fields bound by name, and errors point into the template file. A
+// slot reading a name the object does not declare is an error, since
+// at runtime there is nothing to bind it to; an open object or a map
+// leaves names to the runtime. Slots call only @core functions.
+func CheckTemplate(args []lang.Expr, types []Type, scope *Scope, errs *lang.ErrorList) {
+	if len(args) != 2 || len(types) != 2 || scope == nil || scope.LookupTemplate == nil {
+		return
+	}
+	tpl, err := scope.LookupTemplate(args[0])
+	if err != nil {
+		var perr *lang.Error
+		if errors.As(err, &perr) {
+			errs.Add(perr)
+		} else {
+			errs.Addf(lang.ErrParse, args[0].Span().Start, "%v", err)
+		}
+		return
+	}
+	if tpl == nil {
+		return
+	}
+	vars := types[1].Unwrap()
+	closed := vars.Kind == Object && !vars.Open
+	bindings := make(map[string]Type, len(vars.Fields))
+	if vars.Kind == Object {
+		for _, f := range vars.Fields {
+			bindings[f.Name] = fieldReadType(f)
+		}
+	}
+	if closed {
+		for _, id := range lang.FreeNames(tpl) {
+			if _, ok := bindings[id.Name]; !ok {
+				errs.Addf(lang.ErrResolve, id.S.Start,
+					"template variable %q is not set; the variables object has %s",
+					id.Name, fieldList(vars.Fields))
+			}
+		}
+	}
+	lang.Walk(tpl, func(e lang.Expr) {
+		call, ok := e.(*lang.Call)
+		if ok && call.Library != nil && call.Library.Name != lang.CoreNamespace {
+			errs.Addf(lang.ErrResolve, call.Library.S.Start,
+				"templates may call only %s functions", lang.CoreNamespace)
+		}
+	})
+	inner := &Scope{
+		Bindings: bindings,
+		LookupFunction: func(library, name string) (FuncSig, bool) {
+			if library != lang.CoreNamespace || scope.LookupFunction == nil {
+				return FuncSig{}, false
+			}
+			return scope.LookupFunction(library, name)
+		},
+	}
+	inferInterpolated(tpl, inner, errs)
+}
+
+// fieldReadType is the type a read of f yields: an optional field
+// without a default may be absent, which reads as null.
+func fieldReadType(f ObjectField) Type {
+	if f.Optional && !f.Defaulted {
+		return TOptional(f.Type)
+	}
+	return f.Type
+}
+
+func fieldList(fields []ObjectField) string {
+	if len(fields) == 0 {
+		return "no fields"
+	}
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = f.Name
+	}
+	return "fields " + strings.Join(names, ", ")
+}
@@ -0,0 +1,88 @@
+package typecheck
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudboss/unobin/pkg/lang"
+)
+
+// templateScope types @core.render-template as the runtime registers
+// it and serves src as the template behind any template argument.
+func templateScope(t *testing.T, src string) *Scope {
+	t.Helper()
+	sig := FuncSig{
+		Params: []Type{TUnion([]Type{TString(), TBytes()}), TOpenObject(nil)},
+		Result: TString(),
+		Check:  CheckTemplate,
+	}
+	return &Scope{
+		LookupFunction: func(library, name string) (FuncSig, bool) {
+			switch {
+			case library == lang.CoreNamespace && name == "render-template":
+				return sig, true
+			case library == lang.CoreNamespace && name == "to-string":
+				return FuncSig{Params: []Type{TInteger()}, Result: TString()}, true
+			}
+			return FuncSig{}, false
+		},
+		LookupTemplate: func(lang.Expr) (*lang.InterpolatedString, error) {
+			return lang.ParseTemplate("app.conf", []byte(src))
+		},
+	}
+}
+
+func TestCheckTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		vars     string
+		want     []string
+	}{
+		{
+			name:     "slots read variables",
+			template: "port {{ port }}\nhost {{ @core.to-string(port) }}-{{ name }}\n",
+			vars:     "{ port: 80, name: 'web' }",
+		},
+		{
+			name:     "unset variable",
+			template: "a\nb {{ host }}\n",
+			vars:     "{ port: 80 }",
+			want: []string{
+				`app.conf:2:6: resolve: template variable "host" is not set;` +
+					` the variables object has fields port`,
+			},
+		},
+		{
+			name:     "non-scalar slot",
+			template: "{{ tags }}",
+			vars:     "{ tags: ['a'] }",
+			want:     []string{"app.conf:1:4: type: interpolation slot must be a scalar, got list(string)"},
+		},
+		{
+			name:     "library call",
+			template: "{{ lib.f(port) }}",
+			vars:     "{ port: 80 }",
+			want:     []string{"app.conf:1:4: resolve: templates may call only @core functions"},
+		},
+		{
+			name:     "parse error",
+			template: "ok\n{{ port + }}",
+			vars:     "{ port: 80 }",
+			want: []string{
+				`app.conf:2:10: parse: no match found, expected: "!", "#", "$'", "$'''", "'", "'''",` +
+					` "(", "-", "@", "[", "\"", "false", "null", "true", "{", [ \t\r\n], [0-9] or [a-zA-Z]`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := lang.NewErrorList(0)
+			call := parseExpr(t, "@core.render-template('x', "+tt.vars+")")
+			got := Infer(call, TUnknown(), templateScope(t, tt.template), errs)
+			require.True(t, got.Equal(TString()), "got %s", got)
+			require.Equal(t, tt.want, errs.Strings())
+		})
+	}
+}
@@ -42,6 +42,9 @@ const (
 	Union
 	AssetPath
 	Bytes
+	// TaggedUnion is a written `union(tag, { ... })`: one of Variants,
+	// told apart by the string field Tag.
+	TaggedUnion
 )
 
 // Type is a structural type description. The Kind field discrim-
@@ -50,6 +53,7 @@ const (
 //   - List/Set/Map/Optional read Elem.
 //   - Tuple reads Elems.
 //   - Object reads Fields.
+//   - TaggedUnion reads Tag and Variants.
 //
 // Pass Type around by value; the recursive children live on the
 // pointer fields so a deeply nested type still copies in constant
@@ -65,6 +69,16 @@ type Type struct {
 	Elems         []Type
 	Fields        []ObjectField
 	LibraryConfig *LibraryConfigInfo
+	Tag           string
+	Variants      []Variant
+}
+
+// Variant is one variant of a TaggedUnion. Type is the variant's
+// object with the tag field included, as a string, so a value narrowed
+// to the variant reads its tag like any other field.
+type Variant struct {
+	Name string
+	Type Type
 }
 
 // LibraryConfigInfo is the nominal identity for a Go library config type.
@@ -134,6 +148,30 @@ func TUnion(members []Type) Type {
 	return Type{Kind: Union, Elems: members}
 }
 
+func TTaggedUnion(tag string, variants []Variant) Type {
+	return Type{Kind: TaggedUnion, Tag: tag, Variants: variants}
+}
+
+// Variant returns the variant of a TaggedUnion named name.
+func (t Type) Variant(name string) (Variant, bool) {
+	for _, v := range t.Variants {
+		if v.Name == name {
+			return v, true
+		}
+	}
+	return Variant{}, false
+}
+
+// VariantNames lists a TaggedUnion's variant names in declaration
+// order.
+func (t Type) VariantNames() []string {
+	names := make([]string, len(t.Variants))
+	for i, v := range t.Variants {
+		names[i] = v.Name
+	}
+	return names
+}
+
 // IsKnown returns false when the Type is Unknown or wraps Unknown
 // through an Optional. The checker uses this to bail out of
 // comparisons it cannot reason about.
@@ -167,6 +205,11 @@ func (t Type) ContainsUnknown() bool {
 			return true
 		}
 	}
+	for _, v := range t.Variants {
+		if v.Type.ContainsUnknown() {
+			return true
+		}
+	}
 	return false
 }
 
@@ -181,8 +224,13 @@ func (t Type) Unwrap() Type {
 }
 
 // Field returns the named field of an Object or LibraryConfig type,
-// ok=false when the field is absent or the type has no fields.
+// ok=false when the field is absent or the type has no fields. A
+// TaggedUnion has the fields every variant declares with the same
+// type, its tag among them.
 func (t Type) Field(name string) (ObjectField, bool) {
+	if t.Kind == TaggedUnion {
+		return t.commonField(name)
+	}
 	if t.Kind != Object && t.Kind != LibraryConfig {
 		return ObjectField{}, false
 	}
@@ -194,6 +242,18 @@ func (t Type) Field(name string) (ObjectField, bool) {
 	return ObjectField{}, false
 }
 
+func (t Type) commonField(name string) (ObjectField, bool) {
+	var common ObjectField
+	for i, v := range t.Variants {
+		f, ok := v.Type.Field(name)
+		if !ok || (i > 0 && !objectFieldEqual(common, f)) {
+			return ObjectField{}, false
+		}
+		common = f
+	}
+	return common, len(t.Variants) > 0
+}
+
 // String renders the type in the unobin type vocabulary the
 // operator would have written: `list(string)`, `optional(integer)`,
 // `object({ a: string  b: integer })`. Object field order follows
@@ -253,10 +313,26 @@ func (t Type) String() string {
 			return "open(" + s + ")"
 		}
 		return s
+	case TaggedUnion:
+		parts := make([]string, len(t.Variants))
+		for i, v := range t.Variants {
+			parts[i] = fmt.Sprintf("%s: %s", v.Name, v.withoutTag(t.Tag).String())
+		}
+		return "union(" + t.Tag + ", { " + strings.Join(parts, "  ") + " })"
 	}
 	return fmt.Sprintf("type#%d", t.Kind)
 }
 
+// withoutTag returns the variant's object as written, without the tag
+// field FromLang adds.
+func (v Variant) withoutTag(tag string) Type {
+	out := v.Type
+	out.Fields = slices.DeleteFunc(slices.Clone(v.Type.Fields), func(f ObjectField) bool {
+		return f.Name == tag
+	})
+	return out
+}
+
 func (t Type) elemString() string {
 	if t.Elem == nil {
 		return "unknown"
@@ -288,6 +364,17 @@ func (t Type) Equal(other Type) bool {
 			}
 		}
 		return true
+	case TaggedUnion:
+		if t.Tag != other.Tag || len(t.Variants) != len(other.Variants) {
+			return false
+		}
+		for i := range t.Variants {
+			if t.Variants[i].Name != other.Variants[i].Name ||
+				!t.Variants[i].Type.Equal(other.Variants[i].Type) {
+				return false
+			}
+		}
+		return true
 	case LibraryConfig:
 		if !sameLibraryConfig(t.LibraryConfig, other.LibraryConfig) {
 			return false
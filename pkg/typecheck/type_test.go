@@ -48,6 +48,20 @@ func TestTypeString(t *testing.T) {
 			TUnion([]Type{TString(), TList(TOpaque()), TMap(TOpaque())}),
 			"string | list(opaque) | map(opaque)",
 		},
+		{
+			"tagged union",
+			TTaggedUnion("kind", []Variant{
+				{Name: "git", Type: TObject([]ObjectField{
+					{Name: "kind", Type: TString()},
+					{Name: "url", Type: TString()},
+				})},
+				{Name: "s3", Type: TObject([]ObjectField{
+					{Name: "kind", Type: TString()},
+					{Name: "bucket", Type: TString()},
+				})},
+			}),
+			"union(kind, { git: object({ url: string })  s3: object({ bucket: string }) })",
+		},
 		{"unknown", TUnknown(), "unknown"},
 	}
 	for _, tt := range tests {
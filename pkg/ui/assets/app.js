@@ -54,6 +54,7 @@ function initGraph(f) {
   state.complete = null;
   state.selected = null;
   state.runStartedAt = Date.now();
+  showChecks([]);
 
   const hidden = new Set();
   const depsOf = new Map();
@@ -374,9 +375,23 @@ function finishRun(f) {
     }
   }
   updateStatus();
+  showChecks(f.checks || []);
   if (state.es) state.es.close();
 }
 
+// Failed checks come after an apply whose steps all finished, so they
+// get their own list under the banner rather than a step card.
+function showChecks(checks) {
+  const list = $('checks');
+  list.replaceChildren();
+  for (const c of checks) {
+    const item = document.createElement('li');
+    item.textContent = c;
+    list.appendChild(item);
+  }
+  list.hidden = checks.length === 0;
+}
+
 function counts() {
   let done = 0;
   let failed = 0;
@@ -48,14 +48,16 @@ type applyEventFrame struct {
 // runCompleteFrame ends the stream. NotRun counts steps that never
 // started, whether the scheduler halted on a failure or the run was
 // interrupted. Message explains a failure that has no failed step,
-// such as an interrupt.
+// such as an interrupt. Checks lists the factory checks that failed
+// after an otherwise complete apply.
 type runCompleteFrame struct {
-	Kind      string `json:"kind"`
-	Seq       uint64 `json:"seq"`
-	OK        bool   `json:"ok"`
-	Message   string `json:"message,omitempty"`
-	Succeeded int    `json:"succeeded"`
-	Failed    int    `json:"failed"`
-	NotRun    int    `json:"not-run"`
-	ElapsedMS int64  `json:"elapsed-ms"`
+	Kind      string   `json:"kind"`
+	Seq       uint64   `json:"seq"`
+	OK        bool     `json:"ok"`
+	Message   string   `json:"message,omitempty"`
+	Checks    []string `json:"checks,omitempty"`
+	Succeeded int      `json:"succeeded"`
+	Failed    int      `json:"failed"`
+	NotRun    int      `json:"not-run"`
+	ElapsedMS int64    `json:"elapsed-ms"`
 }
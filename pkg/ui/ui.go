@@ -162,9 +162,10 @@ func (s *Server) Observe(ev runtime.ApplyEvent) {
 
 // Complete ends the stream: it computes the run totals from the
 // observed events and broadcasts the run-complete frame. message
-// explains a failure that has no failed step, such as an interrupt;
-// it is ignored when ok is true.
-func (s *Server) Complete(ok bool, message string) {
+// explains a failure that has no failed step, such as an interrupt,
+// and checks lists the factory checks that failed after apply; both
+// are ignored when ok is true.
+func (s *Server) Complete(ok bool, message string, checks []string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	if s.result != nil {
@@ -172,6 +173,7 @@ func (s *Server) Complete(ok bool, message string) {
 	}
 	if ok {
 		message = ""
+		checks = nil
 	}
 	var done, failed int
 	for _, st := range s.steps {
@@ -188,6 +190,7 @@ func (s *Server) Complete(ok bool, message string) {
 		Seq:       s.seq,
 		OK:        ok,
 		Message:   message,
+		Checks:    checks,
 		Succeeded: done,
 		Failed:    failed,
 		NotRun:    len(s.steps) - done - failed,
@@ -226,7 +226,7 @@ func TestEventStream(t *testing.T) {
 			`"decision":"create","stage":"done","elapsed-ms":1500}`,
 		frames[1])
 
-	s.Complete(true, "")
+	s.Complete(true, "", nil)
 	frames = sseFrames(t, br, 1)
 	assert.Equal(t,
 		`{"kind":"run-complete","seq":3,"ok":true,"succeeded":1,"failed":0,`+
@@ -245,7 +245,7 @@ func TestLateClientSeesFinishedRun(t *testing.T) {
 	s.Observe(doneEvent("resource.aws.vpc.main", 1500*time.Millisecond))
 	s.Observe(startEvent("resource.aws.subnet.this"))
 	s.Observe(failEvent("resource.aws.subnet.this", 2*time.Second, "boom"))
-	s.Complete(false, "")
+	s.Complete(false, "", nil)
 
 	br := connectSSE(t, s)
 	frames := sseFrames(t, br, 3)
@@ -298,7 +298,7 @@ func TestObserveUnknownAddressStillStreams(t *testing.T) {
 
 func TestCompleteMessageKeptOnlyOnFailure(t *testing.T) {
 	s := startTestServer(t)
-	s.Complete(false, "interrupted")
+	s.Complete(false, "interrupted", nil)
 	br := connectSSE(t, s)
 	frames := sseFrames(t, br, 3)
 	assert.Equal(t,
@@ -307,10 +307,22 @@ func TestCompleteMessageKeptOnlyOnFailure(t *testing.T) {
 		frames[2])
 }
 
+func TestCompleteCarriesFailedChecks(t *testing.T) {
+	s := startTestServer(t)
+	s.Complete(false, "1 check failed after apply", []string{"checks[0]: api must answer"})
+	br := connectSSE(t, s)
+	frames := sseFrames(t, br, 3)
+	assert.Equal(t,
+		`{"kind":"run-complete","seq":1,"ok":false,`+
+			`"message":"1 check failed after apply","checks":["checks[0]: api must answer"],`+
+			`"succeeded":0,"failed":0,"not-run":2,"elapsed-ms":0}`,
+		frames[2])
+}
+
 func TestCompleteIsIdempotent(t *testing.T) {
 	s := startTestServer(t)
-	s.Complete(true, "")
-	s.Complete(false, "again")
+	s.Complete(true, "", nil)
+	s.Complete(false, "again", nil)
 	br := connectSSE(t, s)
 	frames := sseFrames(t, br, 3)
 	assert.Contains(t, frames[2], `"ok":true`)
@@ -321,7 +333,7 @@ func TestWaitServed(t *testing.T) {
 	assert.False(t, s.WaitServed(time.Second),
 		"WaitServed before Complete reports false without waiting")
 
-	s.Complete(true, "")
+	s.Complete(true, "", nil)
 	assert.False(t, s.WaitServed(20*time.Millisecond),
 		"no client has seen the run-complete frame yet")
 
@@ -14,6 +14,9 @@ import (
 //go:embed factory.assets
 var factoryAssets []byte
 
+//go:embed factory.materials.json
+var factoryMaterials []byte
+
 var factorySource = parse.NewSourceFile(
 	"factory.ub",
 	[]int{0, 11, 23, 60, 64, 65, 77, 118, 122, 124},
@@ -47,6 +50,7 @@ func main() {
 		Libraries:       map[string]*runtime.Library{},
 		AssetBundle:     factoryAssets,
 		RootAssetSetID:  "f15e4497a8eeb3458e483de306fbd17ef30dfc7834c70c86bd0d87b226b8addf",
+		Materials:       factoryMaterials,
 		UnobinVersion:   unobinVersion,
 	})
 }
@@ -15,6 +15,9 @@ import (
 //go:embed factory.assets
 var factoryAssets []byte
 
+//go:embed factory.materials.json
+var factoryMaterials []byte
+
 var factorySource = parse.NewSourceFile(
 	"factory.ub",
 	[]int{0, 11, 23, 50, 54, 55, 68, 92, 96, 97, 109, 156, 160, 161, 179, 210, 241, 245, 247},
@@ -53,6 +56,7 @@ func main() {
 		},
 		AssetBundle:    factoryAssets,
 		RootAssetSetID: "5ee155a030a53191274f3cb2c9331db2ffb56923763efe652f77e2a69060aee0",
+		Materials:      factoryMaterials,
 		UnobinVersion:  unobinVersion,
 	})
 }
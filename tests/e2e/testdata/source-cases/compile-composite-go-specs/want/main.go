@@ -2,6 +2,8 @@
 package main
 
 import (
+	_ "embed"
+
 	lib_files "demo-factory/internal/files"
 	lib_e2e "example.com/unobin/e2elib"
 	"github.com/cloudboss/unobin/pkg/lang"
@@ -12,6 +14,9 @@ import (
 	"github.com/cloudboss/unobin/pkg/typecheck"
 )
 
+//go:embed factory.materials.json
+var factoryMaterials []byte
+
 var factorySource = parse.NewSourceFile(
 	"factory.ub",
 	[]int{0, 11, 23, 50, 77, 95, 151, 168, 196, 236, 280, 288, 294, 298, 299, 312, 351, 382, 386, 387, 432, 433, 448, 509, 538, 567, 596, 631, 637, 641, 643},
@@ -86,6 +91,7 @@ func main() {
 		FactoryBody:     &factoryBody,
 		LibraryPath:     factoryLibraryPath,
 		Libraries:       libraries,
+		Materials:       factoryMaterials,
 		UnobinVersion:   unobinVersion,
 	})
 }
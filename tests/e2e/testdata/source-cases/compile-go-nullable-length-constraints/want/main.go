@@ -2,6 +2,8 @@
 package main
 
 import (
+	_ "embed"
+
 	lib_len "example.com/lenlib"
 	"github.com/cloudboss/unobin/pkg/lang"
 	"github.com/cloudboss/unobin/pkg/lang/parse"
@@ -10,6 +12,9 @@ import (
 	"github.com/cloudboss/unobin/pkg/runtime"
 )
 
+//go:embed factory.materials.json
+var factoryMaterials []byte
+
 var factorySource = parse.NewSourceFile(
 	"factory.ub",
 	[]int{0, 11, 23, 78, 132, 181, 246, 250, 291, 306, 331, 362, 409, 453, 491, 526, 532, 536, 538},
@@ -58,6 +63,7 @@ func main() {
 		FactoryBody:     &factoryBody,
 		LibraryPath:     factoryLibraryPath,
 		Libraries:       libraries,
+		Materials:       factoryMaterials,
 		UnobinVersion:   unobinVersion,
 	})
 }
@@ -2,6 +2,8 @@
 package main
 
 import (
+	_ "embed"
+
 	"github.com/cloudboss/unobin/pkg/lang"
 	"github.com/cloudboss/unobin/pkg/lang/parse"
 	"github.com/cloudboss/unobin/pkg/lang/syntax"
@@ -10,6 +12,9 @@ import (
 	lib_core "github.com/x/core/lib"
 )
 
+//go:embed factory.materials.json
+var factoryMaterials []byte
+
 var factorySource = parse.NewSourceFile(
 	"factory.ub",
 	[]int{0, 11, 57, 59},
@@ -46,6 +51,7 @@ func main() {
 				"github.com/x/core/lib",
 			),
 		},
+		Materials:     factoryMaterials,
 		UnobinVersion: unobinVersion,
 	})
 }
@@ -2,6 +2,8 @@
 package main
 
 import (
+	_ "embed"
+
 	lib_net "demo-factory/internal/net"
 	lib_e2e "example.com/unobin/e2elib"
 	"github.com/cloudboss/unobin/pkg/lang"
@@ -12,6 +14,9 @@ import (
 	"github.com/cloudboss/unobin/pkg/typecheck"
 )
 
+//go:embed factory.materials.json
+var factoryMaterials []byte
+
 var factorySource = parse.NewSourceFile(
 	"factory.ub",
 	[]int{0, 11, 24, 51, 88, 92, 94},
@@ -74,6 +79,7 @@ func main() {
 		FactoryBody:     &factoryBody,
 		LibraryPath:     factoryLibraryPath,
 		Libraries:       libraries,
+		Materials:       factoryMaterials,
 		UnobinVersion:   unobinVersion,
 	})
 }
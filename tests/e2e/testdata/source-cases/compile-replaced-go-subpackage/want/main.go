@@ -2,6 +2,8 @@
 package main
 
 import (
+	_ "embed"
+
 	lib_fs "example.com/repo/go/fs"
 	"github.com/cloudboss/unobin/pkg/lang"
 	"github.com/cloudboss/unobin/pkg/lang/parse"
@@ -10,6 +12,9 @@ import (
 	"github.com/cloudboss/unobin/pkg/runtime"
 )
 
+//go:embed factory.materials.json
+var factoryMaterials []byte
+
 var factorySource = parse.NewSourceFile(
 	"factory.ub",
 	[]int{0, 11, 56, 58},
@@ -46,6 +51,7 @@ func main() {
 				"example.com/repo/go/fs",
 			),
 		},
+		Materials:     factoryMaterials,
 		UnobinVersion: unobinVersion,
 	})
 }
@@ -2,6 +2,8 @@
 package main
 
 import (
+	_ "embed"
+
 	lib_shared "demo-factory/internal/shared"
 	lib_wrap "demo-factory/internal/wrap"
 	"github.com/cloudboss/unobin/pkg/lang"
@@ -11,6 +13,9 @@ import (
 	"github.com/cloudboss/unobin/pkg/runtime"
 )
 
+//go:embed factory.materials.json
+var factoryMaterials []byte
+
 var factorySource = parse.NewSourceFile(
 	"factory.ub",
 	[]int{0, 11, 24, 75, 120, 124, 126},
@@ -51,6 +56,7 @@ func main() {
 				"demo-factory/internal/wrap",
 			),
 		},
+		Materials:     factoryMaterials,
 		UnobinVersion: unobinVersion,
 	})
 }
@@ -0,0 +1,10 @@
+module example.com/aws
+
+go 1.26
+
+require (
+	github.com/aws/aws-sdk-go-v2 v1.42.1
+	github.com/aws/aws-sdk-go-v2/config v1.32.17
+	github.com/aws/aws-sdk-go-v2/service/cloudcontrol v1.31.1
+	github.com/cloudboss/unobin v0.0.0
+)
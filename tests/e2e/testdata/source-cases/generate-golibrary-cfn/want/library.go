@@ -0,0 +1,20 @@
+// Generated by unobin generate golibrary --from cfn.
+
+package aws
+
+import (
+	"example.com/aws/resources"
+
+	"github.com/cloudboss/unobin/pkg/runtime"
+)
+
+func Library() *runtime.Library {
+	return &runtime.Library{
+		Name:        "aws",
+		LibraryPath: "example.com/aws",
+		Description: "Generated aws library",
+		Resources: map[string]runtime.ResourceRegistration{
+			"s3-bucket": runtime.MakeResource[resources.S3Bucket, *resources.S3BucketOutput, any](),
+		},
+	}
+}
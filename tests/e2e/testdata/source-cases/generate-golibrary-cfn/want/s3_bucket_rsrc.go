@@ -0,0 +1,71 @@
+// Generated by unobin generate golibrary --from cfn.
+
+package resources
+
+import (
+	"context"
+
+	"github.com/cloudboss/unobin/pkg/runtime"
+)
+
+// The AWS::S3::Bucket resource creates an Amazon S3 bucket in the same AWS Region
+// where you create the AWS CloudFormation stack.
+//
+// S3Bucket implements runtime.Resource for AWS::S3::Bucket.
+type S3Bucket struct {
+	// A name for the bucket.
+	BucketName *string `ub:"bucket-name" json:"BucketName,omitempty"`
+	// Indicates whether this bucket has an Object Lock configuration enabled.
+	ObjectLockEnabled *bool `ub:"object-lock-enabled" json:"ObjectLockEnabled,omitempty"`
+	// An arbitrary set of tags (key-value pairs) for this S3 bucket.
+	Tags                    *[]map[string]any `ub:"tags" json:"Tags,omitempty"`
+	VersioningConfiguration *map[string]any   `ub:"versioning-configuration" json:"VersioningConfiguration,omitempty"`
+}
+
+// S3BucketOutput holds the read-only state for AWS::S3::Bucket.
+type S3BucketOutput struct {
+	// The Amazon Resource Name (ARN) of the specified bucket.
+	Arn string `ub:"arn" json:"Arn"`
+	// The IPv4 DNS name of the specified bucket.
+	DomainName string `ub:"domain-name" json:"DomainName"`
+	// CloudControlIdentifier is the identifier Cloud Control returned on create:
+	// the resource's BucketName.
+	CloudControlIdentifier string `ub:"cloud-control-identifier" json:"-"`
+}
+
+func (r *S3Bucket) SchemaVersion() int { return 1 }
+
+func (r *S3Bucket) ReplaceFields() []string {
+	return []string{
+		"bucket-name",
+		"object-lock-enabled",
+	}
+}
+
+func (r *S3Bucket) Create(ctx context.Context, cfg any) (*S3BucketOutput, error) {
+	id, err := createResource(ctx, "AWS::S3::Bucket", r)
+	if err != nil {
+		return nil, err
+	}
+	return r.Read(ctx, cfg, &S3BucketOutput{CloudControlIdentifier: id})
+}
+
+func (r *S3Bucket) Read(ctx context.Context, cfg any, priorOutputs *S3BucketOutput) (*S3BucketOutput, error) {
+	out := &S3BucketOutput{CloudControlIdentifier: priorOutputs.CloudControlIdentifier}
+	if err := getResource(ctx, "AWS::S3::Bucket", out.CloudControlIdentifier, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *S3Bucket) Update(ctx context.Context, cfg any, prior runtime.Prior[S3Bucket, *S3BucketOutput]) (*S3BucketOutput, error) {
+	id := prior.Outputs.CloudControlIdentifier
+	if err := updateResource(ctx, "AWS::S3::Bucket", id, prior.Inputs, r); err != nil {
+		return nil, err
+	}
+	return r.Read(ctx, cfg, prior.Outputs)
+}
+
+func (r *S3Bucket) Delete(ctx context.Context, cfg any, priorOutputs *S3BucketOutput) error {
+	return deleteResource(ctx, "AWS::S3::Bucket", priorOutputs.CloudControlIdentifier)
+}
@@ -0,0 +1,67 @@
+// Generated by unobin generate golibrary --from tf.
+
+package resources
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudboss/unobin/pkg/runtime"
+)
+
+// DbInstance implements runtime.Resource for aws_db_instance.
+type DbInstance struct {
+	// Blue/green update settings
+	BlueGreenUpdate  *map[string]any `ub:"blue-green-update"`
+	CaCertIdentifier *string         `ub:"ca-cert-identifier"`
+	// Name of the DB instance
+	Identifier string `ub:"identifier"`
+	// Password for the master DB user
+	Password             *string           `ub:"password,sensitive"`
+	RestoreToPointInTime map[string]any    `ub:"restore-to-point-in-time"`
+	S3Import             *[]map[string]any `ub:"s3-import,sensitive"`
+	Timeouts             *map[string]any   `ub:"timeouts"`
+}
+
+// DbInstanceOutput holds the read-only state for aws_db_instance.
+type DbInstanceOutput struct {
+	// Hostname of the DB instance
+	Address string `ub:"address"`
+	// Secret holding the master user password
+	MasterUserSecret []map[string]any `ub:"master-user-secret,sensitive"`
+}
+
+func (r *DbInstance) SchemaVersion() int { return 1 }
+
+func (r *DbInstance) ReplaceFields() []string {
+	return nil
+}
+
+func (r *DbInstance) ModifyResourcePlan(
+	req runtime.ResourcePlanRequest[DbInstance, *DbInstanceOutput, any],
+	resp *runtime.ResourcePlanResponse,
+) error {
+	if req.HasPriorState && runtime.Changed(req.PriorInputs, req.CurrentInputs) {
+		resp.MarkOutputUnknown(
+			"address",
+			"master-user-secret",
+		)
+	}
+	return nil
+}
+
+func (r *DbInstance) Create(ctx context.Context, cfg any) (*DbInstanceOutput, error) {
+	return nil, fmt.Errorf("create not implemented")
+}
+
+func (r *DbInstance) Read(ctx context.Context, cfg any, priorOutputs *DbInstanceOutput) (*DbInstanceOutput, error) {
+	return nil, fmt.Errorf("read not implemented")
+}
+
+func (r *DbInstance) Update(ctx context.Context, cfg any, prior runtime.Prior[DbInstance, *DbInstanceOutput]) (*DbInstanceOutput, error) {
+	return nil, fmt.Errorf("update not implemented")
+}
+
+func (r *DbInstance) Delete(ctx context.Context, cfg any, priorOutputs *DbInstanceOutput) error {
+	return fmt.Errorf("delete not implemented")
+}
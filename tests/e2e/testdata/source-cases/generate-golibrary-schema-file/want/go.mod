@@ -0,0 +1,7 @@
+module example.com/aws
+
+go 1.26
+
+require (
+	github.com/cloudboss/unobin v0.0.0
+)
@@ -0,0 +1,20 @@
+// Generated by unobin generate golibrary --from tf.
+
+package aws
+
+import (
+	"example.com/aws/resources"
+
+	"github.com/cloudboss/unobin/pkg/runtime"
+)
+
+func Library() *runtime.Library {
+	return &runtime.Library{
+		Name:        "aws",
+		LibraryPath: "example.com/aws",
+		Description: "Generated aws library",
+		Resources: map[string]runtime.ResourceRegistration{
+			"db-instance": runtime.MakeResource[resources.DbInstance, *resources.DbInstanceOutput, any](),
+		},
+	}
+}
@@ -82,7 +82,7 @@
 (primary_expression (identifier) @font-lock-variable-name-face)
 
 ((field_key (identifier) @font-lock-keyword-face)
- (#match? @font-lock-keyword-face "^(actions|assets|configurations|constraints|data-sources|deps|encryption|factory|imports|inputs|library|library-configs|locals|outputs|parallelism|pin|project|project-lock|replace|requires|resources|stack|state|state-moves|toolchain|unobin-version|version)$"))
+ (#match? @font-lock-keyword-face "^(actions|assets|checks|configurations|constraints|data-sources|deps|encryption|factory|imports|inputs|library|library-configs|locals|outputs|parallelism|pin|project|project-lock|replace|requires|resources|stack|state|state-moves|toolchain|types|unobin-version|version)$"))
 
 ((field_key (identifier) @font-lock-preprocessor-face)
  (#match? @font-lock-preprocessor-face "^@"))
@@ -62,7 +62,7 @@ func TestQueriesValidateWithTreeSitter(t *testing.T) {
 func TestHighlightsIncludeAssetSyntax(t *testing.T) {
 	body := readQueryFile(t, "queries/highlights.scm")
 
-	require.Contains(t, body, "actions|assets|configurations")
+	require.Contains(t, body, "actions|assets|checks|configurations")
 	require.Contains(t, body, "@self|action|asset|data-source")
 }
 